@@ -806,6 +806,154 @@ func (this *GetWorkflowExecutionRawHistoryResponse) Equal(that interface{}) bool
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type StreamWorkflowExecutionRawHistoryRequest to the protobuf v3 wire format
+func (val *StreamWorkflowExecutionRawHistoryRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StreamWorkflowExecutionRawHistoryRequest from the protobuf v3 wire format
+func (val *StreamWorkflowExecutionRawHistoryRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StreamWorkflowExecutionRawHistoryRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StreamWorkflowExecutionRawHistoryRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StreamWorkflowExecutionRawHistoryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StreamWorkflowExecutionRawHistoryRequest
+	switch t := that.(type) {
+	case *StreamWorkflowExecutionRawHistoryRequest:
+		that1 = t
+	case StreamWorkflowExecutionRawHistoryRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StreamWorkflowExecutionRawHistoryResponse to the protobuf v3 wire format
+func (val *StreamWorkflowExecutionRawHistoryResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StreamWorkflowExecutionRawHistoryResponse from the protobuf v3 wire format
+func (val *StreamWorkflowExecutionRawHistoryResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StreamWorkflowExecutionRawHistoryResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StreamWorkflowExecutionRawHistoryResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StreamWorkflowExecutionRawHistoryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StreamWorkflowExecutionRawHistoryResponse
+	switch t := that.(type) {
+	case *StreamWorkflowExecutionRawHistoryResponse:
+		that1 = t
+	case StreamWorkflowExecutionRawHistoryResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StreamImportWorkflowExecutionHistoryRequest to the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StreamImportWorkflowExecutionHistoryRequest from the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StreamImportWorkflowExecutionHistoryRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StreamImportWorkflowExecutionHistoryRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StreamImportWorkflowExecutionHistoryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StreamImportWorkflowExecutionHistoryRequest
+	switch t := that.(type) {
+	case *StreamImportWorkflowExecutionHistoryRequest:
+		that1 = t
+	case StreamImportWorkflowExecutionHistoryRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StreamImportWorkflowExecutionHistoryResponse to the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StreamImportWorkflowExecutionHistoryResponse from the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StreamImportWorkflowExecutionHistoryResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StreamImportWorkflowExecutionHistoryResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StreamImportWorkflowExecutionHistoryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StreamImportWorkflowExecutionHistoryResponse
+	switch t := that.(type) {
+	case *StreamImportWorkflowExecutionHistoryResponse:
+		that1 = t
+	case StreamImportWorkflowExecutionHistoryResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type GetReplicationMessagesRequest to the protobuf v3 wire format
 func (val *GetReplicationMessagesRequest) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
@@ -42,44 +42,46 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	AdminService_RebuildMutableState_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/RebuildMutableState"
-	AdminService_ImportWorkflowExecution_FullMethodName           = "/temporal.server.api.adminservice.v1.AdminService/ImportWorkflowExecution"
-	AdminService_DescribeMutableState_FullMethodName              = "/temporal.server.api.adminservice.v1.AdminService/DescribeMutableState"
-	AdminService_DescribeHistoryHost_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/DescribeHistoryHost"
-	AdminService_GetShard_FullMethodName                          = "/temporal.server.api.adminservice.v1.AdminService/GetShard"
-	AdminService_CloseShard_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/CloseShard"
-	AdminService_ListHistoryTasks_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/ListHistoryTasks"
-	AdminService_RemoveTask_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/RemoveTask"
-	AdminService_GetWorkflowExecutionRawHistoryV2_FullMethodName  = "/temporal.server.api.adminservice.v1.AdminService/GetWorkflowExecutionRawHistoryV2"
-	AdminService_GetWorkflowExecutionRawHistory_FullMethodName    = "/temporal.server.api.adminservice.v1.AdminService/GetWorkflowExecutionRawHistory"
-	AdminService_GetReplicationMessages_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/GetReplicationMessages"
-	AdminService_GetNamespaceReplicationMessages_FullMethodName   = "/temporal.server.api.adminservice.v1.AdminService/GetNamespaceReplicationMessages"
-	AdminService_GetDLQReplicationMessages_FullMethodName         = "/temporal.server.api.adminservice.v1.AdminService/GetDLQReplicationMessages"
-	AdminService_ReapplyEvents_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/ReapplyEvents"
-	AdminService_AddSearchAttributes_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/AddSearchAttributes"
-	AdminService_RemoveSearchAttributes_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/RemoveSearchAttributes"
-	AdminService_GetSearchAttributes_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/GetSearchAttributes"
-	AdminService_DescribeCluster_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/DescribeCluster"
-	AdminService_ListClusters_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/ListClusters"
-	AdminService_ListClusterMembers_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/ListClusterMembers"
-	AdminService_AddOrUpdateRemoteCluster_FullMethodName          = "/temporal.server.api.adminservice.v1.AdminService/AddOrUpdateRemoteCluster"
-	AdminService_RemoveRemoteCluster_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/RemoveRemoteCluster"
-	AdminService_GetDLQMessages_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/GetDLQMessages"
-	AdminService_PurgeDLQMessages_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQMessages"
-	AdminService_MergeDLQMessages_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQMessages"
-	AdminService_RefreshWorkflowTasks_FullMethodName              = "/temporal.server.api.adminservice.v1.AdminService/RefreshWorkflowTasks"
-	AdminService_ResendReplicationTasks_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/ResendReplicationTasks"
-	AdminService_GetTaskQueueTasks_FullMethodName                 = "/temporal.server.api.adminservice.v1.AdminService/GetTaskQueueTasks"
-	AdminService_DeleteWorkflowExecution_FullMethodName           = "/temporal.server.api.adminservice.v1.AdminService/DeleteWorkflowExecution"
-	AdminService_StreamWorkflowReplicationMessages_FullMethodName = "/temporal.server.api.adminservice.v1.AdminService/StreamWorkflowReplicationMessages"
-	AdminService_GetNamespace_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/GetNamespace"
-	AdminService_GetDLQTasks_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/GetDLQTasks"
-	AdminService_PurgeDLQTasks_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQTasks"
-	AdminService_MergeDLQTasks_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQTasks"
-	AdminService_DescribeDLQJob_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/DescribeDLQJob"
-	AdminService_CancelDLQJob_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/CancelDLQJob"
-	AdminService_AddTasks_FullMethodName                          = "/temporal.server.api.adminservice.v1.AdminService/AddTasks"
-	AdminService_ListQueues_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/ListQueues"
+	AdminService_RebuildMutableState_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/RebuildMutableState"
+	AdminService_ImportWorkflowExecution_FullMethodName              = "/temporal.server.api.adminservice.v1.AdminService/ImportWorkflowExecution"
+	AdminService_DescribeMutableState_FullMethodName                 = "/temporal.server.api.adminservice.v1.AdminService/DescribeMutableState"
+	AdminService_DescribeHistoryHost_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/DescribeHistoryHost"
+	AdminService_GetShard_FullMethodName                             = "/temporal.server.api.adminservice.v1.AdminService/GetShard"
+	AdminService_CloseShard_FullMethodName                           = "/temporal.server.api.adminservice.v1.AdminService/CloseShard"
+	AdminService_ListHistoryTasks_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/ListHistoryTasks"
+	AdminService_RemoveTask_FullMethodName                           = "/temporal.server.api.adminservice.v1.AdminService/RemoveTask"
+	AdminService_GetWorkflowExecutionRawHistoryV2_FullMethodName     = "/temporal.server.api.adminservice.v1.AdminService/GetWorkflowExecutionRawHistoryV2"
+	AdminService_GetWorkflowExecutionRawHistory_FullMethodName       = "/temporal.server.api.adminservice.v1.AdminService/GetWorkflowExecutionRawHistory"
+	AdminService_StreamWorkflowExecutionRawHistory_FullMethodName    = "/temporal.server.api.adminservice.v1.AdminService/StreamWorkflowExecutionRawHistory"
+	AdminService_StreamImportWorkflowExecutionHistory_FullMethodName = "/temporal.server.api.adminservice.v1.AdminService/StreamImportWorkflowExecutionHistory"
+	AdminService_GetReplicationMessages_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/GetReplicationMessages"
+	AdminService_GetNamespaceReplicationMessages_FullMethodName      = "/temporal.server.api.adminservice.v1.AdminService/GetNamespaceReplicationMessages"
+	AdminService_GetDLQReplicationMessages_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/GetDLQReplicationMessages"
+	AdminService_ReapplyEvents_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/ReapplyEvents"
+	AdminService_AddSearchAttributes_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/AddSearchAttributes"
+	AdminService_RemoveSearchAttributes_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/RemoveSearchAttributes"
+	AdminService_GetSearchAttributes_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/GetSearchAttributes"
+	AdminService_DescribeCluster_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/DescribeCluster"
+	AdminService_ListClusters_FullMethodName                         = "/temporal.server.api.adminservice.v1.AdminService/ListClusters"
+	AdminService_ListClusterMembers_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/ListClusterMembers"
+	AdminService_AddOrUpdateRemoteCluster_FullMethodName             = "/temporal.server.api.adminservice.v1.AdminService/AddOrUpdateRemoteCluster"
+	AdminService_RemoveRemoteCluster_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/RemoveRemoteCluster"
+	AdminService_GetDLQMessages_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/GetDLQMessages"
+	AdminService_PurgeDLQMessages_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQMessages"
+	AdminService_MergeDLQMessages_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQMessages"
+	AdminService_RefreshWorkflowTasks_FullMethodName                 = "/temporal.server.api.adminservice.v1.AdminService/RefreshWorkflowTasks"
+	AdminService_ResendReplicationTasks_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/ResendReplicationTasks"
+	AdminService_GetTaskQueueTasks_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/GetTaskQueueTasks"
+	AdminService_DeleteWorkflowExecution_FullMethodName              = "/temporal.server.api.adminservice.v1.AdminService/DeleteWorkflowExecution"
+	AdminService_StreamWorkflowReplicationMessages_FullMethodName    = "/temporal.server.api.adminservice.v1.AdminService/StreamWorkflowReplicationMessages"
+	AdminService_GetNamespace_FullMethodName                         = "/temporal.server.api.adminservice.v1.AdminService/GetNamespace"
+	AdminService_GetDLQTasks_FullMethodName                          = "/temporal.server.api.adminservice.v1.AdminService/GetDLQTasks"
+	AdminService_PurgeDLQTasks_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQTasks"
+	AdminService_MergeDLQTasks_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQTasks"
+	AdminService_DescribeDLQJob_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/DescribeDLQJob"
+	AdminService_CancelDLQJob_FullMethodName                         = "/temporal.server.api.adminservice.v1.AdminService/CancelDLQJob"
+	AdminService_AddTasks_FullMethodName                             = "/temporal.server.api.adminservice.v1.AdminService/AddTasks"
+	AdminService_ListQueues_FullMethodName                           = "/temporal.server.api.adminservice.v1.AdminService/ListQueues"
 )
 
 // AdminServiceClient is the client API for AdminService service.
@@ -108,6 +110,12 @@ type AdminServiceClient interface {
 	// StartEventId defines the beginning of the event to fetch. The first event is inclusive.
 	// EndEventId and EndEventVersion defines the end of the event to fetch. The end event is inclusive.
 	GetWorkflowExecutionRawHistory(ctx context.Context, in *GetWorkflowExecutionRawHistoryRequest, opts ...grpc.CallOption) (*GetWorkflowExecutionRawHistoryResponse, error)
+	// StreamWorkflowExecutionRawHistory is GetWorkflowExecutionRawHistory, but streams all pages of the
+	// requested events instead of returning one page per call.
+	StreamWorkflowExecutionRawHistory(ctx context.Context, in *StreamWorkflowExecutionRawHistoryRequest, opts ...grpc.CallOption) (AdminService_StreamWorkflowExecutionRawHistoryClient, error)
+	// StreamImportWorkflowExecutionHistory imports the history of a workflow from the source cluster, starting from
+	// the first event, and streams the import progress back to the caller until the import is done.
+	StreamImportWorkflowExecutionHistory(ctx context.Context, in *StreamImportWorkflowExecutionHistoryRequest, opts ...grpc.CallOption) (AdminService_StreamImportWorkflowExecutionHistoryClient, error)
 	// GetReplicationMessages returns new replication tasks since the read level provided in the token.
 	GetReplicationMessages(ctx context.Context, in *GetReplicationMessagesRequest, opts ...grpc.CallOption) (*GetReplicationMessagesResponse, error)
 	// GetNamespaceReplicationMessages returns new namespace replication tasks since last retrieved task Id.
@@ -265,6 +273,70 @@ func (c *adminServiceClient) GetWorkflowExecutionRawHistory(ctx context.Context,
 	return out, nil
 }
 
+func (c *adminServiceClient) StreamWorkflowExecutionRawHistory(ctx context.Context, in *StreamWorkflowExecutionRawHistoryRequest, opts ...grpc.CallOption) (AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_StreamWorkflowExecutionRawHistory_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceStreamWorkflowExecutionRawHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_StreamWorkflowExecutionRawHistoryClient interface {
+	Recv() (*StreamWorkflowExecutionRawHistoryResponse, error)
+	grpc.ClientStream
+}
+
+type adminServiceStreamWorkflowExecutionRawHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceStreamWorkflowExecutionRawHistoryClient) Recv() (*StreamWorkflowExecutionRawHistoryResponse, error) {
+	m := new(StreamWorkflowExecutionRawHistoryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminServiceClient) StreamImportWorkflowExecutionHistory(ctx context.Context, in *StreamImportWorkflowExecutionHistoryRequest, opts ...grpc.CallOption) (AdminService_StreamImportWorkflowExecutionHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[1], AdminService_StreamImportWorkflowExecutionHistory_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminServiceStreamImportWorkflowExecutionHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AdminService_StreamImportWorkflowExecutionHistoryClient interface {
+	Recv() (*StreamImportWorkflowExecutionHistoryResponse, error)
+	grpc.ClientStream
+}
+
+type adminServiceStreamImportWorkflowExecutionHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminServiceStreamImportWorkflowExecutionHistoryClient) Recv() (*StreamImportWorkflowExecutionHistoryResponse, error) {
+	m := new(StreamImportWorkflowExecutionHistoryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *adminServiceClient) GetReplicationMessages(ctx context.Context, in *GetReplicationMessagesRequest, opts ...grpc.CallOption) (*GetReplicationMessagesResponse, error) {
 	out := new(GetReplicationMessagesResponse)
 	err := c.cc.Invoke(ctx, AdminService_GetReplicationMessages_FullMethodName, in, out, opts...)
@@ -437,7 +509,7 @@ func (c *adminServiceClient) DeleteWorkflowExecution(ctx context.Context, in *De
 }
 
 func (c *adminServiceClient) StreamWorkflowReplicationMessages(ctx context.Context, opts ...grpc.CallOption) (AdminService_StreamWorkflowReplicationMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[0], AdminService_StreamWorkflowReplicationMessages_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdminService_ServiceDesc.Streams[2], AdminService_StreamWorkflowReplicationMessages_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -565,6 +637,12 @@ type AdminServiceServer interface {
 	// StartEventId defines the beginning of the event to fetch. The first event is inclusive.
 	// EndEventId and EndEventVersion defines the end of the event to fetch. The end event is inclusive.
 	GetWorkflowExecutionRawHistory(context.Context, *GetWorkflowExecutionRawHistoryRequest) (*GetWorkflowExecutionRawHistoryResponse, error)
+	// StreamWorkflowExecutionRawHistory is GetWorkflowExecutionRawHistory, but streams all pages of the
+	// requested events instead of returning one page per call.
+	StreamWorkflowExecutionRawHistory(*StreamWorkflowExecutionRawHistoryRequest, AdminService_StreamWorkflowExecutionRawHistoryServer) error
+	// StreamImportWorkflowExecutionHistory imports the history of a workflow from the source cluster, starting from
+	// the first event, and streams the import progress back to the caller until the import is done.
+	StreamImportWorkflowExecutionHistory(*StreamImportWorkflowExecutionHistoryRequest, AdminService_StreamImportWorkflowExecutionHistoryServer) error
 	// GetReplicationMessages returns new replication tasks since the read level provided in the token.
 	GetReplicationMessages(context.Context, *GetReplicationMessagesRequest) (*GetReplicationMessagesResponse, error)
 	// GetNamespaceReplicationMessages returns new namespace replication tasks since last retrieved task Id.
@@ -659,6 +737,12 @@ func (UnimplementedAdminServiceServer) GetWorkflowExecutionRawHistoryV2(context.
 func (UnimplementedAdminServiceServer) GetWorkflowExecutionRawHistory(context.Context, *GetWorkflowExecutionRawHistoryRequest) (*GetWorkflowExecutionRawHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowExecutionRawHistory not implemented")
 }
+func (UnimplementedAdminServiceServer) StreamWorkflowExecutionRawHistory(*StreamWorkflowExecutionRawHistoryRequest, AdminService_StreamWorkflowExecutionRawHistoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamWorkflowExecutionRawHistory not implemented")
+}
+func (UnimplementedAdminServiceServer) StreamImportWorkflowExecutionHistory(*StreamImportWorkflowExecutionHistoryRequest, AdminService_StreamImportWorkflowExecutionHistoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamImportWorkflowExecutionHistory not implemented")
+}
 func (UnimplementedAdminServiceServer) GetReplicationMessages(context.Context, *GetReplicationMessagesRequest) (*GetReplicationMessagesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetReplicationMessages not implemented")
 }
@@ -936,6 +1020,48 @@ func _AdminService_GetWorkflowExecutionRawHistory_Handler(srv interface{}, ctx c
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminService_StreamWorkflowExecutionRawHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamWorkflowExecutionRawHistoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).StreamWorkflowExecutionRawHistory(m, &adminServiceStreamWorkflowExecutionRawHistoryServer{stream})
+}
+
+type AdminService_StreamWorkflowExecutionRawHistoryServer interface {
+	Send(*StreamWorkflowExecutionRawHistoryResponse) error
+	grpc.ServerStream
+}
+
+type adminServiceStreamWorkflowExecutionRawHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceStreamWorkflowExecutionRawHistoryServer) Send(m *StreamWorkflowExecutionRawHistoryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AdminService_StreamImportWorkflowExecutionHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamImportWorkflowExecutionHistoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServiceServer).StreamImportWorkflowExecutionHistory(m, &adminServiceStreamImportWorkflowExecutionHistoryServer{stream})
+}
+
+type AdminService_StreamImportWorkflowExecutionHistoryServer interface {
+	Send(*StreamImportWorkflowExecutionHistoryResponse) error
+	grpc.ServerStream
+}
+
+type adminServiceStreamImportWorkflowExecutionHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminServiceStreamImportWorkflowExecutionHistoryServer) Send(m *StreamImportWorkflowExecutionHistoryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _AdminService_GetReplicationMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetReplicationMessagesRequest)
 	if err := dec(in); err != nil {
@@ -1605,6 +1731,16 @@ var AdminService_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamWorkflowExecutionRawHistory",
+			Handler:       _AdminService_StreamWorkflowExecutionRawHistory_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamImportWorkflowExecutionHistory",
+			Handler:       _AdminService_StreamImportWorkflowExecutionHistory_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamWorkflowReplicationMessages",
 			Handler:       _AdminService_StreamWorkflowReplicationMessages_Handler,
@@ -801,6 +801,46 @@ func (mr *MockAdminServiceClientMockRecorder) ResendReplicationTasks(ctx, in int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendReplicationTasks", reflect.TypeOf((*MockAdminServiceClient)(nil).ResendReplicationTasks), varargs...)
 }
 
+// StreamImportWorkflowExecutionHistory mocks base method.
+func (m *MockAdminServiceClient) StreamImportWorkflowExecutionHistory(ctx context.Context, in *adminservice.StreamImportWorkflowExecutionHistoryRequest, opts ...grpc.CallOption) (adminservice.AdminService_StreamImportWorkflowExecutionHistoryClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StreamImportWorkflowExecutionHistory", varargs...)
+	ret0, _ := ret[0].(adminservice.AdminService_StreamImportWorkflowExecutionHistoryClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamImportWorkflowExecutionHistory indicates an expected call of StreamImportWorkflowExecutionHistory.
+func (mr *MockAdminServiceClientMockRecorder) StreamImportWorkflowExecutionHistory(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamImportWorkflowExecutionHistory", reflect.TypeOf((*MockAdminServiceClient)(nil).StreamImportWorkflowExecutionHistory), varargs...)
+}
+
+// StreamWorkflowExecutionRawHistory mocks base method.
+func (m *MockAdminServiceClient) StreamWorkflowExecutionRawHistory(ctx context.Context, in *adminservice.StreamWorkflowExecutionRawHistoryRequest, opts ...grpc.CallOption) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StreamWorkflowExecutionRawHistory", varargs...)
+	ret0, _ := ret[0].(adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamWorkflowExecutionRawHistory indicates an expected call of StreamWorkflowExecutionRawHistory.
+func (mr *MockAdminServiceClientMockRecorder) StreamWorkflowExecutionRawHistory(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamWorkflowExecutionRawHistory", reflect.TypeOf((*MockAdminServiceClient)(nil).StreamWorkflowExecutionRawHistory), varargs...)
+}
+
 // StreamWorkflowReplicationMessages mocks base method.
 func (m *MockAdminServiceClient) StreamWorkflowReplicationMessages(ctx context.Context, opts ...grpc.CallOption) (adminservice.AdminService_StreamWorkflowReplicationMessagesClient, error) {
 	m.ctrl.T.Helper()
@@ -821,6 +861,252 @@ func (mr *MockAdminServiceClientMockRecorder) StreamWorkflowReplicationMessages(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamWorkflowReplicationMessages", reflect.TypeOf((*MockAdminServiceClient)(nil).StreamWorkflowReplicationMessages), varargs...)
 }
 
+// MockAdminService_StreamImportWorkflowExecutionHistoryClient is a mock of AdminService_StreamImportWorkflowExecutionHistoryClient interface.
+type MockAdminService_StreamImportWorkflowExecutionHistoryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder
+}
+
+// MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder is the mock recorder for MockAdminService_StreamImportWorkflowExecutionHistoryClient.
+type MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder struct {
+	mock *MockAdminService_StreamImportWorkflowExecutionHistoryClient
+}
+
+// NewMockAdminService_StreamImportWorkflowExecutionHistoryClient creates a new mock instance.
+func NewMockAdminService_StreamImportWorkflowExecutionHistoryClient(ctrl *gomock.Controller) *MockAdminService_StreamImportWorkflowExecutionHistoryClient {
+	mock := &MockAdminService_StreamImportWorkflowExecutionHistoryClient{ctrl: ctrl}
+	mock.recorder = &MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryClient) EXPECT() *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryClient) Recv() (*adminservice.StreamImportWorkflowExecutionHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*adminservice.StreamImportWorkflowExecutionHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAdminService_StreamImportWorkflowExecutionHistoryClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAdminService_StreamImportWorkflowExecutionHistoryClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryClient)(nil).Trailer))
+}
+
+// MockAdminService_StreamWorkflowExecutionRawHistoryClient is a mock of AdminService_StreamWorkflowExecutionRawHistoryClient interface.
+type MockAdminService_StreamWorkflowExecutionRawHistoryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder
+}
+
+// MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder is the mock recorder for MockAdminService_StreamWorkflowExecutionRawHistoryClient.
+type MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder struct {
+	mock *MockAdminService_StreamWorkflowExecutionRawHistoryClient
+}
+
+// NewMockAdminService_StreamWorkflowExecutionRawHistoryClient creates a new mock instance.
+func NewMockAdminService_StreamWorkflowExecutionRawHistoryClient(ctrl *gomock.Controller) *MockAdminService_StreamWorkflowExecutionRawHistoryClient {
+	mock := &MockAdminService_StreamWorkflowExecutionRawHistoryClient{ctrl: ctrl}
+	mock.recorder = &MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryClient) EXPECT() *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryClient) Recv() (*adminservice.StreamWorkflowExecutionRawHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*adminservice.StreamWorkflowExecutionRawHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAdminService_StreamWorkflowExecutionRawHistoryClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAdminService_StreamWorkflowExecutionRawHistoryClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryClient)(nil).Trailer))
+}
+
 // MockAdminService_StreamWorkflowReplicationMessagesClient is a mock of AdminService_StreamWorkflowReplicationMessagesClient interface.
 type MockAdminService_StreamWorkflowReplicationMessagesClient struct {
 	ctrl     *gomock.Controller
@@ -1536,6 +1822,34 @@ func (mr *MockAdminServiceServerMockRecorder) ResendReplicationTasks(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendReplicationTasks", reflect.TypeOf((*MockAdminServiceServer)(nil).ResendReplicationTasks), arg0, arg1)
 }
 
+// StreamImportWorkflowExecutionHistory mocks base method.
+func (m *MockAdminServiceServer) StreamImportWorkflowExecutionHistory(arg0 *adminservice.StreamImportWorkflowExecutionHistoryRequest, arg1 adminservice.AdminService_StreamImportWorkflowExecutionHistoryServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamImportWorkflowExecutionHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamImportWorkflowExecutionHistory indicates an expected call of StreamImportWorkflowExecutionHistory.
+func (mr *MockAdminServiceServerMockRecorder) StreamImportWorkflowExecutionHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamImportWorkflowExecutionHistory", reflect.TypeOf((*MockAdminServiceServer)(nil).StreamImportWorkflowExecutionHistory), arg0, arg1)
+}
+
+// StreamWorkflowExecutionRawHistory mocks base method.
+func (m *MockAdminServiceServer) StreamWorkflowExecutionRawHistory(arg0 *adminservice.StreamWorkflowExecutionRawHistoryRequest, arg1 adminservice.AdminService_StreamWorkflowExecutionRawHistoryServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamWorkflowExecutionRawHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamWorkflowExecutionRawHistory indicates an expected call of StreamWorkflowExecutionRawHistory.
+func (mr *MockAdminServiceServerMockRecorder) StreamWorkflowExecutionRawHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamWorkflowExecutionRawHistory", reflect.TypeOf((*MockAdminServiceServer)(nil).StreamWorkflowExecutionRawHistory), arg0, arg1)
+}
+
 // StreamWorkflowReplicationMessages mocks base method.
 func (m *MockAdminServiceServer) StreamWorkflowReplicationMessages(arg0 adminservice.AdminService_StreamWorkflowReplicationMessagesServer) error {
 	m.ctrl.T.Helper()
@@ -1597,6 +1911,244 @@ func (mr *MockUnsafeAdminServiceServerMockRecorder) mustEmbedUnimplementedAdminS
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedAdminServiceServer", reflect.TypeOf((*MockUnsafeAdminServiceServer)(nil).mustEmbedUnimplementedAdminServiceServer))
 }
 
+// MockAdminService_StreamImportWorkflowExecutionHistoryServer is a mock of AdminService_StreamImportWorkflowExecutionHistoryServer interface.
+type MockAdminService_StreamImportWorkflowExecutionHistoryServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder
+}
+
+// MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder is the mock recorder for MockAdminService_StreamImportWorkflowExecutionHistoryServer.
+type MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder struct {
+	mock *MockAdminService_StreamImportWorkflowExecutionHistoryServer
+}
+
+// NewMockAdminService_StreamImportWorkflowExecutionHistoryServer creates a new mock instance.
+func NewMockAdminService_StreamImportWorkflowExecutionHistoryServer(ctrl *gomock.Controller) *MockAdminService_StreamImportWorkflowExecutionHistoryServer {
+	mock := &MockAdminService_StreamImportWorkflowExecutionHistoryServer{ctrl: ctrl}
+	mock.recorder = &MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryServer) EXPECT() *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAdminService_StreamImportWorkflowExecutionHistoryServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryServer) Send(arg0 *adminservice.StreamImportWorkflowExecutionHistoryResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAdminService_StreamImportWorkflowExecutionHistoryServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockAdminService_StreamImportWorkflowExecutionHistoryServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockAdminService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAdminService_StreamImportWorkflowExecutionHistoryServer)(nil).SetTrailer), arg0)
+}
+
+// MockAdminService_StreamWorkflowExecutionRawHistoryServer is a mock of AdminService_StreamWorkflowExecutionRawHistoryServer interface.
+type MockAdminService_StreamWorkflowExecutionRawHistoryServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder
+}
+
+// MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder is the mock recorder for MockAdminService_StreamWorkflowExecutionRawHistoryServer.
+type MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder struct {
+	mock *MockAdminService_StreamWorkflowExecutionRawHistoryServer
+}
+
+// NewMockAdminService_StreamWorkflowExecutionRawHistoryServer creates a new mock instance.
+func NewMockAdminService_StreamWorkflowExecutionRawHistoryServer(ctrl *gomock.Controller) *MockAdminService_StreamWorkflowExecutionRawHistoryServer {
+	mock := &MockAdminService_StreamWorkflowExecutionRawHistoryServer{ctrl: ctrl}
+	mock.recorder = &MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryServer) EXPECT() *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAdminService_StreamWorkflowExecutionRawHistoryServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryServer) Send(arg0 *adminservice.StreamWorkflowExecutionRawHistoryResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAdminService_StreamWorkflowExecutionRawHistoryServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockAdminService_StreamWorkflowExecutionRawHistoryServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockAdminService_StreamWorkflowExecutionRawHistoryServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAdminService_StreamWorkflowExecutionRawHistoryServer)(nil).SetTrailer), arg0)
+}
+
 // MockAdminService_StreamWorkflowReplicationMessagesServer is a mock of AdminService_StreamWorkflowReplicationMessagesServer interface.
 type MockAdminService_StreamWorkflowReplicationMessagesServer struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,276 @@
+// The MIT License
+//
+// Copyright (c) 2019 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// plugins:
+// 	protoc-gen-go
+// 	protoc
+// source: temporal/server/api/dynamicconfigservice/v1/request_response.proto
+
+package dynamicconfigservice
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WatchDynamicConfigRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Identity of the server reading the dynamic config, for the logs and metrics of the service.
+	Identity string `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+}
+
+func (x *WatchDynamicConfigRequest) Reset() {
+	*x = WatchDynamicConfigRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchDynamicConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDynamicConfigRequest) ProtoMessage() {}
+
+func (x *WatchDynamicConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDynamicConfigRequest.ProtoReflect.Descriptor instead.
+func (*WatchDynamicConfigRequest) Descriptor() ([]byte, []int) {
+	return file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WatchDynamicConfigRequest) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+type WatchDynamicConfigResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If snapshot is set, values holds all keys and replaces the values known to the server. Otherwise it only
+	// holds the keys that changed. The first response of a stream is always a snapshot.
+	Snapshot bool `protobuf:"varint,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// Values by dynamic config key. A value is a list of constrained values encoded as YAML or JSON, in the
+	// same format as the value of a key in the dynamic config file.
+	Values map[string][]byte `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Keys that were removed. Only set if snapshot is not.
+	DeletedKeys []string `protobuf:"bytes,3,rep,name=deleted_keys,json=deletedKeys,proto3" json:"deleted_keys,omitempty"`
+}
+
+func (x *WatchDynamicConfigResponse) Reset() {
+	*x = WatchDynamicConfigResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchDynamicConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDynamicConfigResponse) ProtoMessage() {}
+
+func (x *WatchDynamicConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDynamicConfigResponse.ProtoReflect.Descriptor instead.
+func (*WatchDynamicConfigResponse) Descriptor() ([]byte, []int) {
+	return file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WatchDynamicConfigResponse) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *WatchDynamicConfigResponse) GetValues() map[string][]byte {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *WatchDynamicConfigResponse) GetDeletedKeys() []string {
+	if x != nil {
+		return x.DeletedKeys
+	}
+	return nil
+}
+
+var File_temporal_server_api_dynamicconfigservice_v1_request_response_proto protoreflect.FileDescriptor
+
+var file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDesc = []byte{
+	0x0a, 0x42, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x2b, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x6c, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x3b, 0x0a, 0x19, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x42, 0x02, 0x68, 0x00, 0x22, 0x97, 0x02, 0x0a, 0x1a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44,
+	0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x42, 0x02,
+	0x68, 0x00, 0x12, 0x6f, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x53, 0x2e, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x6c, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x42, 0x02, 0x68, 0x00, 0x12, 0x25,
+	0x0a, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x42,
+	0x02, 0x68, 0x00, 0x1a, 0x41, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x14, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x42, 0x02, 0x68, 0x00, 0x12, 0x18, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x02, 0x68, 0x00, 0x3a,
+	0x02, 0x38, 0x01, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x6f, 0x2e, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72,
+	0x61, 0x6c, 0x2e, 0x69, 0x6f, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescOnce sync.Once
+	file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescData = file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDesc
+)
+
+func file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescGZIP() []byte {
+	file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescOnce.Do(func() {
+		file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescData = protoimpl.X.CompressGZIP(file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescData)
+	})
+	return file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDescData
+}
+
+var file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_goTypes = []interface{}{
+	(*WatchDynamicConfigRequest)(nil),  // 0: temporal.server.api.dynamicconfigservice.v1.WatchDynamicConfigRequest
+	(*WatchDynamicConfigResponse)(nil), // 1: temporal.server.api.dynamicconfigservice.v1.WatchDynamicConfigResponse
+	nil,                                // 2: temporal.server.api.dynamicconfigservice.v1.WatchDynamicConfigResponse.ValuesEntry
+}
+var file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_depIdxs = []int32{
+	2, // 0: temporal.server.api.dynamicconfigservice.v1.WatchDynamicConfigResponse.values:type_name -> temporal.server.api.dynamicconfigservice.v1.WatchDynamicConfigResponse.ValuesEntry
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_init() }
+func file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_init() {
+	if File_temporal_server_api_dynamicconfigservice_v1_request_response_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchDynamicConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchDynamicConfigResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_goTypes,
+		DependencyIndexes: file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_depIdxs,
+		MessageInfos:      file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_msgTypes,
+	}.Build()
+	File_temporal_server_api_dynamicconfigservice_v1_request_response_proto = out.File
+	file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_rawDesc = nil
+	file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_goTypes = nil
+	file_temporal_server_api_dynamicconfigservice_v1_request_response_proto_depIdxs = nil
+}
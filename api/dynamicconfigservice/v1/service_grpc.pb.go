@@ -0,0 +1,163 @@
+// The MIT License
+//
+// Copyright (c) 2019 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// plugins:
+// - protoc-gen-go-grpc
+// - protoc
+// source: temporal/server/api/dynamicconfigservice/v1/service.proto
+
+package dynamicconfigservice
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DynamicConfigService_WatchDynamicConfig_FullMethodName = "/temporal.server.api.dynamicconfigservice.v1.DynamicConfigService/WatchDynamicConfig"
+)
+
+// DynamicConfigServiceClient is the client API for DynamicConfigService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DynamicConfigServiceClient interface {
+	// WatchDynamicConfig returns the current values of all dynamic config keys, and then streams the values of
+	// the keys that change for as long as the stream is open.
+	WatchDynamicConfig(ctx context.Context, in *WatchDynamicConfigRequest, opts ...grpc.CallOption) (DynamicConfigService_WatchDynamicConfigClient, error)
+}
+
+type dynamicConfigServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDynamicConfigServiceClient(cc grpc.ClientConnInterface) DynamicConfigServiceClient {
+	return &dynamicConfigServiceClient{cc}
+}
+
+func (c *dynamicConfigServiceClient) WatchDynamicConfig(ctx context.Context, in *WatchDynamicConfigRequest, opts ...grpc.CallOption) (DynamicConfigService_WatchDynamicConfigClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DynamicConfigService_ServiceDesc.Streams[0], DynamicConfigService_WatchDynamicConfig_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dynamicConfigServiceWatchDynamicConfigClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DynamicConfigService_WatchDynamicConfigClient interface {
+	Recv() (*WatchDynamicConfigResponse, error)
+	grpc.ClientStream
+}
+
+type dynamicConfigServiceWatchDynamicConfigClient struct {
+	grpc.ClientStream
+}
+
+func (x *dynamicConfigServiceWatchDynamicConfigClient) Recv() (*WatchDynamicConfigResponse, error) {
+	m := new(WatchDynamicConfigResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DynamicConfigServiceServer is the server API for DynamicConfigService service.
+// All implementations must embed UnimplementedDynamicConfigServiceServer
+// for forward compatibility
+type DynamicConfigServiceServer interface {
+	// WatchDynamicConfig returns the current values of all dynamic config keys, and then streams the values of
+	// the keys that change for as long as the stream is open.
+	WatchDynamicConfig(*WatchDynamicConfigRequest, DynamicConfigService_WatchDynamicConfigServer) error
+	mustEmbedUnimplementedDynamicConfigServiceServer()
+}
+
+// UnimplementedDynamicConfigServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDynamicConfigServiceServer struct {
+}
+
+func (UnimplementedDynamicConfigServiceServer) WatchDynamicConfig(*WatchDynamicConfigRequest, DynamicConfigService_WatchDynamicConfigServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDynamicConfig not implemented")
+}
+func (UnimplementedDynamicConfigServiceServer) mustEmbedUnimplementedDynamicConfigServiceServer() {}
+
+// UnsafeDynamicConfigServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DynamicConfigServiceServer will
+// result in compilation errors.
+type UnsafeDynamicConfigServiceServer interface {
+	mustEmbedUnimplementedDynamicConfigServiceServer()
+}
+
+func RegisterDynamicConfigServiceServer(s grpc.ServiceRegistrar, srv DynamicConfigServiceServer) {
+	s.RegisterService(&DynamicConfigService_ServiceDesc, srv)
+}
+
+func _DynamicConfigService_WatchDynamicConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDynamicConfigRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DynamicConfigServiceServer).WatchDynamicConfig(m, &dynamicConfigServiceWatchDynamicConfigServer{stream})
+}
+
+type DynamicConfigService_WatchDynamicConfigServer interface {
+	Send(*WatchDynamicConfigResponse) error
+	grpc.ServerStream
+}
+
+type dynamicConfigServiceWatchDynamicConfigServer struct {
+	grpc.ServerStream
+}
+
+func (x *dynamicConfigServiceWatchDynamicConfigServer) Send(m *WatchDynamicConfigResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DynamicConfigService_ServiceDesc is the grpc.ServiceDesc for DynamicConfigService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DynamicConfigService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "temporal.server.api.dynamicconfigservice.v1.DynamicConfigService",
+	HandlerType: (*DynamicConfigServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDynamicConfig",
+			Handler:       _DynamicConfigService_WatchDynamicConfig_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "temporal/server/api/dynamicconfigservice/v1/service.proto",
+}
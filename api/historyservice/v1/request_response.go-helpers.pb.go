@@ -3766,6 +3766,80 @@ func (this *ImportWorkflowExecutionResponse) Equal(that interface{}) bool {
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type StreamImportWorkflowExecutionHistoryRequest to the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StreamImportWorkflowExecutionHistoryRequest from the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StreamImportWorkflowExecutionHistoryRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StreamImportWorkflowExecutionHistoryRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StreamImportWorkflowExecutionHistoryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StreamImportWorkflowExecutionHistoryRequest
+	switch t := that.(type) {
+	case *StreamImportWorkflowExecutionHistoryRequest:
+		that1 = t
+	case StreamImportWorkflowExecutionHistoryRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StreamImportWorkflowExecutionHistoryResponse to the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StreamImportWorkflowExecutionHistoryResponse from the protobuf v3 wire format
+func (val *StreamImportWorkflowExecutionHistoryResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StreamImportWorkflowExecutionHistoryResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StreamImportWorkflowExecutionHistoryResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StreamImportWorkflowExecutionHistoryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StreamImportWorkflowExecutionHistoryResponse
+	switch t := that.(type) {
+	case *StreamImportWorkflowExecutionHistoryResponse:
+		that1 = t
+	case StreamImportWorkflowExecutionHistoryResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type DeleteWorkflowVisibilityRecordRequest to the protobuf v3 wire format
 func (val *DeleteWorkflowVisibilityRecordRequest) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
@@ -90,6 +90,7 @@ const (
 	HistoryService_GetReplicationStatus_FullMethodName                   = "/temporal.server.api.historyservice.v1.HistoryService/GetReplicationStatus"
 	HistoryService_RebuildMutableState_FullMethodName                    = "/temporal.server.api.historyservice.v1.HistoryService/RebuildMutableState"
 	HistoryService_ImportWorkflowExecution_FullMethodName                = "/temporal.server.api.historyservice.v1.HistoryService/ImportWorkflowExecution"
+	HistoryService_StreamImportWorkflowExecutionHistory_FullMethodName   = "/temporal.server.api.historyservice.v1.HistoryService/StreamImportWorkflowExecutionHistory"
 	HistoryService_DeleteWorkflowVisibilityRecord_FullMethodName         = "/temporal.server.api.historyservice.v1.HistoryService/DeleteWorkflowVisibilityRecord"
 	HistoryService_UpdateWorkflowExecution_FullMethodName                = "/temporal.server.api.historyservice.v1.HistoryService/UpdateWorkflowExecution"
 	HistoryService_PollWorkflowExecutionUpdate_FullMethodName            = "/temporal.server.api.historyservice.v1.HistoryService/PollWorkflowExecutionUpdate"
@@ -281,6 +282,10 @@ type HistoryServiceClient interface {
 	// ImportWorkflowExecution attempts to import workflow according to persisted history events.
 	// NOTE: this is experimental API
 	ImportWorkflowExecution(ctx context.Context, in *ImportWorkflowExecutionRequest, opts ...grpc.CallOption) (*ImportWorkflowExecutionResponse, error)
+	// StreamImportWorkflowExecutionHistory imports the history of a workflow from the source cluster, starting from
+	// the first event, and streams the import progress back to the caller until the import is done.
+	// NOTE: this is experimental API
+	StreamImportWorkflowExecutionHistory(ctx context.Context, in *StreamImportWorkflowExecutionHistoryRequest, opts ...grpc.CallOption) (HistoryService_StreamImportWorkflowExecutionHistoryClient, error)
 	// DeleteWorkflowVisibilityRecord force delete a workflow's visibility record.
 	// This is used by admin delete workflow execution API to delete visibility record as frontend
 	// visibility manager doesn't support write operations
@@ -757,6 +762,38 @@ func (c *historyServiceClient) ImportWorkflowExecution(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *historyServiceClient) StreamImportWorkflowExecutionHistory(ctx context.Context, in *StreamImportWorkflowExecutionHistoryRequest, opts ...grpc.CallOption) (HistoryService_StreamImportWorkflowExecutionHistoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HistoryService_ServiceDesc.Streams[0], HistoryService_StreamImportWorkflowExecutionHistory_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &historyServiceStreamImportWorkflowExecutionHistoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HistoryService_StreamImportWorkflowExecutionHistoryClient interface {
+	Recv() (*StreamImportWorkflowExecutionHistoryResponse, error)
+	grpc.ClientStream
+}
+
+type historyServiceStreamImportWorkflowExecutionHistoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *historyServiceStreamImportWorkflowExecutionHistoryClient) Recv() (*StreamImportWorkflowExecutionHistoryResponse, error) {
+	m := new(StreamImportWorkflowExecutionHistoryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *historyServiceClient) DeleteWorkflowVisibilityRecord(ctx context.Context, in *DeleteWorkflowVisibilityRecordRequest, opts ...grpc.CallOption) (*DeleteWorkflowVisibilityRecordResponse, error) {
 	out := new(DeleteWorkflowVisibilityRecordResponse)
 	err := c.cc.Invoke(ctx, HistoryService_DeleteWorkflowVisibilityRecord_FullMethodName, in, out, opts...)
@@ -785,7 +822,7 @@ func (c *historyServiceClient) PollWorkflowExecutionUpdate(ctx context.Context,
 }
 
 func (c *historyServiceClient) StreamWorkflowReplicationMessages(ctx context.Context, opts ...grpc.CallOption) (HistoryService_StreamWorkflowReplicationMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &HistoryService_ServiceDesc.Streams[0], HistoryService_StreamWorkflowReplicationMessages_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &HistoryService_ServiceDesc.Streams[1], HistoryService_StreamWorkflowReplicationMessages_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1088,6 +1125,10 @@ type HistoryServiceServer interface {
 	// ImportWorkflowExecution attempts to import workflow according to persisted history events.
 	// NOTE: this is experimental API
 	ImportWorkflowExecution(context.Context, *ImportWorkflowExecutionRequest) (*ImportWorkflowExecutionResponse, error)
+	// StreamImportWorkflowExecutionHistory imports the history of a workflow from the source cluster, starting from
+	// the first event, and streams the import progress back to the caller until the import is done.
+	// NOTE: this is experimental API
+	StreamImportWorkflowExecutionHistory(*StreamImportWorkflowExecutionHistoryRequest, HistoryService_StreamImportWorkflowExecutionHistoryServer) error
 	// DeleteWorkflowVisibilityRecord force delete a workflow's visibility record.
 	// This is used by admin delete workflow execution API to delete visibility record as frontend
 	// visibility manager doesn't support write operations
@@ -1273,6 +1314,9 @@ func (UnimplementedHistoryServiceServer) RebuildMutableState(context.Context, *R
 func (UnimplementedHistoryServiceServer) ImportWorkflowExecution(context.Context, *ImportWorkflowExecutionRequest) (*ImportWorkflowExecutionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportWorkflowExecution not implemented")
 }
+func (UnimplementedHistoryServiceServer) StreamImportWorkflowExecutionHistory(*StreamImportWorkflowExecutionHistoryRequest, HistoryService_StreamImportWorkflowExecutionHistoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamImportWorkflowExecutionHistory not implemented")
+}
 func (UnimplementedHistoryServiceServer) DeleteWorkflowVisibilityRecord(context.Context, *DeleteWorkflowVisibilityRecordRequest) (*DeleteWorkflowVisibilityRecordResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkflowVisibilityRecord not implemented")
 }
@@ -2195,6 +2239,27 @@ func _HistoryService_ImportWorkflowExecution_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HistoryService_StreamImportWorkflowExecutionHistory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamImportWorkflowExecutionHistoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HistoryServiceServer).StreamImportWorkflowExecutionHistory(m, &historyServiceStreamImportWorkflowExecutionHistoryServer{stream})
+}
+
+type HistoryService_StreamImportWorkflowExecutionHistoryServer interface {
+	Send(*StreamImportWorkflowExecutionHistoryResponse) error
+	grpc.ServerStream
+}
+
+type historyServiceStreamImportWorkflowExecutionHistoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *historyServiceStreamImportWorkflowExecutionHistoryServer) Send(m *StreamImportWorkflowExecutionHistoryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _HistoryService_DeleteWorkflowVisibilityRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteWorkflowVisibilityRecordRequest)
 	if err := dec(in); err != nil {
@@ -2730,6 +2795,11 @@ var HistoryService_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamImportWorkflowExecutionHistory",
+			Handler:       _HistoryService_StreamImportWorkflowExecutionHistory_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamWorkflowReplicationMessages",
 			Handler:       _HistoryService_StreamWorkflowReplicationMessages_Handler,
@@ -1181,6 +1181,26 @@ func (mr *MockHistoryServiceClientMockRecorder) StartWorkflowExecution(ctx, in i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWorkflowExecution", reflect.TypeOf((*MockHistoryServiceClient)(nil).StartWorkflowExecution), varargs...)
 }
 
+// StreamImportWorkflowExecutionHistory mocks base method.
+func (m *MockHistoryServiceClient) StreamImportWorkflowExecutionHistory(ctx context.Context, in *historyservice.StreamImportWorkflowExecutionHistoryRequest, opts ...grpc.CallOption) (historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StreamImportWorkflowExecutionHistory", varargs...)
+	ret0, _ := ret[0].(historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamImportWorkflowExecutionHistory indicates an expected call of StreamImportWorkflowExecutionHistory.
+func (mr *MockHistoryServiceClientMockRecorder) StreamImportWorkflowExecutionHistory(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamImportWorkflowExecutionHistory", reflect.TypeOf((*MockHistoryServiceClient)(nil).StreamImportWorkflowExecutionHistory), varargs...)
+}
+
 // StreamWorkflowReplicationMessages mocks base method.
 func (m *MockHistoryServiceClient) StreamWorkflowReplicationMessages(ctx context.Context, opts ...grpc.CallOption) (historyservice.HistoryService_StreamWorkflowReplicationMessagesClient, error) {
 	m.ctrl.T.Helper()
@@ -1321,6 +1341,129 @@ func (mr *MockHistoryServiceClientMockRecorder) VerifyFirstWorkflowTaskScheduled
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyFirstWorkflowTaskScheduled", reflect.TypeOf((*MockHistoryServiceClient)(nil).VerifyFirstWorkflowTaskScheduled), varargs...)
 }
 
+// MockHistoryService_StreamImportWorkflowExecutionHistoryClient is a mock of HistoryService_StreamImportWorkflowExecutionHistoryClient interface.
+type MockHistoryService_StreamImportWorkflowExecutionHistoryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder
+}
+
+// MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder is the mock recorder for MockHistoryService_StreamImportWorkflowExecutionHistoryClient.
+type MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder struct {
+	mock *MockHistoryService_StreamImportWorkflowExecutionHistoryClient
+}
+
+// NewMockHistoryService_StreamImportWorkflowExecutionHistoryClient creates a new mock instance.
+func NewMockHistoryService_StreamImportWorkflowExecutionHistoryClient(ctrl *gomock.Controller) *MockHistoryService_StreamImportWorkflowExecutionHistoryClient {
+	mock := &MockHistoryService_StreamImportWorkflowExecutionHistoryClient{ctrl: ctrl}
+	mock.recorder = &MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) EXPECT() *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) Recv() (*historyservice.StreamImportWorkflowExecutionHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*historyservice.StreamImportWorkflowExecutionHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryClient)(nil).Trailer))
+}
+
 // MockHistoryService_StreamWorkflowReplicationMessagesClient is a mock of HistoryService_StreamWorkflowReplicationMessagesClient interface.
 type MockHistoryService_StreamWorkflowReplicationMessagesClient struct {
 	ctrl     *gomock.Controller
@@ -2321,6 +2464,20 @@ func (mr *MockHistoryServiceServerMockRecorder) StartWorkflowExecution(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWorkflowExecution", reflect.TypeOf((*MockHistoryServiceServer)(nil).StartWorkflowExecution), arg0, arg1)
 }
 
+// StreamImportWorkflowExecutionHistory mocks base method.
+func (m *MockHistoryServiceServer) StreamImportWorkflowExecutionHistory(arg0 *historyservice.StreamImportWorkflowExecutionHistoryRequest, arg1 historyservice.HistoryService_StreamImportWorkflowExecutionHistoryServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamImportWorkflowExecutionHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamImportWorkflowExecutionHistory indicates an expected call of StreamImportWorkflowExecutionHistory.
+func (mr *MockHistoryServiceServerMockRecorder) StreamImportWorkflowExecutionHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamImportWorkflowExecutionHistory", reflect.TypeOf((*MockHistoryServiceServer)(nil).StreamImportWorkflowExecutionHistory), arg0, arg1)
+}
+
 // StreamWorkflowReplicationMessages mocks base method.
 func (m *MockHistoryServiceServer) StreamWorkflowReplicationMessages(arg0 historyservice.HistoryService_StreamWorkflowReplicationMessagesServer) error {
 	m.ctrl.T.Helper()
@@ -2472,6 +2629,125 @@ func (mr *MockUnsafeHistoryServiceServerMockRecorder) mustEmbedUnimplementedHist
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedHistoryServiceServer", reflect.TypeOf((*MockUnsafeHistoryServiceServer)(nil).mustEmbedUnimplementedHistoryServiceServer))
 }
 
+// MockHistoryService_StreamImportWorkflowExecutionHistoryServer is a mock of HistoryService_StreamImportWorkflowExecutionHistoryServer interface.
+type MockHistoryService_StreamImportWorkflowExecutionHistoryServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder
+}
+
+// MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder is the mock recorder for MockHistoryService_StreamImportWorkflowExecutionHistoryServer.
+type MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder struct {
+	mock *MockHistoryService_StreamImportWorkflowExecutionHistoryServer
+}
+
+// NewMockHistoryService_StreamImportWorkflowExecutionHistoryServer creates a new mock instance.
+func NewMockHistoryService_StreamImportWorkflowExecutionHistoryServer(ctrl *gomock.Controller) *MockHistoryService_StreamImportWorkflowExecutionHistoryServer {
+	mock := &MockHistoryService_StreamImportWorkflowExecutionHistoryServer{ctrl: ctrl}
+	mock.recorder = &MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) EXPECT() *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) Send(arg0 *historyservice.StreamImportWorkflowExecutionHistoryResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockHistoryService_StreamImportWorkflowExecutionHistoryServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockHistoryService_StreamImportWorkflowExecutionHistoryServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockHistoryService_StreamImportWorkflowExecutionHistoryServer)(nil).SetTrailer), arg0)
+}
+
 // MockHistoryService_StreamWorkflowReplicationMessagesServer is a mock of HistoryService_StreamWorkflowReplicationMessagesServer interface.
 type MockHistoryService_StreamWorkflowReplicationMessagesServer struct {
 	ctrl     *gomock.Controller
@@ -66,6 +66,43 @@ func (this *ShardInfo) Equal(that interface{}) bool {
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type QuarantinedWorkflow to the protobuf v3 wire format
+func (val *QuarantinedWorkflow) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type QuarantinedWorkflow from the protobuf v3 wire format
+func (val *QuarantinedWorkflow) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *QuarantinedWorkflow) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two QuarantinedWorkflow values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *QuarantinedWorkflow) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *QuarantinedWorkflow
+	switch t := that.(type) {
+	case *QuarantinedWorkflow:
+		that1 = t
+	case QuarantinedWorkflow:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type WorkflowExecutionInfo to the protobuf v3 wire format
 func (val *WorkflowExecutionInfo) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
@@ -83,3 +83,21 @@ func (c *clientImpl) StreamWorkflowReplicationMessages(
 	// do not use createContext function, let caller manage stream API lifecycle
 	return c.client.StreamWorkflowReplicationMessages(ctx, opts...)
 }
+
+func (c *clientImpl) StreamWorkflowExecutionRawHistory(
+	ctx context.Context,
+	request *adminservice.StreamWorkflowExecutionRawHistoryRequest,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+	// do not use createContext function, let caller manage stream API lifecycle
+	return c.client.StreamWorkflowExecutionRawHistory(ctx, request, opts...)
+}
+
+func (c *clientImpl) StreamImportWorkflowExecutionHistory(
+	ctx context.Context,
+	request *adminservice.StreamImportWorkflowExecutionHistoryRequest,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamImportWorkflowExecutionHistoryClient, error) {
+	// do not use createContext function, let caller manage stream API lifecycle
+	return c.client.StreamImportWorkflowExecutionHistory(ctx, request, opts...)
+}
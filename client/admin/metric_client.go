@@ -104,3 +104,31 @@ func (c *metricClient) StreamWorkflowReplicationMessages(
 
 	return c.client.StreamWorkflowReplicationMessages(ctx, opts...)
 }
+
+func (c *metricClient) StreamWorkflowExecutionRawHistory(
+	ctx context.Context,
+	request *adminservice.StreamWorkflowExecutionRawHistoryRequest,
+	opts ...grpc.CallOption,
+) (_ adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, metrics.AdminClientStreamWorkflowExecutionRawHistoryScope)
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.StreamWorkflowExecutionRawHistory(ctx, request, opts...)
+}
+
+func (c *metricClient) StreamImportWorkflowExecutionHistory(
+	ctx context.Context,
+	request *adminservice.StreamImportWorkflowExecutionHistoryRequest,
+	opts ...grpc.CallOption,
+) (_ adminservice.AdminService_StreamImportWorkflowExecutionHistoryClient, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, metrics.AdminClientStreamImportWorkflowExecutionHistoryScope)
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.StreamImportWorkflowExecutionHistory(ctx, request, opts...)
+}
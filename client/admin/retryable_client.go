@@ -63,3 +63,33 @@ func (c *retryableClient) StreamWorkflowReplicationMessages(
 	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) StreamWorkflowExecutionRawHistory(
+	ctx context.Context,
+	request *adminservice.StreamWorkflowExecutionRawHistoryRequest,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+	var resp adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.StreamWorkflowExecutionRawHistory(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) StreamImportWorkflowExecutionHistory(
+	ctx context.Context,
+	request *adminservice.StreamImportWorkflowExecutionHistoryRequest,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamImportWorkflowExecutionHistoryClient, error) {
+	var resp adminservice.AdminService_StreamImportWorkflowExecutionHistoryClient
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.StreamImportWorkflowExecutionHistory(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
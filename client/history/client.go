@@ -261,6 +261,25 @@ func (c *clientImpl) StreamWorkflowReplicationMessages(
 	)
 }
 
+func (c *clientImpl) StreamImportWorkflowExecutionHistory(
+	ctx context.Context,
+	request *historyservice.StreamImportWorkflowExecutionHistoryRequest,
+	opts ...grpc.CallOption,
+) (historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient, error) {
+	shardID := c.shardIDFromWorkflowID(request.GetNamespaceId(), request.GetExecution().GetWorkflowId())
+	var stream historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient
+	op := func(ctx context.Context, client historyservice.HistoryServiceClient) error {
+		var err error
+		// do not use createContext function, let caller manage stream API lifecycle
+		stream, err = client.StreamImportWorkflowExecutionHistory(ctx, request, opts...)
+		return err
+	}
+	if err := c.executeWithRedirect(ctx, shardID, op); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
 // GetDLQTasks doesn't need redirects or routing because DLQ tasks are not sharded, so it just picks any available host
 // in the connection pool (or creates one) and forwards the request to it.
 func (c *clientImpl) GetDLQTasks(
@@ -75,6 +75,20 @@ func (c *metricClient) StreamWorkflowReplicationMessages(
 	return c.client.StreamWorkflowReplicationMessages(ctx, opts...)
 }
 
+func (c *metricClient) StreamImportWorkflowExecutionHistory(
+	ctx context.Context,
+	request *historyservice.StreamImportWorkflowExecutionHistoryRequest,
+	opts ...grpc.CallOption,
+) (_ historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, metrics.HistoryClientStreamImportWorkflowExecutionHistoryScope)
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.StreamImportWorkflowExecutionHistory(ctx, request, opts...)
+}
+
 func (c *metricClient) startMetricsRecording(
 	ctx context.Context,
 	operation string,
@@ -63,3 +63,18 @@ func (c *retryableClient) StreamWorkflowReplicationMessages(
 	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) StreamImportWorkflowExecutionHistory(
+	ctx context.Context,
+	request *historyservice.StreamImportWorkflowExecutionHistoryRequest,
+	opts ...grpc.CallOption,
+) (historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient, error) {
+	var resp historyservice.HistoryService_StreamImportWorkflowExecutionHistoryClient
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.StreamImportWorkflowExecutionHistory(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
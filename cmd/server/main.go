@@ -143,6 +143,10 @@ func buildCLI() *cli.App {
 					Value:   cli.NewStringSlice(temporal.DefaultServices...),
 					Usage:   "service(s) to start",
 				},
+				&cli.StringSliceFlag{
+					Name:  "dynamic-config",
+					Usage: "override a dynamic config key for this process, as key=value (can be repeated)",
+				},
 			},
 			Before: func(c *cli.Context) error {
 				if c.Args().Len() > 0 {
@@ -191,10 +195,19 @@ func buildCLI() *cli.App {
 					if err != nil {
 						return cli.Exit(fmt.Sprintf("Unable to create dynamic config client. Error: %v", err), 1)
 					}
+					// values constrained to an environment apply to the --env the server was started with
+					dynamicConfigClient = dynamicconfig.NewEnvironmentClient(dynamicConfigClient, env)
 				} else {
 					dynamicConfigClient = dynamicconfig.NewNoopClient()
 					logger.Info("Dynamic config client is not configured. Using noop client.")
 				}
+				if overrides := c.StringSlice("dynamic-config"); len(overrides) > 0 {
+					dynamicConfigClient, err = dynamicconfig.NewOverlayClient(dynamicConfigClient, overrides)
+					if err != nil {
+						return cli.Exit(fmt.Sprintf("Unable to apply dynamic config overrides. Error: %v", err), 1)
+					}
+					logger.Warn("Dynamic config keys are overridden from the command line.", tag.NewStringsTag("overrides", overrides))
+				}
 
 				authorizer, err := authorization.GetAuthorizerFromConfig(
 					&cfg.Global.Authorization,
@@ -41,12 +41,15 @@ type (
 		Name      string
 		GoType    string
 		IsGeneric bool
+		IsOrdered bool // can have bounds
 	}
 	settingPrecedence struct {
 		Name   string
 		GoArgs string
 		Expr   string
 		Index  int
+		// ConsArgs are the values for GoArgs taken from a Constraints struct named cons
+		ConsArgs string
 	}
 )
 
@@ -57,25 +60,32 @@ var (
 			GoType: "bool",
 		},
 		{
-			Name:   "Int",
-			GoType: "int",
+			Name:      "Int",
+			GoType:    "int",
+			IsOrdered: true,
 		},
 		{
-			Name:   "Float",
-			GoType: "float64",
+			Name:      "Float",
+			GoType:    "float64",
+			IsOrdered: true,
 		},
 		{
 			Name:   "String",
 			GoType: "string",
 		},
 		{
-			Name:   "Duration",
-			GoType: "time.Duration",
+			Name:      "Duration",
+			GoType:    "time.Duration",
+			IsOrdered: true,
 		},
 		{
 			Name:   "Map",
 			GoType: "map[string]any",
 		},
+		{
+			Name:   "StringSlice",
+			GoType: "[]string",
+		},
 		{
 			Name:      "Typed",
 			GoType:    "<generic>",
@@ -84,23 +94,27 @@ var (
 	}
 	precedences = []*settingPrecedence{
 		{
-			Name:   "Global",
-			GoArgs: "",
-			Expr:   "[]Constraints{{}}",
+			Name:     "Global",
+			GoArgs:   "",
+			Expr:     "[]Constraints{{}}",
+			ConsArgs: "",
 		},
 		{
-			Name:   "Namespace",
-			GoArgs: "namespace string",
-			Expr:   "[]Constraints{{Namespace: namespace}, {}}",
+			Name:     "Namespace",
+			GoArgs:   "namespace string",
+			Expr:     "[]Constraints{{Namespace: namespace}, {}}",
+			ConsArgs: "cons.Namespace",
 		},
 		{
-			Name:   "NamespaceID",
-			GoArgs: "namespaceID string",
-			Expr:   "[]Constraints{{NamespaceID: namespaceID}, {}}",
+			Name:     "NamespaceID",
+			GoArgs:   "namespaceID string",
+			Expr:     "[]Constraints{{NamespaceID: namespaceID}, {}}",
+			ConsArgs: "cons.NamespaceID",
 		},
 		{
-			Name:   "TaskQueue",
-			GoArgs: "namespace string, taskQueue string, taskQueueType enumspb.TaskQueueType",
+			Name:     "TaskQueue",
+			GoArgs:   "namespace string, taskQueue string, taskQueueType enumspb.TaskQueueType",
+			ConsArgs: "cons.Namespace, cons.TaskQueueName, cons.TaskQueueType",
 			// A task-queue-name-only filter applies to a single task queue name across all
 			// namespaces, with higher precedence than a namespace-only filter. This is intended to
 			// be used by the default partition count and is probably not useful otherwise.
@@ -113,18 +127,21 @@ var (
 		}`,
 		},
 		{
-			Name:   "ShardID",
-			GoArgs: "shardID int32",
-			Expr:   "[]Constraints{{ShardID: shardID}, {}}",
+			Name:     "ShardID",
+			GoArgs:   "shardID int32",
+			Expr:     "[]Constraints{{ShardID: shardID}, {}}",
+			ConsArgs: "cons.ShardID",
 		},
 		{
-			Name:   "TaskType",
-			GoArgs: "taskType enumsspb.TaskType",
-			Expr:   "[]Constraints{{TaskType: taskType}, {}}",
+			Name:     "TaskType",
+			GoArgs:   "taskType enumsspb.TaskType",
+			Expr:     "[]Constraints{{TaskType: taskType}, {}}",
+			ConsArgs: "cons.TaskType",
 		},
 		{
-			Name:   "Destination",
-			GoArgs: "namespace string, destination string",
+			Name:     "Destination",
+			GoArgs:   "namespace string, destination string",
+			ConsArgs: "cons.Namespace, cons.Destination",
 			Expr: `[]Constraints{
 			{Namespace: namespace, Destination: destination},
 			{Destination: destination},
@@ -132,6 +149,27 @@ var (
 			{},
 		}`,
 		},
+		{
+			Name:     "WorkflowType",
+			GoArgs:   "namespace string, workflowType string",
+			ConsArgs: "cons.Namespace, cons.WorkflowType",
+			Expr: `[]Constraints{
+			{Namespace: namespace, WorkflowType: workflowType},
+			{Namespace: namespace},
+			{},
+		}`,
+		},
+		{
+			Name:     "SDKVersion",
+			GoArgs:   "namespace string, sdkName string, sdkVersion string",
+			ConsArgs: "cons.Namespace, cons.SDKName, cons.SDKVersion",
+			Expr: `[]Constraints{
+			{Namespace: namespace, SDKName: sdkName, SDKVersion: sdkVersion},
+			{SDKName: sdkName, SDKVersion: sdkVersion},
+			{Namespace: namespace},
+			{},
+		}`,
+		},
 	}
 )
 
@@ -200,6 +238,7 @@ func (s {{.P.Name}}TypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s {{.P.Name}}TypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s {{.P.Name}}TypedSetting[T]) WithDefault(v T) {{.P.Name}}TypedSetting[T] {
 	newS := s
@@ -208,6 +247,24 @@ func (s {{.P.Name}}TypedSetting[T]) WithDefault(v T) {{.P.Name}}TypedSetting[T]
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s {{.P.Name}}TypedSetting[T]) WithAccept(accept func(T) bool) {{.P.Name}}TypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s {{.P.Name}}TypedSetting[T]) WithValidator(validate func(T) error) {{.P.Name}}TypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 {{if eq .P.Name "Global" -}}
 type TypedPropertyFn[T any] func({{.P.GoArgs}}) T
 {{- else -}}
@@ -227,11 +284,82 @@ func (s {{.P.Name}}TypedSetting[T]) Get(c *Collection) TypedPropertyFnWith{{.P.N
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+{{if eq .P.Name "Global" -}}
+func (s {{.P.Name}}TypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFn[T] {
+{{- else -}}
+func (s {{.P.Name}}TypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWith{{.P.Name}}Filter[T] {
+{{- end}}
+	return func({{.P.GoArgs}}) T {
+		prec := {{.P.Expr}}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s {{.P.Name}}TypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func({{.P.GoArgs}}) []Constraints {
+		return {{.P.Expr}}
+	}({{.P.ConsArgs}})
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s {{.P.Name}}TypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(Precedence{{.P.Name}}, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s {{.P.Name}}TypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(Precedence{{.P.Name}}),
+	)
+}
+
 {{if eq .P.Name "Global" -}}
 func GetTypedPropertyFn[T any](value T) TypedPropertyFn[T] {
 {{- else -}}
@@ -248,6 +376,22 @@ func New{{.P.Name}}{{.T.Name}}Setting(key Key, def {{.T.GoType}}, description st
 	return New{{.P.Name}}TypedSettingWithConverter[{{.T.GoType}}](key, convert{{.T.Name}}, def, description)
 }
 
+{{if .T.IsOrdered -}}
+// New{{.P.Name}}{{.T.Name}}SettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func New{{.P.Name}}{{.T.Name}}SettingWithBounds(key Key, def {{.T.GoType}}, bounds Bounds[{{.T.GoType}}], description string) {{.P.Name}}{{.T.Name}}Setting {
+	s := {{.P.Name}}{{.T.Name}}Setting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convert{{.T.Name}}, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+{{end -}}
 func New{{.P.Name}}{{.T.Name}}SettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[{{.T.GoType}}], description string) {{.P.Name}}{{.T.Name}}Setting {
 	return New{{.P.Name}}TypedSettingWithConstrainedDefault[{{.T.GoType}}](key, convert{{.T.Name}}, cdef, description)
 }
@@ -271,15 +415,49 @@ func Get{{.T.Name}}PropertyFnFilteredBy{{.P.Name}}(value {{.T.GoType}}) {{.T.Nam
 `, map[string]any{"T": tp, "P": prec})
 }
 
+// ArgNames returns the names of GoArgs, e.g. "namespace, taskQueue" for
+// "namespace string, taskQueue string".
+func (p *settingPrecedence) ArgNames() string {
+	var names []string
+	for _, arg := range strings.Split(p.GoArgs, ",") {
+		if fields := strings.Fields(arg); len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func generatePrecedenceConstraints(w io.Writer) {
+	writeTemplatedCode(w, `
+// precedenceConstraints returns the precedence list of the given precedence, taking the filter
+// values from cons.
+func precedenceConstraints(p Precedence, cons Constraints) []Constraints {
+	switch p {
+{{- range .}}
+	case Precedence{{.Name}}:
+		{{- if .GoArgs}}
+		{{.ArgNames}} := {{.ConsArgs}}
+		{{- end}}
+		return {{.Expr}}
+{{- end}}
+	default:
+		return nil
+	}
+}
+`, precedences)
+}
+
 func generate(w io.Writer) {
 	writeTemplatedCode(w, `
 package dynamicconfig
 
 import (
+	"context"
 	"time"
 
 	enumspb "go.temporal.io/api/enums/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/common/log"
 )
 
 const PrecedenceUnknown Precedence = 0
@@ -293,6 +471,7 @@ const PrecedenceUnknown Precedence = 0
 			generateType(w, tp, prec)
 		}
 	}
+	generatePrecedenceConstraints(w)
 }
 
 func checkParses(filename string) {
@@ -90,12 +90,21 @@ var (
 	}
 	ignoreMethod = map[string]bool{
 		// TODO stream APIs are not supported. do not generate.
-		"client.admin.StreamWorkflowReplicationMessages":            true,
-		"metricsClient.admin.StreamWorkflowReplicationMessages":     true,
-		"retryableClient.admin.StreamWorkflowReplicationMessages":   true,
-		"client.history.StreamWorkflowReplicationMessages":          true,
-		"metricsClient.history.StreamWorkflowReplicationMessages":   true,
-		"retryableClient.history.StreamWorkflowReplicationMessages": true,
+		"client.admin.StreamWorkflowReplicationMessages":               true,
+		"metricsClient.admin.StreamWorkflowReplicationMessages":        true,
+		"retryableClient.admin.StreamWorkflowReplicationMessages":      true,
+		"client.admin.StreamWorkflowExecutionRawHistory":               true,
+		"metricsClient.admin.StreamWorkflowExecutionRawHistory":        true,
+		"retryableClient.admin.StreamWorkflowExecutionRawHistory":      true,
+		"client.admin.StreamImportWorkflowExecutionHistory":            true,
+		"metricsClient.admin.StreamImportWorkflowExecutionHistory":     true,
+		"retryableClient.admin.StreamImportWorkflowExecutionHistory":   true,
+		"client.history.StreamWorkflowReplicationMessages":             true,
+		"metricsClient.history.StreamWorkflowReplicationMessages":      true,
+		"retryableClient.history.StreamWorkflowReplicationMessages":    true,
+		"client.history.StreamImportWorkflowExecutionHistory":          true,
+		"metricsClient.history.StreamImportWorkflowExecutionHistory":   true,
+		"retryableClient.history.StreamImportWorkflowExecutionHistory": true,
 
 		// these are non-standard implementations. do not generate.
 		"client.history.DescribeHistoryHost":    true,
@@ -0,0 +1,129 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// bindingPollInterval is how often bound values are resolved again, see GlobalTypedSetting.Bind.
+	bindingPollInterval = 10 * time.Second
+)
+
+type (
+	// bindingState holds the refresh functions of the values bound to a Collection. The poll
+	// loop only runs while there is at least one binding.
+	bindingState struct {
+		sync.Mutex
+		nextID   int
+		bindings map[int]func()
+		stopCh   chan struct{}
+	}
+)
+
+// Bind returns a pointer to the value of the setting that c keeps up to date, so that very hot
+// paths can read the value with a single atomic load instead of resolving the setting on each
+// read. Changes are picked up within bindingPollInterval, or right away by Collection.Reload.
+//
+// The returned function stops updating the pointer and must be called once it's no longer
+// needed, otherwise the binding is leaked. The pointer keeps its last value after that.
+func (s GlobalTypedSetting[T]) Bind(c *Collection) (*atomic.Pointer[T], func()) {
+	get := s.Get(c)
+	var ptr atomic.Pointer[T]
+	initial := get()
+	ptr.Store(&initial)
+
+	refresh := func() {
+		val := get()
+		if !reflect.DeepEqual(*ptr.Load(), val) {
+			ptr.Store(&val)
+		}
+	}
+	return &ptr, c.bindings.add(c, refresh)
+}
+
+func (b *bindingState) add(c *Collection, refresh func()) func() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.bindings == nil {
+		b.bindings = make(map[int]func())
+	}
+	id := b.nextID
+	b.nextID++
+	b.bindings[id] = refresh
+	if b.stopCh == nil {
+		b.stopCh = make(chan struct{})
+		go c.pollBindings(b.stopCh)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.remove(id) })
+	}
+}
+
+func (b *bindingState) remove(id int) {
+	b.Lock()
+	defer b.Unlock()
+
+	delete(b.bindings, id)
+	if len(b.bindings) == 0 && b.stopCh != nil {
+		close(b.stopCh)
+		b.stopCh = nil
+	}
+}
+
+// refresh resolves all bound values again. Refresh functions are called without holding the
+// lock, so that a slow client doesn't block adding and removing bindings.
+func (b *bindingState) refresh() {
+	b.Lock()
+	refreshFns := make([]func(), 0, len(b.bindings))
+	for _, refresh := range b.bindings {
+		refreshFns = append(refreshFns, refresh)
+	}
+	b.Unlock()
+
+	for _, refresh := range refreshFns {
+		refresh()
+	}
+}
+
+func (c *Collection) pollBindings(stopCh <-chan struct{}) {
+	for {
+		timerCh, timer := c.timeSource.NewTimer(bindingPollInterval)
+		select {
+		case <-timerCh:
+			c.bindings.refresh()
+		case <-stopCh:
+			timer.Stop()
+			return
+		}
+	}
+}
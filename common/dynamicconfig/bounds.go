@@ -0,0 +1,118 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"cmp"
+	"fmt"
+)
+
+type (
+	// BoundsPolicy controls what happens when a bounded setting has a value outside of its bounds.
+	BoundsPolicy int
+
+	// Bounds is an inclusive range of valid values for a numeric setting. Policy must be set
+	// explicitly, there is no default.
+	Bounds[T cmp.Ordered] struct {
+		Min    T
+		Max    T
+		Policy BoundsPolicy
+	}
+
+	// SettingBounds is the type-erased form of Bounds, exposed through GenericSetting so that
+	// tooling can inspect the valid range of a setting without knowing its type.
+	SettingBounds struct {
+		Min    any
+		Max    any
+		Policy BoundsPolicy
+	}
+
+	// outOfBoundsError is returned by bounded converters for out-of-range values. If the value
+	// was clamped, it's wrapped in a conversionWarning along with the clamped value.
+	outOfBoundsError struct {
+		value    any
+		min, max any
+	}
+)
+
+const (
+	// BoundsPolicyUnspecified is invalid, it's only used to catch Bounds with no policy set.
+	BoundsPolicyUnspecified BoundsPolicy = iota
+	// BoundsClamp replaces out-of-range values with the nearest bound.
+	BoundsClamp
+	// BoundsReject ignores out-of-range values and uses the default instead.
+	BoundsReject
+)
+
+func (e *outOfBoundsError) Error() string {
+	return fmt.Sprintf("value %v is out of bounds [%v, %v]", e.value, e.min, e.max)
+}
+
+func (b Bounds[T]) erase() *SettingBounds {
+	return &SettingBounds{
+		Min:    b.Min,
+		Max:    b.Max,
+		Policy: b.Policy,
+	}
+}
+
+// boundedConverter wraps convert so that converted values are checked against b. It panics if
+// the bounds are invalid or don't contain the default, since that's a bug in the setting definition.
+func boundedConverter[T cmp.Ordered](
+	key Key,
+	def T,
+	convert func(any) (T, error),
+	b Bounds[T],
+) func(any) (T, error) {
+	if b.Policy != BoundsClamp && b.Policy != BoundsReject {
+		panic(fmt.Sprintf("invalid bounds policy for dynamic config key %q: %v", key, b.Policy))
+	}
+	if b.Min > b.Max {
+		panic(fmt.Sprintf("invalid bounds for dynamic config key %q: min %v is greater than max %v", key, b.Min, b.Max))
+	}
+	if def < b.Min || def > b.Max {
+		panic(fmt.Sprintf("default value for dynamic config key %q is out of bounds: %v not in [%v, %v]", key, def, b.Min, b.Max))
+	}
+
+	return func(v any) (T, error) {
+		typedV, err := convert(v)
+		if err != nil || (typedV >= b.Min && typedV <= b.Max) {
+			return typedV, err
+		}
+		boundsErr := &outOfBoundsError{value: typedV, min: b.Min, max: b.Max}
+		if b.Policy == BoundsReject {
+			return typedV, boundsErr
+		}
+		switch {
+		case typedV < b.Min:
+			return b.Min, newConversionWarning("Value out of bounds, clamping", boundsErr)
+		case typedV > b.Max:
+			return b.Max, newConversionWarning("Value out of bounds, clamping", boundsErr)
+		default:
+			// NaN is neither below nor above the bounds, so it can't be clamped
+			return typedV, boundsErr
+		}
+	}
+}
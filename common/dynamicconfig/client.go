@@ -25,6 +25,9 @@
 package dynamicconfig
 
 import (
+	"context"
+	"time"
+
 	enumspb "go.temporal.io/api/enums/v1"
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
@@ -51,6 +54,14 @@ type (
 		GetValue(key Key) []ConstrainedValue
 	}
 
+	// ContextClient is a Client whose lookups can be canceled. If a Client also implements
+	// ContextClient, GetValueWithContext is used instead of GetValue, and ctx is canceled when
+	// the caller gives up on the lookup, e.g. when ResolveWithTimeout times out.
+	ContextClient interface {
+		Client
+		GetValueWithContext(ctx context.Context, key Key) []ConstrainedValue
+	}
+
 	// Key is a key/property stored in dynamic config. For convenience, it is recommended that
 	// you treat keys as case-insensitive.
 	Key string
@@ -64,9 +75,14 @@ type (
 	// timestamp.ParseDurationDefaultDays. If float64 is expected, int is also accepted. In
 	// other cases, the exact type must be used. If a Value is returned with an unexpected
 	// type, it will be ignored.
+	//
+	// If ExpiresAt is set, the value is ignored from that time on, as if it weren't present, so
+	// the key reverts to the next matching value or the server default. This is meant for
+	// temporary overrides that should not outlive an incident.
 	ConstrainedValue struct {
 		Constraints Constraints
 		Value       any
+		ExpiresAt   time.Time
 	}
 	TypedConstrainedValue[T any] struct {
 		Constraints Constraints
@@ -89,12 +105,26 @@ type (
 	//   shard id precedence:
 	//     ShardID
 	//     no constraints
+	//   workflow type precedence:
+	//     Namespace+WorkflowType
+	//     Namespace
+	//     no constraints
+	//   sdk version precedence:
+	//     Namespace+SDKName+SDKVersion
+	//     SDKName+SDKVersion
+	//     Namespace
+	//     no constraints
+	// Values can additionally be constrained to a deployment Environment, see
+	// NewEnvironmentClient. For the environment the server runs in, such a value takes precedence
+	// over the value with the same other constraints but no environment.
 	// In each case, the constraints that the server is checking and the constraints that apply
 	// to the value must match exactly, including the fields that are not set (zero values).
 	// That is, for keys that use namespace precedence, you must either return a
 	// ConstrainedValue with only Namespace set, or with no fields set. (Or return one of
 	// each.) If you return a ConstrainedValue with Namespace and ShardID set, for example,
 	// that value will never be used, even if the Namespace matches.
+	// The only exception is SDKVersion: the SDKVersion of a value is a semver range, e.g.
+	// ">=1.20.0" or ">=1.20.0 <2.0.0", and the version the server is checking must be in it.
 	Constraints struct {
 		Namespace     string
 		NamespaceID   string
@@ -103,6 +133,10 @@ type (
 		ShardID       int32
 		TaskType      enumsspb.TaskType
 		Destination   string
+		WorkflowType  string
+		SDKName       string
+		SDKVersion    string
+		Environment   string
 	}
 )
 
@@ -0,0 +1,104 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"slices"
+)
+
+type (
+	// ClusterPriorityList is an ordered list of cluster names, most preferred first, e.g. the
+	// order in which to pick a replication source or a failover target.
+	ClusterPriorityList []string
+
+	// ClusterPriorityListPropertyFn returns a ClusterPriorityList that is global.
+	ClusterPriorityListPropertyFn = TypedPropertyFn[ClusterPriorityList]
+
+	// droppedClustersError is returned by the ClusterPriorityList converter, wrapped in a
+	// conversionWarning along with a usable value, when some configured names were dropped.
+	droppedClustersError struct {
+		unknown    []string
+		duplicates []string
+	}
+)
+
+// Primary returns the most preferred cluster, or "" if the list is empty.
+func (l ClusterPriorityList) Primary() string {
+	if len(l) == 0 {
+		return ""
+	}
+	return l[0]
+}
+
+// NextAfter returns the cluster that comes after cluster in the list, or "" if cluster is the
+// last one. If cluster is not in the list, it returns the primary cluster.
+func (l ClusterPriorityList) NextAfter(cluster string) string {
+	idx := slices.Index(l, cluster)
+	if idx < 0 {
+		return l.Primary()
+	}
+	if idx+1 < len(l) {
+		return l[idx+1]
+	}
+	return ""
+}
+
+// ConvertClusterPriorityList can be used as a conversion function for
+// New*TypedSettingWithConverter with a ClusterPriorityList type. The value from dynamic config
+// can be a list of cluster names or a single comma-separated string. The order is preserved.
+//
+// Names that appear more than once are only kept at their first position. If knownClusters is
+// not empty, names that are not in it are dropped too. Dropped names are logged as a warning.
+func ConvertClusterPriorityList(knownClusters ...string) func(v any) (ClusterPriorityList, error) {
+	return func(v any) (ClusterPriorityList, error) {
+		if list, ok := v.(ClusterPriorityList); ok {
+			return list, nil
+		}
+		clusters, err := convertStringSlice(v)
+		if err != nil {
+			return nil, err
+		}
+		list := make(ClusterPriorityList, 0, len(clusters))
+		var dropped droppedClustersError
+		for _, cluster := range clusters {
+			if len(knownClusters) > 0 && !slices.Contains(knownClusters, cluster) {
+				dropped.unknown = append(dropped.unknown, cluster)
+			} else if slices.Contains(list, cluster) {
+				dropped.duplicates = append(dropped.duplicates, cluster)
+			} else {
+				list = append(list, cluster)
+			}
+		}
+		if len(dropped.unknown) > 0 || len(dropped.duplicates) > 0 {
+			return list, newConversionWarning("Value contains unknown or duplicate cluster names, dropping them", &dropped)
+		}
+		return list, nil
+	}
+}
+
+func (e *droppedClustersError) Error() string {
+	return fmt.Sprintf("dropped unknown cluster names %v and duplicate cluster names %v", e.unknown, e.duplicates)
+}
@@ -0,0 +1,96 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dgryski/go-farm"
+)
+
+type (
+	// ClusterReadWeights are the relative weights of the clusters that reads of a global namespace
+	// are routed to, see Pick. Clusters with a zero weight get no reads.
+	ClusterReadWeights map[string]int
+
+	// ClusterReadWeightsPropertyFnWithNamespaceFilter returns ClusterReadWeights per namespace.
+	ClusterReadWeightsPropertyFnWithNamespaceFilter = TypedPropertyFnWithNamespaceFilter[ClusterReadWeights]
+)
+
+// Pick returns the cluster to route the read identified by key to, e.g. a workflow ID, so that
+// the reads of the same key always go to the same cluster as long as the weights don't change.
+// Over many keys, each cluster gets a share of the reads proportional to its weight. If no cluster
+// has a positive weight, reads stay in localCluster.
+func (w ClusterReadWeights) Pick(key string, localCluster string) string {
+	clusters := make([]string, 0, len(w))
+	total := uint64(0)
+	for cluster, weight := range w {
+		if weight > 0 {
+			clusters = append(clusters, cluster)
+			total += uint64(weight)
+		}
+	}
+	if total == 0 {
+		return localCluster
+	}
+	// map iteration order is random, sort to make the pick deterministic
+	slices.Sort(clusters)
+
+	point := farm.Fingerprint64([]byte(key)) % total
+	for _, cluster := range clusters {
+		weight := uint64(w[cluster])
+		if point < weight {
+			return cluster
+		}
+		point -= weight
+	}
+	return localCluster // unreachable
+}
+
+// ConvertClusterReadWeights can be used as a conversion function for
+// New*TypedSettingWithConverter with a ClusterReadWeights type. The value from dynamic config
+// must be a map of cluster name to a non-negative integer weight.
+func ConvertClusterReadWeights(v any) (ClusterReadWeights, error) {
+	if weights, ok := v.(ClusterReadWeights); ok {
+		return weights, nil
+	}
+	m, err := convertMap(v)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(ClusterReadWeights, len(m))
+	for cluster, value := range m {
+		weight, err := convertInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("weight of cluster %v: %w", cluster, err)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of cluster %v is negative: %v", cluster, weight)
+		}
+		weights[cluster] = weight
+	}
+	return weights, nil
+}
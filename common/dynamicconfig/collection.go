@@ -25,17 +25,24 @@
 package dynamicconfig
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
 
+	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/primitives/timestamp"
 )
 
@@ -47,8 +54,67 @@ type (
 		client   Client
 		logger   log.Logger
 		errCount int64
+
+		// keys read since tracking was enabled, see EnableReadKeyTracking
+		trackReadKeys atomic.Bool
+		readKeys      sync.Map // Key -> struct{}
+
+		// transforms is copied on write under transformsLock, see SetValueTransform
+		transformsLock sync.Mutex
+		transforms     atomic.Pointer[map[string]ValueTransform] // lowercase key -> transform
+
+		// deprecatedKeys is copied on write under deprecatedKeysLock, see SetDeprecatedKeys
+		deprecatedKeysLock   sync.Mutex
+		deprecatedKeys       atomic.Pointer[map[string][]Key] // lowercase key -> deprecated keys
+		loggedDeprecatedKeys sync.Map                         // lowercase deprecated key -> struct{}
+
+		// virtuals is copied on write under virtualsLock, see RegisterVirtualSetting
+		virtualsLock sync.Mutex
+		virtuals     atomic.Pointer[map[string]VirtualResolver] // lowercase key -> resolver
+
+		reload reloadState
+
+		// values kept up to date for GlobalTypedSetting.Bind
+		bindings bindingState
+
+		// keys that can be overridden per request, see SetRequestOverrideAllowlist
+		requestOverrideAllowlist atomic.Pointer[map[string]struct{}]
+
+		// expiry of constrained values, see ConstrainedValue.ExpiresAt and SetExpiryClock
+		timeSource         clock.TimeSource
+		expiryClockSkew    time.Duration
+		loggedExpiredValue sync.Map // expiredValueKey -> struct{}
+
+		// values rejected by a setting's validator, see WithValidator
+		loggedInvalidValues sync.Map // invalidValueKey -> struct{}
+
+		metricsHandler metrics.Handler
 	}
 
+	// ExpiringOverride is a dynamic config value that's about to expire, see
+	// Collection.ListExpiringOverrides.
+	ExpiringOverride struct {
+		Key         Key
+		Constraints Constraints
+		Value       any
+		ExpiresAt   time.Time
+	}
+
+	expiredValueKey struct {
+		key         string
+		constraints Constraints
+		expiresAt   time.Time
+	}
+
+	invalidValueKey struct {
+		key   string
+		value string
+	}
+
+	// ValueTransform is applied to a dynamic config value before it's converted to the
+	// setting's type.
+	ValueTransform func(key Key, raw any) any
+
 	// These function types follow a similar pattern:
 	//   {X}PropertyFn - returns a value of type X that is global (no filters)
 	//   {X}PropertyFnWith{Y}Filter - returns a value of type X with the given filters
@@ -65,26 +131,52 @@ type (
 	//   TaskQueue func(namespace string, taskQueue string, taskType enumspb.TaskQueueType)  (matching task queue)
 	//   TaskType func(taskType enumspsb.TaskType)  (history task type)
 	//   ShardID func(shardID int32)
+	//   WorkflowType func(namespace string, workflowType string)
+	//   SDKVersion func(namespace string, sdkName string, sdkVersion string)
 )
 
 const (
 	errCountLogThreshold = 1000
+
+	defaultExpiryClockSkew = 5 * time.Second
 )
 
 var (
+	// defaultsCollection resolves every setting to its default, see ResolveWithTimeout.
+	defaultsCollection = NewNoopCollection()
+
 	errKeyNotPresent        = errors.New("key not present")
 	errNoMatchingConstraint = errors.New("no matching constraint in key")
+	errNoAcceptedMatch      = errors.New("no accepted value in key")
 )
 
 // NewCollection creates a new collection
 func NewCollection(client Client, logger log.Logger) *Collection {
 	return &Collection{
-		client:   client,
-		logger:   logger,
-		errCount: -1,
+		client:          client,
+		logger:          logger,
+		errCount:        -1,
+		timeSource:      clock.NewRealTimeSource(),
+		expiryClockSkew: defaultExpiryClockSkew,
+		metricsHandler:  metrics.NoopMetricsHandler,
 	}
 }
 
+// SetExpiryClock sets the time source used to expire constrained values and to resolve
+// TimeSchedule values, see Scheduled, and how long past its ExpiresAt a value is still used. The
+// tolerance makes sure that a host whose clock runs ahead doesn't revert an override before it
+// was meant to expire. It must be called before the collection is used.
+func (c *Collection) SetExpiryClock(timeSource clock.TimeSource, clockSkewTolerance time.Duration) {
+	c.timeSource = timeSource
+	c.expiryClockSkew = clockSkewTolerance
+}
+
+// SetMetricsHandler sets the handler that metrics of the collection are emitted to, e.g. by
+// ResolveWithTimeout. It must be called before the collection is used.
+func (c *Collection) SetMetricsHandler(handler metrics.Handler) {
+	c.metricsHandler = handler
+}
+
 func (c *Collection) throttleLog() bool {
 	// TODO: This is a lot of unnecessary contention with little benefit. Consider using
 	// https://github.com/cespare/percpu here.
@@ -93,23 +185,201 @@ func (c *Collection) throttleLog() bool {
 	return errCount < errCountLogThreshold || errCount%errCountLogThreshold == 0
 }
 
+// EnableReadKeyTracking makes the collection record every distinct key that is looked up from now
+// on, so that ReadKeys can report which settings the server actually uses. It's off by default to
+// keep lookups cheap.
+func (c *Collection) EnableReadKeyTracking() {
+	c.trackReadKeys.Store(true)
+}
+
+// ReadKeys returns the keys looked up since EnableReadKeyTracking was called, sorted.
+func (c *Collection) ReadKeys() []Key {
+	var keys []Key
+	c.readKeys.Range(func(key, _ any) bool {
+		keys = append(keys, key.(Key))
+		return true
+	})
+	slices.Sort(keys)
+	return keys
+}
+
+// SetValueTransform registers transform to be applied to the values of the given keys, replacing
+// any transform previously registered for them. It's meant for deployment-wide adjustments, e.g.
+// scaling all rate limits down in a load test environment.
+//
+// The transform is applied to the value selected for the key, which is the setting's default if
+// dynamic config has no matching value. If the transformed value can't be converted, the
+// untransformed default is used.
+func (c *Collection) SetValueTransform(transform ValueTransform, keys ...Key) {
+	c.transformsLock.Lock()
+	defer c.transformsLock.Unlock()
+
+	transforms := make(map[string]ValueTransform)
+	if old := c.transforms.Load(); old != nil {
+		maps.Copy(transforms, *old)
+	}
+	for _, key := range keys {
+		transforms[strings.ToLower(key.String())] = transform
+	}
+	c.transforms.Store(&transforms)
+}
+
+func (c *Collection) getValueTransform(key Key) ValueTransform {
+	transforms := c.transforms.Load()
+	if transforms == nil {
+		return nil
+	}
+	return (*transforms)[strings.ToLower(key.String())]
+}
+
+// SetDeprecatedKeys registers the keys that key was previously known as, replacing any registered
+// before. If dynamic config has no values for key, the values of the first deprecated key that has
+// any are used instead, so that a renamed setting keeps working with config files that still use
+// the old name. A warning is logged the first time each deprecated key is used.
+func (c *Collection) SetDeprecatedKeys(key Key, deprecatedKeys ...Key) {
+	c.deprecatedKeysLock.Lock()
+	defer c.deprecatedKeysLock.Unlock()
+
+	allDeprecatedKeys := make(map[string][]Key)
+	if old := c.deprecatedKeys.Load(); old != nil {
+		maps.Copy(allDeprecatedKeys, *old)
+	}
+	allDeprecatedKeys[strings.ToLower(key.String())] = slices.Clone(deprecatedKeys)
+	c.deprecatedKeys.Store(&allDeprecatedKeys)
+}
+
+// getValue returns the values of key from the client, falling back to its deprecated keys.
+func (c *Collection) getValue(key Key) []ConstrainedValue {
+	return c.getValueWithContext(context.Background(), key)
+}
+
+// getValueWithContext is getValue with a context for clients that implement ContextClient.
+func (c *Collection) getValueWithContext(ctx context.Context, key Key) []ConstrainedValue {
+	cvs := getClientValue(ctx, c.client, key)
+	if len(cvs) > 0 {
+		return cvs
+	}
+	allDeprecatedKeys := c.deprecatedKeys.Load()
+	if allDeprecatedKeys == nil {
+		return cvs
+	}
+	for _, deprecatedKey := range (*allDeprecatedKeys)[strings.ToLower(key.String())] {
+		if deprecatedCVs := getClientValue(ctx, c.client, deprecatedKey); len(deprecatedCVs) > 0 {
+			c.logDeprecatedKey(key, deprecatedKey)
+			return deprecatedCVs
+		}
+	}
+	return cvs
+}
+
+func getClientValue(ctx context.Context, client Client, key Key) []ConstrainedValue {
+	if cc, ok := client.(ContextClient); ok {
+		return cc.GetValueWithContext(ctx, key)
+	}
+	return client.GetValue(key)
+}
+
+func (c *Collection) logDeprecatedKey(key, deprecatedKey Key) {
+	logKey := strings.ToLower(deprecatedKey.String())
+	if _, logged := c.loggedDeprecatedKeys.LoadOrStore(logKey, struct{}{}); !logged {
+		c.logger.Warn("Dynamic config key is deprecated, please rename it",
+			tag.Key(deprecatedKey.String()),
+			tag.NewStringTag("new-key", key.String()),
+		)
+	}
+}
+
 func (c *Collection) HasKey(key Key) bool {
-	cvs := c.client.GetValue(key)
+	cvs := c.getValue(key)
 	return len(cvs) > 0
 }
 
+// ListExpiringOverrides returns the values of registered settings that are still in effect but
+// expire within the given time from now, sorted by expiry time. It's meant for operators to find
+// temporary overrides that should be extended before the settings revert. Values that already
+// expired are not included.
+func (c *Collection) ListExpiringOverrides(within time.Duration) []ExpiringOverride {
+	now := c.timeSource.Now()
+	deadline := now.Add(within)
+	var overrides []ExpiringOverride
+	for _, key := range registeredKeys() {
+		for _, cv := range c.getValue(key) {
+			if cv.ExpiresAt.IsZero() || cv.ExpiresAt.After(deadline) {
+				continue
+			}
+			if now.After(cv.ExpiresAt.Add(c.expiryClockSkew)) {
+				continue
+			}
+			overrides = append(overrides, ExpiringOverride{
+				Key:         key,
+				Constraints: cv.Constraints,
+				Value:       cv.Value,
+				ExpiresAt:   cv.ExpiresAt,
+			})
+		}
+	}
+	slices.SortStableFunc(overrides, func(a, b ExpiringOverride) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	})
+	return overrides
+}
+
+// dropExpired returns cvs without the values that have expired, logging each expired value the
+// first time it's skipped.
+func (c *Collection) dropExpired(key Key, cvs []ConstrainedValue) []ConstrainedValue {
+	var now time.Time
+	var result []ConstrainedValue
+	for i, cv := range cvs {
+		if !cv.ExpiresAt.IsZero() {
+			if now.IsZero() {
+				now = c.timeSource.Now()
+			}
+			if now.After(cv.ExpiresAt.Add(c.expiryClockSkew)) {
+				if result == nil {
+					result = make([]ConstrainedValue, i, len(cvs)-1)
+					copy(result, cvs[:i])
+				}
+				c.logExpired(key, cv)
+				continue
+			}
+		}
+		if result != nil {
+			result = append(result, cv)
+		}
+	}
+	if result == nil {
+		return cvs
+	}
+	return result
+}
+
+func (c *Collection) logExpired(key Key, cv ConstrainedValue) {
+	logKey := expiredValueKey{
+		key:         strings.ToLower(key.String()),
+		constraints: cv.Constraints,
+		expiresAt:   cv.ExpiresAt,
+	}
+	if _, logged := c.loggedExpiredValue.LoadOrStore(logKey, struct{}{}); !logged {
+		c.logger.Info("Dynamic config value expired, ignoring it",
+			tag.Key(key.String()),
+			tag.IgnoredValue(cv.Value),
+			tag.Timestamp(cv.ExpiresAt),
+		)
+	}
+}
+
 func findMatch[T any](cvs []ConstrainedValue, defaultCVs []TypedConstrainedValue[T], precedence []Constraints) (any, error) {
 	if len(cvs)+len(defaultCVs) == 0 {
 		return nil, errKeyNotPresent
 	}
 	for _, m := range precedence {
 		for _, cv := range cvs {
-			if m == cv.Constraints {
+			if constraintsMatch(m, cv.Constraints) {
 				return cv.Value, nil
 			}
 		}
 		for _, cv := range defaultCVs {
-			if m == cv.Constraints {
+			if constraintsMatch(m, cv.Constraints) {
 				return cv.Value, nil
 			}
 		}
@@ -118,49 +388,252 @@ func findMatch[T any](cvs []ConstrainedValue, defaultCVs []TypedConstrainedValue
 	return nil, errNoMatchingConstraint
 }
 
+// findMatches is like findMatch, but returns all matching values in the order findMatch would
+// have picked them.
+func findMatches[T any](cvs []ConstrainedValue, defaultCVs []TypedConstrainedValue[T], precedence []Constraints) []any {
+	var matches []any
+	for _, m := range precedence {
+		for _, cv := range cvs {
+			if constraintsMatch(m, cv.Constraints) {
+				matches = append(matches, cv.Value)
+			}
+		}
+		for _, cv := range defaultCVs {
+			if constraintsMatch(m, cv.Constraints) {
+				matches = append(matches, cv.Value)
+			}
+		}
+	}
+	return matches
+}
+
 // matchAndConvert can't be a method of Collection because methods can't be generic, but we can
 // take a *Collection as an argument.
+//
+// If accept is not nil, matched values that it rejects are skipped in favor of the next match in
+// precedence order, and finally the default. The default itself is never checked.
 func matchAndConvert[T any](
 	c *Collection,
 	key Key,
 	def T,
 	cdef []TypedConstrainedValue[T],
 	convert func(value any) (T, error),
+	accept func(T) bool,
+	precedence []Constraints,
+) T {
+	return matchAndConvertWithLogger(c, nil, key, def, cdef, convert, accept, precedence)
+}
+
+// matchAndConvertWithLogger is matchAndConvert with the logger used for missing and unconvertible
+// values. If logger is nil, the collection's logger is used.
+func matchAndConvertWithLogger[T any](
+	c *Collection,
+	logger log.Logger,
+	key Key,
+	def T,
+	cdef []TypedConstrainedValue[T],
+	convert func(value any) (T, error),
+	accept func(T) bool,
+	precedence []Constraints,
+) T {
+	return matchAndConvertWithContext(context.Background(), c, logger, key, def, cdef, convert, accept, precedence)
+}
+
+// matchAndConvertWithContext is matchAndConvertWithLogger with the context passed to clients that
+// implement ContextClient.
+func matchAndConvertWithContext[T any](
+	ctx context.Context,
+	c *Collection,
+	logger log.Logger,
+	key Key,
+	def T,
+	cdef []TypedConstrainedValue[T],
+	convert func(value any) (T, error),
+	accept func(T) bool,
 	precedence []Constraints,
 ) T {
-	cvs := c.client.GetValue(key)
+	if logger == nil {
+		logger = c.logger
+	}
+	if c.trackReadKeys.Load() {
+		c.readKeys.LoadOrStore(key, struct{}{})
+	}
+	if resolve := c.getVirtualResolver(key); resolve != nil {
+		return resolveVirtual(c, logger, key, def, cdef, convert, resolve, precedence)
+	}
+	cvs := c.dropExpired(key, c.getValueWithContext(ctx, key))
 
 	defaultCVs := cdef
 	if defaultCVs == nil {
 		defaultCVs = []TypedConstrainedValue[T]{{Value: def}}
 	}
 
-	val, matchErr := findMatch(cvs, defaultCVs, precedence)
+	var val any
+	var matchErr error
+	if accept == nil {
+		val, matchErr = findMatch(cvs, defaultCVs, precedence)
+	} else {
+		for _, val := range findMatches(cvs, defaultCVs, precedence) {
+			val, _ = c.transformValue(key, val)
+			typedVal, convertErr := convertLenient(c, logger, key, val, convert)
+			if isInvalidValue(convertErr) {
+				c.logInvalidValue(logger, key, val, convertErr)
+				continue
+			} else if convertErr != nil {
+				if c.throttleLog() {
+					logger.Warn("Failed to convert value, trying next match", tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(convertErr))
+				}
+				continue
+			}
+			if accept(typedVal) {
+				return typedVal
+			}
+			if c.throttleLog() {
+				logger.Warn("Value not accepted by setting, trying next match", tag.Key(key.String()), tag.IgnoredValue(val))
+			}
+		}
+		matchErr = errNoAcceptedMatch
+	}
 	if matchErr != nil {
 		if c.throttleLog() {
-			c.logger.Debug("No such key in dynamic config, using default", tag.Key(key.String()), tag.Error(matchErr))
+			logger.Debug("No such key in dynamic config, using default", tag.Key(key.String()), tag.Error(matchErr))
 		}
 		// couldn't find a constrained match, use default
 		val = def
 	}
+	if transformed, ok := c.transformValue(key, val); ok {
+		val = transformed
+		// make sure a bad transformed value falls back to the default below
+		matchErr = nil
+	}
 
-	typedVal, convertErr := convert(val)
+	typedVal, convertErr := convertLenient(c, logger, key, val, convert)
 	if convertErr != nil && matchErr == nil {
 		// We failed to convert the value to the desired type. Try converting the default. note
 		// that if matchErr != nil then val _is_ defaultValue and we don't have to try this again.
-		if c.throttleLog() {
-			c.logger.Warn("Failed to convert value, using default", tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(convertErr))
+		if isInvalidValue(convertErr) {
+			c.logInvalidValue(logger, key, val, convertErr)
+		} else if c.throttleLog() {
+			logger.Warn("Failed to convert value, using default", tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(convertErr))
 		}
 		typedVal, convertErr = convert(def)
 	}
 	if convertErr != nil {
 		// If we can't convert the default, that's a bug in our code, use Warn level.
-		c.logger.Warn("Can't convert default value (this is a bug; fix server code)", tag.Key(key.String()), tag.IgnoredValue(def), tag.Error(convertErr))
+		logger.Warn("Can't convert default value (this is a bug; fix server code)", tag.Key(key.String()), tag.IgnoredValue(def), tag.Error(convertErr))
 		// Return typedVal anyway since we have to return something.
 	}
 	return typedVal
 }
 
+// logInvalidValue logs a value rejected by the validator of the setting of key, once per value, at
+// error level since it's a misconfiguration that an operator needs to fix.
+func (c *Collection) logInvalidValue(logger log.Logger, key Key, val any, err error) {
+	logKey := invalidValueKey{key: strings.ToLower(key.String()), value: fmt.Sprintf("%#v", val)}
+	if _, logged := c.loggedInvalidValues.LoadOrStore(logKey, struct{}{}); !logged {
+		logger.Error("Dynamic config value failed validation, ignoring it", tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(err))
+	}
+}
+
+// transformValue applies the value transform registered for key, if any.
+func (c *Collection) transformValue(key Key, val any) (any, bool) {
+	transform := c.getValueTransform(key)
+	if transform == nil {
+		return val, false
+	}
+	return transform(key, val), true
+}
+
+// conversionWarning wraps an error that a converter returns along with a usable value.
+// convertLenient logs it with msg and uses the value instead of the default.
+type conversionWarning struct {
+	msg string
+	err error
+}
+
+func newConversionWarning(msg string, err error) error {
+	return &conversionWarning{msg: msg, err: err}
+}
+
+func (w *conversionWarning) Error() string {
+	return w.err.Error()
+}
+
+func (w *conversionWarning) Unwrap() error {
+	return w.err
+}
+
+func asConversionWarning(err error) (*conversionWarning, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var warning *conversionWarning
+	return warning, errors.As(err, &warning)
+}
+
+// convertLenient converts val, treating errors that come with a usable value as warnings.
+func convertLenient[T any](c *Collection, logger log.Logger, key Key, val any, convert func(value any) (T, error)) (T, error) {
+	typedVal, convertErr := convert(val)
+	if warning, ok := asConversionWarning(convertErr); ok {
+		if c.throttleLog() {
+			logger.Warn(warning.msg, tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(warning.err))
+		}
+		convertErr = nil
+	}
+	return typedVal, convertErr
+}
+
+// ResolveWithConstraints returns the value of a setting for an already-built Constraints struct,
+// e.g. one carried around by a task processor. The given constraints are matched first, then the
+// setting's usual precedence list, filled in from the relevant fields of the constraints.
+func ResolveWithConstraints[T any](c *Collection, s ConstrainedSetting[T], cons Constraints) T {
+	return s.resolveWithConstraints(context.Background(), c, cons)
+}
+
+// ResolveWithTimeout returns the value of a setting for the given filter values like its Get
+// function does, unless the client takes longer than timeout to return the setting's values, or
+// ctx is done first. In that case the setting's default for the filter values is returned, and
+// the timeout is counted in the dynamic_config_resolve_timeouts metric, see SetMetricsHandler. It's
+// meant for request paths with tight latency budgets and clients that can be slow, e.g. remote
+// ones. The lookup is canceled when it times out, for clients that implement ContextClient.
+func ResolveWithTimeout[T any](
+	ctx context.Context,
+	c *Collection,
+	s ConstrainedSetting[T],
+	timeout time.Duration,
+	filters ...FilterOption,
+) T {
+	var cons Constraints
+	for _, filter := range filters {
+		filter(&cons)
+	}
+
+	lookupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// buffered so that a lookup that completes after the timeout doesn't block
+	result := make(chan T, 1)
+	go func() {
+		result <- s.resolveWithConstraints(lookupCtx, c, cons)
+	}()
+	timerC, timer := c.timeSource.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case v := <-result:
+		return v
+	case <-timerC:
+	case <-ctx.Done():
+	}
+
+	metrics.DynamicConfigResolveTimeouts.With(c.metricsHandler).Record(1, metrics.DynamicConfigKeyTag(s.Key().String()))
+	if c.throttleLog() {
+		c.logger.Warn("Dynamic config lookup timed out, using default value",
+			tag.Key(s.Key().String()),
+			tag.NewDurationTag("timeout", timeout),
+		)
+	}
+	return s.resolveWithConstraints(context.Background(), defaultsCollection, cons)
+}
+
 func convertInt(val any) (int, error) {
 	switch val := val.(type) {
 	case int:
@@ -230,12 +703,24 @@ func convertString(val any) (string, error) {
 	return "", errors.New("value type is not string")
 }
 
+// convertBool accepts a bool or a string in a form accepted by strconv.ParseBool, or
+// yes/no/on/off in any case.
 func convertBool(val any) (bool, error) {
 	switch v := val.(type) {
 	case bool:
 		return v, nil
 	case string:
-		return strconv.ParseBool(v)
+		b, err := strconv.ParseBool(v)
+		if err == nil {
+			return b, nil
+		}
+		switch strings.ToLower(v) {
+		case "yes", "on":
+			return true, nil
+		case "no", "off":
+			return false, nil
+		}
+		return false, err
 	default:
 		return false, errors.New("value type is not bool")
 	}
@@ -248,6 +733,38 @@ func convertMap(val any) (map[string]any, error) {
 	return nil, errors.New("value type is not map")
 }
 
+// convertStringSlice accepts either a single comma-separated string or a list of strings, and
+// normalizes it to a []string with whitespace trimmed and empty elements removed.
+// Settings that need strict parsing should use a TypedSetting with a []string default instead.
+func convertStringSlice(val any) ([]string, error) {
+	var elems []string
+	switch v := val.(type) {
+	case string:
+		elems = strings.Split(v, ",")
+	case []string:
+		elems = v
+	case []any:
+		elems = make([]string, 0, len(v))
+		for _, e := range v {
+			stringVal, ok := e.(string)
+			if !ok {
+				return nil, errors.New("value type is not string slice")
+			}
+			elems = append(elems, stringVal)
+		}
+	default:
+		return nil, errors.New("value type is not string slice")
+	}
+
+	result := make([]string, 0, len(elems))
+	for _, e := range elems {
+		if e = strings.TrimSpace(e); e != "" {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
 // ConvertStructure can be used as a conversion function for New*TypedSettingWithConverter.
 // The value from dynamic config will be converted to T, on top of the given default.
 //
@@ -25,16 +25,29 @@
 package dynamicconfig_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"maps"
+	"math"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/metadata"
 
 	enumspb "go.temporal.io/api/enums/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
 )
 
 const (
@@ -49,6 +62,33 @@ const (
 	testGetStringPropertyKey                          = "testGetStringPropertyKey"
 	testGetMapPropertyKey                             = "testGetMapPropertyKey"
 	testGetTypedPropertyKey                           = "testGetTypedPropertyKey"
+	testGetStringSlicePropertyKey                     = "testGetStringSlicePropertyKey"
+	testGetBoundedPropertyKey                         = "testGetBoundedPropertyKey"
+	testResolveWithConstraintsKey                     = "testResolveWithConstraintsKey"
+	testGetMethodSetPropertyKey                       = "testGetMethodSetPropertyKey"
+	testReadKeysKey1                                  = "testReadKeysKey1"
+	testGetShardIDScaledPropertyKey                   = "testGetShardIDScaledPropertyKey"
+	testReadKeysKey2                                  = "testReadKeysKey2"
+	testValueTransformKey1                            = "testValueTransformKey1"
+	testValueTransformKey2                            = "testValueTransformKey2"
+	testGetCronSchedulePropertyKey                    = "testGetCronSchedulePropertyKey"
+	testGetDisableableDurationPropertyKey             = "testGetDisableableDurationPropertyKey"
+	testGetTimeSchedulePropertyKey                    = "testGetTimeSchedulePropertyKey"
+	testGetRatePropertyKey                            = "testGetRatePropertyKey"
+	testGetValidatedPropertyKey                       = "testGetValidatedPropertyKey"
+	testGetStrategyPropertyKey                        = "testGetStrategyPropertyKey"
+	testResolveWithTimeoutKey                         = "testResolveWithTimeoutKey"
+	testGetJSONSchemaPropertyKey                      = "testGetJSONSchemaPropertyKey"
+	testGetAcceptedPropertyKey                        = "testGetAcceptedPropertyKey"
+	testGetExpiringPropertyKey                        = "testGetExpiringPropertyKey"
+	testEvaluateGroupNamespaceKey                     = "testEvaluateGroupNamespaceKey"
+	testEvaluateGroupTaskQueueKey                     = "testEvaluateGroupTaskQueueKey"
+	testEvaluateGroupGlobalKey                        = "testEvaluateGroupGlobalKey"
+	testListExpiringOverridesKey1                     = "testListExpiringOverridesKey1"
+	testListExpiringOverridesKey2                     = "testListExpiringOverridesKey2"
+	testGetNamespaceMapKey                            = "testGetNamespaceMapKey"
+	testGetNamespaceMapConstrainedDefaultKey          = "testGetNamespaceMapConstrainedDefaultKey"
+	testGetWithLoggerKey                              = "testGetWithLoggerKey"
 	testGetIntPropertyFilteredByNamespaceKey          = "testGetIntPropertyFilteredByNamespaceKey"
 	testGetDurationPropertyFilteredByNamespaceKey     = "testGetDurationPropertyFilteredByNamespaceKey"
 	testGetIntPropertyFilteredByTaskQueueInfoKey      = "testGetIntPropertyFilteredByTaskQueueInfoKey"
@@ -59,6 +99,32 @@ const (
 	testGetBoolPropertyFilteredByTaskQueueInfoKey     = "testGetBoolPropertyFilteredByTaskQueueInfoKey"
 	testGetStringPropertyFilteredByNamespaceIDKey     = "testGetStringPropertyFilteredByNamespaceIDKey"
 	testGetIntPropertyFilteredByDestinationKey        = "testGetIntPropertyFilteredByDestinationKey"
+	testGetBoolPropertyFilteredByWorkflowTypeKey      = "testGetBoolPropertyFilteredByWorkflowTypeKey"
+	testGetIntPropertyFilteredBySDKVersionKey         = "testGetIntPropertyFilteredBySDKVersionKey"
+	testGetBoolPropertyFilteredBySDKVersionKey        = "testGetBoolPropertyFilteredBySDKVersionKey"
+	testGetIntPropertyByEnvironmentKey                = "testGetIntPropertyByEnvironmentKey"
+	testGetClusterPriorityListKey                     = "testGetClusterPriorityListKey"
+	testGetClusterReadWeightsKey                      = "testGetClusterReadWeightsKey"
+	testGetLabelSelectorKey                           = "testGetLabelSelectorKey"
+	testGetShedPercentageKey                          = "testGetShedPercentageKey"
+	testGetMergedPropertyKey                          = "testGetMergedPropertyKey"
+	testTracePropertyKey                              = "testTracePropertyKey"
+	testTraceOverlaidPropertyKey                      = "testTraceOverlaidPropertyKey"
+	testTraceUnsetPropertyKey                         = "testTraceUnsetPropertyKey"
+	testDeprecatedKeysKey                             = "testDeprecatedKeysKey"
+	testReloadKey1                                    = "testReloadKey1"
+	testRequestOverrideKey                            = "testRequestOverrideKey"
+	testRequestOverrideNotAllowedKey                  = "testRequestOverrideNotAllowedKey"
+	testReloadKey2                                    = "testReloadKey2"
+	testReloadKey3                                    = "testReloadKey3"
+	testBindKey                                       = "testBindKey"
+	testDeprecatedKeysOldKey1                         = "testDeprecatedKeysOldKey1"
+	testDeprecatedKeysOldKey2                         = "testDeprecatedKeysOldKey2"
+	testVirtualSettingKey                             = "testVirtualSettingKey"
+	testVirtualSettingInputKey1                       = "testVirtualSettingInputKey1"
+	testVirtualSettingInputKey2                       = "testVirtualSettingInputKey2"
+	testVirtualSettingBoundKey                        = "testVirtualSettingBoundKey"
+	testExportValueGaugeKey                           = "testExportValueGaugeKey"
 )
 
 // Note: fileBasedClientSuite also heavily tests Collection, since some tests are easier with data
@@ -149,6 +215,24 @@ func (s *collectionSuite) TestGetBoolProperty() {
 	s.Equal(false, value())
 	s.client[testGetBoolPropertyKey] = "false"
 	s.Equal(false, value())
+
+	for _, str := range []string{"1", "t", "T", "TRUE", "true", "True", "yes", "YES", "Yes", "on", "ON", "On"} {
+		s.client[testGetBoolPropertyKey] = false
+		s.Equal(false, value())
+		s.client[testGetBoolPropertyKey] = str
+		s.Equal(true, value(), str)
+	}
+	for _, str := range []string{"0", "f", "F", "FALSE", "false", "False", "no", "NO", "No", "off", "OFF", "Off"} {
+		s.client[testGetBoolPropertyKey] = true
+		s.Equal(true, value())
+		s.client[testGetBoolPropertyKey] = str
+		s.Equal(false, value(), str)
+	}
+	// invalid strings fall back to the default
+	for _, str := range []string{"", "y", "n", "enabled", "yess", "tRUE", " on"} {
+		s.client[testGetBoolPropertyKey] = str
+		s.Equal(true, value(), str)
+	}
 }
 
 func (s *collectionSuite) TestGetBoolPropertyFilteredByNamespaceID() {
@@ -304,6 +388,166 @@ func (s *collectionSuite) TestGetMapProperty() {
 	s.Equal("321", value()["testKey"])
 }
 
+func (s *collectionSuite) TestGetStringSliceProperty() {
+	def := []string{"a", "b"}
+	setting := dynamicconfig.NewGlobalStringSliceSetting(
+		testGetStringSlicePropertyKey,
+		def,
+		"",
+	)
+	value := setting.Get(s.cln)
+	s.Equal(def, value())
+
+	s.client[testGetStringSlicePropertyKey] = "x,y,z"
+	s.Equal([]string{"x", "y", "z"}, value())
+
+	s.client[testGetStringSlicePropertyKey] = []any{"x", "y"}
+	s.Equal([]string{"x", "y"}, value())
+
+	s.client[testGetStringSlicePropertyKey] = []any{"x", 1}
+	s.Equal(def, value())
+}
+
+func (s *collectionSuite) TestGetStringSliceProperty_Whitespace() {
+	setting := dynamicconfig.NewGlobalStringSliceSetting(
+		testGetStringSlicePropertyKey,
+		nil,
+		"",
+	)
+	value := setting.Get(s.cln)
+
+	s.client[testGetStringSlicePropertyKey] = " x , y,, z ,"
+	s.Equal([]string{"x", "y", "z"}, value())
+
+	s.client[testGetStringSlicePropertyKey] = []any{" x", "", "y "}
+	s.Equal([]string{"x", "y"}, value())
+
+	s.client[testGetStringSlicePropertyKey] = " "
+	s.Equal([]string{}, value())
+}
+
+func (s *collectionSuite) TestGetBoundedProperty_Clamp() {
+	bounds := dynamicconfig.Bounds[time.Duration]{Min: time.Second, Max: time.Minute, Policy: dynamicconfig.BoundsClamp}
+	setting := dynamicconfig.NewGlobalDurationSettingWithBounds(
+		testGetBoundedPropertyKey,
+		10*time.Second,
+		bounds,
+		"",
+	)
+	value := setting.Get(s.cln)
+	s.Equal(10*time.Second, value())
+
+	s.client[testGetBoundedPropertyKey] = "10000s"
+	s.Equal(time.Minute, value())
+	s.client[testGetBoundedPropertyKey] = "-5s"
+	s.Equal(time.Second, value())
+
+	// bounds are inclusive
+	s.client[testGetBoundedPropertyKey] = "1m"
+	s.Equal(time.Minute, value())
+	s.client[testGetBoundedPropertyKey] = "1s"
+	s.Equal(time.Second, value())
+
+	s.Equal(&dynamicconfig.SettingBounds{Min: time.Second, Max: time.Minute, Policy: dynamicconfig.BoundsClamp}, setting.Bounds())
+	s.Error(setting.Validate("10000s"))
+	s.NoError(setting.Validate("30s"))
+}
+
+func (s *collectionSuite) TestGetBoundedProperty_Reject() {
+	bounds := dynamicconfig.Bounds[int]{Min: 0, Max: 100, Policy: dynamicconfig.BoundsReject}
+	setting := dynamicconfig.NewNamespaceIntSettingWithBounds(
+		testGetBoundedPropertyKey,
+		10,
+		bounds,
+		"",
+	)
+	value := setting.Get(s.cln)
+	s.Equal(10, value("ns"))
+
+	s.client[testGetBoundedPropertyKey] = 101
+	s.Equal(10, value("ns"))
+	s.client[testGetBoundedPropertyKey] = -1
+	s.Equal(10, value("ns"))
+
+	s.client[testGetBoundedPropertyKey] = 100
+	s.Equal(100, value("ns"))
+	s.client[testGetBoundedPropertyKey] = 0
+	s.Equal(0, value("ns"))
+}
+
+func (s *collectionSuite) TestGetBoundedProperty_NaN() {
+	for _, policy := range []dynamicconfig.BoundsPolicy{dynamicconfig.BoundsClamp, dynamicconfig.BoundsReject} {
+		dynamicconfig.ResetRegistryForTest()
+		setting := dynamicconfig.NewGlobalFloatSettingWithBounds(
+			testGetBoundedPropertyKey,
+			0.5,
+			dynamicconfig.Bounds[float64]{Min: 0, Max: 1, Policy: policy},
+			"",
+		)
+		value := setting.Get(s.cln)
+
+		s.client[testGetBoundedPropertyKey] = math.NaN()
+		s.Equal(0.5, value())
+		s.Error(setting.Validate(math.NaN()))
+	}
+}
+
+func (s *collectionSuite) TestGetBoundedProperty_InvalidDefinition() {
+	s.Panics(func() {
+		dynamicconfig.NewGlobalFloatSettingWithBounds(
+			testGetBoundedPropertyKey,
+			2.0,
+			dynamicconfig.Bounds[float64]{Min: 0, Max: 1, Policy: dynamicconfig.BoundsClamp},
+			"",
+		)
+	})
+	s.Panics(func() {
+		dynamicconfig.NewGlobalFloatSettingWithBounds(
+			testGetBoundedPropertyKey,
+			0.5,
+			dynamicconfig.Bounds[float64]{Min: 1, Max: 0, Policy: dynamicconfig.BoundsClamp},
+			"",
+		)
+	})
+	s.Panics(func() {
+		dynamicconfig.NewGlobalFloatSettingWithBounds(
+			testGetBoundedPropertyKey,
+			0.5,
+			dynamicconfig.Bounds[float64]{Min: 0, Max: 1},
+			"",
+		)
+	})
+	s.Nil(dynamicconfig.NewGlobalFloatSetting(testGetBoundedPropertyKey, 0.5, "").Bounds())
+}
+
+func (s *collectionSuite) TestResolveWithConstraints() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testResolveWithConstraintsKey, 10, "")
+	s.client[testResolveWithConstraintsKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{Namespace: "ns", TaskQueueName: "tq"},
+			Value:       1,
+		},
+		{
+			Constraints: dynamicconfig.Constraints{Namespace: "ns"},
+			Value:       2,
+		},
+		{
+			Value: 3,
+		},
+	}
+
+	// exact match, even with fields the setting's precedence doesn't use
+	s.Equal(1, dynamicconfig.ResolveWithConstraints(s.cln, setting, dynamicconfig.Constraints{Namespace: "ns", TaskQueueName: "tq"}))
+	// falls back to the setting's precedence using the relevant fields
+	s.Equal(2, dynamicconfig.ResolveWithConstraints(s.cln, setting, dynamicconfig.Constraints{Namespace: "ns", TaskQueueName: "other"}))
+	s.Equal(2, dynamicconfig.ResolveWithConstraints(s.cln, setting, dynamicconfig.Constraints{Namespace: "ns"}))
+	s.Equal(3, dynamicconfig.ResolveWithConstraints(s.cln, setting, dynamicconfig.Constraints{Namespace: "other", TaskQueueName: "tq"}))
+	s.Equal(3, dynamicconfig.ResolveWithConstraints(s.cln, setting, dynamicconfig.Constraints{}))
+
+	delete(s.client, testResolveWithConstraintsKey)
+	s.Equal(10, dynamicconfig.ResolveWithConstraints(s.cln, setting, dynamicconfig.Constraints{Namespace: "ns"}))
+}
+
 func (s *collectionSuite) TestGetTyped() {
 	type myFancyType struct {
 		Number int
@@ -373,6 +617,284 @@ func (s *collectionSuite) TestGetTypedSimpleList() {
 	})
 }
 
+func (s *collectionSuite) TestGetMethodSet() {
+	const (
+		startMethod  = "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution"
+		signalMethod = "/temporal.api.workflowservice.v1.WorkflowService/SignalWorkflowExecution"
+		futureMethod = "/temporal.api.workflowservice.v1.WorkflowService/FutureMethod"
+	)
+	setting := dynamicconfig.NewNamespaceTypedSettingWithConverter(
+		testGetMethodSetPropertyKey,
+		dynamicconfig.ConvertMethodSet(startMethod, signalMethod),
+		dynamicconfig.MethodSet(nil),
+		"",
+	)
+	get := setting.Get(s.cln)
+	namespace := "testNamespace"
+
+	s.Run("Default", func() {
+		s.False(get(namespace).IsEnabled(startMethod))
+	})
+
+	s.Run("List", func() {
+		s.client[testGetMethodSetPropertyKey] = []any{startMethod}
+		s.True(get(namespace).IsEnabled(startMethod))
+		s.False(get(namespace).IsEnabled(signalMethod))
+	})
+
+	s.Run("CommaSeparated", func() {
+		s.client[testGetMethodSetPropertyKey] = startMethod + ", " + signalMethod
+		s.True(get(namespace).IsEnabled(startMethod))
+		s.True(get(namespace).IsEnabled(signalMethod))
+	})
+
+	s.Run("CaseSensitive", func() {
+		s.client[testGetMethodSetPropertyKey] = []any{startMethod}
+		s.False(get(namespace).IsEnabled(strings.ToLower(startMethod)))
+	})
+
+	s.Run("Unknown", func() {
+		s.client[testGetMethodSetPropertyKey] = []any{startMethod, futureMethod}
+		s.True(get(namespace).IsEnabled(startMethod))
+		s.True(get(namespace).IsEnabled(futureMethod))
+	})
+
+	s.Run("PerNamespace", func() {
+		s.client[testGetMethodSetPropertyKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: namespace}, Value: []any{signalMethod}},
+		}
+		s.True(get(namespace).IsEnabled(signalMethod))
+		s.False(get("otherNamespace").IsEnabled(signalMethod))
+	})
+
+	s.Run("WrongType", func() {
+		s.client[testGetMethodSetPropertyKey] = []any{startMethod, 5}
+		s.False(get(namespace).IsEnabled(startMethod))
+	})
+}
+
+func (s *collectionSuite) TestGetClusterPriorityList() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetClusterPriorityListKey,
+		dynamicconfig.ConvertClusterPriorityList("us-east", "us-west", "eu-central"),
+		dynamicconfig.ClusterPriorityList{"us-east"},
+		"",
+	)
+	var get dynamicconfig.ClusterPriorityListPropertyFn = setting.Get(s.cln)
+
+	s.Run("Default", func() {
+		s.Equal(dynamicconfig.ClusterPriorityList{"us-east"}, get())
+	})
+
+	s.Run("PreservesOrder", func() {
+		s.client[testGetClusterPriorityListKey] = []any{"eu-central", "us-west", "us-east"}
+		s.Equal(dynamicconfig.ClusterPriorityList{"eu-central", "us-west", "us-east"}, get())
+		s.client[testGetClusterPriorityListKey] = "us-west, eu-central"
+		s.Equal(dynamicconfig.ClusterPriorityList{"us-west", "eu-central"}, get())
+	})
+
+	s.Run("DropsDuplicatesAndUnknown", func() {
+		s.client[testGetClusterPriorityListKey] = []any{"us-west", "ap-south", "us-east", "us-west"}
+		s.Equal(dynamicconfig.ClusterPriorityList{"us-west", "us-east"}, get())
+	})
+
+	s.Run("WrongType", func() {
+		s.client[testGetClusterPriorityListKey] = 5
+		s.Equal(dynamicconfig.ClusterPriorityList{"us-east"}, get())
+	})
+}
+
+func (s *collectionSuite) TestGetClusterReadWeights() {
+	setting := dynamicconfig.NewNamespaceTypedSettingWithConverter(
+		testGetClusterReadWeightsKey,
+		dynamicconfig.ConvertClusterReadWeights,
+		dynamicconfig.ClusterReadWeights(nil),
+		"",
+	)
+	var get dynamicconfig.ClusterReadWeightsPropertyFnWithNamespaceFilter = setting.Get(s.cln)
+	namespace := "testNamespace"
+
+	s.Run("Default", func() {
+		s.Nil(get(namespace))
+	})
+
+	s.Run("PerNamespace", func() {
+		s.client[testGetClusterReadWeightsKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: namespace}, Value: map[string]any{"us-east": 80, "us-west": 20, "eu-central": 0}},
+		}
+		s.Equal(dynamicconfig.ClusterReadWeights{"us-east": 80, "us-west": 20, "eu-central": 0}, get(namespace))
+		s.Nil(get("otherNamespace"))
+	})
+
+	s.Run("Invalid", func() {
+		s.client[testGetClusterReadWeightsKey] = map[string]any{"us-east": 80, "us-west": -20}
+		s.Nil(get(namespace))
+		s.client[testGetClusterReadWeightsKey] = map[string]any{"us-east": "80"}
+		s.Nil(get(namespace))
+		s.client[testGetClusterReadWeightsKey] = []any{"us-east"}
+		s.Nil(get(namespace))
+	})
+}
+
+func (s *collectionSuite) TestClusterReadWeightsPick() {
+	weights := dynamicconfig.ClusterReadWeights{"us-east": 3, "us-west": 1, "eu-central": 0}
+
+	picks := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("workflow-%d", i)
+		cluster := weights.Pick(key, "local")
+		// the same key is always routed to the same cluster
+		s.Equal(cluster, weights.Pick(key, "local"))
+		picks[cluster]++
+	}
+	s.Len(picks, 2, "zero weight and local clusters get no reads")
+	s.InDelta(7500, picks["us-east"], 300)
+	s.InDelta(2500, picks["us-west"], 300)
+
+	s.Equal("local", dynamicconfig.ClusterReadWeights(nil).Pick("workflow", "local"))
+	s.Equal("local", dynamicconfig.ClusterReadWeights{"us-east": 0}.Pick("workflow", "local"))
+}
+
+func (s *collectionSuite) TestGetLabelSelector() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetLabelSelectorKey,
+		dynamicconfig.ConvertLabelSelector,
+		(*dynamicconfig.LabelSelector)(nil),
+		"",
+	)
+	var get dynamicconfig.LabelSelectorPropertyFn = setting.Get(s.cln)
+	goldUS := map[string]string{"tier": "gold", "region": "us"}
+	silverEU := map[string]string{"tier": "silver", "region": "eu"}
+	bronze := map[string]string{"tier": "bronze"}
+	unlabeled := map[string]string{}
+
+	s.Run("Default", func() {
+		s.False(get().Matches(goldUS))
+		s.False(get().Matches(unlabeled))
+	})
+
+	s.Run("Empty", func() {
+		s.client[testGetLabelSelectorKey] = ""
+		s.True(get().Matches(goldUS))
+		s.True(get().Matches(unlabeled))
+	})
+
+	s.Run("Equality", func() {
+		s.client[testGetLabelSelectorKey] = "tier=gold, region == us"
+		s.True(get().Matches(goldUS))
+		s.False(get().Matches(silverEU))
+		s.False(get().Matches(unlabeled))
+
+		s.client[testGetLabelSelectorKey] = "region!=us"
+		s.False(get().Matches(goldUS))
+		s.True(get().Matches(silverEU))
+		s.True(get().Matches(bronze), "missing label is not equal")
+	})
+
+	s.Run("SetBased", func() {
+		s.client[testGetLabelSelectorKey] = "tier in (gold, silver), region"
+		s.True(get().Matches(goldUS))
+		s.True(get().Matches(silverEU))
+		s.False(get().Matches(bronze), "region label is required")
+
+		s.client[testGetLabelSelectorKey] = "tier notin (gold,silver),!region"
+		s.False(get().Matches(goldUS))
+		s.True(get().Matches(bronze))
+		s.True(get().Matches(unlabeled))
+	})
+
+	s.Run("Malformed", func() {
+		for _, selector := range []any{
+			"tier in (gold,silver",
+			"tier in ()",
+			"tier=gold=silver",
+			"=gold",
+			"tier in gold",
+			"!",
+			",",
+			42,
+		} {
+			s.client[testGetLabelSelectorKey] = selector
+			s.False(get().Matches(goldUS), "selector %v", selector)
+			s.False(get().Matches(unlabeled), "selector %v", selector)
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetShedPercentage() {
+	setting := dynamicconfig.NewNamespaceTypedSettingWithConverter(
+		testGetShedPercentageKey,
+		dynamicconfig.ConvertShedPercentage,
+		dynamicconfig.ShedPercentage(0),
+		"",
+	)
+	var get dynamicconfig.ShedPercentagePropertyFnWithNamespaceFilter = setting.Get(s.cln)
+	namespace := "testNamespace"
+
+	s.Run("Default", func() {
+		s.Equal(dynamicconfig.ShedPercentage(0), get(namespace))
+	})
+
+	s.Run("PerNamespace", func() {
+		s.client[testGetShedPercentageKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: namespace}, Value: 30},
+		}
+		s.Equal(dynamicconfig.ShedPercentage(30), get(namespace))
+		s.Equal(dynamicconfig.ShedPercentage(0), get("otherNamespace"))
+
+		// changes take effect right away
+		s.client[testGetShedPercentageKey] = 100
+		s.Equal(dynamicconfig.ShedPercentage(100), get(namespace))
+	})
+
+	s.Run("Invalid", func() {
+		s.client[testGetShedPercentageKey] = 101
+		s.Equal(dynamicconfig.ShedPercentage(0), get(namespace))
+		s.client[testGetShedPercentageKey] = -1
+		s.Equal(dynamicconfig.ShedPercentage(0), get(namespace))
+		s.client[testGetShedPercentageKey] = "50"
+		s.Equal(dynamicconfig.ShedPercentage(0), get(namespace))
+	})
+}
+
+func (s *collectionSuite) TestShedPercentageShouldShed() {
+	const calls = 100000
+	countShed := func(p dynamicconfig.ShedPercentage) int {
+		shed := 0
+		for i := 0; i < calls; i++ {
+			if p.ShouldShed() {
+				shed++
+			}
+		}
+		return shed
+	}
+
+	s.Equal(0, countShed(0))
+	s.Equal(calls, countShed(100))
+	for _, p := range []dynamicconfig.ShedPercentage{1, 25, 50, 90} {
+		// 1% of the calls is more than 6 standard deviations at any percentage
+		s.InDelta(calls*int(p)/100, countShed(p), 0.01*calls, "percentage %v", p)
+	}
+}
+
+func (s *collectionSuite) TestClusterPriorityListHelpers() {
+	list := dynamicconfig.ClusterPriorityList{"a", "b", "c"}
+	s.Equal("a", list.Primary())
+	s.Equal("b", list.NextAfter("a"))
+	s.Equal("c", list.NextAfter("b"))
+	s.Equal("", list.NextAfter("c"))
+	s.Equal("a", list.NextAfter("unknown"))
+
+	var empty dynamicconfig.ClusterPriorityList
+	s.Equal("", empty.Primary())
+	s.Equal("", empty.NextAfter("a"))
+
+	// without known clusters, only duplicates are dropped
+	converted, err := dynamicconfig.ConvertClusterPriorityList()([]any{"x", "y", "x"})
+	s.Error(err)
+	s.Equal(dynamicconfig.ClusterPriorityList{"x", "y"}, converted)
+}
+
 func (s *collectionSuite) TestGetTypedListOfStruct() {
 	type simple struct{ A, B int }
 	def := []simple{{1, 5}, {2, 9}}
@@ -405,6 +927,170 @@ func (s *collectionSuite) TestGetTypedListOfStruct() {
 	})
 }
 
+func (s *collectionSuite) TestGetBoolPropertyFilteredByWorkflowType() {
+	setting := dynamicconfig.NewWorkflowTypeBoolSetting(testGetBoolPropertyFilteredByWorkflowTypeKey, false, "")
+	namespaceName := "testNamespace"
+	workflowType1 := "testWorkflowType1"
+	workflowType2 := "testWorkflowType2"
+	value := setting.Get(s.cln)
+	s.False(value(namespaceName, workflowType1))
+
+	s.client[testGetBoolPropertyFilteredByWorkflowTypeKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{
+				Namespace:    namespaceName,
+				WorkflowType: workflowType1,
+			},
+			Value: true,
+		},
+	}
+	s.True(value(namespaceName, workflowType1))
+	s.False(value(namespaceName, workflowType2))
+	s.False(value("otherNamespace", workflowType1))
+
+	// namespace+workflowType > namespace > global
+	s.client[testGetBoolPropertyFilteredByWorkflowTypeKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{
+				Namespace:    namespaceName,
+				WorkflowType: workflowType1,
+			},
+			Value: false,
+		},
+		{
+			Constraints: dynamicconfig.Constraints{
+				Namespace: namespaceName,
+			},
+			Value: true,
+		},
+		{
+			Value: false,
+		},
+	}
+	s.False(value(namespaceName, workflowType1))
+	s.True(value(namespaceName, workflowType2))
+	s.False(value("otherNamespace", workflowType1))
+
+	// workflow type alone is not a valid constraint for this precedence
+	s.client[testGetBoolPropertyFilteredByWorkflowTypeKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{
+				WorkflowType: workflowType1,
+			},
+			Value: true,
+		},
+	}
+	s.False(value(namespaceName, workflowType1))
+
+	// existing filters ignore the workflow type constraint
+	namespaceSetting := dynamicconfig.NewNamespaceBoolSetting(testGetBoolPropertyFilteredByWorkflowTypeKey+"Namespace", false, "")
+	s.client[testGetBoolPropertyFilteredByWorkflowTypeKey+"Namespace"] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{
+				Namespace:    namespaceName,
+				WorkflowType: workflowType1,
+			},
+			Value: true,
+		},
+	}
+	s.False(namespaceSetting.Get(s.cln)(namespaceName))
+}
+
+func (s *collectionSuite) TestGetWithEnvironment() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetIntPropertyByEnvironmentKey, 0, "")
+	s.client[testGetIntPropertyByEnvironmentKey] = []dynamicconfig.ConstrainedValue{
+		{Value: 1},
+		{Constraints: dynamicconfig.Constraints{Environment: "staging"}, Value: 2},
+		{Constraints: dynamicconfig.Constraints{Environment: "prod"}, Value: 3},
+		{Constraints: dynamicconfig.Constraints{Namespace: "my-namespace"}, Value: 4},
+		{Constraints: dynamicconfig.Constraints{Namespace: "my-namespace", Environment: "prod"}, Value: 5},
+	}
+
+	staging := setting.Get(dynamicconfig.NewCollection(dynamicconfig.NewEnvironmentClient(s.client, "staging"), log.NewNoopLogger()))
+	s.Equal(2, staging("other-namespace"))
+	s.Equal(4, staging("my-namespace"))
+
+	prod := setting.Get(dynamicconfig.NewCollection(dynamicconfig.NewEnvironmentClient(s.client, "prod"), log.NewNoopLogger()))
+	s.Equal(3, prod("other-namespace"))
+	s.Equal(5, prod("my-namespace"))
+
+	noEnvironment := setting.Get(dynamicconfig.NewCollection(dynamicconfig.NewEnvironmentClient(s.client, ""), log.NewNoopLogger()))
+	s.Equal(1, noEnvironment("other-namespace"))
+	s.Equal(4, noEnvironment("my-namespace"))
+}
+
+func (s *collectionSuite) TestGetBoolPropertyFilteredBySDKVersion() {
+	setting := dynamicconfig.NewSDKVersionBoolSetting(testGetBoolPropertyFilteredBySDKVersionKey, false, "")
+	value := setting.Get(s.cln)
+	s.False(value("ns", "temporal-go", "1.20.0"))
+
+	s.client[testGetBoolPropertyFilteredBySDKVersionKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{SDKName: "temporal-go", SDKVersion: ">=1.20.0 <2.0.0"},
+			Value:       true,
+		},
+	}
+	for version, expected := range map[string]bool{
+		"1.19.9":        false,
+		"1.20.0":        true,
+		"1.20.1-beta.1": true,
+		"1.31.4":        true,
+		"2.0.0":         false,
+		"":              false,
+		"v1.20.0":       false, // invalid versions don't match
+		"1.20":          false,
+	} {
+		s.Equal(expected, value("ns", "temporal-go", version), version)
+	}
+	s.False(value("ns", "temporal-java", "1.20.0"))
+
+	// namespace+sdk > sdk > namespace > global
+	s.client[testGetBoolPropertyFilteredBySDKVersionKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{Namespace: "ns", SDKName: "temporal-go", SDKVersion: ">=1.25.0"},
+			Value:       false,
+		},
+		{
+			Constraints: dynamicconfig.Constraints{SDKName: "temporal-go", SDKVersion: ">=1.20.0"},
+			Value:       true,
+		},
+		{
+			Constraints: dynamicconfig.Constraints{Namespace: "ns"},
+			Value:       false,
+		},
+	}
+	s.False(value("ns", "temporal-go", "1.25.0"))
+	s.True(value("ns", "temporal-go", "1.24.0"))
+	s.True(value("other-ns", "temporal-go", "1.25.0"))
+	s.False(value("ns", "temporal-go", "1.19.0"))
+
+	// invalid ranges don't match
+	s.client[testGetBoolPropertyFilteredBySDKVersionKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{SDKName: "temporal-go", SDKVersion: "at least 1.20"},
+			Value:       true,
+		},
+	}
+	s.False(value("ns", "temporal-go", "1.20.0"))
+
+	s.client[testGetBoolPropertyFilteredBySDKVersionKey] = []dynamicconfig.ConstrainedValue{
+		{
+			Constraints: dynamicconfig.Constraints{SDKName: "temporal-go", SDKVersion: ">=1.20.0"},
+			Value:       true,
+		},
+	}
+	s.Run("FromHeaders", func() {
+		get := setting.GetWithContext(s.cln)
+		s.True(get(headers.SetVersionsForTests(context.Background(), "1.20.0", "temporal-go", "", ""), "ns"))
+		s.False(get(headers.SetVersionsForTests(context.Background(), "1.19.0", "temporal-go", "", ""), "ns"))
+		s.False(get(context.Background(), "ns"))
+	})
+	s.Run("InGroup", func() {
+		s.True(setting.GetInGroup(s.cln.EvaluateGroup(dynamicconfig.SDKVersionFilter("temporal-go", "1.21.0"))))
+		s.False(setting.GetInGroup(s.cln.EvaluateGroup(dynamicconfig.SDKVersionFilter("temporal-go", "1.2.0"))))
+	})
+}
+
 func (s *collectionSuite) TestGetIntPropertyFilteredByDestination() {
 	setting := dynamicconfig.NewDestinationIntSetting(testGetIntPropertyFilteredByDestinationKey, 10, "")
 	namespaceName := "testNamespace"
@@ -525,3 +1211,1239 @@ func BenchmarkCollection(b *testing.B) {
 		}
 	})
 }
+
+func (s *collectionSuite) TestReadKeys() {
+	cln := dynamicconfig.NewCollection(dynamicconfig.StaticClient{}, log.NewNoopLogger())
+	setting1 := dynamicconfig.NewGlobalIntSetting(testReadKeysKey1, 10, "")
+	setting2 := dynamicconfig.NewNamespaceBoolSetting(testReadKeysKey2, true, "")
+	setting3 := dynamicconfig.NewGlobalStringSetting(testGetStringPropertyKey, "", "")
+
+	// not tracked before enabling
+	setting1.Get(cln)()
+	s.Empty(cln.ReadKeys())
+
+	cln.EnableReadKeyTracking()
+	setting2.Get(cln)("ns1")
+	setting2.Get(cln)("ns2")
+	setting1.Get(cln)()
+	_ = setting3.Get(cln) // never called
+	s.Equal([]dynamicconfig.Key{testReadKeysKey1, testReadKeysKey2}, cln.ReadKeys())
+}
+
+func (s *collectionSuite) TestGetShardIDScaledProperty() {
+	const hostBudget = 4096
+	setting := dynamicconfig.NewShardIDIntSettingWithScaledDefault(
+		testGetShardIDScaledPropertyKey,
+		func(shardID int32, numShards int32) int {
+			return max(hostBudget/int(numShards), 1)
+		},
+		"",
+	)
+
+	s.Run("ScalesWithShardCount", func() {
+		for _, tc := range []struct {
+			numShards int32
+			expected  int
+		}{
+			{numShards: 1, expected: 4096},
+			{numShards: 4, expected: 1024},
+			{numShards: 512, expected: 8},
+			{numShards: 8192, expected: 1},
+		} {
+			get := setting.Get(s.cln, tc.numShards)
+			s.Equal(tc.expected, get(1))
+			s.Equal(tc.expected, get(tc.numShards))
+		}
+	})
+
+	s.Run("UsesShardID", func() {
+		evenOdd := dynamicconfig.NewShardIDIntSettingWithScaledDefault(
+			testGetShardIDScaledPropertyKey+"EvenOdd",
+			func(shardID int32, numShards int32) int {
+				return int(shardID%2) * int(numShards)
+			},
+			"",
+		)
+		get := evenOdd.Get(s.cln, 16)
+		s.Equal(0, get(2))
+		s.Equal(16, get(3))
+	})
+
+	s.Run("Override", func() {
+		get := setting.Get(s.cln, 4)
+		s.client[testGetShardIDScaledPropertyKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{ShardID: 2}, Value: 77},
+		}
+		s.Equal(1024, get(1))
+		s.Equal(77, get(2))
+
+		s.client[testGetShardIDScaledPropertyKey] = "not an int"
+		s.Equal(1024, get(1))
+	})
+}
+
+func (s *collectionSuite) TestValueTransform() {
+	halve := func(key dynamicconfig.Key, raw any) any {
+		switch raw := raw.(type) {
+		case int:
+			return raw / 2
+		case float64:
+			return raw / 2
+		}
+		return raw
+	}
+	setting1 := dynamicconfig.NewGlobalIntSetting(testValueTransformKey1, 100, "")
+	setting2 := dynamicconfig.NewGlobalFloatSetting(testValueTransformKey2, 10, "")
+	get1 := setting1.Get(s.cln)
+	get2 := setting2.Get(s.cln)
+
+	s.cln.SetValueTransform(halve, testValueTransformKey1)
+
+	// applied to the default
+	s.Equal(50, get1())
+	// applied to config values
+	s.client[testValueTransformKey1] = 40
+	s.Equal(20, get1())
+	// transform sees the raw value, before conversion to int
+	s.client[testValueTransformKey1] = uint32(40)
+	s.Equal(40, get1())
+	// other keys are not affected
+	s.Equal(10.0, get2())
+	s.client[testValueTransformKey2] = 4.0
+	s.Equal(4.0, get2())
+
+	// key match is case-insensitive, replaces existing transform
+	s.cln.SetValueTransform(func(key dynamicconfig.Key, raw any) any {
+		return "not a number"
+	}, "TESTVALUETRANSFORMKEY1", testValueTransformKey2)
+	// transformed value can't be converted, falls back to untransformed default
+	s.Equal(100, get1())
+	s.Equal(10.0, get2())
+}
+
+func (s *collectionSuite) TestGetCronSchedule() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetCronSchedulePropertyKey,
+		dynamicconfig.ConvertCronSchedule,
+		dynamicconfig.MustParseCronSchedule("0 3 * * *"),
+		"",
+	)
+	get := setting.Get(s.cln)
+	after := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	s.Run("Default", func() {
+		s.Equal("0 3 * * *", get().String())
+		s.Equal(time.Date(2024, 3, 2, 3, 0, 0, 0, time.UTC), get().Next(after))
+	})
+
+	s.Run("Valid", func() {
+		s.client[testGetCronSchedulePropertyKey] = "*/15 * * * *"
+		s.Equal(time.Date(2024, 3, 1, 12, 45, 0, 0, time.UTC), get().Next(after))
+	})
+
+	s.Run("TimeZone", func() {
+		s.client[testGetCronSchedulePropertyKey] = "CRON_TZ=America/New_York 0 9 * * *"
+		// 9am EST is 2pm UTC
+		s.Equal(time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC), get().Next(after).UTC())
+	})
+
+	s.Run("Empty", func() {
+		s.client[testGetCronSchedulePropertyKey] = ""
+		s.True(get().Next(after).IsZero())
+	})
+
+	s.Run("InvalidFallsBackToDefault", func() {
+		for _, spec := range []any{
+			"not a cron",
+			"0 3 * *",
+			"CRON_TZ=Not/AZone 0 3 * * *",
+			"0 0 30 2 *", // never fires
+			123,
+		} {
+			s.client[testGetCronSchedulePropertyKey] = spec
+			s.Equal("0 3 * * *", get().String(), spec)
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetDisableableDuration() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetDisableableDurationPropertyKey,
+		dynamicconfig.ConvertDisableableDuration,
+		dynamicconfig.DisableableDuration(time.Minute),
+		"",
+	)
+	get := setting.Get(s.cln)
+
+	s.Run("Default", func() {
+		s.True(get().IsEnabled())
+		s.Equal(time.Minute, get().Duration())
+	})
+
+	s.Run("Positive", func() {
+		for _, v := range []any{"5s", 5, 5.0, 5 * time.Second} {
+			s.client[testGetDisableableDurationPropertyKey] = v
+			s.True(get().IsEnabled(), v)
+			s.Equal(5*time.Second, get().Duration(), v)
+		}
+	})
+
+	s.Run("Zero", func() {
+		for _, v := range []any{"0s", 0} {
+			s.client[testGetDisableableDurationPropertyKey] = v
+			s.True(get().IsEnabled(), v)
+			s.Zero(get().Duration(), v)
+		}
+	})
+
+	s.Run("Never", func() {
+		for _, v := range []any{"off", "Disabled", "never", "-1", -1} {
+			s.client[testGetDisableableDurationPropertyKey] = v
+			s.False(get().IsEnabled(), v)
+			s.Equal(dynamicconfig.NeverDuration, get(), v)
+			s.Equal("off", get().String())
+		}
+	})
+
+	s.Run("InvalidFallsBackToDefault", func() {
+		for _, v := range []any{"-5s", -2, "soon", true} {
+			s.client[testGetDisableableDurationPropertyKey] = v
+			s.Equal(dynamicconfig.DisableableDuration(time.Minute), get(), v)
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetRate() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetRatePropertyKey,
+		dynamicconfig.ConvertRate,
+		dynamicconfig.MustParseRate("10/m"),
+		"",
+	)
+	get := setting.Get(s.cln)
+
+	s.Run("Default", func() {
+		s.Equal(dynamicconfig.Rate{Count: 10, Per: time.Minute}, get())
+		s.Equal("10/m", get().String())
+	})
+
+	s.Run("Units", func() {
+		for v, rps := range map[string]float64{
+			"100/s":    100,
+			"5/m":      5.0 / 60,
+			"1000/h":   1000.0 / 3600,
+			"864/d":    0.01,
+			" 2.5 / M": 2.5 / 60,
+		} {
+			s.client[testGetRatePropertyKey] = v
+			s.InDelta(rps, get().RPS(), 1e-9, v)
+		}
+	})
+
+	s.Run("BareNumberIsRPS", func() {
+		for _, v := range []any{5, 5.0, "5", int64(5)} {
+			s.client[testGetRatePropertyKey] = v
+			s.Equal(dynamicconfig.PerSecond(5), get(), v)
+			s.Equal(5.0, get().RPS(), v)
+		}
+	})
+
+	s.Run("Limiter", func() {
+		s.client[testGetRatePropertyKey] = "120/m"
+		limiter := get().Limiter(3)
+		s.Equal(rate.Limit(2), limiter.Limit())
+		s.Equal(3, limiter.Burst())
+	})
+
+	s.Run("InvalidFallsBackToDefault", func() {
+		for _, v := range []any{"5/week", "/s", "five/s", "-5/s", -1, true} {
+			s.client[testGetRatePropertyKey] = v
+			s.Equal(dynamicconfig.MustParseRate("10/m"), get(), v)
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetStrategy() {
+	registry := map[string]func() string{
+		"round-robin": func() string { return "round-robin balancer" },
+		"random":      func() string { return "random balancer" },
+	}
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetStrategyPropertyKey,
+		dynamicconfig.ConvertStrategy(registry),
+		dynamicconfig.MustStrategy(registry, "round-robin"),
+		"",
+	)
+	get := setting.Get(s.cln)
+
+	s.Run("Default", func() {
+		s.Equal("round-robin", get().Name)
+		s.Equal("round-robin balancer", get().Impl())
+	})
+
+	s.Run("Valid", func() {
+		s.client[testGetStrategyPropertyKey] = "random"
+		s.Equal("random", get().Name)
+		s.Equal("random balancer", get().Impl())
+	})
+
+	s.Run("ReselectedOnChange", func() {
+		s.client[testGetStrategyPropertyKey] = "random"
+		s.Equal("random balancer", get().Impl())
+		s.client[testGetStrategyPropertyKey] = "round-robin"
+		s.Equal("round-robin balancer", get().Impl())
+	})
+
+	s.Run("InvalidFallsBackToDefault", func() {
+		for _, v := range []any{"least-loaded", "Random", "", 1, true} {
+			s.client[testGetStrategyPropertyKey] = v
+			s.Equal("round-robin", get().Name, v)
+			s.Equal("round-robin balancer", get().Impl(), v)
+		}
+	})
+
+	s.Run("UnknownDefaultPanics", func() {
+		s.Panics(func() { dynamicconfig.MustStrategy(registry, "least-loaded") })
+	})
+}
+
+func (s *collectionSuite) TestGetTimeSchedule() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetTimeSchedulePropertyKey,
+		dynamicconfig.ConvertTimeSchedule(dynamicconfig.ConvertStructure(0)),
+		dynamicconfig.TimeSchedule[int]{},
+		"",
+	)
+	timeSource := clock.NewEventTimeSource()
+	cln := dynamicconfig.NewCollection(s.client, log.NewNoopLogger())
+	cln.SetExpiryClock(timeSource, 0)
+	get := dynamicconfig.Scheduled(cln, setting.Get(cln), 50)
+	twoPM := time.Date(2024, 6, 1, 14, 0, 0, 0, time.UTC)
+
+	s.Run("Default", func() {
+		timeSource.Update(twoPM)
+		s.Equal(50, get())
+	})
+
+	s.Run("CrossingBoundaries", func() {
+		// entries out of order are sorted
+		s.client[testGetTimeSchedulePropertyKey] = []any{
+			map[string]any{"effectiveFrom": "2024-06-01T16:00:00Z", "value": 300},
+			map[string]any{"value": 100},
+			map[string]any{"effectiveFrom": twoPM, "value": 200},
+		}
+		timeSource.Update(twoPM.Add(-time.Second))
+		s.Equal(100, get())
+		timeSource.Update(twoPM)
+		s.Equal(200, get())
+		timeSource.Advance(time.Hour)
+		s.Equal(200, get())
+		timeSource.Advance(time.Hour)
+		s.Equal(300, get())
+	})
+
+	s.Run("BeforeFirstEntry", func() {
+		s.client[testGetTimeSchedulePropertyKey] = []any{
+			map[string]any{"effectiveFrom": "2024-06-01T14:00:00Z", "value": 200},
+		}
+		timeSource.Update(twoPM.Add(-time.Minute))
+		s.Equal(50, get())
+		timeSource.Update(twoPM)
+		s.Equal(200, get())
+	})
+
+	s.Run("Empty", func() {
+		s.client[testGetTimeSchedulePropertyKey] = []any{}
+		s.Equal(50, get())
+	})
+
+	s.Run("InvalidFallsBackToDefault", func() {
+		for _, v := range []any{
+			"200",
+			[]any{map[string]any{"effectiveFrom": "2pm", "value": 200}},
+			[]any{map[string]any{"effectiveFrom": "2024-06-01T14:00:00Z"}},
+			[]any{map[string]any{"value": "lots"}},
+			[]any{map[string]any{"value": 200, "until": "2024-06-01T14:00:00Z"}},
+		} {
+			s.client[testGetTimeSchedulePropertyKey] = v
+			s.Equal(50, get(), v)
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetJSONSchema() {
+	setting := dynamicconfig.NewGlobalTypedSettingWithConverter(
+		testGetJSONSchemaPropertyKey,
+		dynamicconfig.NewJSONSchemaConverter(),
+		dynamicconfig.MustParseJSONSchema(`{"type": "object", "required": ["id"]}`),
+		"",
+	)
+	get := setting.Get(s.cln)
+
+	s.Run("Default", func() {
+		s.NoError(get().Validate([]byte(`{"id": 1}`)))
+		s.Error(get().Validate([]byte(`{}`)))
+	})
+
+	s.Run("Valid", func() {
+		s.client[testGetJSONSchemaPropertyKey] = `{"type": "object", "properties": {"name": {"type": "string"}}}`
+		s.NoError(get().Validate([]byte(`{}`)))
+		s.NoError(get().Validate([]byte(`{"name": "n"}`)))
+		s.Error(get().Validate([]byte(`{"name": 1}`)))
+	})
+
+	s.Run("Empty", func() {
+		s.client[testGetJSONSchemaPropertyKey] = ""
+		s.NoError(get().Validate([]byte(`[1, "two", null]`)))
+		s.Error(get().Validate([]byte(`not json`)))
+	})
+
+	s.Run("InvalidFallsBackToDefault", func() {
+		for _, schema := range []any{
+			`{"type": "object"`,
+			`{"type": "obj"}`,
+			`{"oneOf": [{"type": "string"}]}`,
+			`[]`,
+			123,
+		} {
+			s.client[testGetJSONSchemaPropertyKey] = schema
+			s.Equal(`{"type": "object", "required": ["id"]}`, get().String(), schema)
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetWithAccept() {
+	const floor = 3 * time.Second
+	base := dynamicconfig.NewNamespaceDurationSetting(testGetAcceptedPropertyKey, 5*time.Second, "")
+	get := base.WithAccept(func(d time.Duration) bool { return d >= floor }).Get(s.cln)
+
+	s.Run("Default", func() {
+		s.Equal(5*time.Second, get("ns"))
+	})
+
+	s.Run("Accepted", func() {
+		s.client[testGetAcceptedPropertyKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns"}, Value: "4s"},
+			{Value: "10s"},
+		}
+		s.Equal(4*time.Second, get("ns"))
+		s.Equal(10*time.Second, get("other"))
+	})
+
+	s.Run("RejectedFallsThroughToNextMatch", func() {
+		s.client[testGetAcceptedPropertyKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns"}, Value: "1s"},
+			{Value: "10s"},
+		}
+		s.Equal(10*time.Second, get("ns"))
+	})
+
+	s.Run("UnconvertibleFallsThroughToNextMatch", func() {
+		s.client[testGetAcceptedPropertyKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns"}, Value: "not a duration"},
+			{Value: "10s"},
+		}
+		s.Equal(10*time.Second, get("ns"))
+	})
+
+	s.Run("AllRejectedFallsBackToDefault", func() {
+		s.client[testGetAcceptedPropertyKey] = []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns"}, Value: "1s"},
+			{Value: "2s"},
+		}
+		s.Equal(5*time.Second, get("ns"))
+		s.Equal(5*time.Second, get("other"))
+	})
+
+	s.Run("OriginalSettingUnaffected", func() {
+		s.client[testGetAcceptedPropertyKey] = "1s"
+		s.Equal(5*time.Second, get("ns"))
+		s.Equal(time.Second, base.Get(s.cln)("ns"))
+	})
+}
+
+func (s *collectionSuite) TestWithValidator() {
+	var validated []int
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetValidatedPropertyKey, 100, "").
+		WithValidator(func(v int) error {
+			validated = append(validated, v)
+			if v <= 0 {
+				return errors.New("cache size must be positive")
+			}
+			return nil
+		})
+	controller := gomock.NewController(s.T())
+	logger := log.NewMockLogger(controller)
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	get := setting.Get(dynamicconfig.NewCollection(s.client, logger))
+
+	s.client[testGetValidatedPropertyKey] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "bad"}, Value: 0},
+		{Constraints: dynamicconfig.Constraints{Namespace: "good"}, Value: 50},
+	}
+	// the bad override is logged once as an error and the default is used
+	logger.EXPECT().Error("Dynamic config value failed validation, ignoring it", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	for i := 0; i < 3; i++ {
+		s.Equal(100, get("bad"))
+		s.Equal(50, get("good"))
+		s.Equal(100, get("other"))
+	}
+	// each distinct value is validated once
+	s.ElementsMatch([]int{0, 50, 100}, validated)
+}
+
+func (s *collectionSuite) TestMergedClient() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetMergedPropertyKey, 0, "")
+	otherSetting := dynamicconfig.NewGlobalIntSetting(testGetIntPropertyKey, 0, "")
+	controller := gomock.NewController(s.T())
+	logger := log.NewMockLogger(controller)
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	base := dynamicconfig.StaticClient{
+		testGetMergedPropertyKey: []dynamicconfig.ConstrainedValue{
+			{Value: 1},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 2},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 3},
+		},
+		testGetIntPropertyKey: 100,
+	}
+	overlay := dynamicconfig.StaticClient{
+		testGetMergedPropertyKey: []dynamicconfig.ConstrainedValue{
+			{Value: 10},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-c"}, Value: 30},
+		},
+	}
+	local := dynamicconfig.StaticClient{
+		testGetMergedPropertyKey: []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 200},
+		},
+	}
+	client := dynamicconfig.NewMergedClient(logger, base, overlay, local)
+	get := setting.Get(dynamicconfig.NewCollection(client, logger))
+
+	// one conflict for the global value (base vs overlay) and one for ns-b (base vs local)
+	logger.EXPECT().Info("Dynamic config value is overridden by a client with higher precedence", gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	for i := 0; i < 3; i++ {
+		s.Equal(10, get("other-namespace"), "overlay wins over base")
+		s.Equal(2, get("ns-a"), "only in base")
+		s.Equal(200, get("ns-b"), "highest precedence wins")
+		s.Equal(30, get("ns-c"), "only in overlay")
+	}
+	s.Equal(100, otherSetting.Get(dynamicconfig.NewCollection(client, logger))(), "key only in base")
+
+	s.Equal([]dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 200},
+		{Value: 10},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-c"}, Value: 30},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 2},
+	}, client.GetValue(testGetMergedPropertyKey))
+	s.Nil(client.GetValue(unknownKey))
+}
+
+func (s *collectionSuite) TestTrace() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testTracePropertyKey, 10, "")
+	overlaidSetting := dynamicconfig.NewGlobalIntSetting(testTraceOverlaidPropertyKey, 1, "")
+	unsetSetting := dynamicconfig.NewGlobalIntSetting(testTraceUnsetPropertyKey, 42, "")
+	base := dynamicconfig.StaticClient{
+		testTracePropertyKey: []dynamicconfig.ConstrainedValue{
+			{Value: 1},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 2},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 3},
+		},
+		testTraceOverlaidPropertyKey: 5,
+	}
+	environmentFile := dynamicconfig.StaticClient{
+		testTracePropertyKey: []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-b", Environment: "staging"}, Value: 20},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-c"}, Value: "not a number"},
+		},
+	}
+	client, err := dynamicconfig.NewOverlayClient(
+		dynamicconfig.NewMergedClient(log.NewNoopLogger(), base, dynamicconfig.NewEnvironmentClient(environmentFile, "staging")),
+		[]string{testTraceOverlaidPropertyKey + "=7"},
+	)
+	s.NoError(err)
+	// tracing doesn't log
+	cln := dynamicconfig.NewCollection(client, log.NewMockLogger(gomock.NewController(s.T())))
+	cln.EnableReadKeyTracking()
+	get := setting.Get(dynamicconfig.NewCollection(client, log.NewNoopLogger()))
+
+	result := dynamicconfig.Trace(cln, setting, dynamicconfig.NamespaceFilter("ns-b"))
+	s.Equal(dynamicconfig.Key(testTracePropertyKey), result.Key)
+	var layers []string
+	for _, v := range result.Values {
+		layers = append(layers, fmt.Sprintf("%s %v", v.Layer, v.Value))
+	}
+	s.Equal([]string{
+		"merged[1] > static 20",
+		"merged[1] > static not a number",
+		"merged[0] > static 1",
+		"merged[0] > static 2",
+	}, layers, "ns-b of base is overridden")
+	s.Equal("merged[1] > static", result.Match.Layer)
+	s.Equal(dynamicconfig.Constraints{Namespace: "ns-b"}, result.MatchedConstraints)
+	s.Equal(20, result.Raw)
+	s.Equal(20, result.Value)
+	s.Equal(get("ns-b"), result.Value)
+
+	result = dynamicconfig.Trace(cln, setting, dynamicconfig.NamespaceFilter("ns-a"))
+	s.Equal("merged[0] > static", result.Match.Layer)
+	s.Equal(2, result.Value)
+
+	result = dynamicconfig.Trace(cln, setting, dynamicconfig.NamespaceFilter("other"))
+	s.Equal("merged[0] > static", result.Match.Layer)
+	s.Equal(dynamicconfig.Constraints{}, result.MatchedConstraints)
+	s.Equal(1, result.Value)
+
+	// the matched value can't be converted, so the default is used
+	result = dynamicconfig.Trace(cln, setting, dynamicconfig.NamespaceFilter("ns-c"))
+	s.Equal("merged[1] > static", result.Match.Layer)
+	s.Equal("not a number", result.Raw)
+	s.Error(result.ConvertError)
+	s.Equal(10, result.Value)
+	s.Equal(get("ns-c"), result.Value)
+
+	overlaid := dynamicconfig.Trace(cln, overlaidSetting)
+	s.Equal("overlay", overlaid.Match.Layer)
+	s.Equal(7, overlaid.Value)
+
+	unset := dynamicconfig.Trace(cln, unsetSetting)
+	s.Empty(unset.Values)
+	s.Equal("default", unset.Match.Layer)
+	s.Equal(42, unset.Raw)
+	s.Equal(42, unset.Value)
+
+	s.Empty(cln.ReadKeys())
+}
+
+func (s *collectionSuite) TestExpiringValue() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetExpiringPropertyKey, 10, "")
+	controller := gomock.NewController(s.T())
+	logger := log.NewMockLogger(controller)
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	cln := dynamicconfig.NewCollection(s.client, logger)
+	cln.SetExpiryClock(timeSource, 0)
+	get := setting.Get(cln)
+
+	expiresAt := timeSource.Now().Add(time.Hour)
+	s.client[testGetExpiringPropertyKey] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: 100, ExpiresAt: expiresAt},
+		{Value: 50, ExpiresAt: expiresAt.Add(time.Hour)},
+	}
+	s.Equal(100, get("samples-namespace"))
+	s.Equal(50, get("other-namespace"))
+	timeSource.Update(expiresAt)
+	s.Equal(100, get("samples-namespace"))
+
+	// the expired namespace override is ignored, and logged only once
+	logger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	timeSource.Update(expiresAt.Add(time.Second))
+	s.Equal(50, get("samples-namespace"))
+	s.Equal(50, get("samples-namespace"))
+	s.Equal(50, get("other-namespace"))
+
+	// once everything expired, the default is used
+	logger.EXPECT().Info(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	timeSource.Update(expiresAt.Add(2 * time.Hour))
+	s.Equal(10, get("samples-namespace"))
+	s.Equal(10, get("other-namespace"))
+}
+
+func (s *collectionSuite) TestDeprecatedKeys() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testDeprecatedKeysKey, 10, "")
+	controller := gomock.NewController(s.T())
+	logger := log.NewMockLogger(controller)
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	client := make(dynamicconfig.StaticClient)
+	cln := dynamicconfig.NewCollection(client, logger)
+	cln.SetDeprecatedKeys(testDeprecatedKeysKey, testDeprecatedKeysOldKey1, testDeprecatedKeysOldKey2)
+	get := setting.Get(cln)
+	s.Equal(10, get("ns"))
+
+	// the old key is used if the new one is absent, and the deprecation is logged only once
+	client[testDeprecatedKeysOldKey2] = 30
+	logger.EXPECT().Warn("Dynamic config key is deprecated, please rename it", gomock.Any(), gomock.Any()).Times(1)
+	s.Equal(30, get("ns"))
+	s.Equal(30, get("ns"))
+	s.True(cln.HasKey(testDeprecatedKeysKey))
+
+	// earlier deprecated keys take precedence over later ones
+	client[testDeprecatedKeysOldKey1] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "other-ns"}, Value: 20},
+	}
+	logger.EXPECT().Warn("Dynamic config key is deprecated, please rename it", gomock.Any(), gomock.Any()).Times(1)
+	s.Equal(10, get("ns"))
+	s.Equal(20, get("other-ns"))
+	s.Equal(map[string]int{"other-ns": 20}, setting.GetNamespaceMap(cln)())
+
+	// the new key wins over all deprecated keys, without logging
+	client[testDeprecatedKeysKey] = 40
+	s.Equal(40, get("ns"))
+	s.Equal(40, get("other-ns"))
+}
+
+func (s *collectionSuite) TestReload() {
+	dynamicconfig.NewNamespaceIntSetting(testReloadKey1, 0, "")
+	dynamicconfig.NewNamespaceIntSetting(testReloadKey2, 0, "")
+	dynamicconfig.NewGlobalStringSetting(testReloadKey3, "", "")
+	client := dynamicconfig.StaticClient{
+		testReloadKey1: []dynamicconfig.ConstrainedValue{
+			{Value: 1},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 2},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 3},
+		},
+		testReloadKey2: 10,
+	}
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+
+	// nothing changed by the first reload
+	changes, err := cln.Reload()
+	s.NoError(err)
+	s.Empty(changes)
+
+	client[testReloadKey1] = []dynamicconfig.ConstrainedValue{
+		{Value: 1},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 20},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-c"}, Value: 4},
+	}
+	delete(client, testReloadKey2)
+	client[testReloadKey3] = "new"
+	changes, err = cln.Reload()
+	s.NoError(err)
+	s.Equal([]dynamicconfig.ValueChange{
+		{
+			Key:         testReloadKey1,
+			Constraints: dynamicconfig.Constraints{Namespace: "ns-a"},
+			Old:         &dynamicconfig.ConstrainedValue{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 2},
+			New:         &dynamicconfig.ConstrainedValue{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 20},
+		},
+		{
+			Key:         testReloadKey1,
+			Constraints: dynamicconfig.Constraints{Namespace: "ns-b"},
+			Old:         &dynamicconfig.ConstrainedValue{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 3},
+		},
+		{
+			Key:         testReloadKey1,
+			Constraints: dynamicconfig.Constraints{Namespace: "ns-c"},
+			New:         &dynamicconfig.ConstrainedValue{Constraints: dynamicconfig.Constraints{Namespace: "ns-c"}, Value: 4},
+		},
+		{
+			Key: testReloadKey2,
+			Old: &dynamicconfig.ConstrainedValue{Value: 10},
+		},
+		{
+			Key: testReloadKey3,
+			New: &dynamicconfig.ConstrainedValue{Value: "new"},
+		},
+	}, changes)
+
+	// changes are reported once
+	changes, err = cln.Reload()
+	s.NoError(err)
+	s.Empty(changes)
+}
+
+func (s *collectionSuite) TestBind() {
+	setting := dynamicconfig.NewGlobalIntSetting(testBindKey, 5, "")
+	client := &swappableClient{}
+	client.set(dynamicconfig.StaticClient{testBindKey: 1})
+	timeSource := clock.NewEventTimeSource()
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	cln.SetExpiryClock(timeSource, 0)
+
+	value, cancel := setting.Bind(cln)
+	s.Equal(1, *value.Load())
+
+	// changes are picked up by polling
+	client.set(dynamicconfig.StaticClient{testBindKey: 2})
+	s.Eventually(func() bool {
+		timeSource.Advance(time.Minute)
+		return *value.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	// and right away on reload
+	client.set(dynamicconfig.StaticClient{})
+	_, err := cln.Reload()
+	s.NoError(err)
+	s.Equal(5, *value.Load())
+
+	// the poll loop stops with the last binding
+	cancel()
+	s.Eventually(func() bool { return timeSource.NumTimers() == 0 }, time.Second, time.Millisecond)
+	client.set(dynamicconfig.StaticClient{testBindKey: 3})
+	_, err = cln.Reload()
+	s.NoError(err)
+	s.Equal(5, *value.Load())
+}
+
+func (s *collectionSuite) TestRegisterVirtualSetting() {
+	input1 := dynamicconfig.NewNamespaceIntSetting(testVirtualSettingInputKey1, 10, "")
+	input2 := dynamicconfig.NewGlobalIntSetting(testVirtualSettingInputKey2, 20, "")
+	virtual := dynamicconfig.NewNamespaceIntSetting(testVirtualSettingKey, 0, "").
+		WithValidator(func(v int) error {
+			if v < 0 {
+				return errors.New("must not be negative")
+			}
+			return nil
+		})
+	client := &swappableClient{}
+	client.set(dynamicconfig.StaticClient{
+		// ignored, the value is computed
+		testVirtualSettingKey: 1000,
+	})
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	cln.RegisterVirtualSetting(virtual.Key(), func(cons dynamicconfig.Constraints) any {
+		return min(
+			dynamicconfig.ResolveWithConstraints(cln, input1, cons),
+			dynamicconfig.ResolveWithConstraints(cln, input2, cons),
+		)
+	})
+	get := virtual.Get(cln)
+	s.Equal(10, get("ns-a"))
+
+	// changes to the inputs are seen right away, per namespace
+	client.set(dynamicconfig.StaticClient{
+		testVirtualSettingInputKey1: []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 30},
+			{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 3},
+		},
+	})
+	s.Equal(20, get("ns-a"))
+	s.Equal(3, get("ns-b"))
+	s.Equal(10, get("ns-c"))
+	s.Equal(20, dynamicconfig.ResolveWithConstraints(cln, virtual, dynamicconfig.Constraints{Namespace: "ns-a"}))
+	s.Equal(3, virtual.GetInGroup(cln.EvaluateGroup(dynamicconfig.NamespaceFilter("ns-b"))))
+
+	result := dynamicconfig.Trace(cln, virtual, dynamicconfig.NamespaceFilter("ns-b"))
+	s.Equal(3, result.Value)
+	s.Equal("virtual", result.Match.Layer)
+
+	// values rejected by the setting fall back to its default
+	client.set(dynamicconfig.StaticClient{testVirtualSettingInputKey2: -5})
+	s.Equal(0, get("ns-a"))
+}
+
+func (s *collectionSuite) TestRegisterVirtualSetting_Bind() {
+	input1 := dynamicconfig.NewGlobalIntSetting(testVirtualSettingInputKey1, 10, "")
+	input2 := dynamicconfig.NewGlobalIntSetting(testVirtualSettingInputKey2, 20, "")
+	virtual := dynamicconfig.NewGlobalIntSetting(testVirtualSettingBoundKey, 0, "")
+	client := &swappableClient{}
+	client.set(dynamicconfig.StaticClient{})
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	cln.RegisterVirtualSetting(virtual.Key(), func(dynamicconfig.Constraints) any {
+		return min(input1.Get(cln)(), input2.Get(cln)())
+	})
+
+	value, cancel := virtual.Bind(cln)
+	defer cancel()
+	s.Equal(10, *value.Load())
+
+	// changes to the inputs flow through to the bound value
+	client.set(dynamicconfig.StaticClient{testVirtualSettingInputKey1: 50})
+	_, err := cln.Reload()
+	s.NoError(err)
+	s.Equal(20, *value.Load())
+
+	client.set(dynamicconfig.StaticClient{testVirtualSettingInputKey1: 50, testVirtualSettingInputKey2: 7})
+	_, err = cln.Reload()
+	s.NoError(err)
+	s.Equal(7, *value.Load())
+}
+
+func (s *collectionSuite) TestExportValueGauge() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testExportValueGaugeKey, 10, "")
+	client := &swappableClient{}
+	client.set(dynamicconfig.StaticClient{testExportValueGaugeKey: []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-a"}, Value: 20},
+		// task queue constraints are not exported, nor do they change the value of the namespace
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-a", TaskQueueName: "tq"}, Value: 30},
+	}})
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	cln.SetMetricsHandler(metricsHandler)
+
+	gaugeValues := func() map[string]any {
+		values := make(map[string]any)
+		for _, recording := range capture.Snapshot()[metrics.DynamicConfigValue.Name()] {
+			s.Equal(testExportValueGaugeKey, recording.Tags["dynamic_config_key"])
+			s.Len(recording.Tags, 2)
+			values[recording.Tags["namespace"]] = recording.Value
+		}
+		return values
+	}
+
+	stop := dynamicconfig.ExportValueGauge(cln, setting, dynamicconfig.GaugeDimensionNamespace)
+	defer stop()
+	s.Equal(map[string]any{"_unknown_": 10.0, "ns-a": 20.0}, gaugeValues())
+
+	// the gauge is updated on change, and a removed override falls back to the default
+	metricsHandler.StopCapture(capture)
+	capture = metricsHandler.StartCapture()
+	client.set(dynamicconfig.StaticClient{testExportValueGaugeKey: []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns-b"}, Value: 40},
+		{Value: 15},
+	}})
+	_, err := cln.Reload()
+	s.NoError(err)
+	s.Equal(map[string]any{"_unknown_": 15.0, "ns-a": 15.0, "ns-b": 40.0}, gaugeValues())
+
+	// stopped gauges are no longer emitted
+	stop()
+	metricsHandler.StopCapture(capture)
+	capture = metricsHandler.StartCapture()
+	_, err = cln.Reload()
+	s.NoError(err)
+	s.Empty(gaugeValues())
+}
+
+// swappableClient is a client whose values can be replaced while they're read by another goroutine.
+type swappableClient struct {
+	values atomic.Pointer[dynamicconfig.StaticClient]
+}
+
+func (c *swappableClient) set(values dynamicconfig.StaticClient) {
+	c.values.Store(&values)
+}
+
+func (c *swappableClient) GetValue(key dynamicconfig.Key) []dynamicconfig.ConstrainedValue {
+	return c.values.Load().GetValue(key)
+}
+
+// slowClient is a client that doesn't return values until it's unblocked.
+type slowClient struct {
+	values    dynamicconfig.StaticClient
+	unblocked chan struct{}
+}
+
+func (c *slowClient) GetValue(key dynamicconfig.Key) []dynamicconfig.ConstrainedValue {
+	<-c.unblocked
+	return c.values.GetValue(key)
+}
+
+// cancelableClient is a client whose lookups block until their context is canceled.
+type cancelableClient struct {
+	canceled chan struct{}
+}
+
+func (c *cancelableClient) GetValue(dynamicconfig.Key) []dynamicconfig.ConstrainedValue {
+	panic("GetValueWithContext should be used")
+}
+
+func (c *cancelableClient) GetValueWithContext(ctx context.Context, _ dynamicconfig.Key) []dynamicconfig.ConstrainedValue {
+	<-ctx.Done()
+	close(c.canceled)
+	return nil
+}
+
+func (s *collectionSuite) TestResolveWithTimeout() {
+	setting := dynamicconfig.NewNamespaceIntSettingWithConstrainedDefault(
+		testResolveWithTimeoutKey,
+		[]dynamicconfig.TypedConstrainedValue[int]{
+			{Constraints: dynamicconfig.Constraints{Namespace: "special"}, Value: 7},
+			{Value: 10},
+		},
+		"",
+	)
+	client := &slowClient{
+		values:    dynamicconfig.StaticClient{testResolveWithTimeoutKey: 50},
+		unblocked: make(chan struct{}),
+	}
+	defer close(client.unblocked)
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	cln.SetMetricsHandler(metricsHandler)
+
+	s.Run("DefaultOnTimeout", func() {
+		start := time.Now()
+		s.Equal(10, dynamicconfig.ResolveWithTimeout(context.Background(), cln, setting, 10*time.Millisecond, dynamicconfig.NamespaceFilter("ns")))
+		s.Less(time.Since(start), time.Second)
+		s.Equal(7, dynamicconfig.ResolveWithTimeout(context.Background(), cln, setting, 10*time.Millisecond, dynamicconfig.NamespaceFilter("special")))
+
+		recordings := capture.Snapshot()[metrics.DynamicConfigResolveTimeouts.Name()]
+		s.Len(recordings, 2)
+		s.Equal(int64(1), recordings[0].Value)
+		s.Equal(map[string]string{"dynamic_config_key": testResolveWithTimeoutKey}, recordings[0].Tags)
+	})
+
+	s.Run("DefaultOnContextDone", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s.Equal(10, dynamicconfig.ResolveWithTimeout(ctx, cln, setting, time.Minute, dynamicconfig.NamespaceFilter("ns")))
+	})
+
+	s.Run("ValueInTime", func() {
+		fastCln := dynamicconfig.NewCollection(client.values, log.NewNoopLogger())
+		fastCln.SetMetricsHandler(metricsHandler)
+		s.Equal(50, dynamicconfig.ResolveWithTimeout(context.Background(), fastCln, setting, time.Minute, dynamicconfig.NamespaceFilter("ns")))
+		s.Len(capture.Snapshot()[metrics.DynamicConfigResolveTimeouts.Name()], 3)
+	})
+
+	s.Run("LookupCanceledOnTimeout", func() {
+		cancelable := &cancelableClient{canceled: make(chan struct{})}
+		cancelableCln := dynamicconfig.NewCollection(cancelable, log.NewNoopLogger())
+		s.Equal(10, dynamicconfig.ResolveWithTimeout(context.Background(), cancelableCln, setting, 10*time.Millisecond, dynamicconfig.NamespaceFilter("ns")))
+		select {
+		case <-cancelable.canceled:
+		case <-time.After(time.Second):
+			s.Fail("lookup was not canceled")
+		}
+	})
+}
+
+func (s *collectionSuite) TestGetWithLogger() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetWithLoggerKey, 10, "")
+	controller := gomock.NewController(s.T())
+	collectionLogger := log.NewMockLogger(controller)
+	componentLogger := log.NewMockLogger(controller)
+	cln := dynamicconfig.NewCollection(s.client, collectionLogger)
+	s.client[testGetWithLoggerKey] = "not a number"
+
+	// the conversion warning goes to the supplied logger only
+	componentLogger.EXPECT().Warn("Failed to convert value, using default", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.Equal(10, setting.GetWithLogger(cln, componentLogger)("ns"))
+
+	// without a logger, the collection's logger is used
+	collectionLogger.EXPECT().Warn("Failed to convert value, using default", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.Equal(10, setting.GetWithLogger(cln, nil)("ns"))
+}
+
+func (s *collectionSuite) TestExpiringValue_ClockSkewTolerance() {
+	setting := dynamicconfig.NewGlobalIntSetting(testGetExpiringPropertyKey, 10, "")
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	cln := dynamicconfig.NewCollection(s.client, log.NewNoopLogger())
+	cln.SetExpiryClock(timeSource, 5*time.Second)
+	get := setting.Get(cln)
+
+	expiresAt := timeSource.Now().Add(time.Minute)
+	s.client[testGetExpiringPropertyKey] = []dynamicconfig.ConstrainedValue{
+		{Value: 100, ExpiresAt: expiresAt},
+	}
+	timeSource.Update(expiresAt.Add(3 * time.Second))
+	s.Equal(100, get())
+	timeSource.Update(expiresAt.Add(6 * time.Second))
+	s.Equal(10, get())
+}
+
+func (s *collectionSuite) TestListExpiringOverrides() {
+	dynamicconfig.NewNamespaceIntSetting(testListExpiringOverridesKey1, 10, "")
+	dynamicconfig.NewGlobalBoolSetting(testListExpiringOverridesKey2, false, "")
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	client := dynamicconfig.StaticClient{
+		testListExpiringOverridesKey1: []dynamicconfig.ConstrainedValue{
+			{Value: 11},
+			{Constraints: dynamicconfig.Constraints{Namespace: "soon"}, Value: 12, ExpiresAt: now.Add(time.Minute)},
+			{Constraints: dynamicconfig.Constraints{Namespace: "later"}, Value: 13, ExpiresAt: now.Add(2 * time.Hour)},
+			{Constraints: dynamicconfig.Constraints{Namespace: "expired"}, Value: 14, ExpiresAt: now.Add(-time.Minute)},
+		},
+		testListExpiringOverridesKey2: []dynamicconfig.ConstrainedValue{
+			{Value: true, ExpiresAt: now.Add(30 * time.Minute)},
+		},
+		"unregisteredKey": []dynamicconfig.ConstrainedValue{
+			{Value: 1, ExpiresAt: now.Add(time.Minute)},
+		},
+	}
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	cln.SetExpiryClock(timeSource, 5*time.Second)
+
+	s.Empty(cln.ListExpiringOverrides(30 * time.Second))
+	s.Equal([]dynamicconfig.ExpiringOverride{
+		{
+			Key:         testListExpiringOverridesKey1,
+			Constraints: dynamicconfig.Constraints{Namespace: "soon"},
+			Value:       12,
+			ExpiresAt:   now.Add(time.Minute),
+		},
+	}, cln.ListExpiringOverrides(time.Minute))
+	s.Equal([]dynamicconfig.ExpiringOverride{
+		{
+			Key:         testListExpiringOverridesKey1,
+			Constraints: dynamicconfig.Constraints{Namespace: "soon"},
+			Value:       12,
+			ExpiresAt:   now.Add(time.Minute),
+		},
+		{
+			Key:       testListExpiringOverridesKey2,
+			Value:     true,
+			ExpiresAt: now.Add(30 * time.Minute),
+		},
+	}, cln.ListExpiringOverrides(time.Hour))
+	s.Len(cln.ListExpiringOverrides(3*time.Hour), 3)
+
+	// still listed within the clock skew tolerance, since the value is still in effect
+	timeSource.Update(now.Add(time.Minute + 3*time.Second))
+	s.Len(cln.ListExpiringOverrides(time.Hour), 2)
+	timeSource.Update(now.Add(time.Minute + 6*time.Second))
+	s.Len(cln.ListExpiringOverrides(time.Hour), 1)
+}
+
+func (s *collectionSuite) TestGetNamespaceMap() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetNamespaceMapKey, 10, "")
+	get := setting.GetNamespaceMap(s.cln)
+	s.Empty(get())
+
+	s.client[testGetNamespaceMapKey] = []dynamicconfig.ConstrainedValue{
+		{Value: 11},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns1"}, Value: 12},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns2"}, Value: 13},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns2"}, Value: 14},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns3", TaskQueueName: "tq"}, Value: 15},
+		{Constraints: dynamicconfig.Constraints{ShardID: 1}, Value: 16},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns4"}, Value: "not an int"},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns5"}, Value: 17, ExpiresAt: time.Now().Add(-time.Hour)},
+	}
+	s.Equal(map[string]int{"ns1": 12, "ns2": 13}, get())
+
+	// consistent with per-namespace resolution for the namespaces in the map
+	for namespace, value := range get() {
+		s.Equal(setting.Get(s.cln)(namespace), value)
+	}
+}
+
+func (s *collectionSuite) TestGetNamespaceMap_ConstrainedDefault() {
+	setting := dynamicconfig.NewNamespaceIntSettingWithConstrainedDefault(testGetNamespaceMapConstrainedDefaultKey, []dynamicconfig.TypedConstrainedValue[int]{
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns1"}, Value: 1},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns2"}, Value: 2},
+		{Value: 3},
+	}, "")
+	get := setting.GetNamespaceMap(s.cln)
+	s.Equal(map[string]int{"ns1": 1, "ns2": 2}, get())
+
+	s.client[testGetNamespaceMapConstrainedDefaultKey] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns2"}, Value: 20},
+		{Constraints: dynamicconfig.Constraints{Namespace: "ns3"}, Value: 30},
+	}
+	s.Equal(map[string]int{"ns1": 1, "ns2": 20, "ns3": 30}, get())
+}
+
+func (s *collectionSuite) TestEvaluateGroup() {
+	namespaceSetting := dynamicconfig.NewNamespaceIntSetting(testEvaluateGroupNamespaceKey, 10, "")
+	taskQueueSetting := dynamicconfig.NewTaskQueueIntSetting(testEvaluateGroupTaskQueueKey, 20, "")
+	globalSetting := dynamicconfig.NewGlobalIntSetting(testEvaluateGroupGlobalKey, 30, "")
+	s.client[testEvaluateGroupNamespaceKey] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: 11},
+		{Value: 12},
+	}
+	s.client[testEvaluateGroupTaskQueueKey] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace", TaskQueueName: "tq"}, Value: 21},
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: 22},
+	}
+	s.client[testEvaluateGroupGlobalKey] = 31
+
+	for _, namespace := range []string{"samples-namespace", "other-namespace"} {
+		r := s.cln.EvaluateGroup(
+			dynamicconfig.NamespaceFilter(namespace),
+			dynamicconfig.TaskQueueFilter("tq", enumspb.TASK_QUEUE_TYPE_WORKFLOW),
+		)
+		s.Equal(namespaceSetting.Get(s.cln)(namespace), namespaceSetting.GetInGroup(r))
+		s.Equal(taskQueueSetting.Get(s.cln)(namespace, "tq", enumspb.TASK_QUEUE_TYPE_WORKFLOW), taskQueueSetting.GetInGroup(r))
+		s.Equal(globalSetting.Get(s.cln)(), globalSetting.GetInGroup(r))
+	}
+
+	r := s.cln.EvaluateGroup(dynamicconfig.NamespaceFilter("samples-namespace"))
+	s.Equal(11, namespaceSetting.GetInGroup(r))
+	s.Equal(22, taskQueueSetting.GetInGroup(r))
+	s.Equal(31, globalSetting.GetInGroup(r))
+
+	// values are not cached, only the precedence
+	s.client[testEvaluateGroupNamespaceKey] = 13
+	s.Equal(13, namespaceSetting.GetInGroup(r))
+}
+
+func (s *collectionSuite) TestRequestOverrides() {
+	setting := dynamicconfig.NewNamespaceBoolSetting(testRequestOverrideKey, false, "")
+	notAllowedSetting := dynamicconfig.NewGlobalIntSetting(testRequestOverrideNotAllowedKey, 10, "")
+	s.client[testRequestOverrideKey] = []dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: false},
+	}
+	cln := dynamicconfig.NewCollection(s.client, log.NewNoopLogger())
+	cln.SetRequestOverrideAllowlist(testRequestOverrideKey)
+
+	s.Run("HeaderAbsent", func() {
+		ctx := dynamicconfig.ContextWithRequestOverridesFromHeaders(context.Background())
+		r := cln.EvaluateGroup(dynamicconfig.NamespaceFilter("samples-namespace")).WithRequestOverrides(ctx)
+		s.False(setting.GetInGroup(r))
+		s.Equal(10, notAllowedSetting.GetInGroup(r))
+	})
+
+	s.Run("HeaderPresent", func() {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			dynamicconfig.RequestOverrideHeaderName, "testRequestOverrideKey=true",
+			dynamicconfig.RequestOverrideHeaderName, "testRequestOverrideNotAllowedKey=20",
+			dynamicconfig.RequestOverrideHeaderName, "malformed",
+		))
+		ctx = dynamicconfig.ContextWithRequestOverridesFromHeaders(ctx)
+		r := cln.EvaluateGroup(dynamicconfig.NamespaceFilter("samples-namespace")).WithRequestOverrides(ctx)
+		// the override takes precedence over the namespace value
+		s.True(setting.GetInGroup(r))
+		// keys not in the allowlist can't be overridden
+		s.Equal(10, notAllowedSetting.GetInGroup(r))
+
+		// only for this request
+		s.False(setting.GetInGroup(cln.EvaluateGroup(dynamicconfig.NamespaceFilter("samples-namespace"))))
+		s.False(setting.Get(cln)("samples-namespace"))
+	})
+
+	s.Run("InvalidValue", func() {
+		ctx := dynamicconfig.ContextWithRequestOverrides(context.Background(), map[dynamicconfig.Key]any{
+			testRequestOverrideKey: "not a bool",
+		})
+		r := cln.EvaluateGroup(dynamicconfig.NamespaceFilter("samples-namespace")).WithRequestOverrides(ctx)
+		s.False(setting.GetInGroup(r))
+	})
+
+	s.Run("NoAllowlist", func() {
+		ctx := dynamicconfig.ContextWithRequestOverrides(context.Background(), map[dynamicconfig.Key]any{
+			testRequestOverrideKey: true,
+		})
+		r := s.cln.EvaluateGroup(dynamicconfig.NamespaceFilter("samples-namespace")).WithRequestOverrides(ctx)
+		s.False(setting.GetInGroup(r))
+	})
+}
+
+func BenchmarkEvaluateGroup(b *testing.B) {
+	settings := []dynamicconfig.NamespaceIntSetting{
+		dynamicconfig.BlobSizeLimitError,
+		dynamicconfig.BlobSizeLimitWarn,
+		dynamicconfig.MemoSizeLimitError,
+		dynamicconfig.MemoSizeLimitWarn,
+		dynamicconfig.NumPendingChildExecutionsLimitError,
+		dynamicconfig.NumPendingActivitiesLimitError,
+		dynamicconfig.NumPendingSignalsLimitError,
+		dynamicconfig.NumPendingCancelRequestsLimitError,
+		dynamicconfig.HistorySizeLimitError,
+		dynamicconfig.HistorySizeLimitWarn,
+		dynamicconfig.HistorySizeSuggestContinueAsNew,
+		dynamicconfig.HistoryCountLimitError,
+		dynamicconfig.HistoryCountLimitWarn,
+		dynamicconfig.HistoryCountSuggestContinueAsNew,
+		dynamicconfig.HistoryMaxPageSize,
+	}
+	client := dynamicconfig.StaticClient{
+		dynamicconfig.BlobSizeLimitError.Key(): []dynamicconfig.ConstrainedValue{
+			{Constraints: dynamicconfig.Constraints{Namespace: "my-namespace"}, Value: 1024},
+		},
+	}
+	cln := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+
+	b.Run("independent", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, setting := range settings {
+				_ = setting.Get(cln)("my-namespace")
+			}
+		}
+	})
+	b.Run("group", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r := cln.EvaluateGroup(dynamicconfig.NamespaceFilter("my-namespace"))
+			for _, setting := range settings {
+				_ = setting.GetInGroup(r)
+			}
+		}
+	})
+}
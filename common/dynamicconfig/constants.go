@@ -130,6 +130,24 @@ values in system search attributes.`,
 		true,
 		`EnableNamespaceNotActiveAutoForwarding whether enabling DC auto forwarding to active cluster
 for signal / start / signal with start API if namespace is not active`,
+	)
+	ReadRoutingClusterWeights = NewNamespaceTypedSettingWithConverter(
+		"system.readRoutingClusterWeights",
+		ConvertClusterReadWeights,
+		ClusterReadWeights(nil),
+		`ReadRoutingClusterWeights maps cluster names to the relative share of the reads of a global namespace
+that are routed to them, e.g. {"us-east": 80, "us-west": 20}. Reads of the same workflow are always routed
+to the same cluster. Clusters with a zero weight get no reads, and if no cluster has a positive weight,
+reads are served by the local cluster.`,
+	)
+	TargetNamespacesLabelSelector = NewGlobalTypedSettingWithConverter(
+		"system.targetNamespacesLabelSelector",
+		ConvertLabelSelector,
+		(*LabelSelector)(nil),
+		`TargetNamespacesLabelSelector is a Kubernetes-style label selector, e.g. "tier in (gold,silver), region=us",
+that operations targeting a subset of namespaces match against the data of each namespace. Supported
+requirements are "key=value", "key!=value", "key in (...)", "key notin (...)", "key" and "!key". An empty
+selector matches all namespaces. By default, and if the selector is malformed, no namespace is matched.`,
 	)
 	TransactionSizeLimit = NewGlobalIntSetting(
 		"system.transactionSizeLimit",
@@ -609,6 +627,14 @@ This config is EXPERIMENTAL and may be changed or removed in a later release.`,
 		2400,
 		`FrontendMaxNamespaceRPSPerInstance is workflow namespace rate limit per second`,
 	)
+	FrontendStartWorkflowExecutionShedPercentage = NewNamespaceTypedSettingWithConverter(
+		"frontend.startWorkflowExecutionShedPercentage",
+		ConvertShedPercentage,
+		ShedPercentage(0),
+		`FrontendStartWorkflowExecutionShedPercentage is the percentage of StartWorkflowExecution requests of a
+namespace that are rejected, from 0 to 100. It's an emergency lever to shed load, e.g. during an incident,
+and takes effect right away. Requests are picked at random. Values out of range are ignored.`,
+	)
 	FrontendMaxNamespaceBurstRatioPerInstance = NewNamespaceFloatSetting(
 		"frontend.namespaceBurstRatio",
 		2,
@@ -1317,6 +1343,91 @@ before joining membership after starting.`,
 		false,
 		`EnableHostLevelEventsCache controls if the events cache is host level`,
 	)
+	EventsCacheTopEntriesEnabled = NewGlobalBoolSetting(
+		"history.eventsCacheTopEntriesEnabled",
+		false,
+		`EventsCacheTopEntriesEnabled allows reporting the workflows with the most cached events in the events cache.
+It's off by default since computing the report scans the whole cache while holding its lock.`,
+	)
+	ImportBypassEventsCache = NewNamespaceBoolSetting(
+		"history.importBypassEventsCache",
+		false,
+		`ImportBypassEventsCache stops workflow history import from writing imported events to the events cache,
+so that a bulk import doesn't evict entries used by live traffic. Only applies to workflows that don't exist yet.`,
+	)
+	ImportLeaseTTL = NewGlobalDurationSetting(
+		"history.importLeaseTTL",
+		time.Minute,
+		`ImportLeaseTTL is how long a workflow history import holds its per-workflow lease after its last call.
+A concurrent import of the same workflow fails while the lease is held. The lease expires so that an
+abandoned import doesn't block retries forever.`,
+	)
+	ImportSearchAttributeNameMapping = NewNamespaceTypedSetting(
+		"history.importSearchAttributeNameMapping",
+		map[string]string(nil),
+		`ImportSearchAttributeNameMapping maps custom search attribute field names used by the source cluster
+of a workflow history import to their aliases. Imported events have their search attributes renamed to
+the field names of the same aliases in this cluster, and custom search attributes without a mapping are
+dropped. An empty mapping imports search attributes unchanged.`,
+	)
+	ImportRecordProvenance = NewNamespaceBoolSetting(
+		"history.importRecordProvenance",
+		false,
+		`ImportRecordProvenance makes workflow history import record where a workflow was imported from, and when,
+in the memo of the imported workflow, so that imported workflows can be told apart from replicated ones.`,
+	)
+	ImportMaxBranches = NewNamespaceIntSetting(
+		"history.importMaxBranches",
+		0,
+		`ImportMaxBranches is the max number of version history branches, besides the current one, that workflow
+history import keeps for a workflow, e.g. one that was reset many times. Once over the limit, the least recent
+branches are dropped, with a warning. If set to zero, all branches are kept.`,
+	)
+	ImportRebaseTimers = NewNamespaceBoolSetting(
+		"history.importRebaseTimers",
+		false,
+		`ImportRebaseTimers makes workflow history import move the deadlines of a workflow's pending timers and
+activities, and of the workflow itself, forward by the time that passed between its last imported event and
+the import, so that they fire as they would have in the source cluster instead of all at once. Imported events
+keep their original timestamps either way. If off, deadlines are kept and the ones already past fire right
+after the import.`,
+	)
+	ImportChildWorkflowLinks = NewNamespaceStringSetting(
+		"history.importChildWorkflowLinks",
+		"",
+		`ImportChildWorkflowLinks is what workflow history import does about the started, pending children of
+an imported workflow that don't exist in this cluster. With "import", they are imported from the source cluster
+too, and so are their own children, up to history.importChildWorkflowMaxDepth levels down; children past that
+depth are marked unresolved. With "unresolved", they are only marked unresolved: they're listed in the memo of
+the imported workflow, which can then be found and closed instead of waiting on them forever. If empty, links
+are imported as they are.`,
+	)
+	ImportChildWorkflowMaxDepth = NewNamespaceIntSetting(
+		"history.importChildWorkflowMaxDepth",
+		3,
+		`ImportChildWorkflowMaxDepth is how many levels of children workflow history import imports along with a
+workflow if history.importChildWorkflowLinks is "import".`,
+	)
+	ImportThrottleLatencyThreshold = NewGlobalDurationSetting(
+		"history.importThrottleLatencyThreshold",
+		500*time.Millisecond,
+		`ImportThrottleLatencyThreshold is the latency of the import calls to a target shard over which a batch
+workflow import backs off from that shard, so that it doesn't starve the shard's live traffic. The backoff
+is halved again for each import that stays under it. If set to zero, latency is not checked.`,
+	)
+	ImportThrottleShardExecutionsThreshold = NewGlobalIntSetting(
+		"history.importThrottleShardExecutionsThreshold",
+		0,
+		`ImportThrottleShardExecutionsThreshold is the number of workflow executions of a target shard, as estimated
+by shard.Context.EstimateLoad, over which a batch workflow import backs off from that shard. If set to zero,
+the load estimate is not checked, which avoids scanning the executions of the target shards.`,
+	)
+	ImportThrottleMaxBackoff = NewGlobalDurationSetting(
+		"history.importThrottleMaxBackoff",
+		5*time.Second,
+		`ImportThrottleMaxBackoff is the max time a batch workflow import waits before each import to a target shard
+it backs off from, see history.importThrottleLatencyThreshold.`,
+	)
 	AcquireShardInterval = NewGlobalDurationSetting(
 		"history.acquireShardInterval",
 		time.Minute,
@@ -1340,6 +1451,13 @@ checks while a shard is lingering.`,
 will temporarily delay closing shards after a membership update, awaiting a
 shard ownership lost error from persistence. If set to zero, shards will not delay closing.
 Do NOT use non-zero value with persistence layers that are missing AssertShardOwnership support.`,
+	)
+	ShardOwnershipAssertionFreshness = NewGlobalDurationSetting(
+		"history.shardOwnershipAssertionFreshness",
+		0,
+		`ShardOwnershipAssertionFreshness is how long a successful shard ownership check is reused
+for further checks of the same shard, e.g. 1s. Failed checks are never reused, and losing the shard
+invalidates the last successful one right away. If set to zero, every check goes to persistence.`,
 	)
 	HistoryClientOwnershipCachingEnabled = NewGlobalBoolSetting(
 		"history.clientOwnershipCachingEnabled",
@@ -1359,6 +1477,119 @@ to this require a restart to take effect.`,
 		5*time.Second*debug.TimeoutMultiplier,
 		`ShardIOTimeout sets the timeout for persistence operations in the shard context`,
 	)
+	ShardLivenessProbeTimeout = NewGlobalDurationSetting(
+		"history.shardLivenessProbeTimeout",
+		0,
+		`ShardLivenessProbeTimeout is how long the shard controller waits to acquire a shard's
+internal lock when probing the shard for liveness. A shard whose lock can't be acquired
+within this timeout is considered wedged and is unloaded. If set to zero, shards are not probed.`,
+	)
+	ShardLockCaptureHolderStack = NewGlobalBoolSetting(
+		"history.shardLockCaptureHolderStack",
+		false,
+		`ShardLockCaptureHolderStack records the stack of each caller that acquires the shard lock,
+so that it can be included in liveness probe reports. This is expensive and should only be
+enabled while debugging a stuck shard.`,
+	)
+	ShardLockWaitMetricsEnabled = NewGlobalBoolSetting(
+		"history.shardLockWaitMetricsEnabled",
+		false,
+		`ShardLockWaitMetricsEnabled emits how long each acquire of the shard lock waited, tagged with the shard ID,
+to find shards whose lock is contended. It's off by default because of the per-shard metric cardinality and
+the cost of recording every acquire. Changes take effect within 10 seconds.`,
+	)
+	ShardTaskIDReservationTimeout = NewGlobalDurationSetting(
+		"history.shardTaskIDReservationTimeout",
+		time.Minute,
+		`ShardTaskIDReservationTimeout is how long the immediate queues of a shard are held below a task ID range
+reserved for an external writer, if the writer doesn't release the reservation before that.`,
+	)
+	ShardReadRPS = NewNamespaceIntSetting(
+		"history.shardReadRPS",
+		0,
+		`ShardReadRPS is the max rate of workflow execution reads a single shard serves for a namespace
+on behalf of API callers. Internal reads are not limited. Reads over the limit fail with a retryable ResourceExhausted error. If set to zero, reads are not limited.`,
+	)
+	ShardBatchReadConcurrency = NewGlobalIntSetting(
+		"history.shardBatchReadConcurrency",
+		10,
+		`ShardBatchReadConcurrency is the max number of workflow execution reads a single batch read of a
+shard, e.g. by a consistency scanner, has in flight at once.`,
+	)
+	ShardQueueStateRestoreEnabled = NewGlobalBoolSetting(
+		"history.shardQueueStateRestoreEnabled",
+		false,
+		`ShardQueueStateRestoreEnabled allows operators to overwrite a shard's queue and replication state with
+a snapshot previously exported from the same shard, e.g. as part of disaster recovery. It should only be
+enabled for the duration of the restore.`,
+	)
+	ShardCurrentExecutionCacheSize = NewGlobalIntSetting(
+		"history.shardCurrentExecutionCacheSize",
+		1000,
+		`ShardCurrentExecutionCacheSize is the max number of current workflow executions cached per shard
+for eventually consistent reads. If set to zero, eventually consistent reads always go to persistence.
+Changes only take effect when the shard is reloaded.`,
+	)
+	ShardCurrentExecutionCacheTTL = NewGlobalDurationSetting(
+		"history.shardCurrentExecutionCacheTTL",
+		5*time.Second,
+		`ShardCurrentExecutionCacheTTL is how long a cached current workflow execution can be served to
+eventually consistent reads. Changes only take effect when the shard is reloaded.`,
+	)
+	ShardReplicationTaskAuditSize = NewGlobalIntSetting(
+		"history.shardReplicationTaskAuditSize",
+		100,
+		`ShardReplicationTaskAuditSize is the number of most recently applied replication tasks each shard
+keeps for auditing, see shard.Context.RecentReplicationTasks. If set to zero, nothing is kept.`,
+	)
+	ShardLoadEstimateMaxExecutions = NewGlobalIntSetting(
+		"history.shardLoadEstimateMaxExecutions",
+		100000,
+		`ShardLoadEstimateMaxExecutions is the max number of workflow executions shard.Context.EstimateLoad
+scans. Shards with more executions get a partial estimate.`,
+	)
+	ShardLoadEstimateTTL = NewGlobalDurationSetting(
+		"history.shardLoadEstimateTTL",
+		10*time.Minute,
+		`ShardLoadEstimateTTL is how long shard.Context.EstimateLoad reuses the last estimate of a shard
+before scanning its executions again.`,
+	)
+	ShardTaskGenerationHeatmapTopK = NewGlobalIntSetting(
+		"history.shardTaskGenerationHeatmapTopK",
+		50,
+		`ShardTaskGenerationHeatmapTopK is the number of workflow types shard.Context.TaskGenerationByWorkflowType
+reports, and about how many each shard keeps counts of per minute. If set to zero, nothing is counted.`,
+	)
+	ShardTaskGenerationHeatmapRetention = NewGlobalDurationSetting(
+		"history.shardTaskGenerationHeatmapRetention",
+		time.Hour,
+		`ShardTaskGenerationHeatmapRetention is how long each shard keeps the counts of tasks generated per
+workflow type, i.e. the longest window shard.Context.TaskGenerationByWorkflowType can report on.`,
+	)
+	ShardQueueDrainRateWindow = NewGlobalDurationSetting(
+		"history.shardQueueDrainRateWindow",
+		5*time.Minute,
+		`ShardQueueDrainRateWindow is the window over which shard.Context.EstimateQueueDrainTime measures the
+rate a queue of a shard completes tasks at.`,
+	)
+	ShardRepairCheckpointTTL = NewGlobalDurationSetting(
+		"history.shardRepairCheckpointTTL",
+		24*time.Hour,
+		`ShardRepairCheckpointTTL is how long a checkpoint taken by shard.Context.CheckpointForRepair can be
+rolled back to. Expired checkpoints are dropped the next time a checkpoint is taken.`,
+	)
+	ShardRepairCheckpointMaxCount = NewGlobalIntSetting(
+		"history.shardRepairCheckpointMaxCount",
+		5,
+		`ShardRepairCheckpointMaxCount is the max number of repair checkpoints kept per shard. They're persisted
+as part of the shard info, so the oldest ones are dropped when a new checkpoint would exceed it.`,
+	)
+	ShardAcquisitionMetricsShardIDSampling = NewGlobalIntSetting(
+		"history.shardAcquisitionMetricsShardIDSampling",
+		100,
+		`ShardAcquisitionMetricsShardIDSampling limits the cardinality of the shard acquisition metrics: only
+one in every this many shards is tagged with its shard ID. If set to zero, no shard is tagged with its ID.`,
+	)
 	StandbyClusterDelay = NewGlobalDurationSetting(
 		"history.standbyClusterDelay",
 		5*time.Minute,
@@ -1404,6 +1635,13 @@ for the entire queue but only trigger a queue action to unload tasks. Ideally th
 limit should not be hit and task unloading should happen once critical count is exceeded. But
 since queue action is async, we need this hard limit.`,
 	)
+	QueueStateCompactionThreshold = NewGlobalIntSetting(
+		"history.queueStateCompactionThreshold",
+		100,
+		`QueueStateCompactionThreshold is the number of slice scopes in a persisted queue state above which
+adjacent or overlapping scopes with the same predicate are merged before the state is persisted.
+If set to zero, queue states are never compacted.`,
+	)
 
 	TaskSchedulerEnableRateLimiter = NewGlobalBoolSetting(
 		"history.taskSchedulerEnableRateLimiter",
@@ -0,0 +1,248 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"gopkg.in/yaml.v3"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+var _ Client = (*consulClient)(nil)
+
+const (
+	defaultConsulWaitTime = 5 * time.Minute
+	consulRetryInterval   = 5 * time.Second
+)
+
+type (
+	// ConsulClientConfig is the config for the Consul KV based dynamic config client.
+	// Every key directly under Prefix holds the values of the dynamic config key with the same
+	// name, in the same format as a single key of the dynamic config file, e.g.:
+	//
+	//	- value: 100
+	//	  constraints:
+	//	    namespace: "my-namespace"
+	//	- value: 50
+	ConsulClientConfig struct {
+		// Address, Datacenter and Token are passed to the Consul API client, which falls back
+		// to its own defaults and environment variables when they are empty.
+		Address    string `yaml:"address"`
+		Datacenter string `yaml:"datacenter"`
+		Token      string `yaml:"token"`
+		// Prefix is the KV prefix holding the dynamic config keys.
+		Prefix string `yaml:"prefix"`
+		// WaitTime is the maximum duration of a single blocking query. Defaults to 5 minutes.
+		WaitTime time.Duration `yaml:"waitTime"`
+	}
+
+	// ConsulKV is the part of the Consul KV API used by the Consul based client. It is
+	// implemented by *api.KV.
+	ConsulKV interface {
+		List(prefix string, q *api.QueryOptions) (api.KVPairs, *api.QueryMeta, error)
+	}
+
+	consulClient struct {
+		values    atomic.Value // configValueMap
+		logger    log.Logger
+		kv        ConsulKV
+		prefix    string
+		waitTime  time.Duration
+		lastIndex uint64
+		doneCh    <-chan interface{}
+	}
+)
+
+// NewConsulClient creates a dynamic config client that reads values from a Consul KV prefix
+// and watches it for changes using blocking queries.
+func NewConsulClient(config *ConsulClientConfig, logger log.Logger, doneCh <-chan interface{}) (*consulClient, error) {
+	if config == nil {
+		return nil, errors.New("configuration for dynamic config client is nil")
+	}
+	apiConfig := api.DefaultConfig()
+	if config.Address != "" {
+		apiConfig.Address = config.Address
+	}
+	if config.Datacenter != "" {
+		apiConfig.Datacenter = config.Datacenter
+	}
+	if config.Token != "" {
+		apiConfig.Token = config.Token
+	}
+	consul, err := api.NewClient(apiConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create consul client: %w", err)
+	}
+	return NewConsulClientWithKV(consul.KV(), config, logger, doneCh)
+}
+
+func NewConsulClientWithKV(kv ConsulKV, config *ConsulClientConfig, logger log.Logger, doneCh <-chan interface{}) (*consulClient, error) {
+	if config == nil {
+		return nil, errors.New("configuration for dynamic config client is nil")
+	}
+	prefix := strings.TrimPrefix(config.Prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	waitTime := config.WaitTime
+	if waitTime <= 0 {
+		waitTime = defaultConsulWaitTime
+	}
+	client := &consulClient{
+		logger:   logger,
+		kv:       kv,
+		prefix:   prefix,
+		waitTime: waitTime,
+		doneCh:   doneCh,
+	}
+
+	err := client.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (cc *consulClient) GetValue(key Key) []ConstrainedValue {
+	values := cc.values.Load().(configValueMap)
+	return values[strings.ToLower(key.String())]
+}
+
+func (cc *consulClient) init() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := cc.update(ctx); err != nil {
+		cancel()
+		return fmt.Errorf("unable to read dynamic config: %w", err)
+	}
+
+	go func() {
+		<-cc.doneCh
+		cancel()
+	}()
+	go cc.watch(ctx)
+
+	return nil
+}
+
+// watch keeps issuing blocking queries until ctx is canceled, so that changes are picked up
+// as soon as Consul reports them.
+func (cc *consulClient) watch(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := cc.update(ctx)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+		cc.logger.Error("Unable to update dynamic config.", tag.Error(err))
+		select {
+		case <-time.After(consulRetryInterval):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// update lists the prefix, blocking until the KV index moves past the last seen index or the
+// wait time elapses, and applies the listed values. A key whose value can't be parsed keeps
+// its previous values and does not affect any other key.
+func (cc *consulClient) update(ctx context.Context) error {
+	queryOptions := &api.QueryOptions{
+		WaitIndex: cc.lastIndex,
+		WaitTime:  cc.waitTime,
+	}
+	pairs, meta, err := cc.kv.List(cc.prefix, queryOptions.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("dynamic config consul prefix: %q: %w", cc.prefix, err)
+	}
+	oldValues, _ := cc.values.Load().(configValueMap)
+	if oldValues != nil && meta.LastIndex == cc.lastIndex {
+		// blocking query timed out without changes
+		return nil
+	}
+	if meta.LastIndex < cc.lastIndex {
+		// the index went backwards, e.g. after a snapshot restore, so start over
+		cc.lastIndex = 0
+	} else {
+		cc.lastIndex = meta.LastIndex
+	}
+
+	newValues := make(configValueMap, len(pairs))
+	for _, pair := range pairs {
+		key := strings.TrimPrefix(pair.Key, cc.prefix)
+		if key == "" || strings.HasSuffix(key, "/") {
+			// folder
+			continue
+		}
+		if strings.Contains(key, "/") {
+			cc.logger.Warn("dynamic config warning", tag.Error(fmt.Errorf("ignoring nested consul key %q", pair.Key)))
+			continue
+		}
+		lowerKey := strings.ToLower(key)
+
+		cvs, ok := decodeKeyValues(cc.logger, key, pair.Value)
+		if !ok {
+			if prev, ok := oldValues[lowerKey]; ok {
+				newValues[lowerKey] = prev
+			}
+			continue
+		}
+		newValues[lowerKey] = cvs
+	}
+
+	cc.values.Store(newValues)
+	logDiff(cc.logger, oldValues, newValues)
+	cc.logger.Info("Updated dynamic config")
+
+	return nil
+}
+
+// decodeKeyValues decodes the values of a single dynamic config key stored in a key-value store,
+// in the same format as a single key of the dynamic config file. Errors and warnings are logged,
+// and false is returned if there were errors.
+func decodeKeyValues(logger log.Logger, key string, data []byte) ([]ConstrainedValue, bool) {
+	lr := &LoadResult{}
+	var yamlCV []yamlConstrainedValue
+	var cvs []ConstrainedValue
+	if err := yaml.Unmarshal(data, &yamlCV); err != nil {
+		lr.errorf("key %q: decode error: %w", key, err)
+	} else {
+		cvs = convertYamlConstrainedValues(key, yamlCV, lr)
+	}
+	for _, e := range lr.Errors {
+		logger.Warn("dynamic config error", tag.Error(e))
+	}
+	for _, w := range lr.Warnings {
+		logger.Warn("dynamic config warning", tag.Error(w))
+	}
+	return cvs, len(lr.Errors) == 0
+}
@@ -0,0 +1,197 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig_test
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	consulClientSuite struct {
+		suite.Suite
+		*require.Assertions
+		kv     *fakeConsulKV
+		doneCh chan interface{}
+	}
+
+	// fakeConsulKV implements blocking List queries the way Consul does: a query with a
+	// WaitIndex equal to the current index blocks until the next write or the wait time.
+	fakeConsulKV struct {
+		sync.Mutex
+		pairs   map[string]string
+		index   uint64
+		changed chan struct{}
+	}
+)
+
+func TestConsulClientSuite(t *testing.T) {
+	s := new(consulClientSuite)
+	suite.Run(t, s)
+}
+
+func (s *consulClientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	dynamicconfig.ResetRegistryForTest()
+	s.kv = &fakeConsulKV{
+		pairs:   make(map[string]string),
+		index:   1,
+		changed: make(chan struct{}),
+	}
+	s.doneCh = make(chan interface{})
+}
+
+func (s *consulClientSuite) TearDownTest() {
+	close(s.doneCh)
+}
+
+func (s *consulClientSuite) newClient() dynamicconfig.Client {
+	client, err := dynamicconfig.NewConsulClientWithKV(s.kv, &dynamicconfig.ConsulClientConfig{
+		Prefix:   "temporal/dynamicconfig",
+		WaitTime: time.Second,
+	}, log.NewNoopLogger(), s.doneCh)
+	s.NoError(err)
+	return client
+}
+
+func (s *consulClientSuite) TestGetValue() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetIntPropertyKey, 10, "")
+	s.kv.put("temporal/dynamicconfig/", "")
+	s.kv.put("temporal/dynamicconfig/"+testGetIntPropertyKey, `
+- value: 100
+  constraints:
+    namespace: samples-namespace
+- value: 50
+`)
+	s.kv.put("temporal/dynamicconfig/"+testGetStringPropertyKey, `
+- value: abc
+  expiresAt: 2030-01-02T03:04:05Z
+`)
+	s.kv.put("temporal/dynamicconfig/nested/"+testGetBoolPropertyKey, "- value: true")
+	s.kv.put("temporal/other/"+testGetBoolPropertyKey, "- value: true")
+
+	client := s.newClient()
+	s.ElementsMatch([]dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: 100},
+		{Value: 50},
+	}, client.GetValue(testGetIntPropertyKey))
+	s.Nil(client.GetValue(testGetBoolPropertyKey))
+	s.Equal([]dynamicconfig.ConstrainedValue{
+		{Value: "abc", ExpiresAt: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}, client.GetValue(testGetStringPropertyKey))
+
+	collection := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	s.Equal(100, setting.Get(collection)("samples-namespace"))
+	s.Equal(50, setting.Get(collection)("other-namespace"))
+}
+
+func (s *consulClientSuite) TestWatch() {
+	s.kv.put("temporal/dynamicconfig/"+testGetIntPropertyKey, "- value: 100")
+	client := s.newClient()
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+
+	s.kv.put("temporal/dynamicconfig/"+testGetIntPropertyKey, "- value: 200")
+	s.kv.put("temporal/dynamicconfig/"+testGetBoolPropertyKey, "- value: true")
+	s.Eventually(func() bool {
+		return len(client.GetValue(testGetBoolPropertyKey)) == 1 &&
+			client.GetValue(testGetIntPropertyKey)[0].Value == 200
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.kv.delete("temporal/dynamicconfig/" + testGetIntPropertyKey)
+	s.Eventually(func() bool {
+		return client.GetValue(testGetIntPropertyKey) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *consulClientSuite) TestInvalidKeyIsIsolated() {
+	s.kv.put("temporal/dynamicconfig/"+testGetIntPropertyKey, "- value: 100")
+	s.kv.put("temporal/dynamicconfig/"+testGetBoolPropertyKey, "not: [valid")
+	client := s.newClient()
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+	s.Nil(client.GetValue(testGetBoolPropertyKey))
+
+	// a key that becomes invalid keeps its previous values, other keys are still updated
+	s.kv.put("temporal/dynamicconfig/"+testGetIntPropertyKey, "- value: [200")
+	s.kv.put("temporal/dynamicconfig/"+testGetStringPropertyKey, "- value: abc")
+	s.Eventually(func() bool {
+		return len(client.GetValue(testGetStringPropertyKey)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+}
+
+func (kv *fakeConsulKV) List(prefix string, q *api.QueryOptions) (api.KVPairs, *api.QueryMeta, error) {
+	kv.Lock()
+	if q.WaitIndex == kv.index {
+		changed := kv.changed
+		kv.Unlock()
+		select {
+		case <-changed:
+		case <-time.After(q.WaitTime):
+		case <-q.Context().Done():
+			return nil, nil, q.Context().Err()
+		}
+		kv.Lock()
+	}
+	defer kv.Unlock()
+
+	var pairs api.KVPairs
+	for key, value := range kv.pairs {
+		if strings.HasPrefix(key, prefix) {
+			pairs = append(pairs, &api.KVPair{Key: key, Value: []byte(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	return pairs, &api.QueryMeta{LastIndex: kv.index}, nil
+}
+
+func (kv *fakeConsulKV) put(key string, value string) {
+	kv.Lock()
+	defer kv.Unlock()
+	kv.pairs[key] = value
+	kv.notifyLocked()
+}
+
+func (kv *fakeConsulKV) delete(key string) {
+	kv.Lock()
+	defer kv.Unlock()
+	delete(kv.pairs, key)
+	kv.notifyLocked()
+}
+
+func (kv *fakeConsulKV) notifyLocked() {
+	kv.index++
+	close(kv.changed)
+	kv.changed = make(chan struct{})
+}
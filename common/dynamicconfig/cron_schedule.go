@@ -0,0 +1,92 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// CronSchedule is a parsed cron expression in the standard five-field format, optionally prefixed
+// with a time zone, e.g. "CRON_TZ=America/New_York 0 3 * * *". The zero value never fires.
+type CronSchedule struct {
+	spec     string
+	schedule cron.Schedule
+}
+
+// ParseCronSchedule parses a cron expression. An empty spec is valid and never fires. It's an
+// error if the expression can't be parsed or never fires, e.g. "0 0 30 2 *".
+func ParseCronSchedule(spec string) (CronSchedule, error) {
+	if spec == "" {
+		return CronSchedule{}, nil
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return CronSchedule{}, fmt.Errorf("invalid cron schedule %q: %w", spec, err)
+	}
+	if schedule.Next(time.Now().UTC()).IsZero() {
+		return CronSchedule{}, fmt.Errorf("invalid cron schedule %q: no time satisfies the schedule", spec)
+	}
+	return CronSchedule{spec: spec, schedule: schedule}, nil
+}
+
+// MustParseCronSchedule is ParseCronSchedule for setting defaults. It panics on error.
+func MustParseCronSchedule(spec string) CronSchedule {
+	s, err := ParseCronSchedule(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Next returns the first time the schedule fires after the given time, or the zero time if it
+// never does.
+func (s CronSchedule) Next(after time.Time) time.Time {
+	if s.schedule == nil {
+		return time.Time{}
+	}
+	return s.schedule.Next(after)
+}
+
+// String returns the cron expression the schedule was parsed from.
+func (s CronSchedule) String() string {
+	return s.spec
+}
+
+// ConvertCronSchedule can be used as a conversion function for New*TypedSettingWithConverter
+// with a CronSchedule type. The value from dynamic config must be a cron expression string.
+// Invalid expressions fall back to the setting's default.
+func ConvertCronSchedule(v any) (CronSchedule, error) {
+	switch v := v.(type) {
+	case CronSchedule:
+		return v, nil
+	case string:
+		return ParseCronSchedule(v)
+	}
+	return CronSchedule{}, errors.New("value type is not string")
+}
@@ -0,0 +1,88 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type (
+	// DisableableDuration is a duration setting value that can also be turned off, e.g. an interval
+	// of a periodic job. Zero is a valid duration and doesn't mean off: use NeverDuration for that.
+	DisableableDuration time.Duration
+
+	// DisableableDurationPropertyFn returns a DisableableDuration that is global.
+	DisableableDurationPropertyFn = TypedPropertyFn[DisableableDuration]
+)
+
+// NeverDuration is the DisableableDuration that is turned off. In dynamic config it can be written
+// as "off", "disabled", "never" or -1.
+const NeverDuration DisableableDuration = -1
+
+// IsEnabled returns false if d is NeverDuration.
+func (d DisableableDuration) IsEnabled() bool {
+	return d != NeverDuration
+}
+
+// Duration returns d as a time.Duration. It must only be called if d is enabled.
+func (d DisableableDuration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d DisableableDuration) String() string {
+	if !d.IsEnabled() {
+		return "off"
+	}
+	return d.Duration().String()
+}
+
+// ConvertDisableableDuration can be used as a conversion function for
+// New*TypedSettingWithConverter with a DisableableDuration type. The value from dynamic config
+// can be anything a duration setting accepts, or one of the forms of NeverDuration. Other negative
+// durations are invalid and fall back to the setting's default.
+func ConvertDisableableDuration(v any) (DisableableDuration, error) {
+	switch v := v.(type) {
+	case DisableableDuration:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "off", "disabled", "never", "-1":
+			return NeverDuration, nil
+		}
+	}
+	if i, err := convertInt(v); err == nil && i == -1 {
+		return NeverDuration, nil
+	}
+	d, err := convertDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("negative duration, use \"off\" to disable")
+	}
+	return DisableableDuration(d), nil
+}
@@ -0,0 +1,103 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+)
+
+type (
+	environmentClient struct {
+		client      Client
+		environment string
+	}
+)
+
+// NewEnvironmentClient returns a Client that resolves the Environment constraint of the values
+// returned by client for the deployment environment the server was started in. Values for this
+// environment take precedence over values with otherwise the same constraints but no
+// environment, and values for other environments are dropped. This lets a config file shared
+// between environments override some keys per environment.
+//
+// If environment is empty, only values without an Environment constraint are used.
+func NewEnvironmentClient(client Client, environment string) Client {
+	return &environmentClient{
+		client:      client,
+		environment: environment,
+	}
+}
+
+func (c *environmentClient) GetValue(key Key) []ConstrainedValue {
+	return resolveEnvironment(c.environment, c.client.GetValue(key), func(cv *ConstrainedValue) *Constraints {
+		return &cv.Constraints
+	})
+}
+
+func (c *environmentClient) GetValueWithContext(ctx context.Context, key Key) []ConstrainedValue {
+	return resolveEnvironment(c.environment, getClientValue(ctx, c.client, key), func(cv *ConstrainedValue) *Constraints {
+		return &cv.Constraints
+	})
+}
+
+func (c *environmentClient) traceValue(key Key) []TracedValue {
+	return resolveEnvironment(c.environment, traceClientValue(c.client, key), func(tv *TracedValue) *Constraints {
+		return &tv.Constraints
+	})
+}
+
+func resolveEnvironment[V any](environment string, vs []V, constraints func(*V) *Constraints) []V {
+	hasEnvironment := false
+	for i := range vs {
+		if constraints(&vs[i]).Environment != "" {
+			hasEnvironment = true
+			break
+		}
+	}
+	if !hasEnvironment {
+		return vs
+	}
+
+	// The environment constraint is cleared so that the Collection matches these values like the
+	// ones without an environment. They are returned first, and the Collection uses the first of
+	// several values with the same constraints.
+	result := make([]V, 0, len(vs))
+	for _, v := range vs {
+		if cons := constraints(&v); environment != "" && cons.Environment == environment {
+			cons.Environment = ""
+			result = append(result, v)
+		}
+	}
+	for _, v := range vs {
+		if constraints(&v).Environment == "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Reload reloads the wrapped client, if supported.
+func (c *environmentClient) Reload() error {
+	return reloadClients(c.client)
+}
@@ -0,0 +1,273 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+var _ Client = (*etcdClient)(nil)
+
+const (
+	defaultEtcdDialTimeout = 5 * time.Second
+	etcdRetryInterval      = 5 * time.Second
+)
+
+type (
+	// EtcdClientConfig is the config for the etcd based dynamic config client. Every key
+	// directly under Prefix holds the values of the dynamic config key with the same name, in
+	// the same format as a single key of the dynamic config file, see ConsulClientConfig.
+	EtcdClientConfig struct {
+		Endpoints []string `yaml:"endpoints"`
+		Username  string   `yaml:"username"`
+		Password  string   `yaml:"password"`
+		// Prefix is the key prefix holding the dynamic config keys.
+		Prefix string `yaml:"prefix"`
+		// DialTimeout is the timeout for connecting to etcd. Defaults to 5 seconds.
+		DialTimeout time.Duration `yaml:"dialTimeout"`
+	}
+
+	// EtcdKV is the part of the etcd API used by the etcd based client. It is implemented by
+	// *clientv3.Client.
+	EtcdKV interface {
+		Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+		Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+	}
+
+	etcdClient struct {
+		values atomic.Value // configValueMap
+		logger log.Logger
+		kv     EtcdKV
+		prefix string
+		doneCh <-chan interface{}
+	}
+)
+
+// NewEtcdClient creates a dynamic config client that reads values from an etcd prefix and
+// watches it for changes.
+func NewEtcdClient(config *EtcdClientConfig, logger log.Logger, doneCh <-chan interface{}) (*etcdClient, error) {
+	if config == nil {
+		return nil, errors.New("configuration for dynamic config client is nil")
+	}
+	dialTimeout := config.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultEtcdDialTimeout
+	}
+	etcd, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		Username:    config.Username,
+		Password:    config.Password,
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create etcd client: %w", err)
+	}
+	client, err := NewEtcdClientWithKV(etcd, config, logger, doneCh)
+	if err != nil {
+		_ = etcd.Close()
+		return nil, err
+	}
+	go func() {
+		<-doneCh
+		_ = etcd.Close()
+	}()
+	return client, nil
+}
+
+func NewEtcdClientWithKV(kv EtcdKV, config *EtcdClientConfig, logger log.Logger, doneCh <-chan interface{}) (*etcdClient, error) {
+	if config == nil {
+		return nil, errors.New("configuration for dynamic config client is nil")
+	}
+	prefix := config.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	client := &etcdClient{
+		logger: logger,
+		kv:     kv,
+		prefix: prefix,
+		doneCh: doneCh,
+	}
+
+	err := client.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (ec *etcdClient) GetValue(key Key) []ConstrainedValue {
+	values := ec.values.Load().(configValueMap)
+	return values[strings.ToLower(key.String())]
+}
+
+func (ec *etcdClient) init() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	revision, err := ec.load(ctx)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("unable to read dynamic config: %w", err)
+	}
+
+	go func() {
+		<-ec.doneCh
+		cancel()
+	}()
+	go ec.watch(ctx, revision)
+
+	return nil
+}
+
+// watch applies the changes of the prefix after revision until ctx is canceled. The watch
+// requires a leader, so that it's closed rather than left hanging when the etcd member it's
+// connected to loses its leader. Whenever the watch ends, e.g. because of that or because the
+// revision it's watching from was compacted, the prefix is read again and a new watch is started
+// from the revision read.
+func (ec *etcdClient) watch(ctx context.Context, revision int64) {
+	for ctx.Err() == nil {
+		err := ec.watchFrom(ctx, revision)
+		if ctx.Err() != nil {
+			return
+		}
+		ec.logger.Warn("Dynamic config etcd watch ended, reloading.", tag.Error(err))
+		for {
+			select {
+			case <-time.After(etcdRetryInterval):
+			case <-ctx.Done():
+				return
+			}
+			if revision, err = ec.load(ctx); err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			ec.logger.Error("Unable to update dynamic config.", tag.Error(err))
+		}
+	}
+}
+
+func (ec *etcdClient) watchFrom(ctx context.Context, revision int64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	watchCh := ec.kv.Watch(clientv3.WithRequireLeader(ctx), ec.prefix, clientv3.WithPrefix(), clientv3.WithRev(revision+1))
+	for resp := range watchCh {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		if len(resp.Events) == 0 {
+			continue
+		}
+		ec.apply(resp.Events)
+	}
+	return errors.New("watch channel closed")
+}
+
+// load reads all keys of the prefix and replaces the current values with them. A key whose
+// value can't be parsed keeps its previous values and does not affect any other key.
+func (ec *etcdClient) load(ctx context.Context) (int64, error) {
+	resp, err := ec.kv.Get(ctx, ec.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("dynamic config etcd prefix: %q: %w", ec.prefix, err)
+	}
+
+	oldValues, _ := ec.values.Load().(configValueMap)
+	newValues := make(configValueMap, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key, ok := ec.configKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		lowerKey := strings.ToLower(key)
+		cvs, ok := decodeKeyValues(ec.logger, key, kv.Value)
+		if !ok {
+			if prev, ok := oldValues[lowerKey]; ok {
+				newValues[lowerKey] = prev
+			}
+			continue
+		}
+		newValues[lowerKey] = cvs
+	}
+
+	ec.values.Store(newValues)
+	logDiff(ec.logger, oldValues, newValues)
+	ec.logger.Info("Updated dynamic config")
+
+	return resp.Header.GetRevision(), nil
+}
+
+// apply applies watched changes to the current values. Keys that are deleted, including by the
+// expiry of the lease they're attached to, lose their values. A key whose new value can't be
+// parsed keeps its previous values.
+func (ec *etcdClient) apply(events []*clientv3.Event) {
+	oldValues := ec.values.Load().(configValueMap)
+	newValues := maps.Clone(oldValues)
+	for _, event := range events {
+		key, ok := ec.configKey(string(event.Kv.Key))
+		if !ok {
+			continue
+		}
+		lowerKey := strings.ToLower(key)
+		if event.Type == clientv3.EventTypeDelete {
+			delete(newValues, lowerKey)
+			continue
+		}
+		if cvs, ok := decodeKeyValues(ec.logger, key, event.Kv.Value); ok {
+			newValues[lowerKey] = cvs
+		}
+	}
+
+	ec.values.Store(newValues)
+	logDiff(ec.logger, oldValues, newValues)
+	ec.logger.Info("Updated dynamic config")
+}
+
+// configKey returns the dynamic config key of an etcd key under the prefix. It's false for
+// nested keys, which are ignored.
+func (ec *etcdClient) configKey(etcdKey string) (string, bool) {
+	key := strings.TrimPrefix(etcdKey, ec.prefix)
+	if key == "" || strings.HasSuffix(key, "/") {
+		// folder
+		return "", false
+	}
+	if strings.Contains(key, "/") {
+		ec.logger.Warn("dynamic config warning", tag.Error(fmt.Errorf("ignoring nested etcd key %q", etcdKey)))
+		return "", false
+	}
+	return key, true
+}
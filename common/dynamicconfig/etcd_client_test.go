@@ -0,0 +1,180 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	etcdClientSuite struct {
+		suite.Suite
+		*require.Assertions
+		etcd   *embed.Etcd
+		kv     *clientv3.Client
+		doneCh chan interface{}
+	}
+)
+
+const etcdTestPrefix = "temporal/dynamicconfig/"
+
+func TestEtcdClientSuite(t *testing.T) {
+	s := new(etcdClientSuite)
+	suite.Run(t, s)
+}
+
+func (s *etcdClientSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+	cfg := embed.NewConfig()
+	cfg.Dir = s.T().TempDir()
+	cfg.LogLevel = "error"
+	clientURL, _ := url.Parse("http://127.0.0.1:0")
+	peerURL, _ := url.Parse("http://127.0.0.1:0")
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = []url.URL{*clientURL}, []url.URL{*clientURL}
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = []url.URL{*peerURL}, []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.Name + "=" + peerURL.String()
+	etcd, err := embed.StartEtcd(cfg)
+	s.NoError(err)
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		s.FailNow("embedded etcd didn't start")
+	}
+	s.etcd = etcd
+	s.kv, err = clientv3.New(clientv3.Config{
+		Endpoints:   []string{etcd.Clients[0].Addr().String()},
+		DialTimeout: 5 * time.Second,
+	})
+	s.NoError(err)
+}
+
+func (s *etcdClientSuite) TearDownSuite() {
+	s.NoError(s.kv.Close())
+	s.etcd.Close()
+}
+
+func (s *etcdClientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	dynamicconfig.ResetRegistryForTest()
+	_, err := s.kv.Delete(context.Background(), "", clientv3.WithPrefix())
+	s.NoError(err)
+	s.doneCh = make(chan interface{})
+}
+
+func (s *etcdClientSuite) TearDownTest() {
+	close(s.doneCh)
+}
+
+func (s *etcdClientSuite) put(key, value string, opts ...clientv3.OpOption) {
+	_, err := s.kv.Put(context.Background(), key, value, opts...)
+	s.NoError(err)
+}
+
+func (s *etcdClientSuite) newClient() dynamicconfig.Client {
+	client, err := dynamicconfig.NewEtcdClientWithKV(s.kv, &dynamicconfig.EtcdClientConfig{
+		Prefix: "temporal/dynamicconfig",
+	}, log.NewNoopLogger(), s.doneCh)
+	s.NoError(err)
+	return client
+}
+
+func (s *etcdClientSuite) TestGetValue() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetIntPropertyKey, 10, "")
+	s.put(etcdTestPrefix+testGetIntPropertyKey, `
+- value: 100
+  constraints:
+    namespace: samples-namespace
+- value: 50
+`)
+	s.put(etcdTestPrefix+testGetStringPropertyKey, "- value: abc")
+	s.put(etcdTestPrefix+testGetFloat64PropertyKey, "not: [valid")
+	s.put(etcdTestPrefix+"nested/"+testGetBoolPropertyKey, "- value: true")
+	s.put("temporal/other/"+testGetBoolPropertyKey, "- value: true")
+
+	client := s.newClient()
+	s.ElementsMatch([]dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: 100},
+		{Value: 50},
+	}, client.GetValue(testGetIntPropertyKey))
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: "abc"}}, client.GetValue(testGetStringPropertyKey))
+	// an invalid key doesn't affect the others
+	s.Nil(client.GetValue(testGetFloat64PropertyKey))
+	s.Nil(client.GetValue(testGetBoolPropertyKey))
+
+	collection := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	s.Equal(100, setting.Get(collection)("samples-namespace"))
+	s.Equal(50, setting.Get(collection)("other-namespace"))
+}
+
+func (s *etcdClientSuite) TestWatch() {
+	s.put(etcdTestPrefix+testGetIntPropertyKey, "- value: 100")
+	s.put(etcdTestPrefix+testGetStringPropertyKey, "- value: abc")
+	client := s.newClient()
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+
+	s.put(etcdTestPrefix+testGetIntPropertyKey, "- value: 200")
+	s.Eventually(func() bool {
+		v := client.GetValue(testGetIntPropertyKey)
+		return len(v) == 1 && v[0].Value == 200
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// an invalid update keeps the previous values
+	s.put(etcdTestPrefix+testGetIntPropertyKey, "not: [valid")
+	s.put(etcdTestPrefix+testGetBoolPropertyKey, "- value: true")
+	s.Eventually(func() bool {
+		return client.GetValue(testGetBoolPropertyKey) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 200}}, client.GetValue(testGetIntPropertyKey))
+
+	_, err := s.kv.Delete(context.Background(), etcdTestPrefix+testGetStringPropertyKey)
+	s.NoError(err)
+	s.Eventually(func() bool {
+		return client.GetValue(testGetStringPropertyKey) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *etcdClientSuite) TestWatch_LeaseExpiry() {
+	lease, err := s.kv.Grant(context.Background(), 1)
+	s.NoError(err)
+	s.put(etcdTestPrefix+testGetIntPropertyKey, "- value: 100", clientv3.WithLease(lease.ID))
+	client := s.newClient()
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+
+	// the value is removed once its lease expires
+	s.Eventually(func() bool {
+		return client.GetValue(testGetIntPropertyKey) == nil
+	}, 10*time.Second, 50*time.Millisecond)
+}
@@ -30,11 +30,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/blang/semver/v4"
 	enumspb "go.temporal.io/api/enums/v1"
 	"gopkg.in/yaml.v3"
 
@@ -43,7 +44,7 @@ import (
 	"go.temporal.io/server/common/log/tag"
 )
 
-var _ Client = (*fileBasedClient)(nil)
+var _ ReloadableClient = (*fileBasedClient)(nil)
 
 const (
 	minPollInterval = time.Second * 5
@@ -66,10 +67,19 @@ type (
 
 	configValueMap map[string][]ConstrainedValue
 
+	yamlConstrainedValue struct {
+		Constraints map[string]any
+		Value       any
+		ExpiresAt   time.Time `yaml:"expiresAt"`
+	}
+
 	fileBasedClient struct {
-		values          atomic.Value // configValueMap
-		logger          log.Logger
-		reader          FileReader
+		values atomic.Value // configValueMap
+		logger log.Logger
+		reader FileReader
+
+		// updateLock serializes updates by the update loop and Reload
+		updateLock      sync.Mutex
 		lastUpdatedTime time.Time
 		config          *FileBasedClientConfig
 		doneCh          <-chan interface{}
@@ -154,11 +164,24 @@ func (fc *fileBasedClient) init() error {
 // This is public mainly for testing. The update loop will call this periodically, you don't
 // have to call it explicitly.
 func (fc *fileBasedClient) Update() error {
+	return fc.update(false)
+}
+
+// Reload re-reads the file even if its modification time didn't change, e.g. because it was
+// replaced by a file with an older one.
+func (fc *fileBasedClient) Reload() error {
+	return fc.update(true)
+}
+
+func (fc *fileBasedClient) update(force bool) error {
+	fc.updateLock.Lock()
+	defer fc.updateLock.Unlock()
+
 	modtime, err := fc.reader.GetModTime()
 	if err != nil {
 		return fmt.Errorf("dynamic config file: %s: %w", fc.config.Filepath, err)
 	}
-	if !modtime.After(fc.lastUpdatedTime) {
+	if !force && !modtime.After(fc.lastUpdatedTime) {
 		return nil
 	}
 	fc.lastUpdatedTime = modtime
@@ -182,7 +205,7 @@ func (fc *fileBasedClient) Update() error {
 
 	prev := fc.values.Swap(newValues)
 	oldValues, _ := prev.(configValueMap)
-	fc.logDiff(oldValues, newValues)
+	logDiff(fc.logger, oldValues, newValues)
 	fc.logger.Info("Updated dynamic config")
 
 	return nil
@@ -191,49 +214,52 @@ func (fc *fileBasedClient) Update() error {
 func loadFile(contents []byte) (configValueMap, *LoadResult) {
 	lr := &LoadResult{}
 
-	var yamlValues map[string][]struct {
-		Constraints map[string]any
-		Value       any
-	}
+	var yamlValues map[string][]yamlConstrainedValue
 	if err := yaml.Unmarshal(contents, &yamlValues); err != nil {
 		return nil, lr.errorf("decode error: %w", err)
 	}
 
 	newValues := make(configValueMap, len(yamlValues))
 	for key, yamlCV := range yamlValues {
-		precedence := PrecedenceUnknown
-		setting := queryRegistry(Key(key))
-		if setting == nil {
-			lr.warnf("unregistered key %q", key)
-		} else {
-			precedence = setting.Precedence()
-		}
+		newValues[strings.ToLower(key)] = convertYamlConstrainedValues(key, yamlCV, lr)
+	}
 
-		cvs := make([]ConstrainedValue, len(yamlCV))
-		for i, cv := range yamlCV {
-			// yaml will unmarshal map into map[interface{}]interface{} instead of map[string]interface{}
-			// manually convert key type to string for all values here
-			val, err := convertKeyTypeToString(cv.Value)
-			if err != nil {
-				lr.error(err)
-				continue
-			}
+	return newValues, lr
+}
 
-			// try validating if known setting
-			if setting != nil {
-				if valErr := setting.Validate(val); valErr != nil {
-					// TODO: raise this to error level
-					lr.warnf("validation failed: key %q value %v: %w", key, cv.Value, valErr)
-				}
-			}
+// convertYamlConstrainedValues converts the yaml values of a single key, reporting problems to lr.
+func convertYamlConstrainedValues(key string, yamlCV []yamlConstrainedValue, lr *LoadResult) []ConstrainedValue {
+	precedence := PrecedenceUnknown
+	setting := queryRegistry(Key(key))
+	if setting == nil {
+		lr.warnf("unregistered key %q", key)
+	} else {
+		precedence = setting.Precedence()
+	}
 
-			cvs[i].Value = val
-			cvs[i].Constraints = convertYamlConstraints(key, cv.Constraints, precedence, lr)
+	cvs := make([]ConstrainedValue, len(yamlCV))
+	for i, cv := range yamlCV {
+		// yaml will unmarshal map into map[interface{}]interface{} instead of map[string]interface{}
+		// manually convert key type to string for all values here
+		val, err := convertKeyTypeToString(cv.Value)
+		if err != nil {
+			lr.error(err)
+			continue
 		}
-		newValues[strings.ToLower(key)] = cvs
-	}
 
-	return newValues, lr
+		// try validating if known setting
+		if setting != nil {
+			if valErr := setting.Validate(val); valErr != nil {
+				// TODO: raise this to error level
+				lr.warnf("validation failed: key %q value %v: %w", key, cv.Value, valErr)
+			}
+		}
+
+		cvs[i].Value = val
+		cvs[i].Constraints = convertYamlConstraints(key, cv.Constraints, precedence, lr)
+		cvs[i].ExpiresAt = cv.ExpiresAt
+	}
+	return cvs
 }
 
 func (fc *fileBasedClient) validateStaticConfig(config *FileBasedClientConfig) error {
@@ -249,72 +275,36 @@ func (fc *fileBasedClient) validateStaticConfig(config *FileBasedClientConfig) e
 	return nil
 }
 
-func (fc *fileBasedClient) logDiff(old configValueMap, new configValueMap) {
+func logDiff(logger log.Logger, old configValueMap, new configValueMap) {
 	for key, newValues := range new {
-		oldValues, ok := old[key]
-		if !ok {
-			for _, newValue := range newValues {
-				// new key added
-				fc.logValueDiff(key, nil, &newValue)
-			}
-		} else {
-			// compare existing keys
-			fc.logConstraintsDiff(key, oldValues, newValues)
+		for _, change := range diffConstrainedValues(Key(key), old[key], newValues) {
+			logValueDiff(logger, key, change.Old, change.New)
 		}
 	}
 
-	// check for removed values
+	// check for removed keys
 	for key, oldValues := range old {
 		if _, ok := new[key]; !ok {
-			for _, oldValue := range oldValues {
-				fc.logValueDiff(key, &oldValue, nil)
+			for _, change := range diffConstrainedValues(Key(key), oldValues, nil) {
+				logValueDiff(logger, key, change.Old, change.New)
 			}
 		}
 	}
 }
 
-func (fc *fileBasedClient) logConstraintsDiff(key string, oldValues []ConstrainedValue, newValues []ConstrainedValue) {
-	for _, oldValue := range oldValues {
-		matchFound := false
-		for _, newValue := range newValues {
-			if oldValue.Constraints == newValue.Constraints {
-				matchFound = true
-				if !reflect.DeepEqual(oldValue.Value, newValue.Value) {
-					fc.logValueDiff(key, &oldValue, &newValue)
-				}
-			}
-		}
-		if !matchFound {
-			fc.logValueDiff(key, &oldValue, nil)
-		}
-	}
-
-	for _, newValue := range newValues {
-		matchFound := false
-		for _, oldValue := range oldValues {
-			if oldValue.Constraints == newValue.Constraints {
-				matchFound = true
-			}
-		}
-		if !matchFound {
-			fc.logValueDiff(key, nil, &newValue)
-		}
-	}
-}
-
-func (fc *fileBasedClient) logValueDiff(key string, oldValue *ConstrainedValue, newValue *ConstrainedValue) {
+func logValueDiff(logger log.Logger, key string, oldValue *ConstrainedValue, newValue *ConstrainedValue) {
 	logLine := &strings.Builder{}
 	logLine.Grow(128)
 	logLine.WriteString("dynamic config changed for the key: ")
 	logLine.WriteString(key)
 	logLine.WriteString(" oldValue: ")
-	fc.appendConstrainedValue(logLine, oldValue)
+	appendConstrainedValue(logLine, oldValue)
 	logLine.WriteString(" newValue: ")
-	fc.appendConstrainedValue(logLine, newValue)
-	fc.logger.Info(logLine.String())
+	appendConstrainedValue(logLine, newValue)
+	logger.Info(logLine.String())
 }
 
-func (fc *fileBasedClient) appendConstrainedValue(logLine *strings.Builder, value *ConstrainedValue) {
+func appendConstrainedValue(logLine *strings.Builder, value *ConstrainedValue) {
 	if value == nil {
 		logLine.WriteString("nil")
 	} else {
@@ -340,7 +330,23 @@ func (fc *fileBasedClient) appendConstrainedValue(logLine *strings.Builder, valu
 		if value.Constraints.Destination != "" {
 			logLine.WriteString(fmt.Sprintf("{Destination:%s}", value.Constraints.Destination))
 		}
-		logLine.WriteString(fmt.Sprint("} value: ", value.Value, " }"))
+		if value.Constraints.WorkflowType != "" {
+			logLine.WriteString(fmt.Sprintf("{WorkflowType:%s}", value.Constraints.WorkflowType))
+		}
+		if value.Constraints.SDKName != "" {
+			logLine.WriteString(fmt.Sprintf("{SDKName:%s}", value.Constraints.SDKName))
+		}
+		if value.Constraints.SDKVersion != "" {
+			logLine.WriteString(fmt.Sprintf("{SDKVersion:%s}", value.Constraints.SDKVersion))
+		}
+		if value.Constraints.Environment != "" {
+			logLine.WriteString(fmt.Sprintf("{Environment:%s}", value.Constraints.Environment))
+		}
+		logLine.WriteString(fmt.Sprint("} value: ", value.Value))
+		if !value.ExpiresAt.IsZero() {
+			logLine.WriteString(fmt.Sprint(" expiresAt: ", value.ExpiresAt.UTC().Format(time.RFC3339)))
+		}
+		logLine.WriteString(" }")
 	}
 }
 
@@ -394,7 +400,7 @@ func convertYamlConstraints(key string, m map[string]any, precedence Precedence,
 			} else {
 				lr.errorf("namespace constraint must be string")
 			}
-			validConstraint = precedence == PrecedenceNamespace || precedence == PrecedenceTaskQueue || precedence == PrecedenceDestination
+			validConstraint = precedence == PrecedenceNamespace || precedence == PrecedenceTaskQueue || precedence == PrecedenceDestination || precedence == PrecedenceWorkflowType || precedence == PrecedenceSDKVersion
 		case "namespaceid":
 			if v, ok := v.(string); ok {
 				cs.NamespaceID = v
@@ -459,6 +465,36 @@ func convertYamlConstraints(key string, m map[string]any, precedence Precedence,
 				lr.errorf("destination constraint must be string")
 			}
 			validConstraint = precedence == PrecedenceDestination
+		case "workflowtype":
+			if v, ok := v.(string); ok {
+				cs.WorkflowType = v
+			} else {
+				lr.errorf("workflowType constraint must be string")
+			}
+			validConstraint = precedence == PrecedenceWorkflowType
+		case "sdkname":
+			if v, ok := v.(string); ok {
+				cs.SDKName = v
+			} else {
+				lr.errorf("sdkName constraint must be string")
+			}
+			validConstraint = precedence == PrecedenceSDKVersion
+		case "sdkversion":
+			if v, ok := v.(string); !ok {
+				lr.errorf("sdkVersion constraint must be string")
+			} else if _, err := semver.ParseRange(v); err != nil {
+				lr.errorf("sdkVersion constraint must be a semver range: %w", err)
+			} else {
+				cs.SDKVersion = v
+			}
+			validConstraint = precedence == PrecedenceSDKVersion
+		case "environment":
+			// valid for all keys, see NewEnvironmentClient
+			if v, ok := v.(string); ok {
+				cs.Environment = v
+			} else {
+				lr.errorf("environment constraint must be string")
+			}
 		default:
 			lr.errorf("unknown constraint type %q", k)
 		}
@@ -179,6 +179,87 @@ func (s *fileBasedClientSuite) TestGetIntValue_FilterByDestination() {
 	s.Equal(50, dc("test-namespace", "test-destination-2"))
 }
 
+func (s *fileBasedClientSuite) TestGetBoolValue_FilterByWorkflowType() {
+	dc := dynamicconfig.NewWorkflowTypeBoolSetting(testGetBoolPropertyFilteredByWorkflowTypeKey, false, "").Get(s.collection)
+	s.False(dc("foo", "bar"))
+	s.True(dc("test-namespace", "test-workflow-type-1"))
+	s.False(dc("test-namespace", "test-workflow-type-2"))
+	s.True(dc("other-namespace", "test-workflow-type-2"))
+}
+
+func (s *fileBasedClientSuite) TestGetIntValue_FilterBySDKVersion() {
+	dc := dynamicconfig.NewSDKVersionIntSetting(testGetIntPropertyFilteredBySDKVersionKey, 0, "").Get(s.collection)
+	s.Equal(1, dc("foo", "temporal-go", "1.19.0"))
+	s.Equal(2, dc("foo", "temporal-go", "1.20.0"))
+	s.Equal(3, dc("test-namespace", "temporal-go", "1.22.0"))
+	s.Equal(2, dc("test-namespace", "temporal-go", "1.25.0"))
+	s.Equal(1, dc("foo", "temporal-java", "1.22.0"))
+	s.Equal(1, dc("foo", "temporal-go", "not-a-version"))
+}
+
+func (s *fileBasedClientSuite) TestGetIntValue_FilteredByEnvironment() {
+	setting := dynamicconfig.NewGlobalIntSetting(testGetIntPropertyByEnvironmentKey, 0, "")
+	staging := dynamicconfig.NewCollection(dynamicconfig.NewEnvironmentClient(s.client, "staging"), log.NewNoopLogger())
+	s.Equal(2, setting.Get(staging)())
+	prod := dynamicconfig.NewCollection(dynamicconfig.NewEnvironmentClient(s.client, "prod"), log.NewNoopLogger())
+	s.Equal(1, setting.Get(prod)())
+}
+
+func (s *fileBasedClientSuite) TestOverlayClient() {
+	boolSetting := dynamicconfig.NewNamespaceBoolSetting(testGetBoolPropertyKey, false, "")
+	durationSetting := dynamicconfig.NewGlobalDurationSetting(testGetDurationPropertyKey, 0, "")
+	intSetting := dynamicconfig.NewGlobalIntSetting(testGetIntPropertyKey, 0, "")
+	s.True(boolSetting.Get(s.collection)("global-samples-namespace"))
+	s.Equal(time.Minute, durationSetting.Get(s.collection)())
+
+	client, err := dynamicconfig.NewOverlayClient(s.client, []string{
+		"testgetboolpropertykey=true",
+		"testGetDurationPropertyKey=5s",
+		"testGetDurationPropertyKey=10s",
+	})
+	s.NoError(err)
+	collection := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+
+	// overrides win over constrained file values too
+	s.True(boolSetting.Get(collection)("global-samples-namespace"))
+	s.True(boolSetting.Get(collection)("other-namespace"))
+	s.Equal(10*time.Second, durationSetting.Get(collection)())
+	// keys that aren't overridden still come from the file
+	s.Equal(1000, intSetting.Get(collection)())
+}
+
+func (s *fileBasedClientSuite) TestOverlayClient_Errors() {
+	dynamicconfig.NewGlobalIntSetting(testGetIntPropertyKey, 0, "")
+	for _, override := range []string{
+		"testGetIntPropertyKey",
+		"=5",
+		"unknownKey=5",
+		"testGetIntPropertyKey=[1, 2",
+		"testGetIntPropertyKey=abc",
+	} {
+		_, err := dynamicconfig.NewOverlayClient(s.client, []string{override})
+		s.Error(err, override)
+	}
+}
+
+func (s *fileBasedClientSuite) TestMergedClient() {
+	intSetting := dynamicconfig.NewNamespaceIntSetting(testGetIntPropertyKey, 0, "")
+	boolSetting := dynamicconfig.NewGlobalBoolSetting(testGetBoolPropertyKey, true, "")
+	overlay, err := dynamicconfig.NewFileBasedClient(&dynamicconfig.FileBasedClientConfig{
+		Filepath:     "config/testOverlayConfig.yaml",
+		PollInterval: time.Second * 5,
+	}, log.NewNoopLogger(), s.doneCh)
+	s.NoError(err)
+	collection := dynamicconfig.NewCollection(dynamicconfig.NewMergedClient(log.NewNoopLogger(), s.client, overlay), log.NewNoopLogger())
+
+	s.Equal(2000, intSetting.Get(collection)("other-namespace"))
+	s.Equal(3000, intSetting.Get(collection)("overlay-namespace"))
+	// namespace values of the base file are kept
+	s.Equal(1004, intSetting.Get(collection)("another-namespace"))
+	// keys only in the base file
+	s.False(boolSetting.Get(collection)())
+}
+
 func (s *fileBasedClientSuite) TestGetFloatValue() {
 	v := dynamicconfig.NewGlobalFloatSetting(testGetFloat64PropertyKey, 1, "").Get(s.collection)()
 	s.Equal(12.0, v)
@@ -487,6 +568,44 @@ testGetIntPropertyKey:
 	close(doneCh)
 }
 
+func (s *fileBasedClientSuite) TestReload() {
+	dynamicconfig.NewGlobalIntSetting(testGetIntPropertyKey, 0, "")
+
+	ctrl := gomock.NewController(s.T())
+	reader := dynamicconfig.NewMockFileReader(ctrl)
+	modTime := time.Now()
+	reader.EXPECT().GetModTime().Return(modTime, nil).AnyTimes()
+	reader.EXPECT().ReadFile().Return([]byte(`
+testGetIntPropertyKey:
+- value: 1000
+`), nil)
+	client, err := dynamicconfig.NewFileBasedClientWithReader(reader,
+		&dynamicconfig.FileBasedClientConfig{
+			Filepath:     "anyValue",
+			PollInterval: time.Minute,
+		}, log.NewNoopLogger(), s.doneCh)
+	s.NoError(err)
+	cln := dynamicconfig.NewCollection(dynamicconfig.NewEnvironmentClient(client, ""), log.NewNoopLogger())
+
+	// the file is re-read although its modification time didn't change
+	reader.EXPECT().ReadFile().Return([]byte(`
+testGetIntPropertyKey:
+- value: 2000
+`), nil)
+	s.NoError(client.Update())
+	changes, err := cln.Reload()
+	s.NoError(err)
+	s.Equal([]dynamicconfig.ValueChange{{
+		Key: testGetIntPropertyKey,
+		Old: &dynamicconfig.ConstrainedValue{Value: 1000},
+		New: &dynamicconfig.ConstrainedValue{Value: 2000},
+	}}, changes)
+
+	reader.EXPECT().ReadFile().Return([]byte(`bad yaml: [`), nil)
+	_, err = cln.Reload()
+	s.Error(err)
+}
+
 func (s *fileBasedClientSuite) TestUpdate_ChangeOrder_ShouldNotWriteLog() {
 	dynamicconfig.NewGlobalIntSetting(testGetIntPropertyKey, 0, "")
 	dynamicconfig.NewNamespaceFloatSetting(testGetFloat64PropertyKey, 0, "")
@@ -621,6 +740,20 @@ testGetBoolPropertyKey:
 	s.ErrorContains(lr.Errors[0], "namespace constraint must be string")
 }
 
+func (s *fileBasedClientSuite) TestErrorBadSDKVersionConstraint() {
+	dynamicconfig.NewSDKVersionBoolSetting(testGetBoolPropertyFilteredBySDKVersionKey, true, "")
+
+	lr := dynamicconfig.ValidateFile([]byte(`
+testGetBoolPropertyFilteredBySDKVersionKey:
+- value: false
+  constraints:
+    sdkName: temporal-go
+    sdkVersion: at least 1.20
+`))
+	s.Equal(1, len(lr.Errors))
+	s.ErrorContains(lr.Errors[0], "sdkVersion constraint must be a semver range")
+}
+
 func (s *fileBasedClientSuite) TestErrorBadConstraints() {
 	dynamicconfig.NewTaskQueueBoolSetting(testGetBoolPropertyKey, true, "")
 
@@ -0,0 +1,109 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	enumspb "go.temporal.io/api/enums/v1"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+)
+
+type (
+	// FilterOption sets one of the filter values of a GroupResolver.
+	FilterOption func(*Constraints)
+
+	// GroupResolver resolves settings against one set of filter values, e.g. all limits of a
+	// single request to a namespace. The precedence list is computed once per kind of setting
+	// and reused for consecutive settings of the same kind, while values are still looked up
+	// on every call.
+	//
+	// A GroupResolver is meant to be scoped to a single request: it's not safe for concurrent
+	// use and shouldn't be retained once the request is done.
+	GroupResolver struct {
+		c         *Collection
+		cons      Constraints
+		overrides map[string]any // lowercase key -> value, see WithRequestOverrides
+
+		lastPrecedence  Precedence
+		lastConstraints []Constraints
+	}
+)
+
+func NamespaceFilter(namespace string) FilterOption {
+	return func(cons *Constraints) { cons.Namespace = namespace }
+}
+
+func NamespaceIDFilter(namespaceID string) FilterOption {
+	return func(cons *Constraints) { cons.NamespaceID = namespaceID }
+}
+
+func TaskQueueFilter(taskQueue string, taskQueueType enumspb.TaskQueueType) FilterOption {
+	return func(cons *Constraints) {
+		cons.TaskQueueName = taskQueue
+		cons.TaskQueueType = taskQueueType
+	}
+}
+
+func ShardIDFilter(shardID int32) FilterOption {
+	return func(cons *Constraints) { cons.ShardID = shardID }
+}
+
+func TaskTypeFilter(taskType enumsspb.TaskType) FilterOption {
+	return func(cons *Constraints) { cons.TaskType = taskType }
+}
+
+func DestinationFilter(destination string) FilterOption {
+	return func(cons *Constraints) { cons.Destination = destination }
+}
+
+func WorkflowTypeFilter(workflowType string) FilterOption {
+	return func(cons *Constraints) { cons.WorkflowType = workflowType }
+}
+
+func SDKVersionFilter(sdkName string, sdkVersion string) FilterOption {
+	return func(cons *Constraints) {
+		cons.SDKName = sdkName
+		cons.SDKVersion = sdkVersion
+	}
+}
+
+// EvaluateGroup returns a GroupResolver for the given filter values. Resolving a setting with
+// its GetInGroup method gives the same value as calling its Get function with the same filter
+// values.
+func (c *Collection) EvaluateGroup(filters ...FilterOption) *GroupResolver {
+	r := &GroupResolver{c: c}
+	for _, filter := range filters {
+		filter(&r.cons)
+	}
+	return r
+}
+
+func (r *GroupResolver) precedence(p Precedence) []Constraints {
+	if r.lastConstraints == nil || r.lastPrecedence != p {
+		r.lastPrecedence = p
+		r.lastConstraints = precedenceConstraints(p, r.cons)
+	}
+	return r.lastConstraints
+}
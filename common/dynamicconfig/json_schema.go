@@ -0,0 +1,477 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+type (
+	// JSONSchema is a compiled JSON schema that JSON documents can be validated against. The zero
+	// value accepts any document.
+	//
+	// Only a subset of JSON Schema is supported: the type, enum, const, properties, required,
+	// additionalProperties, items, minItems, maxItems, minLength, maxLength, pattern, minimum,
+	// maximum, exclusiveMinimum and exclusiveMaximum keywords, and boolean schemas. Annotations
+	// like title and description are ignored. Schemas using any other keyword are rejected, so
+	// that they aren't silently more permissive than intended.
+	JSONSchema struct {
+		source string
+		root   *jsonSchemaNode
+	}
+
+	jsonSchemaNode struct {
+		// reject is set for the false schema, which doesn't accept anything
+		reject bool
+
+		types []string
+		enum  []any
+
+		properties           map[string]*jsonSchemaNode
+		required             []string
+		additionalProperties *jsonSchemaNode
+
+		items    *jsonSchemaNode
+		minItems int
+		maxItems int // -1 if unset
+
+		minLength int
+		maxLength int // -1 if unset
+		pattern   *regexp.Regexp
+
+		minimum          *float64
+		maximum          *float64
+		exclusiveMinimum *float64
+		exclusiveMaximum *float64
+	}
+
+	// jsonSchemaCache remembers the last schema compiled by a converter, see
+	// NewJSONSchemaConverter.
+	jsonSchemaCache struct {
+		sync.Mutex
+		source string
+		schema JSONSchema
+		err    error
+		valid  bool
+	}
+)
+
+var (
+	jsonSchemaTypes = map[string]struct{}{
+		"null": {}, "boolean": {}, "object": {}, "array": {}, "number": {}, "integer": {}, "string": {},
+	}
+	jsonSchemaAnnotations = map[string]struct{}{
+		"$schema": {}, "$id": {}, "$comment": {}, "title": {}, "description": {}, "default": {},
+		"examples": {}, "deprecated": {}, "readOnly": {}, "writeOnly": {},
+	}
+)
+
+// ParseJSONSchema compiles a JSON schema. An empty source is valid and accepts any document.
+func ParseJSONSchema(source string) (JSONSchema, error) {
+	if source == "" {
+		return JSONSchema{}, nil
+	}
+	schema, err := decodeJSON([]byte(source))
+	if err != nil {
+		return JSONSchema{}, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	root, err := compileJSONSchema(schema, "#")
+	if err != nil {
+		return JSONSchema{}, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return JSONSchema{source: source, root: root}, nil
+}
+
+// MustParseJSONSchema is ParseJSONSchema for setting defaults. It panics on error.
+func MustParseJSONSchema(source string) JSONSchema {
+	s, err := ParseJSONSchema(source)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate returns an error if data is not a JSON document accepted by the schema. The error
+// names the location of the first violation found, e.g. "$.items[2]: expected string, got number".
+func (s JSONSchema) Validate(data []byte) error {
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if s.root == nil {
+		return nil
+	}
+	return s.root.validate(doc, "$")
+}
+
+// String returns the schema source the schema was compiled from.
+func (s JSONSchema) String() string {
+	return s.source
+}
+
+// NewJSONSchemaConverter returns a conversion function for New*TypedSettingWithConverter with a
+// JSONSchema type. The value from dynamic config must be a JSON schema string. The converter
+// remembers the last schema it compiled, so that a schema is only compiled again when the value
+// changes, which means that every setting should have its own converter. Invalid schemas fall
+// back to the setting's default.
+func NewJSONSchemaConverter() func(v any) (JSONSchema, error) {
+	cache := &jsonSchemaCache{}
+	return func(v any) (JSONSchema, error) {
+		switch v := v.(type) {
+		case JSONSchema:
+			return v, nil
+		case string:
+			return cache.parse(v)
+		}
+		return JSONSchema{}, errors.New("value type is not string")
+	}
+}
+
+func (c *jsonSchemaCache) parse(source string) (JSONSchema, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.valid || c.source != source {
+		c.schema, c.err = ParseJSONSchema(source)
+		c.source = source
+		c.valid = true
+	}
+	return c.schema, c.err
+}
+
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+//nolint:revive // cyclomatic complexity
+func compileJSONSchema(schema any, path string) (*jsonSchemaNode, error) {
+	if b, ok := schema.(bool); ok {
+		return &jsonSchemaNode{reject: !b, maxItems: -1, maxLength: -1}, nil
+	}
+	m, ok := schema.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", path)
+	}
+
+	n := &jsonSchemaNode{maxItems: -1, maxLength: -1}
+	var err error
+	for _, keyword := range sortedKeys(m) {
+		value := m[keyword]
+		keywordPath := path + "/" + keyword
+		switch keyword {
+		case "type":
+			n.types, err = compileJSONSchemaTypes(value, keywordPath)
+		case "enum":
+			values, ok := value.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array", keywordPath)
+			}
+			n.enum = append(n.enum, values...)
+		case "const":
+			n.enum = append(n.enum, value)
+		case "properties":
+			props, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an object", keywordPath)
+			}
+			n.properties = make(map[string]*jsonSchemaNode, len(props))
+			for name, prop := range props {
+				if n.properties[name], err = compileJSONSchema(prop, keywordPath+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			n.required, err = compileJSONSchemaStrings(value, keywordPath)
+		case "additionalProperties":
+			n.additionalProperties, err = compileJSONSchema(value, keywordPath)
+		case "items":
+			n.items, err = compileJSONSchema(value, keywordPath)
+		case "minItems":
+			n.minItems, err = compileJSONSchemaCount(value, keywordPath)
+		case "maxItems":
+			n.maxItems, err = compileJSONSchemaCount(value, keywordPath)
+		case "minLength":
+			n.minLength, err = compileJSONSchemaCount(value, keywordPath)
+		case "maxLength":
+			n.maxLength, err = compileJSONSchemaCount(value, keywordPath)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string", keywordPath)
+			}
+			if n.pattern, err = regexp.Compile(pattern); err != nil {
+				err = fmt.Errorf("%s: %w", keywordPath, err)
+			}
+		case "minimum":
+			n.minimum, err = compileJSONSchemaNumber(value, keywordPath)
+		case "maximum":
+			n.maximum, err = compileJSONSchemaNumber(value, keywordPath)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = compileJSONSchemaNumber(value, keywordPath)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = compileJSONSchemaNumber(value, keywordPath)
+		default:
+			if _, ok := jsonSchemaAnnotations[keyword]; !ok {
+				return nil, fmt.Errorf("%s: unsupported keyword", keywordPath)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func compileJSONSchemaTypes(value any, path string) ([]string, error) {
+	var types []string
+	if t, ok := value.(string); ok {
+		types = []string{t}
+	} else {
+		var err error
+		if types, err = compileJSONSchemaStrings(value, path); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range types {
+		if _, ok := jsonSchemaTypes[t]; !ok {
+			return nil, fmt.Errorf("%s: unknown type %q", path, t)
+		}
+	}
+	return types, nil
+}
+
+func compileJSONSchemaStrings(value any, path string) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an array of strings", path)
+	}
+	strs := make([]string, len(values))
+	for i, v := range values {
+		if strs[i], ok = v.(string); !ok {
+			return nil, fmt.Errorf("%s: must be an array of strings", path)
+		}
+	}
+	return strs, nil
+}
+
+func compileJSONSchemaCount(value any, path string) (int, error) {
+	n, ok := value.(json.Number)
+	if ok {
+		if i, err := strconv.Atoi(n.String()); err == nil && i >= 0 {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s: must be a non-negative integer", path)
+}
+
+func compileJSONSchemaNumber(value any, path string) (*float64, error) {
+	if n, ok := value.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return &f, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: must be a number", path)
+}
+
+//nolint:revive // cyclomatic complexity
+func (n *jsonSchemaNode) validate(doc any, path string) error {
+	if n.reject {
+		return fmt.Errorf("%s: no value is allowed", path)
+	}
+	if len(n.types) > 0 && !n.matchesType(doc) {
+		return fmt.Errorf("%s: expected %s, got %s", path, joinTypes(n.types), jsonTypeOf(doc))
+	}
+	if len(n.enum) > 0 && !n.inEnum(doc) {
+		return fmt.Errorf("%s: value is not one of the allowed values", path)
+	}
+
+	switch doc := doc.(type) {
+	case map[string]any:
+		for _, name := range n.required {
+			if _, ok := doc[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for _, name := range sortedKeys(doc) {
+			propPath := path + "." + name
+			if prop, ok := n.properties[name]; ok {
+				if err := prop.validate(doc[name], propPath); err != nil {
+					return err
+				}
+			} else if n.additionalProperties != nil {
+				if err := n.additionalProperties.validate(doc[name], propPath); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if len(doc) < n.minItems {
+			return fmt.Errorf("%s: expected at least %d items, got %d", path, n.minItems, len(doc))
+		}
+		if n.maxItems >= 0 && len(doc) > n.maxItems {
+			return fmt.Errorf("%s: expected at most %d items, got %d", path, n.maxItems, len(doc))
+		}
+		if n.items != nil {
+			for i, item := range doc {
+				if err := n.items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(doc)
+		if length < n.minLength {
+			return fmt.Errorf("%s: expected at least %d characters, got %d", path, n.minLength, length)
+		}
+		if n.maxLength >= 0 && length > n.maxLength {
+			return fmt.Errorf("%s: expected at most %d characters, got %d", path, n.maxLength, length)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(doc) {
+			return fmt.Errorf("%s: value doesn't match pattern %q", path, n.pattern.String())
+		}
+	case json.Number:
+		f, err := doc.Float64()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if n.minimum != nil && f < *n.minimum {
+			return fmt.Errorf("%s: value must be at least %v", path, *n.minimum)
+		}
+		if n.maximum != nil && f > *n.maximum {
+			return fmt.Errorf("%s: value must be at most %v", path, *n.maximum)
+		}
+		if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+			return fmt.Errorf("%s: value must be greater than %v", path, *n.exclusiveMinimum)
+		}
+		if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+			return fmt.Errorf("%s: value must be less than %v", path, *n.exclusiveMaximum)
+		}
+	}
+	return nil
+}
+
+func (n *jsonSchemaNode) matchesType(doc any) bool {
+	docType := jsonTypeOf(doc)
+	for _, t := range n.types {
+		if t == docType || (t == "number" && docType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *jsonSchemaNode) inEnum(doc any) bool {
+	doc = normalizeJSONNumbers(doc)
+	for _, allowed := range n.enum {
+		if reflect.DeepEqual(normalizeJSONNumbers(allowed), doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeOf returns the JSON Schema type of a decoded document. Numbers without a fractional
+// part are integers.
+func jsonTypeOf(doc any) string {
+	switch doc := doc.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := doc.Float64(); err == nil && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", doc)
+	}
+}
+
+// normalizeJSONNumbers converts numbers to float64, so that e.g. 1 and 1.0 are equal.
+func normalizeJSONNumbers(doc any) any {
+	switch doc := doc.(type) {
+	case json.Number:
+		if f, err := doc.Float64(); err == nil {
+			return f
+		}
+		return doc.String()
+	case map[string]any:
+		normalized := make(map[string]any, len(doc))
+		for k, v := range doc {
+			normalized[k] = normalizeJSONNumbers(v)
+		}
+		return normalized
+	case []any:
+		normalized := make([]any, len(doc))
+		for i, v := range doc {
+			normalized[i] = normalizeJSONNumbers(v)
+		}
+		return normalized
+	default:
+		return doc
+	}
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return fmt.Sprintf("one of %v", types)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/server/common/dynamicconfig"
+)
+
+func TestParseJSONSchema_Invalid(t *testing.T) {
+	for _, source := range []string{
+		`not json`,
+		`{"type": "object"} {}`,
+		`"object"`,
+		`{"type": ["string", "date"]}`,
+		`{"required": "id"}`,
+		`{"minLength": -1}`,
+		`{"maxItems": 1.5}`,
+		`{"minimum": "1"}`,
+		`{"pattern": "("}`,
+		`{"properties": {"id": {"$ref": "#/defs/id"}}}`,
+	} {
+		_, err := dynamicconfig.ParseJSONSchema(source)
+		require.Error(t, err, source)
+	}
+}
+
+func TestJSONSchema_Validate(t *testing.T) {
+	schema := dynamicconfig.MustParseJSONSchema(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "sample payload",
+		"type": "object",
+		"required": ["id", "kind"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"kind": {"enum": ["a", "b"]},
+			"name": {"type": "string", "minLength": 1, "maxLength": 5, "pattern": "^[a-z]+$"},
+			"ratio": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+			"extra": {"type": ["string", "null"]}
+		},
+		"additionalProperties": false
+	}`)
+
+	testCases := []struct {
+		name    string
+		payload string
+		err     string
+	}{
+		{name: "Minimal", payload: `{"id": 1, "kind": "a"}`},
+		{name: "Full", payload: `{"id": 2.0, "kind": "b", "name": "abc", "ratio": 0.5, "tags": ["x", "y"], "extra": null}`},
+		{name: "NotJSON", payload: `{"id": 1`, err: "invalid JSON"},
+		{name: "WrongType", payload: `[]`, err: "$: expected object, got array"},
+		{name: "MissingRequired", payload: `{"id": 1}`, err: `$: missing required property "kind"`},
+		{name: "NotInteger", payload: `{"id": 1.5, "kind": "a"}`, err: "$.id: expected integer, got number"},
+		{name: "BelowMinimum", payload: `{"id": 0, "kind": "a"}`, err: "$.id: value must be at least 1"},
+		{name: "NotInEnum", payload: `{"id": 1, "kind": "c"}`, err: "$.kind: value is not one of the allowed values"},
+		{name: "TooShort", payload: `{"id": 1, "kind": "a", "name": ""}`, err: "$.name: expected at least 1 characters"},
+		{name: "TooLong", payload: `{"id": 1, "kind": "a", "name": "abcdef"}`, err: "$.name: expected at most 5 characters"},
+		{name: "PatternMismatch", payload: `{"id": 1, "kind": "a", "name": "ABC"}`, err: "$.name: value doesn't match pattern"},
+		{name: "ExclusiveBound", payload: `{"id": 1, "kind": "a", "ratio": 1}`, err: "$.ratio: value must be less than 1"},
+		{name: "WrongItemType", payload: `{"id": 1, "kind": "a", "tags": ["x", 2]}`, err: "$.tags[1]: expected string, got integer"},
+		{name: "TooManyItems", payload: `{"id": 1, "kind": "a", "tags": ["x", "y", "z"]}`, err: "$.tags: expected at most 2 items"},
+		{name: "AdditionalProperty", payload: `{"id": 1, "kind": "a", "other": 1}`, err: "$.other: no value is allowed"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.payload))
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestJSONSchema_ZeroValueAcceptsAnything(t *testing.T) {
+	var schema dynamicconfig.JSONSchema
+	require.NoError(t, schema.Validate([]byte(`{"any": ["thing", 1, null]}`)))
+	require.NoError(t, schema.Validate([]byte(`false`)))
+}
@@ -0,0 +1,194 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type (
+	// LabelSelector is a parsed Kubernetes-style label selector, e.g. "tier in (gold,silver), region=us",
+	// see ConvertLabelSelector. A nil LabelSelector matches nothing, an empty one matches everything.
+	LabelSelector struct {
+		requirements []labelRequirement
+	}
+
+	// LabelSelectorPropertyFn returns a LabelSelector.
+	LabelSelectorPropertyFn = TypedPropertyFn[*LabelSelector]
+
+	labelRequirement struct {
+		key      string
+		operator labelOperator
+		values   []string
+	}
+
+	labelOperator int
+)
+
+const (
+	labelOperatorEquals labelOperator = iota
+	labelOperatorNotEquals
+	labelOperatorIn
+	labelOperatorNotIn
+	labelOperatorExists
+	labelOperatorDoesNotExist
+)
+
+var (
+	labelKeyRegexp   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+	labelValueRegexp = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?)?$`)
+	labelSetRegexp   = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\(([^()]*)\)$`)
+)
+
+// Matches returns true if labels satisfy all requirements of the selector. A key that's missing
+// from labels satisfies the "!=", "notin" and "!key" requirements.
+func (s *LabelSelector) Matches(labels map[string]string) bool {
+	if s == nil {
+		return false
+	}
+	for _, r := range s.requirements {
+		value, ok := labels[r.key]
+		var matches bool
+		switch r.operator {
+		case labelOperatorEquals:
+			matches = ok && value == r.values[0]
+		case labelOperatorNotEquals:
+			matches = !ok || value != r.values[0]
+		case labelOperatorIn:
+			matches = ok && slices.Contains(r.values, value)
+		case labelOperatorNotIn:
+			matches = !ok || !slices.Contains(r.values, value)
+		case labelOperatorExists:
+			matches = ok
+		case labelOperatorDoesNotExist:
+			matches = !ok
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLabelSelector parses a comma separated list of requirements, each of which is one of
+// "key=value", "key==value", "key!=value", "key in (v1,v2)", "key notin (v1,v2)", "key" or "!key".
+func ParseLabelSelector(selector string) (*LabelSelector, error) {
+	s := &LabelSelector{}
+	if strings.TrimSpace(selector) == "" {
+		return s, nil
+	}
+	for _, term := range splitLabelSelector(selector) {
+		r, err := parseLabelRequirement(strings.TrimSpace(term))
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+		}
+		s.requirements = append(s.requirements, r)
+	}
+	return s, nil
+}
+
+// ConvertLabelSelector can be used as a conversion function for New*TypedSettingWithConverter
+// with a *LabelSelector type. The value from dynamic config must be a label selector string, see
+// ParseLabelSelector. As the default is used for invalid values, settings should default to a nil
+// selector, so that a malformed selector matches nothing.
+func ConvertLabelSelector(v any) (*LabelSelector, error) {
+	if s, ok := v.(*LabelSelector); ok {
+		return s, nil
+	}
+	str, err := convertString(v)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLabelSelector(str)
+}
+
+// splitLabelSelector splits selector at the commas that are not within the parentheses of a set.
+func splitLabelSelector(selector string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+func parseLabelRequirement(term string) (labelRequirement, error) {
+	if match := labelSetRegexp.FindStringSubmatch(term); match != nil {
+		operator := labelOperatorIn
+		if match[2] == "notin" {
+			operator = labelOperatorNotIn
+		}
+		var values []string
+		for _, value := range strings.Split(match[3], ",") {
+			values = append(values, strings.TrimSpace(value))
+		}
+		return newLabelRequirement(match[1], operator, values...)
+	}
+	for _, op := range []struct {
+		token    string
+		operator labelOperator
+	}{
+		// "!=" and "==" are checked before "=" which they contain
+		{"!=", labelOperatorNotEquals},
+		{"==", labelOperatorEquals},
+		{"=", labelOperatorEquals},
+	} {
+		if key, value, found := strings.Cut(term, op.token); found {
+			return newLabelRequirement(strings.TrimSpace(key), op.operator, strings.TrimSpace(value))
+		}
+	}
+	if key, found := strings.CutPrefix(term, "!"); found {
+		return newLabelRequirement(strings.TrimSpace(key), labelOperatorDoesNotExist)
+	}
+	return newLabelRequirement(term, labelOperatorExists)
+}
+
+func newLabelRequirement(key string, operator labelOperator, values ...string) (labelRequirement, error) {
+	if !labelKeyRegexp.MatchString(key) {
+		return labelRequirement{}, fmt.Errorf("invalid label key %q", key)
+	}
+	for _, value := range values {
+		if !labelValueRegexp.MatchString(value) {
+			return labelRequirement{}, fmt.Errorf("invalid value %q of label %v", value, key)
+		}
+	}
+	if (operator == labelOperatorIn || operator == labelOperatorNotIn) && slices.Contains(values, "") {
+		return labelRequirement{}, fmt.Errorf("empty value in set of label %v", key)
+	}
+	return labelRequirement{key: key, operator: operator, values: values}, nil
+}
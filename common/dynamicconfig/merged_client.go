@@ -0,0 +1,141 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+type (
+	mergedClient struct {
+		clients []Client
+		logger  log.Logger
+
+		// conflicts that were already logged, so that each one is only logged once
+		loggedConflicts sync.Map // mergedClientConflict -> struct{}
+	}
+
+	mergedClientConflict struct {
+		key         string
+		constraints Constraints
+	}
+)
+
+// NewMergedClient returns a Client that merges the values of several clients, e.g. file-based
+// clients for a base config file and environment-specific overlay files. clients are ordered
+// from lowest to highest precedence.
+//
+// Values are merged per key and constraints: if several clients have a value with the same
+// constraints for a key, the one from the client with the highest precedence is used and the
+// others are dropped, which is logged once per key and constraints. Values whose constraints only
+// appear in one client are kept, so an overlay can override a single namespace without repeating
+// the rest of the key.
+func NewMergedClient(logger log.Logger, clients ...Client) Client {
+	return &mergedClient{
+		clients: clients,
+		logger:  logger,
+	}
+}
+
+func (c *mergedClient) GetValue(key Key) []ConstrainedValue {
+	perClient := make([][]ConstrainedValue, len(c.clients))
+	for i, client := range c.clients {
+		perClient[i] = client.GetValue(key)
+	}
+	return mergeValues(perClient, func(cv ConstrainedValue) Constraints {
+		return cv.Constraints
+	}, func(cv ConstrainedValue) {
+		c.logConflict(key, cv)
+	})
+}
+
+func (c *mergedClient) traceValue(key Key) []TracedValue {
+	perClient := make([][]TracedValue, len(c.clients))
+	for i, client := range c.clients {
+		perClient[i] = traceClientValue(client, key)
+		for j := range perClient[i] {
+			perClient[i][j].Layer = fmt.Sprintf("merged[%d] > %s", i, perClient[i][j].Layer)
+		}
+	}
+	return mergeValues(perClient, func(tv TracedValue) Constraints {
+		return tv.Constraints
+	}, func(TracedValue) {})
+}
+
+// mergeValues merges the values of each client, ordered from lowest to highest precedence, see
+// NewMergedClient. onConflict is called for each value that is dropped.
+func mergeValues[V any](perClient [][]V, constraints func(V) Constraints, onConflict func(V)) []V {
+	var result []V
+	merged := 0
+	for i := len(perClient) - 1; i >= 0; i-- {
+		vs := perClient[i]
+		if len(vs) == 0 {
+			continue
+		}
+		merged++
+		if merged == 1 {
+			result = vs
+			continue
+		}
+		if merged == 2 {
+			// don't modify the slice returned by the first client
+			result = slices.Clone(result)
+		}
+		higherPrecedence := len(result)
+		for _, v := range vs {
+			if slices.ContainsFunc(result[:higherPrecedence], func(hv V) bool {
+				return constraints(hv) == constraints(v)
+			}) {
+				onConflict(v)
+				continue
+			}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Reload reloads the wrapped clients, if supported.
+func (c *mergedClient) Reload() error {
+	return reloadClients(c.clients...)
+}
+
+func (c *mergedClient) logConflict(key Key, cv ConstrainedValue) {
+	conflict := mergedClientConflict{key: strings.ToLower(key.String()), constraints: cv.Constraints}
+	if _, logged := c.loggedConflicts.LoadOrStore(conflict, struct{}{}); logged {
+		return
+	}
+	c.logger.Info("Dynamic config value is overridden by a client with higher precedence",
+		tag.Key(key.String()),
+		tag.NewAnyTag("constraints", cv.Constraints),
+		tag.IgnoredValue(cv.Value),
+	)
+}
@@ -0,0 +1,87 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+)
+
+type (
+	// MethodSet is a set of gRPC full method names, e.g.
+	// "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution".
+	// Names are matched case-sensitively, as gRPC does.
+	MethodSet map[string]struct{}
+
+	// unknownMethodsError is returned by the MethodSet converter, wrapped in a conversionWarning
+	// along with a usable value, when some configured names are not in the known set.
+	unknownMethodsError struct {
+		methods []string
+	}
+)
+
+// IsEnabled returns true if method is in the set.
+func (s MethodSet) IsEnabled(method string) bool {
+	_, ok := s[method]
+	return ok
+}
+
+// ConvertMethodSet can be used as a conversion function for New*TypedSettingWithConverter with a
+// MethodSet type. The value from dynamic config can be a list of method names or a single
+// comma-separated string.
+//
+// If knownMethods is not empty, names that are not in it are logged as a warning but still
+// included in the set, so that config for methods added in a newer server version can be rolled
+// out ahead of the binary.
+func ConvertMethodSet(knownMethods ...string) func(v any) (MethodSet, error) {
+	known := make(MethodSet, len(knownMethods))
+	for _, method := range knownMethods {
+		known[method] = struct{}{}
+	}
+	return func(v any) (MethodSet, error) {
+		if set, ok := v.(MethodSet); ok {
+			return set, nil
+		}
+		methods, err := convertStringSlice(v)
+		if err != nil {
+			return nil, err
+		}
+		set := make(MethodSet, len(methods))
+		var unknown []string
+		for _, method := range methods {
+			set[method] = struct{}{}
+			if len(known) > 0 && !known.IsEnabled(method) {
+				unknown = append(unknown, method)
+			}
+		}
+		if len(unknown) > 0 {
+			return set, newConversionWarning("Value contains unknown method names, keeping them", &unknownMethodsError{methods: unknown})
+		}
+		return set, nil
+	}
+}
+
+func (e *unknownMethodsError) Error() string {
+	return fmt.Sprintf("unknown method names %v", e.methods)
+}
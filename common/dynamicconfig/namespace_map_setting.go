@@ -0,0 +1,104 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"go.temporal.io/server/common/log/tag"
+)
+
+// GetNamespaceMap returns a property function for all values the setting has for specific
+// namespaces, keyed by namespace name. All values of the key are read at once, which suits
+// components that handle every namespace together, e.g. a rate limiter manager shared by all
+// namespaces, better than calling Get per namespace.
+//
+// Only values constrained by namespace alone are included, along with namespace constrained
+// defaults. Namespaces without such a value, or whose value can't be converted or isn't
+// accepted, are left out of the map, and Get should be used for them.
+func (s NamespaceTypedSetting[T]) GetNamespaceMap(c *Collection) TypedPropertyFn[map[string]T] {
+	return func() map[string]T {
+		return namespaceValues(c, s.key, s.cdef, s.convert, s.accept)
+	}
+}
+
+func namespaceValues[T any](
+	c *Collection,
+	key Key,
+	cdef []TypedConstrainedValue[T],
+	convert func(value any) (T, error),
+	accept func(T) bool,
+) map[string]T {
+	if c.trackReadKeys.Load() {
+		c.readKeys.LoadOrStore(key, struct{}{})
+	}
+	values := make(map[string]T)
+	for _, cv := range c.dropExpired(key, c.getValue(key)) {
+		namespace := cv.Constraints.Namespace
+		if !isNamespaceOnly(cv.Constraints) {
+			continue
+		}
+		if _, ok := values[namespace]; ok {
+			// like Get, the first value for a namespace wins
+			continue
+		}
+		val, _ := c.transformValue(key, cv.Value)
+		typedVal, convertErr := convertLenient(c, c.logger, key, val, convert)
+		if convertErr != nil {
+			if c.throttleLog() {
+				c.logger.Warn("Failed to convert value, leaving namespace out", tag.Key(key.String()), tag.WorkflowNamespace(namespace), tag.IgnoredValue(val), tag.Error(convertErr))
+			}
+			continue
+		}
+		if accept != nil && !accept(typedVal) {
+			if c.throttleLog() {
+				c.logger.Warn("Value not accepted by setting, leaving namespace out", tag.Key(key.String()), tag.WorkflowNamespace(namespace), tag.IgnoredValue(val))
+			}
+			continue
+		}
+		values[namespace] = typedVal
+	}
+	for _, cv := range cdef {
+		if _, ok := values[cv.Constraints.Namespace]; ok || !isNamespaceOnly(cv.Constraints) {
+			continue
+		}
+		typedVal := cv.Value
+		if transformed, ok := c.transformValue(key, typedVal); ok {
+			converted, convertErr := convertLenient(c, c.logger, key, transformed, convert)
+			if convertErr != nil {
+				if c.throttleLog() {
+					c.logger.Warn("Failed to convert transformed value, using default", tag.Key(key.String()), tag.IgnoredValue(transformed), tag.Error(convertErr))
+				}
+			} else {
+				typedVal = converted
+			}
+		}
+		values[cv.Constraints.Namespace] = typedVal
+	}
+	return values
+}
+
+// isNamespaceOnly returns true if cons only constrains the namespace.
+func isNamespaceOnly(cons Constraints) bool {
+	return cons.Namespace != "" && cons == Constraints{Namespace: cons.Namespace}
+}
@@ -0,0 +1,112 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	overlayClient struct {
+		client  Client
+		overlay map[string][]ConstrainedValue
+	}
+)
+
+// NewOverlayClient returns a Client that serves the given overrides on top of client. Each
+// override is a "key=value" string, e.g. from the server's --dynamic-config flag. Values are
+// decoded as yaml, the same way values in a dynamic config file are, so "10", "true", "5s" or
+// "[a, b]" are all accepted where the setting's converter can coerce them.
+//
+// An overridden key takes precedence over all values of that key in client, including
+// constrained ones. Keys that aren't registered and values that fail the setting's validation
+// are rejected, since these overrides are meant for quick local experiments where a typo should
+// fail loudly rather than be ignored.
+func NewOverlayClient(client Client, overrides []string) (Client, error) {
+	overlay := make(map[string][]ConstrainedValue, len(overrides))
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("dynamic config override %q must be key=value", override)
+		}
+		setting := queryRegistry(Key(key))
+		if setting == nil {
+			return nil, fmt.Errorf("dynamic config override: unregistered key %q", key)
+		}
+		val, err := parseOverlayValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("dynamic config override: key %q: %w", key, err)
+		}
+		if err := setting.Validate(val); err != nil {
+			return nil, fmt.Errorf("dynamic config override: key %q value %q: %w", key, value, err)
+		}
+		// later overrides of the same key replace earlier ones
+		overlay[strings.ToLower(key)] = []ConstrainedValue{{Value: val}}
+	}
+	return &overlayClient{
+		client:  client,
+		overlay: overlay,
+	}, nil
+}
+
+func (c *overlayClient) GetValue(key Key) []ConstrainedValue {
+	if cvs, ok := c.overlay[strings.ToLower(key.String())]; ok {
+		return cvs
+	}
+	return c.client.GetValue(key)
+}
+
+func (c *overlayClient) GetValueWithContext(ctx context.Context, key Key) []ConstrainedValue {
+	if cvs, ok := c.overlay[strings.ToLower(key.String())]; ok {
+		return cvs
+	}
+	return getClientValue(ctx, c.client, key)
+}
+
+func (c *overlayClient) traceValue(key Key) []TracedValue {
+	if cvs, ok := c.overlay[strings.ToLower(key.String())]; ok {
+		return tracedValues(cvs, "overlay")
+	}
+	return traceClientValue(c.client, key)
+}
+
+// Reload reloads the wrapped client, if supported.
+func (c *overlayClient) Reload() error {
+	return reloadClients(c.client)
+}
+
+func parseOverlayValue(value string) (any, error) {
+	var val any
+	if err := yaml.Unmarshal([]byte(value), &val); err != nil {
+		return nil, err
+	}
+	// like file values, maps from yaml need string keys for the converters
+	return convertKeyTypeToString(val)
+}
@@ -0,0 +1,131 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type (
+	// Rate is a rate limit setting value. Low rates are awkward to write as a number of requests
+	// per second, so in dynamic config it can also be written as "N/unit", where unit is one of s,
+	// m, h or d, e.g. "5/m" or "1000/h". A bare number is a number of requests per second.
+	Rate struct {
+		Count float64
+		Per   time.Duration
+	}
+
+	// RatePropertyFn returns a Rate that is global.
+	RatePropertyFn = TypedPropertyFn[Rate]
+)
+
+var rateUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+}
+
+// PerSecond returns a Rate of rps requests per second.
+func PerSecond(rps float64) Rate {
+	return Rate{Count: rps, Per: time.Second}
+}
+
+// ParseRate parses a rate written as "N/unit" or as a bare number of requests per second.
+func ParseRate(s string) (Rate, error) {
+	countStr, unit, hasUnit := strings.Cut(strings.TrimSpace(s), "/")
+	count, err := strconv.ParseFloat(strings.TrimSpace(countStr), 64)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+	if count < 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: negative count", s)
+	}
+	if !hasUnit {
+		return PerSecond(count), nil
+	}
+	per, ok := rateUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid rate %q: unknown unit %q, must be one of s, m, h or d", s, unit)
+	}
+	return Rate{Count: count, Per: per}, nil
+}
+
+// MustParseRate is ParseRate for setting defaults. It panics on error.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// RPS returns the rate in requests per second.
+func (r Rate) RPS() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+	return r.Count / r.Per.Seconds()
+}
+
+// Limiter returns a rate limiter that allows the rate with the given burst.
+func (r Rate) Limiter(burst int) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(r.RPS()), burst)
+}
+
+func (r Rate) String() string {
+	for _, unit := range []string{"s", "m", "h", "d"} {
+		if rateUnits[unit] == r.Per {
+			return strconv.FormatFloat(r.Count, 'g', -1, 64) + "/" + unit
+		}
+	}
+	return strconv.FormatFloat(r.RPS(), 'g', -1, 64) + "/s"
+}
+
+// ConvertRate can be used as a conversion function for New*TypedSettingWithConverter with a Rate
+// type. The value from dynamic config can be a number of requests per second, as for float
+// settings, or a string accepted by ParseRate. Invalid values fall back to the setting's default.
+func ConvertRate(v any) (Rate, error) {
+	switch v := v.(type) {
+	case Rate:
+		return v, nil
+	case string:
+		return ParseRate(v)
+	}
+	rps, err := convertFloat(v)
+	if err != nil {
+		return Rate{}, err
+	}
+	if rps < 0 {
+		return Rate{}, errors.New("negative rate")
+	}
+	return PerSecond(rps), nil
+}
@@ -26,6 +26,7 @@ package dynamicconfig
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync/atomic"
 )
@@ -62,6 +63,19 @@ func queryRegistry(k Key) GenericSetting {
 	return globalRegistry.settings[strings.ToLower(k.String())]
 }
 
+// registeredKeys returns the keys of all registered settings, sorted.
+func registeredKeys() []Key {
+	if !globalRegistry.queried.Load() {
+		globalRegistry.queried.Store(true)
+	}
+	keys := make([]Key, 0, len(globalRegistry.settings))
+	for _, s := range globalRegistry.settings {
+		keys = append(keys, s.Key())
+	}
+	slices.Sort(keys)
+	return keys
+}
+
 // For testing only; do not call from regular code!
 func ResetRegistryForTest() {
 	globalRegistry.settings = nil
@@ -0,0 +1,150 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+type (
+	// ReloadableClient is a Client that can be asked to pick up changes to its values right
+	// away instead of on its own schedule, see Collection.Reload.
+	ReloadableClient interface {
+		Client
+		Reload() error
+	}
+
+	// ValueChange is a change of one value of a key, i.e. of the value for one set of
+	// constraints. Old is nil if the value was added, and New is nil if it was removed.
+	ValueChange struct {
+		Key         Key
+		Constraints Constraints
+		Old         *ConstrainedValue
+		New         *ConstrainedValue
+	}
+
+	// reloadState is the snapshot of all registered keys taken by the last Collection.Reload.
+	reloadState struct {
+		sync.Mutex
+		snapshot map[Key][]ConstrainedValue
+	}
+)
+
+// Reload makes the client pick up changes to dynamic config right away, if it supports that,
+// and returns the values of registered keys that changed since the previous call. For the
+// file-based client, this re-reads the file even if it doesn't look modified. Clients that are
+// updated by pushes can't be reloaded, but the changes pushed since the previous call are still
+// reported. The first call only reports the changes made by the reload itself. Values bound with
+// GlobalTypedSetting.Bind are updated, and gauges exported with ExportValueGauge emitted, before
+// Reload returns.
+//
+// Changes are ordered by key. Values of keys that are not registered are not compared, since the
+// server never reads them.
+func (c *Collection) Reload() ([]ValueChange, error) {
+	c.reload.Lock()
+	defer c.reload.Unlock()
+
+	previous := c.reload.snapshot
+	if previous == nil {
+		previous = c.snapshot()
+	}
+	if reloadable, ok := c.client.(ReloadableClient); ok {
+		if err := reloadable.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	current := c.snapshot()
+	c.reload.snapshot = current
+	c.bindings.refresh()
+
+	var changes []ValueChange
+	for _, key := range registeredKeys() {
+		changes = append(changes, diffConstrainedValues(key, previous[key], current[key])...)
+	}
+	return changes, nil
+}
+
+func (c *Collection) snapshot() map[Key][]ConstrainedValue {
+	snapshot := make(map[Key][]ConstrainedValue)
+	for _, key := range registeredKeys() {
+		if cvs := c.getValue(key); len(cvs) > 0 {
+			snapshot[key] = cvs
+		}
+	}
+	return snapshot
+}
+
+// reloadClients reloads all clients that support it, for clients that wrap others.
+func reloadClients(clients ...Client) error {
+	var errs []error
+	for _, client := range clients {
+		if reloadable, ok := client.(ReloadableClient); ok {
+			errs = append(errs, reloadable.Reload())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// diffConstrainedValues compares the values of a key by constraints. Like the Collection, it
+// only considers the first of several values with the same constraints.
+func diffConstrainedValues(key Key, oldValues, newValues []ConstrainedValue) []ValueChange {
+	var changes []ValueChange
+	for i, oldValue := range oldValues {
+		if isShadowed(oldValues, i) {
+			continue
+		}
+		newValue := findConstraints(newValues, oldValue.Constraints)
+		if newValue == nil {
+			changes = append(changes, ValueChange{Key: key, Constraints: oldValue.Constraints, Old: &oldValues[i]})
+		} else if !reflect.DeepEqual(oldValue.Value, newValue.Value) || !oldValue.ExpiresAt.Equal(newValue.ExpiresAt) {
+			changes = append(changes, ValueChange{Key: key, Constraints: oldValue.Constraints, Old: &oldValues[i], New: newValue})
+		}
+	}
+	for i, newValue := range newValues {
+		if isShadowed(newValues, i) {
+			continue
+		}
+		if findConstraints(oldValues, newValue.Constraints) == nil {
+			changes = append(changes, ValueChange{Key: key, Constraints: newValue.Constraints, New: &newValues[i]})
+		}
+	}
+	return changes
+}
+
+// isShadowed returns true if cvs has a value with the same constraints as cvs[i] before it.
+func isShadowed(cvs []ConstrainedValue, i int) bool {
+	return findConstraints(cvs[:i], cvs[i].Constraints) != nil
+}
+
+func findConstraints(cvs []ConstrainedValue, constraints Constraints) *ConstrainedValue {
+	for i := range cvs {
+		if cvs[i].Constraints == constraints {
+			return &cvs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+
+	"go.temporal.io/server/api/dynamicconfigservice/v1"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+var _ Client = (*remoteClient)(nil)
+
+const (
+	defaultRemoteRetryInterval       = 5 * time.Second
+	defaultRemoteInitialFetchTimeout = 10 * time.Second
+)
+
+type (
+	// RemoteClientConfig is the config for the dynamic config client that reads values from a
+	// DynamicConfigService. The values of a key are in the same format as a single key of the
+	// dynamic config file, see ConsulClientConfig.
+	RemoteClientConfig struct {
+		// Address is the gRPC target of the service.
+		Address string `yaml:"address"`
+		// Identity is sent to the service to identify the server. Defaults to the hostname.
+		Identity string `yaml:"identity"`
+		// CacheFile, if set, is where the last values received from the service are kept, so
+		// that they can be served if the service is unreachable when the server starts.
+		CacheFile string `yaml:"cacheFile"`
+		// RetryInterval is the time between attempts to reconnect to the service. Defaults to
+		// 5 seconds.
+		RetryInterval time.Duration `yaml:"retryInterval"`
+		// InitialFetchTimeout is how long the client waits for the first values from the
+		// service before falling back to CacheFile. Defaults to 10 seconds.
+		InitialFetchTimeout time.Duration `yaml:"initialFetchTimeout"`
+	}
+
+	remoteClient struct {
+		values   atomic.Value // configValueMap
+		logger   log.Logger
+		service  dynamicconfigservice.DynamicConfigServiceClient
+		config   RemoteClientConfig
+		doneCh   <-chan interface{}
+		loadedCh chan struct{}
+		loaded   sync.Once
+
+		// raw holds the encoded values of the keys that decoded successfully, for CacheFile.
+		// It's only accessed by the watch.
+		raw map[string][]byte
+	}
+)
+
+// NewRemoteClient creates a dynamic config client that reads values from a DynamicConfigService
+// and keeps them up to date with the changes the service pushes. When the service is
+// unreachable, the last values received are served. dialOptions must include the transport
+// credentials for the connection, e.g. grpc.WithTransportCredentials.
+func NewRemoteClient(
+	config *RemoteClientConfig,
+	logger log.Logger,
+	doneCh <-chan interface{},
+	dialOptions ...grpc.DialOption,
+) (*remoteClient, error) {
+	if config == nil {
+		return nil, errors.New("configuration for dynamic config client is nil")
+	}
+	conn, err := grpc.Dial(config.Address, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create dynamic config service client: %w", err)
+	}
+	client, err := NewRemoteClientWithServiceClient(dynamicconfigservice.NewDynamicConfigServiceClient(conn), config, logger, doneCh)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	go func() {
+		<-doneCh
+		_ = conn.Close()
+	}()
+	return client, nil
+}
+
+func NewRemoteClientWithServiceClient(
+	service dynamicconfigservice.DynamicConfigServiceClient,
+	config *RemoteClientConfig,
+	logger log.Logger,
+	doneCh <-chan interface{},
+) (*remoteClient, error) {
+	if config == nil {
+		return nil, errors.New("configuration for dynamic config client is nil")
+	}
+	client := &remoteClient{
+		logger:   logger,
+		service:  service,
+		config:   *config,
+		doneCh:   doneCh,
+		loadedCh: make(chan struct{}),
+		raw:      make(map[string][]byte),
+	}
+	if client.config.Identity == "" {
+		client.config.Identity, _ = os.Hostname()
+	}
+	if client.config.RetryInterval <= 0 {
+		client.config.RetryInterval = defaultRemoteRetryInterval
+	}
+	if client.config.InitialFetchTimeout <= 0 {
+		client.config.InitialFetchTimeout = defaultRemoteInitialFetchTimeout
+	}
+
+	err := client.init()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (rc *remoteClient) GetValue(key Key) []ConstrainedValue {
+	values := rc.values.Load().(configValueMap)
+	return values[strings.ToLower(key.String())]
+}
+
+func (rc *remoteClient) init() error {
+	cached, cacheErr := rc.loadCacheFile()
+	if cacheErr != nil {
+		rc.logger.Warn("Unable to read dynamic config cache file.", tag.Error(cacheErr))
+	}
+	rc.values.Store(configValueMap{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-rc.doneCh
+		cancel()
+	}()
+	go rc.watch(ctx)
+
+	select {
+	case <-rc.loadedCh:
+		return nil
+	case <-time.After(rc.config.InitialFetchTimeout):
+	case <-ctx.Done():
+		return errors.New("dynamic config client stopped before reading dynamic config")
+	}
+	if cached == nil {
+		cancel()
+		return fmt.Errorf("unable to read dynamic config from %q within %v", rc.config.Address, rc.config.InitialFetchTimeout)
+	}
+	// the watch hasn't applied any values yet, and won't until the service is reachable
+	rc.loaded.Do(func() {
+		rc.raw = cached.raw
+		rc.values.Store(cached.values)
+		close(rc.loadedCh)
+	})
+	rc.logger.Warn("Dynamic config service is unreachable, serving cached dynamic config.",
+		tag.NewStringTag("cache-file", rc.config.CacheFile))
+	return nil
+}
+
+// watch streams the values from the service until ctx is canceled. Whenever the stream ends, a
+// new one is opened after RetryInterval, and its first snapshot replaces the values. In the
+// meantime, the last values received are served.
+func (rc *remoteClient) watch(ctx context.Context) {
+	for {
+		err := rc.watchOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		rc.logger.Warn("Dynamic config service stream ended, reconnecting.", tag.Error(err))
+		select {
+		case <-time.After(rc.config.RetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (rc *remoteClient) watchOnce(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := rc.service.WatchDynamicConfig(ctx, &dynamicconfigservice.WatchDynamicConfigRequest{
+		Identity: rc.config.Identity,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		rc.apply(resp)
+	}
+}
+
+// apply applies a response of the service to the current values. A key whose value can't be
+// decoded keeps its previous values and does not affect any other key.
+func (rc *remoteClient) apply(resp *dynamicconfigservice.WatchDynamicConfigResponse) {
+	// the cached values must not be replaced by the initial fetch once they're being served
+	rc.loaded.Do(func() { close(rc.loadedCh) })
+
+	oldValues := rc.values.Load().(configValueMap)
+	oldRaw := rc.raw
+	var newValues configValueMap
+	if resp.GetSnapshot() {
+		newValues = make(configValueMap, len(resp.GetValues()))
+		rc.raw = make(map[string][]byte, len(resp.GetValues()))
+	} else {
+		newValues = maps.Clone(oldValues)
+		rc.raw = maps.Clone(oldRaw)
+		for _, key := range resp.GetDeletedKeys() {
+			lowerKey := strings.ToLower(key)
+			delete(newValues, lowerKey)
+			delete(rc.raw, lowerKey)
+		}
+	}
+	for key, data := range resp.GetValues() {
+		lowerKey := strings.ToLower(key)
+		cvs, ok := decodeKeyValues(rc.logger, key, data)
+		if !ok {
+			if prev, ok := oldValues[lowerKey]; ok {
+				newValues[lowerKey] = prev
+				rc.raw[lowerKey] = oldRaw[lowerKey]
+			}
+			continue
+		}
+		newValues[lowerKey] = cvs
+		rc.raw[lowerKey] = data
+	}
+
+	rc.values.Store(newValues)
+	logDiff(rc.logger, oldValues, newValues)
+	rc.logger.Info("Updated dynamic config")
+
+	if err := rc.writeCacheFile(); err != nil {
+		rc.logger.Warn("Unable to write dynamic config cache file.", tag.Error(err))
+	}
+}
+
+type remoteClientCache struct {
+	values configValueMap
+	raw    map[string][]byte
+}
+
+// loadCacheFile reads the values of CacheFile. It returns nil if there is no cache file.
+func (rc *remoteClient) loadCacheFile() (*remoteClientCache, error) {
+	if rc.config.CacheFile == "" {
+		return nil, nil
+	}
+	contents, err := os.ReadFile(rc.config.CacheFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var encoded map[string]string
+	if err := yaml.Unmarshal(contents, &encoded); err != nil {
+		return nil, fmt.Errorf("dynamic config cache file %q: decode error: %w", rc.config.CacheFile, err)
+	}
+	cache := &remoteClientCache{
+		values: make(configValueMap, len(encoded)),
+		raw:    make(map[string][]byte, len(encoded)),
+	}
+	for key, data := range encoded {
+		lowerKey := strings.ToLower(key)
+		if cvs, ok := decodeKeyValues(rc.logger, key, []byte(data)); ok {
+			cache.values[lowerKey] = cvs
+			cache.raw[lowerKey] = []byte(data)
+		}
+	}
+	return cache, nil
+}
+
+// writeCacheFile replaces CacheFile with the current values. The file is replaced with a rename
+// so that a server starting concurrently never reads a partial file.
+func (rc *remoteClient) writeCacheFile() error {
+	if rc.config.CacheFile == "" {
+		return nil
+	}
+	encoded := make(map[string]string, len(rc.raw))
+	for key, data := range rc.raw {
+		encoded[key] = string(data)
+	}
+	contents, err := yaml.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(rc.config.CacheFile), filepath.Base(rc.config.CacheFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(contents); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), rc.config.CacheFile)
+}
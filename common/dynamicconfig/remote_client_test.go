@@ -0,0 +1,244 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"go.temporal.io/server/api/dynamicconfigservice/v1"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	remoteClientSuite struct {
+		suite.Suite
+		*require.Assertions
+		address string
+		server  *grpc.Server
+		service *testDynamicConfigService
+		doneCh  chan interface{}
+	}
+
+	testDynamicConfigService struct {
+		dynamicconfigservice.UnimplementedDynamicConfigServiceServer
+		snapshot map[string][]byte
+		updates  chan *dynamicconfigservice.WatchDynamicConfigResponse
+	}
+)
+
+func TestRemoteClientSuite(t *testing.T) {
+	s := new(remoteClientSuite)
+	suite.Run(t, s)
+}
+
+func (s *remoteClientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	dynamicconfig.ResetRegistryForTest()
+	s.doneCh = make(chan interface{})
+	s.address = "127.0.0.1:0"
+	s.startService(map[string][]byte{})
+}
+
+func (s *remoteClientSuite) TearDownTest() {
+	close(s.doneCh)
+	s.server.Stop()
+}
+
+// startService starts a service on s.address that sends snapshot to every new stream.
+func (s *remoteClientSuite) startService(snapshot map[string][]byte) {
+	listener, err := net.Listen("tcp", s.address)
+	s.NoError(err)
+	s.address = listener.Addr().String()
+	s.service = &testDynamicConfigService{
+		snapshot: snapshot,
+		updates:  make(chan *dynamicconfigservice.WatchDynamicConfigResponse),
+	}
+	s.server = grpc.NewServer()
+	dynamicconfigservice.RegisterDynamicConfigServiceServer(s.server, s.service)
+	go func() { _ = s.server.Serve(listener) }()
+}
+
+func (s *remoteClientSuite) newClient(cacheFile string) (dynamicconfig.Client, error) {
+	return dynamicconfig.NewRemoteClient(&dynamicconfig.RemoteClientConfig{
+		Address:             s.address,
+		CacheFile:           cacheFile,
+		RetryInterval:       10 * time.Millisecond,
+		InitialFetchTimeout: 500 * time.Millisecond,
+	}, log.NewNoopLogger(), s.doneCh, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+func (s *remoteClientSuite) push(resp *dynamicconfigservice.WatchDynamicConfigResponse) {
+	select {
+	case s.service.updates <- resp:
+	case <-time.After(5 * time.Second):
+		s.FailNow("update was not received by the client")
+	}
+}
+
+func (s *remoteClientSuite) TestGetValue() {
+	setting := dynamicconfig.NewNamespaceIntSetting(testGetIntPropertyKey, 10, "")
+	s.service.snapshot = map[string][]byte{
+		testGetIntPropertyKey: []byte(`
+- value: 100
+  constraints:
+    namespace: samples-namespace
+- value: 50
+`),
+		testGetStringPropertyKey:  []byte("- value: abc"),
+		testGetFloat64PropertyKey: []byte("not: [valid"),
+	}
+
+	client, err := s.newClient("")
+	s.NoError(err)
+	s.ElementsMatch([]dynamicconfig.ConstrainedValue{
+		{Constraints: dynamicconfig.Constraints{Namespace: "samples-namespace"}, Value: 100},
+		{Value: 50},
+	}, client.GetValue(testGetIntPropertyKey))
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: "abc"}}, client.GetValue(testGetStringPropertyKey))
+	// an invalid key doesn't affect the others
+	s.Nil(client.GetValue(testGetFloat64PropertyKey))
+
+	collection := dynamicconfig.NewCollection(client, log.NewNoopLogger())
+	s.Equal(100, setting.Get(collection)("samples-namespace"))
+	s.Equal(50, setting.Get(collection)("other-namespace"))
+}
+
+func (s *remoteClientSuite) TestPushUpdates() {
+	s.service.snapshot = map[string][]byte{
+		testGetIntPropertyKey:    []byte("- value: 100"),
+		testGetStringPropertyKey: []byte("- value: abc"),
+	}
+	client, err := s.newClient("")
+	s.NoError(err)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+
+	s.push(&dynamicconfigservice.WatchDynamicConfigResponse{
+		Values: map[string][]byte{testGetIntPropertyKey: []byte("- value: 200")},
+	})
+	s.Eventually(func() bool {
+		v := client.GetValue(testGetIntPropertyKey)
+		return len(v) == 1 && v[0].Value == 200
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: "abc"}}, client.GetValue(testGetStringPropertyKey))
+
+	// an invalid update keeps the previous values
+	s.push(&dynamicconfigservice.WatchDynamicConfigResponse{
+		Values: map[string][]byte{
+			testGetIntPropertyKey:  []byte("not: [valid"),
+			testGetBoolPropertyKey: []byte("- value: true"),
+		},
+		DeletedKeys: []string{testGetStringPropertyKey},
+	})
+	s.Eventually(func() bool {
+		return client.GetValue(testGetBoolPropertyKey) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 200}}, client.GetValue(testGetIntPropertyKey))
+	s.Nil(client.GetValue(testGetStringPropertyKey))
+
+	// a snapshot replaces all values
+	s.push(&dynamicconfigservice.WatchDynamicConfigResponse{
+		Snapshot: true,
+		Values:   map[string][]byte{testGetStringPropertyKey: []byte("- value: def")},
+	})
+	s.Eventually(func() bool {
+		return client.GetValue(testGetStringPropertyKey) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Nil(client.GetValue(testGetIntPropertyKey))
+	s.Nil(client.GetValue(testGetBoolPropertyKey))
+}
+
+func (s *remoteClientSuite) TestServiceDown() {
+	s.service.snapshot = map[string][]byte{testGetIntPropertyKey: []byte("- value: 100")}
+	client, err := s.newClient("")
+	s.NoError(err)
+
+	// the last values are served while the service is down
+	s.server.Stop()
+	time.Sleep(50 * time.Millisecond)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+
+	// and replaced once it's back
+	s.startService(map[string][]byte{testGetIntPropertyKey: []byte("- value: 200")})
+	s.Eventually(func() bool {
+		v := client.GetValue(testGetIntPropertyKey)
+		return len(v) == 1 && v[0].Value == 200
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *remoteClientSuite) TestServiceDown_CacheFile() {
+	cacheFile := filepath.Join(s.T().TempDir(), "dynamicconfig.yaml")
+	s.service.snapshot = map[string][]byte{testGetIntPropertyKey: []byte("- value: 100")}
+	client, err := s.newClient(cacheFile)
+	s.NoError(err)
+	s.push(&dynamicconfigservice.WatchDynamicConfigResponse{
+		Values: map[string][]byte{testGetStringPropertyKey: []byte("- value: abc")},
+	})
+	s.Eventually(func() bool {
+		return client.GetValue(testGetStringPropertyKey) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a server starting while the service is down serves the cached values
+	s.server.Stop()
+	client, err = s.newClient(cacheFile)
+	s.NoError(err)
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: 100}}, client.GetValue(testGetIntPropertyKey))
+	s.Equal([]dynamicconfig.ConstrainedValue{{Value: "abc"}}, client.GetValue(testGetStringPropertyKey))
+
+	// without a cache file it fails to start
+	_, err = s.newClient(filepath.Join(s.T().TempDir(), "dynamicconfig.yaml"))
+	s.Error(err)
+}
+
+func (t *testDynamicConfigService) WatchDynamicConfig(
+	_ *dynamicconfigservice.WatchDynamicConfigRequest,
+	stream dynamicconfigservice.DynamicConfigService_WatchDynamicConfigServer,
+) error {
+	err := stream.Send(&dynamicconfigservice.WatchDynamicConfigResponse{
+		Snapshot: true,
+		Values:   t.snapshot,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case resp := <-t.updates:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
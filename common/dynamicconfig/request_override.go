@@ -0,0 +1,153 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/common/log/tag"
+)
+
+type (
+	requestOverridesKey struct{}
+)
+
+// RequestOverrideHeaderName is the request header that carries dynamic config overrides for a
+// single request, as "key=value" strings, one per header value. Values are decoded as yaml, like
+// values in a dynamic config file.
+const RequestOverrideHeaderName = "dynamic-config-override"
+
+// SetRequestOverrideAllowlist sets the keys that can be overridden per request, replacing the
+// previous allowlist. By default no key can be overridden. Keep the list short, and to settings
+// that are harmless to change for a single request, e.g. debug logging or tracing.
+func (c *Collection) SetRequestOverrideAllowlist(keys ...Key) {
+	allowlist := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		allowlist[strings.ToLower(key.String())] = struct{}{}
+	}
+	c.requestOverrideAllowlist.Store(&allowlist)
+}
+
+func (c *Collection) isRequestOverrideAllowed(key Key) bool {
+	allowlist := c.requestOverrideAllowlist.Load()
+	if allowlist == nil {
+		return false
+	}
+	_, ok := (*allowlist)[strings.ToLower(key.String())]
+	return ok
+}
+
+// ContextWithRequestOverrides returns a context that carries dynamic config overrides for the
+// request it belongs to, adding to and replacing overrides already in ctx.
+func ContextWithRequestOverrides(ctx context.Context, overrides map[Key]any) context.Context {
+	merged := make(map[string]any)
+	if existing, ok := ctx.Value(requestOverridesKey{}).(map[string]any); ok {
+		maps.Copy(merged, existing)
+	}
+	for key, value := range overrides {
+		merged[strings.ToLower(key.String())] = value
+	}
+	return context.WithValue(ctx, requestOverridesKey{}, merged)
+}
+
+// ContextWithRequestOverridesFromHeaders returns a context that carries the overrides in the
+// RequestOverrideHeaderName header of the incoming request. Malformed overrides are ignored.
+//
+// Anyone can set request headers, so this must only be called once the caller is known to be
+// allowed to override dynamic config, e.g. by an interceptor that checks for admin claims.
+func ContextWithRequestOverridesFromHeaders(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	overrides := make(map[Key]any)
+	for _, override := range md.Get(RequestOverrideHeaderName) {
+		key, value, ok := strings.Cut(override, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		val, err := parseOverlayValue(value)
+		if err != nil {
+			continue
+		}
+		overrides[Key(key)] = val
+	}
+	if len(overrides) == 0 {
+		return ctx
+	}
+	return ContextWithRequestOverrides(ctx, overrides)
+}
+
+// WithRequestOverrides makes r use the request overrides in ctx, see ContextWithRequestOverrides,
+// in preference to all other values of the settings it resolves. Only the keys allowed by
+// Collection.SetRequestOverrideAllowlist can be overridden. Overrides that can't be converted to
+// the setting's type, or that the setting doesn't accept, are ignored.
+func (r *GroupResolver) WithRequestOverrides(ctx context.Context) *GroupResolver {
+	r.overrides, _ = ctx.Value(requestOverridesKey{}).(map[string]any)
+	return r
+}
+
+// requestOverride returns the request override of key in r, if there is an allowed and valid one.
+func requestOverride[T any](
+	r *GroupResolver,
+	key Key,
+	convert func(value any) (T, error),
+	accept func(T) bool,
+) (T, bool) {
+	var zero T
+	if len(r.overrides) == 0 {
+		return zero, false
+	}
+	val, ok := r.overrides[strings.ToLower(key.String())]
+	if !ok {
+		return zero, false
+	}
+	c := r.c
+	if !c.isRequestOverrideAllowed(key) {
+		if c.throttleLog() {
+			c.logger.Warn("Request override of dynamic config key is not allowed, ignoring it", tag.Key(key.String()))
+		}
+		return zero, false
+	}
+	typedVal, err := convertLenient(c, c.logger, key, val, convert)
+	if err != nil {
+		if c.throttleLog() {
+			c.logger.Warn("Failed to convert request override, ignoring it", tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(err))
+		}
+		return zero, false
+	}
+	if accept != nil && !accept(typedVal) {
+		if c.throttleLog() {
+			c.logger.Warn("Request override not accepted by setting, ignoring it", tag.Key(key.String()), tag.IgnoredValue(val))
+		}
+		return zero, false
+	}
+	return typedVal, true
+}
@@ -0,0 +1,79 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"sync"
+
+	"github.com/blang/semver/v4"
+
+	"go.temporal.io/server/common/headers"
+)
+
+// sdkVersionRanges caches parsed SDKVersion constraints of values, since they're matched on
+// every read. Ranges that can't be parsed are cached as nil.
+var sdkVersionRanges sync.Map // string -> semver.Range
+
+// GetWithContext returns a property function that resolves the setting for the SDK of the caller,
+// taken from the client name and version headers of the request in ctx.
+func (s SDKVersionTypedSetting[T]) GetWithContext(c *Collection) func(ctx context.Context, namespace string) T {
+	get := s.Get(c)
+	return func(ctx context.Context, namespace string) T {
+		sdkName, sdkVersion := headers.GetClientNameAndVersion(ctx)
+		return get(namespace, sdkName, sdkVersion)
+	}
+}
+
+// constraintsMatch returns true if the constraints the server is checking match the constraints
+// of a value. They must be equal, except that the checked SDKVersion must be in the semver range
+// of the value's SDKVersion.
+func constraintsMatch(checked, value Constraints) bool {
+	if checked.SDKVersion == "" || value.SDKVersion == "" {
+		return checked == value
+	}
+	if !sdkVersionInRange(checked.SDKVersion, value.SDKVersion) {
+		return false
+	}
+	checked.SDKVersion, value.SDKVersion = "", ""
+	return checked == value
+}
+
+// sdkVersionInRange returns false if either the version or the range is invalid.
+func sdkVersionInRange(version string, versionRange string) bool {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return false
+	}
+	r, ok := sdkVersionRanges.Load(versionRange)
+	if !ok {
+		parsed, err := semver.ParseRange(versionRange)
+		if err != nil {
+			parsed = nil
+		}
+		r, _ = sdkVersionRanges.LoadOrStore(versionRange, parsed)
+	}
+	return r.(semver.Range) != nil && r.(semver.Range)(v)
+}
@@ -26,6 +26,10 @@
 
 package dynamicconfig
 
+import (
+	"context"
+)
+
 type (
 	// Precedence is an enum for the search order precedence of a dynamic config setting.
 	// E.g., use the global value, check namespace then global, check task queue then
@@ -42,6 +46,8 @@ type (
 		def         T   // default value. cdef is used in preference to def if non-nil.
 		cdef        []TypedConstrainedValue[T]
 		convert     func(any) (T, error) // converter function
+		bounds      *SettingBounds       // valid range of values, nil if unbounded
+		accept      func(T) bool         // optional predicate for matched values, see WithAccept
 		description string               // documentation
 	}
 
@@ -52,5 +58,15 @@ type (
 		Key() Key
 		Precedence() Precedence
 		Validate(v any) error
+		Bounds() *SettingBounds
+	}
+
+	// ConstrainedSetting is implemented by all settings with value type T, regardless of
+	// precedence, so that they can be resolved against a Constraints struct directly, or traced,
+	// see Trace.
+	ConstrainedSetting[T any] interface {
+		GenericSetting
+		resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T
+		trace(c *Collection, cons Constraints) TraceResult[T]
 	}
 )
@@ -27,10 +27,12 @@
 package dynamicconfig
 
 import (
+	"context"
 	"time"
 
 	enumspb "go.temporal.io/api/enums/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/common/log"
 )
 
 const PrecedenceUnknown Precedence = 0
@@ -49,6 +51,10 @@ const PrecedenceTaskType Precedence = 6
 
 const PrecedenceDestination Precedence = 7
 
+const PrecedenceWorkflowType Precedence = 8
+
+const PrecedenceSDKVersion Precedence = 9
+
 type GlobalBoolSetting = GlobalTypedSetting[bool]
 
 func NewGlobalBoolSetting(key Key, def bool, description string) GlobalBoolSetting {
@@ -161,12 +167,58 @@ func GetBoolPropertyFnFilteredByDestination(value bool) BoolPropertyFnWithDestin
 	return GetTypedPropertyFnFilteredByDestination(value)
 }
 
+type WorkflowTypeBoolSetting = WorkflowTypeTypedSetting[bool]
+
+func NewWorkflowTypeBoolSetting(key Key, def bool, description string) WorkflowTypeBoolSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[bool](key, convertBool, def, description)
+}
+
+func NewWorkflowTypeBoolSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[bool], description string) WorkflowTypeBoolSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[bool](key, convertBool, cdef, description)
+}
+
+type BoolPropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[bool]
+
+func GetBoolPropertyFnFilteredByWorkflowType(value bool) BoolPropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionBoolSetting = SDKVersionTypedSetting[bool]
+
+func NewSDKVersionBoolSetting(key Key, def bool, description string) SDKVersionBoolSetting {
+	return NewSDKVersionTypedSettingWithConverter[bool](key, convertBool, def, description)
+}
+
+func NewSDKVersionBoolSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[bool], description string) SDKVersionBoolSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[bool](key, convertBool, cdef, description)
+}
+
+type BoolPropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[bool]
+
+func GetBoolPropertyFnFilteredBySDKVersion(value bool) BoolPropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
 type GlobalIntSetting = GlobalTypedSetting[int]
 
 func NewGlobalIntSetting(key Key, def int, description string) GlobalIntSetting {
 	return NewGlobalTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewGlobalIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewGlobalIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) GlobalIntSetting {
+	s := GlobalIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewGlobalIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) GlobalIntSetting {
 	return NewGlobalTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -183,6 +235,20 @@ func NewNamespaceIntSetting(key Key, def int, description string) NamespaceIntSe
 	return NewNamespaceTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewNamespaceIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewNamespaceIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) NamespaceIntSetting {
+	s := NamespaceIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewNamespaceIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) NamespaceIntSetting {
 	return NewNamespaceTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -199,6 +265,20 @@ func NewNamespaceIDIntSetting(key Key, def int, description string) NamespaceIDI
 	return NewNamespaceIDTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewNamespaceIDIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewNamespaceIDIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) NamespaceIDIntSetting {
+	s := NamespaceIDIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewNamespaceIDIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) NamespaceIDIntSetting {
 	return NewNamespaceIDTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -215,6 +295,20 @@ func NewTaskQueueIntSetting(key Key, def int, description string) TaskQueueIntSe
 	return NewTaskQueueTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewTaskQueueIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewTaskQueueIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) TaskQueueIntSetting {
+	s := TaskQueueIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewTaskQueueIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) TaskQueueIntSetting {
 	return NewTaskQueueTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -231,6 +325,20 @@ func NewShardIDIntSetting(key Key, def int, description string) ShardIDIntSettin
 	return NewShardIDTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewShardIDIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewShardIDIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) ShardIDIntSetting {
+	s := ShardIDIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewShardIDIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) ShardIDIntSetting {
 	return NewShardIDTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -247,6 +355,20 @@ func NewTaskTypeIntSetting(key Key, def int, description string) TaskTypeIntSett
 	return NewTaskTypeTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewTaskTypeIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewTaskTypeIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) TaskTypeIntSetting {
+	s := TaskTypeIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewTaskTypeIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) TaskTypeIntSetting {
 	return NewTaskTypeTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -263,6 +385,20 @@ func NewDestinationIntSetting(key Key, def int, description string) DestinationI
 	return NewDestinationTypedSettingWithConverter[int](key, convertInt, def, description)
 }
 
+// NewDestinationIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewDestinationIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) DestinationIntSetting {
+	s := DestinationIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewDestinationIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) DestinationIntSetting {
 	return NewDestinationTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
 }
@@ -273,12 +409,86 @@ func GetIntPropertyFnFilteredByDestination(value int) IntPropertyFnWithDestinati
 	return GetTypedPropertyFnFilteredByDestination(value)
 }
 
+type WorkflowTypeIntSetting = WorkflowTypeTypedSetting[int]
+
+func NewWorkflowTypeIntSetting(key Key, def int, description string) WorkflowTypeIntSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[int](key, convertInt, def, description)
+}
+
+// NewWorkflowTypeIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewWorkflowTypeIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) WorkflowTypeIntSetting {
+	s := WorkflowTypeIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewWorkflowTypeIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) WorkflowTypeIntSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
+}
+
+type IntPropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[int]
+
+func GetIntPropertyFnFilteredByWorkflowType(value int) IntPropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionIntSetting = SDKVersionTypedSetting[int]
+
+func NewSDKVersionIntSetting(key Key, def int, description string) SDKVersionIntSetting {
+	return NewSDKVersionTypedSettingWithConverter[int](key, convertInt, def, description)
+}
+
+// NewSDKVersionIntSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewSDKVersionIntSettingWithBounds(key Key, def int, bounds Bounds[int], description string) SDKVersionIntSetting {
+	s := SDKVersionIntSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertInt, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewSDKVersionIntSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[int], description string) SDKVersionIntSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[int](key, convertInt, cdef, description)
+}
+
+type IntPropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[int]
+
+func GetIntPropertyFnFilteredBySDKVersion(value int) IntPropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
 type GlobalFloatSetting = GlobalTypedSetting[float64]
 
 func NewGlobalFloatSetting(key Key, def float64, description string) GlobalFloatSetting {
 	return NewGlobalTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewGlobalFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewGlobalFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) GlobalFloatSetting {
+	s := GlobalFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewGlobalFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) GlobalFloatSetting {
 	return NewGlobalTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -295,6 +505,20 @@ func NewNamespaceFloatSetting(key Key, def float64, description string) Namespac
 	return NewNamespaceTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewNamespaceFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewNamespaceFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) NamespaceFloatSetting {
+	s := NamespaceFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewNamespaceFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) NamespaceFloatSetting {
 	return NewNamespaceTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -311,6 +535,20 @@ func NewNamespaceIDFloatSetting(key Key, def float64, description string) Namesp
 	return NewNamespaceIDTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewNamespaceIDFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewNamespaceIDFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) NamespaceIDFloatSetting {
+	s := NamespaceIDFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewNamespaceIDFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) NamespaceIDFloatSetting {
 	return NewNamespaceIDTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -327,6 +565,20 @@ func NewTaskQueueFloatSetting(key Key, def float64, description string) TaskQueu
 	return NewTaskQueueTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewTaskQueueFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewTaskQueueFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) TaskQueueFloatSetting {
+	s := TaskQueueFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewTaskQueueFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) TaskQueueFloatSetting {
 	return NewTaskQueueTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -343,6 +595,20 @@ func NewShardIDFloatSetting(key Key, def float64, description string) ShardIDFlo
 	return NewShardIDTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewShardIDFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewShardIDFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) ShardIDFloatSetting {
+	s := ShardIDFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewShardIDFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) ShardIDFloatSetting {
 	return NewShardIDTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -359,6 +625,20 @@ func NewTaskTypeFloatSetting(key Key, def float64, description string) TaskTypeF
 	return NewTaskTypeTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewTaskTypeFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewTaskTypeFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) TaskTypeFloatSetting {
+	s := TaskTypeFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewTaskTypeFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) TaskTypeFloatSetting {
 	return NewTaskTypeTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -375,6 +655,20 @@ func NewDestinationFloatSetting(key Key, def float64, description string) Destin
 	return NewDestinationTypedSettingWithConverter[float64](key, convertFloat, def, description)
 }
 
+// NewDestinationFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewDestinationFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) DestinationFloatSetting {
+	s := DestinationFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewDestinationFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) DestinationFloatSetting {
 	return NewDestinationTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
 }
@@ -385,6 +679,66 @@ func GetFloatPropertyFnFilteredByDestination(value float64) FloatPropertyFnWithD
 	return GetTypedPropertyFnFilteredByDestination(value)
 }
 
+type WorkflowTypeFloatSetting = WorkflowTypeTypedSetting[float64]
+
+func NewWorkflowTypeFloatSetting(key Key, def float64, description string) WorkflowTypeFloatSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[float64](key, convertFloat, def, description)
+}
+
+// NewWorkflowTypeFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewWorkflowTypeFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) WorkflowTypeFloatSetting {
+	s := WorkflowTypeFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewWorkflowTypeFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) WorkflowTypeFloatSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
+}
+
+type FloatPropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[float64]
+
+func GetFloatPropertyFnFilteredByWorkflowType(value float64) FloatPropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionFloatSetting = SDKVersionTypedSetting[float64]
+
+func NewSDKVersionFloatSetting(key Key, def float64, description string) SDKVersionFloatSetting {
+	return NewSDKVersionTypedSettingWithConverter[float64](key, convertFloat, def, description)
+}
+
+// NewSDKVersionFloatSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewSDKVersionFloatSettingWithBounds(key Key, def float64, bounds Bounds[float64], description string) SDKVersionFloatSetting {
+	s := SDKVersionFloatSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertFloat, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewSDKVersionFloatSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[float64], description string) SDKVersionFloatSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[float64](key, convertFloat, cdef, description)
+}
+
+type FloatPropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[float64]
+
+func GetFloatPropertyFnFilteredBySDKVersion(value float64) FloatPropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
 type GlobalStringSetting = GlobalTypedSetting[string]
 
 func NewGlobalStringSetting(key Key, def string, description string) GlobalStringSetting {
@@ -497,12 +851,58 @@ func GetStringPropertyFnFilteredByDestination(value string) StringPropertyFnWith
 	return GetTypedPropertyFnFilteredByDestination(value)
 }
 
+type WorkflowTypeStringSetting = WorkflowTypeTypedSetting[string]
+
+func NewWorkflowTypeStringSetting(key Key, def string, description string) WorkflowTypeStringSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[string](key, convertString, def, description)
+}
+
+func NewWorkflowTypeStringSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[string], description string) WorkflowTypeStringSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[string](key, convertString, cdef, description)
+}
+
+type StringPropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[string]
+
+func GetStringPropertyFnFilteredByWorkflowType(value string) StringPropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionStringSetting = SDKVersionTypedSetting[string]
+
+func NewSDKVersionStringSetting(key Key, def string, description string) SDKVersionStringSetting {
+	return NewSDKVersionTypedSettingWithConverter[string](key, convertString, def, description)
+}
+
+func NewSDKVersionStringSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[string], description string) SDKVersionStringSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[string](key, convertString, cdef, description)
+}
+
+type StringPropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[string]
+
+func GetStringPropertyFnFilteredBySDKVersion(value string) StringPropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
 type GlobalDurationSetting = GlobalTypedSetting[time.Duration]
 
 func NewGlobalDurationSetting(key Key, def time.Duration, description string) GlobalDurationSetting {
 	return NewGlobalTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
+// NewGlobalDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewGlobalDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) GlobalDurationSetting {
+	s := GlobalDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewGlobalDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) GlobalDurationSetting {
 	return NewGlobalTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
 }
@@ -519,6 +919,20 @@ func NewNamespaceDurationSetting(key Key, def time.Duration, description string)
 	return NewNamespaceTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
+// NewNamespaceDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewNamespaceDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) NamespaceDurationSetting {
+	s := NamespaceDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewNamespaceDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) NamespaceDurationSetting {
 	return NewNamespaceTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
 }
@@ -535,6 +949,20 @@ func NewNamespaceIDDurationSetting(key Key, def time.Duration, description strin
 	return NewNamespaceIDTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
+// NewNamespaceIDDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewNamespaceIDDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) NamespaceIDDurationSetting {
+	s := NamespaceIDDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewNamespaceIDDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) NamespaceIDDurationSetting {
 	return NewNamespaceIDTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
 }
@@ -551,6 +979,20 @@ func NewTaskQueueDurationSetting(key Key, def time.Duration, description string)
 	return NewTaskQueueTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
+// NewTaskQueueDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewTaskQueueDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) TaskQueueDurationSetting {
+	s := TaskQueueDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewTaskQueueDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) TaskQueueDurationSetting {
 	return NewTaskQueueTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
 }
@@ -567,6 +1009,20 @@ func NewShardIDDurationSetting(key Key, def time.Duration, description string) S
 	return NewShardIDTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
+// NewShardIDDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewShardIDDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) ShardIDDurationSetting {
+	s := ShardIDDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewShardIDDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) ShardIDDurationSetting {
 	return NewShardIDTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
 }
@@ -583,12 +1039,26 @@ func NewTaskTypeDurationSetting(key Key, def time.Duration, description string)
 	return NewTaskTypeTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
-func NewTaskTypeDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) TaskTypeDurationSetting {
-	return NewTaskTypeTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
-}
-
-type DurationPropertyFnWithTaskTypeFilter = TypedPropertyFnWithTaskTypeFilter[time.Duration]
-
+// NewTaskTypeDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewTaskTypeDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) TaskTypeDurationSetting {
+	s := TaskTypeDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewTaskTypeDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) TaskTypeDurationSetting {
+	return NewTaskTypeTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
+}
+
+type DurationPropertyFnWithTaskTypeFilter = TypedPropertyFnWithTaskTypeFilter[time.Duration]
+
 func GetDurationPropertyFnFilteredByTaskType(value time.Duration) DurationPropertyFnWithTaskTypeFilter {
 	return GetTypedPropertyFnFilteredByTaskType(value)
 }
@@ -599,6 +1069,20 @@ func NewDestinationDurationSetting(key Key, def time.Duration, description strin
 	return NewDestinationTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
 }
 
+// NewDestinationDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewDestinationDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) DestinationDurationSetting {
+	s := DestinationDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
 func NewDestinationDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) DestinationDurationSetting {
 	return NewDestinationTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
 }
@@ -609,6 +1093,66 @@ func GetDurationPropertyFnFilteredByDestination(value time.Duration) DurationPro
 	return GetTypedPropertyFnFilteredByDestination(value)
 }
 
+type WorkflowTypeDurationSetting = WorkflowTypeTypedSetting[time.Duration]
+
+func NewWorkflowTypeDurationSetting(key Key, def time.Duration, description string) WorkflowTypeDurationSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
+}
+
+// NewWorkflowTypeDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewWorkflowTypeDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) WorkflowTypeDurationSetting {
+	s := WorkflowTypeDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewWorkflowTypeDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) WorkflowTypeDurationSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
+}
+
+type DurationPropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[time.Duration]
+
+func GetDurationPropertyFnFilteredByWorkflowType(value time.Duration) DurationPropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionDurationSetting = SDKVersionTypedSetting[time.Duration]
+
+func NewSDKVersionDurationSetting(key Key, def time.Duration, description string) SDKVersionDurationSetting {
+	return NewSDKVersionTypedSettingWithConverter[time.Duration](key, convertDuration, def, description)
+}
+
+// NewSDKVersionDurationSettingWithBounds creates a setting whose values must be within the given
+// bounds. Out-of-range values are either clamped or ignored, depending on the bounds policy.
+func NewSDKVersionDurationSettingWithBounds(key Key, def time.Duration, bounds Bounds[time.Duration], description string) SDKVersionDurationSetting {
+	s := SDKVersionDurationSetting{
+		key:         key,
+		def:         def,
+		convert:     boundedConverter(key, def, convertDuration, bounds),
+		bounds:      bounds.erase(),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func NewSDKVersionDurationSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[time.Duration], description string) SDKVersionDurationSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[time.Duration](key, convertDuration, cdef, description)
+}
+
+type DurationPropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[time.Duration]
+
+func GetDurationPropertyFnFilteredBySDKVersion(value time.Duration) DurationPropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
 type GlobalMapSetting = GlobalTypedSetting[map[string]any]
 
 func NewGlobalMapSetting(key Key, def map[string]any, description string) GlobalMapSetting {
@@ -721,6 +1265,182 @@ func GetMapPropertyFnFilteredByDestination(value map[string]any) MapPropertyFnWi
 	return GetTypedPropertyFnFilteredByDestination(value)
 }
 
+type WorkflowTypeMapSetting = WorkflowTypeTypedSetting[map[string]any]
+
+func NewWorkflowTypeMapSetting(key Key, def map[string]any, description string) WorkflowTypeMapSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[map[string]any](key, convertMap, def, description)
+}
+
+func NewWorkflowTypeMapSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[map[string]any], description string) WorkflowTypeMapSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[map[string]any](key, convertMap, cdef, description)
+}
+
+type MapPropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[map[string]any]
+
+func GetMapPropertyFnFilteredByWorkflowType(value map[string]any) MapPropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionMapSetting = SDKVersionTypedSetting[map[string]any]
+
+func NewSDKVersionMapSetting(key Key, def map[string]any, description string) SDKVersionMapSetting {
+	return NewSDKVersionTypedSettingWithConverter[map[string]any](key, convertMap, def, description)
+}
+
+func NewSDKVersionMapSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[map[string]any], description string) SDKVersionMapSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[map[string]any](key, convertMap, cdef, description)
+}
+
+type MapPropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[map[string]any]
+
+func GetMapPropertyFnFilteredBySDKVersion(value map[string]any) MapPropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
+type GlobalStringSliceSetting = GlobalTypedSetting[[]string]
+
+func NewGlobalStringSliceSetting(key Key, def []string, description string) GlobalStringSliceSetting {
+	return NewGlobalTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewGlobalStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) GlobalStringSliceSetting {
+	return NewGlobalTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFn = TypedPropertyFn[[]string]
+
+func GetStringSlicePropertyFn(value []string) StringSlicePropertyFn {
+	return GetTypedPropertyFn(value)
+}
+
+type NamespaceStringSliceSetting = NamespaceTypedSetting[[]string]
+
+func NewNamespaceStringSliceSetting(key Key, def []string, description string) NamespaceStringSliceSetting {
+	return NewNamespaceTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewNamespaceStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) NamespaceStringSliceSetting {
+	return NewNamespaceTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithNamespaceFilter = TypedPropertyFnWithNamespaceFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByNamespace(value []string) StringSlicePropertyFnWithNamespaceFilter {
+	return GetTypedPropertyFnFilteredByNamespace(value)
+}
+
+type NamespaceIDStringSliceSetting = NamespaceIDTypedSetting[[]string]
+
+func NewNamespaceIDStringSliceSetting(key Key, def []string, description string) NamespaceIDStringSliceSetting {
+	return NewNamespaceIDTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewNamespaceIDStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) NamespaceIDStringSliceSetting {
+	return NewNamespaceIDTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithNamespaceIDFilter = TypedPropertyFnWithNamespaceIDFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByNamespaceID(value []string) StringSlicePropertyFnWithNamespaceIDFilter {
+	return GetTypedPropertyFnFilteredByNamespaceID(value)
+}
+
+type TaskQueueStringSliceSetting = TaskQueueTypedSetting[[]string]
+
+func NewTaskQueueStringSliceSetting(key Key, def []string, description string) TaskQueueStringSliceSetting {
+	return NewTaskQueueTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewTaskQueueStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) TaskQueueStringSliceSetting {
+	return NewTaskQueueTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithTaskQueueFilter = TypedPropertyFnWithTaskQueueFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByTaskQueue(value []string) StringSlicePropertyFnWithTaskQueueFilter {
+	return GetTypedPropertyFnFilteredByTaskQueue(value)
+}
+
+type ShardIDStringSliceSetting = ShardIDTypedSetting[[]string]
+
+func NewShardIDStringSliceSetting(key Key, def []string, description string) ShardIDStringSliceSetting {
+	return NewShardIDTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewShardIDStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) ShardIDStringSliceSetting {
+	return NewShardIDTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithShardIDFilter = TypedPropertyFnWithShardIDFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByShardID(value []string) StringSlicePropertyFnWithShardIDFilter {
+	return GetTypedPropertyFnFilteredByShardID(value)
+}
+
+type TaskTypeStringSliceSetting = TaskTypeTypedSetting[[]string]
+
+func NewTaskTypeStringSliceSetting(key Key, def []string, description string) TaskTypeStringSliceSetting {
+	return NewTaskTypeTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewTaskTypeStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) TaskTypeStringSliceSetting {
+	return NewTaskTypeTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithTaskTypeFilter = TypedPropertyFnWithTaskTypeFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByTaskType(value []string) StringSlicePropertyFnWithTaskTypeFilter {
+	return GetTypedPropertyFnFilteredByTaskType(value)
+}
+
+type DestinationStringSliceSetting = DestinationTypedSetting[[]string]
+
+func NewDestinationStringSliceSetting(key Key, def []string, description string) DestinationStringSliceSetting {
+	return NewDestinationTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewDestinationStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) DestinationStringSliceSetting {
+	return NewDestinationTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithDestinationFilter = TypedPropertyFnWithDestinationFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByDestination(value []string) StringSlicePropertyFnWithDestinationFilter {
+	return GetTypedPropertyFnFilteredByDestination(value)
+}
+
+type WorkflowTypeStringSliceSetting = WorkflowTypeTypedSetting[[]string]
+
+func NewWorkflowTypeStringSliceSetting(key Key, def []string, description string) WorkflowTypeStringSliceSetting {
+	return NewWorkflowTypeTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewWorkflowTypeStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) WorkflowTypeStringSliceSetting {
+	return NewWorkflowTypeTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithWorkflowTypeFilter = TypedPropertyFnWithWorkflowTypeFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredByWorkflowType(value []string) StringSlicePropertyFnWithWorkflowTypeFilter {
+	return GetTypedPropertyFnFilteredByWorkflowType(value)
+}
+
+type SDKVersionStringSliceSetting = SDKVersionTypedSetting[[]string]
+
+func NewSDKVersionStringSliceSetting(key Key, def []string, description string) SDKVersionStringSliceSetting {
+	return NewSDKVersionTypedSettingWithConverter[[]string](key, convertStringSlice, def, description)
+}
+
+func NewSDKVersionStringSliceSettingWithConstrainedDefault(key Key, cdef []TypedConstrainedValue[[]string], description string) SDKVersionStringSliceSetting {
+	return NewSDKVersionTypedSettingWithConstrainedDefault[[]string](key, convertStringSlice, cdef, description)
+}
+
+type StringSlicePropertyFnWithSDKVersionFilter = TypedPropertyFnWithSDKVersionFilter[[]string]
+
+func GetStringSlicePropertyFnFilteredBySDKVersion(value []string) StringSlicePropertyFnWithSDKVersionFilter {
+	return GetTypedPropertyFnFilteredBySDKVersion(value)
+}
+
 type GlobalTypedSetting[T any] setting[T, func()]
 
 // NewGlobalTypedSetting creates a setting that uses mapstructure to handle complex structured
@@ -767,6 +1487,7 @@ func (s GlobalTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s GlobalTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s GlobalTypedSetting[T]) WithDefault(v T) GlobalTypedSetting[T] {
 	newS := s
@@ -774,6 +1495,24 @@ func (s GlobalTypedSetting[T]) WithDefault(v T) GlobalTypedSetting[T] {
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s GlobalTypedSetting[T]) WithAccept(accept func(T) bool) GlobalTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s GlobalTypedSetting[T]) WithValidator(validate func(T) error) GlobalTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 type TypedPropertyFn[T any] func() T
 
 func (s GlobalTypedSetting[T]) Get(c *Collection) TypedPropertyFn[T] {
@@ -785,11 +1524,78 @@ func (s GlobalTypedSetting[T]) Get(c *Collection) TypedPropertyFn[T] {
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s GlobalTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFn[T] {
+	return func() T {
+		prec := []Constraints{{}}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s GlobalTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func() []Constraints {
+		return []Constraints{{}}
+	}()
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s GlobalTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceGlobal, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s GlobalTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceGlobal),
+	)
+}
+
 func GetTypedPropertyFn[T any](value T) TypedPropertyFn[T] {
 	return func() T {
 		return value
@@ -842,6 +1648,7 @@ func (s NamespaceTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s NamespaceTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s NamespaceTypedSetting[T]) WithDefault(v T) NamespaceTypedSetting[T] {
 	newS := s
@@ -849,6 +1656,24 @@ func (s NamespaceTypedSetting[T]) WithDefault(v T) NamespaceTypedSetting[T] {
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s NamespaceTypedSetting[T]) WithAccept(accept func(T) bool) NamespaceTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s NamespaceTypedSetting[T]) WithValidator(validate func(T) error) NamespaceTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 type TypedPropertyFnWithNamespaceFilter[T any] func(namespace string) T
 
 func (s NamespaceTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithNamespaceFilter[T] {
@@ -860,11 +1685,78 @@ func (s NamespaceTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithNamespac
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s NamespaceTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithNamespaceFilter[T] {
+	return func(namespace string) T {
+		prec := []Constraints{{Namespace: namespace}, {}}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s NamespaceTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(namespace string) []Constraints {
+		return []Constraints{{Namespace: namespace}, {}}
+	}(cons.Namespace)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s NamespaceTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceNamespace, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s NamespaceTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceNamespace),
+	)
+}
+
 func GetTypedPropertyFnFilteredByNamespace[T any](value T) TypedPropertyFnWithNamespaceFilter[T] {
 	return func(namespace string) T {
 		return value
@@ -917,6 +1809,7 @@ func (s NamespaceIDTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s NamespaceIDTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s NamespaceIDTypedSetting[T]) WithDefault(v T) NamespaceIDTypedSetting[T] {
 	newS := s
@@ -924,6 +1817,24 @@ func (s NamespaceIDTypedSetting[T]) WithDefault(v T) NamespaceIDTypedSetting[T]
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s NamespaceIDTypedSetting[T]) WithAccept(accept func(T) bool) NamespaceIDTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s NamespaceIDTypedSetting[T]) WithValidator(validate func(T) error) NamespaceIDTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 type TypedPropertyFnWithNamespaceIDFilter[T any] func(namespaceID string) T
 
 func (s NamespaceIDTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithNamespaceIDFilter[T] {
@@ -935,11 +1846,78 @@ func (s NamespaceIDTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithNamesp
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s NamespaceIDTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithNamespaceIDFilter[T] {
+	return func(namespaceID string) T {
+		prec := []Constraints{{NamespaceID: namespaceID}, {}}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s NamespaceIDTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(namespaceID string) []Constraints {
+		return []Constraints{{NamespaceID: namespaceID}, {}}
+	}(cons.NamespaceID)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s NamespaceIDTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceNamespaceID, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s NamespaceIDTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceNamespaceID),
+	)
+}
+
 func GetTypedPropertyFnFilteredByNamespaceID[T any](value T) TypedPropertyFnWithNamespaceIDFilter[T] {
 	return func(namespaceID string) T {
 		return value
@@ -992,6 +1970,7 @@ func (s TaskQueueTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s TaskQueueTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s TaskQueueTypedSetting[T]) WithDefault(v T) TaskQueueTypedSetting[T] {
 	newS := s
@@ -999,6 +1978,24 @@ func (s TaskQueueTypedSetting[T]) WithDefault(v T) TaskQueueTypedSetting[T] {
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s TaskQueueTypedSetting[T]) WithAccept(accept func(T) bool) TaskQueueTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s TaskQueueTypedSetting[T]) WithValidator(validate func(T) error) TaskQueueTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 type TypedPropertyFnWithTaskQueueFilter[T any] func(namespace string, taskQueue string, taskQueueType enumspb.TaskQueueType) T
 
 func (s TaskQueueTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithTaskQueueFilter[T] {
@@ -1016,11 +2013,90 @@ func (s TaskQueueTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithTaskQueu
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s TaskQueueTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithTaskQueueFilter[T] {
+	return func(namespace string, taskQueue string, taskQueueType enumspb.TaskQueueType) T {
+		prec := []Constraints{
+			{Namespace: namespace, TaskQueueName: taskQueue, TaskQueueType: taskQueueType},
+			{Namespace: namespace, TaskQueueName: taskQueue},
+			{TaskQueueName: taskQueue},
+			{Namespace: namespace},
+			{},
+		}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s TaskQueueTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(namespace string, taskQueue string, taskQueueType enumspb.TaskQueueType) []Constraints {
+		return []Constraints{
+			{Namespace: namespace, TaskQueueName: taskQueue, TaskQueueType: taskQueueType},
+			{Namespace: namespace, TaskQueueName: taskQueue},
+			{TaskQueueName: taskQueue},
+			{Namespace: namespace},
+			{},
+		}
+	}(cons.Namespace, cons.TaskQueueName, cons.TaskQueueType)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s TaskQueueTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceTaskQueue, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s TaskQueueTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceTaskQueue),
+	)
+}
+
 func GetTypedPropertyFnFilteredByTaskQueue[T any](value T) TypedPropertyFnWithTaskQueueFilter[T] {
 	return func(namespace string, taskQueue string, taskQueueType enumspb.TaskQueueType) T {
 		return value
@@ -1073,6 +2149,7 @@ func (s ShardIDTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s ShardIDTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s ShardIDTypedSetting[T]) WithDefault(v T) ShardIDTypedSetting[T] {
 	newS := s
@@ -1080,9 +2157,27 @@ func (s ShardIDTypedSetting[T]) WithDefault(v T) ShardIDTypedSetting[T] {
 	return newS
 }
 
-type TypedPropertyFnWithShardIDFilter[T any] func(shardID int32) T
-
-func (s ShardIDTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithShardIDFilter[T] {
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s ShardIDTypedSetting[T]) WithAccept(accept func(T) bool) ShardIDTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s ShardIDTypedSetting[T]) WithValidator(validate func(T) error) ShardIDTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
+type TypedPropertyFnWithShardIDFilter[T any] func(shardID int32) T
+
+func (s ShardIDTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithShardIDFilter[T] {
 	return func(shardID int32) T {
 		prec := []Constraints{{ShardID: shardID}, {}}
 		return matchAndConvert(
@@ -1091,11 +2186,78 @@ func (s ShardIDTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithShardIDFil
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s ShardIDTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithShardIDFilter[T] {
+	return func(shardID int32) T {
+		prec := []Constraints{{ShardID: shardID}, {}}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s ShardIDTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(shardID int32) []Constraints {
+		return []Constraints{{ShardID: shardID}, {}}
+	}(cons.ShardID)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s ShardIDTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceShardID, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s ShardIDTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceShardID),
+	)
+}
+
 func GetTypedPropertyFnFilteredByShardID[T any](value T) TypedPropertyFnWithShardIDFilter[T] {
 	return func(shardID int32) T {
 		return value
@@ -1148,6 +2310,7 @@ func (s TaskTypeTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s TaskTypeTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s TaskTypeTypedSetting[T]) WithDefault(v T) TaskTypeTypedSetting[T] {
 	newS := s
@@ -1155,6 +2318,24 @@ func (s TaskTypeTypedSetting[T]) WithDefault(v T) TaskTypeTypedSetting[T] {
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s TaskTypeTypedSetting[T]) WithAccept(accept func(T) bool) TaskTypeTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s TaskTypeTypedSetting[T]) WithValidator(validate func(T) error) TaskTypeTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 type TypedPropertyFnWithTaskTypeFilter[T any] func(taskType enumsspb.TaskType) T
 
 func (s TaskTypeTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithTaskTypeFilter[T] {
@@ -1166,11 +2347,78 @@ func (s TaskTypeTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithTaskTypeF
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s TaskTypeTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithTaskTypeFilter[T] {
+	return func(taskType enumsspb.TaskType) T {
+		prec := []Constraints{{TaskType: taskType}, {}}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+func (s TaskTypeTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(taskType enumsspb.TaskType) []Constraints {
+		return []Constraints{{TaskType: taskType}, {}}
+	}(cons.TaskType)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s TaskTypeTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceTaskType, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s TaskTypeTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceTaskType),
+	)
+}
+
 func GetTypedPropertyFnFilteredByTaskType[T any](value T) TypedPropertyFnWithTaskTypeFilter[T] {
 	return func(taskType enumsspb.TaskType) T {
 		return value
@@ -1223,6 +2471,7 @@ func (s DestinationTypedSetting[T]) Validate(v any) error {
 	_, err := s.convert(v)
 	return err
 }
+func (s DestinationTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
 
 func (s DestinationTypedSetting[T]) WithDefault(v T) DestinationTypedSetting[T] {
 	newS := s
@@ -1230,6 +2479,24 @@ func (s DestinationTypedSetting[T]) WithDefault(v T) DestinationTypedSetting[T]
 	return newS
 }
 
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s DestinationTypedSetting[T]) WithAccept(accept func(T) bool) DestinationTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s DestinationTypedSetting[T]) WithValidator(validate func(T) error) DestinationTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
 type TypedPropertyFnWithDestinationFilter[T any] func(namespace string, destination string) T
 
 func (s DestinationTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithDestinationFilter[T] {
@@ -1246,13 +2513,494 @@ func (s DestinationTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithDestin
 			s.def,
 			s.cdef,
 			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s DestinationTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithDestinationFilter[T] {
+	return func(namespace string, destination string) T {
+		prec := []Constraints{
+			{Namespace: namespace, Destination: destination},
+			{Destination: destination},
+			{Namespace: namespace},
+			{},
+		}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
 			prec,
 		)
 	}
 }
 
+func (s DestinationTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(namespace string, destination string) []Constraints {
+		return []Constraints{
+			{Namespace: namespace, Destination: destination},
+			{Destination: destination},
+			{Namespace: namespace},
+			{},
+		}
+	}(cons.Namespace, cons.Destination)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s DestinationTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceDestination, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s DestinationTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceDestination),
+	)
+}
+
 func GetTypedPropertyFnFilteredByDestination[T any](value T) TypedPropertyFnWithDestinationFilter[T] {
 	return func(namespace string, destination string) T {
 		return value
 	}
 }
+
+type WorkflowTypeTypedSetting[T any] setting[T, func(namespace string, workflowType string)]
+
+// NewWorkflowTypeTypedSetting creates a setting that uses mapstructure to handle complex structured
+// values. The value from dynamic config will be copied over a shallow copy of 'def', which means
+// 'def' must not contain any non-nil slices, maps, or pointers.
+func NewWorkflowTypeTypedSetting[T any](key Key, def T, description string) WorkflowTypeTypedSetting[T] {
+	s := WorkflowTypeTypedSetting[T]{
+		key:         key,
+		def:         def,
+		convert:     ConvertStructure[T](def),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+// NewWorkflowTypeTypedSettingWithConverter creates a setting with a custom converter function.
+func NewWorkflowTypeTypedSettingWithConverter[T any](key Key, convert func(any) (T, error), def T, description string) WorkflowTypeTypedSetting[T] {
+	s := WorkflowTypeTypedSetting[T]{
+		key:         key,
+		def:         def,
+		convert:     convert,
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+// NewWorkflowTypeTypedSettingWithConstrainedDefault creates a setting with a compound default value.
+func NewWorkflowTypeTypedSettingWithConstrainedDefault[T any](key Key, convert func(any) (T, error), cdef []TypedConstrainedValue[T], description string) WorkflowTypeTypedSetting[T] {
+	s := WorkflowTypeTypedSetting[T]{
+		key:         key,
+		cdef:        cdef,
+		convert:     convert,
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func (s WorkflowTypeTypedSetting[T]) Key() Key               { return s.key }
+func (s WorkflowTypeTypedSetting[T]) Precedence() Precedence { return PrecedenceWorkflowType }
+func (s WorkflowTypeTypedSetting[T]) Validate(v any) error {
+	_, err := s.convert(v)
+	return err
+}
+func (s WorkflowTypeTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
+
+func (s WorkflowTypeTypedSetting[T]) WithDefault(v T) WorkflowTypeTypedSetting[T] {
+	newS := s
+	newS.def = v
+	return newS
+}
+
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s WorkflowTypeTypedSetting[T]) WithAccept(accept func(T) bool) WorkflowTypeTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s WorkflowTypeTypedSetting[T]) WithValidator(validate func(T) error) WorkflowTypeTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
+type TypedPropertyFnWithWorkflowTypeFilter[T any] func(namespace string, workflowType string) T
+
+func (s WorkflowTypeTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithWorkflowTypeFilter[T] {
+	return func(namespace string, workflowType string) T {
+		prec := []Constraints{
+			{Namespace: namespace, WorkflowType: workflowType},
+			{Namespace: namespace},
+			{},
+		}
+		return matchAndConvert(
+			c,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s WorkflowTypeTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithWorkflowTypeFilter[T] {
+	return func(namespace string, workflowType string) T {
+		prec := []Constraints{
+			{Namespace: namespace, WorkflowType: workflowType},
+			{Namespace: namespace},
+			{},
+		}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+func (s WorkflowTypeTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(namespace string, workflowType string) []Constraints {
+		return []Constraints{
+			{Namespace: namespace, WorkflowType: workflowType},
+			{Namespace: namespace},
+			{},
+		}
+	}(cons.Namespace, cons.WorkflowType)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s WorkflowTypeTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceWorkflowType, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s WorkflowTypeTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceWorkflowType),
+	)
+}
+
+func GetTypedPropertyFnFilteredByWorkflowType[T any](value T) TypedPropertyFnWithWorkflowTypeFilter[T] {
+	return func(namespace string, workflowType string) T {
+		return value
+	}
+}
+
+type SDKVersionTypedSetting[T any] setting[T, func(namespace string, sdkName string, sdkVersion string)]
+
+// NewSDKVersionTypedSetting creates a setting that uses mapstructure to handle complex structured
+// values. The value from dynamic config will be copied over a shallow copy of 'def', which means
+// 'def' must not contain any non-nil slices, maps, or pointers.
+func NewSDKVersionTypedSetting[T any](key Key, def T, description string) SDKVersionTypedSetting[T] {
+	s := SDKVersionTypedSetting[T]{
+		key:         key,
+		def:         def,
+		convert:     ConvertStructure[T](def),
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+// NewSDKVersionTypedSettingWithConverter creates a setting with a custom converter function.
+func NewSDKVersionTypedSettingWithConverter[T any](key Key, convert func(any) (T, error), def T, description string) SDKVersionTypedSetting[T] {
+	s := SDKVersionTypedSetting[T]{
+		key:         key,
+		def:         def,
+		convert:     convert,
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+// NewSDKVersionTypedSettingWithConstrainedDefault creates a setting with a compound default value.
+func NewSDKVersionTypedSettingWithConstrainedDefault[T any](key Key, convert func(any) (T, error), cdef []TypedConstrainedValue[T], description string) SDKVersionTypedSetting[T] {
+	s := SDKVersionTypedSetting[T]{
+		key:         key,
+		cdef:        cdef,
+		convert:     convert,
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func (s SDKVersionTypedSetting[T]) Key() Key               { return s.key }
+func (s SDKVersionTypedSetting[T]) Precedence() Precedence { return PrecedenceSDKVersion }
+func (s SDKVersionTypedSetting[T]) Validate(v any) error {
+	_, err := s.convert(v)
+	return err
+}
+func (s SDKVersionTypedSetting[T]) Bounds() *SettingBounds { return s.bounds }
+
+func (s SDKVersionTypedSetting[T]) WithDefault(v T) SDKVersionTypedSetting[T] {
+	newS := s
+	newS.def = v
+	return newS
+}
+
+// WithAccept returns a copy of the setting that only uses matched values for which accept
+// returns true, e.g. to only honor timeout overrides above a floor. Rejected values fall through
+// to the next match in precedence order, and finally the default.
+func (s SDKVersionTypedSetting[T]) WithAccept(accept func(T) bool) SDKVersionTypedSetting[T] {
+	newS := s
+	newS.accept = accept
+	return newS
+}
+
+// WithValidator returns a copy of the setting whose values are checked by validate, e.g. to catch a
+// cache size of zero at startup rather than on first use. validate runs once per distinct value.
+// Values it rejects are logged as errors and the default is used instead.
+func (s SDKVersionTypedSetting[T]) WithValidator(validate func(T) error) SDKVersionTypedSetting[T] {
+	newS := s
+	newS.convert = validatingConverter(s.convert, validate)
+	return newS
+}
+
+type TypedPropertyFnWithSDKVersionFilter[T any] func(namespace string, sdkName string, sdkVersion string) T
+
+func (s SDKVersionTypedSetting[T]) Get(c *Collection) TypedPropertyFnWithSDKVersionFilter[T] {
+	return func(namespace string, sdkName string, sdkVersion string) T {
+		prec := []Constraints{
+			{Namespace: namespace, SDKName: sdkName, SDKVersion: sdkVersion},
+			{SDKName: sdkName, SDKVersion: sdkVersion},
+			{Namespace: namespace},
+			{},
+		}
+		return matchAndConvert(
+			c,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+// GetWithLogger is like Get, but logs missing and unconvertible values to logger instead of the
+// collection's logger, so that they are attributed to the calling component. A nil logger falls
+// back to the collection's logger.
+func (s SDKVersionTypedSetting[T]) GetWithLogger(c *Collection, logger log.Logger) TypedPropertyFnWithSDKVersionFilter[T] {
+	return func(namespace string, sdkName string, sdkVersion string) T {
+		prec := []Constraints{
+			{Namespace: namespace, SDKName: sdkName, SDKVersion: sdkVersion},
+			{SDKName: sdkName, SDKVersion: sdkVersion},
+			{Namespace: namespace},
+			{},
+		}
+		return matchAndConvertWithLogger(
+			c,
+			logger,
+			s.key,
+			s.def,
+			s.cdef,
+			s.convert,
+			s.accept,
+			prec,
+		)
+	}
+}
+
+func (s SDKVersionTypedSetting[T]) resolveWithConstraints(ctx context.Context, c *Collection, cons Constraints) T {
+	prec := func(namespace string, sdkName string, sdkVersion string) []Constraints {
+		return []Constraints{
+			{Namespace: namespace, SDKName: sdkName, SDKVersion: sdkVersion},
+			{SDKName: sdkName, SDKVersion: sdkVersion},
+			{Namespace: namespace},
+			{},
+		}
+	}(cons.Namespace, cons.SDKName, cons.SDKVersion)
+	return matchAndConvertWithContext(
+		ctx,
+		c,
+		nil,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		append([]Constraints{cons}, prec...),
+	)
+}
+
+func (s SDKVersionTypedSetting[T]) trace(c *Collection, cons Constraints) TraceResult[T] {
+	return traceMatchAndConvert(
+		c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		precedenceConstraints(PrecedenceSDKVersion, cons),
+	)
+}
+
+// GetInGroup returns the value of the setting for the filter values of r, see
+// Collection.EvaluateGroup. A request override of the setting in r takes precedence, see
+// GroupResolver.WithRequestOverrides.
+func (s SDKVersionTypedSetting[T]) GetInGroup(r *GroupResolver) T {
+	if v, ok := requestOverride(r, s.key, s.convert, s.accept); ok {
+		return v
+	}
+	return matchAndConvert(
+		r.c,
+		s.key,
+		s.def,
+		s.cdef,
+		s.convert,
+		s.accept,
+		r.precedence(PrecedenceSDKVersion),
+	)
+}
+
+func GetTypedPropertyFnFilteredBySDKVersion[T any](value T) TypedPropertyFnWithSDKVersionFilter[T] {
+	return func(namespace string, sdkName string, sdkVersion string) T {
+		return value
+	}
+}
+
+// precedenceConstraints returns the precedence list of the given precedence, taking the filter
+// values from cons.
+func precedenceConstraints(p Precedence, cons Constraints) []Constraints {
+	switch p {
+	case PrecedenceGlobal:
+		return []Constraints{{}}
+	case PrecedenceNamespace:
+		namespace := cons.Namespace
+		return []Constraints{{Namespace: namespace}, {}}
+	case PrecedenceNamespaceID:
+		namespaceID := cons.NamespaceID
+		return []Constraints{{NamespaceID: namespaceID}, {}}
+	case PrecedenceTaskQueue:
+		namespace, taskQueue, taskQueueType := cons.Namespace, cons.TaskQueueName, cons.TaskQueueType
+		return []Constraints{
+			{Namespace: namespace, TaskQueueName: taskQueue, TaskQueueType: taskQueueType},
+			{Namespace: namespace, TaskQueueName: taskQueue},
+			{TaskQueueName: taskQueue},
+			{Namespace: namespace},
+			{},
+		}
+	case PrecedenceShardID:
+		shardID := cons.ShardID
+		return []Constraints{{ShardID: shardID}, {}}
+	case PrecedenceTaskType:
+		taskType := cons.TaskType
+		return []Constraints{{TaskType: taskType}, {}}
+	case PrecedenceDestination:
+		namespace, destination := cons.Namespace, cons.Destination
+		return []Constraints{
+			{Namespace: namespace, Destination: destination},
+			{Destination: destination},
+			{Namespace: namespace},
+			{},
+		}
+	case PrecedenceWorkflowType:
+		namespace, workflowType := cons.Namespace, cons.WorkflowType
+		return []Constraints{
+			{Namespace: namespace, WorkflowType: workflowType},
+			{Namespace: namespace},
+			{},
+		}
+	case PrecedenceSDKVersion:
+		namespace, sdkName, sdkVersion := cons.Namespace, cons.SDKName, cons.SDKVersion
+		return []Constraints{
+			{Namespace: namespace, SDKName: sdkName, SDKVersion: sdkVersion},
+			{SDKName: sdkName, SDKVersion: sdkVersion},
+			{Namespace: namespace},
+			{},
+		}
+	default:
+		return nil
+	}
+}
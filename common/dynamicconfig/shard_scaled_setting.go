@@ -0,0 +1,81 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+type (
+	// ShardIDScaledSetting is a shard ID filtered setting whose default is computed from the
+	// shard ID and the total number of shards, e.g. so that per-shard buffer sizes shrink as the
+	// number of shards grows. Values set in dynamic config are used as-is.
+	ShardIDScaledSetting[T any] struct {
+		key         Key
+		defFn       func(shardID int32, numShards int32) T
+		convert     func(any) (T, error)
+		description string
+	}
+)
+
+// NewShardIDIntSettingWithScaledDefault creates a shard ID filtered int setting with a default
+// computed by defFn.
+func NewShardIDIntSettingWithScaledDefault(key Key, defFn func(shardID int32, numShards int32) int, description string) ShardIDScaledSetting[int] {
+	return NewShardIDTypedSettingWithScaledDefault(key, convertInt, defFn, description)
+}
+
+// NewShardIDTypedSettingWithScaledDefault creates a shard ID filtered setting with a custom
+// converter function and a default computed by defFn.
+func NewShardIDTypedSettingWithScaledDefault[T any](key Key, convert func(any) (T, error), defFn func(shardID int32, numShards int32) T, description string) ShardIDScaledSetting[T] {
+	s := ShardIDScaledSetting[T]{
+		key:         key,
+		defFn:       defFn,
+		convert:     convert,
+		description: description,
+	}
+	register(s)
+	return s
+}
+
+func (s ShardIDScaledSetting[T]) Key() Key               { return s.key }
+func (s ShardIDScaledSetting[T]) Precedence() Precedence { return PrecedenceShardID }
+func (s ShardIDScaledSetting[T]) Validate(v any) error {
+	_, err := s.convert(v)
+	return err
+}
+func (s ShardIDScaledSetting[T]) Bounds() *SettingBounds { return nil }
+
+// Get returns a property function for the setting. numShards is the total number of shards in
+// the cluster, which is passed to the default function along with the shard ID.
+func (s ShardIDScaledSetting[T]) Get(c *Collection, numShards int32) TypedPropertyFnWithShardIDFilter[T] {
+	return func(shardID int32) T {
+		prec := []Constraints{{ShardID: shardID}, {}}
+		return matchAndConvert(
+			c,
+			s.key,
+			s.defFn(shardID, numShards),
+			nil,
+			s.convert,
+			nil,
+			prec,
+		)
+	}
+}
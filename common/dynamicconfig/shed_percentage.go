@@ -0,0 +1,66 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type (
+	// ShedPercentage is the percentage of requests to reject, from 0 to 100, see ShouldShed.
+	ShedPercentage int
+
+	// ShedPercentagePropertyFnWithNamespaceFilter returns a ShedPercentage per namespace.
+	ShedPercentagePropertyFnWithNamespaceFilter = TypedPropertyFnWithNamespaceFilter[ShedPercentage]
+)
+
+// ShouldShed returns true for a random share of the calls equal to the percentage, to decide if a
+// request should be rejected. It never returns true at 0 and always at 100.
+func (p ShedPercentage) ShouldShed() bool {
+	if p <= 0 {
+		return false
+	}
+	if p >= 100 {
+		return true
+	}
+	return rand.Intn(100) < int(p)
+}
+
+// ConvertShedPercentage can be used as a conversion function for New*TypedSettingWithConverter
+// with a ShedPercentage type. The value from dynamic config must be an integer from 0 to 100.
+func ConvertShedPercentage(v any) (ShedPercentage, error) {
+	if p, ok := v.(ShedPercentage); ok {
+		return p, nil
+	}
+	p, err := convertInt(v)
+	if err != nil {
+		return 0, err
+	}
+	if p < 0 || p > 100 {
+		return 0, fmt.Errorf("shed percentage is not between 0 and 100: %v", p)
+	}
+	return ShedPercentage(p), nil
+}
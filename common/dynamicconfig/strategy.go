@@ -0,0 +1,79 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type (
+	// Strategy is the value of a setting that selects one of several interchangeable
+	// implementations of a component by name, e.g. a load balancing strategy. Impl is usually a
+	// constructor, so that callers can build a new instance when the selected name changes.
+	Strategy[T any] struct {
+		Name string
+		Impl T
+	}
+)
+
+// MustStrategy returns the Strategy registered as name, for setting defaults. It panics if there
+// is no such strategy.
+func MustStrategy[T any](registry map[string]T, name string) Strategy[T] {
+	impl, ok := registry[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown strategy %q, must be one of %s", name, strategyNames(registry)))
+	}
+	return Strategy[T]{Name: name, Impl: impl}
+}
+
+// ConvertStrategy can be used as a conversion function for New*TypedSettingWithConverter with a
+// Strategy type. The value from dynamic config is the name of a strategy in registry. Unknown
+// names fall back to the setting's default, with a warning.
+func ConvertStrategy[T any](registry map[string]T) func(v any) (Strategy[T], error) {
+	return func(v any) (Strategy[T], error) {
+		if strategy, ok := v.(Strategy[T]); ok {
+			return strategy, nil
+		}
+		name, ok := v.(string)
+		if !ok {
+			return Strategy[T]{}, fmt.Errorf("strategy name must be a string, got %T", v)
+		}
+		impl, ok := registry[name]
+		if !ok {
+			return Strategy[T]{}, fmt.Errorf("unknown strategy %q, must be one of %s", name, strategyNames(registry))
+		}
+		return Strategy[T]{Name: name, Impl: impl}, nil
+	}
+}
+
+func strategyNames[T any](registry map[string]T) string {
+	names := maps.Keys(registry)
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
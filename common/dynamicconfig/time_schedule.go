@@ -0,0 +1,152 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type (
+	// TimeSchedule is a setting value that changes over time, e.g. to stage a capacity change
+	// ahead of time: "100 until 2pm, then 200". Each entry takes effect at its EffectiveFrom and
+	// lasts until the next one does. Before the first entry, the schedule has no value.
+	//
+	// In dynamic config it's a list of entries, in any order. An entry without effectiveFrom is in
+	// effect since forever:
+	//
+	//	- value:
+	//	  - value: 100
+	//	  - effectiveFrom: 2024-06-01T14:00:00Z
+	//	    value: 200
+	TimeSchedule[T any] struct {
+		entries []TimeScheduleEntry[T] // sorted by EffectiveFrom
+	}
+
+	TimeScheduleEntry[T any] struct {
+		EffectiveFrom time.Time
+		Value         T
+	}
+)
+
+// NewTimeSchedule returns a schedule of the given entries, which don't need to be sorted. Of
+// several entries with the same EffectiveFrom, the last one is used.
+func NewTimeSchedule[T any](entries ...TimeScheduleEntry[T]) TimeSchedule[T] {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b TimeScheduleEntry[T]) int {
+		return a.EffectiveFrom.Compare(b.EffectiveFrom)
+	})
+	return TimeSchedule[T]{entries: sorted}
+}
+
+// At returns the value in effect at the given time. It returns false if the schedule is empty or
+// its first entry is after t.
+func (s TimeSchedule[T]) At(t time.Time) (T, bool) {
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if !s.entries[i].EffectiveFrom.After(t) {
+			return s.entries[i].Value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Entries returns the entries of the schedule, sorted by EffectiveFrom.
+func (s TimeSchedule[T]) Entries() []TimeScheduleEntry[T] {
+	return slices.Clone(s.entries)
+}
+
+// ConvertTimeSchedule returns a conversion function for New*TypedSettingWithConverter with a
+// TimeSchedule type, which converts the value of each entry with convert. A schedule with an
+// invalid entry falls back to the setting's default.
+func ConvertTimeSchedule[T any](convert func(any) (T, error)) func(any) (TimeSchedule[T], error) {
+	return func(v any) (TimeSchedule[T], error) {
+		switch v := v.(type) {
+		case TimeSchedule[T]:
+			return v, nil
+		case []any:
+			entries := make([]TimeScheduleEntry[T], 0, len(v))
+			for i, raw := range v {
+				entry, err := convertTimeScheduleEntry(raw, convert)
+				if err != nil {
+					return TimeSchedule[T]{}, fmt.Errorf("schedule entry %d: %w", i, err)
+				}
+				entries = append(entries, entry)
+			}
+			return NewTimeSchedule(entries...), nil
+		}
+		return TimeSchedule[T]{}, errors.New("value type is not list")
+	}
+}
+
+func convertTimeScheduleEntry[T any](raw any, convert func(any) (T, error)) (TimeScheduleEntry[T], error) {
+	var entry TimeScheduleEntry[T]
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return entry, errors.New("value type is not map")
+	}
+	for k := range m {
+		if k != "effectiveFrom" && k != "value" {
+			return entry, fmt.Errorf("unknown field %q", k)
+		}
+	}
+	switch from := m["effectiveFrom"].(type) {
+	case nil:
+	case time.Time:
+		entry.EffectiveFrom = from
+	case string:
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return entry, fmt.Errorf("invalid effectiveFrom: %w", err)
+		}
+		entry.EffectiveFrom = t
+	default:
+		return entry, errors.New("effectiveFrom is not an RFC 3339 timestamp")
+	}
+	val, ok := m["value"]
+	if !ok {
+		return entry, errors.New("missing value")
+	}
+	typedVal, err := convert(val)
+	if err != nil {
+		return entry, err
+	}
+	entry.Value = typedVal
+	return entry, nil
+}
+
+// Scheduled returns a property function for the value that the schedule returned by get has at
+// the current time of c, see Collection.SetExpiryClock. It returns def if the schedule has no
+// value at that time, e.g. because it's empty.
+func Scheduled[T any](c *Collection, get TypedPropertyFn[TimeSchedule[T]], def T) TypedPropertyFn[T] {
+	return func() T {
+		if val, ok := get().At(c.timeSource.Now()); ok {
+			return val
+		}
+		return def
+	}
+}
@@ -0,0 +1,232 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// TraceResult is the provenance of the value of a setting for some filter values, see Trace.
+	TraceResult[T any] struct {
+		Key Key
+		// DeprecatedKey is the deprecated key the values were found under, if the setting's key has
+		// none, see Collection.SetDeprecatedKeys.
+		DeprecatedKey Key
+		// Values are the unexpired values of the key, with the client layer each came from.
+		Values []TracedValue
+		// Match is the value that was used. The setting's defaults have the layer "default". It's
+		// nil if none of the setting's constrained defaults matched either.
+		Match *TracedValue
+		// MatchedConstraints is the entry of the setting's precedence list that Match matched.
+		MatchedConstraints Constraints
+		// Raw is the value before conversion, after its ValueTransform if any.
+		Raw any
+		// Value is the converted value, the same one the setting's Get returns.
+		Value T
+		// ConvertError is why Raw couldn't be converted, in which case Value is the default.
+		ConvertError error
+	}
+
+	// TracedValue is a ConstrainedValue along with the client layer that supplied it, e.g.
+	// "overlay" or "merged[1] > file config/dynamicconfig/development.yaml".
+	TracedValue struct {
+		ConstrainedValue
+		Layer string
+	}
+
+	// tracingClient is implemented by clients that wrap or combine other clients, so that Trace can
+	// tell which of them supplied each value.
+	tracingClient interface {
+		traceValue(key Key) []TracedValue
+	}
+
+	tracedMatch[T any] struct {
+		value       TracedValue
+		constraints Constraints
+	}
+)
+
+const defaultLayer = "default"
+
+// Trace evaluates a setting for the given filter values like its Get function does, and returns
+// how the value was arrived at: the values of the setting's key in each client layer, the one that
+// matched and which of the setting's constraints it matched, and the value before and after
+// conversion. It's meant for auditing config and doesn't change any state of the collection, e.g.
+// read keys, or log anything. It's a function because methods can't be generic.
+func Trace[T any](c *Collection, s ConstrainedSetting[T], filters ...FilterOption) TraceResult[T] {
+	var cons Constraints
+	for _, filter := range filters {
+		filter(&cons)
+	}
+	return s.trace(c, cons)
+}
+
+// traceMatchAndConvert is matchAndConvertWithLogger without its side effects, returning the
+// provenance of the value.
+func traceMatchAndConvert[T any](
+	c *Collection,
+	key Key,
+	def T,
+	cdef []TypedConstrainedValue[T],
+	convert func(value any) (T, error),
+	accept func(T) bool,
+	precedence []Constraints,
+) TraceResult[T] {
+	if resolve := c.getVirtualResolver(key); resolve != nil {
+		return traceVirtual(key, def, cdef, convert, resolve, precedence)
+	}
+	result := TraceResult[T]{Key: key}
+	result.Values, result.DeprecatedKey = c.traceValue(key)
+
+	defaultCVs := make([]TracedValue, 0, max(len(cdef), 1))
+	if cdef == nil {
+		defaultCVs = append(defaultCVs, TracedValue{ConstrainedValue: ConstrainedValue{Value: def}, Layer: defaultLayer})
+	}
+	for _, cv := range cdef {
+		defaultCVs = append(defaultCVs, TracedValue{
+			ConstrainedValue: ConstrainedValue{Constraints: cv.Constraints, Value: cv.Value},
+			Layer:            defaultLayer,
+		})
+	}
+
+	matches := traceMatches[T](result.Values, defaultCVs, precedence)
+	if accept == nil && len(matches) > 0 {
+		matches = matches[:1]
+	}
+	for _, m := range matches {
+		val, _ := c.transformValue(key, m.value.Value)
+		typedVal, err := convertQuiet(val, convert)
+		if accept != nil && (err != nil || !accept(typedVal)) {
+			continue
+		}
+		result.Match = &m.value
+		result.MatchedConstraints = m.constraints
+		result.Raw = val
+		if err != nil {
+			result.ConvertError = err
+			result.Value, _ = convert(def)
+			return result
+		}
+		result.Value = typedVal
+		return result
+	}
+
+	// the default
+	val, _ := c.transformValue(key, def)
+	result.Raw = val
+	typedVal, err := convertQuiet(val, convert)
+	if err != nil {
+		result.ConvertError = err
+		typedVal, _ = convert(def)
+	}
+	result.Value = typedVal
+	return result
+}
+
+// traceMatches is findMatches, returning the matched constraints too.
+func traceMatches[T any](tvs []TracedValue, defaultCVs []TracedValue, precedence []Constraints) []tracedMatch[T] {
+	var matches []tracedMatch[T]
+	for _, m := range precedence {
+		for _, vs := range [][]TracedValue{tvs, defaultCVs} {
+			for _, tv := range vs {
+				if constraintsMatch(m, tv.Constraints) {
+					matches = append(matches, tracedMatch[T]{value: tv, constraints: m})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// convertQuiet is convertLenient without logging.
+func convertQuiet[T any](val any, convert func(value any) (T, error)) (T, error) {
+	typedVal, err := convert(val)
+	if _, ok := asConversionWarning(err); ok {
+		err = nil
+	}
+	return typedVal, err
+}
+
+// traceValue is getValue with the layer of each value, without logging the use of a deprecated key
+// or expired values.
+func (c *Collection) traceValue(key Key) ([]TracedValue, Key) {
+	tvs := c.dropExpiredTraced(traceClientValue(c.client, key))
+	if len(tvs) > 0 {
+		return tvs, ""
+	}
+	allDeprecatedKeys := c.deprecatedKeys.Load()
+	if allDeprecatedKeys == nil {
+		return tvs, ""
+	}
+	for _, deprecatedKey := range (*allDeprecatedKeys)[strings.ToLower(key.String())] {
+		if deprecatedTVs := c.dropExpiredTraced(traceClientValue(c.client, deprecatedKey)); len(deprecatedTVs) > 0 {
+			return deprecatedTVs, deprecatedKey
+		}
+	}
+	return tvs, ""
+}
+
+func (c *Collection) dropExpiredTraced(tvs []TracedValue) []TracedValue {
+	now := c.timeSource.Now()
+	result := tvs[:0:0]
+	for _, tv := range tvs {
+		if !tv.ExpiresAt.IsZero() && now.After(tv.ExpiresAt.Add(c.expiryClockSkew)) {
+			continue
+		}
+		result = append(result, tv)
+	}
+	return result
+}
+
+// traceClientValue returns the values of key in client, with the layer each came from.
+func traceClientValue(client Client, key Key) []TracedValue {
+	if tc, ok := client.(tracingClient); ok {
+		return tc.traceValue(key)
+	}
+	return tracedValues(client.GetValue(key), clientLayer(client))
+}
+
+func tracedValues(cvs []ConstrainedValue, layer string) []TracedValue {
+	tvs := make([]TracedValue, len(cvs))
+	for i, cv := range cvs {
+		tvs[i] = TracedValue{ConstrainedValue: cv, Layer: layer}
+	}
+	return tvs
+}
+
+func clientLayer(client Client) string {
+	switch client := client.(type) {
+	case *fileBasedClient:
+		return "file " + client.config.Filepath
+	case *consulClient:
+		return "consul " + client.prefix
+	case StaticClient:
+		return "static"
+	default:
+		return fmt.Sprintf("%T", client)
+	}
+}
@@ -0,0 +1,81 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type (
+	// invalidValueError is returned by validating converters for values rejected by the setting's
+	// validator, see WithValidator.
+	invalidValueError struct {
+		value any
+		err   error
+	}
+)
+
+func (e *invalidValueError) Error() string {
+	return fmt.Sprintf("invalid value %v: %v", e.value, e.err)
+}
+
+func (e *invalidValueError) Unwrap() error {
+	return e.err
+}
+
+func isInvalidValue(err error) bool {
+	var invalidErr *invalidValueError
+	return errors.As(err, &invalidErr)
+}
+
+// validatingConverter wraps convert so that converted values are checked by validate. The result
+// of validate is cached per distinct value, so that it only runs once for each.
+func validatingConverter[T any](
+	convert func(any) (T, error),
+	validate func(T) error,
+) func(any) (T, error) {
+	var results sync.Map // fmt.Sprintf("%#v", value) -> error
+	return func(v any) (T, error) {
+		typedV, err := convert(v)
+		if err != nil {
+			return typedV, err
+		}
+		valueKey := fmt.Sprintf("%#v", typedV)
+		result, ok := results.Load(valueKey)
+		if !ok {
+			var invalidErr error
+			if err := validate(typedV); err != nil {
+				invalidErr = &invalidValueError{value: typedV, err: err}
+			}
+			result, _ = results.LoadOrStore(valueKey, invalidErr)
+		}
+		if result != nil {
+			return typedV, result.(error)
+		}
+		return typedV, nil
+	}
+}
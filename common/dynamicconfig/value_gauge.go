@@ -0,0 +1,146 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// GaugeDimension is a filter value that the gauge of a setting can be tagged with, see
+	// ExportValueGauge.
+	GaugeDimension int
+
+	// GaugeValue is a value type that can be exported as a gauge. Durations are exported in
+	// seconds.
+	GaugeValue interface {
+		int | float64 | time.Duration
+	}
+)
+
+const (
+	// GaugeDimensionNamespace tags the gauge with the namespace.
+	GaugeDimensionNamespace GaugeDimension = iota + 1
+	// GaugeDimensionTaskQueue tags the gauge with the task queue name and type.
+	GaugeDimensionTaskQueue
+	// GaugeDimensionShardID tags the gauge with the shard ID.
+	GaugeDimensionShardID
+	// GaugeDimensionDestination tags the gauge with the destination.
+	GaugeDimensionDestination
+)
+
+// ExportValueGauge makes c emit the value of s as the dynamic_config_value gauge, tagged with the
+// key of s, whenever bound values are refreshed, see GlobalTypedSetting.Bind, and once right away.
+// It's meant for dashboards of the effective value of a setting, e.g. the max concurrent workflow
+// tasks of each namespace, without writing an exporter for each setting.
+//
+// To keep the cardinality of the gauge under control, it's only tagged with the given dimensions.
+// A value is emitted for each combination of them that's set in dynamic config for the key of s,
+// plus one with none of them set, e.g. for the default. Constraints on other filters are ignored
+// and the value is resolved for the combination alone, i.e. a task queue override doesn't change
+// the value exported for its namespace. A combination keeps being emitted after its override is
+// removed, with the value it falls back to.
+//
+// The returned function stops emitting the gauge and must be called once it's no longer needed.
+func ExportValueGauge[T GaugeValue](c *Collection, s ConstrainedSetting[T], dimensions ...GaugeDimension) func() {
+	gauge := metrics.DynamicConfigValue.With(c.metricsHandler)
+	keyTag := metrics.DynamicConfigKeyTag(s.Key().String())
+
+	var lock sync.Mutex
+	var exported []Constraints
+	emit := func() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if len(exported) == 0 {
+			exported = append(exported, Constraints{})
+		}
+		for _, cv := range c.getValue(s.Key()) {
+			cons := projectConstraints(cv.Constraints, dimensions)
+			if !slices.Contains(exported, cons) {
+				exported = append(exported, cons)
+			}
+		}
+		for _, cons := range exported {
+			tags := append([]metrics.Tag{keyTag}, gaugeDimensionTags(cons, dimensions)...)
+			gauge.Record(gaugeValue(s.resolveWithConstraints(context.Background(), c, cons)), tags...)
+		}
+	}
+	emit()
+	return c.bindings.add(c, emit)
+}
+
+// projectConstraints returns the filter values of cons that are in dimensions.
+func projectConstraints(cons Constraints, dimensions []GaugeDimension) Constraints {
+	var projected Constraints
+	for _, dimension := range dimensions {
+		switch dimension {
+		case GaugeDimensionNamespace:
+			projected.Namespace = cons.Namespace
+		case GaugeDimensionTaskQueue:
+			projected.TaskQueueName = cons.TaskQueueName
+			projected.TaskQueueType = cons.TaskQueueType
+		case GaugeDimensionShardID:
+			projected.ShardID = cons.ShardID
+		case GaugeDimensionDestination:
+			projected.Destination = cons.Destination
+		}
+	}
+	return projected
+}
+
+func gaugeDimensionTags(cons Constraints, dimensions []GaugeDimension) []metrics.Tag {
+	var tags []metrics.Tag
+	for _, dimension := range dimensions {
+		switch dimension {
+		case GaugeDimensionNamespace:
+			tags = append(tags, metrics.NamespaceTag(cons.Namespace))
+		case GaugeDimensionTaskQueue:
+			tags = append(tags, metrics.TaskQueueTag(cons.TaskQueueName), metrics.TaskQueueTypeTag(cons.TaskQueueType))
+		case GaugeDimensionShardID:
+			tags = append(tags, metrics.ShardIDTag(cons.ShardID, 1))
+		case GaugeDimensionDestination:
+			tags = append(tags, metrics.DestinationTag(cons.Destination))
+		}
+	}
+	return tags
+}
+
+func gaugeValue[T GaugeValue](val T) float64 {
+	switch val := any(val).(type) {
+	case time.Duration:
+		return val.Seconds()
+	case int:
+		return float64(val)
+	case float64:
+		return val
+	}
+	return 0
+}
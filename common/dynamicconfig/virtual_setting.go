@@ -0,0 +1,148 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"maps"
+	"strings"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+type (
+	// VirtualResolver computes the value of a virtual setting for the filter values in cons, see
+	// Collection.RegisterVirtualSetting. Filter values that the setting's Get function doesn't take
+	// are empty.
+	VirtualResolver func(cons Constraints) any
+)
+
+const virtualLayer = "virtual"
+
+// RegisterVirtualSetting makes c compute the value of the setting with the given key with
+// resolve, instead of looking it up in dynamic config, replacing any resolver registered for it
+// before. It's meant for values derived from other settings, e.g. an effective limit that's the
+// min of two others, so that call sites don't each duplicate the derivation:
+//
+//	c.RegisterVirtualSetting(EffectiveLimit.Key(), func(cons Constraints) any {
+//		return min(ResolveWithConstraints(c, LimitA, cons), ResolveWithConstraints(c, LimitB, cons))
+//	})
+//
+// The setting is still declared and read as usual, so its readers can't tell it's computed. The
+// resolver is called on each read, so changes to its inputs are seen right away, and by bound
+// values as soon as they're refreshed, see GlobalTypedSetting.Bind. Values of the key in dynamic
+// config are ignored. The resolved value goes through the setting's converter and validator, and
+// the setting's default is used if it's rejected.
+func (c *Collection) RegisterVirtualSetting(key Key, resolve VirtualResolver) {
+	c.virtualsLock.Lock()
+	defer c.virtualsLock.Unlock()
+
+	virtuals := make(map[string]VirtualResolver)
+	if old := c.virtuals.Load(); old != nil {
+		maps.Copy(virtuals, *old)
+	}
+	virtuals[strings.ToLower(key.String())] = resolve
+	c.virtuals.Store(&virtuals)
+}
+
+func (c *Collection) getVirtualResolver(key Key) VirtualResolver {
+	virtuals := c.virtuals.Load()
+	if virtuals == nil {
+		return nil
+	}
+	return (*virtuals)[strings.ToLower(key.String())]
+}
+
+// resolveVirtual is matchAndConvertWithLogger for a virtual setting. The first entry of the
+// precedence list has all the filter values of the read.
+func resolveVirtual[T any](
+	c *Collection,
+	logger log.Logger,
+	key Key,
+	def T,
+	cdef []TypedConstrainedValue[T],
+	convert func(value any) (T, error),
+	resolve VirtualResolver,
+	precedence []Constraints,
+) T {
+	val := resolve(virtualConstraints(precedence))
+	typedVal, err := convertLenient(c, logger, key, val, convert)
+	if err != nil {
+		if isInvalidValue(err) {
+			c.logInvalidValue(logger, key, val, err)
+		} else if c.throttleLog() {
+			logger.Warn("Failed to convert virtual setting value, using default", tag.Key(key.String()), tag.IgnoredValue(val), tag.Error(err))
+		}
+		typedVal, _ = convert(virtualDefault(def, cdef, precedence))
+	}
+	return typedVal
+}
+
+// traceVirtual is resolveVirtual, returning the provenance of the value. The value is attributed to
+// the "virtual" layer, the provenance of the inputs of the resolver isn't traced.
+func traceVirtual[T any](
+	key Key,
+	def T,
+	cdef []TypedConstrainedValue[T],
+	convert func(value any) (T, error),
+	resolve VirtualResolver,
+	precedence []Constraints,
+) TraceResult[T] {
+	cons := virtualConstraints(precedence)
+	val := resolve(cons)
+	result := TraceResult[T]{
+		Key:                key,
+		Match:              &TracedValue{ConstrainedValue: ConstrainedValue{Constraints: cons, Value: val}, Layer: virtualLayer},
+		MatchedConstraints: cons,
+		Raw:                val,
+	}
+	typedVal, err := convertQuiet(val, convert)
+	if err != nil {
+		result.ConvertError = err
+		typedVal, _ = convert(virtualDefault(def, cdef, precedence))
+	}
+	result.Value = typedVal
+	return result
+}
+
+// virtualDefault returns the default of a virtual setting for the filter values of the read, for
+// when the resolved value is rejected.
+func virtualDefault[T any](def T, cdef []TypedConstrainedValue[T], precedence []Constraints) any {
+	if cdef == nil {
+		return def
+	}
+	val, err := findMatch(nil, cdef, precedence)
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+func virtualConstraints(precedence []Constraints) Constraints {
+	if len(precedence) == 0 {
+		return Constraints{}
+	}
+	return precedence[0]
+}
@@ -71,12 +71,18 @@ const (
 const (
 	// AdminClientStreamWorkflowReplicationMessagesScope tracks RPC calls to admin service
 	AdminClientStreamWorkflowReplicationMessagesScope = "AdminClientStreamWorkflowReplicationMessages"
+	// AdminClientStreamWorkflowExecutionRawHistoryScope tracks RPC calls to admin service
+	AdminClientStreamWorkflowExecutionRawHistoryScope = "AdminClientStreamWorkflowExecutionRawHistory"
+	// AdminClientStreamImportWorkflowExecutionHistoryScope tracks RPC calls to admin service
+	AdminClientStreamImportWorkflowExecutionHistoryScope = "AdminClientStreamImportWorkflowExecutionHistory"
 )
 
 // History Client Operations
 const (
 	// HistoryClientStreamWorkflowReplicationMessagesScope tracks RPC calls to history service
 	HistoryClientStreamWorkflowReplicationMessagesScope = "HistoryClientStreamWorkflowReplicationMessages"
+	// HistoryClientStreamImportWorkflowExecutionHistoryScope tracks RPC calls to history service
+	HistoryClientStreamImportWorkflowExecutionHistoryScope = "HistoryClientStreamImportWorkflowExecutionHistory"
 )
 
 // Matching Client Operations
@@ -730,6 +736,10 @@ var (
 		"shardinfo_scheduled_queue_lag",
 		WithDescription("A histogram across history shards for the difference between the earliest scheduled time of pending history tasks and current time."),
 	)
+	ShardInfoSize = NewBytesHistogramDef(
+		"shardinfo_size",
+		WithDescription("A histogram across history shards for the size of the persisted shard info blob, which mostly consists of queue states."),
+	)
 	SyncShardFromRemoteCounter = NewCounterDef("syncshard_remote_count")
 	SyncShardFromRemoteFailure = NewCounterDef("syncshard_remote_failed")
 	TaskRequests               = NewCounterDef(
@@ -786,6 +796,10 @@ var (
 		"task_errors_workflow_busy",
 		WithDescription("The number of history task processing errors caused by failing to acquire workflow lock within the configured timeout (history.cacheNonUserContextLockTimeout)."),
 	)
+	TaskWorkflowQuarantinedCounter = NewCounterDef(
+		"task_errors_workflow_quarantined",
+		WithDescription("The number of history task processing attempts held because the workflow of the task is quarantined on the shard."),
+	)
 	TaskNotActiveCounter         = NewCounterDef("task_errors_not_active_counter")
 	TaskNamespaceHandoverCounter = NewCounterDef("task_errors_namespace_handover")
 	TaskInternalErrorCounter     = NewCounterDef("task_errors_internal")
@@ -929,6 +943,7 @@ var (
 	ReplicationDLQMaxLevelGauge                    = NewGaugeDef("replication_dlq_max_level")
 	ReplicationDLQAckLevelGauge                    = NewGaugeDef("replication_dlq_ack_level")
 	ReplicationNonEmptyDLQCount                    = NewCounterDef("replication_dlq_non_empty")
+	ReplicationSourcePaused                        = NewGaugeDef("replication_source_paused")
 	ReplicationOutlierNamespace                    = NewCounterDef("replication_outlier_namespace")
 	EventReapplySkippedCount                       = NewCounterDef("event_reapply_skipped_count")
 	DirectQueryDispatchLatency                     = NewTimerDef("direct_query_dispatch_latency")
@@ -954,11 +969,28 @@ var (
 	OutOfOrderBufferedEventsCounter                = NewCounterDef("out_of_order_buffered_events")
 	ShardLingerSuccess                             = NewTimerDef("shard_linger_success")
 	ShardLingerTimeouts                            = NewCounterDef("shard_linger_timeouts")
+	ShardLivenessProbeStuck                        = NewCounterDef("shard_liveness_probe_stuck")
+	ShardLockWaitLatency                           = NewTimerDef("shard_lock_wait_latency")
+	ShardMaintenanceMode                           = NewGaugeDef("shard_maintenance_mode")
+	ShardMaintenanceModeRejectedWrites             = NewCounterDef("shard_maintenance_mode_rejected_writes")
+	ShardAcquisitionDuration                       = NewTimerDef("shard_acquisition_duration")
+	ShardAcquisitionFailures                       = NewCounterDef("shard_acquisition_failures")
+	SpeculativeWorkflowTaskTimeoutTasksDrained     = NewCounterDef("speculative_workflow_task_timeout_tasks_drained")
+	SpeculativeWorkflowTaskTimeoutTasksDropped     = NewCounterDef("speculative_workflow_task_timeout_tasks_dropped")
+	TaskFallbackDeserializations                   = NewCounterDef("task_fallback_deserializations")
 	DynamicRateLimiterMultiplier                   = NewGaugeDef("dynamic_rate_limit_multiplier")
 	DLQWrites                                      = NewCounterDef(
 		"dlq_writes",
 		WithDescription("The number of times a message is enqueued to DLQ. DLQ can be inspected using tdbg dlq command."),
 	)
+	DynamicConfigResolveTimeouts = NewCounterDef(
+		"dynamic_config_resolve_timeouts",
+		WithDescription("The number of dynamic config lookups that timed out and used the setting's default value."),
+	)
+	DynamicConfigValue = NewGaugeDef(
+		"dynamic_config_value",
+		WithDescription("The resolved value of a dynamic config setting exported with dynamicconfig.ExportValueGauge."),
+	)
 	DLQMessageCount = NewGaugeDef(
 		"dlq_message_count",
 		WithDescription("The number of messages currently in DLQ."),
@@ -64,6 +64,8 @@ const (
 	reason = "reason"
 	// See server.api.enums.v1.ReplicationTaskType
 	replicationTaskType = "replicationTaskType"
+	shardID             = "shard_id"
+	dynamicConfigKey    = "dynamic_config_key"
 
 	namespaceAllValue = "all"
 	unknownValue      = "_unknown_"
@@ -159,6 +161,11 @@ func TargetClusterTag(value string) Tag {
 	return &tagImpl{key: targetCluster, value: value}
 }
 
+// DynamicConfigKeyTag returns a new dynamic config key tag.
+func DynamicConfigKeyTag(value string) Tag {
+	return &tagImpl{key: dynamicConfigKey, value: value}
+}
+
 // FromClusterIDTag returns a new from cluster tag.
 func FromClusterIDTag(value int32) Tag {
 	return &tagImpl{key: fromCluster, value: strconv.FormatInt(int64(value), 10)}
@@ -352,6 +359,16 @@ func ReasonTag(value ReasonString) Tag {
 	return &tagImpl{key: reason, value: string(value)}
 }
 
+// ShardIDTag returns a new shard ID tag. To limit cardinality, only one in every sampleEvery
+// shards is tagged with its ID, the other shards share the tagExcludedValue. If sampleEvery is
+// not positive, no shard is tagged with its ID.
+func ShardIDTag(id int32, sampleEvery int32) Tag {
+	if sampleEvery <= 0 || id%sampleEvery != 0 {
+		return &tagImpl{key: shardID, value: tagExcludedValue}
+	}
+	return &tagImpl{key: shardID, value: strconv.Itoa(int(id))}
+}
+
 // ReplicationTaskTypeTag returns a new replication task type tag.
 func ReplicationTaskTypeTag(value enumsspb.ReplicationTaskType) Tag {
 	return &tagImpl{key: replicationTaskType, value: value.String()}
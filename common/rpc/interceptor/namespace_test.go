@@ -167,6 +167,10 @@ func (s *namespaceSuite) TestHistoryAPIMetrics() {
 			continue
 		}
 		request := methodType.In(1)
+		if request.Kind() == reflect.Interface {
+			// server streaming APIs take the request first, then the stream
+			request = methodType.In(0)
+		}
 		if !request.Implements(namespaceIDGetter) {
 			s.Fail(fmt.Sprintf("API: %v not implementing NamespaceIDGetter", methodName))
 		}
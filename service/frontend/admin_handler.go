@@ -883,6 +883,83 @@ func (adh *AdminHandler) GetWorkflowExecutionRawHistory(
 	return response.Response, nil
 }
 
+// StreamWorkflowExecutionRawHistory streams the raw history of a workflow execution one page at a
+// time, each page is sent as soon as it's read.
+func (adh *AdminHandler) StreamWorkflowExecutionRawHistory(
+	request *adminservice.StreamWorkflowExecutionRawHistoryRequest,
+	server adminservice.AdminService_StreamWorkflowExecutionRawHistoryServer,
+) (retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	var token []byte
+	for {
+		response, err := adh.historyClient.GetWorkflowExecutionRawHistory(server.Context(),
+			&historyservice.GetWorkflowExecutionRawHistoryRequest{
+				NamespaceId: request.NamespaceId,
+				Request: &adminservice.GetWorkflowExecutionRawHistoryRequest{
+					NamespaceId:       request.NamespaceId,
+					Execution:         request.Execution,
+					StartEventId:      request.StartEventId,
+					StartEventVersion: request.StartEventVersion,
+					EndEventId:        request.EndEventId,
+					EndEventVersion:   request.EndEventVersion,
+					MaximumPageSize:   request.MaximumPageSize,
+					NextPageToken:     token,
+				},
+			})
+		if err != nil {
+			return err
+		}
+		if err := server.Send(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: response.Response.GetHistoryBatches(),
+			VersionHistory: response.Response.GetVersionHistory(),
+		}); err != nil {
+			return err
+		}
+		token = response.Response.GetNextPageToken()
+		if len(token) == 0 {
+			return nil
+		}
+	}
+}
+
+// StreamImportWorkflowExecutionHistory imports the history of a workflow from the source cluster and
+// forwards the progress of the import, as streamed by the history service, until the import is done.
+// Closing the stream cancels the import.
+func (adh *AdminHandler) StreamImportWorkflowExecutionHistory(
+	request *adminservice.StreamImportWorkflowExecutionHistoryRequest,
+	server adminservice.AdminService_StreamImportWorkflowExecutionHistoryServer,
+) (retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	stream, err := adh.historyClient.StreamImportWorkflowExecutionHistory(server.Context(),
+		&historyservice.StreamImportWorkflowExecutionHistoryRequest{
+			NamespaceId:   request.NamespaceId,
+			Execution:     request.Execution,
+			SourceCluster: request.SourceCluster,
+		})
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := server.Send(&adminservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    response.GetEventsApplied(),
+			CurrentEventId:   response.GetCurrentEventId(),
+			BytesTransferred: response.GetBytesTransferred(),
+			Done:             response.GetDone(),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
 // GetWorkflowExecutionRawHistoryV2 - retrieves the history of workflow execution
 func (adh *AdminHandler) GetWorkflowExecutionRawHistoryV2(ctx context.Context, request *adminservice.GetWorkflowExecutionRawHistoryV2Request) (_ *adminservice.GetWorkflowExecutionRawHistoryV2Response, retError error) {
 	defer log.CapturePanic(adh.logger, &retError)
@@ -28,6 +28,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"sync"
 	"testing"
@@ -43,6 +44,7 @@ import (
 	"go.temporal.io/api/serviceerror"
 	workflowpb "go.temporal.io/api/workflow/v1"
 	"go.temporal.io/api/workflowservice/v1"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/metadata"
@@ -50,6 +52,7 @@ import (
 	"go.temporal.io/server/api/adminservice/v1"
 	"go.temporal.io/server/api/adminservicemock/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
 	"go.temporal.io/server/api/historyservicemock/v1"
 
@@ -990,6 +993,140 @@ func (s *adminHandlerSuite) Test_ListClusters_Success() {
 	s.Equal(0, len(resp.GetNextPageToken()))
 }
 
+func (s *adminHandlerSuite) TestStreamWorkflowExecutionRawHistory() {
+	execution := &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()}
+	request := &adminservice.StreamWorkflowExecutionRawHistoryRequest{
+		NamespaceId:     s.namespaceID.String(),
+		Execution:       execution,
+		StartEventId:    1,
+		EndEventId:      10,
+		MaximumPageSize: 2,
+	}
+	versionHistory := &historyspb.VersionHistory{BranchToken: []byte("branch-token")}
+	pages := [][]*commonpb.DataBlob{
+		{{Data: []byte("batch-1")}, {Data: []byte("batch-2")}},
+		{{Data: []byte("batch-3")}},
+	}
+	server := adminservicemock.NewMockAdminService_StreamWorkflowExecutionRawHistoryServer(s.controller)
+	server.EXPECT().Context().Return(context.Background()).AnyTimes()
+	gomock.InOrder(
+		s.mockHistoryClient.EXPECT().GetWorkflowExecutionRawHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.GetWorkflowExecutionRawHistoryRequest, _ ...grpc.CallOption) (*historyservice.GetWorkflowExecutionRawHistoryResponse, error) {
+				s.Empty(request.Request.NextPageToken)
+				s.Equal(int32(2), request.Request.MaximumPageSize)
+				s.Equal(execution, request.Request.Execution)
+				return &historyservice.GetWorkflowExecutionRawHistoryResponse{
+					Response: &adminservice.GetWorkflowExecutionRawHistoryResponse{
+						HistoryBatches: pages[0],
+						VersionHistory: versionHistory,
+						NextPageToken:  []byte("token"),
+					},
+				}, nil
+			}),
+		server.EXPECT().Send(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: pages[0],
+			VersionHistory: versionHistory,
+		}).Return(nil),
+		s.mockHistoryClient.EXPECT().GetWorkflowExecutionRawHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.GetWorkflowExecutionRawHistoryRequest, _ ...grpc.CallOption) (*historyservice.GetWorkflowExecutionRawHistoryResponse, error) {
+				s.Equal([]byte("token"), request.Request.NextPageToken)
+				return &historyservice.GetWorkflowExecutionRawHistoryResponse{
+					Response: &adminservice.GetWorkflowExecutionRawHistoryResponse{
+						HistoryBatches: pages[1],
+						VersionHistory: versionHistory,
+					},
+				}, nil
+			}),
+		server.EXPECT().Send(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: pages[1],
+			VersionHistory: versionHistory,
+		}).Return(nil),
+	)
+
+	err := s.handler.StreamWorkflowExecutionRawHistory(request, server)
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) TestStreamImportWorkflowExecutionHistory() {
+	execution := &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()}
+	request := &adminservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId:   s.namespaceID.String(),
+		Execution:     execution,
+		SourceCluster: "source-cluster",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := adminservicemock.NewMockAdminService_StreamImportWorkflowExecutionHistoryServer(s.controller)
+	server.EXPECT().Context().Return(ctx).AnyTimes()
+	stream := historyservicemock.NewMockHistoryService_StreamImportWorkflowExecutionHistoryClient(s.controller)
+	s.mockHistoryClient.EXPECT().StreamImportWorkflowExecutionHistory(ctx, &historyservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId:   s.namespaceID.String(),
+		Execution:     execution,
+		SourceCluster: "source-cluster",
+	}).Return(stream, nil)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&historyservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    2,
+			CurrentEventId:   2,
+			BytesTransferred: 100,
+		}, nil),
+		server.EXPECT().Send(&adminservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    2,
+			CurrentEventId:   2,
+			BytesTransferred: 100,
+		}).Return(nil),
+		stream.EXPECT().Recv().Return(&historyservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    5,
+			CurrentEventId:   5,
+			BytesTransferred: 250,
+			Done:             true,
+		}, nil),
+		server.EXPECT().Send(&adminservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    5,
+			CurrentEventId:   5,
+			BytesTransferred: 250,
+			Done:             true,
+		}).Return(nil),
+		stream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	err := s.handler.StreamImportWorkflowExecutionHistory(request, server)
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) TestStreamImportWorkflowExecutionHistory_HistoryStreamFailed() {
+	request := &adminservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId:   s.namespaceID.String(),
+		Execution:     &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()},
+		SourceCluster: "source-cluster",
+	}
+	server := adminservicemock.NewMockAdminService_StreamImportWorkflowExecutionHistoryServer(s.controller)
+	server.EXPECT().Context().Return(context.Background()).AnyTimes()
+	stream := historyservicemock.NewMockHistoryService_StreamImportWorkflowExecutionHistoryClient(s.controller)
+	s.mockHistoryClient.EXPECT().StreamImportWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(stream, nil)
+	stream.EXPECT().Recv().Return(nil, serviceerror.NewUnavailable("import failed"))
+
+	err := s.handler.StreamImportWorkflowExecutionHistory(request, server)
+	s.IsType(&serviceerror.Unavailable{}, err)
+}
+
+func (s *adminHandlerSuite) TestStreamImportWorkflowExecutionHistory_SendFailed() {
+	request := &adminservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId:   s.namespaceID.String(),
+		Execution:     &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()},
+		SourceCluster: "source-cluster",
+	}
+	server := adminservicemock.NewMockAdminService_StreamImportWorkflowExecutionHistoryServer(s.controller)
+	server.EXPECT().Context().Return(context.Background()).AnyTimes()
+	stream := historyservicemock.NewMockHistoryService_StreamImportWorkflowExecutionHistoryClient(s.controller)
+	s.mockHistoryClient.EXPECT().StreamImportWorkflowExecutionHistory(gomock.Any(), gomock.Any()).Return(stream, nil)
+	stream.EXPECT().Recv().Return(&historyservice.StreamImportWorkflowExecutionHistoryResponse{EventsApplied: 1}, nil)
+	server.EXPECT().Send(gomock.Any()).Return(context.Canceled)
+
+	err := s.handler.StreamImportWorkflowExecutionHistory(request, server)
+	s.ErrorIs(err, context.Canceled)
+}
+
 func (s *adminHandlerSuite) TestStreamWorkflowReplicationMessages_ClientToServerBroken() {
 	clientClusterShardID := historyclient.ClusterShardID{
 		ClusterID: rand.Int31(),
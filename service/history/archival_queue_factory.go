@@ -125,6 +125,7 @@ func (f *archivalQueueFactory) CreateQueue(
 	workflowCache wcache.Cache,
 ) queues.Queue {
 	executor := f.newArchivalTaskExecutor(shard, workflowCache)
+	executor = queues.NewQuarantineExecutor(shard, executor)
 	if f.ExecutorWrapper != nil {
 		executor = f.ExecutorWrapper.Wrap(executor)
 	}
@@ -89,15 +89,56 @@ type Config struct {
 	// Change of these configs require service restart
 	EnableHostLevelEventsCache       dynamicconfig.BoolPropertyFn
 	EventsHostLevelCacheMaxSizeBytes dynamicconfig.IntPropertyFn
+	ImportBypassEventsCache          dynamicconfig.BoolPropertyFnWithNamespaceFilter
+	EventsCacheTopEntriesEnabled     dynamicconfig.BoolPropertyFn
+
+	// History import settings
+	ImportLeaseTTL                   dynamicconfig.DurationPropertyFn
+	ImportSearchAttributeNameMapping dynamicconfig.TypedPropertyFnWithNamespaceFilter[map[string]string]
+	ImportRecordProvenance           dynamicconfig.BoolPropertyFnWithNamespaceFilter
+	ImportMaxBranches                dynamicconfig.IntPropertyFnWithNamespaceFilter
+	ImportRebaseTimers               dynamicconfig.BoolPropertyFnWithNamespaceFilter
+	ImportChildWorkflowLinks         dynamicconfig.StringPropertyFnWithNamespaceFilter
+	ImportChildWorkflowMaxDepth      dynamicconfig.IntPropertyFnWithNamespaceFilter
+
+	ImportThrottleLatencyThreshold         dynamicconfig.DurationPropertyFn
+	ImportThrottleShardExecutionsThreshold dynamicconfig.IntPropertyFn
+	ImportThrottleMaxBackoff               dynamicconfig.DurationPropertyFn
 
 	// ShardController settings
-	RangeSizeBits                uint
-	AcquireShardInterval         dynamicconfig.DurationPropertyFn
-	AcquireShardConcurrency      dynamicconfig.IntPropertyFn
-	ShardIOConcurrency           dynamicconfig.IntPropertyFn
-	ShardIOTimeout               dynamicconfig.DurationPropertyFn
-	ShardLingerOwnershipCheckQPS dynamicconfig.IntPropertyFn
-	ShardLingerTimeLimit         dynamicconfig.DurationPropertyFn
+	RangeSizeBits                 uint
+	AcquireShardInterval          dynamicconfig.DurationPropertyFn
+	AcquireShardConcurrency       dynamicconfig.IntPropertyFn
+	ShardIOConcurrency            dynamicconfig.IntPropertyFn
+	ShardIOTimeout                dynamicconfig.DurationPropertyFn
+	ShardLingerOwnershipCheckQPS  dynamicconfig.IntPropertyFn
+	ShardLingerTimeLimit          dynamicconfig.DurationPropertyFn
+	ShardOwnershipFreshness       dynamicconfig.DurationPropertyFn
+	ShardLivenessProbeTimeout     dynamicconfig.DurationPropertyFn
+	ShardLockCaptureHolderStack   dynamicconfig.BoolPropertyFn
+	ShardLockWaitMetricsEnabled   dynamicconfig.BoolPropertyFn
+	ShardTaskIDReservationTimeout dynamicconfig.DurationPropertyFn
+	ShardReadRPS                  dynamicconfig.IntPropertyFnWithNamespaceFilter
+	ShardBatchReadConcurrency     dynamicconfig.IntPropertyFn
+	ShardQueueStateRestore        dynamicconfig.BoolPropertyFn
+
+	ShardCurrentExecutionCacheSize dynamicconfig.IntPropertyFn
+	ShardCurrentExecutionCacheTTL  dynamicconfig.DurationPropertyFn
+
+	ShardReplicationTaskAuditSize dynamicconfig.IntPropertyFn
+
+	ShardLoadEstimateMaxExecutions dynamicconfig.IntPropertyFn
+	ShardLoadEstimateTTL           dynamicconfig.DurationPropertyFn
+
+	ShardTaskGenerationHeatmapTopK      dynamicconfig.IntPropertyFn
+	ShardTaskGenerationHeatmapRetention dynamicconfig.DurationPropertyFn
+
+	ShardQueueDrainRateWindow dynamicconfig.DurationPropertyFn
+
+	ShardRepairCheckpointTTL      dynamicconfig.DurationPropertyFn
+	ShardRepairCheckpointMaxCount dynamicconfig.IntPropertyFn
+
+	ShardAcquisitionMetricsShardIDSampling dynamicconfig.IntPropertyFn
 
 	HistoryClientOwnershipCachingEnabled dynamicconfig.BoolPropertyFn
 
@@ -110,6 +151,7 @@ type Config struct {
 	QueueReaderStuckCriticalAttempts dynamicconfig.IntPropertyFn
 	QueueCriticalSlicesCount         dynamicconfig.IntPropertyFn
 	QueuePendingTaskMaxCount         dynamicconfig.IntPropertyFn
+	QueueStateCompactionThreshold    dynamicconfig.IntPropertyFn
 
 	TaskDLQEnabled                 dynamicconfig.BoolPropertyFn
 	TaskDLQUnexpectedErrorAttempts dynamicconfig.IntPropertyFn
@@ -412,15 +454,55 @@ func NewConfig(
 		EventsHostLevelCacheMaxSizeBytes:  dynamicconfig.EventsHostLevelCacheMaxSizeBytes.Get(dc), // 256MB
 		EventsCacheTTL:                    dynamicconfig.EventsCacheTTL.Get(dc),
 		EnableHostLevelEventsCache:        dynamicconfig.EnableHostLevelEventsCache.Get(dc),
+		EventsCacheTopEntriesEnabled:      dynamicconfig.EventsCacheTopEntriesEnabled.Get(dc),
+		ImportBypassEventsCache:           dynamicconfig.ImportBypassEventsCache.Get(dc),
+
+		ImportLeaseTTL:                   dynamicconfig.ImportLeaseTTL.Get(dc),
+		ImportSearchAttributeNameMapping: dynamicconfig.ImportSearchAttributeNameMapping.Get(dc),
+		ImportRecordProvenance:           dynamicconfig.ImportRecordProvenance.Get(dc),
+		ImportMaxBranches:                dynamicconfig.ImportMaxBranches.Get(dc),
+		ImportRebaseTimers:               dynamicconfig.ImportRebaseTimers.Get(dc),
+		ImportChildWorkflowLinks:         dynamicconfig.ImportChildWorkflowLinks.Get(dc),
+		ImportChildWorkflowMaxDepth:      dynamicconfig.ImportChildWorkflowMaxDepth.Get(dc),
+
+		ImportThrottleLatencyThreshold:         dynamicconfig.ImportThrottleLatencyThreshold.Get(dc),
+		ImportThrottleShardExecutionsThreshold: dynamicconfig.ImportThrottleShardExecutionsThreshold.Get(dc),
+		ImportThrottleMaxBackoff:               dynamicconfig.ImportThrottleMaxBackoff.Get(dc),
 
 		RangeSizeBits: 20, // 20 bits for sequencer, 2^20 sequence number for any range
 
-		AcquireShardInterval:         dynamicconfig.AcquireShardInterval.Get(dc),
-		AcquireShardConcurrency:      dynamicconfig.AcquireShardConcurrency.Get(dc),
-		ShardIOConcurrency:           dynamicconfig.ShardIOConcurrency.Get(dc),
-		ShardIOTimeout:               dynamicconfig.ShardIOTimeout.Get(dc),
-		ShardLingerOwnershipCheckQPS: dynamicconfig.ShardLingerOwnershipCheckQPS.Get(dc),
-		ShardLingerTimeLimit:         dynamicconfig.ShardLingerTimeLimit.Get(dc),
+		AcquireShardInterval:          dynamicconfig.AcquireShardInterval.Get(dc),
+		AcquireShardConcurrency:       dynamicconfig.AcquireShardConcurrency.Get(dc),
+		ShardIOConcurrency:            dynamicconfig.ShardIOConcurrency.Get(dc),
+		ShardIOTimeout:                dynamicconfig.ShardIOTimeout.Get(dc),
+		ShardLingerOwnershipCheckQPS:  dynamicconfig.ShardLingerOwnershipCheckQPS.Get(dc),
+		ShardLingerTimeLimit:          dynamicconfig.ShardLingerTimeLimit.Get(dc),
+		ShardOwnershipFreshness:       dynamicconfig.ShardOwnershipAssertionFreshness.Get(dc),
+		ShardLivenessProbeTimeout:     dynamicconfig.ShardLivenessProbeTimeout.Get(dc),
+		ShardLockCaptureHolderStack:   dynamicconfig.ShardLockCaptureHolderStack.Get(dc),
+		ShardLockWaitMetricsEnabled:   dynamicconfig.ShardLockWaitMetricsEnabled.Get(dc),
+		ShardTaskIDReservationTimeout: dynamicconfig.ShardTaskIDReservationTimeout.Get(dc),
+		ShardReadRPS:                  dynamicconfig.ShardReadRPS.Get(dc),
+		ShardBatchReadConcurrency:     dynamicconfig.ShardBatchReadConcurrency.Get(dc),
+		ShardQueueStateRestore:        dynamicconfig.ShardQueueStateRestoreEnabled.Get(dc),
+
+		ShardCurrentExecutionCacheSize: dynamicconfig.ShardCurrentExecutionCacheSize.Get(dc),
+		ShardCurrentExecutionCacheTTL:  dynamicconfig.ShardCurrentExecutionCacheTTL.Get(dc),
+
+		ShardReplicationTaskAuditSize: dynamicconfig.ShardReplicationTaskAuditSize.Get(dc),
+
+		ShardLoadEstimateMaxExecutions: dynamicconfig.ShardLoadEstimateMaxExecutions.Get(dc),
+		ShardLoadEstimateTTL:           dynamicconfig.ShardLoadEstimateTTL.Get(dc),
+
+		ShardTaskGenerationHeatmapTopK:      dynamicconfig.ShardTaskGenerationHeatmapTopK.Get(dc),
+		ShardTaskGenerationHeatmapRetention: dynamicconfig.ShardTaskGenerationHeatmapRetention.Get(dc),
+
+		ShardQueueDrainRateWindow: dynamicconfig.ShardQueueDrainRateWindow.Get(dc),
+
+		ShardRepairCheckpointTTL:      dynamicconfig.ShardRepairCheckpointTTL.Get(dc),
+		ShardRepairCheckpointMaxCount: dynamicconfig.ShardRepairCheckpointMaxCount.Get(dc),
+
+		ShardAcquisitionMetricsShardIDSampling: dynamicconfig.ShardAcquisitionMetricsShardIDSampling.Get(dc),
 
 		HistoryClientOwnershipCachingEnabled: dynamicconfig.HistoryClientOwnershipCachingEnabled.Get(dc),
 
@@ -432,6 +514,7 @@ func NewConfig(
 		QueueReaderStuckCriticalAttempts: dynamicconfig.QueueReaderStuckCriticalAttempts.Get(dc),
 		QueueCriticalSlicesCount:         dynamicconfig.QueueCriticalSlicesCount.Get(dc),
 		QueuePendingTaskMaxCount:         dynamicconfig.QueuePendingTaskMaxCount.Get(dc),
+		QueueStateCompactionThreshold:    dynamicconfig.QueueStateCompactionThreshold.Get(dc),
 
 		TaskDLQEnabled:                 dynamicconfig.HistoryTaskDLQEnabled.Get(dc),
 		TaskDLQUnexpectedErrorAttempts: dynamicconfig.HistoryTaskDLQUnexpectedErrorAttempts.Get(dc),
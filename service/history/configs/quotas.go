@@ -89,6 +89,7 @@ var (
 		"/temporal.server.api.historyservice.v1.HistoryService/PollWorkflowExecutionUpdate":            1,
 		"/temporal.server.api.historyservice.v1.HistoryService/ExecuteMultiOperation":                  1,
 		"/temporal.server.api.historyservice.v1.HistoryService/StreamWorkflowReplicationMessages":      1,
+		"/temporal.server.api.historyservice.v1.HistoryService/StreamImportWorkflowExecutionHistory":   1,
 		"/temporal.server.api.historyservice.v1.HistoryService/GetWorkflowExecutionHistory":            1,
 		"/temporal.server.api.historyservice.v1.HistoryService/GetWorkflowExecutionHistoryReverse":     1,
 		"/temporal.server.api.historyservice.v1.HistoryService/GetWorkflowExecutionRawHistory":         1,
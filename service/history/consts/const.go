@@ -49,6 +49,8 @@ var (
 	ErrTaskRetry = errors.New("passive task should retry due to condition in mutable state is not met")
 	// ErrDependencyTaskNotCompleted is the error returned when a task this task depends on is not completed yet
 	ErrDependencyTaskNotCompleted = errors.New("a task which this task depends on has not been completed yet")
+	// ErrWorkflowQuarantined is the error returned when the workflow of a task is quarantined on the shard, so the task is held
+	ErrWorkflowQuarantined = errors.New("workflow is quarantined, task is held until it is unquarantined")
 	// ErrDuplicate is exported temporarily for integration test
 	ErrDuplicate = errors.New("duplicate task, completing it")
 	// ErrLocateCurrentWorkflowExecution is the error returned when current workflow execution can't be located
@@ -129,6 +131,18 @@ var (
 		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_NAMESPACE,
 		Message: "Action per second limit exceeded.",
 	}
+	// ErrResourceExhaustedShardReadLimit is an error indicating a shard has reached its workflow execution read limit for a namespace
+	ErrResourceExhaustedShardReadLimit = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_SYSTEM,
+		Message: "Shard read rate limit exceeded.",
+	}
+	// ErrImportInProgress is an error indicating another history import is in progress for the same workflow
+	ErrImportInProgress = &serviceerror.ResourceExhausted{
+		Cause:   enumspb.RESOURCE_EXHAUSTED_CAUSE_BUSY_WORKFLOW,
+		Scope:   enumspb.RESOURCE_EXHAUSTED_SCOPE_NAMESPACE,
+		Message: "Another history import is in progress for the workflow.",
+	}
 	// ErrWorkflowClosedBeforeWorkflowTaskStarted is an error indicating workflow execution was closed before WorkflowTaskStarted event
 	ErrWorkflowClosedBeforeWorkflowTaskStarted = serviceerror.NewWorkflowNotReady("Workflow execution closed before WorkflowTaskStarted event")
 
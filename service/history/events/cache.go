@@ -27,7 +27,9 @@
 package events
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"time"
 
 	historypb "go.temporal.io/api/history/v1"
@@ -56,6 +58,28 @@ type (
 		GetEvent(ctx context.Context, shardID int32, key EventKey, firstEventID int64, branchToken []byte) (*historypb.HistoryEvent, error)
 		PutEvent(key EventKey, event *historypb.HistoryEvent)
 		DeleteEvent(key EventKey)
+		// TopEntries returns the n workflow runs with the largest cached events, largest first,
+		// considering only the events for which include returns true (all if include is nil).
+		// It's meant for diagnostics: it doesn't change the LRU order, but holds the cache lock
+		// while it scans the whole cache.
+		TopEntries(n int, include func(key EventKey) bool) []CacheEntryInfo
+		// SizeBytes returns the total serialized size of the cached events, which is what the max
+		// size of the cache is in. It's kept up to date as events are added and evicted, so it's
+		// cheap to call.
+		SizeBytes() int64
+	}
+
+	// CacheEntryInfo summarizes the cached events of a workflow run.
+	CacheEntryInfo struct {
+		NamespaceID namespace.ID
+		WorkflowID  string
+		RunID       string
+		// EventCount is the number of cached events, SizeBytes their total size.
+		EventCount int
+		SizeBytes  int
+		// MinEventID and MaxEventID are the smallest and largest cached event IDs.
+		MinEventID int64
+		MaxEventID int64
 	}
 
 	CacheImpl struct {
@@ -69,6 +93,10 @@ type (
 	historyEventCacheItemImpl struct {
 		event *historypb.HistoryEvent
 	}
+
+	writeBypassCache struct {
+		Cache
+	}
 )
 
 var (
@@ -97,6 +125,13 @@ func NewShardLevelEventsCache(
 	return newEventsCache(executionManager, handler, logger, config.EventsShardLevelCacheMaxSizeBytes(), config.EventsCacheTTL(), disabled)
 }
 
+// NewWriteBypassCache returns a Cache that reads from and deletes from the given cache, but
+// ignores PutEvent, so that bulk writers like history import don't evict entries used by live
+// traffic.
+func NewWriteBypassCache(cache Cache) Cache {
+	return &writeBypassCache{Cache: cache}
+}
+
 func newEventsCache(
 	executionManager persistence.ExecutionManager,
 	metricsHandler metrics.Handler,
@@ -189,6 +224,73 @@ func (e *CacheImpl) DeleteEvent(key EventKey) {
 	e.Delete(key)
 }
 
+func (c *writeBypassCache) PutEvent(_ EventKey, _ *historypb.HistoryEvent) {}
+
+func (e *CacheImpl) TopEntries(n int, include func(key EventKey) bool) []CacheEntryInfo {
+	if n <= 0 {
+		return nil
+	}
+
+	type runKey struct {
+		namespaceID namespace.ID
+		workflowID  string
+		runID       string
+	}
+	runs := make(map[runKey]*CacheEntryInfo)
+	it := e.Iterator()
+	for it.HasNext() {
+		entry := it.Next()
+		key, ok := entry.Key().(EventKey)
+		if !ok || (include != nil && !include(key)) {
+			continue
+		}
+		item, ok := entry.Value().(*historyEventCacheItemImpl)
+		if !ok {
+			continue
+		}
+		rk := runKey{namespaceID: key.NamespaceID, workflowID: key.WorkflowID, runID: key.RunID}
+		info, ok := runs[rk]
+		if !ok {
+			info = &CacheEntryInfo{
+				NamespaceID: key.NamespaceID,
+				WorkflowID:  key.WorkflowID,
+				RunID:       key.RunID,
+				MinEventID:  key.EventID,
+				MaxEventID:  key.EventID,
+			}
+			runs[rk] = info
+		}
+		info.EventCount++
+		info.SizeBytes += item.CacheSize()
+		info.MinEventID = min(info.MinEventID, key.EventID)
+		info.MaxEventID = max(info.MaxEventID, key.EventID)
+	}
+	it.Close()
+
+	result := make([]CacheEntryInfo, 0, len(runs))
+	for _, info := range runs {
+		result = append(result, *info)
+	}
+	slices.SortFunc(result, func(a, b CacheEntryInfo) int {
+		return cmp.Or(
+			cmp.Compare(b.SizeBytes, a.SizeBytes),
+			cmp.Compare(b.EventCount, a.EventCount),
+			cmp.Compare(a.NamespaceID, b.NamespaceID),
+			cmp.Compare(a.WorkflowID, b.WorkflowID),
+			cmp.Compare(a.RunID, b.RunID),
+		)
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+func (e *CacheImpl) SizeBytes() int64 {
+	// the size of each item is the serialized size of its event, see historyEventCacheItemImpl
+	return int64(e.Size())
+}
+
 func (e *CacheImpl) getHistoryEventFromStore(
 	ctx context.Context,
 	shardID int32,
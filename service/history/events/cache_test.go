@@ -324,3 +324,86 @@ func (s *eventsCacheSuite) TestEventsCacheInvalidKey() {
 		int64(11), branchToken)
 	s.Equal(gotEvent2, event1)
 }
+
+func (s *eventsCacheSuite) TestTopEntries() {
+	eventsCache := newEventsCache(s.mockExecutionManager,
+		metrics.NoopMetricsHandler,
+		s.logger,
+		1024*1024,
+		time.Minute,
+		false)
+	namespaceID := namespace.ID("events-cache-top-entries-namespace")
+	putEvents := func(workflowID string, eventIDs ...int64) {
+		for _, eventID := range eventIDs {
+			eventsCache.PutEvent(
+				EventKey{namespaceID, workflowID, "run-id", eventID, common.EmptyVersion},
+				&historypb.HistoryEvent{EventId: eventID, EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED},
+			)
+		}
+	}
+	putEvents("small", 3)
+	putEvents("large", 10, 4, 7)
+	putEvents("medium", 8, 9)
+
+	entries := eventsCache.TopEntries(2, nil)
+	s.Len(entries, 2)
+	s.Equal("large", entries[0].WorkflowID)
+	s.Equal(3, entries[0].EventCount)
+	s.Equal(int64(4), entries[0].MinEventID)
+	s.Equal(int64(10), entries[0].MaxEventID)
+	s.Equal("medium", entries[1].WorkflowID)
+	s.Equal(2, entries[1].EventCount)
+
+	entries = eventsCache.TopEntries(10, func(key EventKey) bool { return key.WorkflowID == "small" })
+	s.Len(entries, 1)
+	s.Equal("small", entries[0].WorkflowID)
+	s.Nil(eventsCache.TopEntries(0, nil))
+
+	// the report doesn't touch the LRU order, most recently used first
+	var eventIDs []int64
+	it := eventsCache.Iterator()
+	for it.HasNext() {
+		eventIDs = append(eventIDs, it.Next().Key().(EventKey).EventID)
+	}
+	it.Close()
+	s.Equal([]int64{9, 8, 7, 4, 10, 3}, eventIDs)
+}
+
+func (s *eventsCacheSuite) TestSizeBytes() {
+	newEvent := func(eventID int64) *historypb.HistoryEvent {
+		return &historypb.HistoryEvent{EventId: eventID, EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED}
+	}
+	eventSize := int64(newEvent(10).Size())
+	eventsCache := newEventsCache(s.mockExecutionManager,
+		metrics.NoopMetricsHandler,
+		s.logger,
+		3*int(eventSize),
+		time.Minute,
+		false)
+	key := func(eventID int64) EventKey {
+		return EventKey{namespace.ID("events-cache-size-namespace"), "workflow-id", "run-id", eventID, common.EmptyVersion}
+	}
+	s.Zero(eventsCache.SizeBytes())
+
+	for eventID := int64(10); eventID < 13; eventID++ {
+		eventsCache.PutEvent(key(eventID), newEvent(eventID))
+	}
+	s.Equal(3*eventSize, eventsCache.SizeBytes())
+
+	// the least recently used event is evicted to make room
+	eventsCache.PutEvent(key(13), newEvent(13))
+	s.Equal(3*eventSize, eventsCache.SizeBytes())
+	s.Nil(eventsCache.Get(key(10)))
+
+	eventsCache.DeleteEvent(key(11))
+	s.Equal(2*eventSize, eventsCache.SizeBytes())
+
+	// replacing an event accounts for the size difference
+	smallEvent := &historypb.HistoryEvent{EventId: 12}
+	eventsCache.PutEvent(key(12), smallEvent)
+	s.Equal(eventSize+int64(smallEvent.Size()), eventsCache.SizeBytes())
+
+	// bypassed writes don't change the size
+	NewWriteBypassCache(eventsCache).PutEvent(key(14), newEvent(14))
+	s.Equal(eventSize+int64(smallEvent.Size()), NewWriteBypassCache(eventsCache).SizeBytes())
+}
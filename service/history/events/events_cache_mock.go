@@ -97,3 +97,31 @@ func (mr *MockCacheMockRecorder) PutEvent(key, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutEvent", reflect.TypeOf((*MockCache)(nil).PutEvent), key, event)
 }
+
+// SizeBytes mocks base method.
+func (m *MockCache) SizeBytes() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SizeBytes")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// SizeBytes indicates an expected call of SizeBytes.
+func (mr *MockCacheMockRecorder) SizeBytes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SizeBytes", reflect.TypeOf((*MockCache)(nil).SizeBytes))
+}
+
+// TopEntries mocks base method.
+func (m *MockCache) TopEntries(n int, include func(EventKey) bool) []CacheEntryInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopEntries", n, include)
+	ret0, _ := ret[0].([]CacheEntryInfo)
+	return ret0
+}
+
+// TopEntries indicates an expected call of TopEntries.
+func (mr *MockCacheMockRecorder) TopEntries(n, include interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopEntries", reflect.TypeOf((*MockCache)(nil).TopEntries), n, include)
+}
@@ -162,6 +162,7 @@ func HandlerProvider(args NewHandlerArgs) *Handler {
 		replicationTaskFetcherFactory:    args.ReplicationTaskFetcherFactory,
 		replicationTaskConverterProvider: args.ReplicationTaskConverterFactory,
 		streamReceiverMonitor:            args.StreamReceiverMonitor,
+		localEventsHandler:               args.LocalEventsHandler,
 	}
 
 	// prevent us from trying to serve requests before shard controller is started and ready
@@ -73,6 +73,7 @@ import (
 	"go.temporal.io/server/service/history/events"
 	"go.temporal.io/server/service/history/hsm"
 	"go.temporal.io/server/service/history/replication"
+	"go.temporal.io/server/service/history/replication/eventhandler"
 	"go.temporal.io/server/service/history/shard"
 	"go.temporal.io/server/service/history/tasks"
 )
@@ -112,6 +113,7 @@ type (
 		replicationTaskFetcherFactory    replication.TaskFetcherFactory
 		replicationTaskConverterProvider replication.SourceTaskConverterProvider
 		streamReceiverMonitor            replication.StreamReceiverMonitor
+		localEventsHandler               eventhandler.LocalGeneratedEventsHandler
 	}
 
 	NewHandlerArgs struct {
@@ -142,6 +144,7 @@ type (
 		ReplicationTaskFetcherFactory   replication.TaskFetcherFactory
 		ReplicationTaskConverterFactory replication.SourceTaskConverterProvider
 		StreamReceiverMonitor           replication.StreamReceiverMonitor
+		LocalEventsHandler              eventhandler.LocalGeneratedEventsHandler
 	}
 )
 
@@ -796,6 +799,70 @@ func (h *Handler) ImportWorkflowExecution(ctx context.Context, request *historys
 	return resp, nil
 }
 
+// StreamImportWorkflowExecutionHistory imports the history of a workflow from the source cluster and
+// sends the progress of the import to server until the import is done.
+func (h *Handler) StreamImportWorkflowExecutionHistory(
+	request *historyservice.StreamImportWorkflowExecutionHistoryRequest,
+	server historyservice.HistoryService_StreamImportWorkflowExecutionHistoryServer,
+) (retError error) {
+	defer metrics.CapturePanic(h.logger, h.metricsHandler, &retError)
+	h.startWG.Wait()
+
+	if h.isStopped() {
+		return errShuttingDown
+	}
+
+	namespaceID := namespace.ID(request.GetNamespaceId())
+	if namespaceID == "" {
+		return h.convertError(errNamespaceNotSet)
+	}
+	workflowID := request.GetExecution().GetWorkflowId()
+	if workflowID == "" {
+		return h.convertError(errWorkflowIDNotSet)
+	}
+	runID := request.GetExecution().GetRunId()
+	if runID == "" {
+		return h.convertError(errRunIDNotValid)
+	}
+	if request.GetSourceCluster() == "" {
+		return h.convertError(errSourceClusterNotSet)
+	}
+	if !h.clusterMetadata.IsGlobalNamespaceEnabled() {
+		return serviceerror.NewUnimplemented("StreamImportWorkflowExecutionHistory must be used in global namespace mode")
+	}
+
+	err := h.localEventsHandler.StreamImportHistoryEventsFromBeginning(
+		request.GetSourceCluster(),
+		definition.NewWorkflowKey(namespaceID.String(), workflowID, runID),
+		nil,
+		nil,
+		importProgressServer{server: server},
+	)
+	if err != nil {
+		return h.convertError(err)
+	}
+	return nil
+}
+
+// importProgressServer adapts a StreamImportWorkflowExecutionHistory stream to the
+// eventhandler.ImportProgressStream the import reports its progress to.
+type importProgressServer struct {
+	server historyservice.HistoryService_StreamImportWorkflowExecutionHistoryServer
+}
+
+func (s importProgressServer) Context() context.Context {
+	return s.server.Context()
+}
+
+func (s importProgressServer) Send(progress *eventhandler.ImportEventsProgress) error {
+	return s.server.Send(&historyservice.StreamImportWorkflowExecutionHistoryResponse{
+		EventsApplied:    progress.EventsApplied,
+		CurrentEventId:   progress.CurrentEventID,
+		BytesTransferred: progress.BytesTransferred,
+		Done:             progress.Done,
+	})
+}
+
 // DescribeMutableState - returns the internal analysis of workflow execution state
 func (h *Handler) DescribeMutableState(ctx context.Context, request *historyservice.DescribeMutableStateRequest) (_ *historyservice.DescribeMutableStateResponse, retError error) {
 	defer metrics.CapturePanic(h.logger, h.metricsHandler, &retError)
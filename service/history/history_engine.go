@@ -245,6 +245,7 @@ func NewEngineWithShardContext(
 		historyEngImpl.nDCHistoryImporter = ndc.NewHistoryImporter(
 			shard,
 			workflowCache,
+			persistenceVisibilityMgr,
 			logger,
 		)
 		historyEngImpl.nDCActivityStateReplicator = ndc.NewActivityStateReplicator(
@@ -779,6 +780,21 @@ func (e *historyEngineImpl) ImportWorkflowExecution(
 	}, nil
 }
 
+func (e *historyEngineImpl) ReconcileVersionHistory(
+	ctx context.Context,
+	workflowKey definition.WorkflowKey,
+	sourceVersionHistory *historyspb.VersionHistory,
+) error {
+	return e.nDCHistoryImporter.ReconcileVersionHistory(ctx, workflowKey, sourceVersionHistory)
+}
+
+func (e *historyEngineImpl) ReconcileCurrentExecution(
+	ctx context.Context,
+	workflowKey definition.WorkflowKey,
+) error {
+	return e.nDCHistoryImporter.ReconcileCurrentExecution(ctx, workflowKey)
+}
+
 func (e *historyEngineImpl) SyncShardStatus(
 	ctx context.Context,
 	request *historyservice.SyncShardStatusRequest,
@@ -984,7 +1000,7 @@ func (e *historyEngineImpl) AddTasks(
 	return addtasks.Invoke(
 		ctx,
 		e.shardContext,
-		e.eventSerializer,
+		e.shardContext.GetPayloadSerializer(),
 		int(e.config.NumberOfShards),
 		request,
 		e.taskCategoryRegistry,
@@ -36,17 +36,23 @@ import (
 
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/server/api/adminservice/v1"
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
+	"go.temporal.io/server/api/historyservicemock/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/definition"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/visibility/manager"
 	"go.temporal.io/server/service/history/api/forcedeleteworkflowexecution"
+	"go.temporal.io/server/service/history/replication/eventhandler"
 	"go.temporal.io/server/service/history/tests"
 )
 
@@ -189,3 +195,81 @@ func (s *historyAPISuite) TestDeleteWorkflowExecution_LoadMutableStateFailed() {
 	_, err := forcedeleteworkflowexecution.Invoke(context.Background(), request, shardID, s.mockExecutionMgr, s.mockVisibilityMgr, s.logger)
 	s.NoError(err)
 }
+
+func (s *historyAPISuite) TestStreamImportWorkflowExecutionHistory() {
+	execution := &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()}
+	request := &historyservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId:   tests.NamespaceID.String(),
+		Execution:     execution,
+		SourceCluster: "source-cluster",
+	}
+	handler, localEventsHandler := s.newStreamImportHandler()
+	server := historyservicemock.NewMockHistoryService_StreamImportWorkflowExecutionHistoryServer(s.controller)
+	server.EXPECT().Context().Return(context.Background()).AnyTimes()
+	localEventsHandler.EXPECT().StreamImportHistoryEventsFromBeginning(
+		"source-cluster",
+		definition.NewWorkflowKey(tests.NamespaceID.String(), execution.WorkflowId, execution.RunId),
+		gomock.Nil(),
+		gomock.Nil(),
+		gomock.Any(),
+	).DoAndReturn(func(_ string, _ definition.WorkflowKey, _ []*historyspb.VersionHistoryItem, _ *eventhandler.HistoryImportManifest, stream eventhandler.ImportProgressStream) error {
+		s.NoError(stream.Send(&eventhandler.ImportEventsProgress{EventsApplied: 2, CurrentEventID: 2, BytesTransferred: 100}))
+		return stream.Send(&eventhandler.ImportEventsProgress{EventsApplied: 5, CurrentEventID: 5, BytesTransferred: 250, Done: true})
+	})
+	gomock.InOrder(
+		server.EXPECT().Send(&historyservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    2,
+			CurrentEventId:   2,
+			BytesTransferred: 100,
+		}).Return(nil),
+		server.EXPECT().Send(&historyservice.StreamImportWorkflowExecutionHistoryResponse{
+			EventsApplied:    5,
+			CurrentEventId:   5,
+			BytesTransferred: 250,
+			Done:             true,
+		}).Return(nil),
+	)
+
+	err := handler.StreamImportWorkflowExecutionHistory(request, server)
+	s.NoError(err)
+}
+
+func (s *historyAPISuite) TestStreamImportWorkflowExecutionHistory_SourceClusterNotSet() {
+	request := &historyservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId: tests.NamespaceID.String(),
+		Execution:   &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()},
+	}
+	handler, _ := s.newStreamImportHandler()
+	server := historyservicemock.NewMockHistoryService_StreamImportWorkflowExecutionHistoryServer(s.controller)
+
+	err := handler.StreamImportWorkflowExecutionHistory(request, server)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+}
+
+func (s *historyAPISuite) TestStreamImportWorkflowExecutionHistory_ImportFailed() {
+	request := &historyservice.StreamImportWorkflowExecutionHistoryRequest{
+		NamespaceId:   tests.NamespaceID.String(),
+		Execution:     &commonpb.WorkflowExecution{WorkflowId: "workflowID", RunId: uuid.New()},
+		SourceCluster: "source-cluster",
+	}
+	handler, localEventsHandler := s.newStreamImportHandler()
+	server := historyservicemock.NewMockHistoryService_StreamImportWorkflowExecutionHistoryServer(s.controller)
+	localEventsHandler.EXPECT().StreamImportHistoryEventsFromBeginning(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(serviceerror.NewNotFound("workflow not found"))
+
+	err := handler.StreamImportWorkflowExecutionHistory(request, server)
+	s.IsType(&serviceerror.NotFound{}, err)
+}
+
+func (s *historyAPISuite) newStreamImportHandler() (*Handler, *eventhandler.MockLocalGeneratedEventsHandler) {
+	clusterMetadata := cluster.NewMockMetadata(s.controller)
+	clusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
+	localEventsHandler := eventhandler.NewMockLocalGeneratedEventsHandler(s.controller)
+	return &Handler{
+		status:             common.DaemonStatusStarted,
+		logger:             s.logger,
+		metricsHandler:     metrics.NoopMetricsHandler,
+		clusterMetadata:    clusterMetadata,
+		localEventsHandler: localEventsHandler,
+	}, localEventsHandler
+}
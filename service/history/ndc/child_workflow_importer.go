@@ -0,0 +1,183 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -copyright_file ../../../LICENSE -package $GOPACKAGE -source $GOFILE -destination child_workflow_importer_mock.go
+
+package ndc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
+	"go.temporal.io/server/api/historyservice/v1"
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/service/history/shard"
+)
+
+const (
+	// ImportChildWorkflowLinksImport and ImportChildWorkflowLinksUnresolved are the values of
+	// history.importChildWorkflowLinks.
+	ImportChildWorkflowLinksImport     = "import"
+	ImportChildWorkflowLinksUnresolved = "unresolved"
+
+	// importChildWorkflowDepthHeaderName carries how many more levels of children an import of a
+	// child workflow may import, so that the depth limit holds across shards.
+	importChildWorkflowDepthHeaderName = "import-child-workflow-depth"
+
+	childWorkflowImportPageSize = 1000
+)
+
+type (
+	// ChildWorkflowImporter looks up and imports the children of a workflow that is being imported.
+	ChildWorkflowImporter interface {
+		// Exists returns whether the run exists in this cluster.
+		Exists(
+			ctx context.Context,
+			namespaceID namespace.ID,
+			execution *commonpb.WorkflowExecution,
+		) (bool, error)
+		// Import imports the run from sourceCluster, along with its own children up to depth levels
+		// down. It returns false if the run doesn't exist in sourceCluster either.
+		Import(
+			ctx context.Context,
+			sourceCluster string,
+			namespaceID namespace.ID,
+			execution *commonpb.WorkflowExecution,
+			depth int,
+		) (bool, error)
+	}
+
+	ChildWorkflowImporterImpl struct {
+		shardContext shard.Context
+	}
+)
+
+func NewChildWorkflowImporter(
+	shardContext shard.Context,
+) *ChildWorkflowImporterImpl {
+	return &ChildWorkflowImporterImpl{
+		shardContext: shardContext,
+	}
+}
+
+func (i *ChildWorkflowImporterImpl) Exists(
+	ctx context.Context,
+	namespaceID namespace.ID,
+	execution *commonpb.WorkflowExecution,
+) (bool, error) {
+	_, err := i.shardContext.GetHistoryClient().DescribeMutableState(ctx, &historyservice.DescribeMutableStateRequest{
+		NamespaceId: namespaceID.String(),
+		Execution:   execution,
+	})
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (i *ChildWorkflowImporterImpl) Import(
+	ctx context.Context,
+	sourceCluster string,
+	namespaceID namespace.ID,
+	execution *commonpb.WorkflowExecution,
+	depth int,
+) (bool, error) {
+	adminClient, err := i.shardContext.GetRemoteAdminClient(sourceCluster)
+	if err != nil {
+		return false, err
+	}
+	historyClient := i.shardContext.GetHistoryClient()
+	ctx = metadata.AppendToOutgoingContext(ctx, importChildWorkflowDepthHeaderName, strconv.Itoa(depth))
+
+	var pageToken, importToken []byte
+	var versionHistory *historyspb.VersionHistory
+	for {
+		historyResponse, err := adminClient.GetWorkflowExecutionRawHistoryV2(ctx, &adminservice.GetWorkflowExecutionRawHistoryV2Request{
+			NamespaceId:     namespaceID.String(),
+			Execution:       execution,
+			MaximumPageSize: childWorkflowImportPageSize,
+			NextPageToken:   pageToken,
+		})
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) && importToken == nil {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		versionHistory = historyResponse.GetVersionHistory()
+		importResponse, err := historyClient.ImportWorkflowExecution(ctx, &historyservice.ImportWorkflowExecutionRequest{
+			NamespaceId:    namespaceID.String(),
+			Execution:      execution,
+			HistoryBatches: historyResponse.GetHistoryBatches(),
+			VersionHistory: versionHistory,
+			Token:          importToken,
+		})
+		if err != nil {
+			return false, err
+		}
+		importToken = importResponse.GetToken()
+		pageToken = historyResponse.GetNextPageToken()
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	// commit
+	if _, err := historyClient.ImportWorkflowExecution(ctx, &historyservice.ImportWorkflowExecutionRequest{
+		NamespaceId:    namespaceID.String(),
+		Execution:      execution,
+		VersionHistory: versionHistory,
+		Token:          importToken,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// importChildWorkflowDepth returns how many levels of children the import in ctx may import, if it's
+// the import of a child workflow.
+func importChildWorkflowDepth(ctx context.Context) (int, bool) {
+	value := headers.GetValues(ctx, importChildWorkflowDepthHeaderName)[0]
+	if value == "" {
+		return 0, false
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return depth, true
+}
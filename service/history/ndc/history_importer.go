@@ -25,20 +25,37 @@
 package ndc
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/pborman/uuid"
+	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
 	"go.temporal.io/api/serviceerror"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	historyspb "go.temporal.io/server/api/history/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/convert"
 	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/payload"
+	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/service/history/consts"
 	"go.temporal.io/server/service/history/shard"
 	"go.temporal.io/server/service/history/workflow"
 	wcache "go.temporal.io/server/service/history/workflow/cache"
@@ -53,37 +70,91 @@ type (
 			events [][]*historypb.HistoryEvent,
 			token []byte,
 		) ([]byte, bool, error)
+		ReconcileVersionHistory(
+			ctx context.Context,
+			workflowKey definition.WorkflowKey,
+			sourceVersionHistory *historyspb.VersionHistory,
+		) error
+		ReconcileCurrentExecution(
+			ctx context.Context,
+			workflowKey definition.WorkflowKey,
+		) error
 	}
 
 	HistoryImporterImpl struct {
-		shardContext   shard.Context
-		namespaceCache namespace.Registry
-		workflowCache  wcache.Cache
-		taskRefresher  workflow.TaskRefresher
-		transactionMgr TransactionManager
-		logger         log.Logger
+		shardContext      shard.Context
+		namespaceCache    namespace.Registry
+		workflowCache     wcache.Cache
+		visibilityManager manager.VisibilityManager
+		taskRefresher     workflow.TaskRefresher
+		transactionMgr    TransactionManager
+		childImporter     ChildWorkflowImporter
+		logger            log.Logger
 
 		mutableStateInitializer *MutableStateInitializerImpl
 		mutableStateMapper      *MutableStateMapperImpl
+
+		// An import spans multiple ImportWorkflow calls chained by token, and the workflow lock
+		// is only held within a call. Leases keep a second import of the same workflow from
+		// interleaving with the first one.
+		leaseLock sync.Mutex
+		leases    map[definition.WorkflowKey]importLease
+	}
+
+	importLease struct {
+		importID  string
+		expiresAt time.Time
+	}
+
+	// ImportProvenance records where and when a workflow was imported from. It's stored in the memo
+	// of imported workflows under ImportProvenanceMemoKey if history.importRecordProvenance is on.
+	ImportProvenance struct {
+		// SourceCluster is the cluster of the last imported event's version.
+		SourceCluster string    `json:"sourceCluster"`
+		ImportTime    time.Time `json:"importTime"`
+		// Client is the client name header of the import request, e.g. temporal-cli, if any.
+		Client string `json:"client,omitempty"`
+	}
+
+	// UnresolvedChildWorkflow is a started, pending child of an imported workflow that doesn't exist
+	// in this cluster. They're stored in the memo of imported workflows under
+	// ImportUnresolvedChildWorkflowsMemoKey, see history.importChildWorkflowLinks.
+	UnresolvedChildWorkflow struct {
+		NamespaceID      string `json:"namespaceId"`
+		WorkflowID       string `json:"workflowId"`
+		RunID            string `json:"runId"`
+		InitiatedEventID int64  `json:"initiatedEventId"`
 	}
 )
 
+const (
+	// ImportProvenanceMemoKey is the memo key of the ImportProvenance of an imported workflow.
+	ImportProvenanceMemoKey = "__temporal_import_provenance"
+	// ImportUnresolvedChildWorkflowsMemoKey is the memo key of the UnresolvedChildWorkflows of an
+	// imported workflow.
+	ImportUnresolvedChildWorkflowsMemoKey = "__temporal_import_unresolved_children"
+)
+
 func NewHistoryImporter(
 	shardContext shard.Context,
 	workflowCache wcache.Cache,
+	visibilityManager manager.VisibilityManager,
 	logger log.Logger,
 ) *HistoryImporterImpl {
 	logger = log.With(logger, tag.ComponentHistoryImporter)
 	backfiller := &HistoryImporterImpl{
-		shardContext:   shardContext,
-		namespaceCache: shardContext.GetNamespaceRegistry(),
-		workflowCache:  workflowCache,
+		shardContext:      shardContext,
+		namespaceCache:    shardContext.GetNamespaceRegistry(),
+		workflowCache:     workflowCache,
+		visibilityManager: visibilityManager,
 		taskRefresher: workflow.NewTaskRefresher(
 			shardContext,
 			logger,
 		),
 		transactionMgr: NewTransactionManager(shardContext, workflowCache, nil, logger, true),
+		childImporter:  NewChildWorkflowImporter(shardContext),
 		logger:         logger,
+		leases:         make(map[definition.WorkflowKey]importLease),
 
 		mutableStateInitializer: NewMutableStateInitializer(
 			shardContext,
@@ -151,7 +222,30 @@ func (r *HistoryImporterImpl) ImportWorkflow(
 		ndcWorkflow.GetReleaseFn()(retError)
 	}()
 
+	if len(token) == 0 {
+		mutableStateSpec.ImportID = uuid.New()
+	}
+	if mutableStateSpec.ImportID != "" {
+		if err := r.acquireImportLease(workflowKey, mutableStateSpec.ImportID); err != nil {
+			return nil, false, err
+		}
+		defer func() {
+			// a failed import is retried from the beginning, and the lease is released once
+			// the import is committed
+			if retError != nil || len(eventsSlice) == 0 {
+				r.releaseImportLease(workflowKey, mutableStateSpec.ImportID)
+			}
+		}()
+	}
+
 	if len(eventsSlice) != 0 {
+		if err := r.remapSearchAttributes(workflowKey, eventsSlice); err != nil {
+			return nil, false, err
+		}
+		lastBatch := eventsSlice[len(eventsSlice)-1]
+		if len(lastBatch) != 0 && lastBatch[len(lastBatch)-1].GetEventTime() != nil {
+			mutableStateSpec.LastEventTime = lastBatch[len(lastBatch)-1].GetEventTime().AsTime()
+		}
 		return r.applyEvents(
 			ctx,
 			ndcWorkflow,
@@ -172,6 +266,144 @@ func (r *HistoryImporterImpl) ImportWorkflow(
 	return nil, false, nil
 }
 
+// acquireImportLease acquires or renews the lease on workflowKey for the given import. It fails
+// with ErrImportInProgress if another import holds an unexpired lease on the workflow.
+func (r *HistoryImporterImpl) acquireImportLease(
+	workflowKey definition.WorkflowKey,
+	importID string,
+) error {
+	now := r.shardContext.GetTimeSource().Now()
+
+	r.leaseLock.Lock()
+	defer r.leaseLock.Unlock()
+
+	if lease, ok := r.leases[workflowKey]; ok && lease.importID != importID && now.Before(lease.expiresAt) {
+		return consts.ErrImportInProgress
+	}
+	if _, ok := r.leases[workflowKey]; !ok {
+		// drop leases of abandoned imports so that they don't accumulate
+		for key, lease := range r.leases {
+			if !now.Before(lease.expiresAt) {
+				delete(r.leases, key)
+			}
+		}
+	}
+	r.leases[workflowKey] = importLease{
+		importID:  importID,
+		expiresAt: now.Add(r.shardContext.GetConfig().ImportLeaseTTL()),
+	}
+	return nil
+}
+
+func (r *HistoryImporterImpl) releaseImportLease(
+	workflowKey definition.WorkflowKey,
+	importID string,
+) {
+	r.leaseLock.Lock()
+	defer r.leaseLock.Unlock()
+
+	if lease, ok := r.leases[workflowKey]; ok && lease.importID == importID {
+		delete(r.leases, workflowKey)
+	}
+}
+
+// remapSearchAttributes renames the custom search attributes of imported events from the field names
+// used by the source cluster to the field names of the same aliases in this cluster, according to the
+// ImportSearchAttributeNameMapping config. Search attributes that can't be mapped are dropped, so that
+// a single unknown attribute doesn't fail the whole import.
+func (r *HistoryImporterImpl) remapSearchAttributes(
+	workflowKey definition.WorkflowKey,
+	eventsSlice [][]*historypb.HistoryEvent,
+) error {
+	namespaceEntry, err := r.namespaceCache.GetNamespaceByID(namespace.ID(workflowKey.NamespaceID))
+	if err != nil {
+		return err
+	}
+	nsName := namespaceEntry.Name()
+	aliases := r.shardContext.GetConfig().ImportSearchAttributeNameMapping(nsName.String())
+	if len(aliases) == 0 {
+		return nil
+	}
+	mapper, err := r.shardContext.GetSearchAttributesMapperProvider().GetMapper(nsName)
+	if err != nil {
+		return err
+	}
+
+	for _, events := range eventsSlice {
+		for _, event := range events {
+			searchAttributes := getEventSearchAttributes(event)
+			if len(searchAttributes.GetIndexedFields()) == 0 {
+				continue
+			}
+			indexedFields := make(map[string]*commonpb.Payload, len(searchAttributes.IndexedFields))
+			for saName, saPayload := range searchAttributes.IndexedFields {
+				if !searchattribute.IsMappable(saName) {
+					indexedFields[saName] = saPayload
+					continue
+				}
+				fieldName, err := r.mapSearchAttributeName(mapper, aliases, saName, nsName)
+				if err != nil {
+					return err
+				}
+				if fieldName == "" {
+					r.logger.Warn("Dropping unmapped search attribute from imported event",
+						tag.WorkflowNamespace(nsName.String()),
+						tag.WorkflowID(workflowKey.WorkflowID),
+						tag.WorkflowRunID(workflowKey.RunID),
+						tag.WorkflowEventID(event.GetEventId()),
+						tag.NewStringTag("search-attribute", saName),
+					)
+					continue
+				}
+				indexedFields[fieldName] = saPayload
+			}
+			searchAttributes.IndexedFields = indexedFields
+		}
+	}
+	return nil
+}
+
+// mapSearchAttributeName returns the field name in this cluster for the given source cluster field
+// name, or an empty string if either the source name or its alias is unknown.
+func (r *HistoryImporterImpl) mapSearchAttributeName(
+	mapper searchattribute.Mapper,
+	aliases map[string]string,
+	saName string,
+	nsName namespace.Name,
+) (string, error) {
+	alias, ok := aliases[saName]
+	if !ok {
+		return "", nil
+	}
+	if mapper == nil {
+		return alias, nil
+	}
+	fieldName, err := mapper.GetFieldName(alias, nsName.String())
+	if err != nil {
+		if _, isInvalidArgument := err.(*serviceerror.InvalidArgument); isInvalidArgument {
+			// alias is not registered in this cluster
+			return "", nil
+		}
+		return "", err
+	}
+	return fieldName, nil
+}
+
+func getEventSearchAttributes(event *historypb.HistoryEvent) *commonpb.SearchAttributes {
+	switch event.GetEventType() {
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED:
+		return event.GetWorkflowExecutionStartedEventAttributes().GetSearchAttributes()
+	case enumspb.EVENT_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES:
+		return event.GetUpsertWorkflowSearchAttributesEventAttributes().GetSearchAttributes()
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW:
+		return event.GetWorkflowExecutionContinuedAsNewEventAttributes().GetSearchAttributes()
+	case enumspb.EVENT_TYPE_START_CHILD_WORKFLOW_EXECUTION_INITIATED:
+		return event.GetStartChildWorkflowExecutionInitiatedEventAttributes().GetSearchAttributes()
+	default:
+		return nil
+	}
+}
+
 func (r *HistoryImporterImpl) applyEvents(
 	ctx context.Context,
 	ndcWorkflow Workflow,
@@ -336,6 +568,8 @@ func (r *HistoryImporterImpl) persistHistoryAndSerializeMutableState(
 		mutableStateSpec.DBRecordVersion,
 		mutableStateSpec.DBHistorySize,
 		mutableStateSpec.ExistsInDB,
+		mutableStateSpec.ImportID,
+		mutableStateSpec.LastEventTime,
 	)
 }
 
@@ -349,6 +583,18 @@ func (r *HistoryImporterImpl) commit(
 	}
 
 	if !mutableStateSpec.ExistsInDB {
+		if err := r.recordImportProvenance(ctx, memNDCWorkflow.GetMutableState()); err != nil {
+			return err
+		}
+		if err := r.resolveChildWorkflowLinks(ctx, memNDCWorkflow.GetMutableState()); err != nil {
+			return err
+		}
+		if err := r.limitBranches(memNDCWorkflow.GetMutableState()); err != nil {
+			return err
+		}
+		if err := r.rebaseTimers(memNDCWorkflow.GetMutableState(), mutableStateSpec.LastEventTime); err != nil {
+			return err
+		}
 		// refresh tasks to be generated
 		if err := r.taskRefresher.RefreshTasks(
 			ctx,
@@ -359,6 +605,9 @@ func (r *HistoryImporterImpl) commit(
 		memMutableState := memNDCWorkflow.GetMutableState()
 		nextEventID, _ := memMutableState.GetUpdateCondition()
 		memMutableState.SetUpdateCondition(nextEventID, mutableStateSpec.DBRecordVersion)
+		if err := r.recordVisibility(ctx, memMutableState); err != nil {
+			return err
+		}
 		if err := r.transactionMgr.CreateWorkflow(
 			ctx,
 			memNDCWorkflow,
@@ -429,6 +678,9 @@ func (r *HistoryImporterImpl) commit(
 			r.logger.Error("HistoryImporter::commit unable to update version history from DB", tag.Error(err))
 			return err
 		}
+		if err := r.limitBranches(dbNDCWorkflow.GetMutableState()); err != nil {
+			return err
+		}
 		sizeDiff := memNDCWorkflow.GetMutableState().GetHistorySize() - mutableStateSpec.DBHistorySize
 		dbNDCWorkflow.GetMutableState().AddHistorySize(sizeDiff)
 		if err := dbNDCWorkflow.GetContext().SetWorkflowExecution(ctx, r.shardContext); err != nil {
@@ -439,6 +691,18 @@ func (r *HistoryImporterImpl) commit(
 
 	// cmpResult > 0
 	dbNDCWorkflow.GetContext().Clear()
+	if err := r.recordImportProvenance(ctx, memNDCWorkflow.GetMutableState()); err != nil {
+		return err
+	}
+	if err := r.resolveChildWorkflowLinks(ctx, memNDCWorkflow.GetMutableState()); err != nil {
+		return err
+	}
+	if err := r.limitBranches(memNDCWorkflow.GetMutableState()); err != nil {
+		return err
+	}
+	if err := r.rebaseTimers(memNDCWorkflow.GetMutableState(), mutableStateSpec.LastEventTime); err != nil {
+		return err
+	}
 	// imported events is the new current branch, update write to DB
 	// refresh tasks to be generated
 	if err := r.taskRefresher.RefreshTasks(
@@ -450,6 +714,9 @@ func (r *HistoryImporterImpl) commit(
 	memMutableState := memNDCWorkflow.GetMutableState()
 	nextEventID, _ := memMutableState.GetUpdateCondition()
 	memMutableState.SetUpdateCondition(nextEventID, mutableStateSpec.DBRecordVersion)
+	if err := r.recordVisibility(ctx, memMutableState); err != nil {
+		return err
+	}
 	if err := r.transactionMgr.UpdateWorkflow(
 		ctx,
 		true,
@@ -461,3 +728,563 @@ func (r *HistoryImporterImpl) commit(
 	}
 	return nil
 }
+
+// recordVisibility writes the visibility record of a workflow that is being imported, with the memo
+// and search attributes of its imported mutable state, right before the execution is written. The
+// visibility tasks of the import write the record again once they're processed, but until then the
+// imported execution would be missing from visibility, or listed with the memo and search attributes
+// of a previous import. If the execution write fails, the record is left for the retry of the import
+// to overwrite, as the execution might have been written anyway.
+func (r *HistoryImporterImpl) recordVisibility(
+	ctx context.Context,
+	mutableState workflow.MutableState,
+) error {
+	// the record is versioned by task ID, so the visibility tasks generated by the import, which get
+	// task IDs when the execution is written, replace it
+	taskID, err := r.shardContext.GenerateTaskID()
+	if err != nil {
+		return err
+	}
+	namespaceEntry := mutableState.GetNamespaceEntry()
+	executionInfo := mutableState.GetExecutionInfo()
+	executionState := mutableState.GetExecutionState()
+	var parentExecution *commonpb.WorkflowExecution
+	if executionInfo.ParentWorkflowId != "" && executionInfo.ParentRunId != "" {
+		parentExecution = &commonpb.WorkflowExecution{
+			WorkflowId: executionInfo.ParentWorkflowId,
+			RunId:      executionInfo.ParentRunId,
+		}
+	}
+	requestBase := &manager.VisibilityRequestBase{
+		NamespaceID: namespaceEntry.ID(),
+		Namespace:   namespaceEntry.Name(),
+		Execution: &commonpb.WorkflowExecution{
+			WorkflowId: executionInfo.WorkflowId,
+			RunId:      executionState.RunId,
+		},
+		WorkflowTypeName: executionInfo.WorkflowTypeName,
+		StartTime:        timestamp.TimeValue(executionInfo.GetStartTime()),
+		Status:           executionState.GetStatus(),
+		ExecutionTime:    timestamp.TimeValue(executionInfo.GetExecutionTime()),
+		TaskID:           taskID,
+		ShardID:          r.shardContext.GetShardID(),
+		TaskQueue:        executionInfo.TaskQueue,
+		ParentExecution:  parentExecution,
+		RootExecution: &commonpb.WorkflowExecution{
+			WorkflowId: executionInfo.RootWorkflowId,
+			RunId:      executionInfo.RootRunId,
+		},
+	}
+	if executionInfo.Memo != nil {
+		requestBase.Memo = &commonpb.Memo{Fields: executionInfo.Memo}
+	}
+	if executionInfo.SearchAttributes != nil {
+		requestBase.SearchAttributes = &commonpb.SearchAttributes{IndexedFields: executionInfo.SearchAttributes}
+	}
+
+	if mutableState.IsWorkflowExecutionRunning() {
+		err = r.visibilityManager.UpsertWorkflowExecution(ctx, &manager.UpsertWorkflowExecutionRequest{
+			VisibilityRequestBase: requestBase,
+		})
+	} else {
+		closeTime, err := mutableState.GetWorkflowCloseTime(ctx)
+		if err != nil {
+			return err
+		}
+		executionDuration, err := mutableState.GetWorkflowExecutionDuration(ctx)
+		if err != nil {
+			return err
+		}
+		err = r.visibilityManager.RecordWorkflowExecutionClosed(ctx, &manager.RecordWorkflowExecutionClosedRequest{
+			VisibilityRequestBase: requestBase,
+			CloseTime:             closeTime,
+			ExecutionDuration:     executionDuration,
+			HistoryLength:         mutableState.GetNextEventID() - 1,
+			HistorySizeBytes:      executionInfo.GetExecutionStats().GetHistorySize(),
+			StateTransitionCount:  executionInfo.GetStateTransitionCount(),
+		})
+	}
+	if err != nil {
+		r.logger.Error("HistoryImporter::commit unable to record visibility", tag.Error(err))
+		return err
+	}
+	return nil
+}
+
+// limitBranches drops the least recent non-current branches from the version histories of a
+// workflow that is being imported, so that at most ImportMaxBranches are kept besides the current
+// branch. The events of dropped branches are left to the history scavenger.
+func (r *HistoryImporterImpl) limitBranches(
+	mutableState workflow.MutableState,
+) error {
+	maxBranches := r.shardContext.GetConfig().ImportMaxBranches(mutableState.GetNamespaceEntry().Name().String())
+	if maxBranches <= 0 {
+		return nil
+	}
+	executionInfo := mutableState.GetExecutionInfo()
+	dropped, err := trimVersionHistories(executionInfo.GetVersionHistories(), maxBranches)
+	if err != nil {
+		return err
+	}
+	for _, versionHistory := range dropped {
+		lastItem, _ := versionhistory.GetLastVersionHistoryItem(versionHistory)
+		r.logger.Warn("HistoryImporter::commit dropped version history branch over the import branch limit",
+			tag.WorkflowNamespaceID(executionInfo.GetNamespaceId()),
+			tag.WorkflowID(executionInfo.GetWorkflowId()),
+			tag.LastEventVersion(lastItem.GetVersion()),
+			tag.WorkflowEventID(lastItem.GetEventId()),
+		)
+	}
+	return nil
+}
+
+// trimVersionHistories removes all but the maxBranches most recent non-current version histories,
+// keeping the order of the remaining ones, and returns the removed ones.
+func trimVersionHistories(
+	versionHistories *historyspb.VersionHistories,
+	maxBranches int,
+) ([]*historyspb.VersionHistory, error) {
+	histories := versionHistories.GetHistories()
+	if len(histories) <= maxBranches+1 {
+		return nil, nil
+	}
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(versionHistories)
+	if err != nil {
+		return nil, err
+	}
+
+	// most recent first
+	others := slices.DeleteFunc(slices.Clone(histories), func(h *historyspb.VersionHistory) bool {
+		return h == currentVersionHistory
+	})
+	var compareErr error
+	slices.SortStableFunc(others, func(a, b *historyspb.VersionHistory) int {
+		result, err := versionhistory.CompareVersionHistory(b, a)
+		if err != nil {
+			compareErr = err
+		}
+		return result
+	})
+	if compareErr != nil {
+		return nil, compareErr
+	}
+	dropped := others[maxBranches:]
+
+	kept := make([]*historyspb.VersionHistory, 0, maxBranches+1)
+	for _, h := range histories {
+		if slices.Contains(dropped, h) {
+			continue
+		}
+		if h == currentVersionHistory {
+			versionHistories.CurrentVersionHistoryIndex = int32(len(kept))
+		}
+		kept = append(kept, h)
+	}
+	versionHistories.Histories = kept
+	return dropped, nil
+}
+
+// rebaseTimers moves the deadlines of a workflow that is being imported forward by the time between
+// its last imported event and now, if enabled for the namespace. This keeps the time left until each
+// deadline as it was in the source cluster, instead of firing every deadline that passed while the
+// workflow was exported at once. The imported events themselves are already persisted and keep
+// their timestamps.
+func (r *HistoryImporterImpl) rebaseTimers(
+	mutableState workflow.MutableState,
+	lastEventTime time.Time,
+) error {
+	if !r.shardContext.GetConfig().ImportRebaseTimers(mutableState.GetNamespaceEntry().Name().String()) {
+		return nil
+	}
+	if lastEventTime.IsZero() {
+		// imported with a token issued before the last event time was tracked
+		return nil
+	}
+	offset := r.shardContext.GetTimeSource().Now().Sub(lastEventTime)
+	if offset <= 0 {
+		return nil
+	}
+
+	for _, timerInfo := range mutableState.GetPendingTimerInfos() {
+		timerInfo.ExpiryTime = shiftTimestamp(timerInfo.ExpiryTime, offset)
+		if err := mutableState.UpdateUserTimer(timerInfo); err != nil {
+			return err
+		}
+	}
+	for _, activityInfo := range mutableState.GetPendingActivityInfos() {
+		activityInfo.ScheduledTime = shiftTimestamp(activityInfo.ScheduledTime, offset)
+		activityInfo.StartedTime = shiftTimestamp(activityInfo.StartedTime, offset)
+		activityInfo.LastHeartbeatUpdateTime = shiftTimestamp(activityInfo.LastHeartbeatUpdateTime, offset)
+		activityInfo.RetryExpirationTime = shiftTimestamp(activityInfo.RetryExpirationTime, offset)
+		if err := mutableState.UpdateActivity(activityInfo); err != nil {
+			return err
+		}
+	}
+	executionInfo := mutableState.GetExecutionInfo()
+	executionInfo.WorkflowRunExpirationTime = shiftTimestamp(executionInfo.WorkflowRunExpirationTime, offset)
+	executionInfo.WorkflowExecutionExpirationTime = shiftTimestamp(executionInfo.WorkflowExecutionExpirationTime, offset)
+
+	r.logger.Info("HistoryImporter::commit rebased workflow timers to import time",
+		tag.WorkflowNamespaceID(executionInfo.GetNamespaceId()),
+		tag.WorkflowID(executionInfo.GetWorkflowId()),
+		tag.NewDurationTag("offset", offset),
+	)
+	return nil
+}
+
+// shiftTimestamp returns t moved by offset, leaving unset timestamps unset.
+func shiftTimestamp(t *timestamppb.Timestamp, offset time.Duration) *timestamppb.Timestamp {
+	if t == nil || t.AsTime().IsZero() {
+		return t
+	}
+	return timestamppb.New(t.AsTime().Add(offset))
+}
+
+// recordImportProvenance adds the ImportProvenance of a workflow that is being imported to its memo, if
+// enabled for the namespace. The memo isn't part of the imported events, so workflow logic isn't
+// affected, but the provenance shows up wherever the memo does, e.g. DescribeWorkflowExecution.
+func (r *HistoryImporterImpl) recordImportProvenance(
+	ctx context.Context,
+	mutableState workflow.MutableState,
+) error {
+	namespaceEntry := mutableState.GetNamespaceEntry()
+	if !r.shardContext.GetConfig().ImportRecordProvenance(namespaceEntry.Name().String()) {
+		return nil
+	}
+
+	sourceCluster, err := r.sourceCluster(mutableState)
+	if err != nil {
+		return err
+	}
+	clientName, _ := headers.GetClientNameAndVersion(ctx)
+	provenance, err := payload.Encode(ImportProvenance{
+		SourceCluster: sourceCluster,
+		ImportTime:    r.shardContext.GetTimeSource().Now().UTC(),
+		Client:        clientName,
+	})
+	if err != nil {
+		return err
+	}
+	executionInfo := mutableState.GetExecutionInfo()
+	executionInfo.Memo = payload.MergeMapOfPayload(
+		executionInfo.Memo,
+		map[string]*commonpb.Payload{ImportProvenanceMemoKey: provenance},
+	)
+	return nil
+}
+
+// GetImportProvenance returns the ImportProvenance recorded in the memo of an imported workflow. It
+// returns false if the workflow wasn't imported, or was imported with history.importRecordProvenance
+// off.
+func GetImportProvenance(memo map[string]*commonpb.Payload) (ImportProvenance, bool, error) {
+	var provenance ImportProvenance
+	p, ok := memo[ImportProvenanceMemoKey]
+	if !ok {
+		return provenance, false, nil
+	}
+	if err := payload.Decode(p, &provenance); err != nil {
+		return provenance, false, err
+	}
+	return provenance, true, nil
+}
+
+// sourceCluster returns the cluster of the last imported event's version of a workflow that is being
+// imported.
+func (r *HistoryImporterImpl) sourceCluster(
+	mutableState workflow.MutableState,
+) (string, error) {
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(mutableState.GetExecutionInfo().GetVersionHistories())
+	if err != nil {
+		return "", err
+	}
+	lastItem, err := versionhistory.GetLastVersionHistoryItem(currentVersionHistory)
+	if err != nil {
+		return "", err
+	}
+	return r.shardContext.GetClusterMetadata().ClusterNameForFailoverVersion(
+		mutableState.GetNamespaceEntry().IsGlobalNamespace(),
+		lastItem.GetVersion(),
+	), nil
+}
+
+// resolveChildWorkflowLinks handles the started, pending children of a workflow that is being
+// imported which don't exist in this cluster, as configured by history.importChildWorkflowLinks for
+// the namespace. Children are imported from the source cluster up to
+// history.importChildWorkflowMaxDepth levels down, or for children of an imported child, as many
+// levels as are left. Children that aren't imported are added to the memo as
+// UnresolvedChildWorkflows, since the workflow would otherwise wait on them forever.
+func (r *HistoryImporterImpl) resolveChildWorkflowLinks(
+	ctx context.Context,
+	mutableState workflow.MutableState,
+) error {
+	namespaceName := mutableState.GetNamespaceEntry().Name().String()
+	mode := r.shardContext.GetConfig().ImportChildWorkflowLinks(namespaceName)
+	if mode != ImportChildWorkflowLinksImport && mode != ImportChildWorkflowLinksUnresolved {
+		return nil
+	}
+	depth, ok := importChildWorkflowDepth(ctx)
+	if !ok {
+		depth = r.shardContext.GetConfig().ImportChildWorkflowMaxDepth(namespaceName)
+	}
+
+	executionInfo := mutableState.GetExecutionInfo()
+	childInfos := maps.Values(mutableState.GetPendingChildExecutionInfos())
+	slices.SortFunc(childInfos, func(a, b *persistencespb.ChildExecutionInfo) int {
+		return cmp.Compare(a.GetInitiatedEventId(), b.GetInitiatedEventId())
+	})
+	var unresolved []UnresolvedChildWorkflow
+	for _, childInfo := range childInfos {
+		if childInfo.GetStartedRunId() == "" {
+			// not started yet, the child is started once the workflow is imported
+			continue
+		}
+		namespaceID := namespace.ID(childInfo.GetNamespaceId())
+		if namespaceID == "" {
+			namespaceID = mutableState.GetNamespaceEntry().ID()
+		}
+		execution := &commonpb.WorkflowExecution{
+			WorkflowId: childInfo.GetStartedWorkflowId(),
+			RunId:      childInfo.GetStartedRunId(),
+		}
+		exists, err := r.childImporter.Exists(ctx, namespaceID, execution)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if mode == ImportChildWorkflowLinksImport && depth > 0 {
+			sourceCluster, err := r.sourceCluster(mutableState)
+			if err != nil {
+				return err
+			}
+			imported, err := r.childImporter.Import(ctx, sourceCluster, namespaceID, execution, depth-1)
+			if err != nil {
+				return err
+			}
+			if imported {
+				continue
+			}
+		}
+		unresolved = append(unresolved, UnresolvedChildWorkflow{
+			NamespaceID:      namespaceID.String(),
+			WorkflowID:       execution.GetWorkflowId(),
+			RunID:            execution.GetRunId(),
+			InitiatedEventID: childInfo.GetInitiatedEventId(),
+		})
+	}
+	if len(unresolved) == 0 {
+		return nil
+	}
+
+	r.logger.Warn("HistoryImporter::commit marked child workflows of imported workflow unresolved",
+		tag.WorkflowNamespaceID(executionInfo.GetNamespaceId()),
+		tag.WorkflowID(executionInfo.GetWorkflowId()),
+		tag.Counter(len(unresolved)),
+	)
+	unresolvedPayload, err := payload.Encode(unresolved)
+	if err != nil {
+		return err
+	}
+	executionInfo.Memo = payload.MergeMapOfPayload(
+		executionInfo.Memo,
+		map[string]*commonpb.Payload{ImportUnresolvedChildWorkflowsMemoKey: unresolvedPayload},
+	)
+	return nil
+}
+
+// GetImportUnresolvedChildWorkflows returns the UnresolvedChildWorkflows recorded in the memo of an
+// imported workflow, if any.
+func GetImportUnresolvedChildWorkflows(memo map[string]*commonpb.Payload) ([]UnresolvedChildWorkflow, error) {
+	p, ok := memo[ImportUnresolvedChildWorkflowsMemoKey]
+	if !ok {
+		return nil, nil
+	}
+	var unresolved []UnresolvedChildWorkflow
+	if err := payload.Decode(p, &unresolved); err != nil {
+		return nil, err
+	}
+	return unresolved, nil
+}
+
+// ReconcileVersionHistory repairs the current version history of an existing workflow whose events
+// are all present locally, but whose version history is incomplete or diverged. The version history
+// is rebuilt from the local events, which must agree with sourceVersionHistory, the authoritative
+// version history from the source cluster. Only local event bodies are read.
+//
+// If local events are missing, or don't agree with the source, the workflow can't be repaired this
+// way and a FailedPrecondition error is returned; the workflow needs a full import instead.
+func (r *HistoryImporterImpl) ReconcileVersionHistory(
+	ctx context.Context,
+	workflowKey definition.WorkflowKey,
+	sourceVersionHistory *historyspb.VersionHistory,
+) (retError error) {
+	sourceLastItem, err := versionhistory.GetLastVersionHistoryItem(sourceVersionHistory)
+	if err != nil {
+		return err
+	}
+
+	ndcWorkflow, err := r.transactionMgr.LoadWorkflow(
+		ctx,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+		workflowKey.RunID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			ndcWorkflow.GetReleaseFn()(errPanic)
+			panic(rec)
+		} else {
+			ndcWorkflow.GetReleaseFn()(retError)
+		}
+	}()
+
+	mutableState := ndcWorkflow.GetMutableState()
+	localVersionHistory, err := versionhistory.GetCurrentVersionHistory(mutableState.GetExecutionInfo().GetVersionHistories())
+	if err != nil {
+		return err
+	}
+	lastEventID := mutableState.GetNextEventID() - 1
+	if sourceLastItem.GetEventId() > lastEventID {
+		return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+			"unable to reconcile version history: local history ends at event %v but source history ends at event %v, run a full import instead",
+			lastEventID,
+			sourceLastItem.GetEventId(),
+		))
+	}
+
+	rebuiltVersionHistory, err := r.rebuildVersionHistory(ctx, localVersionHistory.GetBranchToken(), lastEventID)
+	if err != nil {
+		return err
+	}
+	lcaItem, err := versionhistory.FindLCAVersionHistoryItem(rebuiltVersionHistory, sourceVersionHistory)
+	if err != nil || !versionhistory.IsEqualVersionHistoryItem(lcaItem, sourceLastItem) {
+		return serviceerror.NewFailedPrecondition(
+			"unable to reconcile version history: local events diverged from source history, run a full import instead",
+		)
+	}
+
+	if versionhistory.IsEqualVersionHistoryItems(localVersionHistory.GetItems(), rebuiltVersionHistory.GetItems()) {
+		return nil
+	}
+	r.logger.Info("HistoryImporter::ReconcileVersionHistory repairing version history",
+		tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+		tag.WorkflowID(workflowKey.WorkflowID),
+		tag.WorkflowRunID(workflowKey.RunID),
+	)
+	localVersionHistory.Items = rebuiltVersionHistory.GetItems()
+	return ndcWorkflow.GetContext().SetWorkflowExecution(ctx, r.shardContext)
+}
+
+// ReconcileCurrentExecution points the current execution record of a workflow at the run of
+// workflowKey, which must exist locally, if it doesn't point there already. The run that was current
+// before is suppressed, the same way replication does when a newer run arrives. If that run happens
+// after the run of workflowKey, the record is left unchanged and a FailedPrecondition error is
+// returned.
+func (r *HistoryImporterImpl) ReconcileCurrentExecution(
+	ctx context.Context,
+	workflowKey definition.WorkflowKey,
+) error {
+	namespaceID := namespace.ID(workflowKey.NamespaceID)
+	currentRunID, err := r.transactionMgr.GetCurrentWorkflowRunID(ctx, namespaceID, workflowKey.WorkflowID)
+	if err != nil {
+		return err
+	}
+	if currentRunID == workflowKey.RunID {
+		return nil
+	}
+
+	targetWorkflow, err := r.transactionMgr.LoadWorkflow(ctx, namespaceID, workflowKey.WorkflowID, workflowKey.RunID)
+	if err != nil {
+		return err
+	}
+	currentWorkflow, err := r.transactionMgr.LoadWorkflow(ctx, namespaceID, workflowKey.WorkflowID, currentRunID)
+	if err != nil {
+		targetWorkflow.GetReleaseFn()(err)
+		return err
+	}
+	targetWorkflowIsNewer, err := targetWorkflow.HappensAfter(currentWorkflow)
+	// the transaction manager loads the current workflow again
+	currentWorkflow.GetReleaseFn()(err)
+	if err != nil {
+		targetWorkflow.GetReleaseFn()(err)
+		return err
+	}
+	if !targetWorkflowIsNewer {
+		targetWorkflow.GetReleaseFn()(nil)
+		return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+			"unable to reconcile current execution: local current run %v happens after run %v",
+			currentRunID,
+			workflowKey.RunID,
+		))
+	}
+
+	r.logger.Info("HistoryImporter::ReconcileCurrentExecution repairing current execution",
+		tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+		tag.WorkflowID(workflowKey.WorkflowID),
+		tag.WorkflowRunID(workflowKey.RunID),
+		tag.NewStringTag("current-run-id", currentRunID),
+	)
+	// the transaction manager releases the target workflow once the transaction is executed
+	return r.transactionMgr.UpdateWorkflow(ctx, true, targetWorkflow, nil)
+}
+
+// rebuildVersionHistory builds the version history of events [1, lastEventID] of the given branch
+// from the events themselves. It fails with FailedPrecondition if any of the events are missing.
+func (r *HistoryImporterImpl) rebuildVersionHistory(
+	ctx context.Context,
+	branchToken []byte,
+	lastEventID int64,
+) (*historyspb.VersionHistory, error) {
+	versionHistory := versionhistory.NewVersionHistory(branchToken, nil)
+	expectedEventID := common.FirstEventID
+	request := &persistence.ReadHistoryBranchRequest{
+		ShardID:     r.shardContext.GetShardID(),
+		BranchToken: branchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  lastEventID + 1,
+		PageSize:    defaultPageSize,
+	}
+	for {
+		events, _, nextPageToken, err := persistence.ReadFullPageEvents(ctx, r.shardContext.GetExecutionManager(), request)
+		switch err.(type) {
+		case nil:
+		case *serviceerror.NotFound, *serviceerror.DataLoss:
+			return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf(
+				"unable to reconcile version history: local history events are missing (%v), run a full import instead",
+				err,
+			))
+		default:
+			return nil, err
+		}
+		for _, event := range events {
+			if event.GetEventId() != expectedEventID {
+				return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf(
+					"unable to reconcile version history: local history event %v is missing, run a full import instead",
+					expectedEventID,
+				))
+			}
+			if err := versionhistory.AddOrUpdateVersionHistoryItem(
+				versionHistory,
+				versionhistory.NewVersionHistoryItem(event.GetEventId(), event.GetVersion()),
+			); err != nil {
+				return nil, err
+			}
+			expectedEventID++
+		}
+		if len(nextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = nextPageToken
+	}
+	if expectedEventID != lastEventID+1 {
+		return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf(
+			"unable to reconcile version history: local history event %v is missing, run a full import instead",
+			expectedEventID,
+		))
+	}
+	return versionHistory, nil
+}
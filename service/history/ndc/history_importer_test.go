@@ -0,0 +1,798 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ndc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	historyspb "go.temporal.io/server/api/history/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/payload"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/service/history/consts"
+	"go.temporal.io/server/service/history/hsm"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tests"
+	"go.temporal.io/server/service/history/workflow"
+	wcache "go.temporal.io/server/service/history/workflow/cache"
+)
+
+type (
+	historyImporterSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockShard         *shard.ContextTest
+		mockWorkflowCache *wcache.MockCache
+		timeSource        *clock.EventTimeSource
+
+		workflowKey definition.WorkflowKey
+
+		importer *HistoryImporterImpl
+	}
+)
+
+func TestHistoryImporterSuite(t *testing.T) {
+	s := new(historyImporterSuite)
+	suite.Run(t, s)
+}
+
+func (s *historyImporterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	s.mockWorkflowCache = wcache.NewMockCache(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(time.Now())
+
+	s.mockShard = shard.NewTestContextWithTimeSource(
+		s.controller,
+		&persistencespb.ShardInfo{
+			ShardId: 10,
+			RangeId: 1,
+		},
+		tests.NewDynamicConfig(),
+		s.timeSource,
+	)
+	reg := hsm.NewRegistry()
+	err := workflow.RegisterStateMachine(reg)
+	s.NoError(err)
+	s.mockShard.SetStateMachineRegistry(reg)
+
+	s.mockShard.Resource.ClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockShard.Resource.ClusterMetadata.EXPECT().GetClusterID().Return(cluster.TestCurrentClusterInitialFailoverVersion).AnyTimes()
+	s.mockShard.Resource.ClusterMetadata.EXPECT().ClusterNameForFailoverVersion(gomock.Any(), gomock.Any()).Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockShard.Resource.NamespaceCache.EXPECT().GetNamespaceByID(tests.NamespaceID).Return(tests.GlobalNamespaceEntry, nil).AnyTimes()
+
+	s.workflowKey = definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	s.importer = NewHistoryImporter(s.mockShard, s.mockWorkflowCache, s.mockShard.Resource.VisibilityManager, s.mockShard.GetLogger())
+}
+
+func (s *historyImporterSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.StopForTest()
+}
+
+func (s *historyImporterSuite) TestImportLease_ConcurrentStart() {
+	const imports = 10
+
+	var acquired atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < imports; i++ {
+		importID := string(rune('a' + i))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.importer.acquireImportLease(s.workflowKey, importID)
+			if err == nil {
+				acquired.Add(1)
+				return
+			}
+			s.ErrorIs(err, consts.ErrImportInProgress)
+		}()
+	}
+	wg.Wait()
+	s.Equal(int32(1), acquired.Load())
+}
+
+func (s *historyImporterSuite) TestImportLease_RenewReleaseExpire() {
+	otherWorkflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, "other-run-id")
+
+	s.NoError(s.importer.acquireImportLease(s.workflowKey, "import-1"))
+	s.ErrorIs(s.importer.acquireImportLease(s.workflowKey, "import-2"), consts.ErrImportInProgress)
+	s.NoError(s.importer.acquireImportLease(otherWorkflowKey, "import-2"))
+
+	// the owner renews its lease
+	ttl := s.mockShard.GetConfig().ImportLeaseTTL()
+	s.timeSource.Update(s.timeSource.Now().Add(ttl / 2))
+	s.NoError(s.importer.acquireImportLease(s.workflowKey, "import-1"))
+	s.timeSource.Update(s.timeSource.Now().Add(ttl / 2))
+	s.ErrorIs(s.importer.acquireImportLease(s.workflowKey, "import-2"), consts.ErrImportInProgress)
+
+	// releasing by another import is a no-op
+	s.importer.releaseImportLease(s.workflowKey, "import-2")
+	s.ErrorIs(s.importer.acquireImportLease(s.workflowKey, "import-2"), consts.ErrImportInProgress)
+
+	// an abandoned lease expires
+	s.timeSource.Update(s.timeSource.Now().Add(ttl))
+	s.NoError(s.importer.acquireImportLease(s.workflowKey, "import-2"))
+
+	s.importer.releaseImportLease(s.workflowKey, "import-2")
+	s.NoError(s.importer.acquireImportLease(s.workflowKey, "import-3"))
+}
+
+func (s *historyImporterSuite) TestImportWorkflow_ImportInProgress() {
+	s.NoError(s.importer.acquireImportLease(s.workflowKey, "import-1"))
+
+	mockContext := workflow.NewMockContext(s.controller)
+	s.mockWorkflowCache.EXPECT().GetOrCreateWorkflowExecution(
+		gomock.Any(),
+		s.mockShard,
+		namespace.ID(s.workflowKey.NamespaceID),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(mockContext, wcache.NoopReleaseFn, nil)
+	mockContext.EXPECT().LoadMutableState(gomock.Any(), s.mockShard).Return(nil, serviceerror.NewNotFound(""))
+	mockContext.EXPECT().Clear()
+
+	_, _, err := s.importer.ImportWorkflow(
+		context.Background(),
+		s.workflowKey,
+		nil,
+		[][]*historypb.HistoryEvent{{{EventId: 1}}},
+		nil,
+	)
+	s.ErrorIs(err, consts.ErrImportInProgress)
+}
+
+func (s *historyImporterSuite) TestReconcileVersionHistory_Repair() {
+	localVersionHistory := versionhistory.NewVersionHistory([]byte("branch-token"), []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+	})
+	sourceVersionHistory := versionhistory.NewVersionHistory([]byte("source-branch-token"), []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+		versionhistory.NewVersionHistoryItem(5, 2),
+	})
+	mockContext := s.mockLoadWorkflow(localVersionHistory, 6)
+	s.mockReadHistoryBranch(localVersionHistory.BranchToken, 6, []int64{1, 1, 1, 2, 2})
+	mockContext.EXPECT().SetWorkflowExecution(gomock.Any(), s.mockShard).Return(nil)
+
+	err := s.importer.ReconcileVersionHistory(context.Background(), s.workflowKey, sourceVersionHistory)
+	s.NoError(err)
+	s.True(versionhistory.IsEqualVersionHistoryItems(sourceVersionHistory.Items, localVersionHistory.Items))
+	s.Equal([]byte("branch-token"), localVersionHistory.BranchToken)
+}
+
+func (s *historyImporterSuite) TestReconcileVersionHistory_AlreadyReconciled() {
+	localVersionHistory := versionhistory.NewVersionHistory([]byte("branch-token"), []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+		versionhistory.NewVersionHistoryItem(5, 2),
+	})
+	sourceVersionHistory := versionhistory.NewVersionHistory(nil, []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+		versionhistory.NewVersionHistoryItem(5, 2),
+	})
+	s.mockLoadWorkflow(localVersionHistory, 6)
+	s.mockReadHistoryBranch(localVersionHistory.BranchToken, 6, []int64{1, 1, 1, 2, 2})
+
+	err := s.importer.ReconcileVersionHistory(context.Background(), s.workflowKey, sourceVersionHistory)
+	s.NoError(err)
+}
+
+func (s *historyImporterSuite) TestReconcileVersionHistory_MissingEvents() {
+	localVersionHistory := versionhistory.NewVersionHistory([]byte("branch-token"), []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+	})
+	sourceVersionHistory := versionhistory.NewVersionHistory(nil, []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+		versionhistory.NewVersionHistoryItem(7, 2),
+	})
+	s.mockLoadWorkflow(localVersionHistory, 6)
+
+	err := s.importer.ReconcileVersionHistory(context.Background(), s.workflowKey, sourceVersionHistory)
+	var failedPrecondition *serviceerror.FailedPrecondition
+	s.ErrorAs(err, &failedPrecondition)
+}
+
+func (s *historyImporterSuite) TestReconcileVersionHistory_EventGap() {
+	localVersionHistory := versionhistory.NewVersionHistory([]byte("branch-token"), []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+	})
+	sourceVersionHistory := versionhistory.NewVersionHistory(nil, []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+		versionhistory.NewVersionHistoryItem(5, 2),
+	})
+	s.mockLoadWorkflow(localVersionHistory, 6)
+	s.mockReadHistoryBranch(localVersionHistory.BranchToken, 6, []int64{1, 1, 1})
+
+	err := s.importer.ReconcileVersionHistory(context.Background(), s.workflowKey, sourceVersionHistory)
+	var failedPrecondition *serviceerror.FailedPrecondition
+	s.ErrorAs(err, &failedPrecondition)
+}
+
+func (s *historyImporterSuite) TestReconcileVersionHistory_Diverged() {
+	localVersionHistory := versionhistory.NewVersionHistory([]byte("branch-token"), []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+	})
+	sourceVersionHistory := versionhistory.NewVersionHistory(nil, []*historyspb.VersionHistoryItem{
+		versionhistory.NewVersionHistoryItem(3, 1),
+		versionhistory.NewVersionHistoryItem(5, 2),
+	})
+	s.mockLoadWorkflow(localVersionHistory, 6)
+	s.mockReadHistoryBranch(localVersionHistory.BranchToken, 6, []int64{1, 1, 1, 3, 3})
+
+	err := s.importer.ReconcileVersionHistory(context.Background(), s.workflowKey, sourceVersionHistory)
+	var failedPrecondition *serviceerror.FailedPrecondition
+	s.ErrorAs(err, &failedPrecondition)
+	s.Len(localVersionHistory.Items, 1)
+}
+
+func (s *historyImporterSuite) TestReconcileCurrentExecution_StalePointer() {
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+	targetWorkflow := NewMockWorkflow(s.controller)
+	currentWorkflow := NewMockWorkflow(s.controller)
+
+	namespaceID := namespace.ID(s.workflowKey.NamespaceID)
+	gomock.InOrder(
+		mockTransactionMgr.EXPECT().GetCurrentWorkflowRunID(gomock.Any(), namespaceID, s.workflowKey.WorkflowID).Return("stale-run-id", nil),
+		mockTransactionMgr.EXPECT().LoadWorkflow(gomock.Any(), namespaceID, s.workflowKey.WorkflowID, s.workflowKey.RunID).Return(targetWorkflow, nil),
+		mockTransactionMgr.EXPECT().LoadWorkflow(gomock.Any(), namespaceID, s.workflowKey.WorkflowID, "stale-run-id").Return(currentWorkflow, nil),
+		targetWorkflow.EXPECT().HappensAfter(currentWorkflow).Return(true, nil),
+		currentWorkflow.EXPECT().GetReleaseFn().Return(wcache.NoopReleaseFn),
+		// the transaction manager releases the target workflow
+		mockTransactionMgr.EXPECT().UpdateWorkflow(gomock.Any(), true, targetWorkflow, nil).Return(nil),
+	)
+
+	err := s.importer.ReconcileCurrentExecution(context.Background(), s.workflowKey)
+	s.NoError(err)
+}
+
+func (s *historyImporterSuite) TestReconcileCurrentExecution_AlreadyCurrent() {
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+	mockTransactionMgr.EXPECT().GetCurrentWorkflowRunID(
+		gomock.Any(),
+		namespace.ID(s.workflowKey.NamespaceID),
+		s.workflowKey.WorkflowID,
+	).Return(s.workflowKey.RunID, nil)
+
+	err := s.importer.ReconcileCurrentExecution(context.Background(), s.workflowKey)
+	s.NoError(err)
+}
+
+func (s *historyImporterSuite) TestReconcileCurrentExecution_CurrentHappensAfter() {
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+	targetWorkflow := NewMockWorkflow(s.controller)
+	currentWorkflow := NewMockWorkflow(s.controller)
+
+	// nothing is written if the local current run is newer, and both workflows are released
+	namespaceID := namespace.ID(s.workflowKey.NamespaceID)
+	mockTransactionMgr.EXPECT().GetCurrentWorkflowRunID(gomock.Any(), namespaceID, s.workflowKey.WorkflowID).Return("newer-run-id", nil)
+	mockTransactionMgr.EXPECT().LoadWorkflow(gomock.Any(), namespaceID, s.workflowKey.WorkflowID, s.workflowKey.RunID).Return(targetWorkflow, nil)
+	mockTransactionMgr.EXPECT().LoadWorkflow(gomock.Any(), namespaceID, s.workflowKey.WorkflowID, "newer-run-id").Return(currentWorkflow, nil)
+	mockTransactionMgr.EXPECT().UpdateWorkflow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	targetWorkflow.EXPECT().HappensAfter(currentWorkflow).Return(false, nil)
+	var released []string
+	releaseFn := func(name string) wcache.ReleaseCacheFunc {
+		return func(error) { released = append(released, name) }
+	}
+	targetWorkflow.EXPECT().GetReleaseFn().Return(releaseFn("target")).Times(1)
+	currentWorkflow.EXPECT().GetReleaseFn().Return(releaseFn("current")).Times(1)
+
+	err := s.importer.ReconcileCurrentExecution(context.Background(), s.workflowKey)
+	var failedPrecondition *serviceerror.FailedPrecondition
+	s.ErrorAs(err, &failedPrecondition)
+	s.ElementsMatch([]string{"target", "current"}, released)
+}
+
+func (s *historyImporterSuite) TestRemapSearchAttributes() {
+	s.mockShard.GetConfig().ImportSearchAttributeNameMapping = dynamicconfig.GetTypedPropertyFnFilteredByNamespace(map[string]string{
+		"Keyword01": "CustomKeyword",
+		"Int01":     "CustomInt",
+		"Text01":    "DeletedText",
+	})
+	mapper := searchattribute.NewMockMapper(s.controller)
+	mapper.EXPECT().GetFieldName("CustomKeyword", tests.Namespace.String()).Return("Keyword05", nil).AnyTimes()
+	mapper.EXPECT().GetFieldName("CustomInt", tests.Namespace.String()).Return("Int02", nil).AnyTimes()
+	mapper.EXPECT().GetFieldName("DeletedText", tests.Namespace.String()).Return("", serviceerror.NewInvalidArgument("unknown alias")).AnyTimes()
+	s.mockShard.Resource.SearchAttributesMapperProvider.EXPECT().GetMapper(tests.Namespace).Return(mapper, nil)
+
+	keywordPayload := payload.EncodeString("keyword")
+	intPayload := payload.EncodeString("1")
+	changeVersionPayload := payload.EncodeString("change-1")
+	startedEvent := &historypb.HistoryEvent{
+		EventId:   common.FirstEventID,
+		EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED,
+		Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+			WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+				SearchAttributes: &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{
+					"Keyword01":                           keywordPayload,
+					"Double01":                            payload.EncodeString("1.5"),
+					searchattribute.TemporalChangeVersion: changeVersionPayload,
+				}},
+			},
+		},
+	}
+	upsertEvent := &historypb.HistoryEvent{
+		EventId:   common.FirstEventID + 1,
+		EventType: enumspb.EVENT_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES,
+		Attributes: &historypb.HistoryEvent_UpsertWorkflowSearchAttributesEventAttributes{
+			UpsertWorkflowSearchAttributesEventAttributes: &historypb.UpsertWorkflowSearchAttributesEventAttributes{
+				SearchAttributes: &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{
+					"Int01":  intPayload,
+					"Text01": payload.EncodeString("text"),
+				}},
+			},
+		},
+	}
+
+	err := s.importer.remapSearchAttributes(s.workflowKey, [][]*historypb.HistoryEvent{{startedEvent}, {upsertEvent}})
+	s.NoError(err)
+
+	// mapped attributes are renamed, system attributes are kept as is, and attributes without a
+	// mapping or without a matching alias are dropped
+	s.Equal(map[string]*commonpb.Payload{
+		"Keyword05":                           keywordPayload,
+		searchattribute.TemporalChangeVersion: changeVersionPayload,
+	}, startedEvent.GetWorkflowExecutionStartedEventAttributes().GetSearchAttributes().GetIndexedFields())
+	s.Equal(map[string]*commonpb.Payload{
+		"Int02": intPayload,
+	}, upsertEvent.GetUpsertWorkflowSearchAttributesEventAttributes().GetSearchAttributes().GetIndexedFields())
+}
+
+func (s *historyImporterSuite) TestRemapSearchAttributes_NoMapping() {
+	indexedFields := map[string]*commonpb.Payload{
+		"Keyword01": payload.EncodeString("keyword"),
+	}
+	event := &historypb.HistoryEvent{
+		EventId:   common.FirstEventID,
+		EventType: enumspb.EVENT_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES,
+		Attributes: &historypb.HistoryEvent_UpsertWorkflowSearchAttributesEventAttributes{
+			UpsertWorkflowSearchAttributesEventAttributes: &historypb.UpsertWorkflowSearchAttributesEventAttributes{
+				SearchAttributes: &commonpb.SearchAttributes{IndexedFields: indexedFields},
+			},
+		},
+	}
+
+	err := s.importer.remapSearchAttributes(s.workflowKey, [][]*historypb.HistoryEvent{{event}})
+	s.NoError(err)
+	s.Equal(indexedFields, event.GetUpsertWorkflowSearchAttributesEventAttributes().GetSearchAttributes().GetIndexedFields())
+}
+
+func (s *historyImporterSuite) TestRecordImportProvenance() {
+	s.mockShard.GetConfig().ImportRecordProvenance = dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true)
+	existingMemo := payload.EncodeString("memo")
+	mutableState, executionInfo := s.mockImportedMutableState(map[string]*commonpb.Payload{"key": existingMemo})
+	ctx := headers.SetVersionsForTests(context.Background(), "1.0.0", "temporal-cli", "", "")
+
+	err := s.importer.recordImportProvenance(ctx, mutableState)
+	s.NoError(err)
+	s.Equal(existingMemo, executionInfo.Memo["key"])
+	provenance, ok, err := GetImportProvenance(executionInfo.Memo)
+	s.NoError(err)
+	s.True(ok)
+	s.Equal(ImportProvenance{
+		SourceCluster: cluster.TestCurrentClusterName,
+		ImportTime:    s.timeSource.Now().UTC(),
+		Client:        "temporal-cli",
+	}, provenance)
+}
+
+func (s *historyImporterSuite) TestRecordImportProvenance_Disabled() {
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+
+	err := s.importer.recordImportProvenance(context.Background(), mutableState)
+	s.NoError(err)
+	_, ok, err := GetImportProvenance(executionInfo.Memo)
+	s.NoError(err)
+	s.False(ok)
+}
+
+func (s *historyImporterSuite) TestLimitBranches() {
+	s.mockShard.GetConfig().ImportMaxBranches = dynamicconfig.GetIntPropertyFnFilteredByNamespace(2)
+	branch := func(token string, lastEventID, version int64) *historyspb.VersionHistory {
+		return versionhistory.NewVersionHistory([]byte(token), []*historyspb.VersionHistoryItem{
+			versionhistory.NewVersionHistoryItem(lastEventID, version),
+		})
+	}
+	// the current branch is the least recent one, e.g. after a reset to an old version
+	versionHistories := &historyspb.VersionHistories{
+		CurrentVersionHistoryIndex: 2,
+		Histories: []*historyspb.VersionHistory{
+			branch("branch-0", 10, 2),
+			branch("branch-1", 20, 2),
+			branch("current", 5, 1),
+			branch("branch-3", 30, 2),
+			branch("branch-4", 5, 2),
+		},
+	}
+	mutableState, _ := s.mockImportedMutableState(nil)
+	mutableState.GetExecutionInfo().VersionHistories = versionHistories
+
+	err := s.importer.limitBranches(mutableState)
+	s.NoError(err)
+	var tokens []string
+	for _, h := range versionHistories.Histories {
+		tokens = append(tokens, string(h.BranchToken))
+	}
+	s.Equal([]string{"branch-1", "current", "branch-3"}, tokens)
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(versionHistories)
+	s.NoError(err)
+	s.Equal([]byte("current"), currentVersionHistory.BranchToken)
+
+	// under the limit, nothing changes
+	err = s.importer.limitBranches(mutableState)
+	s.NoError(err)
+	s.Len(versionHistories.Histories, 3)
+}
+
+func (s *historyImporterSuite) TestLimitBranches_Disabled() {
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+	for i := 0; i < 3; i++ {
+		_, _, err := versionhistory.AddVersionHistory(executionInfo.VersionHistories, versionhistory.NewVersionHistory(nil, []*historyspb.VersionHistoryItem{
+			versionhistory.NewVersionHistoryItem(int64(i+1), cluster.TestCurrentClusterInitialFailoverVersion),
+		}))
+		s.NoError(err)
+	}
+
+	err := s.importer.limitBranches(mutableState)
+	s.NoError(err)
+	s.Len(executionInfo.VersionHistories.Histories, 4)
+}
+
+func (s *historyImporterSuite) TestRebaseTimers() {
+	s.mockShard.GetConfig().ImportRebaseTimers = dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true)
+	now := s.timeSource.Now()
+	lastEventTime := now.Add(-30 * 24 * time.Hour)
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+	executionInfo.WorkflowRunExpirationTime = timestamppb.New(lastEventTime.Add(2 * time.Hour))
+	// both the timer and the activity timed out long ago in the source cluster's time
+	timerInfo := &persistencespb.TimerInfo{TimerId: "timer", ExpiryTime: timestamppb.New(lastEventTime.Add(time.Hour))}
+	activityInfo := &persistencespb.ActivityInfo{
+		ScheduledEventId:       5,
+		ScheduledTime:          timestamppb.New(lastEventTime.Add(-time.Minute)),
+		ScheduleToStartTimeout: durationpb.New(10 * time.Minute),
+	}
+	mutableState.EXPECT().GetPendingTimerInfos().Return(map[string]*persistencespb.TimerInfo{"timer": timerInfo})
+	mutableState.EXPECT().UpdateUserTimer(timerInfo).Return(nil)
+	mutableState.EXPECT().GetPendingActivityInfos().Return(map[int64]*persistencespb.ActivityInfo{5: activityInfo})
+	mutableState.EXPECT().UpdateActivity(activityInfo).Return(nil)
+
+	err := s.importer.rebaseTimers(mutableState, lastEventTime)
+	s.NoError(err)
+	// the time left until each deadline is the same as after the last imported event
+	s.WithinDuration(now.Add(time.Hour), timerInfo.ExpiryTime.AsTime(), 0)
+	s.WithinDuration(now.Add(-time.Minute), activityInfo.ScheduledTime.AsTime(), 0)
+	s.Nil(activityInfo.StartedTime)
+	s.WithinDuration(now.Add(2*time.Hour), executionInfo.WorkflowRunExpirationTime.AsTime(), 0)
+	s.Nil(executionInfo.WorkflowExecutionExpirationTime)
+}
+
+func (s *historyImporterSuite) TestRebaseTimers_Disabled() {
+	lastEventTime := s.timeSource.Now().Add(-30 * 24 * time.Hour)
+	expiryTime := timestamppb.New(lastEventTime.Add(time.Hour))
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+	executionInfo.WorkflowRunExpirationTime = expiryTime
+
+	// original deadlines are kept, so the overdue ones fire right after the import
+	err := s.importer.rebaseTimers(mutableState, lastEventTime)
+	s.NoError(err)
+	s.Equal(expiryTime, executionInfo.WorkflowRunExpirationTime)
+}
+
+func (s *historyImporterSuite) TestRebaseTimers_UnknownLastEventTime() {
+	s.mockShard.GetConfig().ImportRebaseTimers = dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true)
+	mutableState, _ := s.mockImportedMutableState(nil)
+
+	err := s.importer.rebaseTimers(mutableState, time.Time{})
+	s.NoError(err)
+}
+
+func (s *historyImporterSuite) TestResolveChildWorkflowLinks_Import() {
+	s.mockShard.GetConfig().ImportChildWorkflowLinks = dynamicconfig.GetStringPropertyFnFilteredByNamespace(ImportChildWorkflowLinksImport)
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+	childImporter := NewMockChildWorkflowImporter(s.controller)
+	s.importer.childImporter = childImporter
+	existing := &commonpb.WorkflowExecution{WorkflowId: "existing-child", RunId: "existing-run"}
+	imported := &commonpb.WorkflowExecution{WorkflowId: "imported-child", RunId: "imported-run"}
+	missing := &commonpb.WorkflowExecution{WorkflowId: "missing-child", RunId: "missing-run"}
+	mutableState.EXPECT().GetPendingChildExecutionInfos().Return(map[int64]*persistencespb.ChildExecutionInfo{
+		5:  {InitiatedEventId: 5, StartedWorkflowId: existing.WorkflowId, StartedRunId: existing.RunId},
+		6:  {InitiatedEventId: 6, StartedWorkflowId: imported.WorkflowId, StartedRunId: imported.RunId},
+		7:  {InitiatedEventId: 7, StartedWorkflowId: missing.WorkflowId, StartedRunId: missing.RunId},
+		8:  {InitiatedEventId: 8, StartedWorkflowId: "not-started-child"},
+		10: {InitiatedEventId: 10, NamespaceId: "other-namespace-id", StartedWorkflowId: missing.WorkflowId, StartedRunId: missing.RunId},
+	})
+	childImporter.EXPECT().Exists(gomock.Any(), tests.NamespaceID, existing).Return(true, nil)
+	childImporter.EXPECT().Exists(gomock.Any(), tests.NamespaceID, imported).Return(false, nil)
+	childImporter.EXPECT().Exists(gomock.Any(), tests.NamespaceID, missing).Return(false, nil)
+	childImporter.EXPECT().Exists(gomock.Any(), namespace.ID("other-namespace-id"), missing).Return(true, nil)
+	// the imported child may import two more levels of its own children
+	childImporter.EXPECT().Import(gomock.Any(), cluster.TestCurrentClusterName, tests.NamespaceID, imported, 2).Return(true, nil)
+	// gone from the source cluster as well
+	childImporter.EXPECT().Import(gomock.Any(), cluster.TestCurrentClusterName, tests.NamespaceID, missing, 2).Return(false, nil)
+
+	err := s.importer.resolveChildWorkflowLinks(context.Background(), mutableState)
+	s.NoError(err)
+	unresolved, err := GetImportUnresolvedChildWorkflows(executionInfo.Memo)
+	s.NoError(err)
+	s.Equal([]UnresolvedChildWorkflow{{
+		NamespaceID:      tests.NamespaceID.String(),
+		WorkflowID:       missing.WorkflowId,
+		RunID:            missing.RunId,
+		InitiatedEventID: 7,
+	}}, unresolved)
+}
+
+func (s *historyImporterSuite) TestResolveChildWorkflowLinks_ImportDepthReached() {
+	s.mockShard.GetConfig().ImportChildWorkflowLinks = dynamicconfig.GetStringPropertyFnFilteredByNamespace(ImportChildWorkflowLinksImport)
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+	childImporter := NewMockChildWorkflowImporter(s.controller)
+	s.importer.childImporter = childImporter
+	child := &commonpb.WorkflowExecution{WorkflowId: "child", RunId: "child-run"}
+	mutableState.EXPECT().GetPendingChildExecutionInfos().Return(map[int64]*persistencespb.ChildExecutionInfo{
+		5: {InitiatedEventId: 5, StartedWorkflowId: child.WorkflowId, StartedRunId: child.RunId},
+	})
+	childImporter.EXPECT().Exists(gomock.Any(), tests.NamespaceID, child).Return(false, nil)
+	// this workflow is itself a child imported at the depth limit
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(importChildWorkflowDepthHeaderName, "0"))
+
+	err := s.importer.resolveChildWorkflowLinks(ctx, mutableState)
+	s.NoError(err)
+	unresolved, err := GetImportUnresolvedChildWorkflows(executionInfo.Memo)
+	s.NoError(err)
+	s.Len(unresolved, 1)
+	s.Equal(child.RunId, unresolved[0].RunID)
+}
+
+func (s *historyImporterSuite) TestResolveChildWorkflowLinks_Unresolved() {
+	s.mockShard.GetConfig().ImportChildWorkflowLinks = dynamicconfig.GetStringPropertyFnFilteredByNamespace(ImportChildWorkflowLinksUnresolved)
+	existingMemo := payload.EncodeString("memo")
+	mutableState, executionInfo := s.mockImportedMutableState(map[string]*commonpb.Payload{"key": existingMemo})
+	childImporter := NewMockChildWorkflowImporter(s.controller)
+	s.importer.childImporter = childImporter
+	existing := &commonpb.WorkflowExecution{WorkflowId: "existing-child", RunId: "existing-run"}
+	missing := &commonpb.WorkflowExecution{WorkflowId: "missing-child", RunId: "missing-run"}
+	mutableState.EXPECT().GetPendingChildExecutionInfos().Return(map[int64]*persistencespb.ChildExecutionInfo{
+		5: {InitiatedEventId: 5, StartedWorkflowId: existing.WorkflowId, StartedRunId: existing.RunId},
+		7: {InitiatedEventId: 7, StartedWorkflowId: missing.WorkflowId, StartedRunId: missing.RunId},
+	})
+	childImporter.EXPECT().Exists(gomock.Any(), tests.NamespaceID, existing).Return(true, nil)
+	childImporter.EXPECT().Exists(gomock.Any(), tests.NamespaceID, missing).Return(false, nil)
+
+	err := s.importer.resolveChildWorkflowLinks(context.Background(), mutableState)
+	s.NoError(err)
+	s.Equal(existingMemo, executionInfo.Memo["key"])
+	unresolved, err := GetImportUnresolvedChildWorkflows(executionInfo.Memo)
+	s.NoError(err)
+	s.Equal([]UnresolvedChildWorkflow{{
+		NamespaceID:      tests.NamespaceID.String(),
+		WorkflowID:       missing.WorkflowId,
+		RunID:            missing.RunId,
+		InitiatedEventID: 7,
+	}}, unresolved)
+}
+
+func (s *historyImporterSuite) TestResolveChildWorkflowLinks_Disabled() {
+	mutableState, executionInfo := s.mockImportedMutableState(nil)
+	s.importer.childImporter = NewMockChildWorkflowImporter(s.controller)
+
+	err := s.importer.resolveChildWorkflowLinks(context.Background(), mutableState)
+	s.NoError(err)
+	unresolved, err := GetImportUnresolvedChildWorkflows(executionInfo.Memo)
+	s.NoError(err)
+	s.Empty(unresolved)
+}
+
+func (s *historyImporterSuite) TestCommit_RecordVisibility() {
+	s.mockShard.GetConfig().ImportRecordProvenance = dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true)
+	searchAttributes := map[string]*commonpb.Payload{"CustomKeywordField": payload.EncodeString("value")}
+	mockWorkflow, mutableState, executionInfo := s.mockCommitWorkflow(map[string]*commonpb.Payload{"key": payload.EncodeString("memo")}, searchAttributes)
+	mutableState.EXPECT().IsWorkflowExecutionRunning().Return(true).AnyTimes()
+	mutableState.EXPECT().GetExecutionState().Return(&persistencespb.WorkflowExecutionState{
+		RunId:  s.workflowKey.RunID,
+		Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+	}).AnyTimes()
+
+	// the visibility record is written before the execution, with the memo and search attributes of
+	// the imported mutable state
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+	gomock.InOrder(
+		s.mockShard.Resource.VisibilityManager.EXPECT().UpsertWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *manager.UpsertWorkflowExecutionRequest) error {
+				s.Equal(tests.NamespaceID, request.NamespaceID)
+				s.Equal(s.workflowKey.WorkflowID, request.Execution.GetWorkflowId())
+				s.Equal(s.workflowKey.RunID, request.Execution.GetRunId())
+				s.Equal(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, request.Status)
+				s.Equal(executionInfo.Memo, request.Memo.GetFields())
+				s.Contains(request.Memo.GetFields(), ImportProvenanceMemoKey)
+				s.Equal(searchAttributes, request.SearchAttributes.GetIndexedFields())
+				s.Positive(request.TaskID)
+				return nil
+			},
+		),
+		mockTransactionMgr.EXPECT().CreateWorkflow(gomock.Any(), mockWorkflow).Return(nil),
+	)
+
+	err := s.importer.commit(context.Background(), mockWorkflow, MutableStateInitializationSpec{})
+	s.NoError(err)
+}
+
+func (s *historyImporterSuite) TestCommit_RecordVisibility_Closed() {
+	closeTime := s.timeSource.Now().Add(-time.Hour)
+	mockWorkflow, mutableState, _ := s.mockCommitWorkflow(nil, nil)
+	mutableState.EXPECT().IsWorkflowExecutionRunning().Return(false).AnyTimes()
+	mutableState.EXPECT().GetExecutionState().Return(&persistencespb.WorkflowExecutionState{
+		RunId:  s.workflowKey.RunID,
+		Status: enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+	}).AnyTimes()
+	mutableState.EXPECT().GetWorkflowCloseTime(gomock.Any()).Return(closeTime, nil)
+	mutableState.EXPECT().GetWorkflowExecutionDuration(gomock.Any()).Return(time.Minute, nil)
+	mutableState.EXPECT().GetNextEventID().Return(int64(6)).AnyTimes()
+
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+	gomock.InOrder(
+		s.mockShard.Resource.VisibilityManager.EXPECT().RecordWorkflowExecutionClosed(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *manager.RecordWorkflowExecutionClosedRequest) error {
+				s.Equal(enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, request.Status)
+				s.Equal(closeTime, request.CloseTime)
+				s.Equal(time.Minute, request.ExecutionDuration)
+				s.Equal(int64(5), request.HistoryLength)
+				return nil
+			},
+		),
+		mockTransactionMgr.EXPECT().CreateWorkflow(gomock.Any(), mockWorkflow).Return(nil),
+	)
+
+	err := s.importer.commit(context.Background(), mockWorkflow, MutableStateInitializationSpec{})
+	s.NoError(err)
+}
+
+func (s *historyImporterSuite) TestCommit_RecordVisibilityFailed() {
+	mockWorkflow, mutableState, _ := s.mockCommitWorkflow(nil, nil)
+	mutableState.EXPECT().IsWorkflowExecutionRunning().Return(true).AnyTimes()
+	mutableState.EXPECT().GetExecutionState().Return(&persistencespb.WorkflowExecutionState{
+		RunId:  s.workflowKey.RunID,
+		Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+	}).AnyTimes()
+
+	// the execution isn't written without a visibility record
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+	visibilityErr := serviceerror.NewUnavailable("visibility unavailable")
+	s.mockShard.Resource.VisibilityManager.EXPECT().UpsertWorkflowExecution(gomock.Any(), gomock.Any()).Return(visibilityErr)
+
+	err := s.importer.commit(context.Background(), mockWorkflow, MutableStateInitializationSpec{})
+	s.Equal(visibilityErr, err)
+}
+
+func (s *historyImporterSuite) mockImportedMutableState(
+	memo map[string]*commonpb.Payload,
+) (*workflow.MockMutableState, *persistencespb.WorkflowExecutionInfo) {
+	executionInfo := &persistencespb.WorkflowExecutionInfo{
+		Memo: memo,
+		VersionHistories: versionhistory.NewVersionHistories(
+			versionhistory.NewVersionHistory([]byte("branch-token"), []*historyspb.VersionHistoryItem{
+				versionhistory.NewVersionHistoryItem(5, cluster.TestCurrentClusterInitialFailoverVersion),
+			}),
+		),
+	}
+	mutableState := workflow.NewMockMutableState(s.controller)
+	mutableState.EXPECT().GetNamespaceEntry().Return(tests.GlobalNamespaceEntry).AnyTimes()
+	mutableState.EXPECT().GetExecutionInfo().Return(executionInfo).AnyTimes()
+	return mutableState, executionInfo
+}
+func (s *historyImporterSuite) mockCommitWorkflow(
+	memo map[string]*commonpb.Payload,
+	searchAttributes map[string]*commonpb.Payload,
+) (*MockWorkflow, *workflow.MockMutableState, *persistencespb.WorkflowExecutionInfo) {
+	mutableState, executionInfo := s.mockImportedMutableState(memo)
+	executionInfo.NamespaceId = s.workflowKey.NamespaceID
+	executionInfo.WorkflowId = s.workflowKey.WorkflowID
+	executionInfo.SearchAttributes = searchAttributes
+	mutableState.EXPECT().GetUpdateCondition().Return(int64(6), int64(0)).AnyTimes()
+	mutableState.EXPECT().SetUpdateCondition(int64(6), int64(0)).AnyTimes()
+
+	mockTaskRefresher := workflow.NewMockTaskRefresher(s.controller)
+	s.importer.taskRefresher = mockTaskRefresher
+	mockTaskRefresher.EXPECT().RefreshTasks(gomock.Any(), mutableState).Return(nil)
+
+	mockWorkflow := NewMockWorkflow(s.controller)
+	mockWorkflow.EXPECT().GetMutableState().Return(mutableState).AnyTimes()
+	return mockWorkflow, mutableState, executionInfo
+}
+
+func (s *historyImporterSuite) mockLoadWorkflow(
+	versionHistory *historyspb.VersionHistory,
+	nextEventID int64,
+) *workflow.MockContext {
+	mockTransactionMgr := NewMockTransactionManager(s.controller)
+	s.importer.transactionMgr = mockTransactionMgr
+
+	mockWorkflow := NewMockWorkflow(s.controller)
+	mockContext := workflow.NewMockContext(s.controller)
+	mockMutableState := workflow.NewMockMutableState(s.controller)
+	mockTransactionMgr.EXPECT().LoadWorkflow(
+		gomock.Any(),
+		namespace.ID(s.workflowKey.NamespaceID),
+		s.workflowKey.WorkflowID,
+		s.workflowKey.RunID,
+	).Return(mockWorkflow, nil)
+	mockWorkflow.EXPECT().GetReleaseFn().Return(wcache.NoopReleaseFn)
+	mockWorkflow.EXPECT().GetContext().Return(mockContext).AnyTimes()
+	mockWorkflow.EXPECT().GetMutableState().Return(mockMutableState).AnyTimes()
+	mockMutableState.EXPECT().GetExecutionInfo().Return(&persistencespb.WorkflowExecutionInfo{
+		VersionHistories: versionhistory.NewVersionHistories(versionHistory),
+	}).AnyTimes()
+	mockMutableState.EXPECT().GetNextEventID().Return(nextEventID).AnyTimes()
+	return mockContext
+}
+
+func (s *historyImporterSuite) mockReadHistoryBranch(
+	branchToken []byte,
+	nextEventID int64,
+	eventVersions []int64,
+) {
+	events := make([]*historypb.HistoryEvent, len(eventVersions))
+	for i, version := range eventVersions {
+		events[i] = &historypb.HistoryEvent{EventId: int64(i + 1), Version: version}
+	}
+	s.mockShard.Resource.ExecutionMgr.EXPECT().ReadHistoryBranch(gomock.Any(), &persistence.ReadHistoryBranchRequest{
+		ShardID:     s.mockShard.GetShardID(),
+		BranchToken: branchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  nextEventID,
+		PageSize:    defaultPageSize,
+	}).Return(&persistence.ReadHistoryBranchResponse{HistoryEvents: events}, nil)
+}
@@ -43,6 +43,7 @@ import (
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/utf8validator"
+	"go.temporal.io/server/service/history/events"
 	"go.temporal.io/server/service/history/shard"
 	"go.temporal.io/server/service/history/workflow"
 	wcache "go.temporal.io/server/service/history/workflow/cache"
@@ -54,6 +55,12 @@ type (
 		DBRecordVersion int64
 		DBHistorySize   int64
 		MutableStateRow []byte
+		// ImportID identifies the import the token belongs to, see HistoryImporterImpl. It's
+		// empty for tokens issued before import leases were added.
+		ImportID string
+		// LastEventTime is the time of the last event imported so far, see
+		// HistoryImporterImpl.rebaseTimers. It's zero for tokens issued before it was added.
+		LastEventTime time.Time
 	}
 
 	MutableStateInitializationSpec struct {
@@ -61,6 +68,8 @@ type (
 		IsBrandNew      bool
 		DBRecordVersion int64
 		DBHistorySize   int64
+		ImportID        string
+		LastEventTime   time.Time
 	}
 
 	MutableStateInitializer interface {
@@ -137,36 +146,36 @@ func (r *MutableStateInitializerImpl) InitializeFromDB(
 		_, dbRecordVersion := mutableState.GetUpdateCondition()
 		dbHistorySize := mutableState.GetHistorySize()
 		return NewWorkflow(
-				r.shardContext.GetClusterMetadata(),
-				wfContext,
-				mutableState,
-				releaseFn,
-			), MutableStateInitializationSpec{
-				ExistsInDB:      true,
-				IsBrandNew:      false,
-				DBRecordVersion: dbRecordVersion,
-				DBHistorySize:   dbHistorySize,
-			}, nil
+			r.shardContext.GetClusterMetadata(),
+			wfContext,
+			mutableState,
+			releaseFn,
+		), MutableStateInitializationSpec{
+			ExistsInDB:      true,
+			IsBrandNew:      false,
+			DBRecordVersion: dbRecordVersion,
+			DBHistorySize:   dbHistorySize,
+		}, nil
 	case *serviceerror.NotFound:
 		return NewWorkflow(
-				r.shardContext.GetClusterMetadata(),
-				wfContext,
-				workflow.NewMutableState(
-					r.shardContext,
-					r.shardContext.GetEventsCache(),
-					r.logger,
-					namespaceEntry,
-					workflowKey.WorkflowID,
-					workflowKey.RunID,
-					time.Now().UTC(),
-				),
-				releaseFn,
-			), MutableStateInitializationSpec{
-				ExistsInDB:      false,
-				IsBrandNew:      true,
-				DBRecordVersion: 1,
-				DBHistorySize:   0,
-			}, nil
+			r.shardContext.GetClusterMetadata(),
+			wfContext,
+			workflow.NewMutableState(
+				r.shardContext,
+				r.eventsCache(namespaceEntry),
+				r.logger,
+				namespaceEntry,
+				workflowKey.WorkflowID,
+				workflowKey.RunID,
+				time.Now().UTC(),
+			),
+			releaseFn,
+		), MutableStateInitializationSpec{
+			ExistsInDB:      false,
+			IsBrandNew:      true,
+			DBRecordVersion: 1,
+			DBHistorySize:   0,
+		}, nil
 	default:
 		releaseFn(err)
 		return nil, MutableStateInitializationSpec{}, err
@@ -186,32 +195,46 @@ func (r *MutableStateInitializerImpl) InitializeFromToken(
 		r.shardContext.GetThrottledLogger(),
 		r.shardContext.GetMetricsHandler(),
 	)
-	mutableStateRow, dbRecordVersion, dbHistorySize, existsInDB, err := r.deserializeBackfillToken(token)
+	mutableStateRow, backfillToken, err := r.deserializeBackfillToken(token)
 	if err != nil {
 		return nil, MutableStateInitializationSpec{}, err
 	}
 	mutableState, err := workflow.NewMutableStateFromDB(
 		r.shardContext,
-		r.shardContext.GetEventsCache(),
+		r.eventsCache(namespaceEntry),
 		r.logger,
 		namespaceEntry,
 		mutableStateRow,
-		dbRecordVersion,
+		backfillToken.DBRecordVersion,
 	)
 	if err != nil {
 		return nil, MutableStateInitializationSpec{}, err
 	}
 	return NewWorkflow(
-			r.shardContext.GetClusterMetadata(),
-			wfContext,
-			mutableState,
-			wcache.NoopReleaseFn,
-		), MutableStateInitializationSpec{
-			ExistsInDB:      existsInDB,
-			IsBrandNew:      false,
-			DBRecordVersion: dbRecordVersion,
-			DBHistorySize:   dbHistorySize,
-		}, nil
+		r.shardContext.GetClusterMetadata(),
+		wfContext,
+		mutableState,
+		wcache.NoopReleaseFn,
+	), MutableStateInitializationSpec{
+		ExistsInDB:      backfillToken.ExistsInDB,
+		IsBrandNew:      false,
+		DBRecordVersion: backfillToken.DBRecordVersion,
+		DBHistorySize:   backfillToken.DBHistorySize,
+		ImportID:        backfillToken.ImportID,
+		LastEventTime:   backfillToken.LastEventTime,
+	}, nil
+}
+
+// eventsCache returns the events cache for mutable states created by the importer. Workflows that
+// already exist in DB are loaded through the workflow cache and always use the shard's cache.
+func (r *MutableStateInitializerImpl) eventsCache(
+	namespaceEntry *namespace.Namespace,
+) events.Cache {
+	eventsCache := r.shardContext.GetEventsCache()
+	if r.shardContext.GetConfig().ImportBypassEventsCache(namespaceEntry.Name().String()) {
+		return events.NewWriteBypassCache(eventsCache)
+	}
+	return eventsCache
 }
 
 func (r *MutableStateInitializerImpl) flushBufferEvents(
@@ -236,6 +259,8 @@ func (r *MutableStateInitializerImpl) serializeBackfillToken(
 	dbRecordVersion int64,
 	dbHistorySize int64,
 	existsInDB bool,
+	importID string,
+	lastEventTime time.Time,
 ) ([]byte, error) {
 	// This is ultimately for the replication rpc stream, so it's not really a request or
 	// response, but use SourceRPCResponse here since it's outgoing data.
@@ -251,12 +276,14 @@ func (r *MutableStateInitializerImpl) serializeBackfillToken(
 		DBRecordVersion: dbRecordVersion,
 		DBHistorySize:   dbHistorySize,
 		ExistsInDB:      existsInDB,
+		ImportID:        importID,
+		LastEventTime:   lastEventTime,
 	})
 }
 
 func (r *MutableStateInitializerImpl) deserializeBackfillToken(
 	token []byte,
-) (_ *persistencespb.WorkflowMutableState, _ int64, _ int64, _ bool, _ error) {
+) (*persistencespb.WorkflowMutableState, *MutableStateToken, error) {
 	mutableState := &persistencespb.WorkflowMutableState{
 		ActivityInfos:       make(map[int64]*persistencespb.ActivityInfo),
 		TimerInfos:          make(map[string]*persistencespb.TimerInfo),
@@ -274,7 +301,7 @@ func (r *MutableStateInitializerImpl) deserializeBackfillToken(
 
 	historyBackfillToken := &MutableStateToken{}
 	if err := json.Unmarshal(token, historyBackfillToken); err != nil {
-		return nil, 0, 0, false, serialization.NewDeserializationError(enums.ENCODING_TYPE_JSON, err)
+		return nil, nil, serialization.NewDeserializationError(enums.ENCODING_TYPE_JSON, err)
 	}
 	err := proto.Unmarshal(historyBackfillToken.MutableStateRow, mutableState)
 	if err == nil {
@@ -283,7 +310,7 @@ func (r *MutableStateInitializerImpl) deserializeBackfillToken(
 		err = utf8validator.Validate(mutableState, utf8validator.SourceRPCRequest)
 	}
 	if err != nil {
-		return nil, 0, 0, false, serialization.NewDeserializationError(enums.ENCODING_TYPE_PROTO3, err)
+		return nil, nil, serialization.NewDeserializationError(enums.ENCODING_TYPE_PROTO3, err)
 	}
-	return mutableState, historyBackfillToken.DBRecordVersion, historyBackfillToken.DBHistorySize, historyBackfillToken.ExistsInDB, nil
+	return mutableState, historyBackfillToken, nil
 }
@@ -0,0 +1,136 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ndc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/service/history/hsm"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tests"
+	"go.temporal.io/server/service/history/workflow"
+	wcache "go.temporal.io/server/service/history/workflow/cache"
+)
+
+type (
+	mutableStateInitializerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockShard         *shard.ContextTest
+		mockWorkflowCache *wcache.MockCache
+		mockContext       *workflow.MockContext
+
+		workflowKey definition.WorkflowKey
+
+		initializer *MutableStateInitializerImpl
+	}
+)
+
+func TestMutableStateInitializerSuite(t *testing.T) {
+	s := new(mutableStateInitializerSuite)
+	suite.Run(t, s)
+}
+
+func (s *mutableStateInitializerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	s.mockWorkflowCache = wcache.NewMockCache(s.controller)
+	s.mockContext = workflow.NewMockContext(s.controller)
+
+	s.mockShard = shard.NewTestContext(
+		s.controller,
+		&persistencespb.ShardInfo{
+			ShardId: 10,
+			RangeId: 1,
+		},
+		tests.NewDynamicConfig(),
+	)
+	reg := hsm.NewRegistry()
+	err := workflow.RegisterStateMachine(reg)
+	s.NoError(err)
+	s.mockShard.SetStateMachineRegistry(reg)
+
+	s.mockShard.Resource.ClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockShard.Resource.ClusterMetadata.EXPECT().GetClusterID().Return(cluster.TestCurrentClusterInitialFailoverVersion).AnyTimes()
+	s.mockShard.Resource.ClusterMetadata.EXPECT().ClusterNameForFailoverVersion(gomock.Any(), gomock.Any()).Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockShard.Resource.NamespaceCache.EXPECT().GetNamespaceByID(tests.NamespaceID).Return(tests.GlobalNamespaceEntry, nil).AnyTimes()
+
+	s.workflowKey = definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	s.initializer = NewMutableStateInitializer(s.mockShard, s.mockWorkflowCache, s.mockShard.GetLogger())
+}
+
+func (s *mutableStateInitializerSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.StopForTest()
+}
+
+func (s *mutableStateInitializerSuite) TestInitialize_BrandNew_PopulatesEventsCache() {
+	s.mockShard.MockEventsCache.EXPECT().PutEvent(gomock.Any(), gomock.Any()).Times(1)
+
+	s.addEventToBrandNewWorkflow()
+}
+
+func (s *mutableStateInitializerSuite) TestInitialize_BrandNew_BypassEventsCache() {
+	s.mockShard.GetConfig().ImportBypassEventsCache = dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true)
+	// no calls to MockEventsCache expected
+
+	s.addEventToBrandNewWorkflow()
+}
+
+func (s *mutableStateInitializerSuite) addEventToBrandNewWorkflow() {
+	s.mockWorkflowCache.EXPECT().GetOrCreateWorkflowExecution(
+		gomock.Any(),
+		s.mockShard,
+		namespace.ID(s.workflowKey.NamespaceID),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(s.mockContext, wcache.NoopReleaseFn, nil)
+	s.mockContext.EXPECT().LoadMutableState(gomock.Any(), s.mockShard).Return(nil, serviceerror.NewNotFound(""))
+
+	ndcWorkflow, spec, err := s.initializer.Initialize(context.Background(), s.workflowKey, nil)
+	s.NoError(err)
+	s.True(spec.IsBrandNew)
+
+	ndcWorkflow.GetMutableState().AddHistoryEvent(
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED,
+		func(*historypb.HistoryEvent) {},
+	)
+}
@@ -556,6 +556,7 @@ func (s *transactionMgrSuite) TestCheckWorkflowExists_DoesNotExists() {
 	workflowID := "some random workflow ID"
 	runID := "some random run ID"
 
+	s.mockShard.Resource.NamespaceCache.EXPECT().GetNamespaceByID(namespaceID).Return(tests.LocalNamespaceEntry, nil).AnyTimes()
 	s.mockExecutionMgr.EXPECT().GetWorkflowExecution(gomock.Any(), &persistence.GetWorkflowExecutionRequest{
 		ShardID:     s.mockShard.GetShardID(),
 		NamespaceID: namespaceID.String(),
@@ -574,6 +575,7 @@ func (s *transactionMgrSuite) TestCheckWorkflowExists_DoesExists() {
 	workflowID := "some random workflow ID"
 	runID := "some random run ID"
 
+	s.mockShard.Resource.NamespaceCache.EXPECT().GetNamespaceByID(namespaceID).Return(tests.LocalNamespaceEntry, nil).AnyTimes()
 	s.mockExecutionMgr.EXPECT().GetWorkflowExecution(gomock.Any(), &persistence.GetWorkflowExecutionRequest{
 		ShardID:     s.mockShard.GetShardID(),
 		NamespaceID: namespaceID.String(),
@@ -281,6 +281,7 @@ func (f *outboundQueueFactory) CreateQueue(
 		logger,
 	)
 
+	executor = queues.NewQuarantineExecutor(shardContext, executor)
 	if f.ExecutorWrapper != nil {
 		executor = f.ExecutorWrapper.Wrap(executor)
 	}
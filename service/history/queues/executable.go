@@ -425,6 +425,11 @@ func (e *executableImpl) isExpectedRetryableError(err error) (isRetryable bool,
 		return true, err
 	}
 
+	if err == consts.ErrWorkflowQuarantined {
+		metrics.TaskWorkflowQuarantinedCounter.With(e.taggedMetricsHandler).Record(1)
+		return true, err
+	}
+
 	if err == consts.ErrTaskRetry {
 		metrics.TaskStandbyRetryCounter.With(e.taggedMetricsHandler).Record(1)
 		return true, err
@@ -718,7 +723,8 @@ func (e *executableImpl) shouldResubmitOnNack(attempt int, err error) bool {
 
 	return err != consts.ErrTaskRetry &&
 		err != consts.ErrDependencyTaskNotCompleted &&
-		err != consts.ErrNamespaceHandover
+		err != consts.ErrNamespaceHandover &&
+		err != consts.ErrWorkflowQuarantined
 }
 
 func (e *executableImpl) backoffDuration(
@@ -730,6 +736,7 @@ func (e *executableImpl) backoffDuration(
 
 	if err == consts.ErrTaskRetry ||
 		err == consts.ErrNamespaceHandover ||
+		err == consts.ErrWorkflowQuarantined ||
 		common.IsInternalError(err) {
 		// using a different reschedule policy to slow down retry
 		// as immediate retry typically won't resolve the issue.
@@ -715,6 +715,12 @@ func (s *executableSuite) TestHandleErr_ErrDeleteOpenExecution() {
 	s.Equal(consts.ErrDependencyTaskNotCompleted, executable.HandleErr(consts.ErrDependencyTaskNotCompleted))
 }
 
+func (s *executableSuite) TestHandleErr_ErrWorkflowQuarantined() {
+	executable := s.newTestExecutable()
+
+	s.Equal(consts.ErrWorkflowQuarantined, executable.HandleErr(consts.ErrWorkflowQuarantined))
+}
+
 func (s *executableSuite) TestHandleErr_ErrTaskDiscarded() {
 	executable := s.newTestExecutable()
 
@@ -796,6 +802,10 @@ func (s *executableSuite) TestTaskNack_Reschedule() {
 			name:    "ErrDeleteOpenExecErr",
 			taskErr: consts.ErrDependencyTaskNotCompleted, // this error won't trigger re-submit
 		},
+		{
+			name:    "ErrWorkflowQuarantined",
+			taskErr: consts.ErrWorkflowQuarantined, // this error won't trigger re-submit
+		},
 	}
 
 	for _, tc := range testCases {
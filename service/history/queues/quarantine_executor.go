@@ -0,0 +1,80 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package queues
+
+import (
+	"context"
+
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/service/history/consts"
+	"go.temporal.io/server/service/history/shard"
+)
+
+type (
+	quarantineExecutor struct {
+		shardContext shard.Context
+		delegate     Executor
+	}
+)
+
+// NewQuarantineExecutor returns an Executor that holds the tasks of the workflows quarantined on
+// the shard instead of passing them to the delegate. Held tasks fail with
+// consts.ErrWorkflowQuarantined, so they stay pending and are retried with backoff.
+func NewQuarantineExecutor(
+	shardContext shard.Context,
+	delegate Executor,
+) Executor {
+	return &quarantineExecutor{
+		shardContext: shardContext,
+		delegate:     delegate,
+	}
+}
+
+func (e *quarantineExecutor) Execute(
+	ctx context.Context,
+	executable Executable,
+) ExecuteResponse {
+	workflowKey := definition.NewWorkflowKey(
+		executable.GetNamespaceID(),
+		executable.GetWorkflowID(),
+		executable.GetRunID(),
+	)
+	if !e.shardContext.IsWorkflowQuarantined(workflowKey) {
+		return e.delegate.Execute(ctx, executable)
+	}
+
+	registry := e.shardContext.GetNamespaceRegistry()
+	currentClusterName := e.shardContext.GetClusterMetadata().GetCurrentClusterName()
+	isActive := true
+	if ns, err := registry.GetNamespaceByID(namespace.ID(executable.GetNamespaceID())); err == nil {
+		isActive = ns.ActiveInCluster(currentClusterName)
+	}
+	return ExecuteResponse{
+		ExecutionMetricTags: EstimateTaskMetricTag(executable, registry, currentClusterName),
+		ExecutedAsActive:    isActive,
+		ExecutionErr:        consts.ErrWorkflowQuarantined,
+	}
+}
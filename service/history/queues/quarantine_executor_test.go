@@ -0,0 +1,78 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package queues
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/service/history/consts"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tasks"
+	"go.temporal.io/server/service/history/tests"
+)
+
+func TestQuarantineExecutor(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	workflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+
+	registry := namespace.NewMockRegistry(ctrl)
+	registry.EXPECT().GetNamespaceByID(tests.NamespaceID).Return(tests.GlobalNamespaceEntry, nil).AnyTimes()
+	clusterMetadata := cluster.NewMockMetadata(ctrl)
+	clusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	shardContext := shard.NewMockContext(ctrl)
+	shardContext.EXPECT().GetNamespaceRegistry().Return(registry).AnyTimes()
+	shardContext.EXPECT().GetClusterMetadata().Return(clusterMetadata).AnyTimes()
+
+	executable := NewMockExecutable(ctrl)
+	executable.EXPECT().GetNamespaceID().Return(workflowKey.NamespaceID).AnyTimes()
+	executable.EXPECT().GetWorkflowID().Return(workflowKey.WorkflowID).AnyTimes()
+	executable.EXPECT().GetRunID().Return(workflowKey.RunID).AnyTimes()
+	executable.EXPECT().GetTask().Return(&tasks.ActivityTask{WorkflowKey: workflowKey}).AnyTimes()
+
+	delegate := NewMockExecutor(ctrl)
+	executor := NewQuarantineExecutor(shardContext, delegate)
+
+	// the task is held while the workflow is quarantined
+	shardContext.EXPECT().IsWorkflowQuarantined(workflowKey).Return(true)
+	resp := executor.Execute(context.Background(), executable)
+	require.ErrorIs(t, resp.ExecutionErr, consts.ErrWorkflowQuarantined)
+	require.True(t, resp.ExecutedAsActive)
+	require.NotEmpty(t, resp.ExecutionMetricTags)
+
+	// and processed once it's unquarantined
+	shardContext.EXPECT().IsWorkflowQuarantined(workflowKey).Return(false)
+	delegate.EXPECT().Execute(gomock.Any(), executable).Return(ExecuteResponse{ExecutedAsActive: true})
+	resp = executor.Execute(context.Background(), executable)
+	require.NoError(t, resp.ExecutionErr)
+}
@@ -0,0 +1,162 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+
+	historyspb "go.temporal.io/server/api/history/v1"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/collection"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/service/history/shard"
+)
+
+type (
+	// archivalHistoryReader reads the history of a workflow from the history archival store of its
+	// namespace, as HistoryBatches that can be imported like the ones fetched from a remote cluster.
+	archivalHistoryReader struct {
+		historyArchiver archiver.HistoryArchiver
+		uri             archiver.URI
+		serializer      serialization.Serializer
+		workflowKey     definition.WorkflowKey
+	}
+)
+
+func newArchivalHistoryReader(
+	shardContext shard.Context,
+	archiverProvider provider.ArchiverProvider,
+	serializer serialization.Serializer,
+	workflowKey definition.WorkflowKey,
+) (*archivalHistoryReader, error) {
+	if !shardContext.GetArchivalMetadata().GetHistoryConfig().ReadEnabled() {
+		return nil, serviceerror.NewFailedPrecondition("reading from history archival is not enabled on the cluster")
+	}
+	nsEntry, err := shardContext.GetNamespaceRegistry().GetNamespaceByID(namespace.ID(workflowKey.NamespaceID))
+	if err != nil {
+		return nil, err
+	}
+	uriString := nsEntry.HistoryArchivalState().URI
+	if uriString == "" {
+		return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf("namespace %v has no history archival URI", nsEntry.Name()))
+	}
+	uri, err := archiver.NewURI(uriString)
+	if err != nil {
+		return nil, err
+	}
+	historyArchiver, err := archiverProvider.GetHistoryArchiver(uri.Scheme(), string(primitives.HistoryService))
+	if err != nil {
+		return nil, err
+	}
+	return &archivalHistoryReader{
+		historyArchiver: historyArchiver,
+		uri:             uri,
+		serializer:      serializer,
+		workflowKey:     workflowKey,
+	}, nil
+}
+
+// readVersionHistory reads the whole archived history once to build its version history, which
+// the import API needs with every batch but the archive doesn't keep.
+func (r *archivalHistoryReader) readVersionHistory(
+	ctx context.Context,
+) (*historyspb.VersionHistory, error) {
+	versionHistory := versionhistory.NewVersionHistory(nil, nil)
+	var pageToken []byte
+	for {
+		response, err := r.getPage(ctx, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range response.HistoryBatches {
+			for _, event := range batch.GetEvents() {
+				if err := versionhistory.AddOrUpdateVersionHistoryItem(
+					versionHistory,
+					versionhistory.NewVersionHistoryItem(event.GetEventId(), event.GetVersion()),
+				); err != nil {
+					return nil, err
+				}
+			}
+		}
+		pageToken = response.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	if versionhistory.IsEmptyVersionHistory(versionHistory) {
+		return nil, serviceerror.NewNotFound("archived history of workflow is empty")
+	}
+	return versionHistory, nil
+}
+
+// iterator returns the archived history batches, re-encoded in the blob format of the import API.
+func (r *archivalHistoryReader) iterator(
+	ctx context.Context,
+	versionHistory *historyspb.VersionHistory,
+) collection.Iterator[HistoryBatch] {
+	return collection.NewPagingIterator(func(pageToken []byte) ([]HistoryBatch, []byte, error) {
+		response, err := r.getPage(ctx, pageToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		batches := make([]HistoryBatch, 0, len(response.HistoryBatches))
+		for _, batch := range response.HistoryBatches {
+			if len(batch.GetEvents()) == 0 {
+				continue
+			}
+			blob, err := r.serializer.SerializeEvents(batch.Events, enumspb.ENCODING_TYPE_PROTO3)
+			if err != nil {
+				return nil, nil, err
+			}
+			batches = append(batches, HistoryBatch{
+				VersionHistory: versionHistory,
+				RawEventBatch:  blob,
+			})
+		}
+		return batches, response.NextPageToken, nil
+	})
+}
+
+func (r *archivalHistoryReader) getPage(
+	ctx context.Context,
+	pageToken []byte,
+) (*archiver.GetHistoryResponse, error) {
+	return r.historyArchiver.Get(ctx, r.uri, &archiver.GetHistoryRequest{
+		NamespaceID:   r.workflowKey.NamespaceID,
+		WorkflowID:    r.workflowKey.WorkflowID,
+		RunID:         r.workflowKey.RunID,
+		NextPageToken: pageToken,
+		PageSize:      int(defaultPageSize),
+	})
+}
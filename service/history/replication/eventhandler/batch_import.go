@@ -0,0 +1,262 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/quotas"
+	"go.temporal.io/server/service/history/shard"
+)
+
+type (
+	// ImportWorkflowsOptions are the options of LocalGeneratedEventsHandler.ImportWorkflows.
+	ImportWorkflowsOptions struct {
+		// Concurrency is the max number of workflows imported at once. Defaults to 1.
+		Concurrency int
+		// RPS is the max number of workflow imports started per second, shared by all of the
+		// batch. If zero, imports are not rate limited.
+		RPS float64
+		// OnProgress, if set, is called after each workflow of the batch is imported or failed to.
+		// Calls are serialized.
+		OnProgress func(ImportProgress)
+		// Streamed imports each workflow as ImportStreamedHistoryEventsFromBeginning does, to bound
+		// the memory used by workflows with long histories. The source cluster must support
+		// StreamWorkflowExecutionRawHistory.
+		Streamed bool
+		// SkipNamespaceReplicationCheck imports the workflows even if their namespace is not a
+		// global namespace replicated from the source cluster. The imported workflows are then not
+		// replicated to or from the source cluster, so it's only meant for operators who know that
+		// the namespace is going to be set up for it.
+		SkipNamespaceReplicationCheck bool
+	}
+
+	// ImportProgress is the progress of a batch import, see ImportWorkflowsOptions.OnProgress.
+	ImportProgress struct {
+		Total    int
+		Imported int
+		Failed   int
+	}
+
+	// ImportSummary is the result of LocalGeneratedEventsHandler.ImportWorkflows.
+	ImportSummary struct {
+		Imported int
+		// Failures are the errors of the workflows that failed to import.
+		Failures map[definition.WorkflowKey]error
+	}
+)
+
+// ImportWorkflows imports the given workflows from remoteCluster as ImportHistoryEventsFromBeginning
+// does, using the version history of each workflow in remoteCluster. A workflow that fails to
+// import doesn't abort the batch: its error is reported in the summary. An error is only returned
+// if ctx is done before the batch completes, along with the summary of the workflows imported or
+// failed so far.
+//
+// The batch backs off from target shards that are under load, so that it doesn't starve their live
+// traffic, see history.importThrottleLatencyThreshold and
+// history.importThrottleShardExecutionsThreshold.
+//
+// Before anything is imported, the namespaces of the workflows are checked to be global namespaces
+// replicated from remoteCluster, unless opts.SkipNamespaceReplicationCheck is set. Otherwise a
+// FailedPrecondition error is returned and nothing is imported.
+func (h *localEventsHandlerImpl) ImportWorkflows(
+	ctx context.Context,
+	remoteCluster string,
+	workflowKeys []definition.WorkflowKey,
+	opts ImportWorkflowsOptions,
+) (ImportSummary, error) {
+	if opts.SkipNamespaceReplicationCheck {
+		h.logger.Warn("Importing workflows without checking namespace replication config",
+			tag.SourceCluster(remoteCluster),
+		)
+	} else {
+		checked := make(map[string]struct{})
+		for _, workflowKey := range workflowKeys {
+			if _, ok := checked[workflowKey.NamespaceID]; ok {
+				continue
+			}
+			shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+			if err != nil {
+				return ImportSummary{}, err
+			}
+			if err := h.checkNamespaceReplication(shardContext, namespace.ID(workflowKey.NamespaceID), remoteCluster); err != nil {
+				return ImportSummary{}, err
+			}
+			checked[workflowKey.NamespaceID] = struct{}{}
+		}
+	}
+	throttler := newImportThrottler()
+	return importWorkflows(ctx, workflowKeys, opts, func(ctx context.Context, workflowKey definition.WorkflowKey) error {
+		return h.importWorkflow(ctx, remoteCluster, opts.Streamed, workflowKey, throttler)
+	})
+}
+
+// checkNamespaceReplication returns a FailedPrecondition error if namespaceID is not a global
+// namespace replicated from each of remoteClusters, as workflows imported into it would be
+// orphaned: they would not be replicated to or from the remote clusters.
+func (h *localEventsHandlerImpl) checkNamespaceReplication(
+	shardContext shard.Context,
+	namespaceID namespace.ID,
+	remoteClusters ...string,
+) error {
+	namespaceEntry, err := shardContext.GetNamespaceRegistry().GetNamespaceByID(namespaceID)
+	if err != nil {
+		return err
+	}
+	if !namespaceEntry.IsGlobalNamespace() {
+		return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+			"namespace %v is not a global namespace, imported workflows would not be replicated",
+			namespaceEntry.Name(),
+		))
+	}
+	for _, remoteCluster := range remoteClusters {
+		if !slices.Contains(namespaceEntry.ClusterNames(), remoteCluster) {
+			return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+				"namespace %v is not replicated from cluster %v, its clusters are %v",
+				namespaceEntry.Name(),
+				remoteCluster,
+				namespaceEntry.ClusterNames(),
+			))
+		}
+	}
+	return nil
+}
+
+func (h *localEventsHandlerImpl) importWorkflow(
+	ctx context.Context,
+	remoteCluster string,
+	streamed bool,
+	workflowKey definition.WorkflowKey,
+	throttler *importThrottler,
+) error {
+	shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+	if err != nil {
+		return err
+	}
+	if err := throttler.wait(ctx, shardContext); err != nil {
+		return err
+	}
+	versionHistory, err := h.getRemoteVersionHistory(ctx, shardContext, remoteCluster, workflowKey)
+	if err != nil {
+		return err
+	}
+	var maxLatency time.Duration
+	// the namespaces of the batch are checked, or skipped, by ImportWorkflows before it starts
+	err = h.importHistoryEventsFromBeginning(ctx, remoteCluster, streamed, true, workflowKey, versionHistory.GetItems(), nil, func(latency time.Duration) {
+		maxLatency = max(maxLatency, latency)
+	}, nil)
+	throttler.record(ctx, shardContext, maxLatency)
+	if err != nil {
+		h.logger.Warn("Failed to import workflow of batch",
+			tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+			tag.WorkflowID(workflowKey.WorkflowID),
+			tag.WorkflowRunID(workflowKey.RunID),
+			tag.Error(err),
+		)
+	}
+	return err
+}
+
+func importWorkflows(
+	ctx context.Context,
+	workflowKeys []definition.WorkflowKey,
+	opts ImportWorkflowsOptions,
+	importFn func(context.Context, definition.WorkflowKey) error,
+) (ImportSummary, error) {
+	if opts.RPS < 0 {
+		return ImportSummary{}, serviceerror.NewInvalidArgument("import RPS must not be negative")
+	}
+	concurrency := max(opts.Concurrency, 1)
+	var rateLimiter quotas.RateLimiter
+	if opts.RPS > 0 {
+		rateLimiter = quotas.NewDefaultOutgoingRateLimiter(func() float64 { return opts.RPS })
+	}
+
+	var lock sync.Mutex
+	summary := ImportSummary{Failures: make(map[definition.WorkflowKey]error)}
+	progress := ImportProgress{Total: len(workflowKeys)}
+	record := func(workflowKey definition.WorkflowKey, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			summary.Failures[workflowKey] = err
+			progress.Failed++
+		} else {
+			summary.Imported++
+			progress.Imported++
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+
+	keys := make(chan definition.WorkflowKey)
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(workflowKeys)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for workflowKey := range keys {
+				if ctx.Err() != nil {
+					// the batch is abandoned, see below
+					continue
+				}
+				if rateLimiter != nil {
+					if err := rateLimiter.Wait(ctx); err != nil {
+						record(workflowKey, err)
+						continue
+					}
+				}
+				record(workflowKey, importFn(ctx, workflowKey))
+			}
+		}()
+	}
+
+	var err error
+Loop:
+	for _, workflowKey := range workflowKeys {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case keys <- workflowKey:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break Loop
+		}
+	}
+	close(keys)
+	wg.Wait()
+	return summary, err
+}
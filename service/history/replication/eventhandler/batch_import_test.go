@@ -0,0 +1,238 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/serviceerror"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/service/history/shard"
+)
+
+func TestImportWorkflows_MixedBatch(t *testing.T) {
+	var workflowKeys []definition.WorkflowKey
+	for i := 0; i < 10; i++ {
+		workflowKeys = append(workflowKeys, definition.NewWorkflowKey("namespace-id", fmt.Sprintf("workflow-%d", i), "run-id"))
+	}
+	failed := map[definition.WorkflowKey]error{
+		workflowKeys[2]: serviceerror.NewNotFound("workflow not found"),
+		workflowKeys[7]: errors.New("some error"),
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	var progress []ImportProgress
+	summary, err := importWorkflows(
+		context.Background(),
+		workflowKeys,
+		ImportWorkflowsOptions{
+			Concurrency: 3,
+			OnProgress: func(p ImportProgress) {
+				progress = append(progress, p)
+			},
+		},
+		func(_ context.Context, workflowKey definition.WorkflowKey) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			return failed[workflowKey]
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 8, summary.Imported)
+	require.Equal(t, failed, summary.Failures)
+	require.LessOrEqual(t, maxInFlight.Load(), int32(3))
+
+	require.Len(t, progress, 10)
+	require.Equal(t, ImportProgress{Total: 10, Imported: 8, Failed: 2}, progress[9])
+	for i, p := range progress {
+		require.Equal(t, i+1, p.Imported+p.Failed)
+	}
+}
+
+func TestImportWorkflows_Canceled(t *testing.T) {
+	workflowKeys := []definition.WorkflowKey{
+		definition.NewWorkflowKey("namespace-id", "workflow-1", "run-id"),
+		definition.NewWorkflowKey("namespace-id", "workflow-2", "run-id"),
+		definition.NewWorkflowKey("namespace-id", "workflow-3", "run-id"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	summary, err := importWorkflows(ctx, workflowKeys, ImportWorkflowsOptions{}, func(_ context.Context, workflowKey definition.WorkflowKey) error {
+		// the batch stops after the first workflow
+		cancel()
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, summary.Imported)
+	require.Empty(t, summary.Failures)
+
+	_, err = importWorkflows(context.Background(), workflowKeys, ImportWorkflowsOptions{RPS: -1}, nil)
+	var invalidArgument *serviceerror.InvalidArgument
+	require.ErrorAs(t, err, &invalidArgument)
+}
+
+func TestImportWorkflows_PerWorkflowErrors(t *testing.T) {
+	controller := gomock.NewController(t)
+	shardController := shard.NewMockController(controller)
+	handler := NewLocalEventsHandler(
+		cluster.NewMockMetadata(controller),
+		shardController,
+		log.NewNoopLogger(),
+		serialization.NewSerializer(),
+		NewMockHistoryPaginatedFetcher(controller),
+		nil,
+	)
+	workflowKeys := []definition.WorkflowKey{
+		definition.NewWorkflowKey("namespace-id", "workflow-1", "run-id"),
+		definition.NewWorkflowKey("namespace-id", "workflow-2", "run-id"),
+	}
+	shardErr := serviceerror.NewUnavailable("shard not available")
+	for _, workflowKey := range workflowKeys {
+		shardController.EXPECT().GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID).
+			Return(nil, shardErr)
+	}
+
+	summary, err := handler.ImportWorkflows(context.Background(), cluster.TestAlternativeClusterName, workflowKeys, ImportWorkflowsOptions{
+		Concurrency:                   2,
+		SkipNamespaceReplicationCheck: true,
+	})
+	require.NoError(t, err)
+	require.Zero(t, summary.Imported)
+	require.Equal(t, map[definition.WorkflowKey]error{
+		workflowKeys[0]: shardErr,
+		workflowKeys[1]: shardErr,
+	}, summary.Failures)
+}
+
+func TestImportWorkflows_NamespaceReplicationCheck(t *testing.T) {
+	workflowKey := definition.NewWorkflowKey("namespace-id", "workflow-id", "run-id")
+	localNamespace := namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "local-namespace"},
+		nil,
+		cluster.TestCurrentClusterName,
+	)
+	globalNamespace := func(clusters ...string) *namespace.Namespace {
+		return namespace.NewGlobalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "global-namespace"},
+			nil,
+			&persistencespb.NamespaceReplicationConfig{ActiveClusterName: clusters[0], Clusters: clusters},
+			1,
+		)
+	}
+	setup := func(t *testing.T, namespaceEntry *namespace.Namespace) (LocalGeneratedEventsHandler, *shard.MockController, *shard.MockContext) {
+		controller := gomock.NewController(t)
+		shardController := shard.NewMockController(controller)
+		shardContext := shard.NewMockContext(controller)
+		namespaceRegistry := namespace.NewMockRegistry(controller)
+		shardContext.EXPECT().GetNamespaceRegistry().Return(namespaceRegistry).AnyTimes()
+		shardContext.EXPECT().GetShardID().Return(int32(1)).AnyTimes()
+		namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(workflowKey.NamespaceID)).Return(namespaceEntry, nil).AnyTimes()
+		handler := NewLocalEventsHandler(
+			cluster.NewMockMetadata(controller),
+			shardController,
+			log.NewNoopLogger(),
+			serialization.NewSerializer(),
+			NewMockHistoryPaginatedFetcher(controller),
+			nil,
+		)
+		return handler, shardController, shardContext
+	}
+
+	for name, namespaceEntry := range map[string]*namespace.Namespace{
+		"LocalNamespace":       localNamespace,
+		"SourceNotInNamespace": globalNamespace(cluster.TestCurrentClusterName),
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler, shardController, shardContext := setup(t, namespaceEntry)
+			// only the check looks up the shard, nothing is imported
+			shardController.EXPECT().GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID).
+				Return(shardContext, nil).Times(1)
+
+			summary, err := handler.ImportWorkflows(
+				context.Background(),
+				cluster.TestAlternativeClusterName,
+				[]definition.WorkflowKey{workflowKey, workflowKey},
+				ImportWorkflowsOptions{},
+			)
+			var failedPrecondition *serviceerror.FailedPrecondition
+			require.ErrorAs(t, err, &failedPrecondition)
+			require.Zero(t, summary.Imported)
+			require.Empty(t, summary.Failures)
+		})
+	}
+
+	t.Run("ReplicatedNamespace", func(t *testing.T) {
+		handler, shardController, shardContext := setup(t, globalNamespace(cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName))
+		shardController.EXPECT().GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID).
+			Return(shardContext, nil).Times(2)
+		adminClientErr := serviceerror.NewUnavailable("remote cluster not available")
+		shardContext.EXPECT().GetRemoteAdminClient(cluster.TestAlternativeClusterName).Return(nil, adminClientErr)
+
+		summary, err := handler.ImportWorkflows(
+			context.Background(),
+			cluster.TestAlternativeClusterName,
+			[]definition.WorkflowKey{workflowKey},
+			ImportWorkflowsOptions{},
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[definition.WorkflowKey]error{workflowKey: adminClientErr}, summary.Failures)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		handler, shardController, _ := setup(t, localNamespace)
+		shardErr := serviceerror.NewUnavailable("shard not available")
+		// the import is attempted without checking the namespace
+		shardController.EXPECT().GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID).
+			Return(nil, shardErr).Times(1)
+
+		summary, err := handler.ImportWorkflows(
+			context.Background(),
+			cluster.TestAlternativeClusterName,
+			[]definition.WorkflowKey{workflowKey},
+			ImportWorkflowsOptions{SkipNamespaceReplicationCheck: true},
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[definition.WorkflowKey]error{workflowKey: shardErr}, summary.Failures)
+	})
+}
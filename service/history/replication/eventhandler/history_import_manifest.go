@@ -0,0 +1,165 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/serialization"
+)
+
+type (
+	// HistoryImportManifest describes the history event batches of a workflow run as stored in
+	// the source cluster, so that an import can verify that it received exactly those batches.
+	HistoryImportManifest struct {
+		Batches []HistoryImportBatchChecksum
+	}
+
+	HistoryImportBatchChecksum struct {
+		FirstEventID int64
+		EventCount   int
+		// Checksum is the CRC32 of the raw data of this batch and all batches before it.
+		Checksum uint32
+	}
+
+	// historyImportVerifier checks imported batches against a HistoryImportManifest in order.
+	historyImportVerifier struct {
+		manifest  *HistoryImportManifest
+		nextBatch int
+		checksum  uint32
+	}
+)
+
+// ReadHistoryImportManifest builds the manifest of events [1, endEventID] of the given history
+// branch from persistence. It is meant to be called on the source cluster of an import.
+func ReadHistoryImportManifest(
+	ctx context.Context,
+	executionManager persistence.ExecutionManager,
+	eventSerializer serialization.Serializer,
+	shardID int32,
+	branchToken []byte,
+	endEventID int64,
+) (*HistoryImportManifest, error) {
+	request := &persistence.ReadHistoryBranchRequest{
+		ShardID:     shardID,
+		BranchToken: branchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  endEventID + 1,
+		PageSize:    int(defaultPageSize),
+	}
+	var blobs []*commonpb.DataBlob
+	for {
+		resp, err := executionManager.ReadRawHistoryBranch(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, resp.HistoryEventBlobs...)
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+	return NewHistoryImportManifest(eventSerializer, blobs)
+}
+
+// NewHistoryImportManifest builds the manifest of the given raw history event batches.
+func NewHistoryImportManifest(
+	eventSerializer serialization.Serializer,
+	batches []*commonpb.DataBlob,
+) (*HistoryImportManifest, error) {
+	manifest := &HistoryImportManifest{
+		Batches: make([]HistoryImportBatchChecksum, 0, len(batches)),
+	}
+	var checksum uint32
+	for _, batch := range batches {
+		events, err := eventSerializer.DeserializeEvents(batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			return nil, serviceerror.NewInternal("history import manifest: empty history event batch")
+		}
+		checksum = crc32.Update(checksum, crc32.IEEETable, batch.GetData())
+		manifest.Batches = append(manifest.Batches, HistoryImportBatchChecksum{
+			FirstEventID: events[0].GetEventId(),
+			EventCount:   len(events),
+			Checksum:     checksum,
+		})
+	}
+	return manifest, nil
+}
+
+func newHistoryImportVerifier(manifest *HistoryImportManifest) *historyImportVerifier {
+	return &historyImportVerifier{manifest: manifest}
+}
+
+// verifyBatch checks the next batch against the manifest. firstEventID and eventCount are the
+// ones of the deserialized batch.
+func (v *historyImportVerifier) verifyBatch(
+	batch *commonpb.DataBlob,
+	firstEventID int64,
+	eventCount int,
+) error {
+	if v.nextBatch >= len(v.manifest.Batches) {
+		return serviceerror.NewDataLoss(fmt.Sprintf(
+			"history import: unexpected history event batch starting at event %v", firstEventID,
+		))
+	}
+	expected := v.manifest.Batches[v.nextBatch]
+	if firstEventID != expected.FirstEventID {
+		return serviceerror.NewDataLoss(fmt.Sprintf(
+			"history import: expected history event batch starting at event %v, got event %v",
+			expected.FirstEventID,
+			firstEventID,
+		))
+	}
+	checksum := crc32.Update(v.checksum, crc32.IEEETable, batch.GetData())
+	if eventCount != expected.EventCount || checksum != expected.Checksum {
+		return serviceerror.NewDataLoss(fmt.Sprintf(
+			"history import: checksum mismatch in history event batch starting at event %v", firstEventID,
+		))
+	}
+	v.nextBatch++
+	v.checksum = checksum
+	return nil
+}
+
+// verifyComplete checks that all batches of the manifest were verified.
+func (v *historyImportVerifier) verifyComplete() error {
+	if v.nextBatch < len(v.manifest.Batches) {
+		return serviceerror.NewDataLoss(fmt.Sprintf(
+			"history import: missing history event batch starting at event %v",
+			v.manifest.Batches[v.nextBatch].FirstEventID,
+		))
+	}
+	return nil
+}
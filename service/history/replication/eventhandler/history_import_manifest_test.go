@@ -0,0 +1,88 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	historypb "go.temporal.io/api/history/v1"
+
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/serialization"
+)
+
+func TestReadHistoryImportManifest(t *testing.T) {
+	controller := gomock.NewController(t)
+	executionManager := persistence.NewMockExecutionManager(controller)
+	serializer := serialization.NewSerializer()
+	blobs := serializeEvents(serializer, [][]*historypb.HistoryEvent{
+		{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}},
+		{{EventId: 3, Version: 1}},
+	})
+	branchToken := []byte("branch-token")
+
+	gomock.InOrder(
+		executionManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), &persistence.ReadHistoryBranchRequest{
+			ShardID:     1,
+			BranchToken: branchToken,
+			MinEventID:  1,
+			MaxEventID:  4,
+			PageSize:    int(defaultPageSize),
+		}).Return(&persistence.ReadRawHistoryBranchResponse{
+			HistoryEventBlobs: blobs[:1],
+			NextPageToken:     []byte("next"),
+		}, nil),
+		executionManager.EXPECT().ReadRawHistoryBranch(gomock.Any(), &persistence.ReadHistoryBranchRequest{
+			ShardID:       1,
+			BranchToken:   branchToken,
+			MinEventID:    1,
+			MaxEventID:    4,
+			PageSize:      int(defaultPageSize),
+			NextPageToken: []byte("next"),
+		}).Return(&persistence.ReadRawHistoryBranchResponse{
+			HistoryEventBlobs: blobs[1:],
+		}, nil),
+	)
+
+	manifest, err := ReadHistoryImportManifest(context.Background(), executionManager, serializer, 1, branchToken, 3)
+	require.NoError(t, err)
+	expected, err := NewHistoryImportManifest(serializer, blobs)
+	require.NoError(t, err)
+	require.Equal(t, expected, manifest)
+	require.Len(t, manifest.Batches, 2)
+	require.Equal(t, int64(3), manifest.Batches[1].FirstEventID)
+	require.Equal(t, 1, manifest.Batches[1].EventCount)
+	require.NotEqual(t, manifest.Batches[0].Checksum, manifest.Batches[1].Checksum)
+
+	// verifying the same batches succeeds
+	verifier := newHistoryImportVerifier(manifest)
+	require.NoError(t, verifier.verifyBatch(blobs[0], 1, 2))
+	require.NoError(t, verifier.verifyBatch(blobs[1], 3, 1))
+	require.NoError(t, verifier.verifyComplete())
+	require.Error(t, verifier.verifyBatch(blobs[1], 4, 1))
+}
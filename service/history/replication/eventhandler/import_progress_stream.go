@@ -0,0 +1,107 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+
+	historyspb "go.temporal.io/server/api/history/v1"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/namespace"
+)
+
+type (
+	// ImportEventsProgress is the progress of a single workflow import, see
+	// LocalGeneratedEventsHandler.StreamImportHistoryEventsFromBeginning.
+	ImportEventsProgress struct {
+		// EventsApplied is the number of events imported so far.
+		EventsApplied int64
+		// CurrentEventID is the ID of the last imported event.
+		CurrentEventID int64
+		// BytesTransferred is the size of the serialized event batches imported so far.
+		BytesTransferred int64
+		// Done is only set on the last message of a stream, which summarizes the whole import
+		// once it's committed.
+		Done bool
+	}
+
+	// ImportProgressStream is the sending side of a server-streaming RPC that reports the progress
+	// of an import. It's a subset of the generated grpc server stream, so that an RPC handler can
+	// adapt its stream with a thin wrapper.
+	ImportProgressStream interface {
+		// Context is canceled when the client closes the stream.
+		Context() context.Context
+		Send(*ImportEventsProgress) error
+	}
+)
+
+// StreamImportHistoryEventsFromBeginning is ImportHistoryEventsFromBeginning, but reports the
+// progress of the import to stream after each import call to the target shard, followed by a
+// final summary once the import is committed. The import is aborted when the context of stream is
+// done, e.g. because the client closed it, or when a message fails to be sent. When
+// versionHistoryItems is empty, the current version history of the workflow is read from
+// remoteCluster.
+func (h *localEventsHandlerImpl) StreamImportHistoryEventsFromBeginning(
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+	manifest *HistoryImportManifest,
+	stream ImportProgressStream,
+) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	if len(versionHistoryItems) == 0 {
+		shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+		if err != nil {
+			return err
+		}
+		versionHistory, err := h.getRemoteVersionHistory(ctx, shardContext, remoteCluster, workflowKey)
+		if err != nil {
+			return err
+		}
+		versionHistoryItems = versionHistory.GetItems()
+	}
+
+	var progress ImportEventsProgress
+	var sendErr error
+	err := h.importHistoryEventsFromBeginning(ctx, remoteCluster, false, false, workflowKey, versionHistoryItems, manifest, nil, func(p ImportEventsProgress) {
+		progress = p
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(&p); sendErr != nil {
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return err
+	}
+	progress.Done = true
+	return stream.Send(&progress)
+}
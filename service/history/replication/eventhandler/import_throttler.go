@@ -0,0 +1,122 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/service/history/shard"
+)
+
+const (
+	// importThrottleMinBackoff is the backoff a target shard starts at once it's under load.
+	// Halving the backoff below it stops the throttling of the shard.
+	importThrottleMinBackoff = 50 * time.Millisecond
+)
+
+type (
+	// importThrottler slows a batch import down on the target shards that are under load, so that
+	// the import doesn't starve their live traffic. Each shard has its own backoff, which the import
+	// waits for before each workflow imported to the shard. The backoff is doubled, up to
+	// history.importThrottleMaxBackoff, each time the shard is found under load after an import,
+	// and halved each time it isn't, until the import runs at full speed again.
+	//
+	// A shard is under load if an import call to it took longer than
+	// history.importThrottleLatencyThreshold, as those calls are mostly persistence writes to the
+	// shard, or if its load estimate is over history.importThrottleShardExecutionsThreshold.
+	importThrottler struct {
+		sync.Mutex
+		backoffs map[int32]time.Duration
+	}
+)
+
+func newImportThrottler() *importThrottler {
+	return &importThrottler{
+		backoffs: make(map[int32]time.Duration),
+	}
+}
+
+// wait blocks for the current backoff of the shard, or until ctx is done.
+func (t *importThrottler) wait(
+	ctx context.Context,
+	shardContext shard.Context,
+) error {
+	backoff := t.backoff(shardContext.GetShardID())
+	if backoff == 0 {
+		return nil
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record updates the backoff of the shard after a workflow was imported to it. maxLatency is the
+// latency of the slowest import call of the workflow.
+func (t *importThrottler) record(
+	ctx context.Context,
+	shardContext shard.Context,
+	maxLatency time.Duration,
+) {
+	config := shardContext.GetConfig()
+	underLoad := false
+	if threshold := config.ImportThrottleLatencyThreshold(); threshold > 0 && maxLatency > threshold {
+		underLoad = true
+	}
+	if threshold := config.ImportThrottleShardExecutionsThreshold(); !underLoad && threshold > 0 {
+		// The estimate is cached by the shard, so this only scans the shard once in a while. A shard
+		// that can't be estimated is not throttled for it, the import calls would fail as well.
+		if estimate, err := shardContext.EstimateLoad(ctx); err == nil && estimate.ExecutionCount > int64(threshold) {
+			underLoad = true
+		}
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	shardID := shardContext.GetShardID()
+	backoff := t.backoffs[shardID]
+	if underLoad {
+		backoff = min(max(2*backoff, importThrottleMinBackoff), config.ImportThrottleMaxBackoff())
+	} else if backoff /= 2; backoff < importThrottleMinBackoff {
+		backoff = 0
+	}
+	if backoff == 0 {
+		delete(t.backoffs, shardID)
+	} else {
+		t.backoffs[shardID] = backoff
+	}
+}
+
+func (t *importThrottler) backoff(shardID int32) time.Duration {
+	t.Lock()
+	defer t.Unlock()
+	return t.backoffs[shardID]
+}
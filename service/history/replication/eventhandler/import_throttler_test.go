@@ -0,0 +1,131 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/service/history/configs"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tests"
+)
+
+func newThrottledShard(t *testing.T, shardID int32, config *configs.Config) *shard.MockContext {
+	shardContext := shard.NewMockContext(gomock.NewController(t))
+	shardContext.EXPECT().GetShardID().Return(shardID).AnyTimes()
+	shardContext.EXPECT().GetConfig().Return(config).AnyTimes()
+	return shardContext
+}
+
+func TestImportThrottler_Latency(t *testing.T) {
+	config := tests.NewDynamicConfig()
+	config.ImportThrottleLatencyThreshold = dynamicconfig.GetDurationPropertyFn(time.Second)
+	config.ImportThrottleMaxBackoff = dynamicconfig.GetDurationPropertyFn(time.Second)
+	hotShard := newThrottledShard(t, 1, config)
+	coldShard := newThrottledShard(t, 2, config)
+	throttler := newImportThrottler()
+	ctx := context.Background()
+
+	// high latency backs off exponentially, up to the max
+	var backoffs []time.Duration
+	for i := 0; i < 7; i++ {
+		throttler.record(ctx, hotShard, 2*time.Second)
+		backoffs = append(backoffs, throttler.backoff(1))
+	}
+	require.Equal(t, []time.Duration{
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, backoffs)
+
+	// other shards aren't affected
+	throttler.record(ctx, coldShard, 10*time.Millisecond)
+	require.Zero(t, throttler.backoff(2))
+	require.NoError(t, throttler.wait(ctx, coldShard))
+
+	// low latency restores full speed
+	backoffs = nil
+	for i := 0; i < 6; i++ {
+		throttler.record(ctx, hotShard, 10*time.Millisecond)
+		backoffs = append(backoffs, throttler.backoff(1))
+	}
+	require.Equal(t, []time.Duration{
+		500 * time.Millisecond,
+		250 * time.Millisecond,
+		125 * time.Millisecond,
+		62500 * time.Microsecond,
+		0,
+		0,
+	}, backoffs)
+}
+
+func TestImportThrottler_LoadEstimate(t *testing.T) {
+	config := tests.NewDynamicConfig()
+	config.ImportThrottleLatencyThreshold = dynamicconfig.GetDurationPropertyFn(0)
+	config.ImportThrottleShardExecutionsThreshold = dynamicconfig.GetIntPropertyFn(1000)
+	shardContext := newThrottledShard(t, 1, config)
+	throttler := newImportThrottler()
+	ctx := context.Background()
+
+	shardContext.EXPECT().EstimateLoad(ctx).Return(shard.ShardLoadEstimate{ExecutionCount: 5000}, nil).Times(2)
+	throttler.record(ctx, shardContext, time.Hour)
+	throttler.record(ctx, shardContext, time.Hour)
+	require.Equal(t, 100*time.Millisecond, throttler.backoff(1))
+
+	shardContext.EXPECT().EstimateLoad(ctx).Return(shard.ShardLoadEstimate{ExecutionCount: 500}, nil).Times(2)
+	throttler.record(ctx, shardContext, 0)
+	throttler.record(ctx, shardContext, 0)
+	require.Zero(t, throttler.backoff(1))
+}
+
+func TestImportThrottler_Wait(t *testing.T) {
+	config := tests.NewDynamicConfig()
+	config.ImportThrottleLatencyThreshold = dynamicconfig.GetDurationPropertyFn(time.Millisecond)
+	config.ImportThrottleMaxBackoff = dynamicconfig.GetDurationPropertyFn(time.Hour)
+	shardContext := newThrottledShard(t, 1, config)
+	throttler := newImportThrottler()
+
+	throttler.record(context.Background(), shardContext, time.Second)
+	start := time.Now()
+	require.NoError(t, throttler.wait(context.Background(), shardContext))
+	require.GreaterOrEqual(t, time.Since(start), importThrottleMinBackoff)
+
+	for i := 0; i < 20; i++ {
+		throttler.record(context.Background(), shardContext, time.Second)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, throttler.wait(ctx, shardContext), context.DeadlineExceeded)
+}
@@ -26,15 +26,20 @@ package eventhandler
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
 	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/server/api/adminservice/v1"
 	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
 	common2 "go.temporal.io/server/common"
+	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/collection"
 	"go.temporal.io/server/common/definition"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
@@ -60,6 +65,72 @@ type (
 			versionHistoryItems []*historyspb.VersionHistoryItem,
 			localEvents [][]*historypb.HistoryEvent,
 		) error
+		ReconcileVersionHistory(
+			ctx context.Context,
+			remoteCluster string,
+			workflowKey definition.WorkflowKey,
+		) error
+		ImportHistoryEventsFromBeginning(
+			ctx context.Context,
+			remoteCluster string,
+			workflowKey definition.WorkflowKey,
+			versionHistoryItems []*historyspb.VersionHistoryItem,
+			manifest *HistoryImportManifest,
+		) error
+		ImportHistoryEventsFromSources(
+			ctx context.Context,
+			sourceClusters []string,
+			policy SourceSelectionPolicy,
+			workflowKey definition.WorkflowKey,
+			versionHistoryItems []*historyspb.VersionHistoryItem,
+			manifest *HistoryImportManifest,
+		) ([]ImportSourceRange, error)
+		ImportStreamedHistoryEventsFromBeginning(
+			ctx context.Context,
+			remoteCluster string,
+			workflowKey definition.WorkflowKey,
+			versionHistoryItems []*historyspb.VersionHistoryItem,
+			manifest *HistoryImportManifest,
+		) error
+		StreamImportHistoryEventsFromBeginning(
+			remoteCluster string,
+			workflowKey definition.WorkflowKey,
+			versionHistoryItems []*historyspb.VersionHistoryItem,
+			manifest *HistoryImportManifest,
+			stream ImportProgressStream,
+		) error
+		ImportHistoryEventsFromArchive(
+			ctx context.Context,
+			workflowKey definition.WorkflowKey,
+		) error
+		DiffHistory(
+			ctx context.Context,
+			remoteCluster string,
+			workflowKey definition.WorkflowKey,
+		) ([]EventRange, error)
+		ImportWorkflows(
+			ctx context.Context,
+			remoteCluster string,
+			workflowKeys []definition.WorkflowKey,
+			opts ImportWorkflowsOptions,
+		) (ImportSummary, error)
+		ReconcileCurrentExecution(
+			ctx context.Context,
+			remoteCluster string,
+			namespaceID namespace.ID,
+			workflowID string,
+		) error
+	}
+
+	// EventRange is an inclusive range of events of a workflow history that share the same version,
+	// see LocalGeneratedEventsHandler.DiffHistory.
+	EventRange struct {
+		FirstEventID int64
+		LastEventID  int64
+		Version      int64
+		// Divergent is true if the local history has events in the range, but on a different branch
+		// than the source cluster. Otherwise the events are missing locally.
+		Divergent bool
 	}
 
 	localEventsHandlerImpl struct {
@@ -68,6 +139,7 @@ type (
 		logger                  log.Logger
 		eventSerializer         serialization.Serializer
 		historyPaginatedFetcher HistoryPaginatedFetcher
+		archiverProvider        provider.ArchiverProvider
 	}
 )
 
@@ -77,6 +149,7 @@ func NewLocalEventsHandler(
 	logger log.Logger,
 	eventSerializer serialization.Serializer,
 	historyPaginatedFetcher HistoryPaginatedFetcher,
+	archiverProvider provider.ArchiverProvider,
 ) LocalGeneratedEventsHandler {
 	return &localEventsHandlerImpl{
 		clusterMetadata:         clusterMetadata,
@@ -84,6 +157,7 @@ func NewLocalEventsHandler(
 		logger:                  logger,
 		eventSerializer:         eventSerializer,
 		historyPaginatedFetcher: historyPaginatedFetcher,
+		archiverProvider:        archiverProvider,
 	}
 }
 
@@ -132,6 +206,7 @@ func (h *localEventsHandlerImpl) HandleLocalGeneratedHistoryEvents(
 		return h.importEvents(
 			ctx,
 			sourceClusterName,
+			false,
 			engine,
 			workflowKey,
 			common2.FirstEventID,
@@ -139,11 +214,14 @@ func (h *localEventsHandlerImpl) HandleLocalGeneratedHistoryEvents(
 			localVersionHistory[len(localVersionHistory)-1].EventId,
 			localVersionHistory[len(localVersionHistory)-1].Version,
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 	default:
 		return err
 	}
-	response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, localEventsBlobs, versionHistory, nil)
+	response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, localEventsBlobs, versionHistory, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -156,7 +234,7 @@ func (h *localEventsHandlerImpl) HandleLocalGeneratedHistoryEvents(
 
 	if lastEvent.EventId == localVersionHistory[len(localVersionHistory)-1].EventId {
 		// all local events were imported successfully, we call commit to finish the transaction
-		_, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, nil, versionHistory, response.Token)
+		_, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, nil, versionHistory, response.Token, nil)
 		if err != nil {
 			return err
 		}
@@ -170,6 +248,7 @@ func (h *localEventsHandlerImpl) HandleLocalGeneratedHistoryEvents(
 	return h.importEvents(
 		ctx,
 		sourceClusterName,
+		false,
 		engine,
 		workflowKey,
 		nextEventId,
@@ -177,12 +256,357 @@ func (h *localEventsHandlerImpl) HandleLocalGeneratedHistoryEvents(
 		localVersionHistory[len(localVersionHistory)-1].EventId,
 		localVersionHistory[len(localVersionHistory)-1].Version,
 		response.Token,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// ImportHistoryEventsFromBeginning imports all local generated events of a workflow from the
+// source cluster, i.e. events [1, last local generated event] of versionHistoryItems. If manifest
+// is not nil, every batch fetched from the source cluster is verified against it before it is
+// applied, and the import is aborted with a DataLoss error naming the offending event at the first
+// mismatch. The manifest is built on the source cluster with ReadHistoryImportManifest for the
+// same events.
+//
+// Nothing is imported, and a FailedPrecondition error is returned, if the namespace of the workflow
+// is not a global namespace replicated from remoteCluster. Use ImportWorkflows with
+// ImportWorkflowsOptions.SkipNamespaceReplicationCheck to import it anyway.
+func (h *localEventsHandlerImpl) ImportHistoryEventsFromBeginning(
+	ctx context.Context,
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+	manifest *HistoryImportManifest,
+) error {
+	return h.importHistoryEventsFromBeginning(ctx, remoteCluster, false, false, workflowKey, versionHistoryItems, manifest, nil, nil)
+}
+
+// ImportStreamedHistoryEventsFromBeginning is ImportHistoryEventsFromBeginning, but receives the
+// events from the source cluster with a single StreamWorkflowExecutionRawHistory call, and applies
+// each batch as soon as enough of them arrived for an import call. At most one page of the stream
+// and one import call worth of batches are held in memory, however long the history is.
+func (h *localEventsHandlerImpl) ImportStreamedHistoryEventsFromBeginning(
+	ctx context.Context,
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+	manifest *HistoryImportManifest,
+) error {
+	return h.importHistoryEventsFromBeginning(ctx, remoteCluster, true, false, workflowKey, versionHistoryItems, manifest, nil, nil)
+}
+
+// importHistoryEventsFromBeginning is ImportHistoryEventsFromBeginning, or
+// ImportStreamedHistoryEventsFromBeginning if streamed is set, but also calls onImportCall, if set,
+// with the latency of each import call to the target shard, and onProgress, if set, after each
+// import call that applied events. The namespace replication check is skipped if
+// skipNamespaceReplicationCheck is set.
+func (h *localEventsHandlerImpl) importHistoryEventsFromBeginning(
+	ctx context.Context,
+	remoteCluster string,
+	streamed bool,
+	skipNamespaceReplicationCheck bool,
+	workflowKey definition.WorkflowKey,
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+	manifest *HistoryImportManifest,
+	onImportCall func(time.Duration),
+	onProgress func(ImportEventsProgress),
+) error {
+	_, engine, localVersionHistory, err := h.getImportFromBeginningTarget(ctx, []string{remoteCluster}, skipNamespaceReplicationCheck, workflowKey, versionHistoryItems)
+	if err != nil {
+		return err
+	}
+	var verifier *historyImportVerifier
+	if manifest != nil {
+		verifier = newHistoryImportVerifier(manifest)
+	}
+	return h.importEvents(
+		ctx,
+		remoteCluster,
+		streamed,
+		engine,
+		workflowKey,
+		common2.FirstEventID,
+		localVersionHistory[0].Version,
+		localVersionHistory[len(localVersionHistory)-1].EventId,
+		localVersionHistory[len(localVersionHistory)-1].Version,
+		nil,
+		verifier,
+		onImportCall,
+		onProgress,
 	)
 }
 
+// getImportFromBeginningTarget returns the shard and engine to import the workflow of workflowKey to,
+// and the local generated items of versionHistoryItems, which are the events to import. Unless
+// skipNamespaceReplicationCheck is set, it fails if the namespace of the workflow is not replicated
+// from each of remoteClusters, see checkNamespaceReplication.
+func (h *localEventsHandlerImpl) getImportFromBeginningTarget(
+	ctx context.Context,
+	remoteClusters []string,
+	skipNamespaceReplicationCheck bool,
+	workflowKey definition.WorkflowKey,
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+) (shard.Context, shard.Engine, []*historyspb.VersionHistoryItem, error) {
+	shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !skipNamespaceReplicationCheck {
+		if err := h.checkNamespaceReplication(shardContext, namespace.ID(workflowKey.NamespaceID), remoteClusters...); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	engine, err := shardContext.GetEngine(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	localVersionHistory, _ := versionhistory.SplitVersionHistoryByLastLocalGeneratedItem(versionHistoryItems, h.clusterMetadata.GetClusterID(), h.clusterMetadata.GetFailoverVersionIncrement())
+	if len(localVersionHistory) == 0 {
+		return nil, nil, nil, serviceerror.NewInvalidArgument("no local generated events to import")
+	}
+	return shardContext, engine, localVersionHistory, nil
+}
+
+// ImportHistoryEventsFromArchive imports the whole history of a workflow from the history archival
+// store of its namespace instead of a live cluster, e.g. to recover a workflow whose source cluster
+// is gone. The events are applied the same way as the ones fetched by ImportHistoryEventsFromBeginning.
+func (h *localEventsHandlerImpl) ImportHistoryEventsFromArchive(
+	ctx context.Context,
+	workflowKey definition.WorkflowKey,
+) error {
+	shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+	if err != nil {
+		return err
+	}
+	engine, err := shardContext.GetEngine(ctx)
+	if err != nil {
+		return err
+	}
+	reader, err := newArchivalHistoryReader(shardContext, h.archiverProvider, h.eventSerializer, workflowKey)
+	if err != nil {
+		return err
+	}
+	versionHistory, err := reader.readVersionHistory(ctx)
+	if err != nil {
+		return err
+	}
+	return h.importHistoryBatches(
+		ctx,
+		engine,
+		workflowKey,
+		reader.iterator(ctx, versionHistory),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// ReconcileVersionHistory repairs the local version history of a workflow that already has all of its
+// events locally, using the version history of the source cluster as the reference. Unlike
+// HandleLocalGeneratedHistoryEvents, no events are fetched from the source cluster. If local events are
+// missing or diverged from the source, a FailedPrecondition error is returned and the workflow needs
+// a full import.
+func (h *localEventsHandlerImpl) ReconcileVersionHistory(
+	ctx context.Context,
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+) error {
+	shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+	if err != nil {
+		return err
+	}
+	engine, err := shardContext.GetEngine(ctx)
+	if err != nil {
+		return err
+	}
+	sourceVersionHistory, err := h.getRemoteVersionHistory(ctx, shardContext, remoteCluster, workflowKey)
+	if err != nil {
+		return err
+	}
+	return engine.ReconcileVersionHistory(ctx, workflowKey, sourceVersionHistory)
+}
+
+// DiffHistory compares the version history of a workflow with the one of the source cluster and
+// returns the ranges of events that an import would need to fetch, without importing anything. The
+// ranges are split at version boundaries and ordered by event ID. Events the local history has
+// beyond the source's last event are not reported. An empty result means that the local history
+// already contains the source history.
+func (h *localEventsHandlerImpl) DiffHistory(
+	ctx context.Context,
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+) ([]EventRange, error) {
+	shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := shardContext.GetEngine(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sourceVersionHistory, err := h.getRemoteVersionHistory(ctx, shardContext, remoteCluster, workflowKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var localVersionHistory *historyspb.VersionHistory
+	resp, err := engine.GetMutableState(ctx, &historyservice.GetMutableStateRequest{
+		NamespaceId: workflowKey.NamespaceID,
+		Execution: &common.WorkflowExecution{
+			WorkflowId: workflowKey.WorkflowID,
+			RunId:      workflowKey.RunID,
+		},
+	})
+	switch err.(type) {
+	case nil:
+		localVersionHistory, err = versionhistory.GetCurrentVersionHistory(resp.GetVersionHistories())
+		if err != nil {
+			return nil, err
+		}
+	case *serviceerror.NotFound:
+		// workflow doesn't exist locally, all events are missing
+	default:
+		return nil, err
+	}
+	return diffVersionHistories(localVersionHistory, sourceVersionHistory)
+}
+
+// diffVersionHistories returns the event ranges of sourceVersionHistory that are not in
+// localVersionHistory, see DiffHistory. localVersionHistory may be nil if the workflow doesn't
+// exist locally.
+func diffVersionHistories(
+	localVersionHistory *historyspb.VersionHistory,
+	sourceVersionHistory *historyspb.VersionHistory,
+) ([]EventRange, error) {
+	var lcaEventID, localLastEventID int64
+	if len(localVersionHistory.GetItems()) != 0 {
+		lcaItem, err := versionhistory.FindLCAVersionHistoryItem(localVersionHistory, sourceVersionHistory)
+		if err != nil {
+			return nil, err
+		}
+		lastItem, err := versionhistory.GetLastVersionHistoryItem(localVersionHistory)
+		if err != nil {
+			return nil, err
+		}
+		lcaEventID = lcaItem.GetEventId()
+		localLastEventID = lastItem.GetEventId()
+	}
+
+	var ranges []EventRange
+	firstEventID := common2.FirstEventID
+	for _, item := range sourceVersionHistory.GetItems() {
+		if item.GetEventId() > lcaEventID {
+			rangeFirstEventID := max(firstEventID, lcaEventID+1)
+			ranges = append(ranges, EventRange{
+				FirstEventID: rangeFirstEventID,
+				LastEventID:  item.GetEventId(),
+				Version:      item.GetVersion(),
+				Divergent:    rangeFirstEventID <= localLastEventID,
+			})
+		}
+		firstEventID = item.GetEventId() + 1
+	}
+	return ranges, nil
+}
+
+// ReconcileCurrentExecution points the local current execution record of a workflow at the run
+// that is current in the source cluster. If that run doesn't exist locally, e.g. because only an
+// older run was imported, it's imported first. The previously current local run is suppressed, see
+// shard.Engine.ReconcileCurrentExecution. As for ImportHistoryEventsFromBeginning, the namespace must
+// be a global namespace replicated from remoteCluster.
+func (h *localEventsHandlerImpl) ReconcileCurrentExecution(
+	ctx context.Context,
+	remoteCluster string,
+	namespaceID namespace.ID,
+	workflowID string,
+) error {
+	shardContext, err := h.shardController.GetShardByNamespaceWorkflow(namespaceID, workflowID)
+	if err != nil {
+		return err
+	}
+	if err := h.checkNamespaceReplication(shardContext, namespaceID, remoteCluster); err != nil {
+		return err
+	}
+	engine, err := shardContext.GetEngine(ctx)
+	if err != nil {
+		return err
+	}
+	// an empty run ID describes the current run
+	resp, err := h.describeRemoteMutableState(ctx, shardContext, remoteCluster, definition.NewWorkflowKey(namespaceID.String(), workflowID, ""))
+	if err != nil {
+		return err
+	}
+	sourceMutableState := resp.GetDatabaseMutableState()
+	workflowKey := definition.NewWorkflowKey(namespaceID.String(), workflowID, sourceMutableState.GetExecutionState().GetRunId())
+
+	_, err = engine.GetMutableState(ctx, &historyservice.GetMutableStateRequest{
+		NamespaceId: workflowKey.NamespaceID,
+		Execution: &common.WorkflowExecution{
+			WorkflowId: workflowKey.WorkflowID,
+			RunId:      workflowKey.RunID,
+		},
+	})
+	switch err.(type) {
+	case nil:
+	case *serviceerror.NotFound:
+		sourceVersionHistory, err := versionhistory.GetCurrentVersionHistory(sourceMutableState.GetExecutionInfo().GetVersionHistories())
+		if err != nil {
+			return err
+		}
+		// the namespace is already checked
+		if err := h.importHistoryEventsFromBeginning(ctx, remoteCluster, false, true, workflowKey, sourceVersionHistory.GetItems(), nil, nil, nil); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	return engine.ReconcileCurrentExecution(ctx, workflowKey)
+}
+
+func (h *localEventsHandlerImpl) getRemoteVersionHistory(
+	ctx context.Context,
+	shardContext shard.Context,
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+) (*historyspb.VersionHistory, error) {
+	resp, err := h.describeRemoteMutableState(ctx, shardContext, remoteCluster, workflowKey)
+	if err != nil {
+		return nil, err
+	}
+	return versionhistory.GetCurrentVersionHistory(
+		resp.GetDatabaseMutableState().GetExecutionInfo().GetVersionHistories(),
+	)
+}
+
+func (h *localEventsHandlerImpl) describeRemoteMutableState(
+	ctx context.Context,
+	shardContext shard.Context,
+	remoteCluster string,
+	workflowKey definition.WorkflowKey,
+) (*adminservice.DescribeMutableStateResponse, error) {
+	namespaceEntry, err := shardContext.GetNamespaceRegistry().GetNamespaceByID(namespace.ID(workflowKey.NamespaceID))
+	if err != nil {
+		return nil, err
+	}
+	adminClient, err := shardContext.GetRemoteAdminClient(remoteCluster)
+	if err != nil {
+		return nil, err
+	}
+	return adminClient.DescribeMutableState(ctx, &adminservice.DescribeMutableStateRequest{
+		Namespace: namespaceEntry.Name().String(),
+		Execution: &common.WorkflowExecution{
+			WorkflowId: workflowKey.WorkflowID,
+			RunId:      workflowKey.RunID,
+		},
+	})
+}
+
 func (h *localEventsHandlerImpl) importEvents(
 	ctx context.Context,
 	remoteCluster string,
+	streamed bool,
 	engine shard.Engine,
 	workflowKey definition.WorkflowKey,
 	startEventId int64,
@@ -190,8 +614,15 @@ func (h *localEventsHandlerImpl) importEvents(
 	endEventId int64,
 	endEventVersion int64,
 	token []byte,
+	verifier *historyImportVerifier,
+	onImportCall func(time.Duration),
+	onProgress func(ImportEventsProgress),
 ) error {
-	historyIterator := h.historyPaginatedFetcher.GetSingleWorkflowHistoryPaginatedIterator(
+	getHistoryIterator := h.historyPaginatedFetcher.GetSingleWorkflowHistoryPaginatedIterator
+	if streamed {
+		getHistoryIterator = h.historyPaginatedFetcher.GetSingleWorkflowHistoryStreamIterator
+	}
+	historyIterator := getHistoryIterator(
 		ctx,
 		remoteCluster,
 		namespace.ID(workflowKey.NamespaceID),
@@ -202,11 +633,58 @@ func (h *localEventsHandlerImpl) importEvents(
 		endEventId,
 		endEventVersion,
 	)
+	return h.importHistoryBatches(ctx, engine, workflowKey, historyIterator, token, verifier, onImportCall, onProgress)
+}
+
+// closeHistoryIterator closes historyIterator if it holds resources that must be released, see
+// GetSingleWorkflowHistoryStreamIterator.
+func closeHistoryIterator(historyIterator collection.Iterator[HistoryBatch]) {
+	if closer, ok := historyIterator.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// importHistoryBatches imports the batches of historyIterator in as few import calls as possible
+// and commits the import transaction. If set, onProgress is called with the accumulated progress
+// after each import call that applied events.
+func (h *localEventsHandlerImpl) importHistoryBatches(
+	ctx context.Context,
+	engine shard.Engine,
+	workflowKey definition.WorkflowKey,
+	historyIterator collection.Iterator[HistoryBatch],
+	token []byte,
+	verifier *historyImportVerifier,
+	onImportCall func(time.Duration),
+	onProgress func(ImportEventsProgress),
+) error {
+	// end the fetch from the remote cluster, e.g. an open stream, if the import stops early
+	defer closeHistoryIterator(historyIterator)
 
 	blobs := []*common.DataBlob{}
 	blobSize := 0
 	var versionHistory *historyspb.VersionHistory
+	var progress, pending ImportEventsProgress
+	importBlobs := func() error {
+		response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, blobs, versionHistory, token, onImportCall)
+		if err != nil {
+			return err
+		}
+		blobs = []*common.DataBlob{}
+		blobSize = 0
+		token = response.Token
+		progress.EventsApplied += pending.EventsApplied
+		progress.CurrentEventID = pending.CurrentEventID
+		progress.BytesTransferred += pending.BytesTransferred
+		pending = ImportEventsProgress{}
+		if onProgress != nil {
+			onProgress(progress)
+		}
+		return nil
+	}
 	for historyIterator.HasNext() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		batch, err := historyIterator.Next()
 		if err != nil {
 			h.logger.Error("failed to get history events",
@@ -222,36 +700,57 @@ func (h *localEventsHandlerImpl) importEvents(
 		}
 		versionHistory = batch.VersionHistory
 
-		blobSize++
-		blobs = append(blobs, batch.RawEventBatch)
 		events, err := h.eventSerializer.DeserializeEvents(batch.RawEventBatch)
 		if err != nil {
 			return err
 		}
+		if len(events) == 0 {
+			return serviceerror.NewInternal("History import got empty history event batch")
+		}
+		if verifier != nil {
+			if err := verifier.verifyBatch(batch.RawEventBatch, events[0].GetEventId(), len(events)); err != nil {
+				h.logger.Error("failed to verify history events",
+					tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+					tag.WorkflowID(workflowKey.WorkflowID),
+					tag.WorkflowRunID(workflowKey.RunID),
+					tag.Error(err))
+				return err
+			}
+		}
+		blobSize++
+		blobs = append(blobs, batch.RawEventBatch)
+		pending.EventsApplied += int64(len(events))
+		pending.CurrentEventID = events[len(events)-1].GetEventId()
+		pending.BytesTransferred += int64(len(batch.RawEventBatch.GetData()))
 
 		if blobSize >= historyImportBlobSize ||
 			len(blobs) >= historyImportPageSize ||
 			h.isLastEventAtHistoryBoundary(events[len(events)-1], versionHistory) { // Import API only take events that has same version
-			response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, blobs, versionHistory, token)
-			if err != nil {
+			if err := importBlobs(); err != nil {
 				return err
 			}
-			blobs = []*common.DataBlob{}
-			blobSize = 0
-			token = response.Token
 		}
 	}
+	if verifier != nil {
+		if err := verifier.verifyComplete(); err != nil {
+			h.logger.Error("failed to verify history events",
+				tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+				tag.WorkflowID(workflowKey.WorkflowID),
+				tag.WorkflowRunID(workflowKey.RunID),
+				tag.Error(err))
+			return err
+		}
+	}
+
 	if len(blobs) != 0 {
-		response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, blobs, versionHistory, token)
-		if err != nil {
+		if err := importBlobs(); err != nil {
 			return err
 		}
-		token = response.Token
 	}
 
 	// call with empty event blob to commit the import
 	blobs = []*common.DataBlob{}
-	response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, blobs, versionHistory, token)
+	response, err := h.invokeImportWorkflowExecutionCall(ctx, engine, workflowKey, blobs, versionHistory, token, onImportCall)
 	if err != nil || len(response.Token) != 0 {
 		h.logger.Error("failed to commit import action",
 			tag.WorkflowNamespaceID(workflowKey.NamespaceID),
@@ -282,6 +781,7 @@ func (h *localEventsHandlerImpl) invokeImportWorkflowExecutionCall(
 	historyBatches []*common.DataBlob,
 	versionHistory *historyspb.VersionHistory,
 	token []byte,
+	onImportCall func(time.Duration),
 ) (*historyservice.ImportWorkflowExecutionResponse, error) {
 	request := &historyservice.ImportWorkflowExecutionRequest{
 		NamespaceId: workflowKey.NamespaceID,
@@ -293,7 +793,11 @@ func (h *localEventsHandlerImpl) invokeImportWorkflowExecutionCall(
 		VersionHistory: versionHistory,
 		Token:          token,
 	}
+	start := time.Now()
 	response, err := historyEngine.ImportWorkflowExecution(ctx, request)
+	if onImportCall != nil {
+		onImportCall(time.Since(start))
+	}
 	if err != nil {
 		h.logger.Error("failed to import events",
 			tag.WorkflowNamespaceID(workflowKey.NamespaceID),
@@ -33,9 +33,10 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-	history "go.temporal.io/api/history/v1"
-	history0 "go.temporal.io/server/api/history/v1"
+	v1 "go.temporal.io/api/history/v1"
+	v10 "go.temporal.io/server/api/history/v1"
 	definition "go.temporal.io/server/common/definition"
+	namespace "go.temporal.io/server/common/namespace"
 )
 
 // MockLocalGeneratedEventsHandler is a mock of LocalGeneratedEventsHandler interface.
@@ -61,8 +62,23 @@ func (m *MockLocalGeneratedEventsHandler) EXPECT() *MockLocalGeneratedEventsHand
 	return m.recorder
 }
 
+// DiffHistory mocks base method.
+func (m *MockLocalGeneratedEventsHandler) DiffHistory(ctx context.Context, remoteCluster string, workflowKey definition.WorkflowKey) ([]EventRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffHistory", ctx, remoteCluster, workflowKey)
+	ret0, _ := ret[0].([]EventRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffHistory indicates an expected call of DiffHistory.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) DiffHistory(ctx, remoteCluster, workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffHistory", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).DiffHistory), ctx, remoteCluster, workflowKey)
+}
+
 // HandleLocalGeneratedHistoryEvents mocks base method.
-func (m *MockLocalGeneratedEventsHandler) HandleLocalGeneratedHistoryEvents(ctx context.Context, remoteCluster string, workflowKey definition.WorkflowKey, versionHistoryItems []*history0.VersionHistoryItem, localEvents [][]*history.HistoryEvent) error {
+func (m *MockLocalGeneratedEventsHandler) HandleLocalGeneratedHistoryEvents(ctx context.Context, remoteCluster string, workflowKey definition.WorkflowKey, versionHistoryItems []*v10.VersionHistoryItem, localEvents [][]*v1.HistoryEvent) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HandleLocalGeneratedHistoryEvents", ctx, remoteCluster, workflowKey, versionHistoryItems, localEvents)
 	ret0, _ := ret[0].(error)
@@ -74,3 +90,117 @@ func (mr *MockLocalGeneratedEventsHandlerMockRecorder) HandleLocalGeneratedHisto
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleLocalGeneratedHistoryEvents", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).HandleLocalGeneratedHistoryEvents), ctx, remoteCluster, workflowKey, versionHistoryItems, localEvents)
 }
+
+// ImportHistoryEventsFromArchive mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ImportHistoryEventsFromArchive(ctx context.Context, workflowKey definition.WorkflowKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportHistoryEventsFromArchive", ctx, workflowKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportHistoryEventsFromArchive indicates an expected call of ImportHistoryEventsFromArchive.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ImportHistoryEventsFromArchive(ctx, workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportHistoryEventsFromArchive", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ImportHistoryEventsFromArchive), ctx, workflowKey)
+}
+
+// ImportHistoryEventsFromBeginning mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ImportHistoryEventsFromBeginning(ctx context.Context, remoteCluster string, workflowKey definition.WorkflowKey, versionHistoryItems []*v10.VersionHistoryItem, manifest *HistoryImportManifest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportHistoryEventsFromBeginning", ctx, remoteCluster, workflowKey, versionHistoryItems, manifest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportHistoryEventsFromBeginning indicates an expected call of ImportHistoryEventsFromBeginning.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ImportHistoryEventsFromBeginning(ctx, remoteCluster, workflowKey, versionHistoryItems, manifest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportHistoryEventsFromBeginning", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ImportHistoryEventsFromBeginning), ctx, remoteCluster, workflowKey, versionHistoryItems, manifest)
+}
+
+// ImportHistoryEventsFromSources mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ImportHistoryEventsFromSources(ctx context.Context, sourceClusters []string, policy SourceSelectionPolicy, workflowKey definition.WorkflowKey, versionHistoryItems []*v10.VersionHistoryItem, manifest *HistoryImportManifest) ([]ImportSourceRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportHistoryEventsFromSources", ctx, sourceClusters, policy, workflowKey, versionHistoryItems, manifest)
+	ret0, _ := ret[0].([]ImportSourceRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportHistoryEventsFromSources indicates an expected call of ImportHistoryEventsFromSources.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ImportHistoryEventsFromSources(ctx, sourceClusters, policy, workflowKey, versionHistoryItems, manifest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportHistoryEventsFromSources", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ImportHistoryEventsFromSources), ctx, sourceClusters, policy, workflowKey, versionHistoryItems, manifest)
+}
+
+// ImportStreamedHistoryEventsFromBeginning mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ImportStreamedHistoryEventsFromBeginning(ctx context.Context, remoteCluster string, workflowKey definition.WorkflowKey, versionHistoryItems []*v10.VersionHistoryItem, manifest *HistoryImportManifest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStreamedHistoryEventsFromBeginning", ctx, remoteCluster, workflowKey, versionHistoryItems, manifest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportStreamedHistoryEventsFromBeginning indicates an expected call of ImportStreamedHistoryEventsFromBeginning.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ImportStreamedHistoryEventsFromBeginning(ctx, remoteCluster, workflowKey, versionHistoryItems, manifest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStreamedHistoryEventsFromBeginning", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ImportStreamedHistoryEventsFromBeginning), ctx, remoteCluster, workflowKey, versionHistoryItems, manifest)
+}
+
+// ImportWorkflows mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ImportWorkflows(ctx context.Context, remoteCluster string, workflowKeys []definition.WorkflowKey, opts ImportWorkflowsOptions) (ImportSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportWorkflows", ctx, remoteCluster, workflowKeys, opts)
+	ret0, _ := ret[0].(ImportSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportWorkflows indicates an expected call of ImportWorkflows.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ImportWorkflows(ctx, remoteCluster, workflowKeys, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportWorkflows", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ImportWorkflows), ctx, remoteCluster, workflowKeys, opts)
+}
+
+// ReconcileCurrentExecution mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ReconcileCurrentExecution(ctx context.Context, remoteCluster string, namespaceID namespace.ID, workflowID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileCurrentExecution", ctx, remoteCluster, namespaceID, workflowID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileCurrentExecution indicates an expected call of ReconcileCurrentExecution.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ReconcileCurrentExecution(ctx, remoteCluster, namespaceID, workflowID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileCurrentExecution", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ReconcileCurrentExecution), ctx, remoteCluster, namespaceID, workflowID)
+}
+
+// ReconcileVersionHistory mocks base method.
+func (m *MockLocalGeneratedEventsHandler) ReconcileVersionHistory(ctx context.Context, remoteCluster string, workflowKey definition.WorkflowKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileVersionHistory", ctx, remoteCluster, workflowKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileVersionHistory indicates an expected call of ReconcileVersionHistory.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) ReconcileVersionHistory(ctx, remoteCluster, workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileVersionHistory", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).ReconcileVersionHistory), ctx, remoteCluster, workflowKey)
+}
+
+// StreamImportHistoryEventsFromBeginning mocks base method.
+func (m *MockLocalGeneratedEventsHandler) StreamImportHistoryEventsFromBeginning(remoteCluster string, workflowKey definition.WorkflowKey, versionHistoryItems []*v10.VersionHistoryItem, manifest *HistoryImportManifest, stream ImportProgressStream) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamImportHistoryEventsFromBeginning", remoteCluster, workflowKey, versionHistoryItems, manifest, stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamImportHistoryEventsFromBeginning indicates an expected call of StreamImportHistoryEventsFromBeginning.
+func (mr *MockLocalGeneratedEventsHandlerMockRecorder) StreamImportHistoryEventsFromBeginning(remoteCluster, workflowKey, versionHistoryItems, manifest, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamImportHistoryEventsFromBeginning", reflect.TypeOf((*MockLocalGeneratedEventsHandler)(nil).StreamImportHistoryEventsFromBeginning), remoteCluster, workflowKey, versionHistoryItems, manifest, stream)
+}
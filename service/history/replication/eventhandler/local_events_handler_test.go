@@ -26,6 +26,7 @@ package eventhandler
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -35,14 +36,23 @@ import (
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/server/api/adminservice/v1"
+	"go.temporal.io/server/api/adminservicemock/v1"
 	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/collection"
 	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/primitives"
 	"go.temporal.io/server/service/history/shard"
 )
 
@@ -56,6 +66,7 @@ type (
 		logger               log.Logger
 		eventSerializer      serialization.Serializer
 		remoteHistoryFetcher *MockHistoryPaginatedFetcher
+		archiverProvider     *provider.MockArchiverProvider
 
 		localEventsHandler LocalGeneratedEventsHandler
 	}
@@ -81,6 +92,7 @@ func (s *localEventsHandlerSuite) SetupTest() {
 	s.logger = log.NewNoopLogger()
 	s.eventSerializer = serialization.NewSerializer()
 	s.remoteHistoryFetcher = NewMockHistoryPaginatedFetcher(s.controller)
+	s.archiverProvider = provider.NewMockArchiverProvider(s.controller)
 
 	s.localEventsHandler = NewLocalEventsHandler(
 		s.clusterMetadata,
@@ -88,6 +100,7 @@ func (s *localEventsHandlerSuite) SetupTest() {
 		s.logger,
 		s.eventSerializer,
 		s.remoteHistoryFetcher,
+		s.archiverProvider,
 	)
 }
 
@@ -448,3 +461,1006 @@ func serializeEvents(serializer serialization.Serializer, events [][]*historypb.
 	}
 	return blobs
 }
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromBeginning_ManifestVerified() {
+	workflowKey, engine, versionHistory, blobs, manifest := s.setupImportFromBeginning()
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(),
+		cluster.TestAlternativeClusterName,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+		workflowKey.RunID,
+		int64(1),
+		int64(1),
+		int64(4),
+		int64(1),
+	).Return(newHistoryBatchIterator(versionHistory, blobs...))
+
+	importToken := []byte{1, 0, 1}
+	gomock.InOrder(
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Equal(blobs, request.HistoryBatches)
+				s.Nil(request.Token)
+				return &historyservice.ImportWorkflowExecutionResponse{Token: importToken, EventsApplied: true}, nil
+			},
+		),
+		// commit the import
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Empty(request.HistoryBatches)
+				s.Equal(importToken, request.Token)
+				return &historyservice.ImportWorkflowExecutionResponse{}, nil
+			},
+		),
+	)
+
+	err := s.localEventsHandler.ImportHistoryEventsFromBeginning(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		manifest,
+	)
+	s.NoError(err)
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromBeginning_CorruptedBatch() {
+	workflowKey, _, versionHistory, blobs, manifest := s.setupImportFromBeginning()
+	// the second batch lost its last event
+	corruptedBatch := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		{{EventId: 3, Version: 1}},
+	})[0]
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(newHistoryBatchIterator(versionHistory, blobs[0], corruptedBatch))
+
+	err := s.localEventsHandler.ImportHistoryEventsFromBeginning(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		manifest,
+	)
+	var dataLoss *serviceerror.DataLoss
+	s.ErrorAs(err, &dataLoss)
+	s.Contains(err.Error(), "starting at event 3")
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromBeginning_TruncatedHistory() {
+	workflowKey, _, versionHistory, blobs, manifest := s.setupImportFromBeginning()
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(newHistoryBatchIterator(versionHistory, blobs[0]))
+
+	err := s.localEventsHandler.ImportHistoryEventsFromBeginning(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		manifest,
+	)
+	var dataLoss *serviceerror.DataLoss
+	s.ErrorAs(err, &dataLoss)
+	s.Contains(err.Error(), "starting at event 3")
+}
+
+func (s *localEventsHandlerSuite) TestImportFromBeginning_NamespaceNotReplicated() {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	versionHistoryItems := []*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}}
+	namespaceEntries := map[string]*namespace.Namespace{
+		"LocalNamespace": namespace.NewLocalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "local-namespace"},
+			nil,
+			cluster.TestCurrentClusterName,
+		),
+		"SourceNotInNamespace": namespace.NewGlobalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "global-namespace"},
+			nil,
+			&persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+			1,
+		),
+	}
+	imports := map[string]func() error{
+		"ImportHistoryEventsFromBeginning": func() error {
+			return s.localEventsHandler.ImportHistoryEventsFromBeginning(
+				context.Background(), cluster.TestAlternativeClusterName, workflowKey, versionHistoryItems, nil,
+			)
+		},
+		"ImportStreamedHistoryEventsFromBeginning": func() error {
+			return s.localEventsHandler.ImportStreamedHistoryEventsFromBeginning(
+				context.Background(), cluster.TestAlternativeClusterName, workflowKey, versionHistoryItems, nil,
+			)
+		},
+		"StreamImportHistoryEventsFromBeginning": func() error {
+			return s.localEventsHandler.StreamImportHistoryEventsFromBeginning(
+				cluster.TestAlternativeClusterName, workflowKey, versionHistoryItems, nil, &testImportProgressStream{ctx: context.Background()},
+			)
+		},
+		"ImportHistoryEventsFromSources": func() error {
+			// the namespace must be replicated from every source, not only from the first one
+			_, err := s.localEventsHandler.ImportHistoryEventsFromSources(
+				context.Background(),
+				[]string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+				SourceSelectionFirstAvailable,
+				workflowKey,
+				versionHistoryItems,
+				nil,
+			)
+			return err
+		},
+		"ReconcileCurrentExecution": func() error {
+			return s.localEventsHandler.ReconcileCurrentExecution(
+				context.Background(), cluster.TestAlternativeClusterName, namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID,
+			)
+		},
+	}
+	for namespaceName, namespaceEntry := range namespaceEntries {
+		for importName, importFn := range imports {
+			s.Run(namespaceName+"/"+importName, func() {
+				// nothing is fetched or imported, the shard is only used to look up the namespace
+				shardContext := shard.NewMockContext(s.controller)
+				s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+					namespace.ID(workflowKey.NamespaceID),
+					workflowKey.WorkflowID,
+				).Return(shardContext, nil)
+				s.expectNamespace(shardContext, namespaceEntry)
+
+				var failedPrecondition *serviceerror.FailedPrecondition
+				s.ErrorAs(importFn(), &failedPrecondition)
+			})
+		}
+	}
+}
+
+func (s *localEventsHandlerSuite) TestStreamImportHistoryEventsFromBeginning_Progress() {
+	workflowKey, engine, versionHistory, blobs := s.setupStreamImport()
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(newHistoryBatchIterator(versionHistory, blobs...))
+	// one import call per version, plus the commit
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil).Times(3)
+
+	stream := &testImportProgressStream{ctx: context.Background()}
+	err := s.localEventsHandler.StreamImportHistoryEventsFromBeginning(
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+		stream,
+	)
+	s.NoError(err)
+
+	firstBatchSize := int64(len(blobs[0].Data))
+	totalSize := firstBatchSize + int64(len(blobs[1].Data))
+	s.Equal([]*ImportEventsProgress{
+		{EventsApplied: 2, CurrentEventID: 2, BytesTransferred: firstBatchSize},
+		{EventsApplied: 4, CurrentEventID: 4, BytesTransferred: totalSize},
+		{EventsApplied: 4, CurrentEventID: 4, BytesTransferred: totalSize, Done: true},
+	}, stream.sent)
+}
+
+func (s *localEventsHandlerSuite) TestStreamImportHistoryEventsFromBeginning_RemoteVersionHistory() {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	s.clusterMetadata.EXPECT().GetClusterID().Return(int64(1))
+	s.clusterMetadata.EXPECT().GetFailoverVersionIncrement().Return(int64(1000))
+	shardContext := shard.NewMockContext(s.controller)
+	engine := shard.NewMockEngine(s.controller)
+	adminClient := adminservicemock.NewMockAdminServiceClient(s.controller)
+	s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	).Return(shardContext, nil).Times(2)
+	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil)
+	s.expectReplicatedNamespace(shardContext, workflowKey)
+	shardContext.EXPECT().GetRemoteAdminClient(cluster.TestAlternativeClusterName).Return(adminClient, nil)
+	versionHistoryItems := []*historyspb.VersionHistoryItem{{EventId: 2, Version: 1}, {EventId: 4, Version: 1001}}
+	adminClient.EXPECT().DescribeMutableState(gomock.Any(), gomock.Any()).Return(&adminservice.DescribeMutableStateResponse{
+		DatabaseMutableState: &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				VersionHistories: versionhistory.NewVersionHistories(
+					versionhistory.NewVersionHistory(nil, versionHistoryItems),
+				),
+			},
+		},
+	}, nil)
+	versionHistory := &historyspb.VersionHistory{Items: versionHistoryItems}
+	blobs := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}},
+		{{EventId: 3, Version: 1001}, {EventId: 4, Version: 1001}},
+	})
+	// the whole remote history is local generated, so all of it is imported
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(),
+		cluster.TestAlternativeClusterName,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+		workflowKey.RunID,
+		int64(1),
+		int64(1),
+		int64(4),
+		int64(1001),
+	).Return(newHistoryBatchIterator(versionHistory, blobs...))
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil).Times(3)
+
+	stream := &testImportProgressStream{ctx: context.Background()}
+	err := s.localEventsHandler.StreamImportHistoryEventsFromBeginning(
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		nil,
+		nil,
+		stream,
+	)
+	s.NoError(err)
+	s.Len(stream.sent, 3)
+	s.True(stream.sent[2].Done)
+	s.Equal(int64(4), stream.sent[2].CurrentEventID)
+}
+
+func (s *localEventsHandlerSuite) TestStreamImportHistoryEventsFromBeginning_StreamClosed() {
+	workflowKey, engine, versionHistory, blobs := s.setupStreamImport()
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(newHistoryBatchIterator(versionHistory, blobs...))
+	// only the first version is imported, the import is neither continued nor committed
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil).Times(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &testImportProgressStream{ctx: ctx}
+	stream.onSend = func(*ImportEventsProgress) error {
+		// the client closes the stream after the first progress message
+		cancel()
+		return nil
+	}
+	err := s.localEventsHandler.StreamImportHistoryEventsFromBeginning(
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+		stream,
+	)
+	s.ErrorIs(err, context.Canceled)
+	s.Len(stream.sent, 1)
+}
+
+func (s *localEventsHandlerSuite) TestStreamImportHistoryEventsFromBeginning_SendFailed() {
+	workflowKey, engine, versionHistory, blobs := s.setupStreamImport()
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(newHistoryBatchIterator(versionHistory, blobs...))
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil).Times(1)
+
+	sendErr := serviceerror.NewUnavailable("stream broken")
+	stream := &testImportProgressStream{ctx: context.Background()}
+	stream.onSend = func(*ImportEventsProgress) error {
+		return sendErr
+	}
+	err := s.localEventsHandler.StreamImportHistoryEventsFromBeginning(
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+		stream,
+	)
+	s.Equal(sendErr, err)
+}
+
+func (s *localEventsHandlerSuite) TestImportStreamedHistoryEventsFromBeginning_ImportFailure() {
+	workflowKey, engine, _, _, _ := s.setupImportFromBeginning()
+	const numBatches = 5 * historyImportBlobSize
+	versionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 2 * numBatches, Version: 1}},
+	}
+	produced := 0
+	iterator := &closableHistoryBatchIterator{
+		Iterator: collection.NewPagingIterator(func(paginationToken []byte) ([]HistoryBatch, []byte, error) {
+			produced++
+			firstEventID := int64(2*produced - 1)
+			blob := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+				{{EventId: firstEventID, Version: 1}, {EventId: firstEventID + 1, Version: 1}},
+			})[0]
+			return []HistoryBatch{{VersionHistory: versionHistory, RawEventBatch: blob}}, []byte{1}, nil
+		}),
+	}
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryStreamIterator(
+		gomock.Any(), cluster.TestAlternativeClusterName, gomock.Any(), gomock.Any(), gomock.Any(),
+		int64(1), int64(1), int64(2*numBatches), int64(1),
+	).Return(iterator)
+	importErr := serviceerror.NewInternal("import failed")
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, importErr)
+
+	err := s.localEventsHandler.ImportStreamedHistoryEventsFromBeginning(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+	)
+	s.ErrorIs(err, importErr)
+	// the stream is closed without receiving the rest of the history
+	s.True(iterator.closed)
+	s.Less(produced, numBatches)
+}
+
+func (s *localEventsHandlerSuite) TestImportStreamedHistoryEventsFromBeginning_LargeHistory() {
+	workflowKey, engine, _, _, _ := s.setupImportFromBeginning()
+	const numBatches = 5000
+	versionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 2 * numBatches, Version: 1}},
+	}
+	// the batches are only produced as the import consumes them, as they would be received from
+	// the stream
+	produced := 0
+	iterator := collection.NewPagingIterator(func(paginationToken []byte) ([]HistoryBatch, []byte, error) {
+		produced++
+		firstEventID := int64(2*produced - 1)
+		blob := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+			{{EventId: firstEventID, Version: 1}, {EventId: firstEventID + 1, Version: 1}},
+		})[0]
+		if produced == numBatches {
+			return []HistoryBatch{{VersionHistory: versionHistory, RawEventBatch: blob}}, nil, nil
+		}
+		return []HistoryBatch{{VersionHistory: versionHistory, RawEventBatch: blob}}, []byte{1}, nil
+	})
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryStreamIterator(
+		gomock.Any(),
+		cluster.TestAlternativeClusterName,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+		workflowKey.RunID,
+		int64(1),
+		int64(1),
+		int64(2*numBatches),
+		int64(1),
+	).Return(iterator)
+
+	applied := 0
+	var lastToken []byte
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+			// the import doesn't run ahead of the stream by more than one import call
+			s.LessOrEqual(len(request.HistoryBatches), historyImportBlobSize)
+			applied += len(request.HistoryBatches)
+			s.LessOrEqual(produced-applied, 1)
+			// each call continues the import of the previous one
+			s.Equal(lastToken, request.Token)
+			if len(request.HistoryBatches) == 0 {
+				return &historyservice.ImportWorkflowExecutionResponse{}, nil
+			}
+			lastToken = []byte(fmt.Sprintf("token-%d", applied))
+			return &historyservice.ImportWorkflowExecutionResponse{Token: lastToken, EventsApplied: true}, nil
+		},
+	).Times(numBatches/historyImportBlobSize + 2)
+
+	err := s.localEventsHandler.ImportStreamedHistoryEventsFromBeginning(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+	)
+	s.NoError(err)
+	s.Equal(numBatches, produced)
+	s.Equal(numBatches, applied)
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromSources_SourceUnavailableMidImport() {
+	workflowKey, engine, versionHistory, blobs, manifest := s.setupImportFromBeginning()
+	// the first source becomes unavailable after the first page
+	gomock.InOrder(
+		s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+			gomock.Any(), "source-1", namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID, workflowKey.RunID,
+			int64(1), int64(1), int64(4), int64(1),
+		).Return(newFailingHistoryBatchIterator(versionHistory, serviceerror.NewUnavailable("source unavailable"), blobs[0])),
+		s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+			gomock.Any(), "source-2", namespace.ID(workflowKey.NamespaceID), workflowKey.WorkflowID, workflowKey.RunID,
+			int64(3), int64(1), int64(4), int64(1),
+		).Return(newHistoryBatchIterator(versionHistory, blobs[1])),
+	)
+	gomock.InOrder(
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Equal(blobs, request.HistoryBatches)
+				return &historyservice.ImportWorkflowExecutionResponse{Token: []byte{1}, EventsApplied: true}, nil
+			},
+		),
+		// commit the import
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil),
+	)
+
+	ranges, err := s.localEventsHandler.ImportHistoryEventsFromSources(
+		context.Background(),
+		[]string{"source-1", "source-2"},
+		SourceSelectionFirstAvailable,
+		workflowKey,
+		versionHistory.Items,
+		manifest,
+	)
+	s.NoError(err)
+	s.Equal([]ImportSourceRange{
+		{Cluster: "source-1", FirstEventID: 1, LastEventID: 2},
+		{Cluster: "source-2", FirstEventID: 3, LastEventID: 4},
+	}, ranges)
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromSources_SourceMissingSuffix() {
+	workflowKey, engine, versionHistory, blobs, _ := s.setupImportFromBeginning()
+	// the first source only has the prefix of the history
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), "source-1", gomock.Any(), gomock.Any(), gomock.Any(), int64(1), int64(1), int64(4), int64(1),
+	).Return(newHistoryBatchIterator(versionHistory, blobs[0]))
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), "source-2", gomock.Any(), gomock.Any(), gomock.Any(), int64(3), int64(1), int64(4), int64(1),
+	).Return(newHistoryBatchIterator(versionHistory, blobs[1]))
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil).Times(2)
+
+	ranges, err := s.localEventsHandler.ImportHistoryEventsFromSources(
+		context.Background(),
+		[]string{"source-1", "source-2"},
+		SourceSelectionFirstAvailable,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+	)
+	s.NoError(err)
+	s.Equal([]ImportSourceRange{
+		{Cluster: "source-1", FirstEventID: 1, LastEventID: 2},
+		{Cluster: "source-2", FirstEventID: 3, LastEventID: 4},
+	}, ranges)
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromSources_LowestLatency() {
+	workflowKey, shardContext, engine, versionHistory, blobs := s.setupImportFromSources()
+	// the first source fails the probe, so the second one is tried first
+	unavailableClient := adminservicemock.NewMockAdminServiceClient(s.controller)
+	unavailableClient.EXPECT().DescribeMutableState(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewUnavailable("source unavailable"))
+	availableClient := adminservicemock.NewMockAdminServiceClient(s.controller)
+	availableClient.EXPECT().DescribeMutableState(gomock.Any(), gomock.Any()).Return(&adminservice.DescribeMutableStateResponse{}, nil)
+	shardContext.EXPECT().GetRemoteAdminClient("source-1").Return(unavailableClient, nil)
+	shardContext.EXPECT().GetRemoteAdminClient("source-2").Return(availableClient, nil)
+
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), "source-2", gomock.Any(), gomock.Any(), gomock.Any(), int64(1), int64(1), int64(4), int64(1),
+	).Return(newHistoryBatchIterator(versionHistory, blobs...))
+	engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).Return(&historyservice.ImportWorkflowExecutionResponse{}, nil).Times(2)
+
+	ranges, err := s.localEventsHandler.ImportHistoryEventsFromSources(
+		context.Background(),
+		[]string{"source-1", "source-2"},
+		SourceSelectionLowestLatency,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+	)
+	s.NoError(err)
+	s.Equal([]ImportSourceRange{{Cluster: "source-2", FirstEventID: 1, LastEventID: 4}}, ranges)
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromSources_AllSourcesUnavailable() {
+	workflowKey, _, versionHistory, blobs, _ := s.setupImportFromBeginning()
+	sourceErr := serviceerror.NewUnavailable("source unavailable")
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), "source-1", gomock.Any(), gomock.Any(), gomock.Any(), int64(1), int64(1), int64(4), int64(1),
+	).Return(newFailingHistoryBatchIterator(versionHistory, sourceErr, blobs[0]))
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(), "source-2", gomock.Any(), gomock.Any(), gomock.Any(), int64(3), int64(1), int64(4), int64(1),
+	).Return(newFailingHistoryBatchIterator(versionHistory, sourceErr))
+
+	// nothing is imported, as the batches are only imported at the version boundary
+	ranges, err := s.localEventsHandler.ImportHistoryEventsFromSources(
+		context.Background(),
+		[]string{"source-1", "source-2"},
+		SourceSelectionFirstAvailable,
+		workflowKey,
+		versionHistory.Items,
+		nil,
+	)
+	var unavailable *serviceerror.Unavailable
+	s.ErrorAs(err, &unavailable)
+	s.Contains(err.Error(), "from event 3")
+	s.Equal([]ImportSourceRange{{Cluster: "source-1", FirstEventID: 1, LastEventID: 2}}, ranges)
+}
+
+// setupImportFromSources is setupImportFromBeginning, but also returns the shard context of the
+// workflow.
+func (s *localEventsHandlerSuite) setupImportFromSources() (
+	definition.WorkflowKey,
+	*shard.MockContext,
+	*shard.MockEngine,
+	*historyspb.VersionHistory,
+	[]*commonpb.DataBlob,
+) {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	s.clusterMetadata.EXPECT().GetClusterID().Return(int64(1))
+	s.clusterMetadata.EXPECT().GetFailoverVersionIncrement().Return(int64(1000))
+	shardContext := shard.NewMockContext(s.controller)
+	engine := shard.NewMockEngine(s.controller)
+	s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	).Return(shardContext, nil)
+	s.expectReplicatedNamespace(shardContext, workflowKey)
+	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil)
+
+	versionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+	}
+	blobs := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}},
+		{{EventId: 3, Version: 1}, {EventId: 4, Version: 1}},
+	})
+	return workflowKey, shardContext, engine, versionHistory, blobs
+}
+
+// setupStreamImport sets up the import of a workflow with two versions, so that its events are
+// imported with two import calls.
+func (s *localEventsHandlerSuite) setupStreamImport() (
+	definition.WorkflowKey,
+	*shard.MockEngine,
+	*historyspb.VersionHistory,
+	[]*commonpb.DataBlob,
+) {
+	workflowKey, engine, _, _, _ := s.setupImportFromBeginning()
+	versionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 2, Version: 1}, {EventId: 4, Version: 1001}},
+	}
+	blobs := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}},
+		{{EventId: 3, Version: 1001}, {EventId: 4, Version: 1001}},
+	})
+	return workflowKey, engine, versionHistory, blobs
+}
+
+func (s *localEventsHandlerSuite) setupImportFromBeginning() (
+	definition.WorkflowKey,
+	*shard.MockEngine,
+	*historyspb.VersionHistory,
+	[]*commonpb.DataBlob,
+	*HistoryImportManifest,
+) {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	s.clusterMetadata.EXPECT().GetClusterID().Return(int64(1))
+	s.clusterMetadata.EXPECT().GetFailoverVersionIncrement().Return(int64(1000))
+	shardContext := shard.NewMockContext(s.controller)
+	engine := shard.NewMockEngine(s.controller)
+	s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	).Return(shardContext, nil)
+	s.expectReplicatedNamespace(shardContext, workflowKey)
+	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil)
+
+	versionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+	}
+	blobs := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}},
+		{{EventId: 3, Version: 1}, {EventId: 4, Version: 1}},
+	})
+	manifest, err := NewHistoryImportManifest(s.eventSerializer, blobs)
+	s.NoError(err)
+	return workflowKey, engine, versionHistory, blobs, manifest
+}
+
+// expectReplicatedNamespace makes the namespace of workflowKey a global namespace replicated from
+// all of the clusters the tests import from.
+func (s *localEventsHandlerSuite) expectReplicatedNamespace(
+	shardContext *shard.MockContext,
+	workflowKey definition.WorkflowKey,
+) {
+	s.expectNamespace(shardContext, namespace.NewGlobalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "test-namespace"},
+		nil,
+		&persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters:          []string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName, "source-1", "source-2"},
+		},
+		1,
+	))
+}
+
+func (s *localEventsHandlerSuite) expectNamespace(
+	shardContext *shard.MockContext,
+	namespaceEntry *namespace.Namespace,
+) {
+	namespaceRegistry := namespace.NewMockRegistry(s.controller)
+	namespaceRegistry.EXPECT().GetNamespaceByID(namespaceEntry.ID()).Return(namespaceEntry, nil).AnyTimes()
+	shardContext.EXPECT().GetNamespaceRegistry().Return(namespaceRegistry).AnyTimes()
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromArchive() {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	engine := s.setupImportFromArchive(workflowKey, "test:///archive")
+	historyArchiver := archiver.NewMockHistoryArchiver(s.controller)
+	s.archiverProvider.EXPECT().GetHistoryArchiver("test", string(primitives.HistoryService)).Return(historyArchiver, nil)
+
+	// the archive is read twice, once for the version history and once for the events
+	archivedPages := []*archiver.GetHistoryResponse{
+		{
+			HistoryBatches: []*historypb.History{
+				{Events: []*historypb.HistoryEvent{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}}},
+			},
+			NextPageToken: []byte{1},
+		},
+		{
+			HistoryBatches: []*historypb.History{
+				{Events: []*historypb.HistoryEvent{{EventId: 3, Version: 2}, {EventId: 4, Version: 2}}},
+			},
+		},
+	}
+	historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ archiver.URI, request *archiver.GetHistoryRequest) (*archiver.GetHistoryResponse, error) {
+			s.Equal(workflowKey.RunID, request.RunID)
+			if len(request.NextPageToken) == 0 {
+				return archivedPages[0], nil
+			}
+			return archivedPages[1], nil
+		},
+	).Times(4)
+
+	expectedVersionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 2, Version: 1}, {EventId: 4, Version: 2}},
+	}
+	blobs := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		archivedPages[0].HistoryBatches[0].Events,
+		archivedPages[1].HistoryBatches[0].Events,
+	})
+	importToken := []byte{1, 0, 1}
+	gomock.InOrder(
+		// each version is imported with its own call
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Equal(blobs[:1], request.HistoryBatches)
+				s.Equal(expectedVersionHistory.Items, request.VersionHistory.Items)
+				return &historyservice.ImportWorkflowExecutionResponse{Token: importToken, EventsApplied: true}, nil
+			},
+		),
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Equal(blobs[1:], request.HistoryBatches)
+				s.Equal(importToken, request.Token)
+				return &historyservice.ImportWorkflowExecutionResponse{Token: importToken, EventsApplied: true}, nil
+			},
+		),
+		// commit the import
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Empty(request.HistoryBatches)
+				s.Equal(importToken, request.Token)
+				return &historyservice.ImportWorkflowExecutionResponse{}, nil
+			},
+		),
+	)
+
+	err := s.localEventsHandler.ImportHistoryEventsFromArchive(context.Background(), workflowKey)
+	s.NoError(err)
+}
+
+func (s *localEventsHandlerSuite) TestImportHistoryEventsFromArchive_NotArchived() {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	s.setupImportFromArchive(workflowKey, "")
+
+	err := s.localEventsHandler.ImportHistoryEventsFromArchive(context.Background(), workflowKey)
+	var failedPrecondition *serviceerror.FailedPrecondition
+	s.ErrorAs(err, &failedPrecondition)
+}
+
+func (s *localEventsHandlerSuite) setupImportFromArchive(
+	workflowKey definition.WorkflowKey,
+	archivalURI string,
+) *shard.MockEngine {
+	shardContext := shard.NewMockContext(s.controller)
+	engine := shard.NewMockEngine(s.controller)
+	s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	).Return(shardContext, nil)
+	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil)
+
+	archivalMetadata := archiver.NewMockArchivalMetadata(s.controller)
+	archivalMetadata.EXPECT().GetHistoryConfig().Return(archiver.NewArchivalConfig(
+		"enabled",
+		dynamicconfig.GetStringPropertyFn("enabled"),
+		dynamicconfig.GetBoolPropertyFn(true),
+		"enabled",
+		"test:///archive",
+	))
+	shardContext.EXPECT().GetArchivalMetadata().Return(archivalMetadata)
+
+	registry := namespace.NewMockRegistry(s.controller)
+	registry.EXPECT().GetNamespaceByID(namespace.ID(workflowKey.NamespaceID)).Return(namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "test-namespace"},
+		&persistencespb.NamespaceConfig{
+			HistoryArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+			HistoryArchivalUri:   archivalURI,
+		},
+		cluster.TestCurrentClusterName,
+	), nil)
+	shardContext.EXPECT().GetNamespaceRegistry().Return(registry)
+	return engine
+}
+
+func (s *localEventsHandlerSuite) TestDiffHistory_NoDiff() {
+	workflowKey, engine := s.setupDiffHistory(
+		[]*historyspb.VersionHistoryItem{{EventId: 10, Version: 1}, {EventId: 15, Version: 2}},
+	)
+	engine.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(newGetMutableStateResponse(
+		[]*historyspb.VersionHistoryItem{{EventId: 10, Version: 1}, {EventId: 15, Version: 2}},
+	), nil)
+
+	ranges, err := s.localEventsHandler.DiffHistory(context.Background(), cluster.TestAlternativeClusterName, workflowKey)
+	s.NoError(err)
+	s.Empty(ranges)
+}
+
+func (s *localEventsHandlerSuite) TestDiffHistory_PrefixMissing() {
+	workflowKey, engine := s.setupDiffHistory(
+		[]*historyspb.VersionHistoryItem{{EventId: 10, Version: 1}, {EventId: 15, Version: 2}},
+	)
+	engine.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(newGetMutableStateResponse(
+		[]*historyspb.VersionHistoryItem{{EventId: 7, Version: 1}},
+	), nil)
+
+	ranges, err := s.localEventsHandler.DiffHistory(context.Background(), cluster.TestAlternativeClusterName, workflowKey)
+	s.NoError(err)
+	s.Equal([]EventRange{
+		{FirstEventID: 8, LastEventID: 10, Version: 1},
+		{FirstEventID: 11, LastEventID: 15, Version: 2},
+	}, ranges)
+}
+
+func (s *localEventsHandlerSuite) TestDiffHistory_WorkflowNotFound() {
+	workflowKey, engine := s.setupDiffHistory(
+		[]*historyspb.VersionHistoryItem{{EventId: 10, Version: 1}, {EventId: 15, Version: 2}},
+	)
+	engine.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNotFound("not found"))
+
+	ranges, err := s.localEventsHandler.DiffHistory(context.Background(), cluster.TestAlternativeClusterName, workflowKey)
+	s.NoError(err)
+	s.Equal([]EventRange{
+		{FirstEventID: 1, LastEventID: 10, Version: 1},
+		{FirstEventID: 11, LastEventID: 15, Version: 2},
+	}, ranges)
+}
+
+func (s *localEventsHandlerSuite) TestDiffHistory_DivergentBranch() {
+	workflowKey, engine := s.setupDiffHistory(
+		[]*historyspb.VersionHistoryItem{{EventId: 10, Version: 1}, {EventId: 15, Version: 2}, {EventId: 25, Version: 4}},
+	)
+	engine.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(newGetMutableStateResponse(
+		[]*historyspb.VersionHistoryItem{{EventId: 10, Version: 1}, {EventId: 12, Version: 3}},
+	), nil)
+
+	ranges, err := s.localEventsHandler.DiffHistory(context.Background(), cluster.TestAlternativeClusterName, workflowKey)
+	s.NoError(err)
+	s.Equal([]EventRange{
+		{FirstEventID: 11, LastEventID: 15, Version: 2, Divergent: true},
+		{FirstEventID: 16, LastEventID: 25, Version: 4},
+	}, ranges)
+}
+
+func (s *localEventsHandlerSuite) setupDiffHistory(
+	sourceVersionHistoryItems []*historyspb.VersionHistoryItem,
+) (definition.WorkflowKey, *shard.MockEngine) {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	shardContext := shard.NewMockContext(s.controller)
+	engine := shard.NewMockEngine(s.controller)
+	namespaceRegistry := namespace.NewMockRegistry(s.controller)
+	adminClient := adminservicemock.NewMockAdminServiceClient(s.controller)
+	s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	).Return(shardContext, nil)
+	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil)
+	shardContext.EXPECT().GetNamespaceRegistry().Return(namespaceRegistry)
+	namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(workflowKey.NamespaceID)).Return(
+		namespace.NewLocalNamespaceForTest(&persistencespb.NamespaceInfo{Id: workflowKey.NamespaceID, Name: "test-namespace"}, nil, ""),
+		nil,
+	)
+	shardContext.EXPECT().GetRemoteAdminClient(cluster.TestAlternativeClusterName).Return(adminClient, nil)
+	adminClient.EXPECT().DescribeMutableState(gomock.Any(), &adminservice.DescribeMutableStateRequest{
+		Namespace: "test-namespace",
+		Execution: &commonpb.WorkflowExecution{
+			WorkflowId: workflowKey.WorkflowID,
+			RunId:      workflowKey.RunID,
+		},
+	}).Return(&adminservice.DescribeMutableStateResponse{
+		DatabaseMutableState: &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				VersionHistories: versionhistory.NewVersionHistories(
+					versionhistory.NewVersionHistory(nil, sourceVersionHistoryItems),
+				),
+			},
+		},
+	}, nil)
+	return workflowKey, engine
+}
+
+func (s *localEventsHandlerSuite) TestReconcileCurrentExecution_StalePointer() {
+	workflowKey, engine := s.setupReconcileCurrentExecution(
+		[]*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+	)
+	gomock.InOrder(
+		engine.EXPECT().GetMutableState(gomock.Any(), &historyservice.GetMutableStateRequest{
+			NamespaceId: workflowKey.NamespaceID,
+			Execution: &commonpb.WorkflowExecution{
+				WorkflowId: workflowKey.WorkflowID,
+				RunId:      workflowKey.RunID,
+			},
+		}).Return(newGetMutableStateResponse([]*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}}), nil),
+		engine.EXPECT().ReconcileCurrentExecution(gomock.Any(), workflowKey).Return(nil),
+	)
+
+	err := s.localEventsHandler.ReconcileCurrentExecution(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	)
+	s.NoError(err)
+}
+
+func (s *localEventsHandlerSuite) TestReconcileCurrentExecution_ImportThenRepair() {
+	workflowKey, engine := s.setupReconcileCurrentExecution(
+		[]*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+	)
+	s.clusterMetadata.EXPECT().GetClusterID().Return(int64(1))
+	s.clusterMetadata.EXPECT().GetFailoverVersionIncrement().Return(int64(1000))
+	blobs := serializeEvents(s.eventSerializer, [][]*historypb.HistoryEvent{
+		{{EventId: 1, Version: 1}, {EventId: 2, Version: 1}},
+		{{EventId: 3, Version: 1}, {EventId: 4, Version: 1}},
+	})
+	s.remoteHistoryFetcher.EXPECT().GetSingleWorkflowHistoryPaginatedIterator(
+		gomock.Any(),
+		cluster.TestAlternativeClusterName,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+		workflowKey.RunID,
+		int64(1),
+		int64(1),
+		int64(4),
+		int64(1),
+	).Return(newHistoryBatchIterator(&historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+	}, blobs...))
+
+	importToken := []byte{1, 0, 1}
+	gomock.InOrder(
+		// the source's current run doesn't exist locally
+		engine.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNotFound("not found")),
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Equal(workflowKey.RunID, request.Execution.RunId)
+				s.Equal(blobs, request.HistoryBatches)
+				return &historyservice.ImportWorkflowExecutionResponse{Token: importToken, EventsApplied: true}, nil
+			},
+		),
+		engine.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+				s.Equal(importToken, request.Token)
+				return &historyservice.ImportWorkflowExecutionResponse{}, nil
+			},
+		),
+		engine.EXPECT().ReconcileCurrentExecution(gomock.Any(), workflowKey).Return(nil),
+	)
+
+	err := s.localEventsHandler.ReconcileCurrentExecution(
+		context.Background(),
+		cluster.TestAlternativeClusterName,
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	)
+	s.NoError(err)
+}
+
+// setupReconcileCurrentExecution returns the key of the source's current run, and the engine of
+// its shard.
+func (s *localEventsHandlerSuite) setupReconcileCurrentExecution(
+	sourceVersionHistoryItems []*historyspb.VersionHistoryItem,
+) (definition.WorkflowKey, *shard.MockEngine) {
+	workflowKey := definition.NewWorkflowKey(uuid.NewString(), uuid.NewString(), uuid.NewString())
+	shardContext := shard.NewMockContext(s.controller)
+	engine := shard.NewMockEngine(s.controller)
+	adminClient := adminservicemock.NewMockAdminServiceClient(s.controller)
+	s.shardController.EXPECT().GetShardByNamespaceWorkflow(
+		namespace.ID(workflowKey.NamespaceID),
+		workflowKey.WorkflowID,
+	).Return(shardContext, nil).AnyTimes()
+	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	s.expectReplicatedNamespace(shardContext, workflowKey)
+	shardContext.EXPECT().GetRemoteAdminClient(cluster.TestAlternativeClusterName).Return(adminClient, nil)
+	adminClient.EXPECT().DescribeMutableState(gomock.Any(), &adminservice.DescribeMutableStateRequest{
+		Namespace: "test-namespace",
+		Execution: &commonpb.WorkflowExecution{
+			WorkflowId: workflowKey.WorkflowID,
+		},
+	}).Return(&adminservice.DescribeMutableStateResponse{
+		DatabaseMutableState: &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				VersionHistories: versionhistory.NewVersionHistories(
+					versionhistory.NewVersionHistory(nil, sourceVersionHistoryItems),
+				),
+			},
+			ExecutionState: &persistencespb.WorkflowExecutionState{
+				RunId: workflowKey.RunID,
+			},
+		},
+	}, nil)
+	return workflowKey, engine
+}
+
+func newGetMutableStateResponse(
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+) *historyservice.GetMutableStateResponse {
+	return &historyservice.GetMutableStateResponse{
+		VersionHistories: versionhistory.NewVersionHistories(
+			versionhistory.NewVersionHistory(nil, versionHistoryItems),
+		),
+	}
+}
+
+func newHistoryBatchIterator(
+	versionHistory *historyspb.VersionHistory,
+	blobs ...*commonpb.DataBlob,
+) collection.Iterator[HistoryBatch] {
+	return collection.NewPagingIterator(func(paginationToken []byte) ([]HistoryBatch, []byte, error) {
+		batches := make([]HistoryBatch, len(blobs))
+		for i, blob := range blobs {
+			batches[i] = HistoryBatch{RawEventBatch: blob, VersionHistory: versionHistory}
+		}
+		return batches, nil, nil
+	})
+}
+
+// closableHistoryBatchIterator is an iterator that records whether it was closed, like the
+// iterator of a stream.
+type closableHistoryBatchIterator struct {
+	collection.Iterator[HistoryBatch]
+	closed bool
+}
+
+func (i *closableHistoryBatchIterator) Close() error {
+	i.closed = true
+	return nil
+}
+
+// newFailingHistoryBatchIterator returns an iterator whose first page is blobs, and whose second
+// page fails with err.
+func newFailingHistoryBatchIterator(
+	versionHistory *historyspb.VersionHistory,
+	err error,
+	blobs ...*commonpb.DataBlob,
+) collection.Iterator[HistoryBatch] {
+	return collection.NewPagingIterator(func(paginationToken []byte) ([]HistoryBatch, []byte, error) {
+		if len(paginationToken) != 0 {
+			return nil, nil, err
+		}
+		batches := make([]HistoryBatch, len(blobs))
+		for i, blob := range blobs {
+			batches[i] = HistoryBatch{RawEventBatch: blob, VersionHistory: versionHistory}
+		}
+		return batches, []byte{1}, nil
+	})
+}
+
+type testImportProgressStream struct {
+	ctx    context.Context
+	sent   []*ImportEventsProgress
+	onSend func(*ImportEventsProgress) error
+}
+
+func (s *testImportProgressStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testImportProgressStream) Send(progress *ImportEventsProgress) error {
+	s.sent = append(s.sent, progress)
+	if s.onSend != nil {
+		return s.onSend(progress)
+	}
+	return nil
+}
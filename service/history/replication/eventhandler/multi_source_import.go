@@ -0,0 +1,289 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package eventhandler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+
+	historyspb "go.temporal.io/server/api/history/v1"
+	common2 "go.temporal.io/server/common"
+	"go.temporal.io/server/common/collection"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/service/history/shard"
+)
+
+const (
+	// SourceSelectionFirstAvailable fetches events from the source clusters in the given order.
+	SourceSelectionFirstAvailable SourceSelectionPolicy = iota
+	// SourceSelectionLowestLatency fetches events from the source clusters in the order of the
+	// latency of a DescribeMutableState call for the workflow to each of them. Sources that fail the
+	// call come last, in the given order.
+	SourceSelectionLowestLatency
+)
+
+type (
+	// SourceSelectionPolicy is the order in which LocalGeneratedEventsHandler.ImportHistoryEventsFromSources
+	// fetches events from its source clusters.
+	SourceSelectionPolicy int
+
+	// ImportSourceRange is an inclusive range of imported events that were fetched from Cluster, see
+	// LocalGeneratedEventsHandler.ImportHistoryEventsFromSources.
+	ImportSourceRange struct {
+		Cluster      string
+		FirstEventID int64
+		LastEventID  int64
+	}
+
+	// multiSourceHistoryIterator iterates the batches of events [nextEventID, endEventID] of a
+	// workflow, fetching them from one source cluster at a time. If fetching from a source fails, or
+	// the source has no more events before endEventID, it continues with the next source from the
+	// first event not returned yet.
+	multiSourceHistoryIterator struct {
+		ctx             context.Context
+		logger          log.Logger
+		eventSerializer serialization.Serializer
+		newIterator     func(sourceCluster string, startEventID int64, startEventVersion int64) collection.Iterator[HistoryBatch]
+		versionHistory  *historyspb.VersionHistory
+		endEventID      int64
+		sources         []string
+
+		sourceIndex int
+		current     collection.Iterator[HistoryBatch]
+		nextEventID int64
+		ranges      []ImportSourceRange
+		next        *HistoryBatch
+		err         error
+	}
+)
+
+// ImportHistoryEventsFromSources is ImportHistoryEventsFromBeginning, but with a list of candidate
+// source clusters instead of a single one. Events are fetched from one source at a time, in the
+// order of policy. If fetching from a source fails, or the source doesn't have all of the events,
+// e.g. because it only has a prefix of the history, the import continues with the next source from
+// the first event that's not imported yet. It returns the ranges of events each source served, in
+// order, including when the import fails, so that they can be verified later. The namespace of the
+// workflow must be replicated from each of sourceClusters.
+func (h *localEventsHandlerImpl) ImportHistoryEventsFromSources(
+	ctx context.Context,
+	sourceClusters []string,
+	policy SourceSelectionPolicy,
+	workflowKey definition.WorkflowKey,
+	versionHistoryItems []*historyspb.VersionHistoryItem,
+	manifest *HistoryImportManifest,
+) ([]ImportSourceRange, error) {
+	if len(sourceClusters) == 0 {
+		return nil, serviceerror.NewInvalidArgument("no source cluster to import from")
+	}
+	shardContext, engine, localVersionHistory, err := h.getImportFromBeginningTarget(ctx, sourceClusters, false, workflowKey, versionHistoryItems)
+	if err != nil {
+		return nil, err
+	}
+	sources := sourceClusters
+	switch policy {
+	case SourceSelectionFirstAvailable:
+	case SourceSelectionLowestLatency:
+		sources = h.orderSourcesByLatency(ctx, shardContext, sourceClusters, workflowKey)
+	default:
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("unknown source selection policy %v", policy))
+	}
+
+	lastItem := localVersionHistory[len(localVersionHistory)-1]
+	historyIterator := &multiSourceHistoryIterator{
+		ctx:             ctx,
+		logger:          h.logger,
+		eventSerializer: h.eventSerializer,
+		newIterator: func(sourceCluster string, startEventID int64, startEventVersion int64) collection.Iterator[HistoryBatch] {
+			return h.historyPaginatedFetcher.GetSingleWorkflowHistoryPaginatedIterator(
+				ctx,
+				sourceCluster,
+				namespace.ID(workflowKey.NamespaceID),
+				workflowKey.WorkflowID,
+				workflowKey.RunID,
+				startEventID,
+				startEventVersion,
+				lastItem.EventId,
+				lastItem.Version,
+			)
+		},
+		versionHistory: versionhistory.NewVersionHistory(nil, localVersionHistory),
+		endEventID:     lastItem.EventId,
+		sources:        sources,
+		nextEventID:    common2.FirstEventID,
+	}
+	var verifier *historyImportVerifier
+	if manifest != nil {
+		verifier = newHistoryImportVerifier(manifest)
+	}
+	err = h.importHistoryBatches(ctx, engine, workflowKey, historyIterator, nil, verifier, nil, nil)
+	return historyIterator.ranges, err
+}
+
+// orderSourcesByLatency orders sourceClusters by the latency of a DescribeMutableState call for the
+// workflow to each of them. Sources that fail the call come last, in their original order.
+func (h *localEventsHandlerImpl) orderSourcesByLatency(
+	ctx context.Context,
+	shardContext shard.Context,
+	sourceClusters []string,
+	workflowKey definition.WorkflowKey,
+) []string {
+	latencies := make(map[string]time.Duration, len(sourceClusters))
+	for _, sourceCluster := range sourceClusters {
+		start := time.Now()
+		if _, err := h.describeRemoteMutableState(ctx, shardContext, sourceCluster, workflowKey); err != nil {
+			h.logger.Warn("Failed to probe import source cluster",
+				tag.SourceCluster(sourceCluster),
+				tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+				tag.WorkflowID(workflowKey.WorkflowID),
+				tag.WorkflowRunID(workflowKey.RunID),
+				tag.Error(err),
+			)
+			continue
+		}
+		latencies[sourceCluster] = time.Since(start)
+	}
+	sources := slices.Clone(sourceClusters)
+	slices.SortStableFunc(sources, func(a, b string) int {
+		latencyA, okA := latencies[a]
+		latencyB, okB := latencies[b]
+		switch {
+		case okA && okB:
+			return cmp.Compare(latencyA, latencyB)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return sources
+}
+
+func (i *multiSourceHistoryIterator) HasNext() bool {
+	if i.next == nil && i.err == nil {
+		i.advance()
+	}
+	return i.next != nil || i.err != nil
+}
+
+func (i *multiSourceHistoryIterator) Next() (HistoryBatch, error) {
+	if !i.HasNext() {
+		panic("multiSourceHistoryIterator Next() called without checking HasNext()")
+	}
+
+	if i.err != nil {
+		err := i.err
+		i.err = nil
+		// the import is aborted, no more events are fetched
+		i.nextEventID = i.endEventID + 1
+		return HistoryBatch{}, err
+	}
+	batch := *i.next
+	i.next = nil
+	return batch, nil
+}
+
+func (i *multiSourceHistoryIterator) advance() {
+	for i.nextEventID <= i.endEventID {
+		if i.current == nil {
+			if i.sourceIndex == len(i.sources) {
+				i.err = serviceerror.NewUnavailable(fmt.Sprintf(
+					"no source cluster of %v has events from event %v on", i.sources, i.nextEventID,
+				))
+				return
+			}
+			version, err := versionhistory.GetVersionHistoryEventVersion(i.versionHistory, i.nextEventID)
+			if err != nil {
+				i.err = err
+				return
+			}
+			i.current = i.newIterator(i.sources[i.sourceIndex], i.nextEventID, version)
+		}
+
+		if !i.current.HasNext() {
+			i.failover(serviceerror.NewNotFound(fmt.Sprintf("source cluster has no events from event %v on", i.nextEventID)))
+			continue
+		}
+		batch, err := i.current.Next()
+		if err != nil {
+			if i.ctx.Err() != nil {
+				i.err = err
+				return
+			}
+			i.failover(err)
+			continue
+		}
+		i.next = &batch
+		events, err := i.eventSerializer.DeserializeEvents(batch.RawEventBatch)
+		if err == nil && len(events) != 0 {
+			i.record(events[0].GetEventId(), events[len(events)-1].GetEventId())
+		}
+		// otherwise the import fails on the batch
+		return
+	}
+}
+
+func (i *multiSourceHistoryIterator) failover(err error) {
+	i.logger.Warn("Failed to fetch history events from import source cluster, failing over to the next one",
+		tag.SourceCluster(i.sources[i.sourceIndex]),
+		tag.WorkflowEventID(i.nextEventID),
+		tag.Error(err),
+	)
+	closeHistoryIterator(i.current)
+	i.sourceIndex++
+	i.current = nil
+}
+
+// Close closes the iterator of the current source.
+func (i *multiSourceHistoryIterator) Close() error {
+	closeHistoryIterator(i.current)
+	i.current = nil
+	return nil
+}
+
+func (i *multiSourceHistoryIterator) record(firstEventID int64, lastEventID int64) {
+	sourceCluster := i.sources[i.sourceIndex]
+	if n := len(i.ranges); n > 0 && i.ranges[n-1].Cluster == sourceCluster && i.ranges[n-1].LastEventID+1 == firstEventID {
+		i.ranges[n-1].LastEventID = lastEventID
+	} else {
+		i.ranges = append(i.ranges, ImportSourceRange{
+			Cluster:      sourceCluster,
+			FirstEventID: firstEventID,
+			LastEventID:  lastEventID,
+		})
+	}
+	i.nextEventID = lastEventID + 1
+}
@@ -26,15 +26,20 @@ package eventhandler
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
 	"time"
 
 	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
 
 	"go.temporal.io/server/api/adminservice/v1"
 	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/client"
 	"go.temporal.io/server/common/collection"
 	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/namespace"
@@ -63,6 +68,23 @@ type (
 			endEventID int64,
 			endEventVersion int64,
 		) collection.Iterator[HistoryBatch]
+		// GetSingleWorkflowHistoryStreamIterator is GetSingleWorkflowHistoryPaginatedIterator, but
+		// fetches the history with a single StreamWorkflowExecutionRawHistory call to the remote
+		// cluster. Pages are received as the iterator advances, so at most one page is buffered.
+		// The stream has no deadline besides ctx, but each page must be received within
+		// resendContextTimeout. The returned iterator is an io.Closer, Close it to end the stream
+		// early.
+		GetSingleWorkflowHistoryStreamIterator(
+			ctx context.Context,
+			remoteClusterName string,
+			namespaceID namespace.ID,
+			workflowID string,
+			runID string,
+			startEventID int64,
+			startEventVersion int64,
+			endEventID int64,
+			endEventVersion int64,
+		) collection.Iterator[HistoryBatch]
 	}
 
 	HistoryPaginatedFetcherImpl struct {
@@ -77,6 +99,18 @@ type (
 		VersionHistory *historyspb.VersionHistory
 		RawEventBatch  *commonpb.DataBlob
 	}
+
+	historyStreamIterator struct {
+		ctx         context.Context
+		cancel      context.CancelFunc
+		idleTimeout time.Duration
+		openStream  func(context.Context) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error)
+
+		stream  adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient
+		batches []HistoryBatch
+		err     error
+		done    bool
+	}
 )
 
 const (
@@ -135,6 +169,47 @@ func (n *HistoryPaginatedFetcherImpl) GetSingleWorkflowHistoryPaginatedIterator(
 	))
 }
 
+func (n *HistoryPaginatedFetcherImpl) GetSingleWorkflowHistoryStreamIterator(
+	ctx context.Context,
+	remoteClusterName string,
+	namespaceID namespace.ID,
+	workflowID string,
+	runID string,
+	startEventID int64,
+	startEventVersion int64,
+	endEventID int64,
+	endEventVersion int64,
+) collection.Iterator[HistoryBatch] {
+
+	// the import calls are made between receives, so a deadline for the whole stream would cut
+	// off the import of long histories
+	streamCtx, cancel := context.WithCancel(headers.SetVersions(ctx))
+
+	return &historyStreamIterator{
+		ctx:         streamCtx,
+		cancel:      cancel,
+		idleTimeout: resendContextTimeout,
+		openStream: func(ctx context.Context) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+			adminClient, err := n.clientBean.GetRemoteAdminClient(remoteClusterName)
+			if err != nil {
+				return nil, err
+			}
+			return adminClient.StreamWorkflowExecutionRawHistory(ctx, &adminservice.StreamWorkflowExecutionRawHistoryRequest{
+				NamespaceId: namespaceID.String(),
+				Execution: &commonpb.WorkflowExecution{
+					WorkflowId: workflowID,
+					RunId:      runID,
+				},
+				StartEventId:      startEventID,
+				StartEventVersion: startEventVersion,
+				EndEventId:        endEventID,
+				EndEventVersion:   endEventVersion,
+				MaximumPageSize:   defaultPageSize,
+			})
+		},
+	}
+}
+
 func (n *HistoryPaginatedFetcherImpl) getPaginationFn(
 	ctx context.Context,
 	remoteClusterName string,
@@ -222,3 +297,83 @@ func (n *HistoryPaginatedFetcherImpl) getHistory(
 
 	return response, nil
 }
+
+// HasNext returns whether there's a next batch or an error, it blocks until the next page of the
+// stream is received.
+func (i *historyStreamIterator) HasNext() bool {
+	for len(i.batches) == 0 && i.err == nil && !i.done {
+		i.receive()
+	}
+	return len(i.batches) != 0 || i.err != nil
+}
+
+// Next returns the next batch or error. Once an error is returned, the iterator is done.
+func (i *historyStreamIterator) Next() (HistoryBatch, error) {
+	if !i.HasNext() {
+		panic("historyStreamIterator Next() called without checking HasNext()")
+	}
+
+	if i.err != nil {
+		err := i.err
+		i.err = nil
+		i.done = true
+		return HistoryBatch{}, err
+	}
+	batch := i.batches[0]
+	i.batches = i.batches[1:]
+	return batch, nil
+}
+
+// Close ends the stream, a following HasNext returns false.
+func (i *historyStreamIterator) Close() error {
+	i.cancel()
+	i.batches = nil
+	i.err = nil
+	i.done = true
+	return nil
+}
+
+func (i *historyStreamIterator) receive() {
+	// cancel the stream if the remote cluster doesn't send the next page in time
+	var timedOut atomic.Bool
+	idleTimer := time.AfterFunc(i.idleTimeout, func() {
+		timedOut.Store(true)
+		i.cancel()
+	})
+	defer idleTimer.Stop()
+
+	if i.stream == nil {
+		stream, err := i.openStream(i.ctx)
+		if err != nil {
+			i.fail(err, timedOut.Load())
+			return
+		}
+		i.stream = stream
+	}
+
+	response, err := i.stream.Recv()
+	if err == io.EOF {
+		i.done = true
+		i.cancel()
+		return
+	}
+	if err != nil {
+		i.fail(err, timedOut.Load())
+		return
+	}
+	versionHistory := response.GetVersionHistory()
+	for _, blob := range response.GetHistoryBatches() {
+		i.batches = append(i.batches, HistoryBatch{
+			VersionHistory: versionHistory,
+			RawEventBatch:  blob,
+		})
+	}
+}
+
+func (i *historyStreamIterator) fail(err error, timedOut bool) {
+	if timedOut {
+		err = serviceerror.NewDeadlineExceeded(fmt.Sprintf("no history page received within %v: %v", i.idleTimeout, err))
+	}
+	i.err = err
+	i.cancel()
+}
@@ -73,3 +73,17 @@ func (mr *MockHistoryPaginatedFetcherMockRecorder) GetSingleWorkflowHistoryPagin
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSingleWorkflowHistoryPaginatedIterator", reflect.TypeOf((*MockHistoryPaginatedFetcher)(nil).GetSingleWorkflowHistoryPaginatedIterator), ctx, remoteClusterName, namespaceID, workflowID, runID, startEventID, startEventVersion, endEventID, endEventVersion)
 }
+
+// GetSingleWorkflowHistoryStreamIterator mocks base method.
+func (m *MockHistoryPaginatedFetcher) GetSingleWorkflowHistoryStreamIterator(ctx context.Context, remoteClusterName string, namespaceID namespace.ID, workflowID, runID string, startEventID, startEventVersion, endEventID, endEventVersion int64) collection.Iterator[HistoryBatch] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSingleWorkflowHistoryStreamIterator", ctx, remoteClusterName, namespaceID, workflowID, runID, startEventID, startEventVersion, endEventID, endEventVersion)
+	ret0, _ := ret[0].(collection.Iterator[HistoryBatch])
+	return ret0
+}
+
+// GetSingleWorkflowHistoryStreamIterator indicates an expected call of GetSingleWorkflowHistoryStreamIterator.
+func (mr *MockHistoryPaginatedFetcherMockRecorder) GetSingleWorkflowHistoryStreamIterator(ctx, remoteClusterName, namespaceID, workflowID, runID, startEventID, startEventVersion, endEventID, endEventVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSingleWorkflowHistoryStreamIterator", reflect.TypeOf((*MockHistoryPaginatedFetcher)(nil).GetSingleWorkflowHistoryStreamIterator), ctx, remoteClusterName, namespaceID, workflowID, runID, startEventID, startEventVersion, endEventID, endEventVersion)
+}
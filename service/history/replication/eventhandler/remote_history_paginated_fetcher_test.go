@@ -26,6 +26,7 @@ package eventhandler
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
@@ -36,6 +37,8 @@ import (
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.temporal.io/server/api/adminservice/v1"
@@ -229,6 +232,185 @@ func (s *historyPaginatedFetcherSuite) TestGetSingleWorkflowHistoryIterator() {
 	s.False(fetcher.HasNext())
 }
 
+func (s *historyPaginatedFetcherSuite) TestGetSingleWorkflowHistoryStreamIterator() {
+	workflowID := "some random workflow ID"
+	runID := uuid.New()
+	startEventID := int64(123)
+	startEventVersion := int64(100)
+	blob1 := s.serializeEvents([]*historypb.HistoryEvent{{EventId: 123, Version: 100}})
+	blob2 := s.serializeEvents([]*historypb.HistoryEvent{{EventId: 124, Version: 100}})
+	blob3 := s.serializeEvents([]*historypb.HistoryEvent{{EventId: 125, Version: 100}})
+	versionHistory := &historyspb.VersionHistory{
+		Items: []*historyspb.VersionHistoryItem{{EventId: 125, Version: 100}},
+	}
+
+	stream := adminservicemock.NewMockAdminService_StreamWorkflowExecutionRawHistoryClient(s.controller)
+	s.mockAdminClient.EXPECT().StreamWorkflowExecutionRawHistory(
+		gomock.Any(),
+		&adminservice.StreamWorkflowExecutionRawHistoryRequest{
+			NamespaceId: s.namespaceID.String(),
+			Execution: &commonpb.WorkflowExecution{
+				WorkflowId: workflowID,
+				RunId:      runID,
+			},
+			StartEventId:      startEventID,
+			StartEventVersion: startEventVersion,
+			EndEventId:        common.EmptyEventID,
+			EndEventVersion:   common.EmptyVersion,
+			MaximumPageSize:   defaultPageSize,
+		}).Return(stream, nil)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: []*commonpb.DataBlob{blob1, blob2},
+			VersionHistory: versionHistory,
+		}, nil),
+		// an empty page doesn't end the iteration
+		stream.EXPECT().Recv().Return(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			VersionHistory: versionHistory,
+		}, nil),
+		stream.EXPECT().Recv().Return(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: []*commonpb.DataBlob{blob3},
+			VersionHistory: versionHistory,
+		}, nil),
+		stream.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	iterator := s.fetcher.GetSingleWorkflowHistoryStreamIterator(
+		context.Background(),
+		cluster.TestCurrentClusterName,
+		s.namespaceID,
+		workflowID,
+		runID,
+		startEventID,
+		startEventVersion,
+		common.EmptyEventID,
+		common.EmptyVersion,
+	)
+	for _, blob := range []*commonpb.DataBlob{blob1, blob2, blob3} {
+		s.True(iterator.HasNext())
+		batch, err := iterator.Next()
+		s.NoError(err)
+		s.Equal(blob, batch.RawEventBatch)
+		s.Equal(versionHistory, batch.VersionHistory)
+	}
+	s.False(iterator.HasNext())
+}
+
+func (s *historyPaginatedFetcherSuite) TestGetSingleWorkflowHistoryStreamIterator_StreamBroken() {
+	blob := s.serializeEvents([]*historypb.HistoryEvent{{EventId: 1, Version: 1}})
+	stream := adminservicemock.NewMockAdminService_StreamWorkflowExecutionRawHistoryClient(s.controller)
+	s.mockAdminClient.EXPECT().StreamWorkflowExecutionRawHistory(gomock.Any(), gomock.Any()).Return(stream, nil)
+	streamErr := serviceerror.NewUnavailable("stream broken")
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: []*commonpb.DataBlob{blob},
+		}, nil),
+		stream.EXPECT().Recv().Return(nil, streamErr),
+	)
+
+	iterator := s.fetcher.GetSingleWorkflowHistoryStreamIterator(
+		context.Background(),
+		cluster.TestCurrentClusterName,
+		s.namespaceID,
+		"workflowID",
+		uuid.New(),
+		common.FirstEventID,
+		common.EmptyVersion,
+		common.EmptyEventID,
+		common.EmptyVersion,
+	)
+	s.True(iterator.HasNext())
+	_, err := iterator.Next()
+	s.NoError(err)
+	s.True(iterator.HasNext())
+	_, err = iterator.Next()
+	s.Equal(streamErr, err)
+	s.False(iterator.HasNext())
+}
+
+func (s *historyPaginatedFetcherSuite) TestGetSingleWorkflowHistoryStreamIterator_IdleTimeout() {
+	blob := s.serializeEvents([]*historypb.HistoryEvent{{EventId: 1, Version: 1}})
+	stream := adminservicemock.NewMockAdminService_StreamWorkflowExecutionRawHistoryClient(s.controller)
+	var streamCtx context.Context
+	s.mockAdminClient.EXPECT().StreamWorkflowExecutionRawHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *adminservice.StreamWorkflowExecutionRawHistoryRequest, _ ...grpc.CallOption) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+			streamCtx = ctx
+			return stream, nil
+		},
+	)
+	gomock.InOrder(
+		stream.EXPECT().Recv().Return(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+			HistoryBatches: []*commonpb.DataBlob{blob},
+		}, nil).Times(2),
+		// the remote cluster stops sending pages
+		stream.EXPECT().Recv().DoAndReturn(func() (*adminservice.StreamWorkflowExecutionRawHistoryResponse, error) {
+			<-streamCtx.Done()
+			return nil, streamCtx.Err()
+		}),
+	)
+
+	iterator := s.fetcher.GetSingleWorkflowHistoryStreamIterator(
+		context.Background(),
+		cluster.TestCurrentClusterName,
+		s.namespaceID,
+		"workflowID",
+		uuid.New(),
+		common.FirstEventID,
+		common.EmptyVersion,
+		common.EmptyEventID,
+		common.EmptyVersion,
+	)
+	iterator.(*historyStreamIterator).idleTimeout = 10 * time.Millisecond
+	s.True(iterator.HasNext())
+	_, err := iterator.Next()
+	s.NoError(err)
+	// the time between receives, e.g. to import the received page, doesn't count
+	time.Sleep(50 * time.Millisecond)
+	s.True(iterator.HasNext())
+	_, err = iterator.Next()
+	s.NoError(err)
+
+	s.True(iterator.HasNext())
+	_, err = iterator.Next()
+	var deadlineExceeded *serviceerror.DeadlineExceeded
+	s.ErrorAs(err, &deadlineExceeded)
+	s.False(iterator.HasNext())
+}
+
+func (s *historyPaginatedFetcherSuite) TestGetSingleWorkflowHistoryStreamIterator_Close() {
+	blob := s.serializeEvents([]*historypb.HistoryEvent{{EventId: 1, Version: 1}})
+	stream := adminservicemock.NewMockAdminService_StreamWorkflowExecutionRawHistoryClient(s.controller)
+	var streamCtx context.Context
+	s.mockAdminClient.EXPECT().StreamWorkflowExecutionRawHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *adminservice.StreamWorkflowExecutionRawHistoryRequest, _ ...grpc.CallOption) (adminservice.AdminService_StreamWorkflowExecutionRawHistoryClient, error) {
+			streamCtx = ctx
+			return stream, nil
+		},
+	)
+	stream.EXPECT().Recv().Return(&adminservice.StreamWorkflowExecutionRawHistoryResponse{
+		HistoryBatches: []*commonpb.DataBlob{blob, blob},
+	}, nil)
+
+	iterator := s.fetcher.GetSingleWorkflowHistoryStreamIterator(
+		context.Background(),
+		cluster.TestCurrentClusterName,
+		s.namespaceID,
+		"workflowID",
+		uuid.New(),
+		common.FirstEventID,
+		common.EmptyVersion,
+		common.EmptyEventID,
+		common.EmptyVersion,
+	)
+	s.True(iterator.HasNext())
+	_, err := iterator.Next()
+	s.NoError(err)
+
+	s.NoError(iterator.(io.Closer).Close())
+	s.Error(streamCtx.Err())
+	s.False(iterator.HasNext())
+}
+
 func (s *historyPaginatedFetcherSuite) TestGetHistory() {
 	workflowID := "some random workflow ID"
 	runID := uuid.New()
@@ -166,15 +166,17 @@ func (e *ExecutableActivityStateTask) Execute() error {
 		return err
 	}
 	if e.Config.EnableReplicationTaskBatching() {
-		return engine.SyncActivities(ctx, &historyservice.SyncActivitiesRequest{
+		err = engine.SyncActivities(ctx, &historyservice.SyncActivitiesRequest{
 			NamespaceId:    e.NamespaceID,
 			WorkflowId:     e.WorkflowID,
 			RunId:          e.RunID,
 			ActivitiesInfo: e.activityInfos,
 		})
+	} else {
+		err = engine.SyncActivity(ctx, e.req)
 	}
-
-	return engine.SyncActivity(ctx, e.req)
+	recordAppliedTask(shardContext, enumsspb.TASK_TYPE_REPLICATION_SYNC_ACTIVITY, e.ExecutableTask, err)
+	return err
 }
 
 func (e *ExecutableActivityStateTask) HandleErr(err error) error {
@@ -169,6 +169,11 @@ func (s *executableActivityStateTaskSuite) TestExecute_Process() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(shard.ReplicationTaskAudit{
+		TaskType:      enumsspb.TASK_TYPE_REPLICATION_SYNC_ACTIVITY,
+		TaskID:        s.taskID,
+		SourceCluster: s.sourceClusterName,
+	}).Times(1)
 	engine.EXPECT().SyncActivity(gomock.Any(), &historyservice.SyncActivityRequest{
 		NamespaceId:        s.replicationTask.NamespaceId,
 		WorkflowId:         s.replicationTask.WorkflowId,
@@ -230,6 +235,7 @@ func (s *executableActivityStateTaskSuite) TestHandleErr_Resend_Success() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(gomock.Any()).AnyTimes()
 	engine.EXPECT().SyncActivity(gomock.Any(), &historyservice.SyncActivityRequest{
 		NamespaceId:        s.replicationTask.NamespaceId,
 		WorkflowId:         s.replicationTask.WorkflowId,
@@ -416,6 +422,7 @@ func (s *executableActivityStateTaskSuite) TestBatchedTask_ShouldBatchTogether_A
 		workflowId,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(gomock.Any()).AnyTimes()
 
 	engine.EXPECT().SyncActivities(gomock.Any(), &historyservice.SyncActivitiesRequest{
 		NamespaceId:    namespaceId,
@@ -161,7 +161,7 @@ func (e *ExecutableHistoryTask) Execute() error {
 	}
 
 	if !e.Config.EnableReplicateLocalGeneratedEvent() {
-		return engine.ReplicateHistoryEvents(
+		err = engine.ReplicateHistoryEvents(
 			ctx,
 			e.WorkflowKey,
 			e.baseExecutionInfo,
@@ -170,18 +170,20 @@ func (e *ExecutableHistoryTask) Execute() error {
 			newRunEvents,
 			e.newRunID,
 		)
+	} else {
+		err = e.HistoryEventsHandler.HandleHistoryEvents(
+			ctx,
+			e.SourceClusterName(),
+			e.WorkflowKey,
+			e.baseExecutionInfo,
+			e.versionHistoryItems,
+			events,
+			newRunEvents,
+			e.newRunID,
+		)
 	}
-
-	return e.HistoryEventsHandler.HandleHistoryEvents(
-		ctx,
-		e.SourceClusterName(),
-		e.WorkflowKey,
-		e.baseExecutionInfo,
-		e.versionHistoryItems,
-		events,
-		newRunEvents,
-		e.newRunID,
-	)
+	recordAppliedTask(shardContext, enumsspb.TASK_TYPE_REPLICATION_HISTORY, e.ExecutableTask, err)
+	return err
 }
 
 func (e *ExecutableHistoryTask) HandleErr(err error) error {
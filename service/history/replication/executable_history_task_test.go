@@ -224,6 +224,11 @@ func (s *executableHistoryTaskSuite) TestExecute_Process() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(shard.ReplicationTaskAudit{
+		TaskType:      enumsspb.TASK_TYPE_REPLICATION_HISTORY,
+		TaskID:        s.taskID,
+		SourceCluster: s.sourceClusterName,
+	}).Times(1)
 	engine.EXPECT().ReplicateHistoryEvents(
 		gomock.Any(),
 		definition.NewWorkflowKey(s.task.NamespaceID, s.task.WorkflowID, s.task.RunID),
@@ -277,6 +282,7 @@ func (s *executableHistoryTaskSuite) TestHandleErr_Resend_Success() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(gomock.Any()).AnyTimes()
 	engine.EXPECT().ReplicateHistoryEvents(
 		gomock.Any(),
 		definition.NewWorkflowKey(s.task.NamespaceID, s.task.WorkflowID, s.task.RunID),
@@ -133,12 +133,13 @@ func (e *ExecutableSyncHSMTask) Execute() error {
 	if err != nil {
 		return err
 	}
-	return engine.SyncHSM(ctx, &shard.SyncHSMRequest{
+	err = engine.SyncHSM(ctx, &shard.SyncHSMRequest{
 		WorkflowKey:         e.WorkflowKey,
 		StateMachineNode:    e.taskAttr.StateMachineNode,
 		EventVersionHistory: e.taskAttr.VersionHistory,
 	})
-
+	recordAppliedTask(shardContext, enumsspb.TASK_TYPE_REPLICATION_SYNC_HSM, e.ExecutableTask, err)
+	return err
 }
 
 func (e *ExecutableSyncHSMTask) HandleErr(err error) error {
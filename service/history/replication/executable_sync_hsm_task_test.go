@@ -178,6 +178,11 @@ func (s *executableSyncHSMTaskSuite) TestExecute_Process() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(shard.ReplicationTaskAudit{
+		TaskType:      enumsspb.TASK_TYPE_REPLICATION_SYNC_HSM,
+		TaskID:        s.taskID,
+		SourceCluster: s.sourceClusterName,
+	}).Times(1)
 	engine.EXPECT().SyncHSM(gomock.Any(), &shard.SyncHSMRequest{
 		WorkflowKey: definition.WorkflowKey{
 			NamespaceID: s.task.NamespaceID,
@@ -231,6 +236,7 @@ func (s *executableSyncHSMTaskSuite) TestHandleErr_Resend_Success() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(gomock.Any()).AnyTimes()
 	err := serviceerrors.NewRetryReplication(
 		"",
 		s.task.NamespaceID,
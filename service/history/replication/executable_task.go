@@ -42,6 +42,7 @@ import (
 	"go.temporal.io/server/common/namespace"
 	serviceerrors "go.temporal.io/server/common/serviceerror"
 	ctasks "go.temporal.io/server/common/tasks"
+	"go.temporal.io/server/service/history/shard"
 )
 
 //go:generate mockgen -copyright_file ../../../LICENSE -package $GOPACKAGE -source $GOFILE -destination executable_task_mock.go
@@ -467,3 +468,19 @@ func newTaskContext(
 	ctx = headers.SetCallerName(ctx, namespaceName)
 	return context.WithTimeout(ctx, timeout)
 }
+
+// recordAppliedTask adds the result of applying task to the audit log of the shard it was applied
+// on, see shard.Context.RecentReplicationTasks.
+func recordAppliedTask(
+	shardContext shard.Context,
+	taskType enumsspb.TaskType,
+	task ExecutableTask,
+	err error,
+) {
+	shardContext.RecordReplicationTask(shard.ReplicationTaskAudit{
+		TaskType:      taskType,
+		TaskID:        task.TaskID(),
+		SourceCluster: task.SourceClusterName(),
+		Err:           err,
+	})
+}
@@ -135,7 +135,9 @@ func (e *ExecutableWorkflowStateTask) Execute() error {
 	if err != nil {
 		return err
 	}
-	return engine.ReplicateWorkflowState(ctx, e.req)
+	err = engine.ReplicateWorkflowState(ctx, e.req)
+	recordAppliedTask(shardContext, enumsspb.TASK_TYPE_REPLICATION_SYNC_WORKFLOW_STATE, e.ExecutableTask, err)
+	return err
 }
 
 func (e *ExecutableWorkflowStateTask) HandleErr(err error) error {
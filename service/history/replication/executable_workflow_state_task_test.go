@@ -160,6 +160,11 @@ func (s *executableWorkflowStateTaskSuite) TestExecute_Process() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(shard.ReplicationTaskAudit{
+		TaskType:      enumsspb.TASK_TYPE_REPLICATION_SYNC_WORKFLOW_STATE,
+		TaskID:        s.taskID,
+		SourceCluster: s.sourceClusterName,
+	}).Times(1)
 	engine.EXPECT().ReplicateWorkflowState(gomock.Any(), &historyservice.ReplicateWorkflowStateRequest{
 		NamespaceId:   s.task.NamespaceID,
 		WorkflowState: s.replicationTask.GetWorkflowState(),
@@ -209,6 +214,7 @@ func (s *executableWorkflowStateTaskSuite) TestHandleErr_Resend_Success() {
 		s.task.WorkflowID,
 	).Return(shardContext, nil).AnyTimes()
 	shardContext.EXPECT().GetEngine(gomock.Any()).Return(engine, nil).AnyTimes()
+	shardContext.EXPECT().RecordReplicationTask(gomock.Any()).AnyTimes()
 	err := serviceerrors.NewRetryReplication(
 		"",
 		s.task.NamespaceID,
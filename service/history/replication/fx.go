@@ -35,6 +35,7 @@ import (
 
 	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/client"
+	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/definition"
 	"go.temporal.io/server/common/log"
@@ -343,6 +344,7 @@ func localEventHandlerProvider(
 	logger log.Logger,
 	eventSerializer serialization.Serializer,
 	historyPaginatedFetcher eventhandler.HistoryPaginatedFetcher,
+	archiverProvider provider.ArchiverProvider,
 ) eventhandler.LocalGeneratedEventsHandler {
 	return eventhandler.NewLocalEventsHandler(
 		clusterMetadata,
@@ -350,6 +352,7 @@ func localEventHandlerProvider(
 		logger,
 		eventSerializer,
 		historyPaginatedFetcher,
+		archiverProvider,
 	)
 }
 
@@ -240,8 +240,17 @@ func (p *taskProcessorImpl) pollProcessReplicationTasks() (retError error) {
 			p.maxRxReceivedTaskID = p.maxRxProcessedTaskID
 			p.rxTaskBackoff = p.config.ReplicationTaskFetcherErrorRetryWait()
 		}
+		if p.maxRxProcessedTaskID != persistence.EmptyQueueMessageID {
+			p.shard.UpdateAckedReplicationWatermark(p.sourceCluster, p.sourceShardID, p.maxRxProcessedTaskID, p.maxRxProcessedTimestamp)
+		}
 	}()
 
+	if p.shard.IsReplicationPausedFrom(p.sourceCluster) {
+		// nothing is fetched nor acked, the tasks are fetched again once replication is resumed
+		p.rxTaskBackoff = p.config.ReplicationTaskProcessorNoTaskRetryWait(p.sourceShardID)
+		return nil
+	}
+
 	taskIterator := collection.NewPagingIterator(p.paginationFn)
 	for taskIterator.HasNext() && !p.isStopped() {
 		task, err := taskIterator.Next()
@@ -658,3 +658,74 @@ func (s *taskProcessorSuite) TestPaginationFn_Error() {
 		s.Equal(rxTaskBackoff, s.replicationTaskProcessor.rxTaskBackoff)
 	}
 }
+
+func (s *taskProcessorSuite) TestPollProcessReplicationTasks_PausedSource() {
+	s.mockResource.ShardMgr.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	s.NoError(s.mockShard.PauseReplicationFrom(cluster.TestAlternativeClusterName))
+	s.Equal([]string{cluster.TestAlternativeClusterName}, s.mockShard.GetPausedReplicationSources())
+
+	// nothing is fetched nor applied while paused
+	s.NoError(s.replicationTaskProcessor.pollProcessReplicationTasks())
+	s.Empty(s.requestChan)
+	s.Equal(s.config.ReplicationTaskProcessorNoTaskRetryWait(s.shardID), s.replicationTaskProcessor.rxTaskBackoff)
+
+	s.NoError(s.mockShard.ResumeReplicationFrom(cluster.TestAlternativeClusterName))
+	s.Empty(s.mockShard.GetPausedReplicationSources())
+
+	task := &replicationspb.ReplicationTask{
+		SourceTaskId: 123,
+		TaskType:     enumsspb.REPLICATION_TASK_TYPE_SYNC_ACTIVITY_TASK,
+		Attributes: &replicationspb.ReplicationTask_SyncActivityTaskAttributes{
+			SyncActivityTaskAttributes: &replicationspb.SyncActivityTaskAttributes{
+				NamespaceId: uuid.NewRandom().String(),
+				WorkflowId:  uuid.New(),
+				RunId:       uuid.NewRandom().String(),
+			},
+		},
+	}
+	go func() {
+		request := <-s.requestChan
+		defer close(request.respChan)
+		request.respChan <- &replicationspb.ReplicationMessages{
+			ReplicationTasks:       []*replicationspb.ReplicationTask{task},
+			LastRetrievedMessageId: task.SourceTaskId,
+		}
+	}()
+	s.mockReplicationTaskExecutor.EXPECT().Execute(gomock.Any(), task, false).Return(nil)
+
+	s.NoError(s.replicationTaskProcessor.pollProcessReplicationTasks())
+	s.Equal(task.SourceTaskId, s.replicationTaskProcessor.maxRxProcessedTaskID)
+}
+
+func (s *taskProcessorSuite) TestPollProcessReplicationTasks_AckedWatermark() {
+	watermark, _ := s.mockShard.GetAckedReplicationWatermark(cluster.TestAlternativeClusterName)
+	s.Equal(persistence.EmptyQueueMessageID, watermark)
+
+	visibilityTime := time.Now().UTC()
+	task := &replicationspb.ReplicationTask{
+		SourceTaskId:   123,
+		VisibilityTime: timestamppb.New(visibilityTime),
+		TaskType:       enumsspb.REPLICATION_TASK_TYPE_SYNC_ACTIVITY_TASK,
+		Attributes: &replicationspb.ReplicationTask_SyncActivityTaskAttributes{
+			SyncActivityTaskAttributes: &replicationspb.SyncActivityTaskAttributes{
+				NamespaceId: uuid.NewRandom().String(),
+				WorkflowId:  uuid.New(),
+				RunId:       uuid.NewRandom().String(),
+			},
+		},
+	}
+	go func() {
+		request := <-s.requestChan
+		defer close(request.respChan)
+		request.respChan <- &replicationspb.ReplicationMessages{
+			ReplicationTasks:       []*replicationspb.ReplicationTask{task},
+			LastRetrievedMessageId: task.SourceTaskId,
+		}
+	}()
+	s.mockReplicationTaskExecutor.EXPECT().Execute(gomock.Any(), task, false).Return(nil)
+
+	s.NoError(s.replicationTaskProcessor.pollProcessReplicationTasks())
+	watermark, watermarkTime := s.mockShard.GetAckedReplicationWatermark(cluster.TestAlternativeClusterName)
+	s.Equal(task.SourceTaskId, watermark)
+	s.True(visibilityTime.Equal(watermarkTime))
+}
@@ -63,6 +63,14 @@ type (
 		GetClusterMetadata() cluster.Metadata
 		GetConfig() *configs.Config
 		GetEventsCache() events.Cache
+		// EventsCacheTopEntries returns the n workflow runs of this shard with the largest cached
+		// events, to find workflows that flood the events cache. It returns nil unless
+		// history.eventsCacheTopEntriesEnabled is on.
+		EventsCacheTopEntries(n int) []events.CacheEntryInfo
+		// EventsCacheBytes returns the total serialized size of the events in the shard's events
+		// cache. If history.enableHostLevelEventsCache is on, the cache is shared by all shards on
+		// the host and it's the size of the whole cache.
+		EventsCacheBytes() int64
 		GetLogger() log.Logger
 		GetThrottledLogger() log.Logger
 		GetMetricsHandler() metrics.Handler
@@ -81,20 +89,110 @@ type (
 		AssertOwnership(ctx context.Context) error
 		NewVectorClock() (*clockspb.VectorClock, error)
 		CurrentVectorClock() *clockspb.VectorClock
+		GetNamespaceFailoverVersion(namespaceID namespace.ID) (int64, error)
 
 		GenerateTaskID() (int64, error)
 		GenerateTaskIDs(number int) ([]int64, error)
+		// ReserveTaskIDRange allocates n contiguous task IDs for a writer outside of the shard, e.g. a
+		// service that writes tasks directly to the shard's storage during a migration. The shard
+		// never generates these IDs itself, including after it's reloaded. n can be at most the size
+		// of a range ID's task ID block. Until the reservation is released with ReleaseTaskIDRange or
+		// expires after history.shardTaskIDReservationTimeout, the shard's immediate queues don't read
+		// past its first task ID.
+		ReserveTaskIDRange(n int) (TaskIDReservation, error)
+		// ReleaseTaskIDRange releases the reservation starting at the given task ID once its writer is
+		// done. It returns false if there's no such reservation, e.g. because it expired.
+		ReleaseTaskIDRange(start int64) bool
+		// GetTaskIDReservations returns the task ID reservations of the shard that haven't been
+		// released and haven't expired.
+		GetTaskIDReservations() []TaskIDReservation
 
+		// GetShardInfoSize returns the size in bytes of the shard info blob as it would be persisted now.
+		GetShardInfoSize() int
 		GetQueueExclusiveHighReadWatermark(category tasks.Category) tasks.Key
 		GetQueueState(category tasks.Category) (*persistencespb.QueueState, bool)
 		SetQueueState(category tasks.Category, tasksCompleted int, state *persistencespb.QueueState) error
+		RebuildQueueState(ctx context.Context, category tasks.Category) error
+		// ExportQueueState returns a copy of the shard's queue and replication state, e.g. to back it up.
+		ExportQueueState() (*persistencespb.ShardInfo, error)
+		// RestoreQueueState overwrites the shard's queue and replication state with a snapshot
+		// exported from the same shard under the same or an earlier range ID, and unloads the shard
+		// so that its queues are reloaded from it. The range ID and owner of the shard are kept.
+		RestoreQueueState(snapshot *persistencespb.ShardInfo) error
+		// CheckpointForRepair persists a checkpoint of the shard's queue and replication state before
+		// a repair, so that the repair can be undone with RollbackToCheckpoint until the checkpoint
+		// expires after history.shardRepairCheckpointTTL.
+		CheckpointForRepair() (checkpointID string, err error)
+		// RollbackToCheckpoint overwrites the shard's queue and replication state with a checkpoint
+		// taken by CheckpointForRepair, and unloads the shard so that its queues are reloaded from it.
+		RollbackToCheckpoint(checkpointID string) error
+		GetTaskInfo(category tasks.Category, taskID int64) (tasks.Task, error)
+		// ScanForDuplicateTaskIDs returns the IDs shared by more than one of the first limit pending
+		// tasks of the category. It's read-only.
+		ScanForDuplicateTaskIDs(ctx context.Context, category tasks.Category, limit int) ([]int64, error)
+		// UpcomingTimers returns the n pending timer tasks of the shard that fire first, across all
+		// workflows, ordered by fire time. Timers that are due but not processed yet come first. It's
+		// read-only.
+		UpcomingTimers(ctx context.Context, n int) ([]TimerInfo, error)
+		// EstimateLoad returns an estimate of the number and size of the workflow executions of the
+		// shard. It's cached for history.shardLoadEstimateTTL, as it scans the executions.
+		EstimateLoad(ctx context.Context) (ShardLoadEstimate, error)
+		// ListExecutionsWithPendingSignals returns up to limit workflow executions of the shard that
+		// have buffered signals not yet delivered to the workflow. It's read-only.
+		ListExecutionsWithPendingSignals(ctx context.Context, limit int) ([]definition.WorkflowKey, error)
+		// TaskGenerationByWorkflowType returns the approximate number of tasks generated by the
+		// workflow writes of the shard in the last window, for the workflow types with the most tasks.
+		TaskGenerationByWorkflowType(window time.Duration) map[string]int64
+		// OldestPendingTaskTime returns the visibility time of the oldest task of the category that's
+		// ready to be processed but not acked yet: the creation time for immediate tasks and the fire
+		// time for scheduled tasks that are due. It's false if there is no such task. It's based on
+		// the persisted queue state, so it can lag behind the queue by the shard update interval.
+		OldestPendingTaskTime(category tasks.Category) (time.Time, bool)
+		// EstimateQueueDrainTime estimates how long until the pending tasks of the category are
+		// processed, at the rate the shard completed tasks of the category in the last
+		// history.shardQueueDrainRateWindow. It's zero if no tasks are pending, and an error if tasks
+		// are pending but none were completed in the window.
+		EstimateQueueDrainTime(category tasks.Category) (time.Duration, error)
+		// DryRunTask executes a pending task of an immediate queue without persisting anything, and
+		// returns the writes, generated tasks and RPCs the execution would have made, e.g. to debug a
+		// task that keeps failing. The task is not completed.
+		DryRunTask(ctx context.Context, category tasks.Category, taskID int64) (TaskExecutionTrace, error)
+		// ValidateVersionHistory returns the defects of the version histories of a workflow
+		// execution, e.g. for the repair of a workflow with replication issues. It's read-only.
+		ValidateVersionHistory(ctx context.Context, workflowKey definition.WorkflowKey) ([]VersionHistoryDefect, error)
+		ForceCompleteTask(ctx context.Context, category tasks.Category, taskID int64, reason string, dlqWriter TaskDLQWriter) error
 		UpdateReplicationQueueReaderState(readerID int64, readerState *persistencespb.QueueReaderState) error
 
 		GetReplicatorDLQAckLevel(sourceCluster string) int64
 		UpdateReplicatorDLQAckLevel(sourCluster string, ackLevel int64) error
+		// GetReplicationDLQDepth returns the number of replication tasks from sourceCluster that are
+		// in the DLQ and weren't merged or purged yet.
+		GetReplicationDLQDepth(sourceCluster string) (int64, error)
+		// PauseReplicationFrom stops the shard from applying replication tasks from sourceCluster
+		// until ResumeReplicationFrom is called. It's persisted, so it survives a shard reload.
+		PauseReplicationFrom(sourceCluster string) error
+		ResumeReplicationFrom(sourceCluster string) error
+		IsReplicationPausedFrom(sourceCluster string) bool
+		// GetPausedReplicationSources returns the source clusters replication is paused from, sorted.
+		GetPausedReplicationSources() []string
+		// QuarantineWorkflow makes the shard hold the tasks of a workflow, e.g. one whose tasks keep
+		// failing, instead of processing them until UnquarantineWorkflow is called. It's persisted,
+		// so it survives a shard reload.
+		QuarantineWorkflow(workflowKey definition.WorkflowKey, reason string) error
+		UnquarantineWorkflow(workflowKey definition.WorkflowKey) error
+		IsWorkflowQuarantined(workflowKey definition.WorkflowKey) bool
+		// ListQuarantinedWorkflows returns the workflows quarantined on the shard, sorted by workflow key.
+		ListQuarantinedWorkflows() []*persistencespb.QuarantinedWorkflow
 
 		UpdateRemoteClusterInfo(cluster string, ackTaskID int64, ackTimestamp time.Time)
 		UpdateRemoteReaderInfo(readerID int64, ackTaskID int64, ackTimestamp time.Time) error
+		// UpdateAckedReplicationWatermark records the last replication task from a shard of the
+		// source cluster applied by this shard.
+		UpdateAckedReplicationWatermark(sourceCluster string, sourceShardID int32, ackTaskID int64, ackTimestamp time.Time)
+		// GetAckedReplicationWatermark returns the ID and visibility time of the last replication task
+		// from the source cluster applied by this shard, which is what it can tell the source cluster
+		// to ack. It's persistence.EmptyQueueMessageID if none has been applied since the shard was loaded.
+		GetAckedReplicationWatermark(sourceCluster string) (int64, time.Time)
 
 		SetCurrentTime(cluster string, currentTime time.Time)
 		GetCurrentTime(cluster string) time.Time
@@ -102,6 +200,17 @@ type (
 		GetReplicationStatus(cluster []string) (map[string]*historyservice.ShardReplicationStatusPerCluster, map[string]*historyservice.HandoverNamespaceInfo, error)
 
 		UpdateHandoverNamespace(ns *namespace.Namespace, deletedFromDb bool)
+		// ListHandoverNamespaces returns the IDs of the namespaces that the shard considers to be in
+		// handover, i.e. the namespaces reported by GetReplicationStatus, sorted.
+		ListHandoverNamespaces() []namespace.ID
+
+		// RecordReplicationTask adds an applied replication task to the shard's audit log. AppliedTime
+		// defaults to now if not set.
+		RecordReplicationTask(audit ReplicationTaskAudit)
+		// RecentReplicationTasks returns the last applied replication tasks, oldest first. The number
+		// of tasks kept is controlled by history.shardReplicationTaskAuditSize, and they're only kept
+		// until the shard is unloaded.
+		RecentReplicationTasks() []ReplicationTaskAudit
 
 		AppendHistoryEvents(ctx context.Context, request *persistence.AppendHistoryNodesRequest, namespaceID namespace.ID, execution *commonpb.WorkflowExecution) (int, error)
 
@@ -112,16 +221,34 @@ type (
 		ConflictResolveWorkflowExecution(ctx context.Context, request *persistence.ConflictResolveWorkflowExecutionRequest) (*persistence.ConflictResolveWorkflowExecutionResponse, error)
 		SetWorkflowExecution(ctx context.Context, request *persistence.SetWorkflowExecutionRequest) (*persistence.SetWorkflowExecutionResponse, error)
 		GetCurrentExecution(ctx context.Context, request *persistence.GetCurrentExecutionRequest) (*persistence.GetCurrentExecutionResponse, error)
+		// GetCurrentExecutionWithConsistency is GetCurrentExecution, but may be served from a cache
+		// if consistency is ReadConsistencyEventual.
+		GetCurrentExecutionWithConsistency(ctx context.Context, request *persistence.GetCurrentExecutionRequest, consistency ReadConsistency) (*persistence.GetCurrentExecutionResponse, error)
 		GetWorkflowExecution(ctx context.Context, request *persistence.GetWorkflowExecutionRequest) (*persistence.GetWorkflowExecutionResponse, error)
+		// GetWorkflowExecutions reads the given executions of this shard, with up to
+		// ShardBatchReadConcurrency reads in flight at once. Each key is in exactly one of the
+		// returned maps.
+		GetWorkflowExecutions(ctx context.Context, keys []definition.WorkflowKey) (map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse, map[definition.WorkflowKey]error)
 		// DeleteWorkflowExecution add task to delete visibility, current workflow execution, and deletes workflow execution.
 		// If branchToken != nil, then delete history also, otherwise leave history.
 		DeleteWorkflowExecution(ctx context.Context, workflowKey definition.WorkflowKey, branchToken []byte, closeExecutionVisibilityTaskID int64, workflowCloseTime time.Time, stage *tasks.DeleteWorkflowExecutionStage) error
 
 		UnloadForOwnershipLost()
+		// SetMaintenanceMode makes the shard reject workflow writes with a retryable error while on,
+		// e.g. to drain a host before decommissioning it. Reads and replication acks are not affected.
+		SetMaintenanceMode(on bool)
 
 		StateMachineRegistry() *hsm.Registry
 	}
 
+	// TaskDLQWriter writes a task to the history task DLQ, see Context.ForceCompleteTask.
+	TaskDLQWriter interface {
+		WriteTaskToDLQ(ctx context.Context, sourceCluster, targetCluster string, task tasks.Task) error
+	}
+
+	// ReadConsistency is the consistency level of a shard read.
+	ReadConsistency int
+
 	// A ControllableContext is a Context plus other methods needed by
 	// the Controller.
 	ControllableContext interface {
@@ -130,5 +257,33 @@ type (
 
 		IsValid() bool
 		FinishStop()
+		// DrainSpeculativeTasks cancels the pending speculative workflow task timeout tasks of the
+		// shard and returns how many were cancelled. It's called by FinishStop, so that a shard
+		// that is unloaded gracefully doesn't leave timers behind for workflow tasks that its new
+		// owner won't know about.
+		DrainSpeculativeTasks() int
+		ProbeLiveness(ctx context.Context, timeout time.Duration) LivenessReport
 	}
+
+	// LivenessReport is the result of probing a shard's lock for liveness.
+	LivenessReport struct {
+		// Stuck is true if the shard lock could not be acquired within the probe timeout.
+		Stuck  bool
+		Waited time.Duration
+		// HolderOperation is the function currently holding the shard lock for writing.
+		// It's empty if the lock is held by readers, which are not tracked.
+		HolderOperation string
+		HeldFor         time.Duration
+		// HolderStack is only populated if history.shardLockCaptureHolderStack is enabled.
+		HolderStack string
+	}
+)
+
+const (
+	// ReadConsistencyStrong always reads from persistence.
+	ReadConsistencyStrong ReadConsistency = iota
+	// ReadConsistencyEventual may return a cached result, for callers that tolerate staleness,
+	// e.g. dashboards. Cached results are invalidated by writes through this shard, but may
+	// still be stale for up to history.shardCurrentExecutionCacheTTL, e.g. after a shard move.
+	ReadConsistencyEventual
 )
@@ -81,6 +81,9 @@ type (
 		EventsCache                 events.Cache
 
 		StateMachineRegistry *hsm.Registry
+
+		FallbackTaskDeserializer TaskDeserializer `optional:"true"`
+		TaskRewriter             TaskRewriter     `optional:"true"`
 	}
 
 	contextFactoryImpl struct {
@@ -122,6 +125,8 @@ func (c *contextFactoryImpl) CreateContext(
 		c.TaskCategoryRegistry,
 		c.EventsCache,
 		c.StateMachineRegistry,
+		c.FallbackTaskDeserializer,
+		c.TaskRewriter,
 	)
 	if err != nil {
 		return nil, err
@@ -25,10 +25,15 @@
 package shard
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"math"
+	"runtime"
+	rdebug "runtime/debug"
+	"slices"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,6 +43,7 @@ import (
 	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
 	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.temporal.io/server/api/adminservice/v1"
@@ -68,6 +74,7 @@ import (
 	"go.temporal.io/server/common/persistence/serialization"
 	"go.temporal.io/server/common/pingable"
 	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/quotas"
 	"go.temporal.io/server/common/rpc"
 	"go.temporal.io/server/common/searchattribute"
 	"go.temporal.io/server/common/util"
@@ -92,8 +99,16 @@ const (
 const (
 	// ShardUpdateQueueMetricsInterval is the minimum amount of time between updates to a shard's queue metrics
 	queueMetricUpdateInterval = 5 * time.Minute
+	// lockWaitMetricsRefreshInterval is how often the cached ShardLockWaitMetricsEnabled is refreshed
+	lockWaitMetricsRefreshInterval = 10 * time.Second
 
 	pendingMaxReplicationTaskID = math.MaxInt64
+
+	replicationDLQDepthPageSize = 1000
+	duplicateTaskScanPageSize   = 1000
+	upcomingTimerScanPageSize   = 1000
+	loadEstimatePageSize        = 1000
+	queueDepthPageSize          = 1000
 )
 
 var (
@@ -144,11 +159,54 @@ type (
 		// But DO NOT try to acquire ioSemaphore while holding rwLock, as it may cause deadlock.
 		ioSemaphore locks.PrioritySemaphore
 
+		// readRateLimiter limits workflow execution reads per namespace, see ShardReadRPS.
+		readRateLimiter quotas.RequestRateLimiter
+
+		// currentExecutionCache serves eventually consistent current execution reads.
+		currentExecutionCache *currentExecutionCache
+
+		// replicationTaskAuditLog keeps the most recently applied replication tasks.
+		replicationTaskAuditLog *replicationTaskAuditLog
+		// taskGenerationHeatmap counts the tasks generated per workflow type.
+		taskGenerationHeatmap *taskGenerationHeatmap
+		// queueDrainRate measures the rate tasks are completed at per task category.
+		queueDrainRate *queueDrainRate
+		// speculativeTasks are the pending in-memory speculative workflow task timeout tasks.
+		speculativeTasks *speculativeTaskSet
+
+		// taskRewriter, if not nil, is applied to every task before it's written, see TaskRewriter.
+		taskRewriter TaskRewriter
+
 		// state is protected by stateLock
 		stateLock  sync.Mutex
 		state      contextState
 		stopReason stopReason
 
+		// Lock holder tracking for liveness probes. Tracking is only enabled once the shard has
+		// been probed, so that shards that are never probed don't pay for it on every wLock.
+		lockHolderTracking     atomic.Bool
+		captureLockHolderStack atomic.Bool
+		lockHolder             atomic.Pointer[lockHolderInfo] // current writer of rwLock, if any
+		pendingLivenessProbe   atomic.Pointer[livenessProbe]
+
+		// cached ShardLockWaitMetricsEnabled, see lockWaitMetricsEnabled
+		lockWaitMetricsEnabledCache atomic.Bool
+		lockWaitMetricsRefreshAt    atomic.Int64 // unix nanos
+
+		maintenanceMode atomic.Bool
+
+		// lastOwnershipAssertion is the last successful AssertOwnership while the shard is
+		// acquired, see ShardOwnershipFreshness. It's cleared whenever the shard leaves the
+		// acquired state.
+		lastOwnershipAssertion atomic.Pointer[ownershipAssertion]
+
+		// lastLoadEstimate is the last result of EstimateLoad, reused for ShardLoadEstimateTTL.
+		lastLoadEstimate atomic.Pointer[ShardLoadEstimate]
+
+		// quarantinedWorkflows is shardInfo.QuarantinedWorkflows, republished under the write lock
+		// whenever it changes, so that IsWorkflowQuarantined doesn't take the lock for every task.
+		quarantinedWorkflows atomic.Pointer[[]*persistencespb.QuarantinedWorkflow]
+
 		// All following fields are protected by rwLock, and only valid if state >= Acquiring:
 		rwLock                        sync.RWMutex
 		lastUpdated                   time.Time
@@ -174,6 +232,9 @@ type (
 		CurrentTime                time.Time
 		AckedReplicationTaskIDs    map[int32]int64
 		AckedReplicationTimestamps map[int32]time.Time
+		// replication tasks from the remote cluster applied by this shard, keyed on source shard ID
+		AppliedReplicationTaskIDs    map[int32]int64
+		AppliedReplicationTimestamps map[int32]time.Time
 	}
 
 	namespaceHandOverInfo struct {
@@ -181,6 +242,21 @@ type (
 		NotificationVersion  int64
 	}
 
+	lockHolderInfo struct {
+		callerPC   [1]uintptr
+		acquiredAt time.Time
+		stack      []byte
+	}
+
+	livenessProbe struct {
+		acquired chan struct{}
+	}
+
+	ownershipAssertion struct {
+		rangeID    int64
+		assertedAt time.Time
+	}
+
 	// These are the requests that can be passed to transition to change state:
 	contextRequest interface{}
 
@@ -204,6 +280,8 @@ var (
 	// ErrShardStatusUnknown means we're not sure if we have the shard lock or not. This may be returned
 	// during short windows at initialization and if we've lost the connection to the database.
 	ErrShardStatusUnknown = serviceerror.NewUnavailable("shard status unknown")
+	// ErrShardInMaintenanceMode is returned for workflow writes while the shard is in maintenance mode.
+	ErrShardInMaintenanceMode = serviceerror.NewUnavailable("shard is in maintenance mode")
 
 	// errInvalidTransition is an internal error used for acquireShard and transition
 	errInvalidTransition = errors.New("invalid state transition request")
@@ -274,6 +352,68 @@ func (s *ContextImpl) GetPingChecks() []pingable.Check {
 	}
 }
 
+// ProbeLiveness waits for up to the given timeout to acquire the shard lock for writing. If the
+// lock can't be acquired, the returned report is marked as stuck and describes the current writer
+// of the lock, if any.
+//
+// The acquisition is not abandoned on timeout: it stays queued like any other writer, and a later
+// probe waits on the same acquisition instead of queuing another one.
+func (s *ContextImpl) ProbeLiveness(
+	ctx context.Context,
+	timeout time.Duration,
+) LivenessReport {
+	s.captureLockHolderStack.Store(s.config.ShardLockCaptureHolderStack())
+	s.lockHolderTracking.Store(true)
+
+	probe := &livenessProbe{acquired: make(chan struct{})}
+	if s.pendingLivenessProbe.CompareAndSwap(nil, probe) {
+		go func() {
+			// call rwLock.Lock directly to bypass metrics since this isn't a real request
+			s.rwLock.Lock()
+			//nolint:staticcheck // SA2001 just checking if we can acquire the lock
+			s.rwLock.Unlock()
+			s.pendingLivenessProbe.Store(nil)
+			close(probe.acquired)
+		}()
+	} else if pending := s.pendingLivenessProbe.Load(); pending != nil {
+		probe = pending
+	} else {
+		// the pending probe just acquired the lock
+		return LivenessReport{}
+	}
+
+	startTime := s.timeSource.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-probe.acquired:
+		return LivenessReport{}
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	return s.newStuckLivenessReport(s.timeSource.Now().Sub(startTime))
+}
+
+func (s *ContextImpl) newStuckLivenessReport(
+	waited time.Duration,
+) LivenessReport {
+	report := LivenessReport{
+		Stuck:  true,
+		Waited: waited,
+	}
+
+	holder := s.lockHolder.Load()
+	if holder == nil {
+		return report
+	}
+	frame, _ := runtime.CallersFrames(holder.callerPC[:]).Next()
+	report.HolderOperation = frame.Function
+	report.HeldFor = s.timeSource.Now().Sub(holder.acquiredAt)
+	report.HolderStack = string(holder.stack)
+	return report
+}
+
 func (s *ContextImpl) GetEngine(
 	ctx context.Context,
 ) (Engine, error) {
@@ -283,6 +423,10 @@ func (s *ContextImpl) GetEngine(
 func (s *ContextImpl) AssertOwnership(
 	ctx context.Context,
 ) error {
+	if s.isOwnershipAssertionFresh() {
+		return nil
+	}
+
 	if err := s.ioSemaphoreAcquire(ctx); err != nil {
 		return err
 	}
@@ -309,8 +453,36 @@ func (s *ContextImpl) AssertOwnership(
 	}
 	s.wUnlock()
 
+	assertedAt := s.timeSource.Now()
 	err = s.persistenceShardManager.AssertShardOwnership(ctx, request)
-	return s.handleWriteError(request.RangeID, err)
+	if err != nil {
+		s.lastOwnershipAssertion.Store(nil)
+		return s.handleWriteError(request.RangeID, err)
+	}
+	s.lastOwnershipAssertion.Store(&ownershipAssertion{
+		rangeID:    request.RangeID,
+		assertedAt: assertedAt,
+	})
+	return nil
+}
+
+// isOwnershipAssertionFresh returns true if the last successful AssertOwnership is recent enough
+// to be reused, and the shard is still acquired with the same range ID.
+func (s *ContextImpl) isOwnershipAssertionFresh() bool {
+	freshness := s.config.ShardOwnershipFreshness()
+	if freshness <= 0 {
+		return false
+	}
+	assertion := s.lastOwnershipAssertion.Load()
+	if assertion == nil || s.timeSource.Now().Sub(assertion.assertedAt) >= freshness {
+		return false
+	}
+	if s.errorByState() != nil {
+		return false
+	}
+	s.rLock()
+	defer s.rUnlock()
+	return assertion.rangeID == s.getRangeIDLocked()
 }
 
 func (s *ContextImpl) NewVectorClock() (*clockspb.VectorClock, error) {
@@ -354,6 +526,36 @@ func (s *ContextImpl) GenerateTaskIDs(number int) ([]int64, error) {
 	return result, nil
 }
 
+func (s *ContextImpl) ReserveTaskIDRange(n int) (TaskIDReservation, error) {
+	if n <= 0 || n > 1<<s.config.RangeSizeBits {
+		return TaskIDReservation{}, serviceerror.NewInvalidArgument(
+			fmt.Sprintf("task ID range size must be between 1 and %d, got %d", 1<<s.config.RangeSizeBits, n),
+		)
+	}
+
+	s.wLock()
+	defer s.wUnlock()
+
+	reservation, err := s.taskKeyManager.reserveTaskIDs(int64(n), s.config.ShardTaskIDReservationTimeout())
+	if err != nil {
+		return TaskIDReservation{}, err
+	}
+	s.contextTaggedLogger.Info("Reserved task ID range for an external writer.",
+		tag.Number(reservation.Start),
+		tag.NextNumber(reservation.End+1),
+		tag.Timestamp(reservation.ExpireTime),
+	)
+	return reservation, nil
+}
+
+func (s *ContextImpl) ReleaseTaskIDRange(start int64) bool {
+	return s.taskKeyManager.releaseTaskIDs(start)
+}
+
+func (s *ContextImpl) GetTaskIDReservations() []TaskIDReservation {
+	return s.taskKeyManager.getTaskIDReservations()
+}
+
 func (s *ContextImpl) GetQueueExclusiveHighReadWatermark(
 	category tasks.Category,
 ) tasks.Key {
@@ -379,11 +581,32 @@ func (s *ContextImpl) GetQueueState(
 	return queueState, ok
 }
 
+func (s *ContextImpl) GetShardInfoSize() int {
+	s.rLock()
+	defer s.rUnlock()
+
+	return s.serializedShardInfoSize(trimShardInfo(s.clusterMetadata.GetAllClusterInfo(), copyShardInfo(s.shardInfo)))
+}
+
+func (s *ContextImpl) serializedShardInfoSize(shardInfo *persistencespb.ShardInfo) int {
+	// serialize the same way as the shard manager does in UpdateShard
+	blob, err := s.payloadSerializer.ShardInfoToBlob(shardInfo, enums.ENCODING_TYPE_PROTO3)
+	if err != nil {
+		s.contextTaggedLogger.Error("Failed to serialize shard info", tag.Error(err))
+		return 0
+	}
+	return len(blob.Data)
+}
+
 func (s *ContextImpl) SetQueueState(
 	category tasks.Category,
 	tasksCompleted int,
 	state *persistencespb.QueueState,
 ) error {
+	if threshold := s.config.QueueStateCompactionThreshold(); threshold > 0 && countQueueStateScopes(state) > threshold {
+		compactQueueState(state)
+	}
+	s.queueDrainRate.record(category.ID(), tasksCompleted, s.timeSource.Now())
 	return s.updateShardInfo(tasksCompleted,
 		func() {
 			categoryID := category.ID()
@@ -391,6 +614,785 @@ func (s *ContextImpl) SetQueueState(
 		})
 }
 
+// RebuildQueueState recomputes the queue state of the given category from the history tasks
+// still pending in persistence, persists it immediately, and then unloads the shard so that
+// its queue processors restart from the rebuilt state instead of checkpointing over it.
+//
+// The rebuild is conservative: only the lowest pending task key is looked up, and the rebuilt
+// state has a single default reader whose scope covers everything from that key up to the
+// exclusive reader high watermark. Tasks in that range that were already completed but not yet
+// deleted from persistence will be executed again.
+func (s *ContextImpl) RebuildQueueState(
+	ctx context.Context,
+	category tasks.Category,
+) error {
+	if category.Type() != tasks.CategoryTypeImmediate && category.Type() != tasks.CategoryTypeScheduled {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf("unable to rebuild queue state for category type: %v", category.Type()))
+	}
+	if category == tasks.CategoryReplication {
+		return serviceerror.NewInvalidArgument("unable to rebuild replication queue state")
+	}
+
+	exclusiveReaderHighWatermark := s.GetQueueExclusiveHighReadWatermark(category)
+
+	// tasks are returned in key order, so a single task is enough to find the lowest pending task key
+	resp, err := s.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+		ShardID:             s.shardID,
+		TaskCategory:        category,
+		InclusiveMinTaskKey: tasks.MinimumKey,
+		ExclusiveMaxTaskKey: exclusiveReaderHighWatermark,
+		BatchSize:           1,
+	})
+	if err != nil {
+		return err
+	}
+
+	queueState := &persistencespb.QueueState{
+		ReaderStates:                 make(map[int64]*persistencespb.QueueReaderState),
+		ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(exclusiveReaderHighWatermark),
+	}
+	if len(resp.Tasks) != 0 {
+		minPendingTaskKey := resp.Tasks[0].GetKey()
+		if category.Type() == tasks.CategoryTypeScheduled {
+			minPendingTaskKey.TaskID = 0
+		}
+		queueState.ReaderStates[common.DefaultQueueReaderID] = &persistencespb.QueueReaderState{
+			Scopes: []*persistencespb.QueueSliceScope{{
+				Range: &persistencespb.QueueSliceRange{
+					InclusiveMin: ConvertToPersistenceTaskKey(minPendingTaskKey),
+					ExclusiveMax: ConvertToPersistenceTaskKey(exclusiveReaderHighWatermark),
+				},
+				Predicate: &persistencespb.Predicate{
+					PredicateType: enumsspb.PREDICATE_TYPE_UNIVERSAL,
+					Attributes:    &persistencespb.Predicate_UniversalPredicateAttributes{},
+				},
+			}},
+		}
+	}
+
+	err = s.flushShardInfo(0, func() error {
+		categoryID := int32(category.ID())
+		if currentState, ok := s.shardInfo.QueueStates[categoryID]; ok && currentState.ExclusiveReaderHighWatermark != nil {
+			currentHighWatermark := ConvertFromPersistenceTaskKey(currentState.ExclusiveReaderHighWatermark)
+			if currentHighWatermark.CompareTo(exclusiveReaderHighWatermark) > 0 {
+				return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+					"rebuilt queue state would regress exclusive reader high watermark from %v to %v",
+					currentHighWatermark,
+					exclusiveReaderHighWatermark,
+				))
+			}
+		}
+		s.shardInfo.QueueStates[categoryID] = queueState
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.contextTaggedLogger.Info("Rebuilt queue state from pending tasks, unloading shard",
+		tag.TaskCategoryID(category.ID()),
+		tag.NewInt("pending-tasks-found", len(resp.Tasks)),
+		tag.NewAnyTag("queue-state", queueState),
+	)
+	_ = s.transition(contextRequestStop{reason: stopReasonUnspecified})
+	return nil
+}
+
+func (s *ContextImpl) ExportQueueState() (*persistencespb.ShardInfo, error) {
+	if err := s.errorByState(); err != nil {
+		return nil, err
+	}
+
+	s.rLock()
+	defer s.rUnlock()
+
+	return copyShardInfo(s.shardInfo), nil
+}
+
+func (s *ContextImpl) RestoreQueueState(
+	snapshot *persistencespb.ShardInfo,
+) error {
+	if !s.config.ShardQueueStateRestore() {
+		return serviceerror.NewPermissionDenied("restoring shard queue state is disabled", "")
+	}
+	if snapshot == nil {
+		return serviceerror.NewInvalidArgument("shard queue state snapshot is nil")
+	}
+	if snapshot.GetShardId() != s.shardID {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf(
+			"shard queue state snapshot is for shard %v, not shard %v",
+			snapshot.GetShardId(),
+			s.shardID,
+		))
+	}
+	// Copy before taking the lock, the caller may still hold on to the snapshot.
+	restored := copyShardInfo(snapshot)
+	if restored.ReplicationDlqAckLevel == nil {
+		restored.ReplicationDlqAckLevel = make(map[string]int64)
+	}
+
+	err := s.flushShardInfo(0, func() error {
+		// A snapshot taken under a later range ID was written by an owner this shard doesn't
+		// know about yet. Its task IDs may not have been allocated under the current range,
+		// so restoring it could skip tasks written by either owner.
+		if restored.RangeId > s.shardInfo.RangeId {
+			return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+				"shard queue state snapshot range ID %v is ahead of current range ID %v",
+				restored.RangeId,
+				s.shardInfo.RangeId,
+			))
+		}
+		s.shardInfo.QueueStates = restored.QueueStates
+		s.shardInfo.ReplicationDlqAckLevel = restored.ReplicationDlqAckLevel
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.contextTaggedLogger.Info("Restored queue state from snapshot, unloading shard",
+		tag.NewInt64("snapshot-range-id", restored.RangeId),
+	)
+	_ = s.transition(contextRequestStop{reason: stopReasonUnspecified})
+	return nil
+}
+
+// CheckpointForRepair persists a checkpoint of the shard's queue and replication state as part of
+// the shard info, so that a repair can be undone with RollbackToCheckpoint. Checkpoints expire
+// after history.shardRepairCheckpointTTL, and only the latest history.shardRepairCheckpointMaxCount
+// are kept.
+func (s *ContextImpl) CheckpointForRepair() (string, error) {
+	var checkpointID string
+	err := s.flushShardInfo(0, func() error {
+		now := s.timeSource.Now()
+		checkpoints := s.unexpiredRepairCheckpointsLocked(now)
+		// Checkpoints are identified by their creation time, keep them unique and ordered even if
+		// the clock didn't move since the last one.
+		if len(checkpoints) > 0 {
+			if last := checkpoints[len(checkpoints)-1].UpdateTime.AsTime(); !now.After(last) {
+				now = last.Add(time.Nanosecond)
+			}
+		}
+
+		current := copyShardInfo(s.shardInfo)
+		checkpoint := &persistencespb.ShardInfo{
+			ShardId:                current.ShardId,
+			RangeId:                current.RangeId,
+			UpdateTime:             timestamppb.New(now),
+			ReplicationDlqAckLevel: current.ReplicationDlqAckLevel,
+			QueueStates:            current.QueueStates,
+		}
+		checkpoints = append(checkpoints, checkpoint)
+		if maxCount := max(s.config.ShardRepairCheckpointMaxCount(), 1); len(checkpoints) > maxCount {
+			checkpoints = checkpoints[len(checkpoints)-maxCount:]
+		}
+
+		s.shardInfo.RepairCheckpoints = checkpoints
+		checkpointID = repairCheckpointID(checkpoint)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s.contextTaggedLogger.Info("Checkpointed queue state for repair",
+		tag.NewStringTag("checkpoint-id", checkpointID),
+	)
+	return checkpointID, nil
+}
+
+// RollbackToCheckpoint overwrites the shard's queue and replication state with a checkpoint
+// taken by CheckpointForRepair, and unloads the shard so that its queues are reloaded from it.
+// The checkpoint is kept, so it can be rolled back to again until it expires.
+func (s *ContextImpl) RollbackToCheckpoint(
+	checkpointID string,
+) error {
+	var checkpoint *persistencespb.ShardInfo
+	err := s.flushShardInfo(0, func() error {
+		for _, c := range s.unexpiredRepairCheckpointsLocked(s.timeSource.Now()) {
+			if repairCheckpointID(c) == checkpointID {
+				checkpoint = c
+				break
+			}
+		}
+		if checkpoint == nil {
+			return serviceerror.NewNotFound(fmt.Sprintf("repair checkpoint %v not found or expired", checkpointID))
+		}
+		// Same as for RestoreQueueState, the checkpoint is only safe to restore if no task IDs
+		// were allocated by an owner this shard doesn't know about.
+		if checkpoint.RangeId > s.shardInfo.RangeId {
+			return serviceerror.NewFailedPrecondition(fmt.Sprintf(
+				"repair checkpoint range ID %v is ahead of current range ID %v",
+				checkpoint.RangeId,
+				s.shardInfo.RangeId,
+			))
+		}
+
+		restored := copyShardInfo(checkpoint)
+		if restored.ReplicationDlqAckLevel == nil {
+			restored.ReplicationDlqAckLevel = make(map[string]int64)
+		}
+		s.shardInfo.QueueStates = restored.QueueStates
+		s.shardInfo.ReplicationDlqAckLevel = restored.ReplicationDlqAckLevel
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.contextTaggedLogger.Info("Rolled back queue state to repair checkpoint, unloading shard",
+		tag.NewStringTag("checkpoint-id", checkpointID),
+		tag.NewInt64("checkpoint-range-id", checkpoint.RangeId),
+	)
+	_ = s.transition(contextRequestStop{reason: stopReasonUnspecified})
+	return nil
+}
+
+// unexpiredRepairCheckpointsLocked returns the repair checkpoints of the shard that haven't
+// expired yet, oldest first. The returned slice is a copy, the checkpoints themselves are never
+// modified once taken.
+func (s *ContextImpl) unexpiredRepairCheckpointsLocked(now time.Time) []*persistencespb.ShardInfo {
+	ttl := s.config.ShardRepairCheckpointTTL()
+	var checkpoints []*persistencespb.ShardInfo
+	for _, checkpoint := range s.shardInfo.RepairCheckpoints {
+		if checkpoint.UpdateTime.AsTime().Add(ttl).After(now) {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	return checkpoints
+}
+
+func repairCheckpointID(checkpoint *persistencespb.ShardInfo) string {
+	return strconv.FormatInt(checkpoint.UpdateTime.AsTime().UnixNano(), 10)
+}
+
+// GetTaskInfo reads a single pending task of an immediate category from persistence, so that a
+// stuck task can be tied to its workflow. Tasks of scheduled categories are keyed by fire time
+// and can't be looked up by task ID alone. Returns NotFound if the task was already completed.
+func (s *ContextImpl) GetTaskInfo(
+	category tasks.Category,
+	taskID int64,
+) (tasks.Task, error) {
+	if err := s.errorByState(); err != nil {
+		return nil, err
+	}
+	if category.Type() != tasks.CategoryTypeImmediate {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("unable to get task by ID for category: %v", category.Name()))
+	}
+
+	ctx, cancel := s.newIOContext()
+	defer cancel()
+
+	resp, err := s.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+		ShardID:             s.shardID,
+		TaskCategory:        category,
+		InclusiveMinTaskKey: tasks.NewImmediateKey(taskID),
+		ExclusiveMaxTaskKey: tasks.NewImmediateKey(taskID + 1),
+		BatchSize:           1,
+	})
+	if err = s.handleReadError(err); err != nil {
+		return nil, err
+	}
+	if len(resp.Tasks) == 0 {
+		return nil, serviceerror.NewNotFound(fmt.Sprintf("task %v not found in %v queue", taskID, category.Name()))
+	}
+	return resp.Tasks[0], nil
+}
+
+func (s *ContextImpl) OldestPendingTaskTime(
+	category tasks.Category,
+) (time.Time, bool) {
+	if err := s.errorByState(); err != nil {
+		return time.Time{}, false
+	}
+
+	s.rLock()
+	queueState, ok := s.shardInfo.QueueStates[int32(category.ID())]
+	var minTaskKey *tasks.Key
+	if ok {
+		minTaskKey = getMinTaskKey(queueState)
+	}
+	s.rUnlock()
+	if minTaskKey == nil {
+		return time.Time{}, false
+	}
+	exclusiveMaxTaskKey := s.GetQueueExclusiveHighReadWatermark(category)
+	if minTaskKey.CompareTo(exclusiveMaxTaskKey) >= 0 {
+		return time.Time{}, false
+	}
+
+	ctx, cancel := s.newIOContext()
+	defer cancel()
+
+	resp, err := s.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+		ShardID:             s.shardID,
+		TaskCategory:        category,
+		InclusiveMinTaskKey: *minTaskKey,
+		ExclusiveMaxTaskKey: exclusiveMaxTaskKey,
+		BatchSize:           1,
+	})
+	if err = s.handleReadError(err); err != nil {
+		s.contextTaggedLogger.Warn("Failed to read oldest pending task.", tag.TaskCategoryID(category.ID()), tag.Error(err))
+		return time.Time{}, false
+	}
+	if len(resp.Tasks) == 0 {
+		return time.Time{}, false
+	}
+	return resp.Tasks[0].GetVisibilityTime(), true
+}
+
+// EstimateQueueDrainTime divides the number of pending tasks of the category by the rate the
+// queue of the category completed tasks at recently. Pending tasks are counted from persistence,
+// between the lowest key the queue hasn't completed yet and its high read watermark, so tasks
+// that were completed out of order but aren't deleted yet are counted too. The estimate assumes
+// the processing rate stays the same and ignores tasks created in the meantime, so it's only a
+// rough indication, e.g. to plan maintenance.
+func (s *ContextImpl) EstimateQueueDrainTime(
+	category tasks.Category,
+) (time.Duration, error) {
+	depth, err := s.countPendingTasks(category)
+	if err != nil {
+		return 0, err
+	}
+	if depth == 0 {
+		return 0, nil
+	}
+
+	rate := s.queueDrainRate.rate(category.ID(), s.timeSource.Now())
+	if rate <= 0 {
+		return 0, serviceerror.NewFailedPrecondition(
+			fmt.Sprintf("queue of task category %v is not draining, %v tasks are pending", category.Name(), depth),
+		)
+	}
+	return time.Duration(float64(depth) / rate * float64(time.Second)), nil
+}
+
+func (s *ContextImpl) countPendingTasks(
+	category tasks.Category,
+) (int64, error) {
+	if err := s.errorByState(); err != nil {
+		return 0, err
+	}
+
+	s.rLock()
+	queueState, ok := s.shardInfo.QueueStates[int32(category.ID())]
+	var minTaskKey *tasks.Key
+	if ok {
+		minTaskKey = getMinTaskKey(queueState)
+	}
+	s.rUnlock()
+	if minTaskKey == nil {
+		return 0, nil
+	}
+	exclusiveMaxTaskKey := s.GetQueueExclusiveHighReadWatermark(category)
+	if minTaskKey.CompareTo(exclusiveMaxTaskKey) >= 0 {
+		return 0, nil
+	}
+
+	var depth int64
+	var pageToken []byte
+	for {
+		ctx, cancel := s.newIOContext()
+		resp, err := s.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+			ShardID:             s.shardID,
+			TaskCategory:        category,
+			InclusiveMinTaskKey: *minTaskKey,
+			ExclusiveMaxTaskKey: exclusiveMaxTaskKey,
+			BatchSize:           queueDepthPageSize,
+			NextPageToken:       pageToken,
+		})
+		cancel()
+		if err = s.handleReadError(err); err != nil {
+			return 0, err
+		}
+		depth += int64(len(resp.Tasks))
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			return depth, nil
+		}
+	}
+}
+
+// ScanForDuplicateTaskIDs reads up to limit pending tasks of the category, from the lowest key the
+// queue hasn't completed yet, and returns the IDs that more than one of them have, in ascending
+// order. Task IDs are unique per shard, so a duplicate points at a bug in task ID allocation or
+// serialization. It's an integrity check for operators and doesn't change anything.
+func (s *ContextImpl) ScanForDuplicateTaskIDs(
+	ctx context.Context,
+	category tasks.Category,
+	limit int,
+) ([]int64, error) {
+	if err := s.errorByState(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("invalid task scan limit: %v", limit))
+	}
+
+	inclusiveMinTaskKey := tasks.MinimumKey
+	s.rLock()
+	if queueState, ok := s.shardInfo.QueueStates[int32(category.ID())]; ok {
+		if minTaskKey := getMinTaskKey(queueState); minTaskKey != nil {
+			inclusiveMinTaskKey = *minTaskKey
+		}
+	}
+	s.rUnlock()
+
+	seen := make(map[int64]struct{})
+	duplicates := make(map[int64]struct{})
+	var pageToken []byte
+	for scanned := 0; scanned < limit; {
+		resp, err := s.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+			ShardID:             s.shardID,
+			TaskCategory:        category,
+			InclusiveMinTaskKey: inclusiveMinTaskKey,
+			ExclusiveMaxTaskKey: tasks.MaximumKey,
+			BatchSize:           min(limit-scanned, duplicateTaskScanPageSize),
+			NextPageToken:       pageToken,
+		})
+		if err = s.handleReadError(err); err != nil {
+			return nil, err
+		}
+		for _, task := range resp.Tasks[:min(len(resp.Tasks), limit-scanned)] {
+			if _, ok := seen[task.GetTaskID()]; ok {
+				duplicates[task.GetTaskID()] = struct{}{}
+			}
+			seen[task.GetTaskID()] = struct{}{}
+		}
+		scanned += len(resp.Tasks)
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	duplicateIDs := maps.Keys(duplicates)
+	slices.Sort(duplicateIDs)
+	if len(duplicateIDs) != 0 {
+		s.contextTaggedLogger.Warn("Found duplicate task IDs",
+			tag.TaskCategoryID(category.ID()),
+			tag.NewAnyTag("task-ids", duplicateIDs),
+		)
+	}
+	return duplicateIDs, nil
+}
+
+// UpcomingTimers reads the first n tasks of the timer queue, from the lowest key the queue hasn't
+// completed yet. Timer tasks are keyed by fire time, so they're read in the order they fire. Tasks
+// that were completed out of order but aren't deleted yet are included too.
+func (s *ContextImpl) UpcomingTimers(
+	ctx context.Context,
+	n int,
+) ([]TimerInfo, error) {
+	if err := s.errorByState(); err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("invalid timer count: %v", n))
+	}
+
+	inclusiveMinTaskKey := tasks.MinimumKey
+	s.rLock()
+	if queueState, ok := s.shardInfo.QueueStates[int32(tasks.CategoryTimer.ID())]; ok {
+		if minTaskKey := getMinTaskKey(queueState); minTaskKey != nil {
+			inclusiveMinTaskKey = *minTaskKey
+		}
+	}
+	s.rUnlock()
+
+	var timers []TimerInfo
+	var pageToken []byte
+	for len(timers) < n {
+		resp, err := s.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+			ShardID:             s.shardID,
+			TaskCategory:        tasks.CategoryTimer,
+			InclusiveMinTaskKey: inclusiveMinTaskKey,
+			ExclusiveMaxTaskKey: tasks.MaximumKey,
+			BatchSize:           min(n-len(timers), upcomingTimerScanPageSize),
+			NextPageToken:       pageToken,
+		})
+		if err = s.handleReadError(err); err != nil {
+			return nil, err
+		}
+		for _, task := range resp.Tasks[:min(len(resp.Tasks), n-len(timers))] {
+			timers = append(timers, TimerInfo{
+				WorkflowKey: definition.NewWorkflowKey(task.GetNamespaceID(), task.GetWorkflowID(), task.GetRunID()),
+				TaskType:    task.GetType(),
+				TaskID:      task.GetTaskID(),
+				FireTime:    task.GetVisibilityTime(),
+			})
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	return timers, nil
+}
+
+// EstimateLoad scans the workflow executions of the shard, up to ShardLoadEstimateMaxExecutions,
+// and returns how many there are and how much history and mutable state they hold. The result is
+// reused for ShardLoadEstimateTTL, so it can be stale by that much. Sizes are as recorded by the
+// executions and serialized by this host, not as stored by persistence, so they are estimates.
+func (s *ContextImpl) EstimateLoad(
+	ctx context.Context,
+) (ShardLoadEstimate, error) {
+	if err := s.errorByState(); err != nil {
+		return ShardLoadEstimate{}, err
+	}
+
+	now := s.timeSource.Now()
+	if last := s.lastLoadEstimate.Load(); last != nil && now.Sub(last.EstimatedAt) < s.config.ShardLoadEstimateTTL() {
+		return *last, nil
+	}
+
+	maxExecutions := s.config.ShardLoadEstimateMaxExecutions()
+	estimate := ShardLoadEstimate{EstimatedAt: now}
+	var pageToken []byte
+	for {
+		if estimate.ExecutionCount >= int64(maxExecutions) {
+			break
+		}
+		resp, err := s.executionManager.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			ShardID:   s.shardID,
+			PageSize:  min(maxExecutions-int(estimate.ExecutionCount), loadEstimatePageSize),
+			PageToken: pageToken,
+		})
+		if err = s.handleReadError(err); err != nil {
+			return ShardLoadEstimate{}, err
+		}
+		for _, state := range resp.States[:min(len(resp.States), maxExecutions-int(estimate.ExecutionCount))] {
+			estimate.ExecutionCount++
+			estimate.HistorySizeBytes += state.GetExecutionInfo().GetExecutionStats().GetHistorySize()
+			estimate.MutableStateSizeBytes += int64(proto.Size(state))
+		}
+		pageToken = resp.PageToken
+		if len(pageToken) == 0 {
+			estimate.Complete = true
+			break
+		}
+	}
+
+	s.lastLoadEstimate.Store(&estimate)
+	return estimate, nil
+}
+
+// ListExecutionsWithPendingSignals scans the workflow executions of the shard and returns up to
+// limit of them that have signals buffered but not yet delivered to the workflow, e.g. to find
+// workflows that are stuck without a workflow task. It's read-only.
+func (s *ContextImpl) ListExecutionsWithPendingSignals(
+	ctx context.Context,
+	limit int,
+) ([]definition.WorkflowKey, error) {
+	if err := s.errorByState(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("invalid execution scan limit: %v", limit))
+	}
+
+	var workflowKeys []definition.WorkflowKey
+	var pageToken []byte
+	for len(workflowKeys) < limit {
+		resp, err := s.executionManager.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			ShardID:   s.shardID,
+			PageSize:  loadEstimatePageSize,
+			PageToken: pageToken,
+		})
+		if err = s.handleReadError(err); err != nil {
+			return nil, err
+		}
+		for _, state := range resp.States {
+			if len(workflowKeys) == limit {
+				break
+			}
+			if hasBufferedSignal(state) {
+				workflowKeys = append(workflowKeys, definition.NewWorkflowKey(
+					state.GetExecutionInfo().GetNamespaceId(),
+					state.GetExecutionInfo().GetWorkflowId(),
+					state.GetExecutionState().GetRunId(),
+				))
+			}
+		}
+		pageToken = resp.PageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	return workflowKeys, nil
+}
+
+func hasBufferedSignal(state *persistencespb.WorkflowMutableState) bool {
+	for _, event := range state.GetBufferedEvents() {
+		if event.GetEventType() == enums.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED {
+			return true
+		}
+	}
+	return false
+}
+
+// DryRunTask loads a pending task of an immediate queue and has the engine execute it against a
+// copy of the shard that captures the workflow writes and RPCs of the execution in a trace. The
+// copy rejects all other writes, to the shard or directly to persistence, so nothing is persisted:
+// the mutable state of the workflow is loaded into a separate cache and discarded afterwards, and
+// the shard's queues are not notified of the generated tasks.
+func (s *ContextImpl) DryRunTask(
+	ctx context.Context,
+	category tasks.Category,
+	taskID int64,
+) (TaskExecutionTrace, error) {
+	task, err := s.GetTaskInfo(category, taskID)
+	if err != nil {
+		return TaskExecutionTrace{}, err
+	}
+	engine, err := s.GetEngine(ctx)
+	if err != nil {
+		return TaskExecutionTrace{}, err
+	}
+	trace := &TaskExecutionTrace{
+		Task:           task,
+		GeneratedTasks: make(map[tasks.Category][]tasks.Task),
+	}
+	if err := engine.DryRunTask(ctx, newDryRunContext(s, engine, trace), task, trace); err != nil {
+		return TaskExecutionTrace{}, err
+	}
+	return *trace, nil
+}
+
+// ValidateVersionHistory reads a workflow execution and returns the defects of its version
+// histories, e.g. items that are not ordered by event ID and version, or branch tokens that don't
+// belong to the same history tree. It's an on-demand integrity check for repairs and doesn't
+// modify the execution. An execution without defects returns an empty result.
+func (s *ContextImpl) ValidateVersionHistory(
+	ctx context.Context,
+	workflowKey definition.WorkflowKey,
+) ([]VersionHistoryDefect, error) {
+	if workflowKey.NamespaceID == "" || workflowKey.WorkflowID == "" || workflowKey.RunID == "" {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("incomplete workflow key: %v", workflowKey))
+	}
+	resp, err := s.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: workflowKey.NamespaceID,
+		WorkflowID:  workflowKey.WorkflowID,
+		RunID:       workflowKey.RunID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validateVersionHistories(resp.State, s.executionManager.GetHistoryBranchUtil()), nil
+}
+
+// ForceCompleteTask marks a pending task of an immediate queue as completed without executing it,
+// so that a task that can't be processed no longer holds back the ack level of the queue. It's a
+// break glass for operators: the task is logged with the given reason and, if dlqWriter is not
+// nil, written to the DLQ before it's completed.
+//
+// The task is removed from the reader scopes of the persisted queue state, and the shard is
+// unloaded so that the queue reloads the updated state, like RebuildQueueState. Only tasks the
+// queue has already read can be completed, i.e. the task must be below the exclusive reader high
+// watermark of the queue.
+func (s *ContextImpl) ForceCompleteTask(
+	ctx context.Context,
+	category tasks.Category,
+	taskID int64,
+	reason string,
+	dlqWriter TaskDLQWriter,
+) error {
+	if category == tasks.CategoryReplication {
+		return serviceerror.NewInvalidArgument("unable to force complete replication task")
+	}
+	task, err := s.GetTaskInfo(category, taskID)
+	if err != nil {
+		return err
+	}
+
+	categoryID := int32(category.ID())
+	taskKey := task.GetKey()
+	updateQueueStateLocked := func() (*persistencespb.QueueState, error) {
+		queueState, ok := s.shardInfo.QueueStates[categoryID]
+		if !ok || queueState.ExclusiveReaderHighWatermark == nil {
+			return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf("%v queue has no state to update", category.Name()))
+		}
+		highWatermark := ConvertFromPersistenceTaskKey(queueState.ExclusiveReaderHighWatermark)
+		if taskKey.CompareTo(highWatermark) >= 0 {
+			return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf(
+				"task %v is not below the exclusive reader high watermark %v of %v queue",
+				taskKey,
+				highWatermark,
+				category.Name(),
+			))
+		}
+		queueState, removed := removeTaskFromQueueState(queueState, taskKey)
+		if !removed {
+			return nil, serviceerror.NewFailedPrecondition(fmt.Sprintf("task %v in %v queue is already completed", taskID, category.Name()))
+		}
+		return queueState, nil
+	}
+
+	// check before writing to DLQ, the check is repeated when the queue state is updated
+	s.rLock()
+	_, err = updateQueueStateLocked()
+	s.rUnlock()
+	if err != nil {
+		return err
+	}
+
+	logger := log.With(s.contextTaggedLogger,
+		tag.TaskCategoryID(category.ID()),
+		tag.TaskID(taskID),
+		tag.TaskType(task.GetType()),
+		tag.WorkflowNamespaceID(task.GetNamespaceID()),
+		tag.WorkflowID(task.GetWorkflowID()),
+		tag.WorkflowRunID(task.GetRunID()),
+		tag.NewStringTag("reason", reason),
+	)
+	if dlqWriter != nil {
+		currentClusterName := s.clusterMetadata.GetCurrentClusterName()
+		if err := dlqWriter.WriteTaskToDLQ(ctx, currentClusterName, currentClusterName, task); err != nil {
+			logger.Error("Failed to write force completed task to DLQ", tag.Error(err))
+			return err
+		}
+	}
+
+	err = s.flushShardInfo(0, func() error {
+		queueState, err := updateQueueStateLocked()
+		if err != nil {
+			return err
+		}
+		s.shardInfo.QueueStates[categoryID] = queueState
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Warn("Force completed task, unloading shard", tag.NewBoolTag("written-to-dlq", dlqWriter != nil))
+	_ = s.transition(contextRequestStop{reason: stopReasonUnspecified})
+	return nil
+}
+
+// GetNamespaceFailoverVersion returns the failover version of the given namespace as seen by this
+// shard. The version is read under the shard lock, so it is ordered with respect to NewVectorClock
+// and namespace handover updates, and it fails with ErrNamespaceHandover while the namespace is
+// being handed over, as its failover version is about to change.
+func (s *ContextImpl) GetNamespaceFailoverVersion(
+	namespaceID namespace.ID,
+) (int64, error) {
+	// do not try to get namespace cache within shard lock
+	namespaceEntry, err := s.GetNamespaceRegistry().GetNamespaceByID(namespaceID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.rLock()
+	defer s.rUnlock()
+
+	if err := s.errorByNamespaceStateLocked(namespaceEntry.Name()); err != nil {
+		return 0, err
+	}
+	return namespaceEntry.FailoverVersion(), nil
+}
+
 func (s *ContextImpl) UpdateReplicationQueueReaderState(
 	readerID int64,
 	readerState *persistencespb.QueueReaderState,
@@ -446,13 +1448,73 @@ func (s *ContextImpl) UpdateRemoteReaderInfo(
 		return serviceerror.NewInternal(fmt.Sprintf("unknown cluster ID: %v", clusterID))
 	}
 
-	s.wLock()
-	defer s.wUnlock()
+	s.wLock()
+	defer s.wUnlock()
+
+	remoteClusterInfo := s.getOrUpdateRemoteClusterInfoLocked(clusterName)
+	remoteClusterInfo.AckedReplicationTaskIDs[shardID] = ackTaskID
+	remoteClusterInfo.AckedReplicationTimestamps[shardID] = ackTimestamp
+	return nil
+}
+
+// UpdateAckedReplicationWatermark records that this shard has applied the replication tasks of
+// a shard of the source cluster up to ackTaskID. The watermark only moves forward.
+func (s *ContextImpl) UpdateAckedReplicationWatermark(
+	sourceCluster string,
+	sourceShardID int32,
+	ackTaskID int64,
+	ackTimestamp time.Time,
+) {
+	s.wLock()
+	defer s.wUnlock()
+
+	remoteClusterInfo := s.getOrUpdateRemoteClusterInfoLocked(sourceCluster)
+	if remoteClusterInfo.AppliedReplicationTaskIDs == nil {
+		remoteClusterInfo.AppliedReplicationTaskIDs = make(map[int32]int64)
+	}
+	if remoteClusterInfo.AppliedReplicationTimestamps == nil {
+		remoteClusterInfo.AppliedReplicationTimestamps = make(map[int32]time.Time)
+	}
+	if taskID, ok := remoteClusterInfo.AppliedReplicationTaskIDs[sourceShardID]; ok && taskID > ackTaskID {
+		return
+	}
+	remoteClusterInfo.AppliedReplicationTaskIDs[sourceShardID] = ackTaskID
+	if ackTimestamp.After(remoteClusterInfo.AppliedReplicationTimestamps[sourceShardID]) {
+		remoteClusterInfo.AppliedReplicationTimestamps[sourceShardID] = ackTimestamp
+	}
+}
+
+// GetAckedReplicationWatermark returns the ID and visibility time of the last replication task
+// from the source cluster applied by this shard, for the source cluster to trim its replication
+// queue. If this shard receives from more than one shard of the source cluster, it's the lowest
+// of them. It's persistence.EmptyQueueMessageID if no task has been applied since the shard was
+// loaded, as the watermark is only kept in memory.
+func (s *ContextImpl) GetAckedReplicationWatermark(
+	sourceCluster string,
+) (int64, time.Time) {
+	s.rLock()
+	defer s.rUnlock()
 
-	remoteClusterInfo := s.getOrUpdateRemoteClusterInfoLocked(clusterName)
-	remoteClusterInfo.AckedReplicationTaskIDs[shardID] = ackTaskID
-	remoteClusterInfo.AckedReplicationTimestamps[shardID] = ackTimestamp
-	return nil
+	return s.getAckedReplicationWatermarkLocked(sourceCluster)
+}
+
+func (s *ContextImpl) getAckedReplicationWatermarkLocked(
+	sourceCluster string,
+) (int64, time.Time) {
+	remoteClusterInfo, ok := s.remoteClusterInfos[sourceCluster]
+	if !ok || len(remoteClusterInfo.AppliedReplicationTaskIDs) == 0 {
+		return persistence.EmptyQueueMessageID, time.Time{}
+	}
+	ackTaskID := int64(math.MaxInt64)
+	var ackTimestamp time.Time
+	for sourceShardID, taskID := range remoteClusterInfo.AppliedReplicationTaskIDs {
+		ackTaskID = min(ackTaskID, taskID)
+		timestamp := remoteClusterInfo.AppliedReplicationTimestamps[sourceShardID]
+		if ackTimestamp.IsZero() || timestamp.Before(ackTimestamp) {
+			ackTimestamp = timestamp
+		}
+	}
+	return ackTaskID, ackTimestamp
 }
 
 func (s *ContextImpl) GetReplicatorDLQAckLevel(sourceCluster string) int64 {
@@ -484,6 +1546,219 @@ func (s *ContextImpl) UpdateReplicatorDLQAckLevel(
 	return nil
 }
 
+// GetReplicationDLQDepth returns the number of replication tasks from sourceCluster in the DLQ above
+// its ack level, i.e. the ones that were neither merged nor purged yet. Task IDs are allocated
+// for all task categories of the shard, so they are sparse and the depth can't be derived from
+// the highest task ID. The tasks are counted instead, a page at a time.
+func (s *ContextImpl) GetReplicationDLQDepth(
+	sourceCluster string,
+) (int64, error) {
+	if err := s.errorByState(); err != nil {
+		return 0, err
+	}
+
+	ackLevel := s.GetReplicatorDLQAckLevel(sourceCluster)
+	var depth int64
+	var pageToken []byte
+	for {
+		ctx, cancel := s.newIOContext()
+		resp, err := s.executionManager.GetReplicationTasksFromDLQ(ctx, &persistence.GetReplicationTasksFromDLQRequest{
+			GetHistoryTasksRequest: persistence.GetHistoryTasksRequest{
+				ShardID:             s.shardID,
+				TaskCategory:        tasks.CategoryReplication,
+				InclusiveMinTaskKey: tasks.NewImmediateKey(ackLevel + 1),
+				ExclusiveMaxTaskKey: tasks.NewImmediateKey(math.MaxInt64),
+				BatchSize:           replicationDLQDepthPageSize,
+				NextPageToken:       pageToken,
+			},
+			SourceClusterName: sourceCluster,
+		})
+		cancel()
+		if err != nil {
+			return 0, err
+		}
+		depth += int64(len(resp.Tasks))
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			return depth, nil
+		}
+	}
+}
+
+// PauseReplicationFrom makes the replication task processor of sourceCluster stop applying its
+// tasks on this shard, e.g. while that cluster is sending bad tasks, without affecting other
+// source clusters. Tasks are not acked while paused, so they are fetched again once resumed.
+func (s *ContextImpl) PauseReplicationFrom(
+	sourceCluster string,
+) error {
+	return s.setReplicationPausedFrom(sourceCluster, true)
+}
+
+// ResumeReplicationFrom undoes PauseReplicationFrom.
+func (s *ContextImpl) ResumeReplicationFrom(
+	sourceCluster string,
+) error {
+	return s.setReplicationPausedFrom(sourceCluster, false)
+}
+
+func (s *ContextImpl) setReplicationPausedFrom(
+	sourceCluster string,
+	paused bool,
+) error {
+	if _, ok := s.clusterMetadata.GetAllClusterInfo()[sourceCluster]; !ok {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf("unknown source cluster: %v", sourceCluster))
+	}
+
+	err := s.flushShardInfo(0, func() error {
+		sources := s.shardInfo.PausedReplicationSources
+		idx, found := slices.BinarySearch(sources, sourceCluster)
+		switch {
+		case paused && !found:
+			s.shardInfo.PausedReplicationSources = slices.Insert(slices.Clone(sources), idx, sourceCluster)
+		case !paused && found:
+			s.shardInfo.PausedReplicationSources = slices.Delete(slices.Clone(sources), idx, idx+1)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if paused {
+		s.contextTaggedLogger.Warn("Paused replication from source cluster", tag.SourceCluster(sourceCluster))
+		metrics.ReplicationSourcePaused.With(s.metricsHandler).Record(1, metrics.SourceClusterTag(sourceCluster))
+	} else {
+		s.contextTaggedLogger.Info("Resumed replication from source cluster", tag.SourceCluster(sourceCluster))
+		metrics.ReplicationSourcePaused.With(s.metricsHandler).Record(0, metrics.SourceClusterTag(sourceCluster))
+	}
+	return nil
+}
+
+func (s *ContextImpl) IsReplicationPausedFrom(sourceCluster string) bool {
+	s.rLock()
+	defer s.rUnlock()
+
+	_, found := slices.BinarySearch(s.shardInfo.PausedReplicationSources, sourceCluster)
+	return found
+}
+
+func (s *ContextImpl) GetPausedReplicationSources() []string {
+	s.rLock()
+	defer s.rUnlock()
+
+	return slices.Clone(s.shardInfo.PausedReplicationSources)
+}
+
+// QuarantineWorkflow makes the task queues of the shard hold the tasks of the workflow instead of
+// executing them: they stay pending and are retried with backoff, so they're processed once the
+// workflow is unquarantined. Quarantining a workflow again replaces its reason.
+func (s *ContextImpl) QuarantineWorkflow(
+	workflowKey definition.WorkflowKey,
+	reason string,
+) error {
+	if workflowKey.NamespaceID == "" || workflowKey.WorkflowID == "" || workflowKey.RunID == "" {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf("incomplete workflow key: %v", workflowKey))
+	}
+
+	err := s.flushShardInfo(0, func() error {
+		quarantined := s.shardInfo.QuarantinedWorkflows
+		entry := &persistencespb.QuarantinedWorkflow{
+			NamespaceId:    workflowKey.NamespaceID,
+			WorkflowId:     workflowKey.WorkflowID,
+			RunId:          workflowKey.RunID,
+			Reason:         reason,
+			QuarantineTime: timestamppb.New(s.timeSource.Now()),
+		}
+		idx, found := slices.BinarySearchFunc(quarantined, workflowKey, compareQuarantinedWorkflow)
+		if found {
+			s.shardInfo.QuarantinedWorkflows = slices.Clone(quarantined)
+			s.shardInfo.QuarantinedWorkflows[idx] = entry
+		} else {
+			s.shardInfo.QuarantinedWorkflows = slices.Insert(slices.Clone(quarantined), idx, entry)
+		}
+		s.publishQuarantinedWorkflowsLocked()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.contextTaggedLogger.Warn("Quarantined workflow",
+		tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+		tag.WorkflowID(workflowKey.WorkflowID),
+		tag.WorkflowRunID(workflowKey.RunID),
+		tag.NewStringTag("reason", reason),
+	)
+	return nil
+}
+
+// UnquarantineWorkflow undoes QuarantineWorkflow. The held tasks of the workflow are processed
+// on their next retry.
+func (s *ContextImpl) UnquarantineWorkflow(
+	workflowKey definition.WorkflowKey,
+) error {
+	err := s.flushShardInfo(0, func() error {
+		quarantined := s.shardInfo.QuarantinedWorkflows
+		idx, found := slices.BinarySearchFunc(quarantined, workflowKey, compareQuarantinedWorkflow)
+		if found {
+			s.shardInfo.QuarantinedWorkflows = slices.Delete(slices.Clone(quarantined), idx, idx+1)
+			s.publishQuarantinedWorkflowsLocked()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.contextTaggedLogger.Info("Unquarantined workflow",
+		tag.WorkflowNamespaceID(workflowKey.NamespaceID),
+		tag.WorkflowID(workflowKey.WorkflowID),
+		tag.WorkflowRunID(workflowKey.RunID),
+	)
+	return nil
+}
+
+// IsWorkflowQuarantined is called for every task the shard executes, so it reads the published
+// quarantined workflows instead of taking the shard lock.
+func (s *ContextImpl) IsWorkflowQuarantined(workflowKey definition.WorkflowKey) bool {
+	quarantined := s.quarantinedWorkflows.Load()
+	if quarantined == nil || len(*quarantined) == 0 {
+		return false
+	}
+	_, found := slices.BinarySearchFunc(*quarantined, workflowKey, compareQuarantinedWorkflow)
+	return found
+}
+
+// publishQuarantinedWorkflowsLocked publishes shardInfo.QuarantinedWorkflows to
+// IsWorkflowQuarantined. The published slice is never modified, QuarantinedWorkflows is replaced
+// by a copy whenever it changes.
+func (s *ContextImpl) publishQuarantinedWorkflowsLocked() {
+	quarantined := s.shardInfo.GetQuarantinedWorkflows()
+	s.quarantinedWorkflows.Store(&quarantined)
+}
+
+func (s *ContextImpl) ListQuarantinedWorkflows() []*persistencespb.QuarantinedWorkflow {
+	s.rLock()
+	defer s.rUnlock()
+
+	quarantined := make([]*persistencespb.QuarantinedWorkflow, 0, len(s.shardInfo.QuarantinedWorkflows))
+	for _, entry := range s.shardInfo.QuarantinedWorkflows {
+		quarantined = append(quarantined, common.CloneProto(entry))
+	}
+	return quarantined
+}
+
+func compareQuarantinedWorkflow(
+	entry *persistencespb.QuarantinedWorkflow,
+	workflowKey definition.WorkflowKey,
+) int {
+	return cmp.Or(
+		cmp.Compare(entry.GetNamespaceId(), workflowKey.NamespaceID),
+		cmp.Compare(entry.GetWorkflowId(), workflowKey.WorkflowID),
+		cmp.Compare(entry.GetRunId(), workflowKey.RunID),
+	)
+}
+
 func (s *ContextImpl) UpdateHandoverNamespace(ns *namespace.Namespace, deletedFromDb bool) {
 	nsName := ns.Name()
 	// NOTE: replication state field won't be replicated and currently we only update a namespace
@@ -530,6 +1805,28 @@ func (s *ContextImpl) UpdateHandoverNamespace(ns *namespace.Namespace, deletedFr
 	}
 }
 
+func (s *ContextImpl) ListHandoverNamespaces() []namespace.ID {
+	s.rLock()
+	names := make([]namespace.Name, 0, len(s.handoverNamespaces))
+	for nsName := range s.handoverNamespaces {
+		names = append(names, nsName)
+	}
+	s.rUnlock()
+
+	// do not try to get namespace cache within shard lock
+	ids := make([]namespace.ID, 0, len(names))
+	for _, nsName := range names {
+		nsID, err := s.GetNamespaceRegistry().GetNamespaceID(nsName)
+		if err != nil {
+			// the namespace was deleted, it will be removed from handoverNamespaces soon
+			continue
+		}
+		ids = append(ids, nsID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
 func (s *ContextImpl) AddTasks(
 	ctx context.Context,
 	request *persistence.AddHistoryTasksRequest,
@@ -565,14 +1862,33 @@ func (s *ContextImpl) AddSpeculativeWorkflowTaskTimeoutTask(
 	}
 
 	engine.NotifyNewTasks(map[tasks.Category][]tasks.Task{task.GetCategory(): []tasks.Task{task}})
+	s.speculativeTasks.add(task, s.timeSource.Now())
 
 	return nil
 }
 
+func (s *ContextImpl) DrainSpeculativeTasks() int {
+	drained := s.cancelSpeculativeTasks()
+	metrics.SpeculativeWorkflowTaskTimeoutTasksDrained.With(s.metricsHandler).Record(int64(drained))
+	return drained
+}
+
+func (s *ContextImpl) cancelSpeculativeTasks() int {
+	pending := s.speculativeTasks.drain(s.timeSource.Now())
+	for _, task := range pending {
+		// cancelled tasks are not submitted by the memory timer queue
+		task.Cancel()
+	}
+	return len(pending)
+}
+
 func (s *ContextImpl) CreateWorkflowExecution(
 	ctx context.Context,
 	request *persistence.CreateWorkflowExecutionRequest,
 ) (*persistence.CreateWorkflowExecutionResponse, error) {
+	if err := s.errorByMaintenanceMode(); err != nil {
+		return nil, err
+	}
 
 	// do not try to get namespace cache within shard lock
 	namespaceID := namespace.ID(request.NewWorkflowSnapshot.ExecutionInfo.NamespaceId)
@@ -606,7 +1922,7 @@ func (s *ContextImpl) CreateWorkflowExecution(
 		return nil, err
 	}
 
-	requestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(
+	requestCompletionFn, err := s.setAndTrackTaskKeysLocked(
 		request.NewWorkflowSnapshot.Tasks,
 	)
 	if err != nil {
@@ -621,10 +1937,14 @@ func (s *ContextImpl) CreateWorkflowExecution(
 	s.wUnlock()
 	resp, err := s.executionManager.CreateWorkflowExecution(ctx, request)
 	requestCompletionFn(err)
+	if request.Mode != persistence.CreateWorkflowModeBypassCurrent {
+		s.currentExecutionCache.invalidate(namespaceID.String(), request.NewWorkflowSnapshot.ExecutionInfo.WorkflowId)
+	}
 
 	if err = s.handleWriteError(request.RangeID, err); err != nil {
 		return nil, err
 	}
+	s.recordTaskGeneration(request.NewWorkflowSnapshot.ExecutionInfo, request.NewWorkflowSnapshot.Tasks)
 	return resp, nil
 }
 
@@ -632,6 +1952,10 @@ func (s *ContextImpl) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *persistence.UpdateWorkflowExecutionRequest,
 ) (*persistence.UpdateWorkflowExecutionResponse, error) {
+	if err := s.errorByMaintenanceMode(); err != nil {
+		return nil, err
+	}
+
 	// do not try to get namespace cache within shard lock
 	namespaceID := namespace.ID(request.UpdateWorkflowMutation.ExecutionInfo.NamespaceId)
 	namespaceEntry, err := s.GetNamespaceRegistry().GetNamespaceByID(namespaceID)
@@ -669,7 +1993,7 @@ func (s *ContextImpl) UpdateWorkflowExecution(
 	if request.NewWorkflowSnapshot != nil {
 		taskMaps = append(taskMaps, request.NewWorkflowSnapshot.Tasks)
 	}
-	requestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(taskMaps...)
+	requestCompletionFn, err := s.setAndTrackTaskKeysLocked(taskMaps...)
 	if err != nil {
 		s.wUnlock()
 		return nil, err
@@ -684,9 +2008,16 @@ func (s *ContextImpl) UpdateWorkflowExecution(
 
 	resp, err := s.executionManager.UpdateWorkflowExecution(ctx, request)
 	requestCompletionFn(err)
+	if request.Mode != persistence.UpdateWorkflowModeBypassCurrent {
+		s.currentExecutionCache.invalidate(namespaceID.String(), request.UpdateWorkflowMutation.ExecutionInfo.WorkflowId)
+	}
 	if err = s.handleWriteError(request.RangeID, err); err != nil {
 		return nil, err
 	}
+	s.recordTaskGeneration(request.UpdateWorkflowMutation.ExecutionInfo, request.UpdateWorkflowMutation.Tasks)
+	if request.NewWorkflowSnapshot != nil {
+		s.recordTaskGeneration(request.NewWorkflowSnapshot.ExecutionInfo, request.NewWorkflowSnapshot.Tasks)
+	}
 	return resp, nil
 }
 
@@ -709,6 +2040,10 @@ func (s *ContextImpl) ConflictResolveWorkflowExecution(
 	ctx context.Context,
 	request *persistence.ConflictResolveWorkflowExecutionRequest,
 ) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
+	if err := s.errorByMaintenanceMode(); err != nil {
+		return nil, err
+	}
+
 	// do not try to get namespace cache within shard lock
 	namespaceID := namespace.ID(request.ResetWorkflowSnapshot.ExecutionInfo.NamespaceId)
 	namespaceEntry, err := s.GetNamespaceRegistry().GetNamespaceByID(namespaceID)
@@ -750,7 +2085,7 @@ func (s *ContextImpl) ConflictResolveWorkflowExecution(
 		taskMaps = append(taskMaps, request.NewWorkflowSnapshot.Tasks)
 	}
 
-	requestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(taskMaps...)
+	requestCompletionFn, err := s.setAndTrackTaskKeysLocked(taskMaps...)
 	if err != nil {
 		s.wUnlock()
 		return nil, err
@@ -761,9 +2096,19 @@ func (s *ContextImpl) ConflictResolveWorkflowExecution(
 
 	resp, err := s.executionManager.ConflictResolveWorkflowExecution(ctx, request)
 	requestCompletionFn(err)
+	if request.Mode != persistence.ConflictResolveWorkflowModeBypassCurrent {
+		s.currentExecutionCache.invalidate(namespaceID.String(), request.ResetWorkflowSnapshot.ExecutionInfo.WorkflowId)
+	}
 	if err = s.handleWriteError(request.RangeID, err); err != nil {
 		return nil, err
 	}
+	if request.CurrentWorkflowMutation != nil {
+		s.recordTaskGeneration(request.CurrentWorkflowMutation.ExecutionInfo, request.CurrentWorkflowMutation.Tasks)
+	}
+	s.recordTaskGeneration(request.ResetWorkflowSnapshot.ExecutionInfo, request.ResetWorkflowSnapshot.Tasks)
+	if request.NewWorkflowSnapshot != nil {
+		s.recordTaskGeneration(request.NewWorkflowSnapshot.ExecutionInfo, request.NewWorkflowSnapshot.Tasks)
+	}
 	return resp, nil
 }
 
@@ -771,6 +2116,10 @@ func (s *ContextImpl) SetWorkflowExecution(
 	ctx context.Context,
 	request *persistence.SetWorkflowExecutionRequest,
 ) (*persistence.SetWorkflowExecutionResponse, error) {
+	if err := s.errorByMaintenanceMode(); err != nil {
+		return nil, err
+	}
+
 	// do not try to get namespace cache within shard lock
 	namespaceID := namespace.ID(request.SetWorkflowSnapshot.ExecutionInfo.NamespaceId)
 	namespaceEntry, err := s.GetNamespaceRegistry().GetNamespaceByID(namespaceID)
@@ -803,7 +2152,7 @@ func (s *ContextImpl) SetWorkflowExecution(
 		return nil, err
 	}
 
-	snapShotRequestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(
+	snapShotRequestCompletionFn, err := s.setAndTrackTaskKeysLocked(
 		request.SetWorkflowSnapshot.Tasks,
 	)
 	if err != nil {
@@ -819,17 +2168,64 @@ func (s *ContextImpl) SetWorkflowExecution(
 	if err = s.handleWriteError(request.RangeID, err); err != nil {
 		return nil, err
 	}
+	s.recordTaskGeneration(request.SetWorkflowSnapshot.ExecutionInfo, request.SetWorkflowSnapshot.Tasks)
 	return resp, nil
 }
 
+func (s *ContextImpl) recordTaskGeneration(
+	executionInfo *persistencespb.WorkflowExecutionInfo,
+	tasksByCategory map[tasks.Category][]tasks.Task,
+) {
+	taskCount := 0
+	for _, categoryTasks := range tasksByCategory {
+		taskCount += len(categoryTasks)
+	}
+	s.taskGenerationHeatmap.record(executionInfo.GetWorkflowTypeName(), taskCount, s.timeSource.Now())
+}
+
+// TaskGenerationByWorkflowType returns the number of tasks generated by the workflow writes of the
+// shard in the last window per workflow type, for the workflow types with the most tasks, up to
+// history.shardTaskGenerationHeatmapTopK of them. Tasks added with AddTasks aren't counted, as they
+// aren't attributed to a workflow type. Counts are kept per minute for up to
+// history.shardTaskGenerationHeatmapRetention, and only for about the top workflow types of each
+// minute, so they are approximate and meant for capacity analysis.
+func (s *ContextImpl) TaskGenerationByWorkflowType(window time.Duration) map[string]int64 {
+	return s.taskGenerationHeatmap.counts(window, s.timeSource.Now())
+}
+
 func (s *ContextImpl) GetCurrentExecution(
 	ctx context.Context,
 	request *persistence.GetCurrentExecutionRequest,
+) (*persistence.GetCurrentExecutionResponse, error) {
+	return s.GetCurrentExecutionWithConsistency(ctx, request, ReadConsistencyStrong)
+}
+
+func (s *ContextImpl) GetCurrentExecutionWithConsistency(
+	ctx context.Context,
+	request *persistence.GetCurrentExecutionRequest,
+	consistency ReadConsistency,
 ) (*persistence.GetCurrentExecutionResponse, error) {
 	if err := s.errorByState(); err != nil {
 		return nil, err
 	}
 
+	if consistency == ReadConsistencyEventual {
+		cached, generation := s.currentExecutionCache.get(request.NamespaceID, request.WorkflowID)
+		if cached != nil {
+			return cached, nil
+		}
+		resp, err := s.executionManager.GetCurrentExecution(ctx, request)
+		if err != nil {
+			s.currentExecutionCache.put(request.NamespaceID, request.WorkflowID, generation, nil)
+		} else {
+			s.currentExecutionCache.put(request.NamespaceID, request.WorkflowID, generation, resp)
+		}
+		if err = s.handleReadError(err); err != nil {
+			return resp, err
+		}
+		return resp, nil
+	}
+
 	resp, err := s.executionManager.GetCurrentExecution(ctx, request)
 	if err = s.handleReadError(err); err != nil {
 		// also return resp, for RebuildMutableState API
@@ -845,6 +2241,9 @@ func (s *ContextImpl) GetWorkflowExecution(
 	if err := s.errorByState(); err != nil {
 		return nil, err
 	}
+	if err := s.allowExecutionRead(ctx, request.NamespaceID); err != nil {
+		return nil, err
+	}
 
 	resp, err := s.executionManager.GetWorkflowExecution(ctx, request)
 	if err = s.handleReadError(err); err != nil {
@@ -854,6 +2253,97 @@ func (s *ContextImpl) GetWorkflowExecution(
 	return resp, nil
 }
 
+func (s *ContextImpl) GetWorkflowExecutions(
+	ctx context.Context,
+	keys []definition.WorkflowKey,
+) (map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse, map[definition.WorkflowKey]error) {
+	responses := make(map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse, len(keys))
+	errs := make(map[definition.WorkflowKey]error)
+	if err := s.errorByState(); err != nil {
+		for _, key := range keys {
+			errs[key] = err
+		}
+		return responses, errs
+	}
+
+	// persistence has no multi-get for executions, so pipeline single reads instead
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, max(s.config.ShardBatchReadConcurrency(), 1))
+	for _, key := range keys {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			lock.Lock()
+			errs[key] = ctx.Err()
+			lock.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(key definition.WorkflowKey) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			resp, err := s.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+				ShardID:     s.shardID,
+				NamespaceID: key.NamespaceID,
+				WorkflowID:  key.WorkflowID,
+				RunID:       key.RunID,
+			})
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs[key] = err
+				return
+			}
+			responses[key] = resp
+		}(key)
+	}
+	wg.Wait()
+	return responses, errs
+}
+
+func (s *ContextImpl) allowExecutionRead(
+	ctx context.Context,
+	namespaceID string,
+) error {
+	// only limit reads on behalf of API callers, internal loads (e.g. task processing) are
+	// already bounded by their own schedulers
+	if headers.GetCallerInfo(ctx).CallerType != headers.CallerTypeAPI {
+		return nil
+	}
+	namespaceEntry, err := s.namespaceRegistry.GetNamespaceByID(namespace.ID(namespaceID))
+	if err != nil {
+		// don't fail the read because of the limiter, e.g. for a namespace being deleted
+		return nil
+	}
+	namespaceName := namespaceEntry.Name()
+	if s.config.ShardReadRPS(namespaceName.String()) <= 0 {
+		return nil
+	}
+	if !s.readRateLimiter.Allow(s.timeSource.Now(), quotas.NewRequest(
+		"GetWorkflowExecution",
+		1,
+		namespaceName.String(),
+		"",
+		0,
+		"",
+	)) {
+		return consts.ErrResourceExhaustedShardReadLimit
+	}
+	return nil
+}
+
+func newReadRateLimiter(
+	config *configs.Config,
+) quotas.RequestRateLimiter {
+	return quotas.NewNamespaceRequestRateLimiter(func(req quotas.Request) quotas.RequestRateLimiter {
+		return quotas.NewRequestRateLimiterAdapter(quotas.NewDefaultIncomingRateLimiter(func() float64 {
+			return float64(config.ShardReadRPS(req.Caller))
+		}))
+	})
+}
+
 func (s *ContextImpl) addTasksSemaphoreAcquired(
 	ctx context.Context,
 	request *persistence.AddHistoryTasksRequest,
@@ -885,7 +2375,7 @@ func (s *ContextImpl) addTasksSemaphoreAcquired(
 		return err
 	}
 
-	requestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(
+	requestCompletionFn, err := s.setAndTrackTaskKeysLocked(
 		request.Tasks,
 	)
 	if err != nil {
@@ -901,6 +2391,19 @@ func (s *ContextImpl) addTasksSemaphoreAcquired(
 	return s.handleWriteError(request.RangeID, err)
 }
 
+// setAndTrackTaskKeysLocked applies the task rewriter, if any, and then assigns keys to the tasks
+// and tracks them until the returned completion function is invoked. Must be called within rwLock.
+func (s *ContextImpl) setAndTrackTaskKeysLocked(
+	taskMaps ...map[tasks.Category][]tasks.Task,
+) (taskRequestCompletionFn, error) {
+	if s.taskRewriter != nil {
+		if err := rewriteTasks(s.taskRewriter, taskMaps...); err != nil {
+			return nil, err
+		}
+	}
+	return s.taskKeyManager.setAndTrackTaskKeys(taskMaps...)
+}
+
 func (s *ContextImpl) AppendHistoryEvents(
 	ctx context.Context,
 	request *persistence.AppendHistoryNodesRequest,
@@ -1054,10 +2557,12 @@ func (s *ContextImpl) DeleteWorkflowExecution(
 					WorkflowID:  key.WorkflowID,
 					RunID:       key.RunID,
 				}
-				if err := s.GetExecutionManager().DeleteCurrentWorkflowExecution(
+				err = s.GetExecutionManager().DeleteCurrentWorkflowExecution(
 					ctx,
 					delCurRequest,
-				); err != nil {
+				)
+				s.currentExecutionCache.invalidate(key.NamespaceID, key.WorkflowID)
+				if err != nil {
 					return err
 				}
 			}
@@ -1113,6 +2618,20 @@ func (s *ContextImpl) GetEventsCache() events.Cache {
 	return s.eventsCache
 }
 
+func (s *ContextImpl) EventsCacheTopEntries(n int) []events.CacheEntryInfo {
+	if !s.config.EventsCacheTopEntriesEnabled() {
+		return nil
+	}
+	// the events cache may be shared by all shards on the host, only report this shard's workflows
+	return s.eventsCache.TopEntries(n, func(key events.EventKey) bool {
+		return s.config.GetShardID(key.NamespaceID, key.WorkflowID) == s.shardID
+	})
+}
+
+func (s *ContextImpl) EventsCacheBytes() int64 {
+	return s.eventsCache.SizeBytes()
+}
+
 func (s *ContextImpl) GetLogger() log.Logger {
 	// constant from initialization, no need for locks
 	return s.contextTaggedLogger
@@ -1201,6 +2720,7 @@ func (s *ContextImpl) renewRangeLocked(isStealing bool) error {
 	)
 
 	s.shardInfo = trimShardInfo(s.clusterMetadata.GetAllClusterInfo(), copyShardInfo(updatedShardInfo))
+	s.publishQuarantinedWorkflowsLocked()
 	s.taskKeyManager.setRangeID(s.shardInfo.RangeId)
 
 	return nil
@@ -1227,6 +2747,26 @@ func (s *ContextImpl) monitorQueueMetrics() {
 func (s *ContextImpl) updateShardInfo(
 	tasksCompleted int,
 	updateFnLocked func(),
+) error {
+	return s.doUpdateShardInfo(tasksCompleted, false, func() error {
+		updateFnLocked()
+		return nil
+	})
+}
+
+// flushShardInfo is like updateShardInfo, but always persists the shard info, and aborts the
+// update if updateFnLocked returns an error.
+func (s *ContextImpl) flushShardInfo(
+	tasksCompleted int,
+	updateFnLocked func() error,
+) error {
+	return s.doUpdateShardInfo(tasksCompleted, true, updateFnLocked)
+}
+
+func (s *ContextImpl) doUpdateShardInfo(
+	tasksCompleted int,
+	force bool,
+	updateFnLocked func() error,
 ) error {
 	s.wLock()
 	if err := s.errorByState(); err != nil {
@@ -1234,8 +2774,11 @@ func (s *ContextImpl) updateShardInfo(
 		return err
 	}
 
+	if err := updateFnLocked(); err != nil {
+		s.wUnlock()
+		return err
+	}
 	s.tasksCompletedSinceLastUpdate += tasksCompleted
-	updateFnLocked()
 	s.shardInfo.StolenSinceRenew = 0
 
 	now := s.timeSource.Now()
@@ -1243,7 +2786,7 @@ func (s *ContextImpl) updateShardInfo(
 	minTasksUntilUpdate := s.config.ShardUpdateMinTasksCompleted()
 	// If ShardUpdateMinTasksCompleted is set to 0 then we only care about whether enough time has passed
 	tooFewTasksCompleted := minTasksUntilUpdate <= 0 || s.tasksCompletedSinceLastUpdate < minTasksUntilUpdate
-	if tooFewTasksCompleted && tooEarly {
+	if !force && tooFewTasksCompleted && tooEarly {
 		s.wUnlock()
 		return nil
 	}
@@ -1290,10 +2833,15 @@ func (s *ContextImpl) emitShardInfoMetricsLogs() {
 	s.rLock()
 	defer s.rUnlock()
 
-	queueStates := trimShardInfo(s.clusterMetadata.GetAllClusterInfo(), copyShardInfo(s.shardInfo)).QueueStates
+	shardInfo := trimShardInfo(s.clusterMetadata.GetAllClusterInfo(), copyShardInfo(s.shardInfo))
+	queueStates := shardInfo.QueueStates
 	emitShardLagLog := s.config.EmitShardLagLog()
 
 	metricsHandler := s.GetMetricsHandler().WithTags(metrics.OperationTag(metrics.ShardInfoScope))
+	metrics.ShardInfoSize.With(metricsHandler).Record(int64(s.serializedShardInfoSize(shardInfo)))
+	for _, sourceCluster := range shardInfo.PausedReplicationSources {
+		metrics.ReplicationSourcePaused.With(s.metricsHandler).Record(1, metrics.SourceClusterTag(sourceCluster))
+	}
 
 Loop:
 	for categoryID, queueState := range queueStates {
@@ -1453,6 +3001,12 @@ func (s *ContextImpl) maybeRecordShardAcquisitionLatency(ownershipChanged bool)
 	}
 }
 
+// shardIDMetricsTag returns the shard ID tag for metrics of this shard, which is only set to the
+// shard ID for a sample of shards, see ShardAcquisitionMetricsShardIDSampling.
+func (s *ContextImpl) shardIDMetricsTag() metrics.Tag {
+	return metrics.ShardIDTag(s.shardID, int32(s.config.ShardAcquisitionMetricsShardIDSampling()))
+}
+
 func (s *ContextImpl) createEngine() Engine {
 	s.contextTaggedLogger.Info("", tag.LifeCycleStarting, tag.ComponentShardEngine)
 	engine := s.engineFactory.CreateEngine(s)
@@ -1470,12 +3024,43 @@ func (s *ContextImpl) UnloadForOwnershipLost() {
 	_ = s.transition(contextRequestStop{reason: stopReasonOwnershipLost})
 }
 
+func (s *ContextImpl) SetMaintenanceMode(on bool) {
+	if s.maintenanceMode.Swap(on) == on {
+		return
+	}
+	if on {
+		s.contextTaggedLogger.Info("Shard entered maintenance mode, rejecting workflow writes")
+		metrics.ShardMaintenanceMode.With(s.metricsHandler).Record(1)
+	} else {
+		s.contextTaggedLogger.Info("Shard left maintenance mode")
+		metrics.ShardMaintenanceMode.With(s.metricsHandler).Record(0)
+	}
+}
+
+func (s *ContextImpl) errorByMaintenanceMode() error {
+	if !s.maintenanceMode.Load() {
+		return nil
+	}
+	metrics.ShardMaintenanceModeRejectedWrites.With(s.metricsHandler).Record(1)
+	return ErrShardInMaintenanceMode
+}
+
 // FinishStop should only be called by the controller.
 func (s *ContextImpl) FinishStop() {
 	// After this returns, engineFuture.Set may not be called anymore, so if we don't get see
 	// an Engine here, we won't ever have one.
 	_ = s.transition(contextRequestFinishStop{})
 
+	// Speculative workflow tasks are never persisted, so the new owner of the shard loads mutable
+	// state without them. Cancel their timeout tasks so that they don't fire while the engine
+	// stops. If ownership was lost, the shard can't write anymore and they are counted as dropped.
+	if s.stoppedForOwnershipLost() {
+		dropped := s.cancelSpeculativeTasks()
+		metrics.SpeculativeWorkflowTaskTimeoutTasksDropped.With(s.metricsHandler).Record(int64(dropped))
+	} else {
+		s.DrainSpeculativeTasks()
+	}
+
 	// use a context that we know is cancelled so that this doesn't block
 	engine, _ := s.engineFuture.Get(s.lifecycleCtx)
 
@@ -1503,21 +3088,64 @@ func (s *ContextImpl) wLock() {
 	handler := s.metricsHandler.WithTags(metrics.OperationTag(metrics.ShardInfoScope))
 	metrics.LockRequests.With(handler).Record(1)
 	startTime := time.Now().UTC()
-	defer func() { metrics.LockLatency.With(handler).Record(time.Since(startTime)) }()
+	defer func() { s.recordLockLatency(handler, startTime) }()
 
 	s.rwLock.Lock()
+	if s.lockHolderTracking.Load() {
+		s.recordLockHolder()
+	}
+}
+
+// recordLockHolder must be called directly by wLock after acquiring rwLock,
+// so that the recorded caller is the function that called wLock.
+func (s *ContextImpl) recordLockHolder() {
+	holder := &lockHolderInfo{
+		acquiredAt: s.timeSource.Now(),
+	}
+	// skip runtime.Callers, recordLockHolder and wLock
+	runtime.Callers(3, holder.callerPC[:])
+	if s.captureLockHolderStack.Load() {
+		holder.stack = rdebug.Stack()
+	}
+	s.lockHolder.Store(holder)
 }
 
 func (s *ContextImpl) rLock() {
 	handler := s.metricsHandler.WithTags(metrics.OperationTag(metrics.ShardInfoScope))
 	metrics.LockRequests.With(handler).Record(1)
 	startTime := time.Now().UTC()
-	defer func() { metrics.LockLatency.With(handler).Record(time.Since(startTime)) }()
+	defer func() { s.recordLockLatency(handler, startTime) }()
 
 	s.rwLock.RLock()
 }
 
+// recordLockLatency records how long acquiring the shard lock took, and with
+// ShardLockWaitMetricsEnabled, also per shard to find contended shards.
+func (s *ContextImpl) recordLockLatency(handler metrics.Handler, startTime time.Time) {
+	now := time.Now().UTC()
+	latency := now.Sub(startTime)
+	metrics.LockLatency.With(handler).Record(latency)
+	if s.lockWaitMetricsEnabled(now) {
+		metrics.ShardLockWaitLatency.With(s.metricsHandler).Record(latency, metrics.ShardIDTag(s.shardID, 1))
+	}
+}
+
+// lockWaitMetricsEnabled returns the cached value of ShardLockWaitMetricsEnabled, so that every
+// lock acquire doesn't have to resolve dynamic config. The cached value is refreshed at most
+// once per lockWaitMetricsRefreshInterval.
+func (s *ContextImpl) lockWaitMetricsEnabled(now time.Time) bool {
+	refreshAt := s.lockWaitMetricsRefreshAt.Load()
+	if now.UnixNano() >= refreshAt &&
+		s.lockWaitMetricsRefreshAt.CompareAndSwap(refreshAt, now.Add(lockWaitMetricsRefreshInterval).UnixNano()) {
+		s.lockWaitMetricsEnabledCache.Store(s.config.ShardLockWaitMetricsEnabled())
+	}
+	return s.lockWaitMetricsEnabledCache.Load()
+}
+
 func (s *ContextImpl) wUnlock() {
+	if s.lockHolderTracking.Load() {
+		s.lockHolder.Store(nil)
+	}
 	s.rwLock.Unlock()
 }
 
@@ -1604,6 +3232,7 @@ func (s *ContextImpl) transition(request contextRequest) error {
 
 	setStateAcquiring := func() {
 		s.state = contextStateAcquiring
+		s.lastOwnershipAssertion.Store(nil)
 		s.contextTaggedLogger.Info("", tag.LifeCycleStarted, tag.ComponentShardContext)
 		go s.acquireShard()
 	}
@@ -1611,6 +3240,7 @@ func (s *ContextImpl) transition(request contextRequest) error {
 	setStateStopping := func(request contextRequestStop) {
 		s.state = contextStateStopping
 		s.stopReason = request.reason
+		s.lastOwnershipAssertion.Store(nil)
 		s.contextTaggedLogger.Info("", tag.LifeCycleStopping, tag.ComponentShardContext)
 		// Cancel lifecycle context as soon as we know we're shutting down
 		s.lifecycleCancel()
@@ -1622,6 +3252,7 @@ func (s *ContextImpl) transition(request contextRequest) error {
 
 	setStateStopped := func() {
 		s.state = contextStateStopped
+		s.lastOwnershipAssertion.Store(nil)
 		s.contextTaggedLogger.Info("", tag.LifeCycleStopped, tag.ComponentShardContext)
 		// Do this again in case we skipped the stopping state, which could happen
 		// when calling CloseShardByID or the controller is shutting down.
@@ -1833,9 +3464,11 @@ func (s *ContextImpl) loadShardMetadata(ownershipChanged *bool) error {
 
 		if clusterName != currentClusterName {
 			remoteClusterInfos[clusterName] = &remoteClusterInfo{
-				CurrentTime:                exclusiveMaxReadTime,
-				AckedReplicationTaskIDs:    make(map[int32]int64),
-				AckedReplicationTimestamps: make(map[int32]time.Time),
+				CurrentTime:                  exclusiveMaxReadTime,
+				AckedReplicationTaskIDs:      make(map[int32]int64),
+				AckedReplicationTimestamps:   make(map[int32]time.Time),
+				AppliedReplicationTaskIDs:    make(map[int32]int64),
+				AppliedReplicationTimestamps: make(map[int32]time.Time),
 			}
 		}
 	}
@@ -1844,6 +3477,7 @@ func (s *ContextImpl) loadShardMetadata(ownershipChanged *bool) error {
 	defer s.wUnlock()
 
 	s.shardInfo = shardInfo
+	s.publishQuarantinedWorkflowsLocked()
 	s.remoteClusterInfos = remoteClusterInfos
 	s.taskKeyManager.setTaskMinScheduledTime(taskMinScheduledTime)
 
@@ -1894,6 +3528,13 @@ func (s *ContextImpl) GetReplicationStatus(clusterNames []string) (map[string]*h
 				}
 			}
 		}
+
+		if appliedTaskID, appliedTimestamp := s.getAckedReplicationWatermarkLocked(clusterName); appliedTaskID != persistence.EmptyQueueMessageID {
+			if record, ok := remoteClusters[clusterName]; ok {
+				record.AppliedTaskId = appliedTaskID
+				record.AppliedTaskVisibilityTime = timestamppb.New(appliedTimestamp)
+			}
+		}
 	}
 
 	for k, v := range s.handoverNamespaces {
@@ -1905,13 +3546,26 @@ func (s *ContextImpl) GetReplicationStatus(clusterNames []string) (map[string]*h
 	return remoteClusters, handoverNamespaces, nil
 }
 
+func (s *ContextImpl) RecordReplicationTask(audit ReplicationTaskAudit) {
+	if audit.AppliedTime.IsZero() {
+		audit.AppliedTime = s.timeSource.Now()
+	}
+	s.replicationTaskAuditLog.record(audit)
+}
+
+func (s *ContextImpl) RecentReplicationTasks() []ReplicationTaskAudit {
+	return s.replicationTaskAuditLog.recent()
+}
+
 func (s *ContextImpl) getOrUpdateRemoteClusterInfoLocked(clusterName string) *remoteClusterInfo {
 	if info, ok := s.remoteClusterInfos[clusterName]; ok {
 		return info
 	}
 	info := &remoteClusterInfo{
-		AckedReplicationTaskIDs:    make(map[int32]int64),
-		AckedReplicationTimestamps: make(map[int32]time.Time),
+		AckedReplicationTaskIDs:      make(map[int32]int64),
+		AckedReplicationTimestamps:   make(map[int32]time.Time),
+		AppliedReplicationTaskIDs:    make(map[int32]int64),
+		AppliedReplicationTimestamps: make(map[int32]time.Time),
 	}
 	s.remoteClusterInfos[clusterName] = info
 	return info
@@ -1942,6 +3596,7 @@ func (s *ContextImpl) acquireShard() {
 
 	// Remember this value across attempts
 	ownershipChanged := false
+	acquireStartTime := s.timeSource.Now()
 
 	op := func() error {
 		if !s.IsValid() {
@@ -2004,6 +3659,10 @@ func (s *ContextImpl) acquireShard() {
 
 		s.updateHandoverNamespacePendingTaskID()
 
+		metrics.ShardAcquisitionDuration.With(s.GetMetricsHandler()).Record(
+			s.timeSource.Now().Sub(acquireStartTime),
+			s.shardIDMetricsTag(),
+		)
 		return nil
 	}
 
@@ -2015,6 +3674,11 @@ func (s *ContextImpl) acquireShard() {
 				tag.Error(err),
 				tag.IsRetryable(isRetryable),
 			)
+			metrics.ShardAcquisitionFailures.With(s.GetMetricsHandler()).Record(
+				1,
+				s.shardIDMetricsTag(),
+				metrics.ServiceErrorTypeTag(err),
+			)
 		}()
 		if s.lifecycleCtx.Err() != nil {
 			return false
@@ -2064,6 +3728,8 @@ func newContext(
 	taskCategoryRegistry tasks.TaskCategoryRegistry,
 	eventsCache events.Cache,
 	stateMachineRegistry *hsm.Registry,
+	fallbackTaskDeserializer TaskDeserializer,
+	taskRewriter TaskRewriter,
 ) (*ContextImpl, error) {
 	hostIdentity := hostInfoProvider.HostInfo().Identity()
 	sequenceID := atomic.AddInt64(&shardContextSequenceID, 1)
@@ -2109,7 +3775,25 @@ func newContext(
 		engineFuture:            future.NewFuture[Engine](),
 		queueMetricEmitter:      sync.Once{},
 		ioSemaphore:             locks.NewPrioritySemaphore(ioConcurrency),
+		readRateLimiter:         newReadRateLimiter(historyConfig),
+		currentExecutionCache:   newCurrentExecutionCache(historyConfig, timeSource),
+		replicationTaskAuditLog: newReplicationTaskAuditLog(historyConfig.ShardReplicationTaskAuditSize),
+		speculativeTasks:        newSpeculativeTaskSet(),
 		stateMachineRegistry:    stateMachineRegistry,
+		taskRewriter:            taskRewriter,
+		taskGenerationHeatmap: newTaskGenerationHeatmap(
+			historyConfig.ShardTaskGenerationHeatmapTopK,
+			historyConfig.ShardTaskGenerationHeatmapRetention,
+		),
+		queueDrainRate: newQueueDrainRate(historyConfig.ShardQueueDrainRateWindow, timeSource.Now()),
+	}
+	if fallbackTaskDeserializer != nil {
+		shardContext.payloadSerializer = newFallbackTaskSerializer(
+			payloadSerializer,
+			fallbackTaskDeserializer,
+			shardContext.throttledLogger,
+			metricsHandler,
+		)
 	}
 	shardContext.taskKeyManager = newTaskKeyManager(
 		shardContext.taskCategoryRegistry,
@@ -2158,13 +3842,18 @@ func copyShardInfo(shardInfo *persistencespb.ShardInfo) *persistencespb.ShardInf
 	}
 
 	return &persistencespb.ShardInfo{
-		ShardId:                shardInfo.ShardId,
-		Owner:                  shardInfo.Owner,
-		RangeId:                shardInfo.RangeId,
-		StolenSinceRenew:       shardInfo.StolenSinceRenew,
-		ReplicationDlqAckLevel: maps.Clone(shardInfo.ReplicationDlqAckLevel),
-		UpdateTime:             shardInfo.UpdateTime,
-		QueueStates:            queueStates,
+		ShardId:                  shardInfo.ShardId,
+		Owner:                    shardInfo.Owner,
+		RangeId:                  shardInfo.RangeId,
+		StolenSinceRenew:         shardInfo.StolenSinceRenew,
+		ReplicationDlqAckLevel:   maps.Clone(shardInfo.ReplicationDlqAckLevel),
+		UpdateTime:               shardInfo.UpdateTime,
+		QueueStates:              queueStates,
+		PausedReplicationSources: slices.Clone(shardInfo.PausedReplicationSources),
+		// entries are replaced rather than modified, so they can be shared
+		QuarantinedWorkflows: slices.Clone(shardInfo.QuarantinedWorkflows),
+		// checkpoints are never modified once taken, so they can be shared
+		RepairCheckpoints: slices.Clone(shardInfo.RepairCheckpoints),
 	}
 }
 
@@ -136,6 +136,21 @@ func (mr *MockContextMockRecorder) AssertOwnership(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssertOwnership", reflect.TypeOf((*MockContext)(nil).AssertOwnership), ctx)
 }
 
+// CheckpointForRepair mocks base method.
+func (m *MockContext) CheckpointForRepair() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckpointForRepair")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckpointForRepair indicates an expected call of CheckpointForRepair.
+func (mr *MockContextMockRecorder) CheckpointForRepair() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckpointForRepair", reflect.TypeOf((*MockContext)(nil).CheckpointForRepair))
+}
+
 // ConflictResolveWorkflowExecution mocks base method.
 func (m *MockContext) ConflictResolveWorkflowExecution(ctx context.Context, request *persistence.ConflictResolveWorkflowExecutionRequest) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -194,6 +209,108 @@ func (mr *MockContextMockRecorder) DeleteWorkflowExecution(ctx, workflowKey, bra
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkflowExecution", reflect.TypeOf((*MockContext)(nil).DeleteWorkflowExecution), ctx, workflowKey, branchToken, closeExecutionVisibilityTaskID, workflowCloseTime, stage)
 }
 
+// DryRunTask mocks base method.
+func (m *MockContext) DryRunTask(ctx context.Context, category tasks.Category, taskID int64) (TaskExecutionTrace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunTask", ctx, category, taskID)
+	ret0, _ := ret[0].(TaskExecutionTrace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunTask indicates an expected call of DryRunTask.
+func (mr *MockContextMockRecorder) DryRunTask(ctx, category, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunTask", reflect.TypeOf((*MockContext)(nil).DryRunTask), ctx, category, taskID)
+}
+
+// EstimateLoad mocks base method.
+func (m *MockContext) EstimateLoad(ctx context.Context) (ShardLoadEstimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateLoad", ctx)
+	ret0, _ := ret[0].(ShardLoadEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateLoad indicates an expected call of EstimateLoad.
+func (mr *MockContextMockRecorder) EstimateLoad(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateLoad", reflect.TypeOf((*MockContext)(nil).EstimateLoad), ctx)
+}
+
+// EstimateQueueDrainTime mocks base method.
+func (m *MockContext) EstimateQueueDrainTime(category tasks.Category) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateQueueDrainTime", category)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateQueueDrainTime indicates an expected call of EstimateQueueDrainTime.
+func (mr *MockContextMockRecorder) EstimateQueueDrainTime(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateQueueDrainTime", reflect.TypeOf((*MockContext)(nil).EstimateQueueDrainTime), category)
+}
+
+// EventsCacheBytes mocks base method.
+func (m *MockContext) EventsCacheBytes() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsCacheBytes")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// EventsCacheBytes indicates an expected call of EventsCacheBytes.
+func (mr *MockContextMockRecorder) EventsCacheBytes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsCacheBytes", reflect.TypeOf((*MockContext)(nil).EventsCacheBytes))
+}
+
+// EventsCacheTopEntries mocks base method.
+func (m *MockContext) EventsCacheTopEntries(n int) []events.CacheEntryInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsCacheTopEntries", n)
+	ret0, _ := ret[0].([]events.CacheEntryInfo)
+	return ret0
+}
+
+// EventsCacheTopEntries indicates an expected call of EventsCacheTopEntries.
+func (mr *MockContextMockRecorder) EventsCacheTopEntries(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsCacheTopEntries", reflect.TypeOf((*MockContext)(nil).EventsCacheTopEntries), n)
+}
+
+// ExportQueueState mocks base method.
+func (m *MockContext) ExportQueueState() (*v13.ShardInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportQueueState")
+	ret0, _ := ret[0].(*v13.ShardInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportQueueState indicates an expected call of ExportQueueState.
+func (mr *MockContextMockRecorder) ExportQueueState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportQueueState", reflect.TypeOf((*MockContext)(nil).ExportQueueState))
+}
+
+// ForceCompleteTask mocks base method.
+func (m *MockContext) ForceCompleteTask(ctx context.Context, category tasks.Category, taskID int64, reason string, dlqWriter TaskDLQWriter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceCompleteTask", ctx, category, taskID, reason, dlqWriter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceCompleteTask indicates an expected call of ForceCompleteTask.
+func (mr *MockContextMockRecorder) ForceCompleteTask(ctx, category, taskID, reason, dlqWriter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceCompleteTask", reflect.TypeOf((*MockContext)(nil).ForceCompleteTask), ctx, category, taskID, reason, dlqWriter)
+}
+
 // GenerateTaskID mocks base method.
 func (m *MockContext) GenerateTaskID() (int64, error) {
 	m.ctrl.T.Helper()
@@ -224,6 +341,21 @@ func (mr *MockContextMockRecorder) GenerateTaskIDs(number interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTaskIDs", reflect.TypeOf((*MockContext)(nil).GenerateTaskIDs), number)
 }
 
+// GetAckedReplicationWatermark mocks base method.
+func (m *MockContext) GetAckedReplicationWatermark(sourceCluster string) (int64, time.Time) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAckedReplicationWatermark", sourceCluster)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(time.Time)
+	return ret0, ret1
+}
+
+// GetAckedReplicationWatermark indicates an expected call of GetAckedReplicationWatermark.
+func (mr *MockContextMockRecorder) GetAckedReplicationWatermark(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAckedReplicationWatermark", reflect.TypeOf((*MockContext)(nil).GetAckedReplicationWatermark), sourceCluster)
+}
+
 // GetArchivalMetadata mocks base method.
 func (m *MockContext) GetArchivalMetadata() archiver.ArchivalMetadata {
 	m.ctrl.T.Helper()
@@ -281,6 +413,21 @@ func (mr *MockContextMockRecorder) GetCurrentExecution(ctx, request interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentExecution", reflect.TypeOf((*MockContext)(nil).GetCurrentExecution), ctx, request)
 }
 
+// GetCurrentExecutionWithConsistency mocks base method.
+func (m *MockContext) GetCurrentExecutionWithConsistency(ctx context.Context, request *persistence.GetCurrentExecutionRequest, consistency ReadConsistency) (*persistence.GetCurrentExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentExecutionWithConsistency", ctx, request, consistency)
+	ret0, _ := ret[0].(*persistence.GetCurrentExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentExecutionWithConsistency indicates an expected call of GetCurrentExecutionWithConsistency.
+func (mr *MockContextMockRecorder) GetCurrentExecutionWithConsistency(ctx, request, consistency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentExecutionWithConsistency", reflect.TypeOf((*MockContext)(nil).GetCurrentExecutionWithConsistency), ctx, request, consistency)
+}
+
 // GetCurrentTime mocks base method.
 func (m *MockContext) GetCurrentTime(cluster string) time.Time {
 	m.ctrl.T.Helper()
@@ -380,6 +527,21 @@ func (mr *MockContextMockRecorder) GetMetricsHandler() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsHandler", reflect.TypeOf((*MockContext)(nil).GetMetricsHandler))
 }
 
+// GetNamespaceFailoverVersion mocks base method.
+func (m *MockContext) GetNamespaceFailoverVersion(namespaceID namespace.ID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceFailoverVersion", namespaceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceFailoverVersion indicates an expected call of GetNamespaceFailoverVersion.
+func (mr *MockContextMockRecorder) GetNamespaceFailoverVersion(namespaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceFailoverVersion", reflect.TypeOf((*MockContext)(nil).GetNamespaceFailoverVersion), namespaceID)
+}
+
 // GetNamespaceRegistry mocks base method.
 func (m *MockContext) GetNamespaceRegistry() namespace.Registry {
 	m.ctrl.T.Helper()
@@ -408,6 +570,20 @@ func (mr *MockContextMockRecorder) GetOwner() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwner", reflect.TypeOf((*MockContext)(nil).GetOwner))
 }
 
+// GetPausedReplicationSources mocks base method.
+func (m *MockContext) GetPausedReplicationSources() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPausedReplicationSources")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetPausedReplicationSources indicates an expected call of GetPausedReplicationSources.
+func (mr *MockContextMockRecorder) GetPausedReplicationSources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPausedReplicationSources", reflect.TypeOf((*MockContext)(nil).GetPausedReplicationSources))
+}
+
 // GetPayloadSerializer mocks base method.
 func (m *MockContext) GetPayloadSerializer() serialization.Serializer {
 	m.ctrl.T.Helper()
@@ -480,6 +656,21 @@ func (mr *MockContextMockRecorder) GetRemoteAdminClient(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteAdminClient", reflect.TypeOf((*MockContext)(nil).GetRemoteAdminClient), arg0)
 }
 
+// GetReplicationDLQDepth mocks base method.
+func (m *MockContext) GetReplicationDLQDepth(sourceCluster string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicationDLQDepth", sourceCluster)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicationDLQDepth indicates an expected call of GetReplicationDLQDepth.
+func (mr *MockContextMockRecorder) GetReplicationDLQDepth(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationDLQDepth", reflect.TypeOf((*MockContext)(nil).GetReplicationDLQDepth), sourceCluster)
+}
+
 // GetReplicationStatus mocks base method.
 func (m *MockContext) GetReplicationStatus(cluster []string) (map[string]*v12.ShardReplicationStatusPerCluster, map[string]*v12.HandoverNamespaceInfo, error) {
 	m.ctrl.T.Helper()
@@ -552,6 +743,49 @@ func (mr *MockContextMockRecorder) GetShardID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardID", reflect.TypeOf((*MockContext)(nil).GetShardID))
 }
 
+// GetShardInfoSize mocks base method.
+func (m *MockContext) GetShardInfoSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShardInfoSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetShardInfoSize indicates an expected call of GetShardInfoSize.
+func (mr *MockContextMockRecorder) GetShardInfoSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardInfoSize", reflect.TypeOf((*MockContext)(nil).GetShardInfoSize))
+}
+
+// GetTaskIDReservations mocks base method.
+func (m *MockContext) GetTaskIDReservations() []TaskIDReservation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskIDReservations")
+	ret0, _ := ret[0].([]TaskIDReservation)
+	return ret0
+}
+
+// GetTaskIDReservations indicates an expected call of GetTaskIDReservations.
+func (mr *MockContextMockRecorder) GetTaskIDReservations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskIDReservations", reflect.TypeOf((*MockContext)(nil).GetTaskIDReservations))
+}
+
+// GetTaskInfo mocks base method.
+func (m *MockContext) GetTaskInfo(category tasks.Category, taskID int64) (tasks.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskInfo", category, taskID)
+	ret0, _ := ret[0].(tasks.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskInfo indicates an expected call of GetTaskInfo.
+func (mr *MockContextMockRecorder) GetTaskInfo(category, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskInfo", reflect.TypeOf((*MockContext)(nil).GetTaskInfo), category, taskID)
+}
+
 // GetThrottledLogger mocks base method.
 func (m *MockContext) GetThrottledLogger() log.Logger {
 	m.ctrl.T.Helper()
@@ -595,6 +829,92 @@ func (mr *MockContextMockRecorder) GetWorkflowExecution(ctx, request interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecution", reflect.TypeOf((*MockContext)(nil).GetWorkflowExecution), ctx, request)
 }
 
+// GetWorkflowExecutions mocks base method.
+func (m *MockContext) GetWorkflowExecutions(ctx context.Context, keys []definition.WorkflowKey) (map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse, map[definition.WorkflowKey]error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflowExecutions", ctx, keys)
+	ret0, _ := ret[0].(map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse)
+	ret1, _ := ret[1].(map[definition.WorkflowKey]error)
+	return ret0, ret1
+}
+
+// GetWorkflowExecutions indicates an expected call of GetWorkflowExecutions.
+func (mr *MockContextMockRecorder) GetWorkflowExecutions(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecutions", reflect.TypeOf((*MockContext)(nil).GetWorkflowExecutions), ctx, keys)
+}
+
+// IsReplicationPausedFrom mocks base method.
+func (m *MockContext) IsReplicationPausedFrom(sourceCluster string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReplicationPausedFrom", sourceCluster)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsReplicationPausedFrom indicates an expected call of IsReplicationPausedFrom.
+func (mr *MockContextMockRecorder) IsReplicationPausedFrom(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReplicationPausedFrom", reflect.TypeOf((*MockContext)(nil).IsReplicationPausedFrom), sourceCluster)
+}
+
+// IsWorkflowQuarantined mocks base method.
+func (m *MockContext) IsWorkflowQuarantined(workflowKey definition.WorkflowKey) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsWorkflowQuarantined", workflowKey)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsWorkflowQuarantined indicates an expected call of IsWorkflowQuarantined.
+func (mr *MockContextMockRecorder) IsWorkflowQuarantined(workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsWorkflowQuarantined", reflect.TypeOf((*MockContext)(nil).IsWorkflowQuarantined), workflowKey)
+}
+
+// ListExecutionsWithPendingSignals mocks base method.
+func (m *MockContext) ListExecutionsWithPendingSignals(ctx context.Context, limit int) ([]definition.WorkflowKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExecutionsWithPendingSignals", ctx, limit)
+	ret0, _ := ret[0].([]definition.WorkflowKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExecutionsWithPendingSignals indicates an expected call of ListExecutionsWithPendingSignals.
+func (mr *MockContextMockRecorder) ListExecutionsWithPendingSignals(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExecutionsWithPendingSignals", reflect.TypeOf((*MockContext)(nil).ListExecutionsWithPendingSignals), ctx, limit)
+}
+
+// ListHandoverNamespaces mocks base method.
+func (m *MockContext) ListHandoverNamespaces() []namespace.ID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHandoverNamespaces")
+	ret0, _ := ret[0].([]namespace.ID)
+	return ret0
+}
+
+// ListHandoverNamespaces indicates an expected call of ListHandoverNamespaces.
+func (mr *MockContextMockRecorder) ListHandoverNamespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHandoverNamespaces", reflect.TypeOf((*MockContext)(nil).ListHandoverNamespaces))
+}
+
+// ListQuarantinedWorkflows mocks base method.
+func (m *MockContext) ListQuarantinedWorkflows() []*v13.QuarantinedWorkflow {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuarantinedWorkflows")
+	ret0, _ := ret[0].([]*v13.QuarantinedWorkflow)
+	return ret0
+}
+
+// ListQuarantinedWorkflows indicates an expected call of ListQuarantinedWorkflows.
+func (mr *MockContextMockRecorder) ListQuarantinedWorkflows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuarantinedWorkflows", reflect.TypeOf((*MockContext)(nil).ListQuarantinedWorkflows))
+}
+
 // NewVectorClock mocks base method.
 func (m *MockContext) NewVectorClock() (*v11.VectorClock, error) {
 	m.ctrl.T.Helper()
@@ -610,6 +930,175 @@ func (mr *MockContextMockRecorder) NewVectorClock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewVectorClock", reflect.TypeOf((*MockContext)(nil).NewVectorClock))
 }
 
+// OldestPendingTaskTime mocks base method.
+func (m *MockContext) OldestPendingTaskTime(category tasks.Category) (time.Time, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OldestPendingTaskTime", category)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// OldestPendingTaskTime indicates an expected call of OldestPendingTaskTime.
+func (mr *MockContextMockRecorder) OldestPendingTaskTime(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OldestPendingTaskTime", reflect.TypeOf((*MockContext)(nil).OldestPendingTaskTime), category)
+}
+
+// PauseReplicationFrom mocks base method.
+func (m *MockContext) PauseReplicationFrom(sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseReplicationFrom", sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseReplicationFrom indicates an expected call of PauseReplicationFrom.
+func (mr *MockContextMockRecorder) PauseReplicationFrom(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseReplicationFrom", reflect.TypeOf((*MockContext)(nil).PauseReplicationFrom), sourceCluster)
+}
+
+// QuarantineWorkflow mocks base method.
+func (m *MockContext) QuarantineWorkflow(workflowKey definition.WorkflowKey, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuarantineWorkflow", workflowKey, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QuarantineWorkflow indicates an expected call of QuarantineWorkflow.
+func (mr *MockContextMockRecorder) QuarantineWorkflow(workflowKey, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuarantineWorkflow", reflect.TypeOf((*MockContext)(nil).QuarantineWorkflow), workflowKey, reason)
+}
+
+// RebuildQueueState mocks base method.
+func (m *MockContext) RebuildQueueState(ctx context.Context, category tasks.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildQueueState", ctx, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildQueueState indicates an expected call of RebuildQueueState.
+func (mr *MockContextMockRecorder) RebuildQueueState(ctx, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildQueueState", reflect.TypeOf((*MockContext)(nil).RebuildQueueState), ctx, category)
+}
+
+// RecentReplicationTasks mocks base method.
+func (m *MockContext) RecentReplicationTasks() []ReplicationTaskAudit {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentReplicationTasks")
+	ret0, _ := ret[0].([]ReplicationTaskAudit)
+	return ret0
+}
+
+// RecentReplicationTasks indicates an expected call of RecentReplicationTasks.
+func (mr *MockContextMockRecorder) RecentReplicationTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentReplicationTasks", reflect.TypeOf((*MockContext)(nil).RecentReplicationTasks))
+}
+
+// RecordReplicationTask mocks base method.
+func (m *MockContext) RecordReplicationTask(audit ReplicationTaskAudit) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordReplicationTask", audit)
+}
+
+// RecordReplicationTask indicates an expected call of RecordReplicationTask.
+func (mr *MockContextMockRecorder) RecordReplicationTask(audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordReplicationTask", reflect.TypeOf((*MockContext)(nil).RecordReplicationTask), audit)
+}
+
+// ReleaseTaskIDRange mocks base method.
+func (m *MockContext) ReleaseTaskIDRange(start int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseTaskIDRange", start)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReleaseTaskIDRange indicates an expected call of ReleaseTaskIDRange.
+func (mr *MockContextMockRecorder) ReleaseTaskIDRange(start interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseTaskIDRange", reflect.TypeOf((*MockContext)(nil).ReleaseTaskIDRange), start)
+}
+
+// ReserveTaskIDRange mocks base method.
+func (m *MockContext) ReserveTaskIDRange(n int) (TaskIDReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveTaskIDRange", n)
+	ret0, _ := ret[0].(TaskIDReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveTaskIDRange indicates an expected call of ReserveTaskIDRange.
+func (mr *MockContextMockRecorder) ReserveTaskIDRange(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveTaskIDRange", reflect.TypeOf((*MockContext)(nil).ReserveTaskIDRange), n)
+}
+
+// RestoreQueueState mocks base method.
+func (m *MockContext) RestoreQueueState(snapshot *v13.ShardInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreQueueState", snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreQueueState indicates an expected call of RestoreQueueState.
+func (mr *MockContextMockRecorder) RestoreQueueState(snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreQueueState", reflect.TypeOf((*MockContext)(nil).RestoreQueueState), snapshot)
+}
+
+// ResumeReplicationFrom mocks base method.
+func (m *MockContext) ResumeReplicationFrom(sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeReplicationFrom", sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeReplicationFrom indicates an expected call of ResumeReplicationFrom.
+func (mr *MockContextMockRecorder) ResumeReplicationFrom(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeReplicationFrom", reflect.TypeOf((*MockContext)(nil).ResumeReplicationFrom), sourceCluster)
+}
+
+// RollbackToCheckpoint mocks base method.
+func (m *MockContext) RollbackToCheckpoint(checkpointID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackToCheckpoint", checkpointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackToCheckpoint indicates an expected call of RollbackToCheckpoint.
+func (mr *MockContextMockRecorder) RollbackToCheckpoint(checkpointID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackToCheckpoint", reflect.TypeOf((*MockContext)(nil).RollbackToCheckpoint), checkpointID)
+}
+
+// ScanForDuplicateTaskIDs mocks base method.
+func (m *MockContext) ScanForDuplicateTaskIDs(ctx context.Context, category tasks.Category, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanForDuplicateTaskIDs", ctx, category, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanForDuplicateTaskIDs indicates an expected call of ScanForDuplicateTaskIDs.
+func (mr *MockContextMockRecorder) ScanForDuplicateTaskIDs(ctx, category, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanForDuplicateTaskIDs", reflect.TypeOf((*MockContext)(nil).ScanForDuplicateTaskIDs), ctx, category, limit)
+}
+
 // SetCurrentTime mocks base method.
 func (m *MockContext) SetCurrentTime(cluster string, currentTime time.Time) {
 	m.ctrl.T.Helper()
@@ -622,6 +1111,18 @@ func (mr *MockContextMockRecorder) SetCurrentTime(cluster, currentTime interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrentTime", reflect.TypeOf((*MockContext)(nil).SetCurrentTime), cluster, currentTime)
 }
 
+// SetMaintenanceMode mocks base method.
+func (m *MockContext) SetMaintenanceMode(on bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMaintenanceMode", on)
+}
+
+// SetMaintenanceMode indicates an expected call of SetMaintenanceMode.
+func (mr *MockContextMockRecorder) SetMaintenanceMode(on interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenanceMode", reflect.TypeOf((*MockContext)(nil).SetMaintenanceMode), on)
+}
+
 // SetQueueState mocks base method.
 func (m *MockContext) SetQueueState(category tasks.Category, tasksCompleted int, state *v13.QueueState) error {
 	m.ctrl.T.Helper()
@@ -665,6 +1166,20 @@ func (mr *MockContextMockRecorder) StateMachineRegistry() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateMachineRegistry", reflect.TypeOf((*MockContext)(nil).StateMachineRegistry))
 }
 
+// TaskGenerationByWorkflowType mocks base method.
+func (m *MockContext) TaskGenerationByWorkflowType(window time.Duration) map[string]int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TaskGenerationByWorkflowType", window)
+	ret0, _ := ret[0].(map[string]int64)
+	return ret0
+}
+
+// TaskGenerationByWorkflowType indicates an expected call of TaskGenerationByWorkflowType.
+func (mr *MockContextMockRecorder) TaskGenerationByWorkflowType(window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TaskGenerationByWorkflowType", reflect.TypeOf((*MockContext)(nil).TaskGenerationByWorkflowType), window)
+}
+
 // UnloadForOwnershipLost mocks base method.
 func (m *MockContext) UnloadForOwnershipLost() {
 	m.ctrl.T.Helper()
@@ -677,6 +1192,47 @@ func (mr *MockContextMockRecorder) UnloadForOwnershipLost() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadForOwnershipLost", reflect.TypeOf((*MockContext)(nil).UnloadForOwnershipLost))
 }
 
+// UnquarantineWorkflow mocks base method.
+func (m *MockContext) UnquarantineWorkflow(workflowKey definition.WorkflowKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnquarantineWorkflow", workflowKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnquarantineWorkflow indicates an expected call of UnquarantineWorkflow.
+func (mr *MockContextMockRecorder) UnquarantineWorkflow(workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnquarantineWorkflow", reflect.TypeOf((*MockContext)(nil).UnquarantineWorkflow), workflowKey)
+}
+
+// UpcomingTimers mocks base method.
+func (m *MockContext) UpcomingTimers(ctx context.Context, n int) ([]TimerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpcomingTimers", ctx, n)
+	ret0, _ := ret[0].([]TimerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpcomingTimers indicates an expected call of UpcomingTimers.
+func (mr *MockContextMockRecorder) UpcomingTimers(ctx, n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpcomingTimers", reflect.TypeOf((*MockContext)(nil).UpcomingTimers), ctx, n)
+}
+
+// UpdateAckedReplicationWatermark mocks base method.
+func (m *MockContext) UpdateAckedReplicationWatermark(sourceCluster string, sourceShardID int32, ackTaskID int64, ackTimestamp time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateAckedReplicationWatermark", sourceCluster, sourceShardID, ackTaskID, ackTimestamp)
+}
+
+// UpdateAckedReplicationWatermark indicates an expected call of UpdateAckedReplicationWatermark.
+func (mr *MockContextMockRecorder) UpdateAckedReplicationWatermark(sourceCluster, sourceShardID, ackTaskID, ackTimestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAckedReplicationWatermark", reflect.TypeOf((*MockContext)(nil).UpdateAckedReplicationWatermark), sourceCluster, sourceShardID, ackTaskID, ackTimestamp)
+}
+
 // UpdateHandoverNamespace mocks base method.
 func (m *MockContext) UpdateHandoverNamespace(ns *namespace.Namespace, deletedFromDb bool) {
 	m.ctrl.T.Helper()
@@ -758,6 +1314,58 @@ func (mr *MockContextMockRecorder) UpdateWorkflowExecution(ctx, request interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkflowExecution", reflect.TypeOf((*MockContext)(nil).UpdateWorkflowExecution), ctx, request)
 }
 
+// ValidateVersionHistory mocks base method.
+func (m *MockContext) ValidateVersionHistory(ctx context.Context, workflowKey definition.WorkflowKey) ([]VersionHistoryDefect, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateVersionHistory", ctx, workflowKey)
+	ret0, _ := ret[0].([]VersionHistoryDefect)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateVersionHistory indicates an expected call of ValidateVersionHistory.
+func (mr *MockContextMockRecorder) ValidateVersionHistory(ctx, workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateVersionHistory", reflect.TypeOf((*MockContext)(nil).ValidateVersionHistory), ctx, workflowKey)
+}
+
+// MockTaskDLQWriter is a mock of TaskDLQWriter interface.
+type MockTaskDLQWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskDLQWriterMockRecorder
+}
+
+// MockTaskDLQWriterMockRecorder is the mock recorder for MockTaskDLQWriter.
+type MockTaskDLQWriterMockRecorder struct {
+	mock *MockTaskDLQWriter
+}
+
+// NewMockTaskDLQWriter creates a new mock instance.
+func NewMockTaskDLQWriter(ctrl *gomock.Controller) *MockTaskDLQWriter {
+	mock := &MockTaskDLQWriter{ctrl: ctrl}
+	mock.recorder = &MockTaskDLQWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskDLQWriter) EXPECT() *MockTaskDLQWriterMockRecorder {
+	return m.recorder
+}
+
+// WriteTaskToDLQ mocks base method.
+func (m *MockTaskDLQWriter) WriteTaskToDLQ(ctx context.Context, sourceCluster, targetCluster string, task tasks.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteTaskToDLQ", ctx, sourceCluster, targetCluster, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteTaskToDLQ indicates an expected call of WriteTaskToDLQ.
+func (mr *MockTaskDLQWriterMockRecorder) WriteTaskToDLQ(ctx, sourceCluster, targetCluster, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteTaskToDLQ", reflect.TypeOf((*MockTaskDLQWriter)(nil).WriteTaskToDLQ), ctx, sourceCluster, targetCluster, task)
+}
+
 // MockControllableContext is a mock of ControllableContext interface.
 type MockControllableContext struct {
 	ctrl     *gomock.Controller
@@ -838,6 +1446,21 @@ func (mr *MockControllableContextMockRecorder) AssertOwnership(ctx interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssertOwnership", reflect.TypeOf((*MockControllableContext)(nil).AssertOwnership), ctx)
 }
 
+// CheckpointForRepair mocks base method.
+func (m *MockControllableContext) CheckpointForRepair() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckpointForRepair")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckpointForRepair indicates an expected call of CheckpointForRepair.
+func (mr *MockControllableContextMockRecorder) CheckpointForRepair() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckpointForRepair", reflect.TypeOf((*MockControllableContext)(nil).CheckpointForRepair))
+}
+
 // ConflictResolveWorkflowExecution mocks base method.
 func (m *MockControllableContext) ConflictResolveWorkflowExecution(ctx context.Context, request *persistence.ConflictResolveWorkflowExecutionRequest) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -896,6 +1519,108 @@ func (mr *MockControllableContextMockRecorder) DeleteWorkflowExecution(ctx, work
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkflowExecution", reflect.TypeOf((*MockControllableContext)(nil).DeleteWorkflowExecution), ctx, workflowKey, branchToken, closeExecutionVisibilityTaskID, workflowCloseTime, stage)
 }
 
+// DrainSpeculativeTasks mocks base method.
+func (m *MockControllableContext) DrainSpeculativeTasks() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainSpeculativeTasks")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// DrainSpeculativeTasks indicates an expected call of DrainSpeculativeTasks.
+func (mr *MockControllableContextMockRecorder) DrainSpeculativeTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainSpeculativeTasks", reflect.TypeOf((*MockControllableContext)(nil).DrainSpeculativeTasks))
+}
+
+// DryRunTask mocks base method.
+func (m *MockControllableContext) DryRunTask(ctx context.Context, category tasks.Category, taskID int64) (TaskExecutionTrace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunTask", ctx, category, taskID)
+	ret0, _ := ret[0].(TaskExecutionTrace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunTask indicates an expected call of DryRunTask.
+func (mr *MockControllableContextMockRecorder) DryRunTask(ctx, category, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunTask", reflect.TypeOf((*MockControllableContext)(nil).DryRunTask), ctx, category, taskID)
+}
+
+// EstimateLoad mocks base method.
+func (m *MockControllableContext) EstimateLoad(ctx context.Context) (ShardLoadEstimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateLoad", ctx)
+	ret0, _ := ret[0].(ShardLoadEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateLoad indicates an expected call of EstimateLoad.
+func (mr *MockControllableContextMockRecorder) EstimateLoad(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateLoad", reflect.TypeOf((*MockControllableContext)(nil).EstimateLoad), ctx)
+}
+
+// EstimateQueueDrainTime mocks base method.
+func (m *MockControllableContext) EstimateQueueDrainTime(category tasks.Category) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateQueueDrainTime", category)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateQueueDrainTime indicates an expected call of EstimateQueueDrainTime.
+func (mr *MockControllableContextMockRecorder) EstimateQueueDrainTime(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateQueueDrainTime", reflect.TypeOf((*MockControllableContext)(nil).EstimateQueueDrainTime), category)
+}
+
+// EventsCacheBytes mocks base method.
+func (m *MockControllableContext) EventsCacheBytes() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsCacheBytes")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// EventsCacheBytes indicates an expected call of EventsCacheBytes.
+func (mr *MockControllableContextMockRecorder) EventsCacheBytes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsCacheBytes", reflect.TypeOf((*MockControllableContext)(nil).EventsCacheBytes))
+}
+
+// EventsCacheTopEntries mocks base method.
+func (m *MockControllableContext) EventsCacheTopEntries(n int) []events.CacheEntryInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsCacheTopEntries", n)
+	ret0, _ := ret[0].([]events.CacheEntryInfo)
+	return ret0
+}
+
+// EventsCacheTopEntries indicates an expected call of EventsCacheTopEntries.
+func (mr *MockControllableContextMockRecorder) EventsCacheTopEntries(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsCacheTopEntries", reflect.TypeOf((*MockControllableContext)(nil).EventsCacheTopEntries), n)
+}
+
+// ExportQueueState mocks base method.
+func (m *MockControllableContext) ExportQueueState() (*v13.ShardInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportQueueState")
+	ret0, _ := ret[0].(*v13.ShardInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportQueueState indicates an expected call of ExportQueueState.
+func (mr *MockControllableContextMockRecorder) ExportQueueState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportQueueState", reflect.TypeOf((*MockControllableContext)(nil).ExportQueueState))
+}
+
 // FinishStop mocks base method.
 func (m *MockControllableContext) FinishStop() {
 	m.ctrl.T.Helper()
@@ -905,7 +1630,21 @@ func (m *MockControllableContext) FinishStop() {
 // FinishStop indicates an expected call of FinishStop.
 func (mr *MockControllableContextMockRecorder) FinishStop() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishStop", reflect.TypeOf((*MockControllableContext)(nil).FinishStop))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishStop", reflect.TypeOf((*MockControllableContext)(nil).FinishStop))
+}
+
+// ForceCompleteTask mocks base method.
+func (m *MockControllableContext) ForceCompleteTask(ctx context.Context, category tasks.Category, taskID int64, reason string, dlqWriter TaskDLQWriter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceCompleteTask", ctx, category, taskID, reason, dlqWriter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceCompleteTask indicates an expected call of ForceCompleteTask.
+func (mr *MockControllableContextMockRecorder) ForceCompleteTask(ctx, category, taskID, reason, dlqWriter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceCompleteTask", reflect.TypeOf((*MockControllableContext)(nil).ForceCompleteTask), ctx, category, taskID, reason, dlqWriter)
 }
 
 // GenerateTaskID mocks base method.
@@ -938,6 +1677,21 @@ func (mr *MockControllableContextMockRecorder) GenerateTaskIDs(number interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTaskIDs", reflect.TypeOf((*MockControllableContext)(nil).GenerateTaskIDs), number)
 }
 
+// GetAckedReplicationWatermark mocks base method.
+func (m *MockControllableContext) GetAckedReplicationWatermark(sourceCluster string) (int64, time.Time) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAckedReplicationWatermark", sourceCluster)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(time.Time)
+	return ret0, ret1
+}
+
+// GetAckedReplicationWatermark indicates an expected call of GetAckedReplicationWatermark.
+func (mr *MockControllableContextMockRecorder) GetAckedReplicationWatermark(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAckedReplicationWatermark", reflect.TypeOf((*MockControllableContext)(nil).GetAckedReplicationWatermark), sourceCluster)
+}
+
 // GetArchivalMetadata mocks base method.
 func (m *MockControllableContext) GetArchivalMetadata() archiver.ArchivalMetadata {
 	m.ctrl.T.Helper()
@@ -995,6 +1749,21 @@ func (mr *MockControllableContextMockRecorder) GetCurrentExecution(ctx, request
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentExecution", reflect.TypeOf((*MockControllableContext)(nil).GetCurrentExecution), ctx, request)
 }
 
+// GetCurrentExecutionWithConsistency mocks base method.
+func (m *MockControllableContext) GetCurrentExecutionWithConsistency(ctx context.Context, request *persistence.GetCurrentExecutionRequest, consistency ReadConsistency) (*persistence.GetCurrentExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentExecutionWithConsistency", ctx, request, consistency)
+	ret0, _ := ret[0].(*persistence.GetCurrentExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentExecutionWithConsistency indicates an expected call of GetCurrentExecutionWithConsistency.
+func (mr *MockControllableContextMockRecorder) GetCurrentExecutionWithConsistency(ctx, request, consistency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentExecutionWithConsistency", reflect.TypeOf((*MockControllableContext)(nil).GetCurrentExecutionWithConsistency), ctx, request, consistency)
+}
+
 // GetCurrentTime mocks base method.
 func (m *MockControllableContext) GetCurrentTime(cluster string) time.Time {
 	m.ctrl.T.Helper()
@@ -1094,6 +1863,21 @@ func (mr *MockControllableContextMockRecorder) GetMetricsHandler() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsHandler", reflect.TypeOf((*MockControllableContext)(nil).GetMetricsHandler))
 }
 
+// GetNamespaceFailoverVersion mocks base method.
+func (m *MockControllableContext) GetNamespaceFailoverVersion(namespaceID namespace.ID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceFailoverVersion", namespaceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceFailoverVersion indicates an expected call of GetNamespaceFailoverVersion.
+func (mr *MockControllableContextMockRecorder) GetNamespaceFailoverVersion(namespaceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceFailoverVersion", reflect.TypeOf((*MockControllableContext)(nil).GetNamespaceFailoverVersion), namespaceID)
+}
+
 // GetNamespaceRegistry mocks base method.
 func (m *MockControllableContext) GetNamespaceRegistry() namespace.Registry {
 	m.ctrl.T.Helper()
@@ -1122,6 +1906,20 @@ func (mr *MockControllableContextMockRecorder) GetOwner() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwner", reflect.TypeOf((*MockControllableContext)(nil).GetOwner))
 }
 
+// GetPausedReplicationSources mocks base method.
+func (m *MockControllableContext) GetPausedReplicationSources() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPausedReplicationSources")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetPausedReplicationSources indicates an expected call of GetPausedReplicationSources.
+func (mr *MockControllableContextMockRecorder) GetPausedReplicationSources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPausedReplicationSources", reflect.TypeOf((*MockControllableContext)(nil).GetPausedReplicationSources))
+}
+
 // GetPayloadSerializer mocks base method.
 func (m *MockControllableContext) GetPayloadSerializer() serialization.Serializer {
 	m.ctrl.T.Helper()
@@ -1208,6 +2006,21 @@ func (mr *MockControllableContextMockRecorder) GetRemoteAdminClient(arg0 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteAdminClient", reflect.TypeOf((*MockControllableContext)(nil).GetRemoteAdminClient), arg0)
 }
 
+// GetReplicationDLQDepth mocks base method.
+func (m *MockControllableContext) GetReplicationDLQDepth(sourceCluster string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicationDLQDepth", sourceCluster)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicationDLQDepth indicates an expected call of GetReplicationDLQDepth.
+func (mr *MockControllableContextMockRecorder) GetReplicationDLQDepth(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationDLQDepth", reflect.TypeOf((*MockControllableContext)(nil).GetReplicationDLQDepth), sourceCluster)
+}
+
 // GetReplicationStatus mocks base method.
 func (m *MockControllableContext) GetReplicationStatus(cluster []string) (map[string]*v12.ShardReplicationStatusPerCluster, map[string]*v12.HandoverNamespaceInfo, error) {
 	m.ctrl.T.Helper()
@@ -1280,6 +2093,49 @@ func (mr *MockControllableContextMockRecorder) GetShardID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardID", reflect.TypeOf((*MockControllableContext)(nil).GetShardID))
 }
 
+// GetShardInfoSize mocks base method.
+func (m *MockControllableContext) GetShardInfoSize() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShardInfoSize")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetShardInfoSize indicates an expected call of GetShardInfoSize.
+func (mr *MockControllableContextMockRecorder) GetShardInfoSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardInfoSize", reflect.TypeOf((*MockControllableContext)(nil).GetShardInfoSize))
+}
+
+// GetTaskIDReservations mocks base method.
+func (m *MockControllableContext) GetTaskIDReservations() []TaskIDReservation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskIDReservations")
+	ret0, _ := ret[0].([]TaskIDReservation)
+	return ret0
+}
+
+// GetTaskIDReservations indicates an expected call of GetTaskIDReservations.
+func (mr *MockControllableContextMockRecorder) GetTaskIDReservations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskIDReservations", reflect.TypeOf((*MockControllableContext)(nil).GetTaskIDReservations))
+}
+
+// GetTaskInfo mocks base method.
+func (m *MockControllableContext) GetTaskInfo(category tasks.Category, taskID int64) (tasks.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskInfo", category, taskID)
+	ret0, _ := ret[0].(tasks.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskInfo indicates an expected call of GetTaskInfo.
+func (mr *MockControllableContextMockRecorder) GetTaskInfo(category, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskInfo", reflect.TypeOf((*MockControllableContext)(nil).GetTaskInfo), category, taskID)
+}
+
 // GetThrottledLogger mocks base method.
 func (m *MockControllableContext) GetThrottledLogger() log.Logger {
 	m.ctrl.T.Helper()
@@ -1323,6 +2179,35 @@ func (mr *MockControllableContextMockRecorder) GetWorkflowExecution(ctx, request
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecution", reflect.TypeOf((*MockControllableContext)(nil).GetWorkflowExecution), ctx, request)
 }
 
+// GetWorkflowExecutions mocks base method.
+func (m *MockControllableContext) GetWorkflowExecutions(ctx context.Context, keys []definition.WorkflowKey) (map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse, map[definition.WorkflowKey]error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflowExecutions", ctx, keys)
+	ret0, _ := ret[0].(map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse)
+	ret1, _ := ret[1].(map[definition.WorkflowKey]error)
+	return ret0, ret1
+}
+
+// GetWorkflowExecutions indicates an expected call of GetWorkflowExecutions.
+func (mr *MockControllableContextMockRecorder) GetWorkflowExecutions(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecutions", reflect.TypeOf((*MockControllableContext)(nil).GetWorkflowExecutions), ctx, keys)
+}
+
+// IsReplicationPausedFrom mocks base method.
+func (m *MockControllableContext) IsReplicationPausedFrom(sourceCluster string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReplicationPausedFrom", sourceCluster)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsReplicationPausedFrom indicates an expected call of IsReplicationPausedFrom.
+func (mr *MockControllableContextMockRecorder) IsReplicationPausedFrom(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReplicationPausedFrom", reflect.TypeOf((*MockControllableContext)(nil).IsReplicationPausedFrom), sourceCluster)
+}
+
 // IsValid mocks base method.
 func (m *MockControllableContext) IsValid() bool {
 	m.ctrl.T.Helper()
@@ -1337,6 +2222,63 @@ func (mr *MockControllableContextMockRecorder) IsValid() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsValid", reflect.TypeOf((*MockControllableContext)(nil).IsValid))
 }
 
+// IsWorkflowQuarantined mocks base method.
+func (m *MockControllableContext) IsWorkflowQuarantined(workflowKey definition.WorkflowKey) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsWorkflowQuarantined", workflowKey)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsWorkflowQuarantined indicates an expected call of IsWorkflowQuarantined.
+func (mr *MockControllableContextMockRecorder) IsWorkflowQuarantined(workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsWorkflowQuarantined", reflect.TypeOf((*MockControllableContext)(nil).IsWorkflowQuarantined), workflowKey)
+}
+
+// ListExecutionsWithPendingSignals mocks base method.
+func (m *MockControllableContext) ListExecutionsWithPendingSignals(ctx context.Context, limit int) ([]definition.WorkflowKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExecutionsWithPendingSignals", ctx, limit)
+	ret0, _ := ret[0].([]definition.WorkflowKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExecutionsWithPendingSignals indicates an expected call of ListExecutionsWithPendingSignals.
+func (mr *MockControllableContextMockRecorder) ListExecutionsWithPendingSignals(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExecutionsWithPendingSignals", reflect.TypeOf((*MockControllableContext)(nil).ListExecutionsWithPendingSignals), ctx, limit)
+}
+
+// ListHandoverNamespaces mocks base method.
+func (m *MockControllableContext) ListHandoverNamespaces() []namespace.ID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHandoverNamespaces")
+	ret0, _ := ret[0].([]namespace.ID)
+	return ret0
+}
+
+// ListHandoverNamespaces indicates an expected call of ListHandoverNamespaces.
+func (mr *MockControllableContextMockRecorder) ListHandoverNamespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHandoverNamespaces", reflect.TypeOf((*MockControllableContext)(nil).ListHandoverNamespaces))
+}
+
+// ListQuarantinedWorkflows mocks base method.
+func (m *MockControllableContext) ListQuarantinedWorkflows() []*v13.QuarantinedWorkflow {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuarantinedWorkflows")
+	ret0, _ := ret[0].([]*v13.QuarantinedWorkflow)
+	return ret0
+}
+
+// ListQuarantinedWorkflows indicates an expected call of ListQuarantinedWorkflows.
+func (mr *MockControllableContextMockRecorder) ListQuarantinedWorkflows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuarantinedWorkflows", reflect.TypeOf((*MockControllableContext)(nil).ListQuarantinedWorkflows))
+}
+
 // NewVectorClock mocks base method.
 func (m *MockControllableContext) NewVectorClock() (*v11.VectorClock, error) {
 	m.ctrl.T.Helper()
@@ -1352,6 +2294,189 @@ func (mr *MockControllableContextMockRecorder) NewVectorClock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewVectorClock", reflect.TypeOf((*MockControllableContext)(nil).NewVectorClock))
 }
 
+// OldestPendingTaskTime mocks base method.
+func (m *MockControllableContext) OldestPendingTaskTime(category tasks.Category) (time.Time, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OldestPendingTaskTime", category)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// OldestPendingTaskTime indicates an expected call of OldestPendingTaskTime.
+func (mr *MockControllableContextMockRecorder) OldestPendingTaskTime(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OldestPendingTaskTime", reflect.TypeOf((*MockControllableContext)(nil).OldestPendingTaskTime), category)
+}
+
+// PauseReplicationFrom mocks base method.
+func (m *MockControllableContext) PauseReplicationFrom(sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseReplicationFrom", sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseReplicationFrom indicates an expected call of PauseReplicationFrom.
+func (mr *MockControllableContextMockRecorder) PauseReplicationFrom(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseReplicationFrom", reflect.TypeOf((*MockControllableContext)(nil).PauseReplicationFrom), sourceCluster)
+}
+
+// ProbeLiveness mocks base method.
+func (m *MockControllableContext) ProbeLiveness(ctx context.Context, timeout time.Duration) LivenessReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProbeLiveness", ctx, timeout)
+	ret0, _ := ret[0].(LivenessReport)
+	return ret0
+}
+
+// ProbeLiveness indicates an expected call of ProbeLiveness.
+func (mr *MockControllableContextMockRecorder) ProbeLiveness(ctx, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProbeLiveness", reflect.TypeOf((*MockControllableContext)(nil).ProbeLiveness), ctx, timeout)
+}
+
+// QuarantineWorkflow mocks base method.
+func (m *MockControllableContext) QuarantineWorkflow(workflowKey definition.WorkflowKey, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuarantineWorkflow", workflowKey, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QuarantineWorkflow indicates an expected call of QuarantineWorkflow.
+func (mr *MockControllableContextMockRecorder) QuarantineWorkflow(workflowKey, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuarantineWorkflow", reflect.TypeOf((*MockControllableContext)(nil).QuarantineWorkflow), workflowKey, reason)
+}
+
+// RebuildQueueState mocks base method.
+func (m *MockControllableContext) RebuildQueueState(ctx context.Context, category tasks.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildQueueState", ctx, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildQueueState indicates an expected call of RebuildQueueState.
+func (mr *MockControllableContextMockRecorder) RebuildQueueState(ctx, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildQueueState", reflect.TypeOf((*MockControllableContext)(nil).RebuildQueueState), ctx, category)
+}
+
+// RecentReplicationTasks mocks base method.
+func (m *MockControllableContext) RecentReplicationTasks() []ReplicationTaskAudit {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentReplicationTasks")
+	ret0, _ := ret[0].([]ReplicationTaskAudit)
+	return ret0
+}
+
+// RecentReplicationTasks indicates an expected call of RecentReplicationTasks.
+func (mr *MockControllableContextMockRecorder) RecentReplicationTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentReplicationTasks", reflect.TypeOf((*MockControllableContext)(nil).RecentReplicationTasks))
+}
+
+// RecordReplicationTask mocks base method.
+func (m *MockControllableContext) RecordReplicationTask(audit ReplicationTaskAudit) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordReplicationTask", audit)
+}
+
+// RecordReplicationTask indicates an expected call of RecordReplicationTask.
+func (mr *MockControllableContextMockRecorder) RecordReplicationTask(audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordReplicationTask", reflect.TypeOf((*MockControllableContext)(nil).RecordReplicationTask), audit)
+}
+
+// ReleaseTaskIDRange mocks base method.
+func (m *MockControllableContext) ReleaseTaskIDRange(start int64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseTaskIDRange", start)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReleaseTaskIDRange indicates an expected call of ReleaseTaskIDRange.
+func (mr *MockControllableContextMockRecorder) ReleaseTaskIDRange(start interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseTaskIDRange", reflect.TypeOf((*MockControllableContext)(nil).ReleaseTaskIDRange), start)
+}
+
+// ReserveTaskIDRange mocks base method.
+func (m *MockControllableContext) ReserveTaskIDRange(n int) (TaskIDReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveTaskIDRange", n)
+	ret0, _ := ret[0].(TaskIDReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveTaskIDRange indicates an expected call of ReserveTaskIDRange.
+func (mr *MockControllableContextMockRecorder) ReserveTaskIDRange(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveTaskIDRange", reflect.TypeOf((*MockControllableContext)(nil).ReserveTaskIDRange), n)
+}
+
+// RestoreQueueState mocks base method.
+func (m *MockControllableContext) RestoreQueueState(snapshot *v13.ShardInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreQueueState", snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreQueueState indicates an expected call of RestoreQueueState.
+func (mr *MockControllableContextMockRecorder) RestoreQueueState(snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreQueueState", reflect.TypeOf((*MockControllableContext)(nil).RestoreQueueState), snapshot)
+}
+
+// ResumeReplicationFrom mocks base method.
+func (m *MockControllableContext) ResumeReplicationFrom(sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeReplicationFrom", sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeReplicationFrom indicates an expected call of ResumeReplicationFrom.
+func (mr *MockControllableContextMockRecorder) ResumeReplicationFrom(sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeReplicationFrom", reflect.TypeOf((*MockControllableContext)(nil).ResumeReplicationFrom), sourceCluster)
+}
+
+// RollbackToCheckpoint mocks base method.
+func (m *MockControllableContext) RollbackToCheckpoint(checkpointID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackToCheckpoint", checkpointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackToCheckpoint indicates an expected call of RollbackToCheckpoint.
+func (mr *MockControllableContextMockRecorder) RollbackToCheckpoint(checkpointID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackToCheckpoint", reflect.TypeOf((*MockControllableContext)(nil).RollbackToCheckpoint), checkpointID)
+}
+
+// ScanForDuplicateTaskIDs mocks base method.
+func (m *MockControllableContext) ScanForDuplicateTaskIDs(ctx context.Context, category tasks.Category, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanForDuplicateTaskIDs", ctx, category, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanForDuplicateTaskIDs indicates an expected call of ScanForDuplicateTaskIDs.
+func (mr *MockControllableContextMockRecorder) ScanForDuplicateTaskIDs(ctx, category, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanForDuplicateTaskIDs", reflect.TypeOf((*MockControllableContext)(nil).ScanForDuplicateTaskIDs), ctx, category, limit)
+}
+
 // SetCurrentTime mocks base method.
 func (m *MockControllableContext) SetCurrentTime(cluster string, currentTime time.Time) {
 	m.ctrl.T.Helper()
@@ -1364,6 +2489,18 @@ func (mr *MockControllableContextMockRecorder) SetCurrentTime(cluster, currentTi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrentTime", reflect.TypeOf((*MockControllableContext)(nil).SetCurrentTime), cluster, currentTime)
 }
 
+// SetMaintenanceMode mocks base method.
+func (m *MockControllableContext) SetMaintenanceMode(on bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMaintenanceMode", on)
+}
+
+// SetMaintenanceMode indicates an expected call of SetMaintenanceMode.
+func (mr *MockControllableContextMockRecorder) SetMaintenanceMode(on interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenanceMode", reflect.TypeOf((*MockControllableContext)(nil).SetMaintenanceMode), on)
+}
+
 // SetQueueState mocks base method.
 func (m *MockControllableContext) SetQueueState(category tasks.Category, tasksCompleted int, state *v13.QueueState) error {
 	m.ctrl.T.Helper()
@@ -1407,6 +2544,20 @@ func (mr *MockControllableContextMockRecorder) StateMachineRegistry() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateMachineRegistry", reflect.TypeOf((*MockControllableContext)(nil).StateMachineRegistry))
 }
 
+// TaskGenerationByWorkflowType mocks base method.
+func (m *MockControllableContext) TaskGenerationByWorkflowType(window time.Duration) map[string]int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TaskGenerationByWorkflowType", window)
+	ret0, _ := ret[0].(map[string]int64)
+	return ret0
+}
+
+// TaskGenerationByWorkflowType indicates an expected call of TaskGenerationByWorkflowType.
+func (mr *MockControllableContextMockRecorder) TaskGenerationByWorkflowType(window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TaskGenerationByWorkflowType", reflect.TypeOf((*MockControllableContext)(nil).TaskGenerationByWorkflowType), window)
+}
+
 // UnloadForOwnershipLost mocks base method.
 func (m *MockControllableContext) UnloadForOwnershipLost() {
 	m.ctrl.T.Helper()
@@ -1419,6 +2570,47 @@ func (mr *MockControllableContextMockRecorder) UnloadForOwnershipLost() *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadForOwnershipLost", reflect.TypeOf((*MockControllableContext)(nil).UnloadForOwnershipLost))
 }
 
+// UnquarantineWorkflow mocks base method.
+func (m *MockControllableContext) UnquarantineWorkflow(workflowKey definition.WorkflowKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnquarantineWorkflow", workflowKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnquarantineWorkflow indicates an expected call of UnquarantineWorkflow.
+func (mr *MockControllableContextMockRecorder) UnquarantineWorkflow(workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnquarantineWorkflow", reflect.TypeOf((*MockControllableContext)(nil).UnquarantineWorkflow), workflowKey)
+}
+
+// UpcomingTimers mocks base method.
+func (m *MockControllableContext) UpcomingTimers(ctx context.Context, n int) ([]TimerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpcomingTimers", ctx, n)
+	ret0, _ := ret[0].([]TimerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpcomingTimers indicates an expected call of UpcomingTimers.
+func (mr *MockControllableContextMockRecorder) UpcomingTimers(ctx, n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpcomingTimers", reflect.TypeOf((*MockControllableContext)(nil).UpcomingTimers), ctx, n)
+}
+
+// UpdateAckedReplicationWatermark mocks base method.
+func (m *MockControllableContext) UpdateAckedReplicationWatermark(sourceCluster string, sourceShardID int32, ackTaskID int64, ackTimestamp time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateAckedReplicationWatermark", sourceCluster, sourceShardID, ackTaskID, ackTimestamp)
+}
+
+// UpdateAckedReplicationWatermark indicates an expected call of UpdateAckedReplicationWatermark.
+func (mr *MockControllableContextMockRecorder) UpdateAckedReplicationWatermark(sourceCluster, sourceShardID, ackTaskID, ackTimestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAckedReplicationWatermark", reflect.TypeOf((*MockControllableContext)(nil).UpdateAckedReplicationWatermark), sourceCluster, sourceShardID, ackTaskID, ackTimestamp)
+}
+
 // UpdateHandoverNamespace mocks base method.
 func (m *MockControllableContext) UpdateHandoverNamespace(ns *namespace.Namespace, deletedFromDb bool) {
 	m.ctrl.T.Helper()
@@ -1499,3 +2691,18 @@ func (mr *MockControllableContextMockRecorder) UpdateWorkflowExecution(ctx, requ
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkflowExecution", reflect.TypeOf((*MockControllableContext)(nil).UpdateWorkflowExecution), ctx, request)
 }
+
+// ValidateVersionHistory mocks base method.
+func (m *MockControllableContext) ValidateVersionHistory(ctx context.Context, workflowKey definition.WorkflowKey) ([]VersionHistoryDefect, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateVersionHistory", ctx, workflowKey)
+	ret0, _ := ret[0].([]VersionHistoryDefect)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateVersionHistory indicates an expected call of ValidateVersionHistory.
+func (mr *MockControllableContextMockRecorder) ValidateVersionHistory(ctx, workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateVersionHistory", reflect.TypeOf((*MockControllableContext)(nil).ValidateVersionHistory), ctx, workflowKey)
+}
@@ -28,7 +28,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -36,17 +39,30 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
 	"go.temporal.io/server/api/historyservice/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/primitives/timestamp"
+	ctasks "go.temporal.io/server/common/tasks"
+	"go.temporal.io/server/service/history/consts"
+	"go.temporal.io/server/service/history/events"
 	"go.temporal.io/server/service/history/tasks"
 	"go.temporal.io/server/service/history/tests"
 )
@@ -431,6 +447,57 @@ func (s *contextSuite) TestAcquireShardNoError() {
 	s.Assert().Equal(contextStateAcquired, s.mockShard.state)
 }
 
+func (s *contextSuite) TestAcquireShardMetrics() {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	defer metricsHandler.StopCapture(capture)
+	s.mockShard.metricsHandler = metricsHandler
+	s.mockShard.config.ShardAcquisitionMetricsShardIDSampling = dynamicconfig.GetIntPropertyFn(1)
+
+	s.mockShard.state = contextStateAcquiring
+	s.mockShard.acquireShardRetryPolicy = backoff.NewExponentialRetryPolicy(time.Nanosecond).
+		WithMaximumAttempts(5)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+		Return(serviceerror.NewUnavailable("temp error")).Times(2)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+		Return(nil).Times(1)
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).MinTimes(1)
+
+	s.mockShard.acquireShard()
+	s.Equal(contextStateAcquired, s.mockShard.state)
+
+	snapshot := capture.Snapshot()
+	failures := snapshot[metrics.ShardAcquisitionFailures.Name()]
+	s.Len(failures, 2)
+	for _, failure := range failures {
+		s.Equal(int64(1), failure.Value)
+		s.Equal(strconv.Itoa(int(s.shardID)), failure.Tags["shard_id"])
+		s.Equal("serviceerror.Unavailable", failure.Tags[metrics.ErrorTypeTagName])
+	}
+	durations := snapshot[metrics.ShardAcquisitionDuration.Name()]
+	s.Len(durations, 1)
+	s.Equal(strconv.Itoa(int(s.shardID)), durations[0].Tags["shard_id"])
+}
+
+func (s *contextSuite) TestAcquireShardMetrics_ShardIDSampledOut() {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	defer metricsHandler.StopCapture(capture)
+	s.mockShard.metricsHandler = metricsHandler
+	s.mockShard.config.ShardAcquisitionMetricsShardIDSampling = dynamicconfig.GetIntPropertyFn(0)
+
+	s.mockShard.state = contextStateAcquiring
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+		Return(nil).Times(1)
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).MinTimes(1)
+
+	s.mockShard.acquireShard()
+
+	durations := capture.Snapshot()[metrics.ShardAcquisitionDuration.Name()]
+	s.Len(durations, 1)
+	s.NotEqual(strconv.Itoa(int(s.shardID)), durations[0].Tags["shard_id"])
+}
+
 func (s *contextSuite) TestHandoverNamespace() {
 	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).Times(1)
 
@@ -489,6 +556,131 @@ func (s *contextSuite) TestHandoverNamespace() {
 	s.False(ok)
 }
 
+func (s *contextSuite) TestEventsCacheTopEntries() {
+	s.mockShard.config.NumberOfShards = 4
+	eventsCache := events.NewHostLevelEventsCache(
+		s.mockExecutionManager,
+		s.mockShard.config,
+		metrics.NoopMetricsHandler,
+		s.mockShard.GetLogger(),
+		false,
+	)
+	s.mockShard.SetEventsCacheForTesting(eventsCache)
+
+	// find workflow IDs owned by this shard and by another shard
+	var ownedWorkflowIDs []string
+	otherWorkflowID := ""
+	for i := 0; len(ownedWorkflowIDs) < 2 || otherWorkflowID == ""; i++ {
+		workflowID := fmt.Sprintf("workflow-%d", i)
+		if s.mockShard.config.GetShardID(tests.NamespaceID, workflowID) == s.shardID {
+			ownedWorkflowIDs = append(ownedWorkflowIDs, workflowID)
+		} else {
+			otherWorkflowID = workflowID
+		}
+	}
+	putEvents := func(workflowID string, eventIDs ...int64) {
+		for _, eventID := range eventIDs {
+			eventsCache.PutEvent(
+				events.EventKey{NamespaceID: tests.NamespaceID, WorkflowID: workflowID, RunID: tests.RunID, EventID: eventID},
+				&historypb.HistoryEvent{EventId: eventID, EventType: enums.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED},
+			)
+		}
+	}
+	putEvents(ownedWorkflowIDs[0], 5, 6)
+	putEvents(ownedWorkflowIDs[1], 5, 6, 7, 8)
+	putEvents(otherWorkflowID, 5, 6, 7, 8, 9, 10)
+
+	s.Nil(s.mockShard.EventsCacheTopEntries(10), "disabled by default")
+
+	s.mockShard.config.EventsCacheTopEntriesEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	entries := s.mockShard.EventsCacheTopEntries(10)
+	s.Len(entries, 2)
+	s.Equal(ownedWorkflowIDs[1], entries[0].WorkflowID)
+	s.Equal(4, entries[0].EventCount)
+	s.Equal(int64(5), entries[0].MinEventID)
+	s.Equal(int64(8), entries[0].MaxEventID)
+	s.Equal(ownedWorkflowIDs[0], entries[1].WorkflowID)
+	s.Equal(2, entries[1].EventCount)
+	s.Greater(entries[0].SizeBytes, entries[1].SizeBytes)
+
+	s.Len(s.mockShard.EventsCacheTopEntries(1), 1)
+}
+
+func (s *contextSuite) TestReserveTaskIDRange() {
+	rangeSize := 1 << s.mockShard.config.RangeSizeBits
+	_, err := s.mockShard.ReserveTaskIDRange(0)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+	_, err = s.mockShard.ReserveTaskIDRange(rangeSize + 1)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+
+	before, err := s.mockShard.GenerateTaskIDs(5)
+	s.NoError(err)
+	reservation, err := s.mockShard.ReserveTaskIDRange(100)
+	s.NoError(err)
+	start, end := reservation.Start, reservation.End
+	s.Equal(int64(99), end-start)
+	after, err := s.mockShard.GenerateTaskIDs(5)
+	s.NoError(err)
+
+	for _, taskID := range append(before, after...) {
+		s.False(taskID >= start && taskID <= end, "task ID %d is in reserved range [%d, %d]", taskID, start, end)
+	}
+	s.Less(before[len(before)-1], start)
+	s.Greater(after[0], end)
+
+	// queues are held below the reservation until it's released
+	s.Equal([]TaskIDReservation{reservation}, s.mockShard.GetTaskIDReservations())
+	s.Equal(start, s.mockShard.GetQueueExclusiveHighReadWatermark(tasks.CategoryTransfer).TaskID)
+	s.True(s.mockShard.ReleaseTaskIDRange(start))
+	s.Empty(s.mockShard.GetTaskIDReservations())
+	s.Greater(s.mockShard.GetQueueExclusiveHighReadWatermark(tasks.CategoryTransfer).TaskID, end)
+
+	// reserving a whole range renews the range ID, and uses all of it
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	reservation, err = s.mockShard.ReserveTaskIDRange(rangeSize)
+	s.NoError(err)
+	s.Equal(int64(rangeSize-1), reservation.End-reservation.Start)
+	taskID, err := s.mockShard.GenerateTaskID()
+	s.NoError(err)
+	s.Greater(taskID, reservation.End)
+}
+
+func (s *contextSuite) TestListHandoverNamespaces() {
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).Times(1)
+	s.mockNamespaceCache.EXPECT().GetNamespaceID(tests.Namespace).Return(tests.NamespaceID, nil).AnyTimes()
+	newNamespace := func(state enums.ReplicationState) *namespace.Namespace {
+		return namespace.NewGlobalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: tests.NamespaceID.String(), Name: tests.Namespace.String()},
+			&persistencespb.NamespaceConfig{
+				Retention: timestamp.DurationFromDays(1),
+			},
+			&persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters: []string{
+					cluster.TestCurrentClusterName,
+					cluster.TestAlternativeClusterName,
+				},
+				State: state,
+			},
+			tests.Version,
+		)
+	}
+	s.Empty(s.mockShard.ListHandoverNamespaces())
+
+	s.mockShard.UpdateHandoverNamespace(newNamespace(enums.REPLICATION_STATE_HANDOVER), false)
+	s.Equal([]namespace.ID{tests.NamespaceID}, s.mockShard.ListHandoverNamespaces())
+	_, handoverNS, err := s.mockShard.GetReplicationStatus([]string{})
+	s.NoError(err)
+	s.Len(handoverNS, 1)
+	s.Contains(handoverNS, tests.Namespace.String())
+
+	s.mockShard.UpdateHandoverNamespace(newNamespace(enums.REPLICATION_STATE_NORMAL), false)
+	s.Empty(s.mockShard.ListHandoverNamespaces())
+	_, handoverNS, err = s.mockShard.GetReplicationStatus([]string{})
+	s.NoError(err)
+	s.Empty(handoverNS)
+}
+
 func (s *contextSuite) TestUpdateGetRemoteClusterInfo_Legacy_8_4() {
 	clusterMetadata := cluster.NewMockMetadata(s.controller)
 	clusterMetadata.EXPECT().GetClusterID().Return(cluster.TestCurrentClusterInitialFailoverVersion).AnyTimes()
@@ -676,6 +868,50 @@ func (s *contextSuite) TestShardStopReasonAssertOwnership() {
 	s.True(s.mockShard.stoppedForOwnershipLost())
 }
 
+func (s *contextSuite) TestAssertOwnership_Freshness() {
+	s.mockShard.state = contextStateAcquired
+	s.mockShard.config.ShardOwnershipFreshness = dynamicconfig.GetDurationPropertyFn(time.Second)
+	now := time.Now()
+	s.timeSource.Update(now)
+
+	// a successful check is reused within the freshness window
+	s.mockShardManager.EXPECT().AssertShardOwnership(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	s.NoError(s.mockShard.AssertOwnership(context.Background()))
+	s.timeSource.Update(now.Add(500 * time.Millisecond))
+	s.NoError(s.mockShard.AssertOwnership(context.Background()))
+
+	// and checked again after it
+	s.timeSource.Update(now.Add(time.Second))
+	s.mockShardManager.EXPECT().AssertShardOwnership(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	s.NoError(s.mockShard.AssertOwnership(context.Background()))
+
+	// failures are never reused
+	s.mockShardManager.EXPECT().AssertShardOwnership(gomock.Any(), gomock.Any()).
+		Return(&persistence.ConditionFailedError{Msg: "some error"}).Times(1)
+	s.timeSource.Update(now.Add(3 * time.Second))
+	s.Error(s.mockShard.AssertOwnership(context.Background()))
+	s.Nil(s.mockShard.lastOwnershipAssertion.Load())
+}
+
+func (s *contextSuite) TestAssertOwnership_FreshnessInvalidatedOnOwnershipLost() {
+	s.mockShard.state = contextStateAcquired
+	s.mockShard.config.ShardOwnershipFreshness = dynamicconfig.GetDurationPropertyFn(time.Minute)
+	s.mockShardManager.EXPECT().AssertShardOwnership(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	s.NoError(s.mockShard.AssertOwnership(context.Background()))
+	s.NotNil(s.mockShard.lastOwnershipAssertion.Load())
+
+	// another request finds out the shard was stolen
+	s.mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).
+		Return(nil, &persistence.ShardOwnershipLostError{}).Times(1)
+	_, err := s.mockShard.GetCurrentExecution(context.Background(), nil)
+	s.Error(err)
+
+	s.Nil(s.mockShard.lastOwnershipAssertion.Load())
+	err = s.mockShard.AssertOwnership(context.Background())
+	var ownershipLost *persistence.ShardOwnershipLostError
+	s.ErrorAs(err, &ownershipLost)
+}
+
 func (s *contextSuite) TestShardStopReasonShardRead() {
 	s.mockShard.state = contextStateAcquired
 	s.mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), gomock.Any()).
@@ -720,6 +956,123 @@ func (s *contextSuite) TestShardStopReasonCloseShard() {
 	s.False(s.mockShard.stoppedForOwnershipLost())
 }
 
+func (s *contextSuite) TestDrainSpeculativeTasks() {
+	now := time.Now()
+	s.timeSource.Update(now)
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).Times(3)
+
+	pending := s.addSpeculativeTask(now.Add(5 * time.Second))
+	cancelled := s.addSpeculativeTask(now.Add(5 * time.Second))
+	cancelled.Cancel()
+	fired := s.addSpeculativeTask(now.Add(time.Second))
+	s.timeSource.Update(now.Add(2 * time.Second))
+
+	s.Equal(1, s.mockShard.DrainSpeculativeTasks())
+	s.Equal(ctasks.TaskStateCancelled, pending.State())
+	s.NotEqual(ctasks.TaskStateCancelled, fired.State())
+
+	s.Equal(0, s.mockShard.DrainSpeculativeTasks())
+}
+
+func (s *contextSuite) TestLockWaitMetrics() {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	defer metricsHandler.StopCapture(capture)
+	s.mockShard.metricsHandler = metricsHandler
+
+	// off by default
+	s.mockShard.rLock()
+	s.mockShard.rUnlock()
+	s.Empty(capture.Snapshot()[metrics.ShardLockWaitLatency.Name()])
+
+	s.mockShard.config.ShardLockWaitMetricsEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	// the flag is cached, so it's only picked up once the cache is refreshed
+	s.mockShard.rLock()
+	s.mockShard.rUnlock()
+	s.Empty(capture.Snapshot()[metrics.ShardLockWaitLatency.Name()])
+	s.mockShard.lockWaitMetricsRefreshAt.Store(0)
+
+	s.mockShard.wLock()
+	acquired := make(chan struct{})
+	go func() {
+		s.mockShard.rLock()
+		defer s.mockShard.rUnlock()
+		close(acquired)
+	}()
+	const holdTime = 50 * time.Millisecond
+	time.Sleep(holdTime)
+	s.mockShard.wUnlock()
+	<-acquired
+
+	recordings := capture.Snapshot()[metrics.ShardLockWaitLatency.Name()]
+	s.NotEmpty(recordings)
+	maxWait := time.Duration(0)
+	for _, recording := range recordings {
+		s.Equal(strconv.Itoa(int(s.shardID)), recording.Tags["shard_id"])
+		maxWait = max(maxWait, recording.Value.(time.Duration))
+	}
+	// the rLock waited for the wLock to be released
+	s.GreaterOrEqual(maxWait, holdTime)
+}
+
+func (s *contextSuite) TestFinishStop_DrainsSpeculativeTasks() {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	defer metricsHandler.StopCapture(capture)
+	s.mockShard.metricsHandler = metricsHandler
+
+	now := time.Now()
+	s.timeSource.Update(now)
+	s.mockShard.state = contextStateAcquired
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).Times(2)
+	s.mockHistoryEngine.EXPECT().Stop().Times(1)
+	task1 := s.addSpeculativeTask(now.Add(5 * time.Second))
+	task2 := s.addSpeculativeTask(now.Add(10 * time.Second))
+
+	s.mockShard.FinishStop()
+
+	s.Equal(ctasks.TaskStateCancelled, task1.State())
+	s.Equal(ctasks.TaskStateCancelled, task2.State())
+	snapshot := capture.Snapshot()
+	s.Len(snapshot[metrics.SpeculativeWorkflowTaskTimeoutTasksDropped.Name()], 0)
+	drained := snapshot[metrics.SpeculativeWorkflowTaskTimeoutTasksDrained.Name()]
+	s.Len(drained, 1)
+	s.Equal(int64(2), drained[0].Value)
+}
+
+func (s *contextSuite) TestFinishStop_OwnershipLost_DropsSpeculativeTasks() {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	defer metricsHandler.StopCapture(capture)
+	s.mockShard.metricsHandler = metricsHandler
+
+	now := time.Now()
+	s.timeSource.Update(now)
+	s.mockShard.state = contextStateAcquired
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).Times(1)
+	s.mockHistoryEngine.EXPECT().Stop().Times(1)
+	s.addSpeculativeTask(now.Add(5 * time.Second))
+
+	s.mockShard.UnloadForOwnershipLost()
+	s.mockShard.FinishStop()
+
+	snapshot := capture.Snapshot()
+	s.Len(snapshot[metrics.SpeculativeWorkflowTaskTimeoutTasksDrained.Name()], 0)
+	dropped := snapshot[metrics.SpeculativeWorkflowTaskTimeoutTasksDropped.Name()]
+	s.Len(dropped, 1)
+	s.Equal(int64(1), dropped[0].Value)
+}
+
+func (s *contextSuite) addSpeculativeTask(visibilityTime time.Time) *tasks.WorkflowTaskTimeoutTask {
+	task := &tasks.WorkflowTaskTimeoutTask{
+		WorkflowKey:         definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID),
+		VisibilityTimestamp: visibilityTime,
+		InMemory:            true,
+	}
+	s.NoError(s.mockShard.AddSpeculativeWorkflowTaskTimeoutTask(task))
+	return task
+}
+
 func (s *contextSuite) TestUpdateShardInfo_CallbackIsInvoked_EvenWhenNotPersisted() {
 	s.mockShard.state = contextStateAcquired
 
@@ -904,3 +1257,1567 @@ func (s *contextSuite) TestUpdateShardInfo_FirstUpdate() {
 	s.True(called)
 	s.Equal(s.mockShard.tasksCompletedSinceLastUpdate, 0)
 }
+
+func (s *contextSuite) TestProbeLiveness_NotStuck() {
+	report := s.mockShard.ProbeLiveness(context.Background(), time.Second)
+	s.False(report.Stuck)
+}
+
+func (s *contextSuite) TestProbeLiveness_Stuck() {
+	// lock holders are only tracked once the shard has been probed
+	s.mockShard.wLock()
+	s.mockShard.wUnlock()
+	s.Nil(s.mockShard.lockHolder.Load())
+	s.False(s.mockShard.ProbeLiveness(context.Background(), time.Second).Stuck)
+
+	now := time.Now()
+	s.timeSource.Update(now)
+	s.mockShard.wLock()
+
+	s.timeSource.Update(now.Add(time.Minute))
+	report := s.mockShard.ProbeLiveness(context.Background(), 50*time.Millisecond)
+	s.True(report.Stuck)
+	s.Contains(report.HolderOperation, "TestProbeLiveness_Stuck")
+	s.Equal(time.Minute, report.HeldFor)
+	s.Empty(report.HolderStack)
+
+	// a second probe waits on the pending acquisition
+	pending := s.mockShard.pendingLivenessProbe.Load()
+	s.NotNil(pending)
+	s.True(s.mockShard.ProbeLiveness(context.Background(), 10*time.Millisecond).Stuck)
+	s.Equal(pending, s.mockShard.pendingLivenessProbe.Load())
+
+	s.mockShard.wUnlock()
+	s.Nil(s.mockShard.lockHolder.Load())
+	<-pending.acquired
+	s.False(s.mockShard.ProbeLiveness(context.Background(), time.Second).Stuck)
+}
+
+func (s *contextSuite) TestProbeLiveness_Readers() {
+	s.mockShard.rLock()
+	report := s.mockShard.ProbeLiveness(context.Background(), 50*time.Millisecond)
+	s.True(report.Stuck)
+	s.Empty(report.HolderOperation)
+	pending := s.mockShard.pendingLivenessProbe.Load()
+
+	s.mockShard.rUnlock()
+	<-pending.acquired
+}
+
+func (s *contextSuite) TestRebuildQueueState() {
+	workflowKey := definition.NewWorkflowKey(
+		tests.NamespaceID.String(),
+		tests.WorkflowID,
+		tests.RunID,
+	)
+	pendingTask := tasks.NewFakeTask(workflowKey, tasks.CategoryTransfer, time.Time{})
+	pendingTask.SetTaskID(100)
+
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+			s.Equal(tasks.CategoryTransfer, request.TaskCategory)
+			s.Equal(tasks.MinimumKey, request.InclusiveMinTaskKey)
+			return &persistence.GetHistoryTasksResponse{Tasks: []tasks.Task{pendingTask}}, nil
+		},
+	).Times(1)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	err := s.mockShard.RebuildQueueState(context.Background(), tasks.CategoryTransfer)
+	s.NoError(err)
+	s.False(s.mockShard.IsValid(), "shard should be unloaded after rebuilding queue state")
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	s.Len(queueState.ReaderStates, 1)
+	scopes := queueState.ReaderStates[common.DefaultQueueReaderID].Scopes
+	s.Len(scopes, 1)
+	s.Equal(pendingTask.GetKey(), ConvertFromPersistenceTaskKey(scopes[0].Range.InclusiveMin))
+	s.Equal(queueState.ExclusiveReaderHighWatermark.TaskId, scopes[0].Range.ExclusiveMax.TaskId)
+}
+
+func (s *contextSuite) TestRebuildQueueState_NoPendingTasks() {
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{}, nil).Times(1)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	err := s.mockShard.RebuildQueueState(context.Background(), tasks.CategoryTransfer)
+	s.NoError(err)
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	s.Empty(queueState.ReaderStates)
+	s.NotNil(queueState.ExclusiveReaderHighWatermark)
+}
+
+func (s *contextSuite) TestRebuildQueueState_RegressHighWatermark() {
+	s.mockShard.shardInfo.QueueStates[int32(tasks.CategoryTransfer.ID())] = &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(math.MaxInt64)),
+	}
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{}, nil).Times(1)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.mockShard.RebuildQueueState(context.Background(), tasks.CategoryTransfer)
+	s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestRebuildQueueState_Replication() {
+	err := s.mockShard.RebuildQueueState(context.Background(), tasks.CategoryReplication)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestRebuildQueueState_PersistFailure() {
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{}, nil).Times(1)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).
+		Return(&persistence.ShardOwnershipLostError{ShardID: s.shardID}).Times(1)
+
+	s.timeSource.Update(time.Now())
+	lastUpdated := s.mockShard.lastUpdated
+
+	err := s.mockShard.RebuildQueueState(context.Background(), tasks.CategoryTransfer)
+	s.ErrorAs(err, new(*persistence.ShardOwnershipLostError))
+	// a failed write reverts the update time so that it can be retried
+	s.Equal(lastUpdated, s.mockShard.lastUpdated)
+}
+
+func (s *contextSuite) TestGetTaskInfo() {
+	workflowKey := definition.NewWorkflowKey(
+		tests.NamespaceID.String(),
+		tests.WorkflowID,
+		tests.RunID,
+	)
+	task := tasks.NewFakeTask(workflowKey, tasks.CategoryTransfer, time.Time{})
+	task.SetTaskID(123)
+
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), &persistence.GetHistoryTasksRequest{
+		ShardID:             s.shardID,
+		TaskCategory:        tasks.CategoryTransfer,
+		InclusiveMinTaskKey: tasks.NewImmediateKey(123),
+		ExclusiveMaxTaskKey: tasks.NewImmediateKey(124),
+		BatchSize:           1,
+	}).Return(&persistence.GetHistoryTasksResponse{Tasks: []tasks.Task{task}}, nil).Times(1)
+
+	result, err := s.mockShard.GetTaskInfo(tasks.CategoryTransfer, 123)
+	s.NoError(err)
+	s.Equal(workflowKey, definition.NewWorkflowKey(result.GetNamespaceID(), result.GetWorkflowID(), result.GetRunID()))
+}
+
+func (s *contextSuite) TestGetTaskInfo_NotFound() {
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{}, nil).Times(1)
+
+	_, err := s.mockShard.GetTaskInfo(tasks.CategoryTransfer, 123)
+	s.ErrorAs(err, new(*serviceerror.NotFound))
+
+	_, err = s.mockShard.GetTaskInfo(tasks.CategoryTimer, 123)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestForceCompleteTask_AdvancesAckLevel() {
+	task := s.setupForceCompleteTask(100, tasks.NewImmediateKey(100), tasks.NewImmediateKey(200))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", nil)
+	s.NoError(err)
+	s.False(s.mockShard.IsValid(), "shard should be unloaded after force completing a task")
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	s.Equal(tasks.NewImmediateKey(101), *getMinTaskKey(queueState))
+	scopes := queueState.ReaderStates[common.DefaultQueueReaderID].Scopes
+	s.Len(scopes, 1)
+	s.Equal(tasks.NewImmediateKey(200), ConvertFromPersistenceTaskKey(scopes[0].Range.ExclusiveMax))
+}
+
+func (s *contextSuite) TestForceCompleteTask_SplitsScope() {
+	task := s.setupForceCompleteTask(150, tasks.NewImmediateKey(100), tasks.NewImmediateKey(200))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", nil)
+	s.NoError(err)
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	// tasks before the completed one are still pending
+	s.Equal(tasks.NewImmediateKey(100), *getMinTaskKey(queueState))
+	scopes := queueState.ReaderStates[common.DefaultQueueReaderID].Scopes
+	s.Len(scopes, 2)
+	s.Equal(tasks.NewImmediateKey(150), ConvertFromPersistenceTaskKey(scopes[0].Range.ExclusiveMax))
+	s.Equal(tasks.NewImmediateKey(151), ConvertFromPersistenceTaskKey(scopes[1].Range.InclusiveMin))
+}
+
+func (s *contextSuite) TestForceCompleteTask_LastPendingTask() {
+	task := s.setupForceCompleteTask(100, tasks.NewImmediateKey(100), tasks.NewImmediateKey(101))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", nil)
+	s.NoError(err)
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	s.Empty(queueState.ReaderStates)
+	s.Equal(tasks.NewImmediateKey(200), *getMinTaskKey(queueState))
+}
+
+func (s *contextSuite) TestForceCompleteTask_WriteToDLQ() {
+	task := s.setupForceCompleteTask(100, tasks.NewImmediateKey(100), tasks.NewImmediateKey(200))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	dlqWriter := &fakeTaskDLQWriter{}
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", dlqWriter)
+	s.NoError(err)
+	s.Equal([]tasks.Task{task}, dlqWriter.tasks)
+}
+
+func (s *contextSuite) TestForceCompleteTask_DLQFailure() {
+	task := s.setupForceCompleteTask(100, tasks.NewImmediateKey(100), tasks.NewImmediateKey(200))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Times(0)
+	dlqWriter := &fakeTaskDLQWriter{err: errors.New("dlq unavailable")}
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", dlqWriter)
+	s.Error(err)
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestForceCompleteTask_AboveHighWatermark() {
+	task := s.setupForceCompleteTask(200, tasks.NewImmediateKey(100), tasks.NewImmediateKey(200))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Times(0)
+	dlqWriter := &fakeTaskDLQWriter{}
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", dlqWriter)
+	s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+	s.Empty(dlqWriter.tasks)
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestForceCompleteTask_AlreadyCompleted() {
+	task := s.setupForceCompleteTask(50, tasks.NewImmediateKey(100), tasks.NewImmediateKey(200))
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryTransfer, task.GetTaskID(), "poison task", nil)
+	s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestForceCompleteTask_Replication() {
+	err := s.mockShard.ForceCompleteTask(context.Background(), tasks.CategoryReplication, 100, "poison task", nil)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+// setupForceCompleteTask sets up a pending transfer task with the given ID, and a transfer queue
+// state with a single scope [inclusiveMin, exclusiveMax) and a high watermark of 200.
+func (s *contextSuite) setupForceCompleteTask(
+	taskID int64,
+	inclusiveMin tasks.Key,
+	exclusiveMax tasks.Key,
+) tasks.Task {
+	workflowKey := definition.NewWorkflowKey(
+		tests.NamespaceID.String(),
+		tests.WorkflowID,
+		tests.RunID,
+	)
+	task := tasks.NewFakeTask(workflowKey, tasks.CategoryTransfer, time.Time{})
+	task.SetTaskID(taskID)
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{Tasks: []tasks.Task{task}}, nil).Times(1)
+
+	s.mockShard.shardInfo.QueueStates[int32(tasks.CategoryTransfer.ID())] = &persistencespb.QueueState{
+		ReaderStates: map[int64]*persistencespb.QueueReaderState{
+			common.DefaultQueueReaderID: {
+				Scopes: []*persistencespb.QueueSliceScope{{
+					Range: &persistencespb.QueueSliceRange{
+						InclusiveMin: ConvertToPersistenceTaskKey(inclusiveMin),
+						ExclusiveMax: ConvertToPersistenceTaskKey(exclusiveMax),
+					},
+					Predicate: &persistencespb.Predicate{
+						PredicateType: enumsspb.PREDICATE_TYPE_UNIVERSAL,
+						Attributes:    &persistencespb.Predicate_UniversalPredicateAttributes{},
+					},
+				}},
+			},
+		},
+		ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(200)),
+	}
+	return task
+}
+
+func (s *contextSuite) TestEstimateQueueDrainTime() {
+	// the shard has been loaded for longer than the rate window
+	now := s.timeSource.Now().Add(10 * time.Minute)
+	s.timeSource.Update(now)
+
+	duration, err := s.mockShard.EstimateQueueDrainTime(tasks.CategoryTransfer)
+	s.NoError(err)
+	s.Zero(duration, "no queue state")
+
+	s.mockShard.shardInfo.QueueStates[int32(tasks.CategoryTransfer.ID())] = &persistencespb.QueueState{
+		ReaderStates: map[int64]*persistencespb.QueueReaderState{
+			common.DefaultQueueReaderID: {
+				Scopes: []*persistencespb.QueueSliceScope{{
+					Range: &persistencespb.QueueSliceRange{
+						InclusiveMin: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(10)),
+						ExclusiveMax: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(20)),
+					},
+					Predicate: &persistencespb.Predicate{
+						PredicateType: enumsspb.PREDICATE_TYPE_UNIVERSAL,
+						Attributes:    &persistencespb.Predicate_UniversalPredicateAttributes{},
+					},
+				}},
+			},
+		},
+		ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(20)),
+	}
+	pendingTask := tasks.NewFakeTask(tests.WorkflowKey, tasks.CategoryTransfer, now)
+	expectPendingTasks := func() {
+		gomock.InOrder(
+			s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+					s.Equal(tasks.CategoryTransfer, request.TaskCategory)
+					s.Equal(tasks.NewImmediateKey(10), request.InclusiveMinTaskKey)
+					s.Empty(request.NextPageToken)
+					return &persistence.GetHistoryTasksResponse{
+						Tasks:         []tasks.Task{pendingTask, pendingTask, pendingTask},
+						NextPageToken: []byte{1},
+					}, nil
+				}),
+			s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+				Return(&persistence.GetHistoryTasksResponse{Tasks: []tasks.Task{pendingTask}}, nil),
+		)
+	}
+
+	// tasks are pending, but none were completed
+	expectPendingTasks()
+	_, err = s.mockShard.EstimateQueueDrainTime(tasks.CategoryTransfer)
+	s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+
+	// 120 tasks completed in the 5 minute window is 0.4 tasks per second, so 4 tasks take 10 seconds
+	s.mockShard.queueDrainRate.record(tasks.CategoryTransfer.ID(), 100, now.Add(-time.Minute))
+	s.mockShard.queueDrainRate.record(tasks.CategoryTransfer.ID(), 20, now)
+	s.mockShard.queueDrainRate.record(tasks.CategoryTimer.ID(), 1000, now)
+	expectPendingTasks()
+	duration, err = s.mockShard.EstimateQueueDrainTime(tasks.CategoryTransfer)
+	s.NoError(err)
+	s.Equal(10*time.Second, duration)
+}
+
+func (s *contextSuite) TestOldestPendingTaskTime() {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	s.timeSource.Update(now)
+
+	_, ok := s.mockShard.OldestPendingTaskTime(tasks.CategoryTimer)
+	s.False(ok, "no queue state")
+
+	ackLevel := tasks.NewKey(now.Add(-10*time.Minute), 0)
+	s.mockShard.shardInfo.QueueStates[int32(tasks.CategoryTimer.ID())] = &persistencespb.QueueState{
+		ReaderStates: map[int64]*persistencespb.QueueReaderState{
+			common.DefaultQueueReaderID: {
+				Scopes: []*persistencespb.QueueSliceScope{{
+					Range: &persistencespb.QueueSliceRange{
+						InclusiveMin: ConvertToPersistenceTaskKey(ackLevel),
+						ExclusiveMax: ConvertToPersistenceTaskKey(tasks.NewKey(now.Add(-time.Minute), 0)),
+					},
+					Predicate: &persistencespb.Predicate{
+						PredicateType: enumsspb.PREDICATE_TYPE_UNIVERSAL,
+						Attributes:    &persistencespb.Predicate_UniversalPredicateAttributes{},
+					},
+				}},
+			},
+		},
+		ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(tasks.NewKey(now.Add(-time.Minute), 0)),
+	}
+
+	fireTime := now.Add(-5 * time.Minute)
+	timerTask := tasks.NewFakeTask(tests.WorkflowKey, tasks.CategoryTimer, fireTime)
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+			s.Equal(tasks.CategoryTimer, request.TaskCategory)
+			s.Zero(ackLevel.CompareTo(request.InclusiveMinTaskKey))
+			s.False(request.ExclusiveMaxTaskKey.FireTime.Before(now), "due timers are included")
+			s.Equal(1, request.BatchSize)
+			return &persistence.GetHistoryTasksResponse{Tasks: []tasks.Task{timerTask}}, nil
+		}).Times(1)
+	oldest, ok := s.mockShard.OldestPendingTaskTime(tasks.CategoryTimer)
+	s.True(ok)
+	s.Equal(fireTime, oldest)
+
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{}, nil).Times(1)
+	_, ok = s.mockShard.OldestPendingTaskTime(tasks.CategoryTimer)
+	s.False(ok, "no due timer")
+}
+
+func (s *contextSuite) TestGetNamespaceFailoverVersion() {
+	version, err := s.mockShard.GetNamespaceFailoverVersion(tests.NamespaceID)
+	s.NoError(err)
+	s.Equal(tests.LocalNamespaceEntry.FailoverVersion(), version)
+
+	unknownNamespaceID := namespace.ID("unknown-namespace-id")
+	s.mockNamespaceCache.EXPECT().GetNamespaceByID(unknownNamespaceID).DoAndReturn(
+		func(namespace.ID) (*namespace.Namespace, error) {
+			// the namespace is looked up without holding the shard lock
+			s.True(s.mockShard.rwLock.TryLock())
+			s.mockShard.rwLock.Unlock()
+			return nil, serviceerror.NewNamespaceNotFound(unknownNamespaceID.String())
+		},
+	).Times(1)
+	_, err = s.mockShard.GetNamespaceFailoverVersion(unknownNamespaceID)
+	s.ErrorAs(err, new(*serviceerror.NamespaceNotFound))
+}
+
+func (s *contextSuite) TestGetNamespaceFailoverVersion_Handover() {
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).Times(1)
+
+	namespaceEntry := namespace.NewGlobalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Id: tests.NamespaceID.String(), Name: tests.Namespace.String()},
+		&persistencespb.NamespaceConfig{
+			Retention: timestamp.DurationFromDays(1),
+		},
+		&persistencespb.NamespaceReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters: []string{
+				cluster.TestCurrentClusterName,
+				cluster.TestAlternativeClusterName,
+			},
+			State: enums.REPLICATION_STATE_HANDOVER,
+		},
+		tests.Version,
+	)
+	s.mockShard.UpdateHandoverNamespace(namespaceEntry, false)
+
+	_, err := s.mockShard.GetNamespaceFailoverVersion(tests.NamespaceID)
+	s.ErrorIs(err, consts.ErrNamespaceHandover)
+}
+
+func (s *contextSuite) TestGetWorkflowExecution_ReadRateLimited() {
+	s.mockShard.config.ShardReadRPS = dynamicconfig.GetIntPropertyFnFilteredByNamespace(1)
+	request := &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		RunID:       tests.RunID,
+	}
+	s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), request).
+		Return(&persistence.GetWorkflowExecutionResponse{}, nil).Times(3)
+	ctx := headers.SetCallerInfo(context.Background(), headers.NewCallerInfo(tests.Namespace.String(), headers.CallerTypeAPI, "DescribeWorkflowExecution"))
+
+	// default burst is twice the rate
+	for i := 0; i < 2; i++ {
+		_, err := s.mockShard.GetWorkflowExecution(ctx, request)
+		s.NoError(err)
+	}
+	_, err := s.mockShard.GetWorkflowExecution(ctx, request)
+	s.ErrorIs(err, consts.ErrResourceExhaustedShardReadLimit)
+	s.True(common.IsServiceClientTransientError(err))
+
+	s.timeSource.Update(s.timeSource.Now().Add(time.Second))
+	_, err = s.mockShard.GetWorkflowExecution(ctx, request)
+	s.NoError(err)
+}
+
+func (s *contextSuite) TestGetWorkflowExecution_ReadRateLimitDisabled() {
+	request := &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		RunID:       tests.RunID,
+	}
+	s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), request).
+		Return(&persistence.GetWorkflowExecutionResponse{}, nil).Times(10)
+	ctx := headers.SetCallerInfo(context.Background(), headers.NewCallerInfo(tests.Namespace.String(), headers.CallerTypeAPI, "DescribeWorkflowExecution"))
+
+	for i := 0; i < 10; i++ {
+		_, err := s.mockShard.GetWorkflowExecution(ctx, request)
+		s.NoError(err)
+	}
+}
+
+func (s *contextSuite) TestGetWorkflowExecution_InternalReadNotRateLimited() {
+	s.mockShard.config.ShardReadRPS = dynamicconfig.GetIntPropertyFnFilteredByNamespace(1)
+	request := &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		RunID:       tests.RunID,
+	}
+	s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), request).
+		Return(&persistence.GetWorkflowExecutionResponse{}, nil).Times(10)
+	ctx := headers.SetCallerInfo(context.Background(), headers.SystemBackgroundCallerInfo)
+
+	for i := 0; i < 10; i++ {
+		_, err := s.mockShard.GetWorkflowExecution(ctx, request)
+		s.NoError(err)
+	}
+}
+
+func (s *contextSuite) TestGetWorkflowExecutions() {
+	s.mockShard.config.ShardBatchReadConcurrency = dynamicconfig.GetIntPropertyFn(2)
+	found := definition.NewWorkflowKey(tests.NamespaceID.String(), "workflow-1", tests.RunID)
+	notFound := definition.NewWorkflowKey(tests.NamespaceID.String(), "workflow-2", tests.RunID)
+	failed := definition.NewWorkflowKey(tests.NamespaceID.String(), "workflow-3", tests.RunID)
+	response := &persistence.GetWorkflowExecutionResponse{DBRecordVersion: 5}
+	expectRead := func(key definition.WorkflowKey) *gomock.Call {
+		return s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), &persistence.GetWorkflowExecutionRequest{
+			ShardID:     s.shardID,
+			NamespaceID: key.NamespaceID,
+			WorkflowID:  key.WorkflowID,
+			RunID:       key.RunID,
+		})
+	}
+	expectRead(found).Return(response, nil).Times(1)
+	expectRead(notFound).Return(nil, serviceerror.NewNotFound("workflow not found")).Times(1)
+	expectRead(failed).Return(nil, serviceerror.NewUnavailable("some error")).Times(1)
+
+	responses, errs := s.mockShard.GetWorkflowExecutions(context.Background(), []definition.WorkflowKey{found, notFound, failed})
+	s.Equal(map[definition.WorkflowKey]*persistence.GetWorkflowExecutionResponse{found: response}, responses)
+	s.Len(errs, 2)
+	var notFoundErr *serviceerror.NotFound
+	s.ErrorAs(errs[notFound], &notFoundErr)
+	var unavailableErr *serviceerror.Unavailable
+	s.ErrorAs(errs[failed], &unavailableErr)
+}
+
+func (s *contextSuite) TestGetWorkflowExecutions_ShardNotAcquired() {
+	s.mockShard.state = contextStateStopping
+	key := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+
+	responses, errs := s.mockShard.GetWorkflowExecutions(context.Background(), []definition.WorkflowKey{key})
+	s.Empty(responses)
+	var ownershipLost *persistence.ShardOwnershipLostError
+	s.ErrorAs(errs[key], &ownershipLost)
+}
+
+func (s *contextSuite) TestMaintenanceMode() {
+	s.mockShard.SetMaintenanceMode(true)
+
+	getRequest := &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		RunID:       tests.RunID,
+	}
+	s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), getRequest).
+		Return(&persistence.GetWorkflowExecutionResponse{}, nil).Times(1)
+	_, err := s.mockShard.GetWorkflowExecution(context.Background(), getRequest)
+	s.NoError(err)
+
+	// writes are rejected before reaching persistence
+	_, err = s.mockShard.CreateWorkflowExecution(context.Background(), &persistence.CreateWorkflowExecutionRequest{})
+	s.ErrorIs(err, ErrShardInMaintenanceMode)
+	_, err = s.mockShard.UpdateWorkflowExecution(context.Background(), &persistence.UpdateWorkflowExecutionRequest{})
+	s.ErrorIs(err, ErrShardInMaintenanceMode)
+	_, err = s.mockShard.ConflictResolveWorkflowExecution(context.Background(), &persistence.ConflictResolveWorkflowExecutionRequest{})
+	s.ErrorIs(err, ErrShardInMaintenanceMode)
+	_, err = s.mockShard.SetWorkflowExecution(context.Background(), &persistence.SetWorkflowExecutionRequest{})
+	s.ErrorIs(err, ErrShardInMaintenanceMode)
+	s.True(common.IsServiceTransientError(err))
+
+	s.mockShard.SetMaintenanceMode(false)
+	s.NoError(s.mockShard.errorByMaintenanceMode())
+}
+
+func (s *contextSuite) TestGetCurrentExecution_EventualConsistency() {
+	request := &persistence.GetCurrentExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+	}
+	running := &persistence.GetCurrentExecutionResponse{
+		RunID:  tests.RunID,
+		State:  enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING,
+		Status: enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+	}
+	completed := &persistence.GetCurrentExecutionResponse{
+		RunID:  tests.RunID,
+		State:  enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED,
+		Status: enums.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+	}
+
+	s.mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), request).Return(running, nil).Times(2)
+	for i := 0; i < 3; i++ {
+		resp, err := s.mockShard.GetCurrentExecutionWithConsistency(context.Background(), request, ReadConsistencyEventual)
+		s.NoError(err)
+		s.Equal(running, resp)
+	}
+	// strong reads always go to persistence
+	resp, err := s.mockShard.GetCurrentExecution(context.Background(), request)
+	s.NoError(err)
+	s.Equal(running, resp)
+
+	// updates bypassing the current execution don't invalidate it
+	updateRequest := &persistence.UpdateWorkflowExecutionRequest{
+		Mode: persistence.UpdateWorkflowModeBypassCurrent,
+		UpdateWorkflowMutation: persistence.WorkflowMutation{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				NamespaceId: tests.NamespaceID.String(),
+				WorkflowId:  tests.WorkflowID,
+			},
+		},
+	}
+	s.mockExecutionManager.EXPECT().UpdateWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&persistence.UpdateWorkflowExecutionResponse{}, nil).Times(2)
+	_, err = s.mockShard.UpdateWorkflowExecution(context.Background(), updateRequest)
+	s.NoError(err)
+	resp, err = s.mockShard.GetCurrentExecutionWithConsistency(context.Background(), request, ReadConsistencyEventual)
+	s.NoError(err)
+	s.Equal(running, resp)
+
+	// updating the current execution invalidates it
+	updateRequest.Mode = persistence.UpdateWorkflowModeUpdateCurrent
+	_, err = s.mockShard.UpdateWorkflowExecution(context.Background(), updateRequest)
+	s.NoError(err)
+	s.mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), request).Return(completed, nil).Times(2)
+	resp, err = s.mockShard.GetCurrentExecutionWithConsistency(context.Background(), request, ReadConsistencyEventual)
+	s.NoError(err)
+	s.Equal(completed, resp)
+
+	// and so does expiry
+	s.timeSource.Update(s.timeSource.Now().Add(s.mockShard.config.ShardCurrentExecutionCacheTTL() + time.Second))
+	resp, err = s.mockShard.GetCurrentExecutionWithConsistency(context.Background(), request, ReadConsistencyEventual)
+	s.NoError(err)
+	s.Equal(completed, resp)
+}
+
+func (s *contextSuite) TestGetCurrentExecution_EventualConsistency_InvalidatedDuringRead() {
+	request := &persistence.GetCurrentExecutionRequest{
+		ShardID:     s.shardID,
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+	}
+	running := &persistence.GetCurrentExecutionResponse{
+		RunID:  tests.RunID,
+		State:  enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING,
+		Status: enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+	}
+	completed := &persistence.GetCurrentExecutionResponse{
+		RunID:  tests.RunID,
+		State:  enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED,
+		Status: enums.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+	}
+	s.mockExecutionManager.EXPECT().UpdateWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&persistence.UpdateWorkflowExecutionResponse{}, nil).Times(1)
+
+	// the workflow completes while the read is in flight, so the read returns the state before the
+	// write and must not be cached
+	s.mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), request).DoAndReturn(
+		func(ctx context.Context, request *persistence.GetCurrentExecutionRequest) (*persistence.GetCurrentExecutionResponse, error) {
+			_, err := s.mockShard.UpdateWorkflowExecution(ctx, &persistence.UpdateWorkflowExecutionRequest{
+				UpdateWorkflowMutation: persistence.WorkflowMutation{
+					ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+						NamespaceId: tests.NamespaceID.String(),
+						WorkflowId:  tests.WorkflowID,
+					},
+				},
+			})
+			s.NoError(err)
+			return running, nil
+		},
+	).Times(1)
+	resp, err := s.mockShard.GetCurrentExecutionWithConsistency(context.Background(), request, ReadConsistencyEventual)
+	s.NoError(err)
+	s.Equal(running, resp)
+
+	s.mockExecutionManager.EXPECT().GetCurrentExecution(gomock.Any(), request).Return(completed, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		resp, err = s.mockShard.GetCurrentExecutionWithConsistency(context.Background(), request, ReadConsistencyEventual)
+		s.NoError(err)
+		s.Equal(completed, resp)
+	}
+	s.Empty(s.mockShard.currentExecutionCache.inflight)
+}
+
+func (s *contextSuite) TestGetShardInfoSize() {
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	blob, err := s.mockShard.GetPayloadSerializer().ShardInfoToBlob(s.mockShard.shardInfo, enums.ENCODING_TYPE_PROTO3)
+	s.NoError(err)
+	initialSize := s.mockShard.GetShardInfoSize()
+	s.Equal(len(blob.Data), initialSize)
+
+	newQueueState := func() *persistencespb.QueueState {
+		scopes := make([]*persistencespb.QueueSliceScope, 0, 1000)
+		for i := int64(0); i < 1000; i++ {
+			scopes = append(scopes, &persistencespb.QueueSliceScope{
+				Range: &persistencespb.QueueSliceRange{
+					InclusiveMin: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(i * 10)),
+					ExclusiveMax: ConvertToPersistenceTaskKey(tasks.NewImmediateKey((i + 1) * 10)),
+				},
+				Predicate: &persistencespb.Predicate{
+					PredicateType: enumsspb.PREDICATE_TYPE_UNIVERSAL,
+					Attributes:    &persistencespb.Predicate_UniversalPredicateAttributes{},
+				},
+			})
+		}
+		return &persistencespb.QueueState{
+			ReaderStates: map[int64]*persistencespb.QueueReaderState{
+				0: {Scopes: scopes},
+			},
+			ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(10000)),
+		}
+	}
+
+	s.mockShard.config.QueueStateCompactionThreshold = dynamicconfig.GetIntPropertyFn(0)
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, newQueueState()))
+	uncompactedSize := s.mockShard.GetShardInfoSize()
+	s.Greater(uncompactedSize, initialSize+1000*10)
+
+	// compacting the adjacent scopes shrinks the shard info back down
+	s.mockShard.config.QueueStateCompactionThreshold = dynamicconfig.GetIntPropertyFn(100)
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, newQueueState()))
+	s.Less(s.mockShard.GetShardInfoSize(), initialSize+100)
+}
+
+func (s *contextSuite) TestExportImportState() {
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	queueState := &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: &persistencespb.TaskKey{TaskId: 100, FireTime: timestamp.TimePtr(tasks.DefaultFireTime)},
+	}
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, queueState))
+	ackTimestamp := s.timeSource.Now()
+	s.mockShard.UpdateRemoteClusterInfo(cluster.TestAlternativeClusterName, 10, ackTimestamp)
+	s.mockShard.UpdateAckedReplicationWatermark(cluster.TestAlternativeClusterName, 1, 30, ackTimestamp)
+	taskID, err := s.mockShard.GenerateTaskID()
+	s.NoError(err)
+
+	state := s.mockShard.ExportState()
+
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: &persistencespb.TaskKey{TaskId: 200, FireTime: timestamp.TimePtr(tasks.DefaultFireTime)},
+	}))
+	s.mockShard.UpdateRemoteClusterInfo(cluster.TestAlternativeClusterName, 20, ackTimestamp.Add(time.Minute))
+	_, err = s.mockShard.GenerateTaskID()
+	s.NoError(err)
+
+	// a snapshot can be imported more than once
+	for i := 0; i < 2; i++ {
+		s.NoError(s.mockShard.ImportState(state))
+
+		importedQueueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+		s.True(ok)
+		s.Equal(int64(100), importedQueueState.ExclusiveReaderHighWatermark.TaskId)
+		remoteAckStatus, _, err := s.mockShard.GetReplicationStatus([]string{cluster.TestAlternativeClusterName})
+		s.NoError(err)
+		s.Equal(int64(10), remoteAckStatus[cluster.TestAlternativeClusterName].AckedTaskId)
+		appliedTaskID, _ := s.mockShard.GetAckedReplicationWatermark(cluster.TestAlternativeClusterName)
+		s.Equal(int64(30), appliedTaskID)
+		nextTaskID, err := s.mockShard.GenerateTaskID()
+		s.NoError(err)
+		s.Equal(taskID+1, nextTaskID)
+	}
+
+	// the imported state can still be updated
+	s.mockShard.UpdateAckedReplicationWatermark(cluster.TestAlternativeClusterName, 2, 40, ackTimestamp)
+	appliedTaskID, _ := s.mockShard.GetAckedReplicationWatermark(cluster.TestAlternativeClusterName)
+	s.Equal(int64(30), appliedTaskID)
+}
+
+func (s *contextSuite) TestImportState_ShardMismatch() {
+	state := s.mockShard.ExportState()
+	state.shardInfo.ShardId = s.shardID + 1
+
+	s.Error(s.mockShard.ImportState(state))
+}
+
+func (s *contextSuite) TestExportRestoreQueueState() {
+	s.mockShard.config.ShardQueueStateRestore = dynamicconfig.GetBoolPropertyFn(true)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: &persistencespb.TaskKey{TaskId: 100, FireTime: timestamp.TimePtr(tasks.DefaultFireTime)},
+	}))
+	s.mockShard.shardInfo.ReplicationDlqAckLevel = map[string]int64{cluster.TestAlternativeClusterName: 10}
+
+	snapshot, err := s.mockShard.ExportQueueState()
+	s.NoError(err)
+	blob, err := snapshot.Marshal()
+	s.NoError(err)
+
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: &persistencespb.TaskKey{TaskId: 200, FireTime: timestamp.TimePtr(tasks.DefaultFireTime)},
+	}))
+	// the exported snapshot doesn't change with the shard
+	s.Equal(int64(100), snapshot.QueueStates[int32(tasks.CategoryTransfer.ID())].ExclusiveReaderHighWatermark.TaskId)
+
+	restored := &persistencespb.ShardInfo{}
+	s.NoError(restored.Unmarshal(blob))
+	s.NoError(s.mockShard.RestoreQueueState(restored))
+	s.False(s.mockShard.IsValid(), "shard should be unloaded after restoring queue state")
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	s.Equal(int64(100), queueState.ExclusiveReaderHighWatermark.TaskId)
+	s.Equal(int64(10), s.mockShard.GetReplicatorDLQAckLevel(cluster.TestAlternativeClusterName))
+}
+
+func (s *contextSuite) TestRestoreQueueState_RangeIDAhead() {
+	s.mockShard.config.ShardQueueStateRestore = dynamicconfig.GetBoolPropertyFn(true)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Times(0)
+
+	snapshot, err := s.mockShard.ExportQueueState()
+	s.NoError(err)
+	snapshot.RangeId++
+
+	err = s.mockShard.RestoreQueueState(snapshot)
+	s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestRestoreQueueState_ShardMismatch() {
+	s.mockShard.config.ShardQueueStateRestore = dynamicconfig.GetBoolPropertyFn(true)
+
+	snapshot, err := s.mockShard.ExportQueueState()
+	s.NoError(err)
+	snapshot.ShardId = s.shardID + 1
+
+	err = s.mockShard.RestoreQueueState(snapshot)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestRestoreQueueState_Disabled() {
+	snapshot, err := s.mockShard.ExportQueueState()
+	s.NoError(err)
+
+	err = s.mockShard.RestoreQueueState(snapshot)
+	s.ErrorAs(err, new(*serviceerror.PermissionDenied))
+}
+
+func (s *contextSuite) TestCheckpointForRepair_Rollback() {
+	var persisted *persistencespb.ShardInfo
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateShardRequest) error {
+			persisted = request.ShardInfo
+			return nil
+		},
+	).AnyTimes()
+
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: &persistencespb.TaskKey{TaskId: 100, FireTime: timestamp.TimePtr(tasks.DefaultFireTime)},
+	}))
+	s.mockShard.shardInfo.ReplicationDlqAckLevel = map[string]int64{cluster.TestAlternativeClusterName: 10}
+
+	checkpointID, err := s.mockShard.CheckpointForRepair()
+	s.NoError(err)
+	s.Len(persisted.RepairCheckpoints, 1)
+
+	// the repair
+	s.NoError(s.mockShard.SetQueueState(tasks.CategoryTransfer, 0, &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: &persistencespb.TaskKey{TaskId: 200, FireTime: timestamp.TimePtr(tasks.DefaultFireTime)},
+	}))
+	s.NoError(s.mockShard.UpdateReplicatorDLQAckLevel(cluster.TestAlternativeClusterName, 20))
+
+	// the checkpoint survives a shard reload
+	blob, err := persisted.Marshal()
+	s.NoError(err)
+	reloaded := &persistencespb.ShardInfo{}
+	s.NoError(reloaded.Unmarshal(blob))
+	s.mockShard.shardInfo = reloaded
+
+	s.NoError(s.mockShard.RollbackToCheckpoint(checkpointID))
+	s.False(s.mockShard.IsValid(), "shard should be unloaded after rolling back to a checkpoint")
+
+	queueState, ok := s.mockShard.GetQueueState(tasks.CategoryTransfer)
+	s.True(ok)
+	s.Equal(int64(100), queueState.ExclusiveReaderHighWatermark.TaskId)
+	s.Equal(int64(10), s.mockShard.GetReplicatorDLQAckLevel(cluster.TestAlternativeClusterName))
+	s.Equal(int64(100), persisted.QueueStates[int32(tasks.CategoryTransfer.ID())].ExclusiveReaderHighWatermark.TaskId)
+	s.Len(persisted.RepairCheckpoints, 1)
+}
+
+func (s *contextSuite) TestCheckpointForRepair_Expired() {
+	s.mockShard.config.ShardRepairCheckpointTTL = dynamicconfig.GetDurationPropertyFn(time.Hour)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	checkpointID, err := s.mockShard.CheckpointForRepair()
+	s.NoError(err)
+
+	s.timeSource.Update(s.timeSource.Now().Add(time.Hour))
+	err = s.mockShard.RollbackToCheckpoint(checkpointID)
+	s.ErrorAs(err, new(*serviceerror.NotFound))
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestCheckpointForRepair_MaxCount() {
+	s.mockShard.config.ShardRepairCheckpointTTL = dynamicconfig.GetDurationPropertyFn(time.Hour)
+	s.mockShard.config.ShardRepairCheckpointMaxCount = dynamicconfig.GetIntPropertyFn(2)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	first, err := s.mockShard.CheckpointForRepair()
+	s.NoError(err)
+	// same time, the IDs are still unique
+	second, err := s.mockShard.CheckpointForRepair()
+	s.NoError(err)
+	s.NotEqual(first, second)
+	s.timeSource.Update(s.timeSource.Now().Add(time.Minute))
+	third, err := s.mockShard.CheckpointForRepair()
+	s.NoError(err)
+
+	s.Len(s.mockShard.shardInfo.RepairCheckpoints, 2)
+	s.ErrorAs(s.mockShard.RollbackToCheckpoint(first), new(*serviceerror.NotFound))
+	s.NoError(s.mockShard.RollbackToCheckpoint(third))
+}
+
+func (s *contextSuite) TestRollbackToCheckpoint_RangeIDAhead() {
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	checkpointID, err := s.mockShard.CheckpointForRepair()
+	s.NoError(err)
+	s.mockShard.shardInfo.RangeId--
+
+	err = s.mockShard.RollbackToCheckpoint(checkpointID)
+	s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+	s.True(s.mockShard.IsValid())
+}
+
+func (s *contextSuite) TestGetReplicationDLQDepth() {
+	s.mockShard.shardInfo.ReplicationDlqAckLevel = map[string]int64{cluster.TestAlternativeClusterName: 100}
+	workflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	dlqTasks := func(taskIDs ...int64) []tasks.Task {
+		var result []tasks.Task
+		for _, taskID := range taskIDs {
+			result = append(result, &tasks.HistoryReplicationTask{WorkflowKey: workflowKey, TaskID: taskID})
+		}
+		return result
+	}
+
+	// task IDs are sparse, the depth is the number of tasks above the ack level
+	gomock.InOrder(
+		s.mockExecutionManager.EXPECT().GetReplicationTasksFromDLQ(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.GetReplicationTasksFromDLQRequest) (*persistence.GetHistoryTasksResponse, error) {
+				s.Equal(cluster.TestAlternativeClusterName, request.SourceClusterName)
+				s.Equal(tasks.CategoryReplication, request.TaskCategory)
+				s.Equal(int64(101), request.InclusiveMinTaskKey.TaskID)
+				s.Nil(request.NextPageToken)
+				return &persistence.GetHistoryTasksResponse{Tasks: dlqTasks(105, 230, 1000), NextPageToken: []byte("next")}, nil
+			},
+		),
+		s.mockExecutionManager.EXPECT().GetReplicationTasksFromDLQ(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.GetReplicationTasksFromDLQRequest) (*persistence.GetHistoryTasksResponse, error) {
+				s.Equal([]byte("next"), request.NextPageToken)
+				return &persistence.GetHistoryTasksResponse{Tasks: dlqTasks(5000, 5001)}, nil
+			},
+		),
+	)
+
+	depth, err := s.mockShard.GetReplicationDLQDepth(cluster.TestAlternativeClusterName)
+	s.NoError(err)
+	s.Equal(int64(5), depth)
+}
+
+func (s *contextSuite) TestGetReplicationDLQDepth_Empty() {
+	s.mockExecutionManager.EXPECT().GetReplicationTasksFromDLQ(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{}, nil)
+
+	depth, err := s.mockShard.GetReplicationDLQDepth(cluster.TestAlternativeClusterName)
+	s.NoError(err)
+	s.Zero(depth)
+}
+
+func (s *contextSuite) TestAckedReplicationWatermark() {
+	source := cluster.TestAlternativeClusterName
+	ackTaskID, ackTime := s.mockShard.GetAckedReplicationWatermark(source)
+	s.Equal(persistence.EmptyQueueMessageID, ackTaskID)
+	s.True(ackTime.IsZero())
+
+	now := time.Now()
+	s.mockShard.UpdateAckedReplicationWatermark(source, 1, 100, now)
+	ackTaskID, ackTime = s.mockShard.GetAckedReplicationWatermark(source)
+	s.Equal(int64(100), ackTaskID)
+	s.Equal(now, ackTime)
+
+	// the watermark doesn't move backwards
+	s.mockShard.UpdateAckedReplicationWatermark(source, 1, 50, now.Add(-time.Minute))
+	ackTaskID, ackTime = s.mockShard.GetAckedReplicationWatermark(source)
+	s.Equal(int64(100), ackTaskID)
+	s.Equal(now, ackTime)
+
+	// with more than one source shard, it's the lowest of them
+	s.mockShard.UpdateAckedReplicationWatermark(source, 2, 80, now.Add(-time.Second))
+	ackTaskID, ackTime = s.mockShard.GetAckedReplicationWatermark(source)
+	s.Equal(int64(80), ackTaskID)
+	s.Equal(now.Add(-time.Second), ackTime)
+
+	ackTaskID, _ = s.mockShard.GetAckedReplicationWatermark(cluster.TestCurrentClusterName)
+	s.Equal(persistence.EmptyQueueMessageID, ackTaskID)
+
+	// the watermark is reported in the replication status of the source cluster
+	status, _, err := s.mockShard.GetReplicationStatus([]string{source})
+	s.NoError(err)
+	s.Equal(int64(80), status[source].AppliedTaskId)
+	s.Equal(timestamppb.New(now.Add(-time.Second)), status[source].AppliedTaskVisibilityTime)
+}
+
+func (s *contextSuite) TestAckedReplicationWatermark_RemoteClusterInfoWithoutWatermark() {
+	source := cluster.TestAlternativeClusterName
+	s.mockShard.remoteClusterInfos[source] = &remoteClusterInfo{
+		AckedReplicationTaskIDs:    map[int32]int64{},
+		AckedReplicationTimestamps: map[int32]time.Time{},
+	}
+
+	now := time.Now()
+	s.mockShard.UpdateAckedReplicationWatermark(source, 1, 100, now)
+	ackTaskID, ackTime := s.mockShard.GetAckedReplicationWatermark(source)
+	s.Equal(int64(100), ackTaskID)
+	s.Equal(now, ackTime)
+}
+
+func (s *contextSuite) TestPauseReplicationFrom() {
+	var persisted [][]string
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateShardRequest) error {
+			persisted = append(persisted, request.ShardInfo.PausedReplicationSources)
+			return nil
+		},
+	).Times(3)
+
+	s.NoError(s.mockShard.PauseReplicationFrom(cluster.TestAlternativeClusterName))
+	s.NoError(s.mockShard.PauseReplicationFrom(cluster.TestCurrentClusterName))
+	s.True(s.mockShard.IsReplicationPausedFrom(cluster.TestAlternativeClusterName))
+	s.Equal([]string{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName}, s.mockShard.GetPausedReplicationSources())
+
+	s.NoError(s.mockShard.ResumeReplicationFrom(cluster.TestAlternativeClusterName))
+	s.False(s.mockShard.IsReplicationPausedFrom(cluster.TestAlternativeClusterName))
+	s.True(s.mockShard.IsReplicationPausedFrom(cluster.TestCurrentClusterName))
+	s.Equal([][]string{
+		{cluster.TestAlternativeClusterName},
+		{cluster.TestCurrentClusterName, cluster.TestAlternativeClusterName},
+		{cluster.TestCurrentClusterName},
+	}, persisted)
+
+	err := s.mockShard.PauseReplicationFrom("unknown-cluster")
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestQuarantineWorkflow() {
+	var persisted [][]string
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateShardRequest) error {
+			var runIDs []string
+			for _, entry := range request.ShardInfo.QuarantinedWorkflows {
+				runIDs = append(runIDs, entry.RunId)
+			}
+			persisted = append(persisted, runIDs)
+			return nil
+		},
+	).Times(4)
+
+	workflowKey1 := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, "run-2")
+	workflowKey2 := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, "run-1")
+
+	s.NoError(s.mockShard.QuarantineWorkflow(workflowKey1, "stuck"))
+	s.NoError(s.mockShard.QuarantineWorkflow(workflowKey2, "stuck"))
+	s.NoError(s.mockShard.QuarantineWorkflow(workflowKey1, "still stuck"))
+	s.True(s.mockShard.IsWorkflowQuarantined(workflowKey1))
+	s.True(s.mockShard.IsWorkflowQuarantined(workflowKey2))
+
+	quarantined := s.mockShard.ListQuarantinedWorkflows()
+	s.Len(quarantined, 2)
+	s.Equal("run-1", quarantined[0].RunId)
+	s.Equal("run-2", quarantined[1].RunId)
+	s.Equal("still stuck", quarantined[1].Reason)
+	s.NotNil(quarantined[1].QuarantineTime)
+
+	s.NoError(s.mockShard.UnquarantineWorkflow(workflowKey1))
+	s.False(s.mockShard.IsWorkflowQuarantined(workflowKey1))
+	s.True(s.mockShard.IsWorkflowQuarantined(workflowKey2))
+	s.Equal([][]string{
+		{"run-2"},
+		{"run-1", "run-2"},
+		{"run-1", "run-2"},
+		{"run-1"},
+	}, persisted)
+
+	err := s.mockShard.QuarantineWorkflow(definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, ""), "stuck")
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestIsWorkflowQuarantined_WithoutShardLock() {
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	workflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	otherWorkflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, "other-run")
+	emptyState := s.mockShard.ExportState()
+
+	// the lookup must not wait for the shard lock
+	s.mockShard.wLock()
+	s.False(s.mockShard.IsWorkflowQuarantined(workflowKey))
+	s.mockShard.wUnlock()
+
+	s.NoError(s.mockShard.QuarantineWorkflow(workflowKey, "stuck"))
+	quarantinedState := s.mockShard.ExportState()
+	s.mockShard.wLock()
+	s.True(s.mockShard.IsWorkflowQuarantined(workflowKey))
+	s.False(s.mockShard.IsWorkflowQuarantined(otherWorkflowKey))
+	s.mockShard.wUnlock()
+
+	s.NoError(s.mockShard.UnquarantineWorkflow(workflowKey))
+	s.False(s.mockShard.IsWorkflowQuarantined(workflowKey))
+
+	// replacing the shard info republishes its quarantined workflows
+	s.NoError(s.mockShard.ImportState(quarantinedState))
+	s.True(s.mockShard.IsWorkflowQuarantined(workflowKey))
+	s.NoError(s.mockShard.ImportState(emptyState))
+	s.False(s.mockShard.IsWorkflowQuarantined(workflowKey))
+}
+
+func (s *contextSuite) TestScanForDuplicateTaskIDs() {
+	workflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	timerTasks := func(taskIDs ...int64) []tasks.Task {
+		var result []tasks.Task
+		for i, taskID := range taskIDs {
+			task := tasks.NewFakeTask(workflowKey, tasks.CategoryTimer, time.Unix(int64(i), 0))
+			task.SetTaskID(taskID)
+			result = append(result, task)
+		}
+		return result
+	}
+
+	// 7 is duplicated within a page, 3 across pages
+	gomock.InOrder(
+		s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+				s.Equal(tasks.CategoryTimer, request.TaskCategory)
+				s.Equal(10, request.BatchSize)
+				return &persistence.GetHistoryTasksResponse{Tasks: timerTasks(3, 7, 5, 7), NextPageToken: []byte("next")}, nil
+			},
+		),
+		s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+				s.Equal([]byte("next"), request.NextPageToken)
+				s.Equal(6, request.BatchSize)
+				return &persistence.GetHistoryTasksResponse{Tasks: timerTasks(3, 9)}, nil
+			},
+		),
+	)
+
+	duplicateIDs, err := s.mockShard.ScanForDuplicateTaskIDs(context.Background(), tasks.CategoryTimer, 10)
+	s.NoError(err)
+	s.Equal([]int64{3, 7}, duplicateIDs)
+}
+
+func (s *contextSuite) TestScanForDuplicateTaskIDs_Limit() {
+	workflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	var pendingTasks []tasks.Task
+	for _, taskID := range []int64{1, 2, 1} {
+		task := tasks.NewFakeTask(workflowKey, tasks.CategoryTransfer, time.Time{})
+		task.SetTaskID(taskID)
+		pendingTasks = append(pendingTasks, task)
+	}
+	// the duplicate is past the limit, so it isn't found and no more pages are read
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{Tasks: pendingTasks[:2], NextPageToken: []byte("next")}, nil).Times(1)
+
+	duplicateIDs, err := s.mockShard.ScanForDuplicateTaskIDs(context.Background(), tasks.CategoryTransfer, 2)
+	s.NoError(err)
+	s.Empty(duplicateIDs)
+
+	_, err = s.mockShard.ScanForDuplicateTaskIDs(context.Background(), tasks.CategoryTransfer, 0)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestUpcomingTimers() {
+	now := time.Now().UTC()
+	minTaskKey := tasks.NewKey(now.Add(-time.Minute), 0)
+	s.mockShard.shardInfo.QueueStates[int32(tasks.CategoryTimer.ID())] = &persistencespb.QueueState{
+		ExclusiveReaderHighWatermark: ConvertToPersistenceTaskKey(minTaskKey),
+	}
+	workflowKey1 := definition.NewWorkflowKey(tests.NamespaceID.String(), "workflow-1", tests.RunID)
+	workflowKey2 := definition.NewWorkflowKey(tests.NamespaceID.String(), "workflow-2", tests.RunID)
+	workflowKey3 := definition.NewWorkflowKey(tests.NamespaceID.String(), "workflow-3", tests.RunID)
+	// timer tasks of several workflows, in the order the timer queue stores them
+	timerTasks := []tasks.Task{
+		&tasks.UserTimerTask{WorkflowKey: workflowKey2, VisibilityTimestamp: now.Add(-time.Second), TaskID: 5},
+		&tasks.ActivityTimeoutTask{WorkflowKey: workflowKey1, VisibilityTimestamp: now.Add(time.Second), TaskID: 3},
+		&tasks.UserTimerTask{WorkflowKey: workflowKey3, VisibilityTimestamp: now.Add(time.Second), TaskID: 4},
+		&tasks.UserTimerTask{WorkflowKey: workflowKey1, VisibilityTimestamp: now.Add(time.Minute), TaskID: 1},
+	}
+	gomock.InOrder(
+		s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+				s.Equal(tasks.CategoryTimer, request.TaskCategory)
+				s.Equal(minTaskKey, request.InclusiveMinTaskKey)
+				s.Equal(3, request.BatchSize)
+				return &persistence.GetHistoryTasksResponse{Tasks: timerTasks[:2], NextPageToken: []byte("next")}, nil
+			},
+		),
+		s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+				s.Equal([]byte("next"), request.NextPageToken)
+				s.Equal(1, request.BatchSize)
+				// more tasks than requested are ignored
+				return &persistence.GetHistoryTasksResponse{Tasks: timerTasks[2:], NextPageToken: []byte("next")}, nil
+			},
+		),
+	)
+
+	timers, err := s.mockShard.UpcomingTimers(context.Background(), 3)
+	s.NoError(err)
+	s.Equal([]TimerInfo{
+		{WorkflowKey: workflowKey2, TaskType: enumsspb.TASK_TYPE_USER_TIMER, TaskID: 5, FireTime: now.Add(-time.Second)},
+		{WorkflowKey: workflowKey1, TaskType: enumsspb.TASK_TYPE_ACTIVITY_TIMEOUT, TaskID: 3, FireTime: now.Add(time.Second)},
+		{WorkflowKey: workflowKey3, TaskType: enumsspb.TASK_TYPE_USER_TIMER, TaskID: 4, FireTime: now.Add(time.Second)},
+	}, timers)
+	s.True(slices.IsSortedFunc(timers, func(a, b TimerInfo) int {
+		return tasks.NewKey(a.FireTime, a.TaskID).CompareTo(tasks.NewKey(b.FireTime, b.TaskID))
+	}))
+
+	_, err = s.mockShard.UpcomingTimers(context.Background(), 0)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestEstimateLoad() {
+	s.mockShard.config.ShardLoadEstimateTTL = dynamicconfig.GetDurationPropertyFn(time.Minute)
+	now := time.Now()
+	s.timeSource.Update(now)
+	executions := func(historySizes ...int64) []*persistencespb.WorkflowMutableState {
+		var states []*persistencespb.WorkflowMutableState
+		for _, historySize := range historySizes {
+			states = append(states, &persistencespb.WorkflowMutableState{
+				ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+					ExecutionStats: &persistencespb.ExecutionStats{HistorySize: historySize},
+				},
+			})
+		}
+		return states
+	}
+	page1, page2 := executions(100, 200), executions(300)
+	gomock.InOrder(
+		s.mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).
+			Return(&persistence.ListConcreteExecutionsResponse{States: page1, PageToken: []byte("next")}, nil),
+		s.mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.ListConcreteExecutionsRequest) (*persistence.ListConcreteExecutionsResponse, error) {
+				s.Equal([]byte("next"), request.PageToken)
+				return &persistence.ListConcreteExecutionsResponse{States: page2}, nil
+			},
+		),
+	)
+
+	estimate, err := s.mockShard.EstimateLoad(context.Background())
+	s.NoError(err)
+	s.Equal(ShardLoadEstimate{
+		ExecutionCount:        3,
+		HistorySizeBytes:      600,
+		MutableStateSizeBytes: int64(proto.Size(page1[0]) + proto.Size(page1[1]) + proto.Size(page2[0])),
+		Complete:              true,
+		EstimatedAt:           now,
+	}, estimate)
+
+	// the estimate is reused until it expires
+	s.timeSource.Update(now.Add(30 * time.Second))
+	cached, err := s.mockShard.EstimateLoad(context.Background())
+	s.NoError(err)
+	s.Equal(estimate, cached)
+
+	s.timeSource.Update(now.Add(time.Minute))
+	s.mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).
+		Return(&persistence.ListConcreteExecutionsResponse{}, nil)
+	estimate, err = s.mockShard.EstimateLoad(context.Background())
+	s.NoError(err)
+	s.Zero(estimate.ExecutionCount)
+	s.True(estimate.Complete)
+}
+
+func (s *contextSuite) TestEstimateLoad_MaxExecutions() {
+	s.mockShard.config.ShardLoadEstimateMaxExecutions = dynamicconfig.GetIntPropertyFn(2)
+	states := []*persistencespb.WorkflowMutableState{{}, {}, {}}
+	s.mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.ListConcreteExecutionsRequest) (*persistence.ListConcreteExecutionsResponse, error) {
+			s.Equal(2, request.PageSize)
+			return &persistence.ListConcreteExecutionsResponse{States: states, PageToken: []byte("next")}, nil
+		},
+	).Times(1)
+
+	estimate, err := s.mockShard.EstimateLoad(context.Background())
+	s.NoError(err)
+	s.Equal(int64(2), estimate.ExecutionCount)
+	s.False(estimate.Complete)
+}
+
+func (s *contextSuite) TestListExecutionsWithPendingSignals() {
+	execution := func(workflowID string, bufferedEventTypes ...enums.EventType) *persistencespb.WorkflowMutableState {
+		state := &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				NamespaceId: tests.NamespaceID.String(),
+				WorkflowId:  workflowID,
+			},
+			ExecutionState: &persistencespb.WorkflowExecutionState{RunId: tests.RunID},
+		}
+		for _, eventType := range bufferedEventTypes {
+			state.BufferedEvents = append(state.BufferedEvents, &historypb.HistoryEvent{EventType: eventType})
+		}
+		return state
+	}
+	gomock.InOrder(
+		s.mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).Return(
+			&persistence.ListConcreteExecutionsResponse{
+				States: []*persistencespb.WorkflowMutableState{
+					execution("no-buffered-events"),
+					execution("signaled", enums.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED),
+					execution("activity-completed", enums.EVENT_TYPE_ACTIVITY_TASK_COMPLETED),
+				},
+				PageToken: []byte("next"),
+			}, nil),
+		s.mockExecutionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.ListConcreteExecutionsRequest) (*persistence.ListConcreteExecutionsResponse, error) {
+				s.Equal([]byte("next"), request.PageToken)
+				return &persistence.ListConcreteExecutionsResponse{
+					States: []*persistencespb.WorkflowMutableState{
+						execution("signaled-twice", enums.EVENT_TYPE_ACTIVITY_TASK_COMPLETED, enums.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED),
+						execution("signaled-again", enums.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED),
+					},
+				}, nil
+			},
+		),
+	)
+
+	workflowKeys, err := s.mockShard.ListExecutionsWithPendingSignals(context.Background(), 2)
+	s.NoError(err)
+	s.Equal([]definition.WorkflowKey{
+		definition.NewWorkflowKey(tests.NamespaceID.String(), "signaled", tests.RunID),
+		definition.NewWorkflowKey(tests.NamespaceID.String(), "signaled-twice", tests.RunID),
+	}, workflowKeys)
+
+	_, err = s.mockShard.ListExecutionsWithPendingSignals(context.Background(), 0)
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestValidateVersionHistory() {
+	branchUtil := &persistence.HistoryBranchUtilImpl{}
+	s.mockExecutionManager.EXPECT().GetHistoryBranchUtil().Return(branchUtil).AnyTimes()
+	newBranchToken := func(treeID string, branchID string, ancestors []*persistencespb.HistoryBranchRange) []byte {
+		token, err := branchUtil.NewHistoryBranch(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID, treeID, &branchID, ancestors, 0, 0, 0)
+		s.NoError(err)
+		return token
+	}
+	workflowKey := definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID)
+	expectExecution := func(versionHistories *historyspb.VersionHistories, nextEventID int64) {
+		s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), &persistence.GetWorkflowExecutionRequest{
+			ShardID:     s.shardID,
+			NamespaceID: workflowKey.NamespaceID,
+			WorkflowID:  workflowKey.WorkflowID,
+			RunID:       workflowKey.RunID,
+		}).Return(&persistence.GetWorkflowExecutionResponse{
+			State: &persistencespb.WorkflowMutableState{
+				ExecutionInfo: &persistencespb.WorkflowExecutionInfo{VersionHistories: versionHistories},
+				NextEventId:   nextEventID,
+			},
+		}, nil)
+	}
+
+	// a workflow that was reset at event 3
+	expectExecution(&historyspb.VersionHistories{
+		CurrentVersionHistoryIndex: 1,
+		Histories: []*historyspb.VersionHistory{
+			{
+				BranchToken: newBranchToken("tree", "branch-1", nil),
+				Items:       []*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+			},
+			{
+				BranchToken: newBranchToken("tree", "branch-2", []*persistencespb.HistoryBranchRange{
+					{BranchId: "branch-1", BeginNodeId: 1, EndNodeId: 4},
+				}),
+				Items: []*historyspb.VersionHistoryItem{{EventId: 3, Version: 1}, {EventId: 6, Version: 2}},
+			},
+		},
+	}, 7)
+	defects, err := s.mockShard.ValidateVersionHistory(context.Background(), workflowKey)
+	s.NoError(err)
+	s.Empty(defects)
+
+	// the same workflow, corrupted
+	expectExecution(&historyspb.VersionHistories{
+		CurrentVersionHistoryIndex: 1,
+		Histories: []*historyspb.VersionHistory{
+			{
+				BranchToken: newBranchToken("other-tree", "branch-1", nil),
+				Items:       []*historyspb.VersionHistoryItem{{EventId: 4, Version: 1}},
+			},
+			{
+				BranchToken: newBranchToken("tree", "branch-2", []*persistencespb.HistoryBranchRange{
+					{BranchId: "branch-1", BeginNodeId: 2, EndNodeId: 4},
+				}),
+				Items: []*historyspb.VersionHistoryItem{{EventId: 3, Version: 2}, {EventId: 3, Version: 1}},
+			},
+		},
+	}, 7)
+	defects, err = s.mockShard.ValidateVersionHistory(context.Background(), workflowKey)
+	s.NoError(err)
+	var defectIndexes []int
+	for _, defect := range defects {
+		defectIndexes = append(defectIndexes, defect.VersionHistoryIndex)
+	}
+	s.Equal([]int{1, 1, 1, 1, 1}, defectIndexes)
+	s.Contains(defects[0].Description, "item 1 ends at event 3")
+	s.Contains(defects[1].Description, "item 1 has version 1")
+	s.Contains(defects[2].Description, "history tree tree")
+	s.Contains(defects[3].Description, "ancestor 0 begins at node 2")
+	s.Contains(defects[4].Description, "ends at event 3")
+
+	expectExecution(&historyspb.VersionHistories{CurrentVersionHistoryIndex: 0}, 7)
+	defects, err = s.mockShard.ValidateVersionHistory(context.Background(), workflowKey)
+	s.NoError(err)
+	s.Equal([]VersionHistoryDefect{{VersionHistoryIndex: -1, Description: "execution has no version history"}}, defects)
+
+	_, err = s.mockShard.ValidateVersionHistory(context.Background(), definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, ""))
+	s.ErrorAs(err, new(*serviceerror.InvalidArgument))
+}
+
+func (s *contextSuite) TestDryRunTask() {
+	workflowKey := definition.NewWorkflowKey(
+		tests.NamespaceID.String(),
+		tests.WorkflowID,
+		tests.RunID,
+	)
+	task := tasks.NewFakeTask(workflowKey, tasks.CategoryTransfer, time.Time{})
+	task.SetTaskID(123)
+	s.mockExecutionManager.EXPECT().GetHistoryTasks(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetHistoryTasksResponse{Tasks: []tasks.Task{task}}, nil).Times(1)
+
+	generatedTask := tasks.NewFakeTask(workflowKey, tasks.CategoryTimer, time.Now())
+	updateRequest := &persistence.UpdateWorkflowExecutionRequest{
+		ShardID: s.shardID,
+		UpdateWorkflowMutation: persistence.WorkflowMutation{
+			Tasks: map[tasks.Category][]tasks.Task{tasks.CategoryTimer: {generatedTask}},
+		},
+	}
+	recordRequest := &historyservice.RecordChildExecutionCompletedRequest{NamespaceId: tests.NamespaceID.String()}
+	executionErr := errors.New("task failed")
+	s.mockHistoryEngine.EXPECT().DryRunTask(gomock.Any(), gomock.Any(), task, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, shardContext Context, _ tasks.Task, trace *TaskExecutionTrace) error {
+			// workflow writes are captured
+			_, err := shardContext.UpdateWorkflowExecution(ctx, updateRequest)
+			s.NoError(err)
+			// RPCs are captured
+			_, err = shardContext.GetHistoryClient().RecordChildExecutionCompleted(ctx, recordRequest)
+			s.NoError(err)
+			// any other write is rejected
+			_, err = shardContext.GetExecutionManager().UpdateWorkflowExecution(ctx, updateRequest)
+			s.ErrorAs(err, new(*serviceerror.FailedPrecondition))
+			s.ErrorAs(shardContext.SetQueueState(tasks.CategoryTransfer, 1, &persistencespb.QueueState{}), new(*serviceerror.FailedPrecondition))
+
+			trace.ExecutionErr = executionErr
+			return nil
+		},
+	)
+
+	trace, err := s.mockShard.DryRunTask(context.Background(), tasks.CategoryTransfer, 123)
+	s.NoError(err)
+	s.Equal(task, trace.Task)
+	s.Equal([]any{updateRequest}, trace.Writes)
+	s.Equal(map[tasks.Category][]tasks.Task{tasks.CategoryTimer: {generatedTask}}, trace.GeneratedTasks)
+	s.Len(trace.Calls, 1)
+	s.Equal(historyservice.HistoryService_RecordChildExecutionCompleted_FullMethodName, trace.Calls[0].Method)
+	s.Equal(recordRequest, trace.Calls[0].Request)
+	s.Equal(executionErr, trace.ExecutionErr)
+}
+
+func (s *contextSuite) TestAddTasks_TaskRewriter() {
+	s.mockShard.taskRewriter = func(task tasks.Task) tasks.Task {
+		if activityTask, ok := task.(*tasks.ActivityTask); ok {
+			rewritten := *activityTask
+			rewritten.Version = 42
+			return &rewritten
+		}
+		return task
+	}
+	fireTime := s.timeSource.Now().Add(time.Minute)
+	addTasksRequest := &persistence.AddHistoryTasksRequest{
+		ShardID:     s.mockShard.GetShardID(),
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		Tasks: map[tasks.Category][]tasks.Task{
+			tasks.CategoryTransfer: {&tasks.ActivityTask{
+				WorkflowKey: definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID),
+				TaskQueue:   "task-queue",
+				Version:     1,
+			}},
+			tasks.CategoryTimer: {&tasks.UserTimerTask{
+				WorkflowKey:         definition.NewWorkflowKey(tests.NamespaceID.String(), tests.WorkflowID, tests.RunID),
+				VisibilityTimestamp: fireTime,
+				EventID:             5,
+			}},
+		},
+	}
+
+	var persistedTasks map[tasks.Category][]tasks.Task
+	s.mockExecutionManager.EXPECT().AddHistoryTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.AddHistoryTasksRequest) error {
+			persistedTasks = request.Tasks
+			return nil
+		},
+	)
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any())
+
+	s.NoError(s.mockShard.AddTasks(context.Background(), addTasksRequest))
+
+	serializer := s.mockShard.GetPayloadSerializer()
+	for category, categoryTasks := range persistedTasks {
+		s.Len(categoryTasks, 1)
+		blob, err := serializer.SerializeTask(categoryTasks[0])
+		s.NoError(err)
+		task, err := serializer.DeserializeTask(category, blob)
+		s.NoError(err)
+		s.Zero(categoryTasks[0].GetKey().CompareTo(task.GetKey()))
+
+		switch task := task.(type) {
+		case *tasks.ActivityTask:
+			s.Equal(int64(42), task.Version)
+			s.Equal("task-queue", task.TaskQueue)
+		case *tasks.UserTimerTask:
+			s.Equal(int64(5), task.EventID)
+		default:
+			s.Fail("unexpected task type", "%T", task)
+		}
+	}
+}
+
+func (s *contextSuite) TestAddTasks_TaskRewriterChangesKey() {
+	s.mockShard.taskRewriter = func(task tasks.Task) tasks.Task {
+		rewritten := *task.(*tasks.UserTimerTask)
+		rewritten.VisibilityTimestamp = rewritten.VisibilityTimestamp.Add(time.Hour)
+		return &rewritten
+	}
+	addTasksRequest := &persistence.AddHistoryTasksRequest{
+		ShardID:     s.mockShard.GetShardID(),
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		Tasks: map[tasks.Category][]tasks.Task{
+			tasks.CategoryTimer: {&tasks.UserTimerTask{VisibilityTimestamp: s.timeSource.Now()}},
+		},
+	}
+
+	err := s.mockShard.AddTasks(context.Background(), addTasksRequest)
+	var invalidRequestErr *persistence.InvalidPersistenceRequestError
+	s.ErrorAs(err, &invalidRequestErr)
+}
+
+func (s *contextSuite) TestAddTasks_TaskRewriterChangesCategory() {
+	s.mockShard.taskRewriter = func(task tasks.Task) tasks.Task {
+		return &tasks.UserTimerTask{}
+	}
+	addTasksRequest := &persistence.AddHistoryTasksRequest{
+		ShardID:     s.mockShard.GetShardID(),
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  tests.WorkflowID,
+		Tasks: map[tasks.Category][]tasks.Task{
+			tasks.CategoryTransfer: {&tasks.ActivityTask{}},
+		},
+	}
+
+	err := s.mockShard.AddTasks(context.Background(), addTasksRequest)
+	var invalidRequestErr *persistence.InvalidPersistenceRequestError
+	s.ErrorAs(err, &invalidRequestErr)
+}
+
+type fakeTaskDLQWriter struct {
+	err   error
+	tasks []tasks.Task
+}
+
+func (w *fakeTaskDLQWriter) WriteTaskToDLQ(_ context.Context, _, _ string, task tasks.Task) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.tasks = append(w.tasks, task)
+	return nil
+}
@@ -27,7 +27,9 @@ package shard
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"go.temporal.io/server/api/historyservice/v1"
@@ -55,6 +57,17 @@ type ContextTest struct {
 	MockEventsCache *events.MockCache
 }
 
+// ContextState is a snapshot of the in-memory state of a shard context, taken by
+// ContextTest.ExportState and reloaded by ContextTest.ImportState. Only used by tests.
+type ContextState struct {
+	shardInfo            *persistencespb.ShardInfo
+	remoteClusterInfos   map[string]*remoteClusterInfo
+	handoverNamespaces   map[namespace.Name]*namespaceHandOverInfo
+	nextTaskID           int64
+	exclusiveMaxTaskID   int64
+	taskMinScheduledTime time.Time
+}
+
 var _ Context = (*ContextTest)(nil)
 
 func NewTestContextWithTimeSource(
@@ -66,6 +79,8 @@ func NewTestContextWithTimeSource(
 	result := NewTestContext(ctrl, shardInfo, config)
 	result.timeSource = timeSource
 	result.taskKeyManager.generator.timeSource = timeSource
+	result.currentExecutionCache = newCurrentExecutionCache(config, timeSource)
+	result.queueDrainRate = newQueueDrainRate(config.ShardQueueDrainRateWindow, timeSource.Now())
 	result.Resource.TimeSource = timeSource
 	return result
 }
@@ -180,6 +195,15 @@ func newTestContext(t *resourcetest.Test, eventsCache events.Cache, config Conte
 		hostInfoProvider:        hostInfoProvider,
 		taskCategoryRegistry:    taskCategoryRegistry,
 		ioSemaphore:             locks.NewPrioritySemaphore(1),
+		readRateLimiter:         newReadRateLimiter(config.Config),
+		currentExecutionCache:   newCurrentExecutionCache(config.Config, t.TimeSource),
+		speculativeTasks:        newSpeculativeTaskSet(),
+		replicationTaskAuditLog: newReplicationTaskAuditLog(config.Config.ShardReplicationTaskAuditSize),
+		taskGenerationHeatmap: newTaskGenerationHeatmap(
+			config.Config.ShardTaskGenerationHeatmapTopK,
+			config.Config.ShardTaskGenerationHeatmapRetention,
+		),
+		queueDrainRate: newQueueDrainRate(config.Config.ShardQueueDrainRateWindow, t.TimeSource.Now()),
 	}
 	ctx.taskKeyManager = newTaskKeyManager(
 		ctx.taskCategoryRegistry,
@@ -191,6 +215,7 @@ func newTestContext(t *resourcetest.Test, eventsCache events.Cache, config Conte
 		},
 	)
 	ctx.taskKeyManager.setRangeID(config.ShardInfo.RangeId)
+	ctx.publishQuarantinedWorkflowsLocked()
 	return ctx
 }
 
@@ -221,6 +246,69 @@ func (s *ContextTest) SetStateMachineRegistry(reg *hsm.Registry) {
 	s.stateMachineRegistry = reg
 }
 
+// ExportState captures the in-memory shard state: shard info including queue and replication
+// states, remote cluster ack levels, namespace handover state and the task key range. Only
+// used by tests.
+func (s *ContextTest) ExportState() *ContextState {
+	s.rLock()
+	defer s.rUnlock()
+
+	generator := s.taskKeyManager.generator
+	return &ContextState{
+		shardInfo:            copyShardInfo(s.shardInfo),
+		remoteClusterInfos:   copyRemoteClusterInfos(s.remoteClusterInfos),
+		handoverNamespaces:   copyHandoverNamespaces(s.handoverNamespaces),
+		nextTaskID:           generator.nextTaskID,
+		exclusiveMaxTaskID:   generator.exclusiveMaxTaskID,
+		taskMinScheduledTime: generator.taskMinScheduledTime,
+	}
+}
+
+// ImportState replaces the in-memory shard state with a snapshot from ExportState, without
+// going through persistence. The snapshot must be from the same shard and can be imported
+// more than once. Only used by tests.
+func (s *ContextTest) ImportState(state *ContextState) error {
+	if state.shardInfo.GetShardId() != s.shardID {
+		return fmt.Errorf("unable to import state of shard %v into shard %v", state.shardInfo.GetShardId(), s.shardID)
+	}
+
+	s.wLock()
+	defer s.wUnlock()
+
+	s.shardInfo = copyShardInfo(state.shardInfo)
+	s.publishQuarantinedWorkflowsLocked()
+	s.remoteClusterInfos = copyRemoteClusterInfos(state.remoteClusterInfos)
+	s.handoverNamespaces = copyHandoverNamespaces(state.handoverNamespaces)
+	generator := s.taskKeyManager.generator
+	generator.nextTaskID = state.nextTaskID
+	generator.exclusiveMaxTaskID = state.exclusiveMaxTaskID
+	generator.taskMinScheduledTime = state.taskMinScheduledTime
+	return nil
+}
+
+func copyRemoteClusterInfos(infos map[string]*remoteClusterInfo) map[string]*remoteClusterInfo {
+	result := make(map[string]*remoteClusterInfo, len(infos))
+	for clusterName, info := range infos {
+		result[clusterName] = &remoteClusterInfo{
+			CurrentTime:                  info.CurrentTime,
+			AckedReplicationTaskIDs:      maps.Clone(info.AckedReplicationTaskIDs),
+			AckedReplicationTimestamps:   maps.Clone(info.AckedReplicationTimestamps),
+			AppliedReplicationTaskIDs:    maps.Clone(info.AppliedReplicationTaskIDs),
+			AppliedReplicationTimestamps: maps.Clone(info.AppliedReplicationTimestamps),
+		}
+	}
+	return result
+}
+
+func copyHandoverNamespaces(handovers map[namespace.Name]*namespaceHandOverInfo) map[namespace.Name]*namespaceHandOverInfo {
+	result := make(map[namespace.Name]*namespaceHandOverInfo, len(handovers))
+	for nsName, handover := range handovers {
+		handoverCopy := *handover
+		result[nsName] = &handoverCopy
+	}
+	return result
+}
+
 // StopForTest calls FinishStop(). In general only the controller
 // should call that, but integration tests need to do it also to clean up any
 // background acquireShard goroutines that may exist.
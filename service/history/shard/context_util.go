@@ -25,9 +25,13 @@
 package shard
 
 import (
+	"slices"
+
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/primitives/timestamp"
 	"go.temporal.io/server/service/history/tasks"
 )
@@ -106,3 +110,106 @@ func getMinTaskKey(
 	}
 	return minTaskKey
 }
+
+func countQueueStateScopes(
+	queueState *persistencespb.QueueState,
+) int {
+	count := 0
+	for _, readerState := range queueState.ReaderStates {
+		count += len(readerState.Scopes)
+	}
+	return count
+}
+
+// removeTaskFromQueueState returns a copy of queueState whose reader scopes no longer include
+// taskKey, so that the task is considered completed when the queue loads the state. Readers that
+// are left without scopes are removed. It returns false if no scope included taskKey.
+func removeTaskFromQueueState(
+	queueState *persistencespb.QueueState,
+	taskKey tasks.Key,
+) (*persistencespb.QueueState, bool) {
+	queueState = common.CloneProto(queueState)
+	removed := false
+	for readerID, readerState := range queueState.ReaderStates {
+		scopes := make([]*persistencespb.QueueSliceScope, 0, len(readerState.Scopes)+1)
+		for _, scope := range readerState.Scopes {
+			inclusiveMin := ConvertFromPersistenceTaskKey(scope.Range.InclusiveMin)
+			exclusiveMax := ConvertFromPersistenceTaskKey(scope.Range.ExclusiveMax)
+			if taskKey.CompareTo(inclusiveMin) < 0 || taskKey.CompareTo(exclusiveMax) >= 0 {
+				scopes = append(scopes, scope)
+				continue
+			}
+
+			removed = true
+			if inclusiveMin.CompareTo(taskKey) < 0 {
+				scopes = append(scopes, &persistencespb.QueueSliceScope{
+					Range: &persistencespb.QueueSliceRange{
+						InclusiveMin: scope.Range.InclusiveMin,
+						ExclusiveMax: ConvertToPersistenceTaskKey(taskKey),
+					},
+					Predicate: scope.Predicate,
+				})
+			}
+			if next := taskKey.Next(); next.CompareTo(exclusiveMax) < 0 {
+				scopes = append(scopes, &persistencespb.QueueSliceScope{
+					Range: &persistencespb.QueueSliceRange{
+						InclusiveMin: ConvertToPersistenceTaskKey(next),
+						ExclusiveMax: scope.Range.ExclusiveMax,
+					},
+					Predicate: common.CloneProto(scope.Predicate),
+				})
+			}
+		}
+		if len(scopes) == 0 {
+			delete(queueState.ReaderStates, readerID)
+			continue
+		}
+		readerState.Scopes = scopes
+	}
+	return queueState, removed
+}
+
+// compactQueueState merges adjacent or overlapping scopes that have the same predicate within
+// each reader. The merged scopes cover exactly the same tasks as the original ones.
+func compactQueueState(
+	queueState *persistencespb.QueueState,
+) {
+	for _, readerState := range queueState.ReaderStates {
+		readerState.Scopes = compactQueueSliceScopes(readerState.Scopes)
+	}
+}
+
+func compactQueueSliceScopes(
+	scopes []*persistencespb.QueueSliceScope,
+) []*persistencespb.QueueSliceScope {
+	if len(scopes) <= 1 {
+		return scopes
+	}
+
+	scopes = slices.Clone(scopes)
+	slices.SortStableFunc(scopes, func(a, b *persistencespb.QueueSliceScope) int {
+		return ConvertFromPersistenceTaskKey(a.Range.InclusiveMin).CompareTo(ConvertFromPersistenceTaskKey(b.Range.InclusiveMin))
+	})
+
+	compacted := []*persistencespb.QueueSliceScope{scopes[0]}
+	for _, scope := range scopes[1:] {
+		last := compacted[len(compacted)-1]
+		lastMax := ConvertFromPersistenceTaskKey(last.Range.ExclusiveMax)
+		if ConvertFromPersistenceTaskKey(scope.Range.InclusiveMin).CompareTo(lastMax) > 0 ||
+			!proto.Equal(last.Predicate, scope.Predicate) {
+			compacted = append(compacted, scope)
+			continue
+		}
+
+		if ConvertFromPersistenceTaskKey(scope.Range.ExclusiveMax).CompareTo(lastMax) > 0 {
+			compacted[len(compacted)-1] = &persistencespb.QueueSliceScope{
+				Range: &persistencespb.QueueSliceRange{
+					InclusiveMin: last.Range.InclusiveMin,
+					ExclusiveMax: scope.Range.ExclusiveMax,
+				},
+				Predicate: last.Predicate,
+			}
+		}
+	}
+	return compacted
+}
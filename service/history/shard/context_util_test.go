@@ -33,6 +33,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/service/history/tasks"
 	"go.temporal.io/server/service/history/tests"
 )
 
@@ -96,3 +99,74 @@ func (s *contextUtilSuite) newMockContext() *MockContext {
 	mockContext.EXPECT().GetConfig().Return(tests.NewDynamicConfig()).AnyTimes()
 	return mockContext
 }
+
+func (s *contextUtilSuite) TestCompactQueueState() {
+	universal := &persistencespb.Predicate{
+		PredicateType: enumsspb.PREDICATE_TYPE_UNIVERSAL,
+		Attributes:    &persistencespb.Predicate_UniversalPredicateAttributes{},
+	}
+	namespace := &persistencespb.Predicate{
+		PredicateType: enumsspb.PREDICATE_TYPE_NAMESPACE_ID,
+		Attributes: &persistencespb.Predicate_NamespaceIdPredicateAttributes{
+			NamespaceIdPredicateAttributes: &persistencespb.NamespaceIdPredicateAttributes{
+				NamespaceIds: []string{tests.NamespaceID.String()},
+			},
+		},
+	}
+	newScope := func(min, max int64, predicate *persistencespb.Predicate) *persistencespb.QueueSliceScope {
+		return &persistencespb.QueueSliceScope{
+			Range: &persistencespb.QueueSliceRange{
+				InclusiveMin: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(min)),
+				ExclusiveMax: ConvertToPersistenceTaskKey(tasks.NewImmediateKey(max)),
+			},
+			Predicate: predicate,
+		}
+	}
+
+	queueState := &persistencespb.QueueState{
+		ReaderStates: map[int64]*persistencespb.QueueReaderState{
+			0: {
+				Scopes: []*persistencespb.QueueSliceScope{
+					newScope(20, 30, universal), // out of order
+					newScope(0, 10, universal),
+					newScope(10, 15, universal), // adjacent
+					newScope(12, 18, universal), // overlapping
+					newScope(25, 28, universal), // contained
+					newScope(30, 40, namespace), // adjacent, but different predicate
+					newScope(40, 50, namespace),
+					newScope(60, 70, namespace), // gap
+				},
+			},
+			1: {
+				Scopes: []*persistencespb.QueueSliceScope{
+					newScope(0, 10, universal),
+				},
+			},
+		},
+	}
+	s.Equal(9, countQueueStateScopes(queueState))
+
+	compactQueueState(queueState)
+
+	s.Equal([]*persistencespb.QueueSliceScope{
+		newScope(0, 18, universal),
+		newScope(20, 30, universal),
+		newScope(30, 50, namespace),
+		newScope(60, 70, namespace),
+	}, queueState.ReaderStates[0].Scopes)
+	s.Equal([]*persistencespb.QueueSliceScope{
+		newScope(0, 10, universal),
+	}, queueState.ReaderStates[1].Scopes)
+
+	// every task covered before compaction is still covered, and no new task is
+	for taskID := int64(0); taskID < 80; taskID++ {
+		covered := (taskID < 18) || (taskID >= 20 && taskID < 50) || (taskID >= 60 && taskID < 70)
+		found := false
+		for _, scope := range queueState.ReaderStates[0].Scopes {
+			if taskID >= scope.Range.InclusiveMin.TaskId && taskID < scope.Range.ExclusiveMax.TaskId {
+				found = true
+			}
+		}
+		s.Equal(covered, found, "task %v", taskID)
+	}
+}
@@ -427,6 +427,8 @@ func (c *ControllerImpl) acquireShards(ctx context.Context) {
 		engineCtx, engineCancel := context.WithTimeout(ctx, 1*time.Second)
 		defer engineCancel()
 		_, _ = shard.GetEngine(engineCtx)
+
+		c.probeShardLiveness(ctx, shardID)
 	}
 
 	concurrency := int64(max(c.config.AcquireShardConcurrency(), 1))
@@ -452,6 +454,38 @@ func (c *ControllerImpl) acquireShards(ctx context.Context) {
 	c.publishShardCountUpdate(numOfOwnedShards)
 }
 
+// probeShardLiveness unloads the shard if its lock can't be acquired within
+// ShardLivenessProbeTimeout. It does nothing if the probe is disabled.
+func (c *ControllerImpl) probeShardLiveness(ctx context.Context, shardID int32) {
+	timeout := c.config.ShardLivenessProbeTimeout()
+	if timeout <= 0 {
+		return
+	}
+
+	c.RLock()
+	shard, ok := c.historyShards[shardID]
+	c.RUnlock()
+	if !ok || !shard.IsValid() {
+		return
+	}
+
+	report := shard.ProbeLiveness(ctx, timeout)
+	if !report.Stuck || ctx.Err() != nil {
+		return
+	}
+
+	metrics.ShardLivenessProbeStuck.With(c.taggedMetricsHandler).Record(1)
+	c.contextTaggedLogger.Error("Shard lock liveness probe failed, unloading shard",
+		tag.ShardID(shardID),
+		tag.NewDurationTag("probe-wait", report.Waited),
+		tag.NewStringTag("lock-holder-operation", report.HolderOperation),
+		tag.NewDurationTag("lock-holder-duration", report.HeldFor),
+		tag.NewStringTag("lock-holder-stack", report.HolderStack),
+	)
+	// Stopping a wedged shard may block on its lock, so don't block shard acquisition on it.
+	go c.shardRemoveAndStop(shard)
+}
+
 // publishShardCountUpdate publishes the current number of shards that this controller owns to all shard count
 // subscribers in a non-blocking manner.
 func (c *ControllerImpl) publishShardCountUpdate(shardCount int) {
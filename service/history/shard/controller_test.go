@@ -847,6 +847,47 @@ func (s *controllerSuite) TestShardLingerSuccess() {
 	s.Len(s.shardController.ShardIDs(), 0)
 }
 
+func (s *controllerSuite) TestShardLivenessProbe_Stuck() {
+	shardID := int32(1)
+	s.config.NumberOfShards = 1
+	s.config.ShardLivenessProbeTimeout = dynamicconfig.GetDurationPropertyFn(time.Second)
+
+	shard := NewMockControllableContext(s.controller)
+	s.shardController.historyShards[shardID] = shard
+
+	stopped := make(chan struct{})
+	shard.EXPECT().IsValid().Return(true)
+	shard.EXPECT().ProbeLiveness(gomock.Any(), time.Second).Return(LivenessReport{Stuck: true})
+	shard.EXPECT().GetShardID().Return(shardID).AnyTimes()
+	shard.EXPECT().FinishStop().Do(func() { close(stopped) })
+
+	s.shardController.probeShardLiveness(context.Background(), shardID)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		s.Fail("shard was not stopped")
+	}
+	s.Empty(s.shardController.ShardIDs())
+	s.Equal(float64(1), s.readMetricsCounter(
+		metrics.ShardLivenessProbeStuck.Name(),
+		metrics.OperationTag(metrics.HistoryShardControllerScope)))
+}
+
+func (s *controllerSuite) TestShardLivenessProbe_Disabled() {
+	shardID := int32(1)
+	s.config.NumberOfShards = 1
+	s.config.ShardLivenessProbeTimeout = dynamicconfig.GetDurationPropertyFn(0)
+
+	// no calls are expected on the shard
+	shard := NewMockControllableContext(s.controller)
+	s.shardController.historyShards[shardID] = shard
+
+	s.shardController.probeShardLiveness(context.Background(), shardID)
+
+	s.Equal([]int32{shardID}, s.shardController.ShardIDs())
+}
+
 // TestShardCounter verifies that we can subscribe to shard count updates, receive them when shards are acquired, and
 // unsubscribe from the updates when needed.
 func (s *controllerSuite) TestShardCounter() {
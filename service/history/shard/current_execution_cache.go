@@ -0,0 +1,135 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"sync"
+
+	"go.temporal.io/server/common/cache"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/service/history/configs"
+)
+
+type (
+	// currentExecutionCache caches current execution reads for ReadConsistencyEventual. Entries are
+	// invalidated whenever the shard writes the current execution of a workflow.
+	//
+	// A read that was in flight while its workflow was written may return the state from before the
+	// write. Invalidation bumps the generation of in-flight reads of the workflow, and a read is only
+	// cached if its generation didn't change.
+	currentExecutionCache struct {
+		sync.Mutex
+		cache    cache.Cache
+		inflight map[currentExecutionKey]*inflightReads
+	}
+
+	currentExecutionKey struct {
+		namespaceID string
+		workflowID  string
+	}
+
+	inflightReads struct {
+		count      int
+		generation int64
+	}
+)
+
+func newCurrentExecutionCache(
+	config *configs.Config,
+	timeSource clock.TimeSource,
+) *currentExecutionCache {
+	return &currentExecutionCache{
+		cache: cache.New(config.ShardCurrentExecutionCacheSize(), &cache.Options{
+			TTL:        config.ShardCurrentExecutionCacheTTL(),
+			TimeSource: timeSource,
+		}),
+		inflight: make(map[currentExecutionKey]*inflightReads),
+	}
+}
+
+// get returns the cached response for the workflow. On a miss, it returns a generation which
+// must be passed to put once the read from persistence is done, even if it failed.
+func (c *currentExecutionCache) get(
+	namespaceID string,
+	workflowID string,
+) (*persistence.GetCurrentExecutionResponse, int64) {
+	key := currentExecutionKey{namespaceID, workflowID}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if resp, ok := c.cache.Get(key).(*persistence.GetCurrentExecutionResponse); ok {
+		copied := *resp
+		return &copied, 0
+	}
+	reads, ok := c.inflight[key]
+	if !ok {
+		reads = &inflightReads{}
+		c.inflight[key] = reads
+	}
+	reads.count++
+	return nil, reads.generation
+}
+
+// put caches resp if the workflow wasn't invalidated since the matching get. resp may be nil if
+// the read failed.
+func (c *currentExecutionCache) put(
+	namespaceID string,
+	workflowID string,
+	generation int64,
+	resp *persistence.GetCurrentExecutionResponse,
+) {
+	key := currentExecutionKey{namespaceID, workflowID}
+
+	c.Lock()
+	defer c.Unlock()
+
+	reads := c.inflight[key]
+	reads.count--
+	if reads.count == 0 {
+		delete(c.inflight, key)
+	}
+	if resp == nil || generation != reads.generation {
+		return
+	}
+	copied := *resp
+	c.cache.Put(key, &copied)
+}
+
+func (c *currentExecutionCache) invalidate(
+	namespaceID string,
+	workflowID string,
+) {
+	key := currentExecutionKey{namespaceID, workflowID}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.cache.Delete(key)
+	if reads, ok := c.inflight[key]; ok {
+		reads.generation++
+	}
+}
@@ -146,6 +146,20 @@ func (mr *MockEngineMockRecorder) DescribeWorkflowExecution(ctx, request interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkflowExecution", reflect.TypeOf((*MockEngine)(nil).DescribeWorkflowExecution), ctx, request)
 }
 
+// DryRunTask mocks base method.
+func (m *MockEngine) DryRunTask(ctx context.Context, shardContext Context, task tasks.Task, trace *TaskExecutionTrace) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunTask", ctx, shardContext, task, trace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DryRunTask indicates an expected call of DryRunTask.
+func (mr *MockEngineMockRecorder) DryRunTask(ctx, shardContext, task, trace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunTask", reflect.TypeOf((*MockEngine)(nil).DryRunTask), ctx, shardContext, task, trace)
+}
+
 // ExecuteMultiOperation mocks base method.
 func (m *MockEngine) ExecuteMultiOperation(ctx context.Context, request *v12.ExecuteMultiOperationRequest) (*v12.ExecuteMultiOperationResponse, error) {
 	m.ctrl.T.Helper()
@@ -513,6 +527,34 @@ func (mr *MockEngineMockRecorder) RebuildMutableState(ctx, namespaceUUID, execut
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildMutableState", reflect.TypeOf((*MockEngine)(nil).RebuildMutableState), ctx, namespaceUUID, execution)
 }
 
+// ReconcileCurrentExecution mocks base method.
+func (m *MockEngine) ReconcileCurrentExecution(ctx context.Context, workflowKey definition.WorkflowKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileCurrentExecution", ctx, workflowKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileCurrentExecution indicates an expected call of ReconcileCurrentExecution.
+func (mr *MockEngineMockRecorder) ReconcileCurrentExecution(ctx, workflowKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileCurrentExecution", reflect.TypeOf((*MockEngine)(nil).ReconcileCurrentExecution), ctx, workflowKey)
+}
+
+// ReconcileVersionHistory mocks base method.
+func (m *MockEngine) ReconcileVersionHistory(ctx context.Context, workflowKey definition.WorkflowKey, sourceVersionHistory *v11.VersionHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileVersionHistory", ctx, workflowKey, sourceVersionHistory)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileVersionHistory indicates an expected call of ReconcileVersionHistory.
+func (mr *MockEngineMockRecorder) ReconcileVersionHistory(ctx, workflowKey, sourceVersionHistory interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileVersionHistory", reflect.TypeOf((*MockEngine)(nil).ReconcileVersionHistory), ctx, workflowKey, sourceVersionHistory)
+}
+
 // RecordActivityTaskHeartbeat mocks base method.
 func (m *MockEngine) RecordActivityTaskHeartbeat(ctx context.Context, request *v12.RecordActivityTaskHeartbeatRequest) (*v12.RecordActivityTaskHeartbeatResponse, error) {
 	m.ctrl.T.Helper()
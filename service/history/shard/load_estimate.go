@@ -0,0 +1,51 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"time"
+)
+
+type (
+	// ShardLoadEstimate is an estimate of how much data a shard holds, see Context.EstimateLoad.
+	// It's meant to compare shards with each other, e.g. to rebalance them, not for accounting.
+	ShardLoadEstimate struct {
+		// ExecutionCount is the number of workflow executions of the shard, open or closed but not
+		// deleted yet.
+		ExecutionCount int64
+		// HistorySizeBytes is the sum of the history sizes of the executions, as recorded in their
+		// mutable state.
+		HistorySizeBytes int64
+		// MutableStateSizeBytes is the sum of the serialized sizes of the mutable states of the
+		// executions.
+		MutableStateSizeBytes int64
+		// Complete is false if the shard has more executions than
+		// history.shardLoadEstimateMaxExecutions, in which case the other fields only cover the
+		// executions that were scanned and are lower bounds.
+		Complete bool
+		// EstimatedAt is when the executions were scanned.
+		EstimatedAt time.Time
+	}
+)
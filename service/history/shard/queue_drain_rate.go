@@ -0,0 +1,112 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/dynamicconfig"
+)
+
+const queueDrainRateBucketSize = 10 * time.Second
+
+type (
+	// queueDrainRate measures the rate the queues of a shard complete tasks at, per task category,
+	// over a rolling window, from the task counts the queues report when they checkpoint their
+	// state. It's kept in memory only, so it restarts when the shard is reloaded.
+	queueDrainRate struct {
+		sync.Mutex
+		window dynamicconfig.DurationPropertyFn
+		// start is when the measurement started, the rate is averaged over less than the window
+		// until it's been running for that long
+		start time.Time
+		// buckets are ordered by start time, oldest first
+		buckets map[int][]queueDrainRateBucket
+	}
+
+	queueDrainRateBucket struct {
+		start time.Time
+		count int64
+	}
+)
+
+func newQueueDrainRate(
+	window dynamicconfig.DurationPropertyFn,
+	now time.Time,
+) *queueDrainRate {
+	return &queueDrainRate{
+		window:  window,
+		start:   now,
+		buckets: make(map[int][]queueDrainRateBucket),
+	}
+}
+
+func (r *queueDrainRate) record(categoryID int, tasksCompleted int, now time.Time) {
+	if tasksCompleted <= 0 {
+		return
+	}
+	start := now.Truncate(queueDrainRateBucketSize)
+
+	r.Lock()
+	defer r.Unlock()
+
+	buckets := r.expireLocked(categoryID, now)
+	if len(buckets) == 0 || buckets[len(buckets)-1].start.Before(start) {
+		buckets = append(buckets, queueDrainRateBucket{start: start})
+	}
+	// checkpoints racing with the start of a new bucket may land in the previous one
+	buckets[len(buckets)-1].count += int64(tasksCompleted)
+	r.buckets[categoryID] = buckets
+}
+
+// rate returns the number of tasks of the category completed per second in the window, or since
+// the measurement started if that's more recent.
+func (r *queueDrainRate) rate(categoryID int, now time.Time) float64 {
+	r.Lock()
+	defer r.Unlock()
+
+	buckets := r.expireLocked(categoryID, now)
+	r.buckets[categoryID] = buckets
+	var count int64
+	for _, bucket := range buckets {
+		count += bucket.count
+	}
+	elapsed := min(r.window(), now.Sub(r.start))
+	if count == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(count) / elapsed.Seconds()
+}
+
+func (r *queueDrainRate) expireLocked(categoryID int, now time.Time) []queueDrainRateBucket {
+	since := now.Add(-r.window())
+	buckets := r.buckets[categoryID]
+	expired := 0
+	for expired < len(buckets) && !buckets[expired].start.Add(queueDrainRateBucketSize).After(since) {
+		expired++
+	}
+	return buckets[expired:]
+}
@@ -0,0 +1,55 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueDrainRate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	categoryID := 1
+	r := newQueueDrainRate(func() time.Duration { return time.Minute }, start)
+	require.Zero(t, r.rate(categoryID, start))
+
+	// until the window is full, the rate is averaged over the time since the start
+	r.record(categoryID, 10, start.Add(5*time.Second))
+	r.record(categoryID, 0, start.Add(6*time.Second))
+	r.record(categoryID, 20, start.Add(15*time.Second))
+	require.Equal(t, 1.5, r.rate(categoryID, start.Add(20*time.Second)))
+	require.Zero(t, r.rate(categoryID+1, start.Add(20*time.Second)))
+
+	// and then over the window
+	r.record(categoryID, 30, start.Add(70*time.Second))
+	require.Equal(t, 0.5, r.rate(categoryID, start.Add(80*time.Second)))
+
+	// the queue stopped draining
+	require.Zero(t, r.rate(categoryID, start.Add(3*time.Minute)))
+}
@@ -0,0 +1,114 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"sync"
+	"time"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/common/dynamicconfig"
+)
+
+type (
+	// ReplicationTaskAudit is the metadata of a replication task applied on a shard, see
+	// Context.RecentReplicationTasks.
+	ReplicationTaskAudit struct {
+		TaskType      enumsspb.TaskType
+		TaskID        int64
+		SourceCluster string
+		// Err is the error returned when applying the task, nil if it was applied successfully.
+		Err         error
+		AppliedTime time.Time
+	}
+
+	// replicationTaskAuditLog is a ring buffer of the most recently applied replication tasks. It's
+	// kept in memory only, so it's lost when the shard is unloaded.
+	replicationTaskAuditLog struct {
+		sync.Mutex
+		size    dynamicconfig.IntPropertyFn
+		entries []ReplicationTaskAudit
+		// next is the index of the oldest entry once entries is full, which is overwritten next.
+		next int
+	}
+)
+
+func newReplicationTaskAuditLog(
+	size dynamicconfig.IntPropertyFn,
+) *replicationTaskAuditLog {
+	return &replicationTaskAuditLog{
+		size: size,
+	}
+}
+
+func (l *replicationTaskAuditLog) record(audit ReplicationTaskAudit) {
+	size := l.size()
+
+	l.Lock()
+	defer l.Unlock()
+
+	if size != cap(l.entries) {
+		l.resizeLocked(size)
+	}
+	if size <= 0 {
+		return
+	}
+	if len(l.entries) < size {
+		l.entries = append(l.entries, audit)
+		return
+	}
+	l.entries[l.next] = audit
+	l.next = (l.next + 1) % size
+}
+
+// recent returns the recorded entries, oldest first.
+func (l *replicationTaskAuditLog) recent() []ReplicationTaskAudit {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.orderedLocked()
+}
+
+func (l *replicationTaskAuditLog) orderedLocked() []ReplicationTaskAudit {
+	ordered := make([]ReplicationTaskAudit, 0, len(l.entries))
+	ordered = append(ordered, l.entries[l.next:]...)
+	return append(ordered, l.entries[:l.next]...)
+}
+
+// resizeLocked changes the capacity of the buffer, keeping the most recent entries that fit.
+func (l *replicationTaskAuditLog) resizeLocked(size int) {
+	if size <= 0 {
+		l.entries = nil
+		l.next = 0
+		return
+	}
+	ordered := l.orderedLocked()
+	if len(ordered) > size {
+		ordered = ordered[len(ordered)-size:]
+	}
+	l.entries = make([]ReplicationTaskAudit, len(ordered), size)
+	copy(l.entries, ordered)
+	l.next = 0
+}
@@ -0,0 +1,119 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+)
+
+type (
+	replicationTaskAuditLogSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		size atomic.Int64
+		log  *replicationTaskAuditLog
+	}
+)
+
+func TestReplicationTaskAuditLogSuite(t *testing.T) {
+	s := &replicationTaskAuditLogSuite{}
+	suite.Run(t, s)
+}
+
+func (s *replicationTaskAuditLogSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.size.Store(3)
+	s.log = newReplicationTaskAuditLog(func() int { return int(s.size.Load()) })
+}
+
+func (s *replicationTaskAuditLogSuite) TestRecent_Empty() {
+	s.Empty(s.log.recent())
+}
+
+func (s *replicationTaskAuditLogSuite) TestRecent_Wraps() {
+	s.recordTasks(1, 2)
+	s.Equal([]int64{1, 2}, s.recentTaskIDs())
+
+	s.recordTasks(3)
+	s.Equal([]int64{1, 2, 3}, s.recentTaskIDs())
+
+	s.recordTasks(4, 5)
+	s.Equal([]int64{3, 4, 5}, s.recentTaskIDs())
+
+	s.recordTasks(6, 7, 8, 9)
+	s.Equal([]int64{7, 8, 9}, s.recentTaskIDs())
+}
+
+func (s *replicationTaskAuditLogSuite) TestRecent_KeepsMetadata() {
+	applyErr := errors.New("failed to apply")
+	audit := ReplicationTaskAudit{
+		TaskType:      enumsspb.TASK_TYPE_REPLICATION_HISTORY,
+		TaskID:        1,
+		SourceCluster: "source",
+		Err:           applyErr,
+	}
+	s.log.record(audit)
+
+	s.Equal([]ReplicationTaskAudit{audit}, s.log.recent())
+}
+
+func (s *replicationTaskAuditLogSuite) TestResize() {
+	s.recordTasks(1, 2, 3, 4)
+
+	s.size.Store(2)
+	s.recordTasks(5)
+	s.Equal([]int64{4, 5}, s.recentTaskIDs())
+
+	s.size.Store(4)
+	s.recordTasks(6, 7, 8)
+	s.Equal([]int64{5, 6, 7, 8}, s.recentTaskIDs())
+
+	s.size.Store(0)
+	s.recordTasks(9)
+	s.Empty(s.log.recent())
+}
+
+func (s *replicationTaskAuditLogSuite) recordTasks(taskIDs ...int64) {
+	for _, taskID := range taskIDs {
+		s.log.record(ReplicationTaskAudit{TaskID: taskID})
+	}
+}
+
+func (s *replicationTaskAuditLogSuite) recentTaskIDs() []int64 {
+	var taskIDs []int64
+	for _, audit := range s.log.recent() {
+		taskIDs = append(taskIDs, audit.TaskID)
+	}
+	return taskIDs
+}
@@ -0,0 +1,90 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"sync"
+	"time"
+
+	ctasks "go.temporal.io/server/common/tasks"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+const (
+	minSpeculativeTaskPruneSize = 64
+)
+
+type (
+	// speculativeTaskSet keeps track of the in-memory speculative workflow task timeout tasks
+	// added to a shard, so that they can be drained when the shard is unloaded. Tasks that were
+	// cancelled or already fired are pruned lazily.
+	speculativeTaskSet struct {
+		sync.Mutex
+		tasks map[*tasks.WorkflowTaskTimeoutTask]struct{}
+		// pruneAt is the number of tracked tasks at which the set is pruned next.
+		pruneAt int
+	}
+)
+
+func newSpeculativeTaskSet() *speculativeTaskSet {
+	return &speculativeTaskSet{
+		tasks:   make(map[*tasks.WorkflowTaskTimeoutTask]struct{}),
+		pruneAt: minSpeculativeTaskPruneSize,
+	}
+}
+
+func (s *speculativeTaskSet) add(task *tasks.WorkflowTaskTimeoutTask, now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.tasks) >= s.pruneAt {
+		s.pruneLocked(now)
+		s.pruneAt = max(2*len(s.tasks), minSpeculativeTaskPruneSize)
+	}
+	s.tasks[task] = struct{}{}
+}
+
+// drain removes and returns the pending tasks.
+func (s *speculativeTaskSet) drain(now time.Time) []*tasks.WorkflowTaskTimeoutTask {
+	s.Lock()
+	defer s.Unlock()
+
+	s.pruneLocked(now)
+	pending := make([]*tasks.WorkflowTaskTimeoutTask, 0, len(s.tasks))
+	for task := range s.tasks {
+		pending = append(pending, task)
+	}
+	s.tasks = make(map[*tasks.WorkflowTaskTimeoutTask]struct{})
+	s.pruneAt = minSpeculativeTaskPruneSize
+	return pending
+}
+
+func (s *speculativeTaskSet) pruneLocked(now time.Time) {
+	for task := range s.tasks {
+		if task.State() == ctasks.TaskStateCancelled || !task.GetVisibilityTime().After(now) {
+			delete(s.tasks, task)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	commonpb "go.temporal.io/api/common/v1"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+type (
+	// TaskDeserializer decodes task blobs. If one is provided to the shard ContextFactory, it's
+	// tried when the shard's payload serializer fails to decode a task blob, so that tasks written in
+	// an old format can still be read during a format migration. It's never used for writes.
+	TaskDeserializer interface {
+		DeserializeTask(category tasks.Category, blob *commonpb.DataBlob) (tasks.Task, error)
+	}
+
+	fallbackTaskSerializer struct {
+		serialization.Serializer
+
+		fallback       TaskDeserializer
+		logger         log.Logger
+		metricsHandler metrics.Handler
+	}
+)
+
+func newFallbackTaskSerializer(
+	serializer serialization.Serializer,
+	fallback TaskDeserializer,
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+) *fallbackTaskSerializer {
+	return &fallbackTaskSerializer{
+		Serializer:     serializer,
+		fallback:       fallback,
+		logger:         logger,
+		metricsHandler: metricsHandler,
+	}
+}
+
+func (s *fallbackTaskSerializer) DeserializeTask(
+	category tasks.Category,
+	blob *commonpb.DataBlob,
+) (tasks.Task, error) {
+	task, err := s.Serializer.DeserializeTask(category, blob)
+	if err == nil {
+		return task, nil
+	}
+	task, fallbackErr := s.fallback.DeserializeTask(category, blob)
+	if fallbackErr != nil {
+		// the blob is most likely corrupted rather than in the old format, report the primary error
+		return nil, err
+	}
+	metrics.TaskFallbackDeserializations.With(s.metricsHandler).Record(
+		1,
+		metrics.TaskCategoryTag(category.Name()),
+	)
+	s.logger.Info("Task blob decoded by fallback deserializer",
+		tag.TaskCategoryID(category.ID()),
+		tag.TaskID(task.GetTaskID()),
+		tag.Error(err),
+	)
+	return task, nil
+}
@@ -0,0 +1,121 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/service/history/tasks"
+	"go.temporal.io/server/service/history/tests"
+)
+
+type testTaskDeserializer struct {
+	task tasks.Task
+	err  error
+}
+
+func (d testTaskDeserializer) DeserializeTask(tasks.Category, *commonpb.DataBlob) (tasks.Task, error) {
+	return d.task, d.err
+}
+
+func TestFallbackTaskSerializer(t *testing.T) {
+	t.Parallel()
+
+	primary := serialization.NewSerializer()
+	task := &tasks.ActivityTask{
+		WorkflowKey: tests.WorkflowKey,
+		TaskID:      123,
+		TaskQueue:   "test-task-queue",
+	}
+	newBlob, err := primary.SerializeTask(task)
+	require.NoError(t, err)
+	oldBlob := &commonpb.DataBlob{EncodingType: enumspb.ENCODING_TYPE_JSON, Data: []byte(`{"taskId":123}`)}
+
+	t.Run("PrimarySucceeds", func(t *testing.T) {
+		metricsHandler := metricstest.NewCaptureHandler()
+		capture := metricsHandler.StartCapture()
+		serializer := newFallbackTaskSerializer(
+			primary,
+			testTaskDeserializer{err: errors.New("should not be called")},
+			log.NewNoopLogger(),
+			metricsHandler,
+		)
+		decoded, err := serializer.DeserializeTask(tasks.CategoryTransfer, newBlob)
+		require.NoError(t, err)
+		assert.Equal(t, task.TaskID, decoded.GetTaskID())
+		assert.Empty(t, capture.Snapshot()[metrics.TaskFallbackDeserializations.Name()])
+	})
+
+	t.Run("FallbackSucceeds", func(t *testing.T) {
+		metricsHandler := metricstest.NewCaptureHandler()
+		capture := metricsHandler.StartCapture()
+		serializer := newFallbackTaskSerializer(
+			primary,
+			testTaskDeserializer{task: task},
+			log.NewNoopLogger(),
+			metricsHandler,
+		)
+		decoded, err := serializer.DeserializeTask(tasks.CategoryTransfer, oldBlob)
+		require.NoError(t, err)
+		assert.Equal(t, task, decoded)
+		recordings := capture.Snapshot()[metrics.TaskFallbackDeserializations.Name()]
+		require.Len(t, recordings, 1)
+		assert.Equal(t, tasks.CategoryTransfer.Name(), recordings[0].Tags[metrics.TaskCategoryTagName])
+	})
+
+	t.Run("BothFail", func(t *testing.T) {
+		serializer := newFallbackTaskSerializer(
+			primary,
+			testTaskDeserializer{err: errors.New("fallback error")},
+			log.NewNoopLogger(),
+			metrics.NoopMetricsHandler,
+		)
+		_, err := serializer.DeserializeTask(tasks.CategoryTransfer, oldBlob)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "fallback error")
+	})
+
+	t.Run("WritesUsePrimary", func(t *testing.T) {
+		serializer := newFallbackTaskSerializer(
+			primary,
+			testTaskDeserializer{},
+			log.NewNoopLogger(),
+			metrics.NoopMetricsHandler,
+		)
+		blob, err := serializer.SerializeTask(task)
+		require.NoError(t, err)
+		assert.Equal(t, newBlob, blob)
+	})
+}
@@ -0,0 +1,466 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	clockspb "go.temporal.io/server/api/clock/v1"
+	"go.temporal.io/server/api/historyservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/service/history/events"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+type (
+	// TaskExecutionTrace is what a task would have done if it was executed, see Context.DryRunTask.
+	TaskExecutionTrace struct {
+		Task tasks.Task
+		// Writes are the workflow persistence requests of the execution, in order. Each of them is
+		// one of *persistence.CreateWorkflowExecutionRequest, *persistence.UpdateWorkflowExecutionRequest,
+		// *persistence.ConflictResolveWorkflowExecutionRequest, *persistence.SetWorkflowExecutionRequest,
+		// *persistence.AppendHistoryNodesRequest, *persistence.AddHistoryTasksRequest and
+		// *persistence.DeleteWorkflowExecutionRequest.
+		Writes []any
+		// GeneratedTasks are the tasks of Writes by category. They are not assigned task IDs.
+		GeneratedTasks map[tasks.Category][]tasks.Task
+		// Calls are the RPCs to other services and clusters made by the execution, in order. They
+		// are not sent and get an empty response.
+		Calls []TracedCall
+		// ExecutionErr is the error returned by the execution of the task, if any.
+		ExecutionErr error
+	}
+
+	// TracedCall is an RPC captured by a task dry run.
+	TracedCall struct {
+		// Method is the full gRPC method name, e.g. /temporal.server.api.matchingservice.v1.MatchingService/AddActivityTask.
+		Method  string
+		Request any
+	}
+
+	// dryRunContext is a shard context that captures the workflow writes of a task dry run in a
+	// trace instead of persisting them, and rejects any other write to the shard or persistence.
+	dryRunContext struct {
+		Context
+
+		trace            *TaskExecutionTrace
+		engine           Engine
+		eventsCache      events.Cache
+		executionManager persistence.ExecutionManager
+		conn             grpc.ClientConnInterface
+
+		lock       sync.Mutex
+		nextTaskID int64
+	}
+
+	// dryRunEngine drops the notifications of a task dry run, as its writes are not persisted.
+	dryRunEngine struct {
+		Engine
+	}
+
+	// dryRunExecutionManager rejects all writes of a task dry run made directly to persistence.
+	dryRunExecutionManager struct {
+		persistence.ExecutionManager
+	}
+
+	// dryRunClientConn captures the RPCs of a task dry run in a trace instead of sending them.
+	dryRunClientConn struct {
+		trace *TaskExecutionTrace
+	}
+)
+
+var errDryRunWrite = serviceerror.NewFailedPrecondition("write is not allowed in a task dry run")
+
+var _ Context = (*dryRunContext)(nil)
+
+func newDryRunContext(
+	shardContext Context,
+	engine Engine,
+	trace *TaskExecutionTrace,
+) *dryRunContext {
+	return &dryRunContext{
+		Context: shardContext,
+		trace:   trace,
+		engine:  &dryRunEngine{Engine: engine},
+		// events of the dry run must not be cached, as they are not persisted
+		eventsCache:      events.NewWriteBypassCache(shardContext.GetEventsCache()),
+		executionManager: &dryRunExecutionManager{ExecutionManager: shardContext.GetExecutionManager()},
+		conn:             NewDryRunClientConn(trace),
+		nextTaskID:       shardContext.CurrentVectorClock().GetClock(),
+	}
+}
+
+// NewDryRunClientConn returns a gRPC client connection that captures the RPCs made with it in
+// trace instead of sending them, for the clients used by a task dry run.
+func NewDryRunClientConn(trace *TaskExecutionTrace) grpc.ClientConnInterface {
+	return &dryRunClientConn{trace: trace}
+}
+
+// RecordCall adds an RPC made by the execution to the trace.
+func (t *TaskExecutionTrace) RecordCall(method string, request any) {
+	t.Calls = append(t.Calls, TracedCall{Method: method, Request: request})
+}
+
+// recordWrite adds a persistence request and the tasks it generates to the trace. request may be
+// nil for tasks that are not persisted.
+func (t *TaskExecutionTrace) recordWrite(
+	request any,
+	generatedTasks ...map[tasks.Category][]tasks.Task,
+) {
+	if request != nil {
+		t.Writes = append(t.Writes, request)
+	}
+	for _, tasksByCategory := range generatedTasks {
+		for category, categoryTasks := range tasksByCategory {
+			t.GeneratedTasks[category] = append(t.GeneratedTasks[category], categoryTasks...)
+		}
+	}
+}
+
+func (c *dryRunClientConn) Invoke(
+	_ context.Context,
+	method string,
+	args any,
+	_ any,
+	_ ...grpc.CallOption,
+) error {
+	c.trace.RecordCall(method, args)
+	return nil
+}
+
+func (c *dryRunClientConn) NewStream(
+	_ context.Context,
+	_ *grpc.StreamDesc,
+	method string,
+	_ ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, serviceerror.NewUnimplemented("streaming RPCs are not supported in a task dry run: " + method)
+}
+
+func (c *dryRunContext) GetEventsCache() events.Cache {
+	return c.eventsCache
+}
+
+func (c *dryRunContext) GetExecutionManager() persistence.ExecutionManager {
+	return c.executionManager
+}
+
+func (c *dryRunContext) GetRemoteAdminClient(string) (adminservice.AdminServiceClient, error) {
+	return adminservice.NewAdminServiceClient(c.conn), nil
+}
+
+func (c *dryRunContext) GetHistoryClient() historyservice.HistoryServiceClient {
+	return historyservice.NewHistoryServiceClient(c.conn)
+}
+
+func (c *dryRunContext) GetEngine(context.Context) (Engine, error) {
+	return c.engine, nil
+}
+
+// NewVectorClock doesn't advance the clock of the shard, as that might renew its range ID.
+func (c *dryRunContext) NewVectorClock() (*clockspb.VectorClock, error) {
+	return c.CurrentVectorClock(), nil
+}
+
+// GenerateTaskID returns IDs from the next task ID of the shard on, without allocating them.
+func (c *dryRunContext) GenerateTaskID() (int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	taskID := c.nextTaskID
+	c.nextTaskID++
+	return taskID, nil
+}
+
+func (c *dryRunContext) GenerateTaskIDs(number int) ([]int64, error) {
+	taskIDs := make([]int64, 0, number)
+	for i := 0; i < number; i++ {
+		taskID, _ := c.GenerateTaskID()
+		taskIDs = append(taskIDs, taskID)
+	}
+	return taskIDs, nil
+}
+
+func (c *dryRunContext) ReserveTaskIDRange(int) (TaskIDReservation, error) {
+	return TaskIDReservation{}, errDryRunWrite
+}
+
+func (c *dryRunContext) ReleaseTaskIDRange(int64) bool {
+	return false
+}
+
+func (c *dryRunContext) SetQueueState(tasks.Category, int, *persistencespb.QueueState) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) RebuildQueueState(context.Context, tasks.Category) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) RestoreQueueState(*persistencespb.ShardInfo) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) CheckpointForRepair() (string, error) {
+	return "", errDryRunWrite
+}
+
+func (c *dryRunContext) RollbackToCheckpoint(string) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) DryRunTask(context.Context, tasks.Category, int64) (TaskExecutionTrace, error) {
+	return TaskExecutionTrace{}, errDryRunWrite
+}
+
+func (c *dryRunContext) ForceCompleteTask(context.Context, tasks.Category, int64, string, TaskDLQWriter) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) UpdateReplicationQueueReaderState(int64, *persistencespb.QueueReaderState) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) UpdateReplicatorDLQAckLevel(string, int64) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) PauseReplicationFrom(string) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) ResumeReplicationFrom(string) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) QuarantineWorkflow(definition.WorkflowKey, string) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) UnquarantineWorkflow(definition.WorkflowKey) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) UpdateRemoteClusterInfo(string, int64, time.Time) {}
+
+func (c *dryRunContext) UpdateRemoteReaderInfo(int64, int64, time.Time) error {
+	return errDryRunWrite
+}
+
+func (c *dryRunContext) UpdateAckedReplicationWatermark(string, int32, int64, time.Time) {}
+
+func (c *dryRunContext) SetCurrentTime(string, time.Time) {}
+
+func (c *dryRunContext) UpdateHandoverNamespace(*namespace.Namespace, bool) {}
+
+func (c *dryRunContext) RecordReplicationTask(ReplicationTaskAudit) {}
+
+func (c *dryRunContext) UnloadForOwnershipLost() {}
+
+func (c *dryRunContext) SetMaintenanceMode(bool) {}
+
+func (c *dryRunContext) AppendHistoryEvents(
+	_ context.Context,
+	request *persistence.AppendHistoryNodesRequest,
+	_ namespace.ID,
+	_ *commonpb.WorkflowExecution,
+) (int, error) {
+	c.trace.recordWrite(request)
+	return 0, nil
+}
+
+func (c *dryRunContext) AddTasks(
+	_ context.Context,
+	request *persistence.AddHistoryTasksRequest,
+) error {
+	c.trace.recordWrite(request, request.Tasks)
+	return nil
+}
+
+func (c *dryRunContext) AddSpeculativeWorkflowTaskTimeoutTask(
+	task *tasks.WorkflowTaskTimeoutTask,
+) error {
+	c.trace.recordWrite(nil, map[tasks.Category][]tasks.Task{task.GetCategory(): {task}})
+	return nil
+}
+
+func (c *dryRunContext) CreateWorkflowExecution(
+	_ context.Context,
+	request *persistence.CreateWorkflowExecutionRequest,
+) (*persistence.CreateWorkflowExecutionResponse, error) {
+	c.trace.recordWrite(request, request.NewWorkflowSnapshot.Tasks)
+	return &persistence.CreateWorkflowExecutionResponse{
+		NewMutableStateStats: dryRunMutableStateStats(),
+	}, nil
+}
+
+func (c *dryRunContext) UpdateWorkflowExecution(
+	_ context.Context,
+	request *persistence.UpdateWorkflowExecutionRequest,
+) (*persistence.UpdateWorkflowExecutionResponse, error) {
+	response := &persistence.UpdateWorkflowExecutionResponse{
+		UpdateMutableStateStats: dryRunMutableStateStats(),
+	}
+	generatedTasks := []map[tasks.Category][]tasks.Task{request.UpdateWorkflowMutation.Tasks}
+	if request.NewWorkflowSnapshot != nil {
+		generatedTasks = append(generatedTasks, request.NewWorkflowSnapshot.Tasks)
+		stats := dryRunMutableStateStats()
+		response.NewMutableStateStats = &stats
+	}
+	c.trace.recordWrite(request, generatedTasks...)
+	return response, nil
+}
+
+func (c *dryRunContext) ConflictResolveWorkflowExecution(
+	_ context.Context,
+	request *persistence.ConflictResolveWorkflowExecutionRequest,
+) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
+	response := &persistence.ConflictResolveWorkflowExecutionResponse{
+		ResetMutableStateStats: dryRunMutableStateStats(),
+	}
+	generatedTasks := []map[tasks.Category][]tasks.Task{request.ResetWorkflowSnapshot.Tasks}
+	if request.NewWorkflowSnapshot != nil {
+		generatedTasks = append(generatedTasks, request.NewWorkflowSnapshot.Tasks)
+		stats := dryRunMutableStateStats()
+		response.NewMutableStateStats = &stats
+	}
+	if request.CurrentWorkflowMutation != nil {
+		generatedTasks = append(generatedTasks, request.CurrentWorkflowMutation.Tasks)
+		stats := dryRunMutableStateStats()
+		response.CurrentMutableStateStats = &stats
+	}
+	c.trace.recordWrite(request, generatedTasks...)
+	return response, nil
+}
+
+func (c *dryRunContext) SetWorkflowExecution(
+	_ context.Context,
+	request *persistence.SetWorkflowExecutionRequest,
+) (*persistence.SetWorkflowExecutionResponse, error) {
+	c.trace.recordWrite(request, request.SetWorkflowSnapshot.Tasks)
+	return &persistence.SetWorkflowExecutionResponse{}, nil
+}
+
+func (c *dryRunContext) DeleteWorkflowExecution(
+	_ context.Context,
+	workflowKey definition.WorkflowKey,
+	_ []byte,
+	_ int64,
+	_ time.Time,
+	_ *tasks.DeleteWorkflowExecutionStage,
+) error {
+	c.trace.recordWrite(&persistence.DeleteWorkflowExecutionRequest{
+		ShardID:     c.GetShardID(),
+		NamespaceID: workflowKey.NamespaceID,
+		WorkflowID:  workflowKey.WorkflowID,
+		RunID:       workflowKey.RunID,
+	})
+	return nil
+}
+
+func dryRunMutableStateStats() persistence.MutableStateStatistics {
+	return persistence.MutableStateStatistics{HistoryStatistics: &persistence.HistoryStatistics{}}
+}
+
+func (e *dryRunEngine) NotifyNewHistoryEvent(*events.Notification) {}
+
+func (e *dryRunEngine) NotifyNewTasks(map[tasks.Category][]tasks.Task) {}
+
+func (m *dryRunExecutionManager) CreateWorkflowExecution(context.Context, *persistence.CreateWorkflowExecutionRequest) (*persistence.CreateWorkflowExecutionResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) UpdateWorkflowExecution(context.Context, *persistence.UpdateWorkflowExecutionRequest) (*persistence.UpdateWorkflowExecutionResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) ConflictResolveWorkflowExecution(context.Context, *persistence.ConflictResolveWorkflowExecutionRequest) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) DeleteWorkflowExecution(context.Context, *persistence.DeleteWorkflowExecutionRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) DeleteCurrentWorkflowExecution(context.Context, *persistence.DeleteCurrentWorkflowExecutionRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) SetWorkflowExecution(context.Context, *persistence.SetWorkflowExecutionRequest) (*persistence.SetWorkflowExecutionResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) AddHistoryTasks(context.Context, *persistence.AddHistoryTasksRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) CompleteHistoryTask(context.Context, *persistence.CompleteHistoryTaskRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) RangeCompleteHistoryTasks(context.Context, *persistence.RangeCompleteHistoryTasksRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) PutReplicationTaskToDLQ(context.Context, *persistence.PutReplicationTaskToDLQRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) DeleteReplicationTaskFromDLQ(context.Context, *persistence.DeleteReplicationTaskFromDLQRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) RangeDeleteReplicationTaskFromDLQ(context.Context, *persistence.RangeDeleteReplicationTaskFromDLQRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) AppendHistoryNodes(context.Context, *persistence.AppendHistoryNodesRequest) (*persistence.AppendHistoryNodesResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) AppendRawHistoryNodes(context.Context, *persistence.AppendRawHistoryNodesRequest) (*persistence.AppendHistoryNodesResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) ForkHistoryBranch(context.Context, *persistence.ForkHistoryBranchRequest) (*persistence.ForkHistoryBranchResponse, error) {
+	return nil, errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) DeleteHistoryBranch(context.Context, *persistence.DeleteHistoryBranchRequest) error {
+	return errDryRunWrite
+}
+
+func (m *dryRunExecutionManager) TrimHistoryBranch(context.Context, *persistence.TrimHistoryBranchRequest) (*persistence.TrimHistoryBranchResponse, error) {
+	return nil, errDryRunWrite
+}
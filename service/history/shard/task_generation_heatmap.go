@@ -0,0 +1,141 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"go.temporal.io/server/common/dynamicconfig"
+)
+
+const taskGenerationBucketSize = time.Minute
+
+type (
+	// taskGenerationHeatmap counts the tasks generated by the workflow writes of a shard per
+	// workflow type, in one minute buckets, see Context.TaskGenerationByWorkflowType. It's kept in
+	// memory only. To bound its size, each bucket only keeps about the topK workflow types with the
+	// most tasks, so counts are approximate: the tasks of a workflow type that was trimmed from a
+	// bucket are lost.
+	taskGenerationHeatmap struct {
+		sync.Mutex
+		topK      dynamicconfig.IntPropertyFn
+		retention dynamicconfig.DurationPropertyFn
+		// buckets are ordered by start time, oldest first
+		buckets []taskGenerationBucket
+	}
+
+	taskGenerationBucket struct {
+		start  time.Time
+		counts map[string]int64
+	}
+)
+
+func newTaskGenerationHeatmap(
+	topK dynamicconfig.IntPropertyFn,
+	retention dynamicconfig.DurationPropertyFn,
+) *taskGenerationHeatmap {
+	return &taskGenerationHeatmap{
+		topK:      topK,
+		retention: retention,
+	}
+}
+
+func (h *taskGenerationHeatmap) record(workflowType string, taskCount int, now time.Time) {
+	topK := h.topK()
+	if topK <= 0 || taskCount == 0 {
+		return
+	}
+	start := now.Truncate(taskGenerationBucketSize)
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.expireLocked(now)
+	if len(h.buckets) == 0 || h.buckets[len(h.buckets)-1].start.Before(start) {
+		h.buckets = append(h.buckets, taskGenerationBucket{start: start, counts: make(map[string]int64)})
+	}
+	// writes racing with the start of a new bucket may land in the previous one
+	bucket := &h.buckets[len(h.buckets)-1]
+	bucket.counts[workflowType] += int64(taskCount)
+	// trimming is amortized over topK records
+	if len(bucket.counts) > 2*topK {
+		bucket.counts = topCounts(bucket.counts, topK)
+	}
+}
+
+// counts returns the number of tasks generated per workflow type in the last window, for at most
+// the topK workflow types with the most tasks. The window is capped by the retention.
+func (h *taskGenerationHeatmap) counts(window time.Duration, now time.Time) map[string]int64 {
+	topK := h.topK()
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.expireLocked(now)
+	counts := make(map[string]int64)
+	since := now.Add(-window)
+	for _, bucket := range h.buckets {
+		// a bucket is counted if any part of it is in the window
+		if bucket.start.Add(taskGenerationBucketSize).After(since) {
+			for workflowType, count := range bucket.counts {
+				counts[workflowType] += count
+			}
+		}
+	}
+	return topCounts(counts, topK)
+}
+
+func (h *taskGenerationHeatmap) expireLocked(now time.Time) {
+	since := now.Add(-h.retention())
+	expired := 0
+	for expired < len(h.buckets) && !h.buckets[expired].start.Add(taskGenerationBucketSize).After(since) {
+		expired++
+	}
+	h.buckets = h.buckets[expired:]
+}
+
+// topCounts returns the k entries of counts with the highest counts, ties broken by key.
+func topCounts(counts map[string]int64, k int) map[string]int64 {
+	if len(counts) <= k {
+		return counts
+	}
+	keys := maps.Keys(counts)
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	top := make(map[string]int64, max(k, 0))
+	for _, key := range keys[:max(k, 0)] {
+		top[key] = counts[key]
+	}
+	return top
+}
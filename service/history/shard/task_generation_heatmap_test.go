@@ -0,0 +1,113 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	taskGenerationHeatmapSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		topK    atomic.Int64
+		now     time.Time
+		heatmap *taskGenerationHeatmap
+	}
+)
+
+func TestTaskGenerationHeatmapSuite(t *testing.T) {
+	s := &taskGenerationHeatmapSuite{}
+	suite.Run(t, s)
+}
+
+func (s *taskGenerationHeatmapSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.topK.Store(3)
+	s.now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.heatmap = newTaskGenerationHeatmap(
+		func() int { return int(s.topK.Load()) },
+		func() time.Duration { return time.Hour },
+	)
+}
+
+func (s *taskGenerationHeatmapSuite) TestCounts_Empty() {
+	s.Empty(s.heatmap.counts(time.Hour, s.now))
+}
+
+func (s *taskGenerationHeatmapSuite) TestCounts_Accumulates() {
+	s.heatmap.record("order", 2, s.now)
+	s.heatmap.record("order", 3, s.now.Add(10*time.Second))
+	s.heatmap.record("payment", 1, s.now.Add(20*time.Second))
+	s.heatmap.record("payment", 0, s.now.Add(20*time.Second))
+	s.heatmap.record("order", 4, s.now.Add(5*time.Minute))
+
+	s.Equal(map[string]int64{"order": 9, "payment": 1}, s.heatmap.counts(time.Hour, s.now.Add(5*time.Minute)))
+}
+
+func (s *taskGenerationHeatmapSuite) TestCounts_Window() {
+	s.heatmap.record("order", 2, s.now)
+	s.heatmap.record("payment", 3, s.now.Add(10*time.Minute))
+	now := s.now.Add(10*time.Minute + 30*time.Second)
+
+	s.Equal(map[string]int64{"payment": 3}, s.heatmap.counts(5*time.Minute, now))
+	s.Equal(map[string]int64{"order": 2, "payment": 3}, s.heatmap.counts(15*time.Minute, now))
+	// counts older than the retention are dropped
+	s.Equal(map[string]int64{"payment": 3}, s.heatmap.counts(2*time.Hour, s.now.Add(time.Hour+time.Minute)))
+}
+
+func (s *taskGenerationHeatmapSuite) TestCounts_TopK() {
+	for i := 1; i <= 5; i++ {
+		s.heatmap.record(fmt.Sprintf("type-%d", i), i, s.now)
+	}
+	s.Equal(map[string]int64{"type-3": 3, "type-4": 4, "type-5": 5}, s.heatmap.counts(time.Hour, s.now))
+
+	// across buckets, the top types of the sums are reported
+	s.heatmap.record("type-1", 10, s.now.Add(time.Minute))
+	s.Equal(map[string]int64{"type-1": 11, "type-4": 4, "type-5": 5}, s.heatmap.counts(time.Hour, s.now.Add(time.Minute)))
+}
+
+func (s *taskGenerationHeatmapSuite) TestRecord_TrimsBucket() {
+	for i := 1; i <= 7; i++ {
+		s.heatmap.record(fmt.Sprintf("type-%d", i), i, s.now)
+	}
+	// the bucket is trimmed to the top 3 once it has more than 6 types
+	s.Len(s.heatmap.buckets, 1)
+	s.Equal(map[string]int64{"type-5": 5, "type-6": 6, "type-7": 7}, s.heatmap.buckets[0].counts)
+}
+
+func (s *taskGenerationHeatmapSuite) TestRecord_Disabled() {
+	s.topK.Store(0)
+	s.heatmap.record("order", 2, s.now)
+	s.Empty(s.heatmap.buckets)
+}
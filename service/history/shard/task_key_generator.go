@@ -205,3 +205,26 @@ func (a *taskKeyGenerator) generateTaskID() (int64, error) {
 	a.nextTaskID++
 	return taskID, nil
 }
+
+// reserveTaskIDs allocates n contiguous task IDs and returns the first and the last one. If the
+// current range doesn't have n IDs left, the rest of it is skipped and the range is renewed.
+// n must not be larger than the range size.
+func (a *taskKeyGenerator) reserveTaskIDs(n int64) (int64, int64, error) {
+	if a.nextTaskID == taskIDUninitialized {
+		a.logger.Panic("Range id is not initialized before reserving task ids")
+	}
+
+	if a.exclusiveMaxTaskID-a.nextTaskID < n {
+		if err := a.renewRangeIDFn(); err != nil {
+			return taskIDUninitialized, taskIDUninitialized, err
+		}
+
+		if a.exclusiveMaxTaskID-a.nextTaskID < n {
+			a.logger.Panic("Renew rangeID succeeded, but rangeID in task key allocator is not updated.")
+		}
+	}
+
+	start := a.nextTaskID
+	a.nextTaskID += n
+	return start, a.nextTaskID - 1, nil
+}
@@ -192,3 +192,41 @@ func (s *taskKeyGeneratorSuite) TestPeekAndGenerateTaskKey() {
 	s.NoError(err)
 	s.Zero(nextKey.CompareTo(generatedKey))
 }
+
+func (s *taskKeyGeneratorSuite) TestReserveTaskIDs() {
+	initialTaskID := s.rangeID << int64(s.rangeSizeBits)
+	taskID, err := s.generator.generateTaskID()
+	s.NoError(err)
+	s.Equal(initialTaskID, taskID)
+
+	start, end, err := s.generator.reserveTaskIDs(3)
+	s.NoError(err)
+	s.Equal(initialTaskID+1, start)
+	s.Equal(initialTaskID+3, end)
+
+	taskID, err = s.generator.generateTaskID()
+	s.NoError(err)
+	s.Equal(end+1, taskID)
+}
+
+func (s *taskKeyGeneratorSuite) TestReserveTaskIDs_RenewRange() {
+	initialRangeID := s.rangeID
+	for i := 0; i < 5; i++ {
+		_, err := s.generator.generateTaskID()
+		s.NoError(err)
+	}
+
+	// only 3 IDs left in the current range
+	start, end, err := s.generator.reserveTaskIDs(4)
+	s.NoError(err)
+	s.Equal(initialRangeID+1, s.rangeID)
+	s.Equal(s.rangeID<<int64(s.rangeSizeBits), start)
+	s.Equal(start+3, end)
+
+	for i := 0; i < 10; i++ {
+		taskID, err := s.generator.generateTaskID()
+		s.NoError(err)
+		s.False(taskID >= start && taskID <= end)
+		s.Greater(taskID, end)
+	}
+}
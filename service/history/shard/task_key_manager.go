@@ -89,6 +89,34 @@ func (m *taskKeyManager) generateTaskKey(
 	return m.generator.generateTaskKey(category)
 }
 
+func (m *taskKeyManager) reserveTaskIDs(
+	n int64,
+	timeout time.Duration,
+) (TaskIDReservation, error) {
+	start, end, err := m.generator.reserveTaskIDs(n)
+	if err != nil {
+		return TaskIDReservation{}, err
+	}
+
+	reservation := TaskIDReservation{
+		Start:      start,
+		End:        end,
+		ExpireTime: m.timeSource.Now().Add(timeout),
+	}
+	m.tracker.trackReservation(reservation)
+	return reservation, nil
+}
+
+func (m *taskKeyManager) releaseTaskIDs(
+	start int64,
+) bool {
+	return m.tracker.releaseReservation(start)
+}
+
+func (m *taskKeyManager) getTaskIDReservations() []TaskIDReservation {
+	return m.tracker.activeReservations(m.timeSource.Now())
+}
+
 func (m *taskKeyManager) drainTaskRequests() {
 	m.tracker.drain()
 }
@@ -116,6 +144,12 @@ func (m *taskKeyManager) getExclusiveReaderHighWatermark(
 	if !ok {
 		minTaskKey = tasks.MaximumKey
 	}
+	if category.Type() == tasks.CategoryTypeImmediate {
+		// tasks with reserved IDs might still be written by an external writer
+		for _, reservation := range m.getTaskIDReservations() {
+			minTaskKey = tasks.MinKey(minTaskKey, tasks.NewImmediateKey(reservation.Start))
+		}
+	}
 
 	// TODO: should this be moved generator.setTaskKeys() ?
 	m.setTaskMinScheduledTime(
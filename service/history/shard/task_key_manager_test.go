@@ -202,3 +202,32 @@ func (s *taskKeyManagerSuite) TestGetExclusiveReaderHighWatermark_WithPendingTas
 	highReaderWatermark = s.manager.getExclusiveReaderHighWatermark(tasks.CategoryTimer)
 	s.Zero(tasks.NewKey(timerTask.GetVisibilityTime(), 0).CompareTo(highReaderWatermark))
 }
+
+func (s *taskKeyManagerSuite) TestGetExclusiveReaderHighWatermark_WithReservedTaskIDs() {
+	now := time.Now()
+	s.mockTimeSource.Update(now)
+
+	reservation, err := s.manager.reserveTaskIDs(3, time.Minute)
+	s.NoError(err)
+	s.Equal(s.initialTaskID, reservation.Start)
+	s.Equal(s.initialTaskID+2, reservation.End)
+	s.Equal([]TaskIDReservation{reservation}, s.manager.getTaskIDReservations())
+
+	// the reservation holds immediate queues, and survives a range ID update
+	s.rangeID++
+	s.manager.setRangeID(s.rangeID)
+	s.Equal(reservation.Start, s.manager.getExclusiveReaderHighWatermark(tasks.CategoryTransfer).TaskID)
+	s.Zero(s.manager.getExclusiveReaderHighWatermark(tasks.CategoryTimer).TaskID)
+
+	s.True(s.manager.releaseTaskIDs(reservation.Start))
+	s.False(s.manager.releaseTaskIDs(reservation.Start))
+	s.Equal(s.rangeID<<int64(s.rangeSizeBits), s.manager.getExclusiveReaderHighWatermark(tasks.CategoryTransfer).TaskID)
+
+	// an expired reservation no longer holds immediate queues
+	reservation, err = s.manager.reserveTaskIDs(1, time.Minute)
+	s.NoError(err)
+	s.Equal(reservation.Start, s.manager.getExclusiveReaderHighWatermark(tasks.CategoryTransfer).TaskID)
+	s.mockTimeSource.Update(now.Add(time.Minute))
+	s.Empty(s.manager.getTaskIDReservations())
+	s.Equal(reservation.End+1, s.manager.getExclusiveReaderHighWatermark(tasks.CategoryTransfer).TaskID)
+}
@@ -26,6 +26,7 @@ package shard
 
 import (
 	"sync"
+	"time"
 
 	"go.temporal.io/server/service/history/tasks"
 )
@@ -42,6 +43,11 @@ type (
 		pendingTaskKeys      map[tasks.Category]map[tasks.Key]struct{}
 		inflightRequestCount int
 
+		// reservations are the task ID ranges reserved for writers outside of the shard, by their
+		// first task ID. Unlike pending task keys, they are kept when the range ID changes, since
+		// those writers don't write conditioned on the shard's range ID.
+		reservations map[int64]TaskIDReservation
+
 		waitChannels []chan<- struct{}
 	}
 )
@@ -53,6 +59,7 @@ func newTaskRequestTracker(registry tasks.TaskCategoryRegistry) *taskRequestTrac
 	}
 	return &taskRequestTracker{
 		pendingTaskKeys: outstandingTaskKeys,
+		reservations:    make(map[int64]TaskIDReservation),
 	}
 }
 
@@ -133,6 +140,45 @@ func (t *taskRequestTracker) minTaskKey(
 	return minKey, true
 }
 
+func (t *taskRequestTracker) trackReservation(
+	reservation TaskIDReservation,
+) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.reservations[reservation.Start] = reservation
+}
+
+func (t *taskRequestTracker) releaseReservation(
+	start int64,
+) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	_, ok := t.reservations[start]
+	delete(t.reservations, start)
+	return ok
+}
+
+// activeReservations returns the reservations that haven't expired by now, and drops the
+// expired ones.
+func (t *taskRequestTracker) activeReservations(
+	now time.Time,
+) []TaskIDReservation {
+	t.Lock()
+	defer t.Unlock()
+
+	result := make([]TaskIDReservation, 0, len(t.reservations))
+	for start, reservation := range t.reservations {
+		if !now.Before(reservation.ExpireTime) {
+			delete(t.reservations, start)
+			continue
+		}
+		result = append(result, reservation)
+	}
+	return result
+}
+
 // drain method blocks until all inflight requests are completed
 // This method should be called before updating shard rangeID,
 // otherwise inflight request can fails as those requests are conditioned on
@@ -0,0 +1,81 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"fmt"
+
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+// TaskRewriter is applied to every task a shard writes, before the task gets its key and is
+// serialized. If one is provided to the shard ContextFactory, it can be used to backfill task
+// fields during a rolling migration. The returned task must have the same category, type and
+// key as the given one, otherwise the write fails with InvalidPersistenceRequestError.
+type TaskRewriter func(tasks.Task) tasks.Task
+
+// rewriteTasks applies rewriter to all tasks in taskMaps in place.
+func rewriteTasks(
+	rewriter TaskRewriter,
+	taskMaps ...map[tasks.Category][]tasks.Task,
+) error {
+	for _, taskMap := range taskMaps {
+		for category, tasksByCategory := range taskMap {
+			for i, task := range tasksByCategory {
+				rewritten := rewriter(task)
+				if err := validateRewrittenTask(category, task, rewritten); err != nil {
+					return err
+				}
+				tasksByCategory[i] = rewritten
+			}
+		}
+	}
+	return nil
+}
+
+func validateRewrittenTask(
+	category tasks.Category,
+	original tasks.Task,
+	rewritten tasks.Task,
+) error {
+	switch {
+	case rewritten == nil:
+		return &persistence.InvalidPersistenceRequestError{Msg: fmt.Sprintf("task rewriter dropped %v task", original.GetType())}
+	case rewritten.GetCategory() != category:
+		return &persistence.InvalidPersistenceRequestError{Msg: fmt.Sprintf(
+			"task rewriter changed task category from %v to %v", category.Name(), rewritten.GetCategory().Name(),
+		)}
+	case rewritten.GetType() != original.GetType():
+		return &persistence.InvalidPersistenceRequestError{Msg: fmt.Sprintf(
+			"task rewriter changed task type from %v to %v", original.GetType(), rewritten.GetType(),
+		)}
+	case rewritten.GetKey().CompareTo(original.GetKey()) != 0:
+		return &persistence.InvalidPersistenceRequestError{Msg: fmt.Sprintf(
+			"task rewriter changed %v task key from %v to %v", original.GetType(), original.GetKey(), rewritten.GetKey(),
+		)}
+	}
+	return nil
+}
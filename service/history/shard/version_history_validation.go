@@ -0,0 +1,155 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shard
+
+import (
+	"fmt"
+
+	historyspb "go.temporal.io/server/api/history/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/persistence"
+)
+
+type (
+	// VersionHistoryDefect is a broken invariant of the version histories of a workflow execution,
+	// see Context.ValidateVersionHistory.
+	VersionHistoryDefect struct {
+		// VersionHistoryIndex is the index of the version history with the defect, or -1 if the
+		// defect is not about a single version history.
+		VersionHistoryIndex int
+		Description         string
+	}
+)
+
+// validateVersionHistories returns the defects of the version histories of state. Each version
+// history must have items with increasing event IDs and versions, and a branch token of the same
+// history tree as the other version histories, whose ancestors cover the events before the branch
+// without gaps. The current version history must end at the last event of the execution.
+func validateVersionHistories(
+	state *persistencespb.WorkflowMutableState,
+	branchUtil persistence.HistoryBranchUtil,
+) []VersionHistoryDefect {
+	var defects []VersionHistoryDefect
+	addDefect := func(index int, format string, args ...any) {
+		defects = append(defects, VersionHistoryDefect{
+			VersionHistoryIndex: index,
+			Description:         fmt.Sprintf(format, args...),
+		})
+	}
+
+	versionHistories := state.GetExecutionInfo().GetVersionHistories()
+	histories := versionHistories.GetHistories()
+	if len(histories) == 0 {
+		addDefect(-1, "execution has no version history")
+		return defects
+	}
+
+	var treeID string
+	for index, history := range histories {
+		for _, description := range validateVersionHistoryItems(history.GetItems()) {
+			addDefect(index, "%v", description)
+		}
+
+		if len(history.GetBranchToken()) == 0 {
+			addDefect(index, "branch token is empty")
+			continue
+		}
+		branch, err := branchUtil.ParseHistoryBranchInfo(history.GetBranchToken())
+		if err != nil {
+			addDefect(index, "branch token is invalid: %v", err)
+			continue
+		}
+		if treeID == "" {
+			treeID = branch.GetTreeId()
+		} else if branch.GetTreeId() != treeID {
+			addDefect(index, "branch is in history tree %v, other branches are in history tree %v", branch.GetTreeId(), treeID)
+		}
+		for _, description := range validateBranchAncestors(branch) {
+			addDefect(index, "%v", description)
+		}
+	}
+
+	currentIndex := int(versionHistories.GetCurrentVersionHistoryIndex())
+	if currentIndex < 0 || currentIndex >= len(histories) {
+		addDefect(-1, "current version history index %v is out of range of %v version histories", currentIndex, len(histories))
+		return defects
+	}
+	currentItems := histories[currentIndex].GetItems()
+	if len(currentItems) != 0 {
+		lastEventID := currentItems[len(currentItems)-1].GetEventId()
+		if lastEventID != state.GetNextEventId()-1 {
+			addDefect(currentIndex, "current version history ends at event %v, but the next event of the execution is %v", lastEventID, state.GetNextEventId())
+		}
+	}
+	return defects
+}
+
+func validateVersionHistoryItems(items []*historyspb.VersionHistoryItem) []string {
+	if len(items) == 0 {
+		return []string{"version history has no items"}
+	}
+	var descriptions []string
+	prevEventID := common.FirstEventID - 1
+	var prevVersion int64
+	for index, item := range items {
+		if item.GetEventId() <= prevEventID {
+			descriptions = append(descriptions, fmt.Sprintf(
+				"item %v ends at event %v, which is not after the end of the previous item at event %v",
+				index, item.GetEventId(), prevEventID,
+			))
+		}
+		if index > 0 && item.GetVersion() <= prevVersion {
+			descriptions = append(descriptions, fmt.Sprintf(
+				"item %v has version %v, which is not greater than the version %v of the previous item",
+				index, item.GetVersion(), prevVersion,
+			))
+		}
+		prevEventID = item.GetEventId()
+		prevVersion = item.GetVersion()
+	}
+	return descriptions
+}
+
+func validateBranchAncestors(branch *persistencespb.HistoryBranch) []string {
+	var descriptions []string
+	nextNodeID := common.FirstEventID
+	for index, ancestor := range branch.GetAncestors() {
+		if ancestor.GetBeginNodeId() != nextNodeID {
+			descriptions = append(descriptions, fmt.Sprintf(
+				"ancestor %v begins at node %v, expected node %v",
+				index, ancestor.GetBeginNodeId(), nextNodeID,
+			))
+		}
+		if ancestor.GetEndNodeId() <= ancestor.GetBeginNodeId() {
+			descriptions = append(descriptions, fmt.Sprintf(
+				"ancestor %v ends at node %v, which is not after its beginning at node %v",
+				index, ancestor.GetEndNodeId(), ancestor.GetBeginNodeId(),
+			))
+		}
+		nextNodeID = ancestor.GetEndNodeId()
+	}
+	return descriptions
+}
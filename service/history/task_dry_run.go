@@ -0,0 +1,109 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/api/historyservice/v1"
+	"go.temporal.io/server/api/matchingservice/v1"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/service/history/queues"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tasks"
+	wcache "go.temporal.io/server/service/history/workflow/cache"
+	"go.temporal.io/server/service/worker/parentclosepolicy"
+)
+
+const dryRunParentClosePolicyMethod = "parentclosepolicy.Client/SendParentClosePolicyRequest"
+
+type (
+	// dryRunExecutable is the executable of a task dry run. Executors only use GetTask.
+	dryRunExecutable struct {
+		queues.Executable
+
+		task tasks.Task
+	}
+
+	// dryRunParentClosePolicyClient captures the parent close policy requests of a task dry run.
+	dryRunParentClosePolicyClient struct {
+		trace *shard.TaskExecutionTrace
+	}
+)
+
+// DryRunTask implements shard.Engine. Only transfer tasks of active namespaces are supported. The
+// task is executed by a transfer queue active executor of its own, with a separate workflow cache
+// and clients that capture RPCs in trace instead of sending them.
+func (e *historyEngineImpl) DryRunTask(
+	ctx context.Context,
+	shardContext shard.Context,
+	task tasks.Task,
+	trace *shard.TaskExecutionTrace,
+) error {
+	if task.GetCategory() != tasks.CategoryTransfer {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf("dry run is not supported for %v tasks", task.GetCategory().Name()))
+	}
+	namespaceEntry, err := shardContext.GetNamespaceRegistry().GetNamespaceByID(namespace.ID(task.GetNamespaceID()))
+	if err != nil {
+		return err
+	}
+	if !namespaceEntry.ActiveInCluster(shardContext.GetClusterMetadata().GetCurrentClusterName()) {
+		return serviceerror.NewFailedPrecondition(fmt.Sprintf("dry run is only supported for tasks of active namespaces, namespace %v is not active", namespaceEntry.Name()))
+	}
+
+	conn := shard.NewDryRunClientConn(trace)
+	executor := newTransferQueueActiveTaskExecutor(
+		shardContext,
+		wcache.NewShardLevelCache(e.config, metrics.NoopMetricsHandler),
+		e.sdkClientFactory,
+		e.logger,
+		metrics.NoopMetricsHandler,
+		e.config,
+		historyservice.NewHistoryServiceClient(conn),
+		matchingservice.NewMatchingServiceClient(conn),
+		e.persistenceVisibilityMgr,
+	).(*transferQueueActiveTaskExecutor)
+	executor.parentClosePolicyClient = &dryRunParentClosePolicyClient{trace: trace}
+
+	response := executor.Execute(ctx, &dryRunExecutable{task: task})
+	trace.ExecutionErr = response.ExecutionErr
+	return nil
+}
+
+func (e *dryRunExecutable) GetTask() tasks.Task {
+	return e.task
+}
+
+func (c *dryRunParentClosePolicyClient) SendParentClosePolicyRequest(
+	_ context.Context,
+	request parentclosepolicy.Request,
+) error {
+	c.trace.RecordCall(dryRunParentClosePolicyMethod, request)
+	return nil
+}
@@ -204,6 +204,7 @@ func (f *timerQueueFactory) CreateQueue(
 		standbyExecutor,
 		logger,
 	)
+	executor = queues.NewQuarantineExecutor(shard, executor)
 	if f.ExecutorWrapper != nil {
 		executor = f.ExecutorWrapper.Wrap(executor)
 	}
@@ -197,6 +197,7 @@ func (f *transferQueueFactory) CreateQueue(
 		standbyExecutor,
 		logger,
 	)
+	executor = queues.NewQuarantineExecutor(shard, executor)
 	if f.ExecutorWrapper != nil {
 		executor = f.ExecutorWrapper.Wrap(executor)
 	}
@@ -226,6 +226,7 @@ func ServerOptionsProvider(opts []ServerOption) (serverOptionsProvider, error) {
 			if err != nil {
 				return serverOptionsProvider{}, fmt.Errorf("unable to create dynamic config client: %w", err)
 			}
+			dcClient = dynamicconfig.NewEnvironmentClient(dcClient, so.env)
 		} else {
 			// noop client
 			logger.Info("Dynamic config client is not configured. Using default values.")